@@ -21,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/eth/devtools"
 
 	"github.com/golang/glog"
 )
@@ -152,13 +153,21 @@ func getNodeType(isBroadcaster bool) string {
 }
 
 func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
+	ctx := context.Background()
 	time.Sleep(3 * time.Second)
 	//Set up eth client
-	backend, err := ethclient.Dial(endpoint)
+	rpcClient, err := rpc.Dial(endpoint)
 	if err != nil {
 		glog.Errorf("Failed to connect to Ethereum client: %v", err)
 		return
 	}
+	backend := ethclient.NewClient(rpcClient)
+
+	funder, err := devtools.NewFunder(ctx, rpcClient)
+	if err != nil {
+		glog.Errorf("Failed to create funder: %v", err)
+		return
+	}
 	glog.Infof("Using controller address %s", ethController)
 
 	gpm := eth.NewGasPriceMonitor(backend, 5*time.Second, big.NewInt(0), nil)
@@ -188,7 +197,7 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 		return
 	}
 
-	tm := eth.NewTransactionManager(backend, gpm, am, 5*time.Minute, 0)
+	tm := eth.NewTransactionManager(backend, gpm, am, 5*time.Minute, 0, 0, 0)
 	go tm.Start()
 	defer tm.Stop()
 
@@ -218,12 +227,12 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 		glog.Infof("Funding deposit with %v", amount)
 		glog.Infof("Funding reserve with %v", amount)
 
-		tx, err := client.FundDepositAndReserve(amount, amount)
+		tx, err := client.FundDepositAndReserve(ctx, amount, amount)
 		if err != nil {
 			glog.Error(err)
 			return
 		}
-		if err := client.CheckTx(tx); err != nil {
+		if err := client.CheckTx(ctx, tx); err != nil {
 			glog.Error(err)
 			return
 		}
@@ -232,15 +241,9 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 	} else {
 		glog.Infof("Requesting tokens from faucet")
 
-		tx, err := client.Request()
-		if err != nil {
-			glog.Errorf("Error requesting tokens from faucet: %v", err)
-			return
-		}
-
-		err = client.CheckTx(tx)
-		if err != nil {
-			glog.Errorf("Error requesting tokens from faucet: %v", err)
+		var amount *big.Int = big.NewInt(int64(500))
+		if err := funder.FundLPT(ctx, client, amount); err != nil {
+			glog.Error(err)
 			return
 		}
 		glog.Info("Done requesting tokens.")
@@ -249,7 +252,7 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 		// XXX TODO curl -X "POST" http://localhost:$transcoderCliPort/initializeRound
 		time.Sleep(3 * time.Second)
 		for {
-			currentRound, err := client.CurrentRound()
+			currentRound, err := client.CurrentRound(ctx)
 			if err != nil {
 				glog.Errorf("Error getting current round: %v", err)
 				return
@@ -261,7 +264,7 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 			glog.Info("Waiting will first round ended.")
 			time.Sleep(4 * time.Second)
 		}
-		tx, err = client.InitializeRound()
+		tx, err := client.InitializeRound(ctx)
 		// ErrRoundInitialized
 		if err != nil {
 			if err.Error() != "ErrRoundInitialized" {
@@ -269,7 +272,7 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 				return
 			}
 		} else {
-			err = client.CheckTx(tx)
+			err = client.CheckTx(ctx, tx)
 			if err != nil {
 				glog.Errorf("Error initializng round: %v", err)
 				return
@@ -280,16 +283,15 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 		// curl -d "blockRewardCut=10&feeShare=5&amount=500" --data-urlencode "serviceURI=https://$transcoderServiceAddr" \
 		//   -H "Content-Type: application/x-www-form-urlencoded" \
 		//   -X "POST" http://localhost:$transcoderCliPort/activateTranscoder\
-		var amount *big.Int = big.NewInt(int64(500))
 		glog.Infof("Bonding %v to %s", amount, ethAcctAddr)
 
-		tx, err = client.Bond(amount, ethcommon.HexToAddress(ethAcctAddr))
+		tx, err = client.Bond(ctx, amount, ethcommon.HexToAddress(ethAcctAddr))
 		if err != nil {
 			glog.Error(err)
 			return
 		}
 
-		err = client.CheckTx(tx)
+		err = client.CheckTx(ctx, tx)
 		if err != nil {
 			glog.Error("=== Bonding failed")
 			glog.Error(err)
@@ -297,7 +299,7 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 		}
 		glog.Infof("Registering transcoder %v", ethAcctAddr)
 
-		tx, err = client.Transcoder(eth.FromPerc(10), eth.FromPerc(5))
+		tx, err = client.Transcoder(ctx, eth.FromPerc(10), eth.FromPerc(5))
 		if err == eth.ErrCurrentRoundLocked {
 			// wait for next round and retry
 		}
@@ -306,7 +308,7 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 			return
 		}
 
-		err = client.CheckTx(tx)
+		err = client.CheckTx(ctx, tx)
 		if err != nil {
 			glog.Error(err)
 			return
@@ -314,13 +316,13 @@ func ethSetup(ethAcctAddr, keystoreDir string, isBroadcaster bool) {
 
 		glog.Infof("Storing service URI %v in service registry...", serviceURI)
 
-		tx, err = client.SetServiceURI(serviceURI)
+		tx, err = client.SetServiceURI(ctx, serviceURI)
 		if err != nil {
 			glog.Error(err)
 			return
 		}
 
-		err = client.CheckTx(tx)
+		err = client.CheckTx(ctx, tx)
 		if err != nil {
 			glog.Error(err)
 		}
@@ -443,15 +445,18 @@ func remoteConsole(destAccountAddr string) error {
 		glog.Infof("Found controller address: %s", ethController)
 	}
 
-	script := fmt.Sprintf("eth.sendTransaction({from: \"%s\", to: \"%s\", value: web3.toWei(834, \"ether\")})",
-		gethMiningAccount, broadcasterGeth)
-	glog.Infof("Running eth script: %s", script)
-
-	console.Evaluate(script)
-
-	time.Sleep(3 * time.Second)
+	funder, err := devtools.NewFunder(context.Background(), client)
+	if err != nil {
+		glog.Fatalf("Can't fund account: %v", err)
+	}
+	miningAccount := ethcommon.HexToAddress(gethMiningAccount)
+	funder.ETHSource = &miningAccount
+	amount := new(big.Int).Mul(big.NewInt(834), big.NewInt(1e18))
+	if err := funder.FundETH(context.Background(), ethcommon.HexToAddress(broadcasterGeth), amount); err != nil {
+		glog.Fatalf("Can't fund account: %v", err)
+	}
 
-	return err
+	return nil
 }
 
 func moveDir(src, dst string) error {
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/livepeer/go-livepeer/core"
+)
+
+const (
+	capabilitySupported   = "supported"
+	capabilityUnsupported = "unsupported"
+	// capabilityUntested is used for capabilities without a test which are assumed to be supported
+	capabilityUntested = "untested"
+	// capabilitySkipped is used for capabilities that were not tested because testing stopped on a fatal error
+	capabilitySkipped = "skipped"
+	// capabilityDisabled is used for capabilities that are not supported by the installed driver or CUDA version
+	capabilityDisabled = "disabled"
+)
+
+type capabilityReport struct {
+	Devices      []string                `json:"devices"`
+	Passed       bool                    `json:"passed"`
+	Error        string                  `json:"error,omitempty"`
+	Capabilities []capabilityReportEntry `json:"capabilities"`
+}
+
+type capabilityReportEntry struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// newCapabilityReport creates a report for the results of testing the capabilities of devices. The report passes if
+// fatalErr is nil i.e. if all required capabilities are supported. Capabilities in disabled are reported as disabled
+func newCapabilityReport(devices []string, results []core.CapabilityTestResult, disabled []core.Capability, fatalErr error) *capabilityReport {
+	report := &capabilityReport{Devices: devices, Passed: fatalErr == nil, Capabilities: []capabilityReportEntry{}}
+	if fatalErr != nil {
+		report.Error = fatalErr.Error()
+	}
+
+	tested := make(map[core.Capability]bool)
+	for _, res := range results {
+		entry := capabilityReportEntry{ID: int(res.Capability), Name: res.Name, Required: res.Required}
+		switch {
+		case core.InArray(res.Capability, disabled):
+			entry.Status = capabilityDisabled
+		case !res.Tested:
+			entry.Status = capabilityUntested
+		case res.Supported:
+			entry.Status = capabilitySupported
+		default:
+			entry.Status = capabilityUnsupported
+		}
+		if res.Err != nil {
+			entry.Error = res.Err.Error()
+		}
+		report.Capabilities = append(report.Capabilities, entry)
+		tested[res.Capability] = true
+	}
+
+	for _, c := range append(core.DefaultCapabilities(), core.OptionalCapabilities()...) {
+		if tested[c] {
+			continue
+		}
+		name, err := core.CapabilityToName(c)
+		if err != nil {
+			name = "unknown"
+		}
+		report.Capabilities = append(report.Capabilities, capabilityReportEntry{
+			ID:       int(c),
+			Name:     name,
+			Required: core.InArray(c, core.DefaultCapabilities()),
+			Status:   capabilitySkipped,
+		})
+	}
+
+	return report
+}
+
+// write writes the report to w as JSON if asJSON is true or as a table otherwise
+func (r *capabilityReport) write(w io.Writer, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tREQUIRED\tSTATUS\tERROR")
+	for _, c := range r.Capabilities {
+		fmt.Fprintf(tw, "%d\t%s\t%t\t%s\t%s\n", c.ID, c.Name, c.Required, c.Status, c.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if r.Passed {
+		_, err := fmt.Fprintf(w, "PASSED devices=%v\n", r.Devices)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "FAILED devices=%v err=%q\n", r.Devices, r.Error)
+	return err
+}
+
+// runCapabilitiesTest tests the capabilities of the Nvidia devices, writes the report to w and returns the exit code
+// of the test which is non-zero if a required capability is not supported
+func runCapabilitiesTest(w io.Writer, devices []string, disabled []core.Capability, asJSON bool) int {
+	results, err := core.TestTranscoderCapabilityResults(devices, nil)
+	report := newCapabilityReport(devices, results, disabled, err)
+	if err := report.write(w, asJSON); err != nil {
+		return 2
+	}
+	if !report.Passed {
+		return 1
+	}
+	return 0
+}
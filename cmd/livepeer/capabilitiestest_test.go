@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilityReport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	devices := []string{"0", "1"}
+	results := []core.CapabilityTestResult{
+		{Capability: core.Capability_H264, Name: "H.264", Required: true, Tested: true, Supported: true},
+		{Capability: core.Capability_MPEGTS, Name: "MPEGTS", Required: true, Supported: true},
+		{Capability: core.Capability_HEVC_Encode, Name: "HEVC encode", Tested: true, Err: errors.New("device 1: unsupported")},
+	}
+
+	report := newCapabilityReport(devices, results, nil, nil)
+	assert.True(report.Passed)
+	assert.Empty(report.Error)
+	assert.Len(report.Capabilities, len(core.DefaultCapabilities())+len(core.OptionalCapabilities()))
+	assert.Equal(capabilityReportEntry{ID: int(core.Capability_H264), Name: "H.264", Required: true, Status: capabilitySupported}, report.Capabilities[0])
+	assert.Equal(capabilityUntested, report.Capabilities[1].Status)
+	assert.Equal(capabilityReportEntry{ID: int(core.Capability_HEVC_Encode), Name: "HEVC encode", Status: capabilityUnsupported, Error: "device 1: unsupported"}, report.Capabilities[2])
+	// Capabilities that were not tested are reported as skipped
+	for _, c := range report.Capabilities[3:] {
+		assert.Equal(capabilitySkipped, c.Status)
+		assert.NotEqual(core.Capability_HEVC_Encode, core.Capability(c.ID))
+	}
+
+	// JSON output
+	var buf bytes.Buffer
+	require.Nil(report.write(&buf, true))
+	var decoded capabilityReport
+	require.Nil(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(*report, decoded)
+
+	// Table output
+	buf.Reset()
+	require.Nil(report.write(&buf, false))
+	assert.Contains(buf.String(), "HEVC encode")
+	assert.Contains(buf.String(), "PASSED devices=[0 1]")
+
+	// A required capability is not supported
+	report = newCapabilityReport(devices, results[:1], nil, errors.New(`required capability "MPEGTS" is not supported on hardware`))
+	assert.False(report.Passed)
+	assert.Equal(`required capability "MPEGTS" is not supported on hardware`, report.Error)
+
+	buf.Reset()
+	require.Nil(report.write(&buf, false))
+	assert.Contains(buf.String(), "FAILED devices=[0 1]")
+
+	// Capabilities that are not supported by the driver are reported as disabled
+	report = newCapabilityReport(devices, results, []core.Capability{core.Capability_HEVC_Encode}, nil)
+	assert.Equal(capabilityDisabled, report.Capabilities[2].Status)
+}
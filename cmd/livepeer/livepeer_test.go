@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"net"
 	"testing"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -12,6 +13,7 @@ import (
 	"github.com/livepeer/go-livepeer/eth"
 	lpTypes "github.com/livepeer/go-livepeer/eth/types"
 	"github.com/livepeer/go-livepeer/pm"
+	"github.com/livepeer/lpms/ffmpeg"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -85,3 +87,132 @@ func TestIsLocalURL(t *testing.T) {
 	assert.Nil(err)
 	assert.False(isLocal)
 }
+
+func TestParseStreamLimitOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	senders, ips, err := parseStreamLimitOverrides("")
+	assert.Nil(err)
+	assert.Empty(senders)
+	assert.Empty(ips)
+
+	senders, ips, err = parseStreamLimitOverrides("0x1111111111111111111111111111111111111111=10, 1.2.3.4=0")
+	assert.Nil(err)
+	assert.Equal(map[ethcommon.Address]int{ethcommon.HexToAddress("0x1111111111111111111111111111111111111111"): 10}, senders)
+	assert.Equal(map[string]int{"1.2.3.4": 0}, ips)
+
+	_, _, err = parseStreamLimitOverrides("1.2.3.4")
+	assert.Contains(err.Error(), "expected <address>=<limit>")
+
+	_, _, err = parseStreamLimitOverrides("1.2.3.4=foo")
+	assert.Contains(err.Error(), "invalid stream limit for 1.2.3.4")
+
+	_, _, err = parseStreamLimitOverrides("1.2.3.4=-1")
+	assert.Contains(err.Error(), "invalid stream limit for 1.2.3.4")
+
+	_, _, err = parseStreamLimitOverrides("foo=1")
+	assert.Contains(err.Error(), "invalid address foo")
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	proxies, err := parseTrustedProxies("10.0.0.0/8, 192.168.1.1,::1")
+	require.Nil(err)
+	require.Len(proxies, 3)
+	assert.Equal("10.0.0.0/8", proxies[0].String())
+	assert.Equal("192.168.1.1/32", proxies[1].String())
+	assert.Equal("::1/128", proxies[2].String())
+	assert.True(proxies[0].Contains(net.ParseIP("10.1.2.3")))
+	assert.False(proxies[1].Contains(net.ParseIP("192.168.1.2")))
+
+	_, err = parseTrustedProxies("foo")
+	assert.EqualError(err, `invalid proxy "foo", expected an IP address or a CIDR network`)
+	_, err = parseTrustedProxies("10.0.0.0/33")
+	assert.EqualError(err, `invalid proxy "10.0.0.0/33", expected an IP address or a CIDR network`)
+}
+
+func TestParseMethodConfirmations(t *testing.T) {
+	assert := assert.New(t)
+
+	confirmations, err := parseMethodConfirmations("")
+	assert.Nil(err)
+	assert.Nil(confirmations)
+
+	confirmations, err = parseMethodConfirmations("initializeRound=1, bond=5,transfer=0")
+	assert.Nil(err)
+	assert.Equal(map[string]uint64{"initializeRound": 1, "bond": 5, "transfer": 0}, confirmations)
+
+	_, err = parseMethodConfirmations("bond")
+	assert.Contains(err.Error(), "expected <method>=<blocks>")
+
+	_, err = parseMethodConfirmations("bond=-1")
+	assert.Contains(err.Error(), "invalid confirmations for bond")
+
+	_, err = parseMethodConfirmations("=5")
+	assert.Contains(err.Error(), "invalid confirmations for")
+}
+
+func TestParseSpendLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	limits, err := parseSpendLimits("", "")
+	assert.Nil(err)
+	assert.Nil(limits)
+
+	limits, err = parseSpendLimits("reward=1000, *=500", "")
+	assert.Nil(err)
+	assert.Equal(map[string]*big.Int{"reward": big.NewInt(1000), "*": big.NewInt(500)}, limits.MaxTxCost)
+	assert.Nil(limits.MaxDailySpend)
+
+	limits, err = parseSpendLimits("", "2000")
+	assert.Nil(err)
+	assert.Empty(limits.MaxTxCost)
+	assert.Equal(big.NewInt(2000), limits.MaxDailySpend)
+
+	_, err = parseSpendLimits("reward", "")
+	assert.Contains(err.Error(), "expected <method>=<wei>")
+
+	_, err = parseSpendLimits("reward=foo", "")
+	assert.Contains(err.Error(), "invalid max transaction cost for reward")
+
+	_, err = parseSpendLimits("reward=-1", "")
+	assert.Contains(err.Error(), "invalid max transaction cost for reward")
+
+	_, err = parseSpendLimits("", "foo")
+	assert.Contains(err.Error(), "invalid max daily spend")
+}
+
+func TestParseCapabilities(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(parseCapabilities(""))
+	assert.Equal([]core.Capability{core.Capability_HEVC_Decode, core.Capability_HEVC_Encode}, parseCapabilities("hevc"))
+	assert.Equal([]core.Capability{core.Capability_HEVC_Encode, core.Capability_VP9_Decode}, parseCapabilities("HEVC encode, vp9"))
+	// Unknown capabilities are ignored
+	assert.Equal([]core.Capability{core.Capability_HEVC_Decode, core.Capability_HEVC_Encode}, parseCapabilities("hevc,av1"))
+	assert.Len(parseCapabilities("all"), len(core.DefaultCapabilities())+len(core.OptionalCapabilities()))
+}
+
+func TestParseProfileEncoders(t *testing.T) {
+	assert := assert.New(t)
+
+	accels, err := parseProfileEncoders("240p=software, 360p=libx264,P720p30fps16x9=nvidia,1080p=NVENC")
+	assert.Nil(err)
+	assert.Equal(map[string]ffmpeg.Acceleration{
+		"240p":           ffmpeg.Software,
+		"360p":           ffmpeg.Software,
+		"P720p30fps16x9": ffmpeg.Nvidia,
+		"1080p":          ffmpeg.Nvidia,
+	}, accels)
+
+	_, err = parseProfileEncoders("240p")
+	assert.Contains(err.Error(), "expected <profile>=<encoder>")
+
+	_, err = parseProfileEncoders("=software")
+	assert.Contains(err.Error(), "expected <profile>=<encoder>")
+
+	_, err = parseProfileEncoders("240p=foo")
+	assert.Contains(err.Error(), "unknown encoder foo for profile 240p")
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// NetworkConfig is a named network profile bundling everything needed to connect to a network
+type NetworkConfig struct {
+	EthController string `json:"ethController"`
+	EthURL        string `json:"ethUrl"`
+	ChainID       int64  `json:"chainId"`
+	MinGasPrice   int64  `json:"minGasPrice"`
+	RedeemGas     int    `json:"redeemGas"`
+	// Mainnet marks a network on which real funds are at stake
+	Mainnet bool `json:"mainnet"`
+}
+
+func defaultNetworkProfiles() map[string]*NetworkConfig {
+	return map[string]*NetworkConfig{
+		"rinkeby": {
+			EthController: "0x9a9827455911a858E55f07911904fACC0D66027E",
+			ChainID:       4,
+			RedeemGas:     redeemGasL1,
+		},
+		"arbitrum-one-rinkeby": {
+			EthController: "0x9ceC649179e2C7Ab91688271bcD09fb707b3E574",
+			ChainID:       421611,
+			RedeemGas:     redeemGasL2,
+		},
+		"mainnet": {
+			EthController: "0xf96d54e490317c557a967abfa5d6e33006be69b3",
+			ChainID:       1,
+			MinGasPrice:   int64(params.GWei),
+			RedeemGas:     redeemGasL1,
+			Mainnet:       true,
+		},
+		"arbitrum-one-mainnet": {
+			EthController: "0xD8E8328501E9645d16Cf49539efC04f734606ee4",
+			ChainID:       42161,
+			RedeemGas:     redeemGasL2,
+			Mainnet:       true,
+		},
+	}
+}
+
+// loadNetworkProfiles returns the built-in network profiles merged with the profiles from the JSON file at path, if provided.
+// Profiles in the file override built-in profiles with the same name
+func loadNetworkProfiles(path string) (map[string]*NetworkConfig, error) {
+	profiles := defaultNetworkProfiles()
+	if path == "" {
+		return profiles, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read network profiles: %v", err)
+	}
+
+	custom := make(map[string]*NetworkConfig)
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return nil, fmt.Errorf("unable to parse network profiles: %v", err)
+	}
+
+	for name, profile := range custom {
+		if name == "offchain" {
+			return nil, fmt.Errorf("network profile name %q is reserved", name)
+		}
+		if profile.EthController != "" && !ethcommon.IsHexAddress(profile.EthController) {
+			return nil, fmt.Errorf("invalid ethController for network profile %v: %v", name, profile.EthController)
+		}
+		if profile.RedeemGas == 0 {
+			profile.RedeemGas = redeemGasL1
+		}
+		profiles[name] = profile
+	}
+
+	return profiles, nil
+}
+
+// checkNetworkChainID makes sure that the ETH node the node is connected to is on the chain expected by the network profile
+func checkNetworkChainID(name string, profile *NetworkConfig, chainID *big.Int) error {
+	if profile == nil || profile.ChainID == 0 {
+		return nil
+	}
+
+	if big.NewInt(profile.ChainID).Cmp(chainID) != 0 {
+		return fmt.Errorf("network %v expects chainID %v, but the ETH node is on chainID %v. Check -ethUrl", name, profile.ChainID, chainID)
+	}
+
+	return nil
+}
+
+// networkProfileForChainID returns the name and the profile of the network profile with a Controller for chainID, i.e.
+// to connect to the protocol deployment of the chain that -ethUrl is on when -network does not name a profile.
+// Profiles are searched by name so that the result does not depend on the order of the map
+func networkProfileForChainID(profiles map[string]*NetworkConfig, chainID *big.Int) (string, *NetworkConfig) {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		profile := profiles[name]
+		if profile.EthController != "" && big.NewInt(profile.ChainID).Cmp(chainID) == 0 {
+			return name, profile
+		}
+	}
+	return "", nil
+}
+
+const accountNetworksFile = "networks.json"
+
+// checkOrStoreAccountNetwork records the networks each account in a keystore has been used with and refuses to use
+// an account that has been used on a mainnet network with a non-mainnet network, unless explicitly allowed.
+// This prevents a key holding real funds from being used against a devnet contract set by mistake
+func checkOrStoreAccountNetwork(keystoreDir string, addr ethcommon.Address, name string, profiles map[string]*NetworkConfig, allowMainnetKey bool) error {
+	path := filepath.Join(keystoreDir, accountNetworksFile)
+
+	accountNetworks := make(map[string][]string)
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &accountNetworks); err != nil {
+			return fmt.Errorf("unable to parse %v: %v", path, err)
+		}
+	}
+
+	key := strings.ToLower(addr.Hex())
+	profile, ok := profiles[name]
+	isMainnet := ok && profile.Mainnet
+
+	if !isMainnet && !allowMainnetKey {
+		for _, used := range accountNetworks[key] {
+			if p, ok := profiles[used]; ok && p.Mainnet {
+				return fmt.Errorf("ETH account %v has been used on the %v network and cannot be used on the %v network. Use a different account or set -ethAllowMainnetKey", addr.Hex(), used, name)
+			}
+		}
+	}
+
+	for _, used := range accountNetworks[key] {
+		if used == name {
+			return nil
+		}
+	}
+	accountNetworks[key] = append(accountNetworks[key], name)
+
+	data, err = json.MarshalIndent(accountNetworks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// overridableContracts are the contracts resolved from the Controller that can be overridden with -contractOverrides.
+// The PollCreator is not registered with the Controller and can only be set with -contractOverrides
+var overridableContracts = []string{
+	"LivepeerToken",
+	"LivepeerTokenFaucet",
+	"ServiceRegistry",
+	"BondingManager",
+	"TicketBroker",
+	"RoundsManager",
+	"Minter",
+	"PollCreator",
+}
+
+// parseContractOverrides parses a comma separated list of <ContractName>=<address> pairs
+func parseContractOverrides(overrides string) (map[string]ethcommon.Address, error) {
+	addrs := make(map[string]ethcommon.Address)
+	if overrides == "" {
+		return addrs, nil
+	}
+
+	for _, override := range strings.Split(overrides, ",") {
+		kv := strings.SplitN(strings.TrimSpace(override), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid contract override %q, expected <ContractName>=<address>", override)
+		}
+
+		name, addr := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if !isOverridableContract(name) {
+			return nil, fmt.Errorf("unknown contract %v, must be one of %v", name, strings.Join(overridableContracts, ", "))
+		}
+		if !ethcommon.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid address for contract %v: %v", name, addr)
+		}
+
+		addrs[name] = ethcommon.HexToAddress(addr)
+	}
+
+	return addrs, nil
+}
+
+func isOverridableContract(name string) bool {
+	for _, c := range overridableContracts {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadNetworkProfiles(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// No file uses the built-in profiles
+	profiles, err := loadNetworkProfiles("")
+	require.Nil(err)
+	assert.Equal(defaultNetworkProfiles(), profiles)
+
+	dir, err := ioutil.TempDir("", t.Name())
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	// Missing file
+	_, err = loadNetworkProfiles(filepath.Join(dir, "missing.json"))
+	assert.Contains(err.Error(), "unable to read network profiles")
+
+	// Invalid JSON
+	path := filepath.Join(dir, "profiles.json")
+	require.Nil(ioutil.WriteFile(path, []byte("foo"), 0644))
+	_, err = loadNetworkProfiles(path)
+	assert.Contains(err.Error(), "unable to parse network profiles")
+
+	// Reserved name
+	require.Nil(ioutil.WriteFile(path, []byte(`{"offchain": {}}`), 0644))
+	_, err = loadNetworkProfiles(path)
+	assert.EqualError(err, `network profile name "offchain" is reserved`)
+
+	// Invalid controller
+	require.Nil(ioutil.WriteFile(path, []byte(`{"devnet": {"ethController": "foo"}}`), 0644))
+	_, err = loadNetworkProfiles(path)
+	assert.EqualError(err, "invalid ethController for network profile devnet: foo")
+
+	// Custom profile is merged with the built-in profiles
+	require.Nil(ioutil.WriteFile(path, []byte(`{"devnet": {"ethController": "0x04B5a3B5b5E67B4c6B6a0e1aB3b1a7bEe3C1bB45", "ethUrl": "http://localhost:8545", "chainId": 54321}}`), 0644))
+	profiles, err = loadNetworkProfiles(path)
+	require.Nil(err)
+	assert.Len(profiles, len(defaultNetworkProfiles())+1)
+	assert.Equal("http://localhost:8545", profiles["devnet"].EthURL)
+	assert.Equal(int64(54321), profiles["devnet"].ChainID)
+	assert.Equal(redeemGasL1, profiles["devnet"].RedeemGas)
+	assert.False(profiles["devnet"].Mainnet)
+}
+
+func TestCheckNetworkChainID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(checkNetworkChainID("foo", nil, big.NewInt(1)))
+	assert.Nil(checkNetworkChainID("foo", &NetworkConfig{}, big.NewInt(1)))
+	assert.Nil(checkNetworkChainID("mainnet", &NetworkConfig{ChainID: 1}, big.NewInt(1)))
+	assert.EqualError(
+		checkNetworkChainID("mainnet", &NetworkConfig{ChainID: 1}, big.NewInt(4)),
+		"network mainnet expects chainID 1, but the ETH node is on chainID 4. Check -ethUrl",
+	)
+}
+
+func TestNetworkProfileForChainID(t *testing.T) {
+	assert := assert.New(t)
+
+	profiles := defaultNetworkProfiles()
+	profiles["devnet"] = &NetworkConfig{ChainID: 54321}
+
+	name, profile := networkProfileForChainID(profiles, big.NewInt(42161))
+	assert.Equal("arbitrum-one-mainnet", name)
+	assert.Equal(profiles["arbitrum-one-mainnet"], profile)
+
+	// Profiles without a Controller are skipped
+	name, profile = networkProfileForChainID(profiles, big.NewInt(54321))
+	assert.Equal("", name)
+	assert.Nil(profile)
+}
+
+func TestCheckOrStoreAccountNetwork(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", t.Name())
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	profiles := defaultNetworkProfiles()
+	profiles["devnet"] = &NetworkConfig{}
+	addr := pm.RandAddress()
+
+	// Unused account can be used on any network
+	assert.Nil(checkOrStoreAccountNetwork(dir, addr, "devnet", profiles, false))
+	assert.Nil(checkOrStoreAccountNetwork(dir, addr, "mainnet", profiles, false))
+	assert.Nil(checkOrStoreAccountNetwork(dir, addr, "mainnet", profiles, false))
+
+	// Account used on mainnet cannot be used on devnet
+	err = checkOrStoreAccountNetwork(dir, addr, "devnet", profiles, false)
+	assert.Contains(err.Error(), "has been used on the mainnet network and cannot be used on the devnet network")
+
+	// Unless explicitly allowed
+	assert.Nil(checkOrStoreAccountNetwork(dir, addr, "devnet", profiles, true))
+
+	// Other accounts are not affected
+	assert.Nil(checkOrStoreAccountNetwork(dir, pm.RandAddress(), "devnet", profiles, false))
+
+	// Invalid file
+	require.Nil(ioutil.WriteFile(filepath.Join(dir, accountNetworksFile), []byte("foo"), 0600))
+	err = checkOrStoreAccountNetwork(dir, addr, "devnet", profiles, false)
+	assert.Contains(err.Error(), "unable to parse")
+}
+
+func TestParseContractOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	addrs, err := parseContractOverrides("")
+	assert.Nil(err)
+	assert.Empty(addrs)
+
+	addrs, err = parseContractOverrides("BondingManager=0x1111111111111111111111111111111111111111, TicketBroker=0x2222222222222222222222222222222222222222")
+	assert.Nil(err)
+	assert.Equal(map[string]ethcommon.Address{
+		"BondingManager": ethcommon.HexToAddress("0x1111111111111111111111111111111111111111"),
+		"TicketBroker":   ethcommon.HexToAddress("0x2222222222222222222222222222222222222222"),
+	}, addrs)
+
+	_, err = parseContractOverrides("BondingManager")
+	assert.Contains(err.Error(), "expected <ContractName>=<address>")
+
+	_, err = parseContractOverrides("Foo=0x1111111111111111111111111111111111111111")
+	assert.Contains(err.Error(), "unknown contract Foo")
+
+	_, err = parseContractOverrides("BondingManager=foo")
+	assert.Contains(err.Error(), "invalid address for contract BondingManager")
+}
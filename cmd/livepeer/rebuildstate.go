@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
+	lpTypes "github.com/livepeer/go-livepeer/eth/types"
+	"github.com/livepeer/go-livepeer/eth/watchers"
+)
+
+// chainStateReader reads the on-chain state that the rebuilt local state is verified against
+type chainStateReader interface {
+	GetDelegator(ctx context.Context, addr ethcommon.Address) (*lpTypes.Delegator, error)
+	GetDelegatorUnbondingLock(ctx context.Context, addr ethcommon.Address, unbondingLockId *big.Int) (*lpTypes.UnbondingLock, error)
+	GetTranscoder(ctx context.Context, addr ethcommon.Address) (*lpTypes.Transcoder, error)
+	GetServiceURI(ctx context.Context, addr ethcommon.Address) (string, error)
+}
+
+// rebuildEventState clears the local state derived from contract events for addr, replays the events emitted by the
+// contracts at contractAddrs from fromBlock to the latest block and verifies the rebuilt state against the current chain state
+func rebuildEventState(ctx context.Context, dbh *common.DB, client blockwatch.Client, lpEth chainStateReader, addr ethcommon.Address,
+	contractAddrs []ethcommon.Address, fromBlock *big.Int, handlers ...watchers.LogHandler) error {
+
+	head, err := client.HeaderByNumber(nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the latest block: %v", err)
+	}
+
+	glog.Infof("Rebuilding local state from contract events fromBlock=%v toBlock=%v", fromBlock, head.Number)
+
+	if err := dbh.DeleteUnbondingLocks(addr); err != nil {
+		return err
+	}
+
+	processed, err := watchers.NewEventReplayer(client, contractAddrs, handlers...).Replay(ctx, fromBlock, head.Number)
+	if err != nil {
+		return err
+	}
+
+	glog.Infof("Replayed %v contract events", processed)
+
+	if err := verifyUnbondingLocks(ctx, dbh, lpEth, addr); err != nil {
+		return err
+	}
+
+	return verifyOrchestrators(ctx, dbh, lpEth)
+}
+
+// verifyUnbondingLocks checks that the unused unbonding locks stored for addr match the unbonding locks on-chain
+func verifyUnbondingLocks(ctx context.Context, dbh *common.DB, lpEth chainStateReader, addr ethcommon.Address) error {
+	locks, err := dbh.UnbondingLocks(nil)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[int64]*common.DBUnbondingLock)
+	for _, l := range locks {
+		if l.Delegator == addr {
+			stored[l.ID] = l
+		}
+	}
+
+	delegator, err := lpEth.GetDelegator(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []string
+	for id := int64(0); delegator.NextUnbondingLockId != nil && id < delegator.NextUnbondingLockId.Int64(); id++ {
+		lock, err := lpEth.GetDelegatorUnbondingLock(ctx, addr, big.NewInt(id))
+		if err != nil {
+			return err
+		}
+
+		s, ok := stored[id]
+		delete(stored, id)
+
+		// Used unbonding locks are deleted on-chain
+		if lock.Amount.Sign() == 0 {
+			if ok {
+				mismatches = append(mismatches, fmt.Sprintf("lock %v is used on-chain but unused locally", id))
+			}
+			continue
+		}
+
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("lock %v is missing locally", id))
+			continue
+		}
+
+		if s.Amount.Cmp(lock.Amount) != 0 || s.WithdrawRound != lock.WithdrawRound.Int64() {
+			mismatches = append(mismatches, fmt.Sprintf("lock %v is amount=%v withdrawRound=%v on-chain but amount=%v withdrawRound=%v locally", id, lock.Amount, lock.WithdrawRound, s.Amount, s.WithdrawRound))
+		}
+	}
+
+	extra := make([]int64, 0, len(stored))
+	for id := range stored {
+		extra = append(extra, id)
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+	for _, id := range extra {
+		mismatches = append(mismatches, fmt.Sprintf("lock %v does not exist on-chain", id))
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("rebuilt unbonding locks are inconsistent with the chain: %v", strings.Join(mismatches, "; "))
+	}
+
+	glog.Infof("Verified rebuilt unbonding locks against the chain")
+
+	return nil
+}
+
+// verifyOrchestrators checks that the activation rounds and service URIs stored for the orchestrators match the
+// orchestrators on-chain
+func verifyOrchestrators(ctx context.Context, dbh *common.DB, lpEth chainStateReader) error {
+	orchs, err := dbh.SelectOrchs(nil)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []string
+	for _, o := range orchs {
+		addr := ethcommon.HexToAddress(o.EthereumAddr)
+
+		t, err := lpEth.GetTranscoder(ctx, addr)
+		if err != nil {
+			return err
+		}
+
+		activationRound := common.ToInt64(t.ActivationRound)
+		deactivationRound := common.ToInt64(t.DeactivationRound)
+		if o.ActivationRound != activationRound || o.DeactivationRound != deactivationRound {
+			mismatches = append(mismatches, fmt.Sprintf("orchestrator %v is activationRound=%v deactivationRound=%v on-chain but activationRound=%v deactivationRound=%v locally",
+				addr.Hex(), activationRound, deactivationRound, o.ActivationRound, o.DeactivationRound))
+		}
+
+		uri, err := lpEth.GetServiceURI(ctx, addr)
+		if err != nil {
+			return err
+		}
+
+		if o.ServiceURI != uri {
+			mismatches = append(mismatches, fmt.Sprintf("orchestrator %v is serviceURI=%v on-chain but serviceURI=%v locally", addr.Hex(), uri, o.ServiceURI))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("rebuilt orchestrators are inconsistent with the chain: %v", strings.Join(mismatches, "; "))
+	}
+
+	glog.Infof("Verified %v rebuilt orchestrators against the chain", len(orchs))
+
+	return nil
+}
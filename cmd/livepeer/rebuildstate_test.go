@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/common"
+	lpTypes "github.com/livepeer/go-livepeer/eth/types"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubChainStateReader struct {
+	locks       map[int64]*lpTypes.UnbondingLock
+	transcoders map[ethcommon.Address]*lpTypes.Transcoder
+}
+
+func (r *stubChainStateReader) GetDelegator(ctx context.Context, addr ethcommon.Address) (*lpTypes.Delegator, error) {
+	return &lpTypes.Delegator{NextUnbondingLockId: big.NewInt(int64(len(r.locks)))}, nil
+}
+
+func (r *stubChainStateReader) GetDelegatorUnbondingLock(ctx context.Context, addr ethcommon.Address, unbondingLockId *big.Int) (*lpTypes.UnbondingLock, error) {
+	return r.locks[unbondingLockId.Int64()], nil
+}
+
+func (r *stubChainStateReader) GetTranscoder(ctx context.Context, addr ethcommon.Address) (*lpTypes.Transcoder, error) {
+	if t, ok := r.transcoders[addr]; ok {
+		return t, nil
+	}
+	return &lpTypes.Transcoder{Address: addr, ActivationRound: big.NewInt(0), DeactivationRound: big.NewInt(0)}, nil
+}
+
+func (r *stubChainStateReader) GetServiceURI(ctx context.Context, addr ethcommon.Address) (string, error) {
+	if t, ok := r.transcoders[addr]; ok {
+		return t.ServiceURI, nil
+	}
+	return "", nil
+}
+
+func TestVerifyUnbondingLocks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	addr := pm.RandAddress()
+	reader := &stubChainStateReader{
+		locks: map[int64]*lpTypes.UnbondingLock{
+			0: {Amount: big.NewInt(0), WithdrawRound: big.NewInt(0)},
+			1: {Amount: big.NewInt(10), WithdrawRound: big.NewInt(100)},
+		},
+	}
+
+	// Lock missing locally
+	err = verifyUnbondingLocks(context.Background(), dbh, reader, addr)
+	assert.EqualError(err, "rebuilt unbonding locks are inconsistent with the chain: lock 1 is missing locally")
+
+	// Consistent state
+	require.Nil(dbh.InsertUnbondingLock(big.NewInt(1), addr, big.NewInt(10), big.NewInt(100)))
+	assert.Nil(verifyUnbondingLocks(context.Background(), dbh, reader, addr))
+
+	// Locks of other delegators are ignored
+	require.Nil(dbh.InsertUnbondingLock(big.NewInt(5), pm.RandAddress(), big.NewInt(10), big.NewInt(100)))
+	assert.Nil(verifyUnbondingLocks(context.Background(), dbh, reader, addr))
+
+	// Inconsistent state
+	require.Nil(dbh.InsertUnbondingLock(big.NewInt(0), addr, big.NewInt(10), big.NewInt(100)))
+	require.Nil(dbh.InsertUnbondingLock(big.NewInt(2), addr, big.NewInt(10), big.NewInt(100)))
+	reader.locks[1].Amount = big.NewInt(20)
+	err = verifyUnbondingLocks(context.Background(), dbh, reader, addr)
+	assert.EqualError(err, "rebuilt unbonding locks are inconsistent with the chain: lock 0 is used on-chain but unused locally; "+
+		"lock 1 is amount=20 withdrawRound=100 on-chain but amount=10 withdrawRound=100 locally; lock 2 does not exist on-chain")
+}
+
+func TestVerifyOrchestrators(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	active := pm.RandAddress()
+	registered := pm.RandAddress()
+	maxFutureRound, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	reader := &stubChainStateReader{
+		transcoders: map[ethcommon.Address]*lpTypes.Transcoder{
+			active: {
+				Address:           active,
+				ServiceURI:        "https://127.0.0.1:8935",
+				ActivationRound:   big.NewInt(5),
+				DeactivationRound: maxFutureRound,
+			},
+			registered: {
+				Address:           registered,
+				ServiceURI:        "https://127.0.0.1:9935",
+				ActivationRound:   big.NewInt(0),
+				DeactivationRound: big.NewInt(0),
+			},
+		},
+	}
+
+	// No orchestrators stored
+	assert.Nil(verifyOrchestrators(context.Background(), dbh, reader))
+
+	// Consistent state
+	require.Nil(dbh.UpdateOrch(common.NewDBOrch(active.String(), "https://127.0.0.1:8935", 0, 5, math.MaxInt64, 0)))
+	require.Nil(dbh.UpdateOrch(common.NewDBOrch(registered.String(), "https://127.0.0.1:9935", 0, 0, 0, 0)))
+	assert.Nil(verifyOrchestrators(context.Background(), dbh, reader))
+
+	// Inconsistent state
+	reader.transcoders[active].DeactivationRound = big.NewInt(10)
+	reader.transcoders[registered].ServiceURI = "https://127.0.0.1:10935"
+	err = verifyOrchestrators(context.Background(), dbh, reader)
+	require.NotNil(err)
+	assert.Contains(err.Error(), "rebuilt orchestrators are inconsistent with the chain: ")
+	assert.Contains(err.Error(), fmt.Sprintf("orchestrator %v is activationRound=5 deactivationRound=10 on-chain but activationRound=5 deactivationRound=%v locally", active.Hex(), int64(math.MaxInt64)))
+	assert.Contains(err.Error(), fmt.Sprintf("orchestrator %v is serviceURI=https://127.0.0.1:10935 on-chain but serviceURI=https://127.0.0.1:9935 locally", registered.Hex()))
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/verification"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/livepeer/m3u8"
+	"github.com/olekukonko/tablewriter"
+)
+
+// compareRendition is the result of transcoding the source segment to a single profile
+type compareRendition struct {
+	profile ffmpeg.VideoProfile
+	fname   string
+	size    int64
+	ssim    string
+	vmaf    string
+}
+
+// compareResult is the result of transcoding the source segment with an option set
+type compareResult struct {
+	elapsed    time.Duration
+	renditions []*compareRendition
+}
+
+// runCompare transcodes the same source segment with the option sets a and b and prints the size of each rendition,
+// the average transcoding time and, if an ffmpeg binary with the ssim or libvmaf filters is in the PATH, the quality
+// of each rendition compared to the source
+func runCompare(in, a, b string, accel ffmpeg.Acceleration, devices []string, outPrefix string, repeat int) {
+	src, err := compareSource(in)
+	if err != nil {
+		glog.Fatal("Couldn't find source segment: ", err)
+	}
+
+	prefix := outPrefix
+	if prefix == "" {
+		dir, err := ioutil.TempDir("", "livepeer_bench_compare")
+		if err != nil {
+			glog.Fatal("Couldn't create output directory: ", err)
+		}
+		defer os.RemoveAll(dir)
+		prefix = path.Join(dir, "compare")
+	}
+
+	device := ""
+	if accel != ffmpeg.Software && len(devices) > 0 {
+		device = devices[0]
+	}
+
+	ffmpeg.InitFFmpegWithLogLevel(ffmpeg.FFLogWarning)
+	metrics := verification.AvailableQualityMetrics("ffmpeg")
+	if len(metrics) == 0 {
+		glog.Warning("No ffmpeg binary with the ssim or libvmaf filters found in the PATH, skipping quality metrics")
+	}
+
+	var results []*compareResult
+	for i, opts := range []string{a, b} {
+		label := string(rune('a' + i))
+		res, err := compareTranscode(src, parseVideoProfiles(opts), accel, device, prefix+"_"+label, repeat)
+		if err != nil {
+			glog.Fatalf("Transcoding failed for option set %s=%q: %v", label, opts, err)
+		}
+		for _, r := range res.renditions {
+			r.ssim, r.vmaf = "n/a", "n/a"
+			if metrics[verification.QualitySSIM] {
+				r.ssim = formatQuality(verification.ScoreQuality("ffmpeg", verification.QualitySSIM, src, r.fname))
+			}
+			if metrics[verification.QualityVMAF] {
+				r.vmaf = formatQuality(verification.ScoreQuality("ffmpeg", verification.QualityVMAF, src, r.fname))
+			}
+		}
+		results = append(results, res)
+	}
+
+	table := tablewriter.NewWriter(os.Stderr)
+	table.SetHeader([]string{"Set", "Profile", "Resolution", "Bitrate", "Size (bytes)", "SSIM", "VMAF", "Transcode Time"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("*")
+	table.SetColumnSeparator("|")
+	for i, res := range results {
+		label := string(rune('a' + i))
+		for _, r := range res.renditions {
+			table.Append([]string{label, r.profile.Name, r.profile.Resolution, r.profile.Bitrate, strconv.FormatInt(r.size, 10),
+				r.ssim, r.vmaf, fmt.Sprintf("%0.4vs", res.elapsed.Seconds())})
+		}
+	}
+	table.Render()
+}
+
+// compareSource returns the source segment for a comparison. If in is a media playlist, the first segment is used
+func compareSource(in string) (string, error) {
+	if filepath.Ext(in) != ".m3u8" {
+		return in, nil
+	}
+
+	f, err := os.Open(in)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	p, _, err := m3u8.DecodeFrom(bufio.NewReader(f), true)
+	if err != nil {
+		return "", err
+	}
+	pl, ok := p.(*m3u8.MediaPlaylist)
+	if !ok {
+		return "", fmt.Errorf("expecting media playlist in the input %s", in)
+	}
+	for _, seg := range pl.Segments {
+		if seg != nil {
+			return path.Join(path.Dir(in), seg.URI), nil
+		}
+	}
+	return "", errors.New("input manifest has no segments")
+}
+
+// compareTranscode transcodes src to profiles repeat times and returns the renditions of the last run together with
+// the average transcoding time
+func compareTranscode(src string, profiles []ffmpeg.VideoProfile, accel ffmpeg.Acceleration, device, prefix string, repeat int) (*compareResult, error) {
+	if repeat < 1 {
+		repeat = 1
+	}
+
+	res := &compareResult{}
+	var out []ffmpeg.TranscodeOptions
+	for n, p := range profiles {
+		fname := fmt.Sprintf("%s_%s_%d.ts", prefix, p.Name, n)
+		out = append(out, ffmpeg.TranscodeOptions{
+			Oname:        fname,
+			Profile:      p,
+			Accel:        accel,
+			AudioEncoder: ffmpeg.ComponentOptions{Name: "copy"},
+			Muxer:        ffmpeg.ComponentOptions{Name: "mpegts"},
+		})
+		res.renditions = append(res.renditions, &compareRendition{profile: p, fname: fname})
+	}
+
+	var total time.Duration
+	for i := 0; i < repeat; i++ {
+		tc := ffmpeg.NewTranscoder()
+		start := time.Now()
+		_, err := tc.Transcode(&ffmpeg.TranscodeOptionsIn{Fname: src, Accel: accel, Device: device}, out)
+		total += time.Since(start)
+		tc.StopTranscoder()
+		if err != nil {
+			return nil, err
+		}
+	}
+	res.elapsed = total / time.Duration(repeat)
+
+	for _, r := range res.renditions {
+		info, err := os.Stat(r.fname)
+		if err != nil {
+			return nil, err
+		}
+		r.size = info.Size()
+	}
+
+	return res, nil
+}
+
+func formatQuality(score float64, err error) string {
+	if err != nil {
+		glog.Warningf("Couldn't measure quality: %v", err)
+		return "error"
+	}
+	return fmt.Sprintf("%0.4f", score)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSource(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// Segments are used as is
+	src, err := compareSource("foo/seg.ts")
+	assert.Nil(err)
+	assert.Equal("foo/seg.ts", src)
+
+	dir, err := ioutil.TempDir("", "compare")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	// The first segment of a manifest is used
+	manifest := path.Join(dir, "in.m3u8")
+	require.Nil(ioutil.WriteFile(manifest, []byte("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:2\n#EXTINF:2.000,\nseg0.ts\n#EXTINF:2.000,\nseg1.ts\n"), 0644))
+	src, err = compareSource(manifest)
+	assert.Nil(err)
+	assert.Equal(path.Join(dir, "seg0.ts"), src)
+
+	// Manifest without segments
+	require.Nil(ioutil.WriteFile(manifest, []byte("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:2\n"), 0644))
+	_, err = compareSource(manifest)
+	assert.EqualError(err, "input manifest has no segments")
+
+	_, err = compareSource(path.Join(dir, "missing.m3u8"))
+	assert.NotNil(err)
+}
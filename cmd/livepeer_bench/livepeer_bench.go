@@ -39,6 +39,10 @@ func main() {
 	detectionSampleRate := flag.Uint("detectionSampleRate", 1, "Run content-detection on every nth frame of a particular segment, if detectionFreq > 0.")
 	concurrentSessionDelay := flag.Duration("concurrentSessionDelay", 300*time.Millisecond, "Delay before starting a new concurrent session")
 	sign := flag.Bool("mpeg7Sign", false, "Calculate MPEG-7 video signature while transcoding")
+	orchAddr := flag.String("orchAddr", "", "Orchestrator to load test. Simulates -concurrentSessions broadcasters that stream the segments of -in to the orchestrator and reports latency percentiles and error rates")
+	segmentRate := flag.Float64("segmentRate", 0, "Segments per second submitted by each simulated broadcaster when load testing an orchestrator (default real-time)")
+	replay := flag.String("replay", "", "File of RPC exchanges recorded by a broadcaster with -rpcRecordFile to replay against the orchestrator at -orchAddr. Segment payloads are taken from the segments of -in")
+	compare := flag.String("compare", "", "Transcoding options to compare against -transcodingOptions. Transcodes a single segment (-in may be a segment or a manifest) with both option sets and reports size, quality and timing")
 
 	flag.Parse()
 
@@ -48,8 +52,37 @@ func main() {
 		os.Exit(1)
 	}
 
+	accel := ffmpeg.Software
+	devices := []string{}
+	if *nvidia != "" {
+		var err error
+		accel = ffmpeg.Nvidia
+		devices, err = common.ParseNvidiaDevices(*nvidia)
+		if err != nil {
+			glog.Fatalf("Error while parsing '-nvidia %v' flag: %v", *nvidia, err)
+		}
+	}
+
+	if *compare != "" {
+		runCompare(*in, *transcodingOptions, *compare, accel, devices, *outPrefix, *repeat)
+		return
+	}
+
+	if *replay != "" {
+		if *orchAddr == "" {
+			glog.Fatal("Please provide the orchestrator to replay the exchanges against with -orchAddr")
+		}
+		runReplay(*in, *replay, *orchAddr)
+		return
+	}
+
 	profiles := parseVideoProfiles(*transcodingOptions)
 
+	if *orchAddr != "" {
+		runLoad(*in, *orchAddr, *concurrentSessions, *segs, *segmentRate, profiles, *concurrentSessionDelay)
+		return
+	}
+
 	f, err := os.Open(*in)
 	if err != nil {
 		glog.Fatal("Couldn't open input manifest: ", err)
@@ -63,16 +96,6 @@ func main() {
 		glog.Fatalf("Expecting media playlist in the input %s", *in)
 	}
 
-	accel := ffmpeg.Software
-	devices := []string{}
-	if *nvidia != "" {
-		var err error
-		accel = ffmpeg.Nvidia
-		devices, err = common.ParseNvidiaDevices(*nvidia)
-		if err != nil {
-			glog.Fatalf("Error while parsing '-nvidia %v' flag: %v", *nvidia, err)
-		}
-	}
 	var wg sync.WaitGroup
 	dir := path.Dir(*in)
 
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/server"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/livepeer/lpms/stream"
+	"github.com/livepeer/m3u8"
+	"github.com/olekukonko/tablewriter"
+)
+
+// loadSegment is a canned segment that is streamed by every simulated broadcaster
+type loadSegment struct {
+	data     []byte
+	duration float64
+}
+
+// loadStats collects the results of the segments submitted by the simulated broadcasters
+type loadStats struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	realTime  int
+	errors    map[string]int
+}
+
+func newLoadStats() *loadStats {
+	return &loadStats{errors: make(map[string]int)}
+}
+
+func (s *loadStats) record(latency time.Duration, seg loadSegment, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.errors[err.Error()]++
+		return
+	}
+	s.latencies = append(s.latencies, latency)
+	if latency.Seconds() <= seg.duration {
+		s.realTime++
+	}
+}
+
+func (s *loadStats) failed() int {
+	failed := 0
+	for _, n := range s.errors {
+		failed += n
+	}
+	return failed
+}
+
+// percentile returns the p-th percentile of the latencies of successful segments using the nearest rank method
+func (s *loadStats) percentile(p float64) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), s.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// runLoad simulates streams concurrent broadcasters that stream the segments of the input manifest to the orchestrator
+// at orchAddr and reports the latency percentiles and the error rate of the segments. Each broadcaster submits rate
+// segments per second or streams in real time if rate is 0. The orchestrator must accept segments without payments
+func runLoad(in, orchAddr string, streams, segs int, rate float64, profiles []ffmpeg.VideoProfile, sessionDelay time.Duration) {
+	canned, err := loadSegments(in, segs)
+	if err != nil {
+		glog.Fatal("Couldn't load input segments: ", err)
+	}
+	orchURL, err := parseOrchURL(orchAddr)
+	if err != nil {
+		glog.Fatal("Couldn't parse orchestrator address: ", err)
+	}
+
+	table := tablewriter.NewWriter(os.Stderr)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("*")
+	table.SetColumnSeparator("|")
+	table.AppendBulk([][]string{
+		{"Source File", in},
+		{"Orchestrator", orchURL.String()},
+		{"Concurrent Streams", strconv.Itoa(streams)},
+		{"Segments Per Stream", strconv.Itoa(len(canned))},
+		{"Segment Rate", formatLoadRate(rate)},
+	})
+	table.Render()
+
+	stats := newLoadStats()
+	start := time.Now()
+	var wg sync.WaitGroup
+	for k := 0; k < streams; k++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			runLoadStream(k, orchURL, canned, rate, profiles, stats)
+		}(k)
+		time.Sleep(sessionDelay)
+	}
+	wg.Wait()
+
+	printLoadStats(stats, time.Since(start))
+}
+
+// runLoadStream streams the canned segments to the orchestrator as a single broadcaster
+func runLoadStream(k int, orchURL *url.URL, canned []loadSegment, rate float64, profiles []ffmpeg.VideoProfile, stats *loadStats) {
+	ctx := context.Background()
+	mid := core.ManifestID(fmt.Sprintf("load_%d_%s", k, common.RandomIDGenerator(8)))
+	params := &core.StreamParameters{
+		ManifestID: mid,
+		Profiles:   profiles,
+		OS:         drivers.NewMemoryDriver(nil).NewSession(string(mid)),
+	}
+	caps, err := core.JobCapabilities(params)
+	if err != nil {
+		glog.Fatal("Couldn't get job capabilities: ", err)
+	}
+	params.Capabilities = caps
+
+	bcast := core.NewBroadcaster(nil)
+	info, err := server.GetOrchestratorInfo(ctx, bcast, orchURL)
+	if err != nil {
+		glog.Errorf("Couldn't get orchestrator info for stream %d: %v", k, err)
+		for _, seg := range canned {
+			stats.record(0, seg, err)
+		}
+		return
+	}
+	sess := server.NewOffchainBroadcastSession(bcast, params, info)
+
+	for seqNo, seg := range canned {
+		iterStart := time.Now()
+		hlsSeg := &stream.HLSSegment{SeqNo: uint64(seqNo), Data: seg.data, Duration: seg.duration, Name: ""}
+		res, err := server.SubmitSegment(ctx, sess, hlsSeg, uint64(k), false, true)
+		latency := time.Since(iterStart)
+		if err == nil && res == nil {
+			err = errors.New("empty response")
+		}
+		stats.record(latency, seg, err)
+		fmt.Printf("%s,%d,%d,%0.4v,%0.4v,%v\n", time.Now().Format("2006-01-02 15:04:05.9999"), k, seqNo, seg.duration, latency.Seconds(), err)
+		if err == nil && res.Info != nil {
+			// The orchestrator may refresh the session's ticket params, price or auth token
+			sess.OrchestratorInfo = res.Info
+		}
+
+		interval := time.Duration(seg.duration * float64(time.Second))
+		if rate > 0 {
+			interval = time.Duration(float64(time.Second) / rate)
+		}
+		time.Sleep(interval - time.Since(iterStart))
+	}
+}
+
+// loadSegments reads at most segs segments (all segments if segs is 0) of the input manifest into memory
+func loadSegments(in string, segs int) ([]loadSegment, error) {
+	f, err := os.Open(in)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, _, err := m3u8.DecodeFrom(bufio.NewReader(f), true)
+	if err != nil {
+		return nil, err
+	}
+	pl, ok := p.(*m3u8.MediaPlaylist)
+	if !ok {
+		return nil, fmt.Errorf("expecting media playlist in the input %s", in)
+	}
+
+	var canned []loadSegment
+	for _, seg := range pl.Segments {
+		if seg == nil {
+			continue
+		}
+		if segs > 0 && len(canned) >= segs {
+			break
+		}
+		data, err := ioutil.ReadFile(path.Join(path.Dir(in), seg.URI))
+		if err != nil {
+			return nil, err
+		}
+		canned = append(canned, loadSegment{data: data, duration: seg.Duration})
+	}
+	if len(canned) == 0 {
+		return nil, errors.New("input manifest has no segments")
+	}
+	return canned, nil
+}
+
+func parseOrchURL(addr string) (*url.URL, error) {
+	addr = strings.TrimSpace(addr)
+	if !strings.HasPrefix(addr, "http") {
+		addr = "https://" + addr
+	}
+	return url.ParseRequestURI(addr)
+}
+
+func formatLoadRate(rate float64) string {
+	if rate <= 0 {
+		return "real-time"
+	}
+	return fmt.Sprintf("%v segs/s per stream", rate)
+}
+
+func printLoadStats(stats *loadStats, elapsed time.Duration) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	succeeded, failed := len(stats.latencies), stats.failed()
+	total := succeeded + failed
+	if total == 0 {
+		glog.Fatal("No segments were submitted")
+	}
+
+	statsTable := tablewriter.NewWriter(os.Stderr)
+	statsTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	statsTable.SetCenterSeparator("*")
+	statsTable.SetColumnSeparator("|")
+	statsTable.AppendBulk([][]string{
+		{"Total Segs Submitted", strconv.Itoa(total)},
+		{"Segs Transcoded", strconv.Itoa(succeeded)},
+		{"* Error Rate *", fmt.Sprintf("%0.4v", float64(failed)/float64(total))},
+		{"Real-Time Segs Transcoded", strconv.Itoa(stats.realTime)},
+		{"Latency p50", fmt.Sprintf("%0.4vs", stats.percentile(50).Seconds())},
+		{"Latency p90", fmt.Sprintf("%0.4vs", stats.percentile(90).Seconds())},
+		{"* Latency p99 *", fmt.Sprintf("%0.4vs", stats.percentile(99).Seconds())},
+		{"Latency max", fmt.Sprintf("%0.4vs", stats.percentile(100).Seconds())},
+		{"Elapsed", fmt.Sprintf("%0.4vs", elapsed.Seconds())},
+	})
+	statsTable.Render()
+
+	if failed == 0 {
+		return
+	}
+	errTable := tablewriter.NewWriter(os.Stderr)
+	errTable.SetHeader([]string{"Error", "Count"})
+	errTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	errTable.SetCenterSeparator("*")
+	errTable.SetColumnSeparator("|")
+	for msg, n := range stats.errors {
+		errTable.Append([]string{msg, strconv.Itoa(n)})
+	}
+	errTable.Render()
+}
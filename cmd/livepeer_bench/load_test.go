@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSegments(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "load")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	manifest := path.Join(dir, "in.m3u8")
+	require.Nil(ioutil.WriteFile(manifest, []byte("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:2\n#EXTINF:2.000,\nseg0.ts\n#EXTINF:1.500,\nseg1.ts\n"), 0644))
+	require.Nil(ioutil.WriteFile(path.Join(dir, "seg0.ts"), []byte("seg0"), 0644))
+	require.Nil(ioutil.WriteFile(path.Join(dir, "seg1.ts"), []byte("seg1"), 0644))
+
+	// All segments are loaded
+	segs, err := loadSegments(manifest, 0)
+	assert.Nil(err)
+	assert.Equal([]loadSegment{{data: []byte("seg0"), duration: 2}, {data: []byte("seg1"), duration: 1.5}}, segs)
+
+	// The number of segments is limited
+	segs, err = loadSegments(manifest, 1)
+	assert.Nil(err)
+	assert.Equal([]loadSegment{{data: []byte("seg0"), duration: 2}}, segs)
+
+	// Missing segment
+	require.Nil(os.Remove(path.Join(dir, "seg1.ts")))
+	_, err = loadSegments(manifest, 0)
+	assert.NotNil(err)
+
+	// Manifest without segments
+	require.Nil(ioutil.WriteFile(manifest, []byte("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:2\n"), 0644))
+	_, err = loadSegments(manifest, 0)
+	assert.EqualError(err, "input manifest has no segments")
+}
+
+func TestLoadStats(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := newLoadStats()
+	assert.Equal(time.Duration(0), stats.percentile(50))
+
+	seg := loadSegment{duration: 2}
+	for i := 1; i <= 100; i++ {
+		stats.record(time.Duration(i)*50*time.Millisecond, seg, nil)
+	}
+	stats.record(0, seg, errors.New("boom"))
+	stats.record(0, seg, errors.New("boom"))
+	stats.record(0, seg, errors.New("timeout"))
+
+	assert.Len(stats.latencies, 100)
+	assert.Equal(3, stats.failed())
+	assert.Equal(map[string]int{"boom": 2, "timeout": 1}, stats.errors)
+	// Segments transcoded within their duration are real-time
+	assert.Equal(40, stats.realTime)
+	assert.Equal(2500*time.Millisecond, stats.percentile(50))
+	assert.Equal(4500*time.Millisecond, stats.percentile(90))
+	assert.Equal(4950*time.Millisecond, stats.percentile(99))
+	assert.Equal(5000*time.Millisecond, stats.percentile(100))
+	assert.Equal(50*time.Millisecond, stats.percentile(0))
+}
+
+func TestParseOrchURL(t *testing.T) {
+	assert := assert.New(t)
+
+	u, err := parseOrchURL("127.0.0.1:8935")
+	assert.Nil(err)
+	assert.Equal("https://127.0.0.1:8935", u.String())
+
+	u, err = parseOrchURL(" https://orch.example.com:8935 ")
+	assert.Nil(err)
+	assert.Equal("https://orch.example.com:8935", u.String())
+}
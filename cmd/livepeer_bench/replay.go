@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/server"
+	"github.com/olekukonko/tablewriter"
+)
+
+// runReplay replays the RPC exchanges recorded by a broadcaster with -rpcRecordFile against the orchestrator at
+// orchAddr and reports the exchanges whose outcome differs from the recorded one. The payloads of recorded segments
+// are looked up among the segments of the input manifest
+func runReplay(in, record, orchAddr string) {
+	exchanges, err := server.ReadRPCExchanges(record)
+	if err != nil {
+		glog.Fatal("Couldn't read recorded RPC exchanges: ", err)
+	}
+	canned, err := loadSegments(in, 0)
+	if err != nil {
+		glog.Fatal("Couldn't load input segments: ", err)
+	}
+	orchURL, err := parseOrchURL(orchAddr)
+	if err != nil {
+		glog.Fatal("Couldn't parse orchestrator address: ", err)
+	}
+
+	results := server.ReplayRPC(context.Background(), exchanges, orchURL, replaySegments(canned))
+
+	table := tablewriter.NewWriter(os.Stderr)
+	table.SetHeader([]string{"#", "Method", "Recorded Version", "Status", "Error", "Mismatch", "Latency"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("*")
+	table.SetColumnSeparator("|")
+	skipped, mismatches := 0, 0
+	for i, res := range results {
+		if res.Skipped {
+			skipped++
+			continue
+		}
+		if res.Mismatch != "" {
+			mismatches++
+		}
+		table.Append([]string{strconv.Itoa(i), res.Exchange.Method, res.Exchange.Version, strconv.Itoa(res.Status), res.Error,
+			res.Mismatch, fmt.Sprintf("%0.4vs", res.Latency.Seconds())})
+	}
+	table.Render()
+
+	fmt.Fprintf(os.Stderr, "Replayed %d of %d exchanges, %d skipped without payload, %d mismatches\n",
+		len(results)-skipped, len(results), skipped, mismatches)
+	if mismatches > 0 {
+		os.Exit(1)
+	}
+}
+
+// replaySegments indexes the payloads of the segments by their hash in recorded exchanges
+func replaySegments(canned []loadSegment) map[string][]byte {
+	segments := make(map[string][]byte)
+	for _, seg := range canned {
+		segments[server.HashSegment(seg.data)] = seg.data
+	}
+	return segments
+}
@@ -180,6 +180,10 @@ func (w *wizard) bond() {
 		"toAddr": {fmt.Sprintf("%v", tAddr.Hex())},
 	}
 
+	if !w.confirmTxCost("bond", val) {
+		return
+	}
+
 	httpPostWithParams(fmt.Sprintf("http://%v:%v/bond", w.host, w.httpPort), val)
 }
 
@@ -240,6 +244,10 @@ func (w *wizard) rebond() {
 		val["toAddr"] = []string{fmt.Sprintf("%v", toAddr.Hex())}
 	}
 
+	if !w.confirmTxCost("rebond", val) {
+		return
+	}
+
 	httpPostWithParams(fmt.Sprintf("http://%v:%v/rebond", w.host, w.httpPort), val)
 }
 
@@ -288,6 +296,10 @@ func (w *wizard) unbond() {
 		"amount": {fmt.Sprintf("%v", amount.String())},
 	}
 
+	if !w.confirmTxCost("unbond", val) {
+		return
+	}
+
 	httpPostWithParams(fmt.Sprintf("http://%v:%v/unbond", w.host, w.httpPort), val)
 }
 
@@ -325,6 +337,10 @@ func (w *wizard) withdrawStake() {
 		"unbondingLockId": {fmt.Sprintf("%v", strconv.FormatInt(unbondingLockID, 10))},
 	}
 
+	if !w.confirmTxCost("withdrawStake", val) {
+		return
+	}
+
 	httpPostWithParams(fmt.Sprintf("http://%v:%v/withdrawStake", w.host, w.httpPort), val)
 }
 
@@ -339,5 +355,9 @@ func (w *wizard) withdrawFees() {
 		"amount": {fmt.Sprintf("%v", dInfo.PendingFees.String())},
 	}
 
+	if !w.confirmTxCost("withdrawFees", val) {
+		return
+	}
+
 	httpPostWithParams(fmt.Sprintf("http://%v:%v/withdrawFees", w.host, w.httpPort), val)
 }
@@ -1,10 +1,38 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
+
+	"github.com/livepeer/go-livepeer/eth"
 )
 
+// confirmTxCost shows the estimated cost of the transaction that the endpoint of method would submit with val and asks
+// whether to submit it. The transaction is submitted without asking if the cost cannot be estimated
+func (w *wizard) confirmTxCost(method string, val url.Values) bool {
+	params := url.Values{"method": {method}}
+	for k, v := range val {
+		params[k] = v
+	}
+	result, ok := httpPostWithParams(fmt.Sprintf("http://%v:%v/estimateTxCost", w.host, w.httpPort), params)
+	if !ok {
+		fmt.Printf("Could not estimate the transaction cost: %v\n", result)
+		return true
+	}
+
+	var cost eth.TxCost
+	if err := json.Unmarshal([]byte(result), &cost); err != nil {
+		fmt.Printf("Could not estimate the transaction cost: %v\n", err)
+		return true
+	}
+
+	fmt.Printf("Estimated transaction cost: %v (at most %v)\n", eth.FormatUnits(cost.Cost, "ETH"), eth.FormatUnits(cost.MaxCost, "ETH"))
+	fmt.Printf("Would you like to submit the transaction? (y/n) - ")
+
+	return w.readStringYesOrNo() == "y"
+}
+
 func (w *wizard) setMaxGasPrice() {
 	fmt.Printf("Current maximum gas price: %v\n", w.maxGasPrice())
 	fmt.Printf("Enter new maximum gas price in Wei (enter \"0\" for no maximum gas price)")
@@ -59,6 +59,9 @@ func (w *wizard) deposit() {
 		"depositAmount": {depositAmount.String()},
 		"reserveAmount": {reserveAmount.String()},
 	}
+	if !w.confirmTxCost("fundDepositAndReserve", form) {
+		return
+	}
 	fmt.Println(httpPostWithParams(fmt.Sprintf("http://%v:%v/fundDepositAndReserve", w.host, w.httpPort), form))
 
 	return
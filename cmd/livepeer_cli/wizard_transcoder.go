@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/url"
@@ -219,6 +220,8 @@ func (w *wizard) vote() {
 		return
 	}
 
+	w.showPolls()
+
 	fmt.Print("Enter the contract address for the poll you want to vote in -")
 	poll := w.readStringAndValidate(func(in string) (string, error) {
 		if !ethcommon.IsHexAddress(in) {
@@ -272,6 +275,31 @@ func (w *wizard) showVoteChoices() {
 	wtr.Flush()
 }
 
+// showPolls lists the active governance polls with the current vote of the node. The polls cannot be listed without
+// the address of the PollCreator, in which case the address of the poll has to be looked up elsewhere
+func (w *wizard) showPolls() {
+	result := httpGet(fmt.Sprintf("http://%v:%v/polls", w.host, w.httpPort))
+	var polls []*types.Poll
+	if err := json.Unmarshal([]byte(result), &polls); err != nil {
+		fmt.Printf("Could not list polls: %v\n", strings.TrimSpace(result))
+		return
+	}
+
+	wtr := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(wtr, "Poll\tProposal\tEnd Block\tVote")
+	for _, p := range polls {
+		if !p.Active {
+			continue
+		}
+		vote := "-"
+		if p.Vote != nil {
+			vote = p.Vote.String()
+		}
+		fmt.Fprintf(wtr, "%v\t%v\t%v\t%v\n", p.Address.Hex(), p.Proposal, p.EndBlock, vote)
+	}
+	wtr.Flush()
+}
+
 func flipPerc(perc *big.Int) *big.Int {
 	return new(big.Int).Sub(hundredPercent, perc)
 }
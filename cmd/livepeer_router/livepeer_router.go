@@ -35,6 +35,7 @@ func main() {
 	httpAddr := flag.String("httpAddr", "", "Address (IP:port) to bind to for HTTP")
 	serviceAddr := flag.String("serviceAddr", "", "Publicly accessible URI (IP:port or hostname) to receive requests at")
 	orchAddr := flag.String("orchAddr", "", "Comma delimited list of orchestrator URIs (IP:port or hostname) to use")
+	shardByManifest := flag.Bool("shardByManifest", false, "Set to true if -orchAddr are the machines of a single on-chain orchestrator. Segments are received by the router and the segments of each stream are sent to the same orchestrator. The orchestrators must use the same -ethOrchAddr and -orchShardSecret")
 
 	flag.Parse()
 
@@ -89,7 +90,12 @@ func main() {
 	}
 
 	errCh := make(chan error)
-	srv := server.NewRouter(uris)
+	var srv *server.Router
+	if *shardByManifest {
+		srv = server.NewShardRouter(uris)
+	} else {
+		srv = server.NewRouter(uris)
+	}
 	go func() {
 		errCh <- srv.Start(uri, serviceURI, *datadir)
 	}()
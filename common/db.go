@@ -9,10 +9,12 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/golang/glog"
+	_ "github.com/lib/pq"
 	"github.com/livepeer/go-livepeer/eth/blockwatch"
 	"github.com/livepeer/go-livepeer/pm"
 	_ "github.com/mattn/go-sqlite3"
@@ -21,7 +23,8 @@ import (
 
 // DB is an initialized DB driver with prepared statements
 type DB struct {
-	dbh *sql.DB
+	dbh     *sql.DB
+	dialect dbDialect
 
 	// prepared statements
 	updateOrch                       *sql.Stmt
@@ -34,6 +37,7 @@ type DB struct {
 	withdrawableUnbondingLocks       *sql.Stmt
 	insertWinningTicket              *sql.Stmt
 	selectEarliestWinningTicket      *sql.Stmt
+	selectEarliestWinningTickets     *sql.Stmt
 	winningTicketCount               *sql.Stmt
 	markWinningTicketRedeemed        *sql.Stmt
 	removeWinningTicket              *sql.Stmt
@@ -41,6 +45,22 @@ type DB struct {
 	findLatestMiniHeader             *sql.Stmt
 	findAllMiniHeadersSortedByNumber *sql.Stmt
 	deleteMiniHeader                 *sql.Stmt
+	updateStream                     *sql.Stmt
+	findStream                       *sql.Stmt
+	deleteStream                     *sql.Stmt
+	insertStreamEvent                *sql.Stmt
+	streamEvents                     *sql.Stmt
+	deleteStreamEvents               *sql.Stmt
+	insertContractEvent              *sql.Stmt
+	deleteContractEvents             *sql.Stmt
+	insertIndexedBlock               *sql.Stmt
+	latestIndexedBlock               *sql.Stmt
+	deleteIndexedBlocks              *sql.Stmt
+	pruneIndexedBlocks               *sql.Stmt
+	insertTxJournalEntry             *sql.Stmt
+	insertBatchAsset                 *sql.Stmt
+	updateBatchAsset                 *sql.Stmt
+	cancelBatchAssets                *sql.Stmt
 }
 
 // DBOrch is the type binding for a row result from the orchestrators table
@@ -61,6 +81,127 @@ type DBUnbondingLock struct {
 	WithdrawRound int64
 }
 
+// DBStream is the type binding for a row result from the streams table
+type DBStream struct {
+	ManifestID string
+	// NodeID is the ID of the node that ingests the stream
+	NodeID    string
+	LastSeqNo uint64
+	// MasterPlaylist and MediaPlaylists are the encoded HLS playlists of the stream. MediaPlaylists is keyed by rendition
+	MasterPlaylist string
+	MediaPlaylists map[string]string
+	// UpdatedAt is set when the stream is updated
+	UpdatedAt time.Time
+}
+
+// DBStreamEvent is the type binding for a row result from the streamEvents table
+type DBStreamEvent struct {
+	ManifestID string
+	// NodeID is the ID of the node that recorded the event
+	NodeID string
+	Type   string
+	// Details is the JSON encoded details of the event
+	Details   string
+	CreatedAt time.Time
+}
+
+// DBContractEvent is the type binding for a row result from the contractEvents table
+type DBContractEvent struct {
+	BlockNumber uint64
+	BlockHash   ethcommon.Hash
+	TxHash      ethcommon.Hash
+	LogIndex    uint
+	Contract    ethcommon.Address
+	Name        string
+	// Addresses are the indexed address arguments of the event
+	Addresses []ethcommon.Address
+	// Data is the JSON encoded arguments of the event
+	Data string
+}
+
+// DBContractEventFilter is an object used to attach a filter to a ContractEvents query
+type DBContractEventFilter struct {
+	Name string
+	// Address matches the events that have Address as one of their indexed address arguments
+	Address   *ethcommon.Address
+	FromBlock *big.Int
+	ToBlock   *big.Int
+}
+
+// DBTxJournalEntry is the type binding for a row result from the txJournal table
+type DBTxJournalEntry struct {
+	Hash ethcommon.Hash
+	// Replaces is the hash of the tx that the tx replaced, if it is a replacement
+	Replaces ethcommon.Hash
+	Method   string
+	Inputs   string
+	Nonce    uint64
+	Gas      uint64
+	GasPrice *big.Int
+	// Status is the status of the tx at the time of the entry, i.e. sent or mined
+	Status string
+	// BlockNumber and GasUsed are set from the receipt of a mined tx
+	BlockNumber uint64
+	GasUsed     uint64
+	Error       string
+	CreatedAt   time.Time
+}
+
+// DBTxJournalFilter is an object used to attach a filter to a TxJournal query
+type DBTxJournalFilter struct {
+	Method string
+	// Hash matches the entries of the tx with Hash and of the txs that replaced it directly
+	Hash  *ethcommon.Hash
+	Since time.Time
+	// Limit restricts the query to the latest Limit entries if it is positive
+	Limit int
+}
+
+// States of the assets in the batchAssets table
+const (
+	BatchAssetQueued      = "queued"
+	BatchAssetTranscoding = "transcoding"
+	BatchAssetUploading   = "uploading"
+	BatchAssetCompleted   = "completed"
+	BatchAssetFailed      = "failed"
+	BatchAssetCancelled   = "cancelled"
+)
+
+// BatchAssetUnfinished are the states of the assets that are still to be transcoded
+var BatchAssetUnfinished = []string{BatchAssetQueued, BatchAssetTranscoding, BatchAssetUploading}
+
+// DBBatchAsset is the type binding for a row result from the batchAssets table
+type DBBatchAsset struct {
+	BatchID string
+	AssetID string
+	// Position is the index of the asset in its batch, the assets of a batch are transcoded in this order
+	Position int
+	// NodeID is the ID of the node that runs the batch
+	NodeID string
+	URL    string
+	// Profiles is the JSON encoded renditions of the batch
+	Profiles    string
+	ObjectStore string
+	CallbackURL string
+	State       string
+	// Attempts is the number of times the asset was attempted to be transcoded
+	Attempts int
+	Error    string
+	// Result is the JSON encoded result of a completed asset
+	Result string
+	// NextAttemptAt is when a queued asset is retried after a failed attempt
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// DBBatchAssetFilter is an object used to attach a filter to a BatchAssets query
+type DBBatchAssetFilter struct {
+	BatchID string
+	NodeID  string
+	States  []string
+}
+
 // DBOrchFilter is an object used to attach a filter to a selectOrch query
 type DBOrchFilter struct {
 	MaxPrice       *big.Rat
@@ -69,8 +210,6 @@ type DBOrchFilter struct {
 	UpdatedLastDay bool
 }
 
-var LivepeerDBVersion = 1
-
 var ErrDBTooNew = errors.New("DB Too New")
 
 var schema = `
@@ -158,33 +297,42 @@ func NewDBOrch(ethereumAddr string, serviceURI string, pricePerPixel int64, acti
 	}
 }
 
+// InitDB opens the DB at dbPath, which is either the path of a SQLite DB or a Postgres connection URL
 func InitDB(dbPath string) (*DB, error) {
 	// XXX need a way to ensure (via unit tests?) that all DB{} fields are
 	// properly closed / cleaned up in the case of an error
-	d := DB{}
-	db, err := sql.Open("sqlite3", dbPath)
+	dialect, dsn := dialectFor(dbPath)
+	d := DB{dialect: dialect}
+	db, err := sql.Open(dialect.driver(), dsn)
 	if err != nil {
-		glog.Error("Unable to open DB ", dbPath, err)
+		glog.Errorf("Unable to open DB driver=%s err=%q", dialect.driver(), err)
 		return nil, err
 	}
-	// The DB connection might be used in multiple goroutines (i.e. when recovering claims during node restart)
-	// resulting in concurrent access. SQLite can only handle one writer at a time, so if concurrent writes occur
-	// we can encounter a `database is locked` error. To avoid concurrent writes, we limit SQLite to a single connection
-	db.SetMaxOpenConns(1)
-	d.dbh = db
-	schemaBuf := new(bytes.Buffer)
-	tmpl := template.Must(template.New("schema").Parse(schema))
-	tmpl.Execute(schemaBuf, LivepeerDBVersion)
-	_, err = db.Exec(schemaBuf.String())
-	if err != nil {
-		glog.Error("Error initializing schema ", err)
-		d.Close()
-		return nil, err
+	if dialect.singleConn() {
+		// The DB connection might be used in multiple goroutines (i.e. when recovering claims during node restart)
+		// resulting in concurrent access. SQLite can only handle one writer at a time, so if concurrent writes occur
+		// we can encounter a `database is locked` error. To avoid concurrent writes, we limit SQLite to a single connection
+		db.SetMaxOpenConns(1)
 	}
+	d.dbh = db
 
-	// Check for correct DB version and upgrade if needed
+	// The base schema is only applied to new DBs and DBs at the base version, later changes are applied by migrations
 	var dbVersion int
 	row := db.QueryRow("SELECT value FROM kv WHERE key = 'dbVersion'")
+	if err := row.Scan(&dbVersion); err != nil || dbVersion <= baseDBVersion {
+		schemaBuf := new(bytes.Buffer)
+		tmpl := template.Must(template.New("schema").Parse(dialect.schema()))
+		tmpl.Execute(schemaBuf, baseDBVersion)
+		_, err = db.Exec(schemaBuf.String())
+		if err != nil {
+			glog.Error("Error initializing schema ", err)
+			d.Close()
+			return nil, err
+		}
+	}
+
+	// Check for correct DB version and upgrade if needed
+	row = db.QueryRow("SELECT value FROM kv WHERE key = 'dbVersion'")
 	err = row.Scan(&dbVersion)
 	if err != nil {
 		glog.Error("Unable to fetch DB version ", err)
@@ -198,12 +346,17 @@ func InitDB(dbPath string) (*DB, error) {
 	} else if dbVersion < LivepeerDBVersion {
 		// Upgrade stepwise up to the correct version using the migration
 		// procedure for each version
+		if err := migrateDB(db, dialect, dbVersion, dbMigrations); err != nil {
+			glog.Error("Error migrating DB ", err)
+			d.Close()
+			return nil, err
+		}
 	} else if dbVersion == LivepeerDBVersion {
 		// all good; nothing to do
 	}
 
 	// selectKV prepared statement
-	stmt, err := db.Prepare("SELECT value FROM kv WHERE key=?")
+	stmt, err := d.prepare("SELECT value FROM kv WHERE key=?")
 	if err != nil {
 		glog.Error("Unable to prepare selectKV stmt ", err)
 		d.Close()
@@ -212,7 +365,10 @@ func InitDB(dbPath string) (*DB, error) {
 	d.selectKV = stmt
 
 	// updateKV prepared statement
-	stmt, err = db.Prepare("INSERT OR REPLACE INTO kv(key, value, updatedAt) VALUES(?1, ?2, datetime())")
+	stmt, err = d.prepare(`
+	INSERT INTO kv(key, value, updatedAt) VALUES(?, ?, CURRENT_TIMESTAMP)
+	ON CONFLICT(key) DO UPDATE SET value = excluded.value, updatedAt = excluded.updatedAt
+	`)
 	if err != nil {
 		glog.Error("Unable to prepare updatekv stmt ", err)
 		d.Close()
@@ -221,29 +377,29 @@ func InitDB(dbPath string) (*DB, error) {
 	d.updateKV = stmt
 
 	// updateOrch prepared statement
-	stmt, err = db.Prepare(`
+	stmt, err = d.prepare(`
 	INSERT INTO orchestrators(updatedAt, ethereumAddr, serviceURI, pricePerPixel, activationRound, deactivationRound, stake, createdAt) 
-	VALUES(datetime(), :ethereumAddr, :serviceURI, :pricePerPixel, :activationRound, :deactivationRound, :stake, datetime()) 
+	VALUES(CURRENT_TIMESTAMP, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP) 
 	ON CONFLICT(ethereumAddr) DO UPDATE SET 
 	updatedAt = excluded.updatedAt,
 	serviceURI =
-  		CASE WHEN trim(excluded.serviceURI) == ""
+  		CASE WHEN trim(excluded.serviceURI) = ''
   		THEN orchestrators.serviceURI
 		ELSE trim(excluded.serviceURI) END, 
 	pricePerPixel = 
-		CASE WHEN excluded.pricePerPixel == 0
+		CASE WHEN excluded.pricePerPixel = 0
 		THEN orchestrators.pricePerPixel
 		ELSE excluded.pricePerPixel END, 
 	activationRound = 
-		CASE WHEN excluded.activationRound == 0
+		CASE WHEN excluded.activationRound = 0
 		THEN orchestrators.activationRound
 		ELSE excluded.activationRound END, 
 	deactivationRound = 
-		CASE WHEN excluded.deactivationRound == 0
+		CASE WHEN excluded.deactivationRound = 0
 		THEN orchestrators.deactivationRound
 		ELSE excluded.deactivationRound END,
 	stake = 
-		CASE WHEN excluded.stake == 0
+		CASE WHEN excluded.stake = 0
 		THEN orchestrators.stake
 		ELSE excluded.stake END 
 	`)
@@ -255,35 +411,35 @@ func InitDB(dbPath string) (*DB, error) {
 	d.updateOrch = stmt
 
 	// Unbonding locks prepared statements
-	stmt, err = db.Prepare("INSERT INTO unbondingLocks(id, delegator, amount, withdrawRound) VALUES(?, ?, ?, ?)")
+	stmt, err = d.prepare("INSERT INTO unbondingLocks(id, delegator, amount, withdrawRound) VALUES(?, ?, ?, ?)")
 	if err != nil {
 		glog.Error("Unable to prepare insertUnbondingLock ", err)
 		d.Close()
 		return nil, err
 	}
 	d.insertUnbondingLock = stmt
-	stmt, err = db.Prepare("DELETE FROM unbondingLocks WHERE id=? AND delegator=?")
+	stmt, err = d.prepare("DELETE FROM unbondingLocks WHERE id=? AND delegator=?")
 	if err != nil {
 		glog.Error("Unable to prepare deleteUnbondingLock ", err)
 		d.Close()
 		return nil, err
 	}
 	d.deleteUnbondingLock = stmt
-	stmt, err = db.Prepare("UPDATE unbondingLocks SET usedBlock=? WHERE id=? AND delegator=?")
+	stmt, err = d.prepare("UPDATE unbondingLocks SET usedBlock=? WHERE id=? AND delegator=?")
 	if err != nil {
 		glog.Error("Unable to prepare useUnbondingLock ", err)
 		d.Close()
 		return nil, err
 	}
 	d.useUnbondingLock = stmt
-	stmt, err = db.Prepare("SELECT id, delegator, amount, withdrawRound FROM unbondingLocks WHERE usedBlock IS NULL")
+	stmt, err = d.prepare("SELECT id, delegator, amount, withdrawRound FROM unbondingLocks WHERE usedBlock IS NULL")
 	if err != nil {
 		glog.Error("Unable to prepare unbondingLocks ", err)
 		d.Close()
 		return nil, err
 	}
 	d.unbondingLocks = stmt
-	stmt, err = db.Prepare("SELECT id, delegator, amount, withdrawRound FROM unbondingLocks WHERE usedBlock IS NULL AND withdrawRound <= ?")
+	stmt, err = d.prepare("SELECT id, delegator, amount, withdrawRound FROM unbondingLocks WHERE usedBlock IS NULL AND withdrawRound <= ?")
 	if err != nil {
 		glog.Error("Unable to prepare withdrawableUnbondingLocks ", err)
 		d.Close()
@@ -292,9 +448,9 @@ func InitDB(dbPath string) (*DB, error) {
 	d.withdrawableUnbondingLocks = stmt
 
 	// Winning tickets prepared statements
-	stmt, err = db.Prepare(`
+	stmt, err = d.prepare(`
 	INSERT INTO ticketQueue(sender, recipient, faceValue, winProb, senderNonce, recipientRand, recipientRandHash, sig, creationRound, creationRoundBlockHash, paramsExpirationBlock)
-	VALUES(:sender, :recipient, :faceValue, :winProb, :senderNonce, :recipientRand, :recipientRandHash, :sig, :creationRound, :creationRoundBlockHash, :paramsExpirationBlock)
+	VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		glog.Error("Unable to prepare insertWinningTicket ", err)
@@ -304,7 +460,7 @@ func InitDB(dbPath string) (*DB, error) {
 	d.insertWinningTicket = stmt
 
 	// Select earliest ticket
-	stmt, err = db.Prepare("SELECT sender, recipient, faceValue, winProb, senderNonce, recipientRand, recipientRandHash, sig, creationRound, creationRoundBlockHash, paramsExpirationBlock FROM ticketQueue WHERE sender=? AND creationRound >= ? AND redeemedAt IS NULL AND txHash IS NULL ORDER BY createdAt ASC LIMIT 1")
+	stmt, err = d.prepare("SELECT sender, recipient, faceValue, winProb, senderNonce, recipientRand, recipientRandHash, sig, creationRound, creationRoundBlockHash, paramsExpirationBlock FROM ticketQueue WHERE sender=? AND creationRound >= ? AND redeemedAt IS NULL AND txHash IS NULL ORDER BY createdAt ASC LIMIT 1")
 	if err != nil {
 		glog.Error("Unable to prepare selectEarliestWinningTicket ", err)
 		d.Close()
@@ -312,7 +468,15 @@ func InitDB(dbPath string) (*DB, error) {
 	}
 	d.selectEarliestWinningTicket = stmt
 
-	stmt, err = db.Prepare("SELECT count(sig) FROM ticketQueue WHERE sender=? AND creationRound >= ? AND redeemedAt IS NULL AND txHash IS NULL")
+	stmt, err = d.prepare("SELECT sender, recipient, faceValue, winProb, senderNonce, recipientRand, recipientRandHash, sig, creationRound, creationRoundBlockHash, paramsExpirationBlock FROM ticketQueue WHERE sender=? AND creationRound >= ? AND redeemedAt IS NULL AND txHash IS NULL ORDER BY createdAt ASC LIMIT ?")
+	if err != nil {
+		glog.Error("Unable to prepare selectEarliestWinningTickets ", err)
+		d.Close()
+		return nil, err
+	}
+	d.selectEarliestWinningTickets = stmt
+
+	stmt, err = d.prepare("SELECT count(sig) FROM ticketQueue WHERE sender=? AND creationRound >= ? AND redeemedAt IS NULL AND txHash IS NULL")
 	if err != nil {
 		glog.Error("Unable to prepare winningTicketCount ", err)
 		d.Close()
@@ -321,7 +485,7 @@ func InitDB(dbPath string) (*DB, error) {
 	d.winningTicketCount = stmt
 
 	// Remove latest ticket
-	stmt, err = db.Prepare("DELETE FROM ticketQueue WHERE sig=?")
+	stmt, err = d.prepare("DELETE FROM ticketQueue WHERE sig=?")
 	if err != nil {
 		glog.Error("Unable to prepare removeWinningTicket ", err)
 		d.Close()
@@ -330,7 +494,7 @@ func InitDB(dbPath string) (*DB, error) {
 	d.removeWinningTicket = stmt
 
 	// Mark ticket redeemed
-	stmt, err = db.Prepare("UPDATE ticketQueue SET redeemedAt=dateTime('now'), txHash=? WHERE sig=?")
+	stmt, err = d.prepare("UPDATE ticketQueue SET redeemedAt=CURRENT_TIMESTAMP, txHash=? WHERE sig=?")
 	if err != nil {
 		glog.Error("Unable to prepare markWinningTicketRedeemed", err)
 		d.Close()
@@ -339,7 +503,7 @@ func InitDB(dbPath string) (*DB, error) {
 	d.markWinningTicketRedeemed = stmt
 
 	// Insert block header
-	stmt, err = db.Prepare("INSERT INTO blockheaders(number, parent, hash, logs) VALUES(?, ?, ?, ?)")
+	stmt, err = d.prepare("INSERT INTO blockheaders(number, parent, hash, logs) VALUES(?, ?, ?, ?)")
 	if err != nil {
 		glog.Error("Unable to prepare insertMiniHeader ", err)
 		d.Close()
@@ -348,7 +512,7 @@ func InitDB(dbPath string) (*DB, error) {
 	d.insertMiniHeader = stmt
 
 	// Find the latest block header
-	stmt, err = db.Prepare("SELECT * FROM blockheaders ORDER BY number DESC LIMIT 1")
+	stmt, err = d.prepare("SELECT * FROM blockheaders ORDER BY number DESC LIMIT 1")
 	if err != nil {
 		glog.Error("Unable to prepare findLatestMiniHeader ", err)
 		d.Close()
@@ -357,7 +521,7 @@ func InitDB(dbPath string) (*DB, error) {
 	d.findLatestMiniHeader = stmt
 
 	// Find all block headers sorted by number
-	stmt, err = db.Prepare("SELECT * FROM blockheaders ORDER BY number DESC")
+	stmt, err = d.prepare("SELECT * FROM blockheaders ORDER BY number DESC")
 	if err != nil {
 		glog.Error("Unable to prepare findAllMiniHeadersSortedByNumber ", err)
 		d.Close()
@@ -366,7 +530,7 @@ func InitDB(dbPath string) (*DB, error) {
 	d.findAllMiniHeadersSortedByNumber = stmt
 
 	// Delete block header
-	stmt, err = db.Prepare("DELETE FROM blockheaders WHERE hash=?")
+	stmt, err = d.prepare("DELETE FROM blockheaders WHERE hash=?")
 	if err != nil {
 		glog.Error("Unable to prepare deleteMiniHeader ", err)
 		d.Close()
@@ -374,10 +538,173 @@ func InitDB(dbPath string) (*DB, error) {
 	}
 	d.deleteMiniHeader = stmt
 
+	// Streams prepared statements
+	stmt, err = d.prepare(`
+	INSERT INTO streams(manifestID, nodeID, lastSeqNo, masterPlaylist, mediaPlaylists, updatedAt)
+	VALUES(?, ?, ?, ?, ?, ?)
+	ON CONFLICT(manifestID) DO UPDATE SET
+	nodeID = excluded.nodeID,
+	lastSeqNo = excluded.lastSeqNo,
+	masterPlaylist = excluded.masterPlaylist,
+	mediaPlaylists = excluded.mediaPlaylists,
+	updatedAt = excluded.updatedAt
+	`)
+	if err != nil {
+		glog.Error("Unable to prepare updateStream ", err)
+		d.Close()
+		return nil, err
+	}
+	d.updateStream = stmt
+
+	stmt, err = d.prepare("SELECT manifestID, nodeID, lastSeqNo, masterPlaylist, mediaPlaylists, updatedAt FROM streams WHERE manifestID=?")
+	if err != nil {
+		glog.Error("Unable to prepare findStream ", err)
+		d.Close()
+		return nil, err
+	}
+	d.findStream = stmt
+
+	stmt, err = d.prepare("DELETE FROM streams WHERE manifestID=? AND nodeID=?")
+	if err != nil {
+		glog.Error("Unable to prepare deleteStream ", err)
+		d.Close()
+		return nil, err
+	}
+	d.deleteStream = stmt
+
+	// Stream events prepared statements
+	stmt, err = d.prepare("INSERT INTO streamEvents(manifestID, nodeID, type, details, createdAt) VALUES(?, ?, ?, ?, ?)")
+	if err != nil {
+		glog.Error("Unable to prepare insertStreamEvent ", err)
+		d.Close()
+		return nil, err
+	}
+	d.insertStreamEvent = stmt
+
+	stmt, err = d.prepare("SELECT manifestID, nodeID, type, details, createdAt FROM streamEvents WHERE manifestID=? AND createdAt >= ? ORDER BY createdAt")
+	if err != nil {
+		glog.Error("Unable to prepare streamEvents ", err)
+		d.Close()
+		return nil, err
+	}
+	d.streamEvents = stmt
+
+	stmt, err = d.prepare("DELETE FROM streamEvents WHERE createdAt < ?")
+	if err != nil {
+		glog.Error("Unable to prepare deleteStreamEvents ", err)
+		d.Close()
+		return nil, err
+	}
+	d.deleteStreamEvents = stmt
+
+	// Contract events prepared statements
+	stmt, err = d.prepare(`
+	INSERT INTO contractEvents(blockNumber, blockHash, txHash, logIndex, contract, name, addr1, addr2, addr3, data)
+	VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		glog.Error("Unable to prepare insertContractEvent ", err)
+		d.Close()
+		return nil, err
+	}
+	d.insertContractEvent = stmt
+
+	stmt, err = d.prepare("DELETE FROM contractEvents WHERE blockNumber >= ?")
+	if err != nil {
+		glog.Error("Unable to prepare deleteContractEvents ", err)
+		d.Close()
+		return nil, err
+	}
+	d.deleteContractEvents = stmt
+
+	stmt, err = d.prepare("INSERT INTO indexedBlocks(number, hash) VALUES(?, ?) ON CONFLICT(number) DO UPDATE SET hash = excluded.hash")
+	if err != nil {
+		glog.Error("Unable to prepare insertIndexedBlock ", err)
+		d.Close()
+		return nil, err
+	}
+	d.insertIndexedBlock = stmt
+
+	stmt, err = d.prepare("SELECT number, hash FROM indexedBlocks ORDER BY number DESC LIMIT 1")
+	if err != nil {
+		glog.Error("Unable to prepare latestIndexedBlock ", err)
+		d.Close()
+		return nil, err
+	}
+	d.latestIndexedBlock = stmt
+
+	stmt, err = d.prepare("DELETE FROM indexedBlocks WHERE number >= ?")
+	if err != nil {
+		glog.Error("Unable to prepare deleteIndexedBlocks ", err)
+		d.Close()
+		return nil, err
+	}
+	d.deleteIndexedBlocks = stmt
+
+	stmt, err = d.prepare("DELETE FROM indexedBlocks WHERE number NOT IN (SELECT number FROM indexedBlocks ORDER BY number DESC LIMIT ?)")
+	if err != nil {
+		glog.Error("Unable to prepare pruneIndexedBlocks ", err)
+		d.Close()
+		return nil, err
+	}
+	d.pruneIndexedBlocks = stmt
+
+	// Transaction journal prepared statements
+	stmt, err = d.prepare(`
+	INSERT INTO txJournal(hash, replaces, method, inputs, nonce, gas, gasPrice, status, blockNumber, gasUsed, error, createdAt)
+	VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		glog.Error("Unable to prepare insertTxJournalEntry ", err)
+		d.Close()
+		return nil, err
+	}
+	d.insertTxJournalEntry = stmt
+
+	// Batch assets prepared statements
+	stmt, err = d.prepare(`
+	INSERT INTO batchAssets(batchID, assetID, position, nodeID, url, profiles, objectStore, callbackURL, state, attempts, error, result, nextAttemptAt, createdAt, updatedAt)
+	VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		glog.Error("Unable to prepare insertBatchAsset ", err)
+		d.Close()
+		return nil, err
+	}
+	d.insertBatchAsset = stmt
+
+	// Cancelled assets are not updated anymore, so a running batch can not override the cancellation
+	stmt, err = d.prepare(`
+	UPDATE batchAssets SET state=?, attempts=?, error=?, result=?, nextAttemptAt=?, updatedAt=?
+	WHERE batchID=? AND assetID=? AND state != 'cancelled'
+	`)
+	if err != nil {
+		glog.Error("Unable to prepare updateBatchAsset ", err)
+		d.Close()
+		return nil, err
+	}
+	d.updateBatchAsset = stmt
+
+	stmt, err = d.prepare(`
+	UPDATE batchAssets SET state='cancelled', updatedAt=?
+	WHERE batchID=? AND state IN ('queued', 'transcoding', 'uploading')
+	`)
+	if err != nil {
+		glog.Error("Unable to prepare cancelBatchAssets ", err)
+		d.Close()
+		return nil, err
+	}
+	d.cancelBatchAssets = stmt
+
 	glog.V(DEBUG).Info("Initialized DB node")
 	return &d, nil
 }
 
+// prepare prepares query for the backend of the DB
+func (db *DB) prepare(query string) (*sql.Stmt, error) {
+	return db.dbh.Prepare(db.dialect.rebind(query))
+}
+
 func (db *DB) Close() {
 	glog.V(DEBUG).Info("Closing DB")
 	if db.selectKV != nil {
@@ -410,6 +737,9 @@ func (db *DB) Close() {
 	if db.selectEarliestWinningTicket != nil {
 		db.selectEarliestWinningTicket.Close()
 	}
+	if db.selectEarliestWinningTickets != nil {
+		db.selectEarliestWinningTickets.Close()
+	}
 	if db.winningTicketCount != nil {
 		db.winningTicketCount.Close()
 	}
@@ -431,6 +761,54 @@ func (db *DB) Close() {
 	if db.deleteMiniHeader != nil {
 		db.deleteMiniHeader.Close()
 	}
+	if db.updateStream != nil {
+		db.updateStream.Close()
+	}
+	if db.findStream != nil {
+		db.findStream.Close()
+	}
+	if db.deleteStream != nil {
+		db.deleteStream.Close()
+	}
+	if db.insertStreamEvent != nil {
+		db.insertStreamEvent.Close()
+	}
+	if db.streamEvents != nil {
+		db.streamEvents.Close()
+	}
+	if db.deleteStreamEvents != nil {
+		db.deleteStreamEvents.Close()
+	}
+	if db.insertContractEvent != nil {
+		db.insertContractEvent.Close()
+	}
+	if db.deleteContractEvents != nil {
+		db.deleteContractEvents.Close()
+	}
+	if db.insertIndexedBlock != nil {
+		db.insertIndexedBlock.Close()
+	}
+	if db.latestIndexedBlock != nil {
+		db.latestIndexedBlock.Close()
+	}
+	if db.deleteIndexedBlocks != nil {
+		db.deleteIndexedBlocks.Close()
+	}
+	if db.pruneIndexedBlocks != nil {
+		db.pruneIndexedBlocks.Close()
+	}
+	if db.insertTxJournalEntry != nil {
+		db.insertTxJournalEntry.Close()
+	}
+	if db.insertBatchAsset != nil {
+		db.insertBatchAsset.Close()
+	}
+	if db.updateBatchAsset != nil {
+		db.updateBatchAsset.Close()
+	}
+	if db.cancelBatchAssets != nil {
+		db.cancelBatchAssets.Close()
+	}
 	if db.dbh != nil {
 		db.dbh.Close()
 	}
@@ -474,6 +852,39 @@ func (db *DB) SetChainID(id *big.Int) error {
 	return nil
 }
 
+// ContractAddresses returns the contract addresses last resolved from the Controller at controllerAddr
+func (db *DB) ContractAddresses(controllerAddr ethcommon.Address) (map[string]ethcommon.Address, error) {
+	addrsString, err := db.selectKVStore(contractAddressesKey(controllerAddr))
+	if err != nil {
+		return nil, err
+	}
+
+	if addrsString == "" {
+		return nil, nil
+	}
+
+	var addrs map[string]ethcommon.Address
+	if err := json.Unmarshal([]byte(addrsString), &addrs); err != nil {
+		return nil, fmt.Errorf("unable to parse contract addresses: %v", err)
+	}
+
+	return addrs, nil
+}
+
+// SetContractAddresses caches the contract addresses resolved from the Controller at controllerAddr
+func (db *DB) SetContractAddresses(controllerAddr ethcommon.Address, addrs map[string]ethcommon.Address) error {
+	addrsJSON, err := json.Marshal(addrs)
+	if err != nil {
+		return err
+	}
+
+	return db.updateKVStore(contractAddressesKey(controllerAddr), string(addrsJSON))
+}
+
+func contractAddressesKey(controllerAddr ethcommon.Address) string {
+	return "contracts-" + strings.ToLower(controllerAddr.Hex())
+}
+
 func (db *DB) selectKVStore(key string) (string, error) {
 	row := db.selectKV.QueryRow(key)
 	var valueString string
@@ -501,12 +912,12 @@ func (db *DB) UpdateOrch(orch *DBOrch) error {
 	}
 
 	_, err := db.updateOrch.Exec(
-		sql.Named("ethereumAddr", orch.EthereumAddr),
-		sql.Named("serviceURI", orch.ServiceURI),
-		sql.Named("pricePerPixel", orch.PricePerPixel),
-		sql.Named("activationRound", orch.ActivationRound),
-		sql.Named("deactivationRound", orch.DeactivationRound),
-		sql.Named("stake", orch.Stake),
+		orch.EthereumAddr,
+		orch.ServiceURI,
+		orch.PricePerPixel,
+		orch.ActivationRound,
+		orch.DeactivationRound,
+		orch.Stake,
 	)
 
 	if err != nil {
@@ -521,7 +932,7 @@ func (db *DB) SelectOrchs(filter *DBOrchFilter) ([]*DBOrch, error) {
 		return nil, nil
 	}
 
-	rows, err := db.dbh.Query(buildSelectOrchsQuery(filter))
+	rows, err := db.dbh.Query(buildSelectOrchsQuery(db.dialect, filter))
 	if err != nil {
 		glog.Error("db: Unable to get orchestrators updated in the last 24 hours: ", err)
 		return nil, err
@@ -552,7 +963,7 @@ func (db *DB) OrchCount(filter *DBOrchFilter) (int, error) {
 		return 0, nil
 	}
 
-	qry, err := buildOrchCountQuery(filter)
+	qry, err := buildOrchCountQuery(db.dialect, filter)
 	if err != nil {
 		return 0, err
 	}
@@ -602,6 +1013,17 @@ func (db *DB) DeleteUnbondingLock(id *big.Int, delegator ethcommon.Address) erro
 	return nil
 }
 
+// DeleteUnbondingLocks deletes all unbonding locks of a delegator from the DB
+func (db *DB) DeleteUnbondingLocks(delegator ethcommon.Address) error {
+	glog.V(DEBUG).Infof("db: Deleting unbonding locks for delegator %v", delegator.Hex())
+	_, err := db.dbh.Exec(db.dialect.rebind("DELETE FROM unbondingLocks WHERE delegator=?"), delegator.Hex())
+	if err != nil {
+		glog.Errorf("db: Error deleting unbonding locks for delegator %v: %v", delegator.Hex(), err)
+		return err
+	}
+	return nil
+}
+
 // UseUnbondingLock sets an unbonding lock in the DB as used by setting the lock's used block.
 // If usedBlock is nil this method will set the lock's used block to NULL
 func (db *DB) UseUnbondingLock(id *big.Int, delegator ethcommon.Address, usedBlock *big.Int) error {
@@ -699,17 +1121,17 @@ func (db *DB) StoreWinningTicket(ticket *pm.SignedTicket) error {
 	}
 
 	_, err := db.insertWinningTicket.Exec(
-		sql.Named("sender", ticket.Sender.Hex()),
-		sql.Named("recipient", ticket.Recipient.Hex()),
-		sql.Named("faceValue", ticket.FaceValue.Bytes()),
-		sql.Named("winProb", ticket.WinProb.Bytes()),
-		sql.Named("senderNonce", ticket.SenderNonce),
-		sql.Named("recipientRand", ticket.RecipientRand.Bytes()),
-		sql.Named("recipientRandHash", ticket.RecipientRandHash.Hex()),
-		sql.Named("sig", ticket.Sig),
-		sql.Named("creationRound", ticket.CreationRound),
-		sql.Named("creationRoundBlockHash", ticket.CreationRoundBlockHash.Hex()),
-		sql.Named("paramsExpirationBlock", ticket.ParamsExpirationBlock.Int64()),
+		ticket.Sender.Hex(),
+		ticket.Recipient.Hex(),
+		ticket.FaceValue.Bytes(),
+		ticket.WinProb.Bytes(),
+		int64(ticket.SenderNonce),
+		ticket.RecipientRand.Bytes(),
+		ticket.RecipientRandHash.Hex(),
+		ticket.Sig,
+		ticket.CreationRound,
+		ticket.CreationRoundBlockHash.Hex(),
+		ticket.ParamsExpirationBlock.Int64(),
 	)
 
 	if err != nil {
@@ -764,6 +1186,39 @@ func (db *DB) RemoveWinningTicket(ticket *pm.SignedTicket) error {
 func (db *DB) SelectEarliestWinningTicket(sender ethcommon.Address, minCreationRound int64) (*pm.SignedTicket, error) {
 
 	row := db.selectEarliestWinningTicket.QueryRow(sender.Hex(), minCreationRound)
+	ticket, err := scanWinningTicket(sender, row)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("could not retrieve earliest ticket err=%q", err)
+		}
+		// If there is no result return no error, just nil value
+		return nil, nil
+	}
+	return ticket, nil
+}
+
+// SelectEarliestWinningTickets selects up to 'limit' of the earliest stored winning tickets for a 'sender' that are not
+// expired and not yet redeemed, in the order they were stored
+func (db *DB) SelectEarliestWinningTickets(sender ethcommon.Address, minCreationRound int64, limit int) ([]*pm.SignedTicket, error) {
+	rows, err := db.selectEarliestWinningTickets.Query(sender.Hex(), minCreationRound, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve earliest tickets err=%q", err)
+	}
+	defer rows.Close()
+
+	var tickets []*pm.SignedTicket
+	for rows.Next() {
+		ticket, err := scanWinningTicket(sender, rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve earliest tickets err=%q", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, rows.Err()
+}
+
+// scanWinningTicket scans a row of the ticket queue of 'sender' into a signed ticket
+func scanWinningTicket(sender ethcommon.Address, row interface{ Scan(...interface{}) error }) (*pm.SignedTicket, error) {
 	var (
 		senderString           string
 		recipient              string
@@ -778,11 +1233,7 @@ func (db *DB) SelectEarliestWinningTicket(sender ethcommon.Address, minCreationR
 		paramsExpirationBlock  int64
 	)
 	if err := row.Scan(&senderString, &recipient, &faceValue, &winProb, &senderNonce, &recipientRand, &recipientRandHash, &sig, &creationRound, &creationRoundBlockHash, &paramsExpirationBlock); err != nil {
-		if err.Error() != "sql: no rows in result set" {
-			return nil, fmt.Errorf("could not retrieve earliest ticket err=%q", err)
-		}
-		// If there is no result return no error, just nil value
-		return nil, nil
+		return nil, err
 	}
 
 	return &pm.SignedTicket{
@@ -817,31 +1268,31 @@ func (db *DB) WinningTicketCount(sender ethcommon.Address, minCreationRound int6
 	return int(count64), nil
 }
 
-func buildSelectOrchsQuery(filter *DBOrchFilter) (string, error) {
+func buildSelectOrchsQuery(dialect dbDialect, filter *DBOrchFilter) (string, error) {
 	query := "SELECT ethereumAddr, serviceURI, pricePerPixel, activationRound, deactivationRound, stake FROM orchestrators "
-	fil, err := buildFilterOrchsQuery(filter)
+	fil, err := buildFilterOrchsQuery(dialect, filter)
 	if err != nil {
 		return "", err
 	}
 	return query + fil, nil
 }
 
-func buildOrchCountQuery(filter *DBOrchFilter) (string, error) {
+func buildOrchCountQuery(dialect dbDialect, filter *DBOrchFilter) (string, error) {
 	query := "SELECT count(ethereumAddr) FROM orchestrators "
-	fil, err := buildFilterOrchsQuery(filter)
+	fil, err := buildFilterOrchsQuery(dialect, filter)
 	if err != nil {
 		return "", err
 	}
 	return query + fil, nil
 }
 
-func buildFilterOrchsQuery(filter *DBOrchFilter) (string, error) {
+func buildFilterOrchsQuery(dialect dbDialect, filter *DBOrchFilter) (string, error) {
 	qry := ""
 	var filters []string
 
 	if filter != nil {
 		if filter.UpdatedLastDay {
-			filters = append(filters, "updatedAt >= datetime('now','-1 day')")
+			filters = append(filters, dialect.sinceOneDay("updatedAt"))
 		}
 
 		if filter.MaxPrice != nil {
@@ -962,6 +1413,422 @@ func (db *DB) DeleteMiniHeader(hash ethcommon.Hash) error {
 	return nil
 }
 
+// UpdateStream inserts or updates the state of a stream
+func (db *DB) UpdateStream(stream *DBStream) error {
+	if stream == nil {
+		return errors.New("must provide a stream")
+	}
+	mediaPlaylists, err := json.Marshal(stream.MediaPlaylists)
+	if err != nil {
+		return err
+	}
+	_, err = db.updateStream.Exec(stream.ManifestID, stream.NodeID, int64(stream.LastSeqNo), stream.MasterPlaylist, string(mediaPlaylists), time.Now().Unix())
+	return err
+}
+
+// FindStream returns the state of the stream with manifestID or nil if the stream is unknown
+func (db *DB) FindStream(manifestID string) (*DBStream, error) {
+	var (
+		stream         DBStream
+		lastSeqNo      int64
+		mediaPlaylists string
+		updatedAt      int64
+	)
+	row := db.findStream.QueryRow(manifestID)
+	if err := row.Scan(&stream.ManifestID, &stream.NodeID, &lastSeqNo, &stream.MasterPlaylist, &mediaPlaylists, &updatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not retrieve stream manifestID=%s err=%q", manifestID, err)
+	}
+	if err := json.Unmarshal([]byte(mediaPlaylists), &stream.MediaPlaylists); err != nil {
+		return nil, err
+	}
+	stream.LastSeqNo = uint64(lastSeqNo)
+	stream.UpdatedAt = time.Unix(updatedAt, 0)
+	return &stream, nil
+}
+
+// DeleteStream deletes the state of the stream with manifestID if the stream is ingested by nodeID. The state is kept
+// if another node took over the stream
+func (db *DB) DeleteStream(manifestID, nodeID string) error {
+	_, err := db.deleteStream.Exec(manifestID, nodeID)
+	return err
+}
+
+// InsertStreamEvent appends an event to the timeline of a stream
+func (db *DB) InsertStreamEvent(event *DBStreamEvent) error {
+	if event == nil {
+		return errors.New("must provide a stream event")
+	}
+	_, err := db.insertStreamEvent.Exec(event.ManifestID, event.NodeID, event.Type, event.Details, event.CreatedAt.UnixNano())
+	return err
+}
+
+// StreamEvents returns the events of the stream with manifestID that were created at or after since in the order they
+// were created
+func (db *DB) StreamEvents(manifestID string, since time.Time) ([]*DBStreamEvent, error) {
+	rows, err := db.streamEvents.Query(manifestID, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve stream events manifestID=%s err=%q", manifestID, err)
+	}
+	defer rows.Close()
+
+	events := []*DBStreamEvent{}
+	for rows.Next() {
+		var (
+			event     DBStreamEvent
+			createdAt int64
+		)
+		if err := rows.Scan(&event.ManifestID, &event.NodeID, &event.Type, &event.Details, &createdAt); err != nil {
+			return nil, fmt.Errorf("could not retrieve stream events manifestID=%s err=%q", manifestID, err)
+		}
+		event.CreatedAt = time.Unix(0, createdAt)
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+// DeleteStreamEvents deletes the events of all streams that were created before the given time
+func (db *DB) DeleteStreamEvents(before time.Time) error {
+	_, err := db.deleteStreamEvents.Exec(before.UnixNano())
+	return err
+}
+
+// InsertContractEvent inserts an indexed contract event into the DB
+func (db *DB) InsertContractEvent(event *DBContractEvent) error {
+	if event == nil {
+		return errors.New("must provide a contract event")
+	}
+	if len(event.Addresses) > 3 {
+		return errors.New("contract event has more than 3 indexed addresses")
+	}
+
+	var addrs [3]string
+	for i, addr := range event.Addresses {
+		addrs[i] = addr.Hex()
+	}
+	_, err := db.insertContractEvent.Exec(int64(event.BlockNumber), event.BlockHash.Hex(), event.TxHash.Hex(), int64(event.LogIndex),
+		event.Contract.Hex(), event.Name, addrs[0], addrs[1], addrs[2], event.Data)
+	return err
+}
+
+// ContractEvents returns the indexed contract events that match filter in the order they were emitted
+func (db *DB) ContractEvents(filter *DBContractEventFilter) ([]*DBContractEvent, error) {
+	qry, args := buildContractEventsQuery(filter)
+	rows, err := db.dbh.Query(db.dialect.rebind(qry), args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve contract events err=%q", err)
+	}
+	defer rows.Close()
+
+	events := []*DBContractEvent{}
+	for rows.Next() {
+		var (
+			event                       DBContractEvent
+			blockNumber, logIndex       int64
+			blockHash, txHash, contract string
+			addr1, addr2, addr3         string
+		)
+		if err := rows.Scan(&blockNumber, &blockHash, &txHash, &logIndex, &contract, &event.Name, &addr1, &addr2, &addr3, &event.Data); err != nil {
+			return nil, fmt.Errorf("could not retrieve contract events err=%q", err)
+		}
+		event.BlockNumber = uint64(blockNumber)
+		event.BlockHash = ethcommon.HexToHash(blockHash)
+		event.TxHash = ethcommon.HexToHash(txHash)
+		event.LogIndex = uint(logIndex)
+		event.Contract = ethcommon.HexToAddress(contract)
+		for _, a := range []string{addr1, addr2, addr3} {
+			if a != "" {
+				event.Addresses = append(event.Addresses, ethcommon.HexToAddress(a))
+			}
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+func buildContractEventsQuery(filter *DBContractEventFilter) (string, []interface{}) {
+	qry := "SELECT blockNumber, blockHash, txHash, logIndex, contract, name, addr1, addr2, addr3, data FROM contractEvents "
+	var (
+		filters []string
+		args    []interface{}
+	)
+
+	if filter != nil {
+		if filter.Name != "" {
+			filters = append(filters, "name = ?")
+			args = append(args, filter.Name)
+		}
+
+		if filter.Address != nil {
+			addr := filter.Address.Hex()
+			filters = append(filters, "(addr1 = ? OR addr2 = ? OR addr3 = ?)")
+			args = append(args, addr, addr, addr)
+		}
+
+		if filter.FromBlock != nil {
+			filters = append(filters, "blockNumber >= ?")
+			args = append(args, filter.FromBlock.Int64())
+		}
+
+		if filter.ToBlock != nil {
+			filters = append(filters, "blockNumber <= ?")
+			args = append(args, filter.ToBlock.Int64())
+		}
+	}
+
+	if len(filters) > 0 {
+		qry += "WHERE " + strings.Join(filters, " AND ") + " "
+	}
+
+	return qry + "ORDER BY blockNumber, logIndex", args
+}
+
+// DeleteContractEvents deletes the indexed contract events emitted at or after fromBlock
+func (db *DB) DeleteContractEvents(fromBlock *big.Int) error {
+	_, err := db.deleteContractEvents.Exec(fromBlock.Int64())
+	return err
+}
+
+// InsertIndexedBlock records that the contract events up to and including header have been indexed
+func (db *DB) InsertIndexedBlock(header *blockwatch.MiniHeader) error {
+	if header == nil || header.Number == nil {
+		return errors.New("must provide a block header with a number")
+	}
+	_, err := db.insertIndexedBlock.Exec(header.Number.Int64(), header.Hash.Hex())
+	return err
+}
+
+// LatestIndexedBlock returns the latest block up to which the contract events have been indexed, nil if no block
+// has been indexed
+func (db *DB) LatestIndexedBlock() (*blockwatch.MiniHeader, error) {
+	var (
+		number int64
+		hash   string
+	)
+	if err := db.latestIndexedBlock.QueryRow().Scan(&number, &hash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not retrieve latest indexed block: %v", err)
+	}
+	return &blockwatch.MiniHeader{
+		Number: big.NewInt(number),
+		Hash:   ethcommon.HexToHash(hash),
+	}, nil
+}
+
+// DeleteIndexedBlocks deletes the indexed blocks at or after fromBlock i.e. when the blocks were reorged
+func (db *DB) DeleteIndexedBlocks(fromBlock *big.Int) error {
+	_, err := db.deleteIndexedBlocks.Exec(fromBlock.Int64())
+	return err
+}
+
+// PruneIndexedBlocks deletes all but the latest keep indexed blocks. The contract events of the deleted blocks are kept
+func (db *DB) PruneIndexedBlocks(keep int) error {
+	_, err := db.pruneIndexedBlocks.Exec(keep)
+	return err
+}
+
+// InsertTxJournalEntry appends an entry to the journal of the submitted txs
+func (db *DB) InsertTxJournalEntry(entry *DBTxJournalEntry) error {
+	if entry == nil {
+		return errors.New("must provide a tx journal entry")
+	}
+	var replaces, gasPrice string
+	if entry.Replaces != (ethcommon.Hash{}) {
+		replaces = entry.Replaces.Hex()
+	}
+	if entry.GasPrice != nil {
+		gasPrice = entry.GasPrice.String()
+	}
+	_, err := db.insertTxJournalEntry.Exec(entry.Hash.Hex(), replaces, entry.Method, entry.Inputs, int64(entry.Nonce), int64(entry.Gas),
+		gasPrice, entry.Status, int64(entry.BlockNumber), int64(entry.GasUsed), entry.Error, entry.CreatedAt.UnixNano())
+	return err
+}
+
+// TxJournal returns the entries of the tx journal that match filter in the order they were created
+func (db *DB) TxJournal(filter *DBTxJournalFilter) ([]*DBTxJournalEntry, error) {
+	qry, args := buildTxJournalQuery(filter)
+	rows, err := db.dbh.Query(db.dialect.rebind(qry), args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve tx journal err=%q", err)
+	}
+	defer rows.Close()
+
+	entries := []*DBTxJournalEntry{}
+	for rows.Next() {
+		var (
+			entry                                     DBTxJournalEntry
+			hash, replaces, gasPrice                  string
+			nonce, gas, blockNumber, gasUsed, created int64
+		)
+		if err := rows.Scan(&hash, &replaces, &entry.Method, &entry.Inputs, &nonce, &gas, &gasPrice, &entry.Status,
+			&blockNumber, &gasUsed, &entry.Error, &created); err != nil {
+			return nil, fmt.Errorf("could not retrieve tx journal err=%q", err)
+		}
+		entry.Hash = ethcommon.HexToHash(hash)
+		if replaces != "" {
+			entry.Replaces = ethcommon.HexToHash(replaces)
+		}
+		if gasPrice != "" {
+			entry.GasPrice, _ = new(big.Int).SetString(gasPrice, 10)
+		}
+		entry.Nonce = uint64(nonce)
+		entry.Gas = uint64(gas)
+		entry.BlockNumber = uint64(blockNumber)
+		entry.GasUsed = uint64(gasUsed)
+		entry.CreatedAt = time.Unix(0, created)
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// The latest entries are selected first if the query is limited
+	if filter != nil && filter.Limit > 0 {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+	return entries, nil
+}
+
+func buildTxJournalQuery(filter *DBTxJournalFilter) (string, []interface{}) {
+	qry := "SELECT hash, replaces, method, inputs, nonce, gas, gasPrice, status, blockNumber, gasUsed, error, createdAt FROM txJournal "
+	var (
+		filters []string
+		args    []interface{}
+	)
+
+	order := "ORDER BY createdAt"
+	if filter != nil {
+		if filter.Method != "" {
+			filters = append(filters, "method = ?")
+			args = append(args, filter.Method)
+		}
+
+		if filter.Hash != nil {
+			hash := filter.Hash.Hex()
+			filters = append(filters, "(hash = ? OR replaces = ?)")
+			args = append(args, hash, hash)
+		}
+
+		if !filter.Since.IsZero() {
+			filters = append(filters, "createdAt >= ?")
+			args = append(args, filter.Since.UnixNano())
+		}
+
+		if filter.Limit > 0 {
+			order = fmt.Sprintf("ORDER BY createdAt DESC LIMIT %d", filter.Limit)
+		}
+	}
+
+	if len(filters) > 0 {
+		qry += "WHERE " + strings.Join(filters, " AND ") + " "
+	}
+
+	return qry + order, args
+}
+
+// InsertBatchAsset adds an asset of a batch to the queue of batch assets
+func (db *DB) InsertBatchAsset(asset *DBBatchAsset) error {
+	if asset == nil {
+		return errors.New("must provide a batch asset")
+	}
+	_, err := db.insertBatchAsset.Exec(asset.BatchID, asset.AssetID, int64(asset.Position), asset.NodeID, asset.URL, asset.Profiles,
+		asset.ObjectStore, asset.CallbackURL, asset.State, int64(asset.Attempts), asset.Error, asset.Result,
+		asset.NextAttemptAt.UnixNano(), asset.CreatedAt.UnixNano(), asset.UpdatedAt.UnixNano())
+	return err
+}
+
+// UpdateBatchAsset updates the state, attempts, error, result and next attempt of a batch asset. It returns false if
+// the asset does not exist or was cancelled
+func (db *DB) UpdateBatchAsset(asset *DBBatchAsset) (bool, error) {
+	if asset == nil {
+		return false, errors.New("must provide a batch asset")
+	}
+	res, err := db.updateBatchAsset.Exec(asset.State, int64(asset.Attempts), asset.Error, asset.Result,
+		asset.NextAttemptAt.UnixNano(), asset.UpdatedAt.UnixNano(), asset.BatchID, asset.AssetID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// CancelBatchAssets cancels the unfinished assets of the batch with batchID and returns how many were cancelled
+func (db *DB) CancelBatchAssets(batchID string, at time.Time) (int64, error) {
+	res, err := db.cancelBatchAssets.Exec(at.UnixNano(), batchID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// BatchAssets returns the batch assets that match filter, ordered by batch in the order the batches were created
+func (db *DB) BatchAssets(filter *DBBatchAssetFilter) ([]*DBBatchAsset, error) {
+	qry, args := buildBatchAssetsQuery(filter)
+	rows, err := db.dbh.Query(db.dialect.rebind(qry), args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve batch assets err=%q", err)
+	}
+	defer rows.Close()
+
+	assets := []*DBBatchAsset{}
+	for rows.Next() {
+		var (
+			asset                                             DBBatchAsset
+			position, attempts, nextAttempt, created, updated int64
+		)
+		if err := rows.Scan(&asset.BatchID, &asset.AssetID, &position, &asset.NodeID, &asset.URL, &asset.Profiles, &asset.ObjectStore,
+			&asset.CallbackURL, &asset.State, &attempts, &asset.Error, &asset.Result, &nextAttempt, &created, &updated); err != nil {
+			return nil, fmt.Errorf("could not retrieve batch assets err=%q", err)
+		}
+		asset.Position = int(position)
+		asset.Attempts = int(attempts)
+		asset.NextAttemptAt = time.Unix(0, nextAttempt)
+		asset.CreatedAt = time.Unix(0, created)
+		asset.UpdatedAt = time.Unix(0, updated)
+		assets = append(assets, &asset)
+	}
+	return assets, rows.Err()
+}
+
+func buildBatchAssetsQuery(filter *DBBatchAssetFilter) (string, []interface{}) {
+	qry := "SELECT batchID, assetID, position, nodeID, url, profiles, objectStore, callbackURL, state, attempts, error, result, nextAttemptAt, createdAt, updatedAt FROM batchAssets "
+	var (
+		filters []string
+		args    []interface{}
+	)
+
+	if filter != nil {
+		if filter.BatchID != "" {
+			filters = append(filters, "batchID = ?")
+			args = append(args, filter.BatchID)
+		}
+
+		if filter.NodeID != "" {
+			filters = append(filters, "nodeID = ?")
+			args = append(args, filter.NodeID)
+		}
+
+		if len(filter.States) > 0 {
+			filters = append(filters, "state IN (?"+strings.Repeat(", ?", len(filter.States)-1)+")")
+			for _, state := range filter.States {
+				args = append(args, state)
+			}
+		}
+	}
+
+	if len(filters) > 0 {
+		qry += "WHERE " + strings.Join(filters, " AND ") + " "
+	}
+
+	return qry + "ORDER BY createdAt, batchID, position", args
+}
+
 func encodeLogsJSON(logs []types.Log) ([]byte, error) {
 	logsEnc, err := json.Marshal(logs)
 	if err != nil {
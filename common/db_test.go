@@ -103,6 +103,39 @@ func TestSetChainID(t *testing.T) {
 	assert.Equal(chainID, expectedChainIDInt)
 }
 
+func TestContractAddresses(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	dbh, dbraw, err := TempDB(t)
+	require.Nil(err)
+
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	controller := ethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	// Nothing cached yet
+	addrs, err := dbh.ContractAddresses(controller)
+	assert.Nil(err)
+	assert.Nil(addrs)
+
+	expected := map[string]ethcommon.Address{
+		"BondingManager": ethcommon.HexToAddress("0x2222222222222222222222222222222222222222"),
+		"TicketBroker":   ethcommon.HexToAddress("0x3333333333333333333333333333333333333333"),
+	}
+	require.Nil(dbh.SetContractAddresses(controller, expected))
+
+	addrs, err = dbh.ContractAddresses(controller)
+	assert.Nil(err)
+	assert.Equal(expected, addrs)
+
+	// Addresses are cached per controller
+	addrs, err = dbh.ContractAddresses(ethcommon.HexToAddress("0x4444444444444444444444444444444444444444"))
+	assert.Nil(err)
+	assert.Nil(addrs)
+}
+
 func TestDBLastSeenBlock(t *testing.T) {
 	dbh, dbraw, err := TempDB(t)
 	if err != nil {
@@ -790,6 +823,30 @@ func TestDBUnbondingLocks(t *testing.T) {
 		t.Error("Unexpected number of unbonding locks after reverting used lock; expected 3, got", len(unbondingLocks))
 		return
 	}
+
+	// Check deleting all locks of a delegator
+	other := ethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	err = dbh.InsertUnbondingLock(big.NewInt(0), other, big.NewInt(10), big.NewInt(100))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = dbh.DeleteUnbondingLocks(delegator)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	unbondingLocks, err = dbh.UnbondingLocks(nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(unbondingLocks) != 1 || unbondingLocks[0].Delegator != other {
+		t.Error("Unexpected unbonding locks after deleting delegator locks; expected 1 lock of the other delegator, got", len(unbondingLocks))
+		return
+	}
 }
 
 func TestWinningTicketCount(t *testing.T) {
@@ -1069,6 +1126,53 @@ func TestSelectEarliestWinningTicket(t *testing.T) {
 
 }
 
+func TestSelectEarliestWinningTickets(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	dbh, dbraw, err := TempDB(t)
+	defer dbh.Close()
+	defer dbraw.Close()
+	require.Nil(err)
+
+	sender := ethcommon.HexToAddress("charizard")
+	var tickets []*pm.SignedTicket
+	for i := 0; i < 3; i++ {
+		_, ticket, sig, recipientRand := defaultWinningTicket(t)
+		ticket.Sender = sender
+		ticket.SenderNonce = uint32(i)
+		tickets = append(tickets, &pm.SignedTicket{Ticket: ticket, Sig: sig, RecipientRand: recipientRand})
+	}
+	creationRound := tickets[0].CreationRound
+
+	// no tickets found
+	earliest, err := dbh.SelectEarliestWinningTickets(sender, creationRound, 10)
+	assert.Nil(err)
+	assert.Empty(earliest)
+
+	for _, ticket := range tickets {
+		require.Nil(dbh.StoreWinningTicket(ticket))
+	}
+	earliest, err = dbh.SelectEarliestWinningTickets(sender, creationRound, 10)
+	assert.Nil(err)
+	assert.ElementsMatch(tickets, earliest)
+
+	// Test limit
+	earliest, err = dbh.SelectEarliestWinningTickets(sender, creationRound, 2)
+	assert.Nil(err)
+	assert.Len(earliest, 2)
+
+	// Test excluding expired tickets
+	earliest, err = dbh.SelectEarliestWinningTickets(sender, creationRound+1, 10)
+	assert.Nil(err)
+	assert.Empty(earliest)
+
+	// Test excluding submitted tickets
+	require.Nil(dbh.MarkWinningTicketRedeemed(tickets[0], pm.RandHash()))
+	earliest, err = dbh.SelectEarliestWinningTickets(sender, creationRound, 10)
+	assert.Nil(err)
+	assert.ElementsMatch(tickets[1:], earliest)
+}
+
 func TestMarkWinningTicketRedeemed_GivenNilTicket_ReturnsError(t *testing.T) {
 	dbh, dbraw, err := TempDB(t)
 	defer dbh.Close()
@@ -1307,6 +1411,202 @@ func TestDeleteMiniHeader(t *testing.T) {
 	assert.Equal(headers[0].Hash, h1.Hash)
 }
 
+func TestStreams(t *testing.T) {
+	dbh, dbraw, err := TempDB(t)
+	defer dbh.Close()
+	defer dbraw.Close()
+	assert := assert.New(t)
+	require := require.New(t)
+	require.Nil(err)
+
+	// Unknown stream
+	stream, err := dbh.FindStream("foo")
+	assert.Nil(err)
+	assert.Nil(stream)
+
+	assert.EqualError(dbh.UpdateStream(nil), "must provide a stream")
+
+	err = dbh.UpdateStream(&DBStream{
+		ManifestID:     "foo",
+		NodeID:         "node1",
+		LastSeqNo:      1,
+		MasterPlaylist: "master",
+		MediaPlaylists: map[string]string{"source": "media"},
+	})
+	require.Nil(err)
+	stream, err = dbh.FindStream("foo")
+	require.Nil(err)
+	assert.Equal("foo", stream.ManifestID)
+	assert.Equal("node1", stream.NodeID)
+	assert.Equal(uint64(1), stream.LastSeqNo)
+	assert.Equal("master", stream.MasterPlaylist)
+	assert.Equal(map[string]string{"source": "media"}, stream.MediaPlaylists)
+	assert.WithinDuration(time.Now(), stream.UpdatedAt, time.Minute)
+
+	// Another node takes over the stream
+	err = dbh.UpdateStream(&DBStream{
+		ManifestID:     "foo",
+		NodeID:         "node2",
+		LastSeqNo:      2,
+		MasterPlaylist: "master2",
+		MediaPlaylists: map[string]string{"source": "media2", "P144p30fps16x9": "media3"},
+	})
+	require.Nil(err)
+	stream, err = dbh.FindStream("foo")
+	require.Nil(err)
+	assert.Equal("node2", stream.NodeID)
+	assert.Equal(uint64(2), stream.LastSeqNo)
+	assert.Equal("master2", stream.MasterPlaylist)
+	assert.Len(stream.MediaPlaylists, 2)
+
+	// The stream is only deleted by the node that ingests it
+	require.Nil(dbh.DeleteStream("foo", "node1"))
+	stream, err = dbh.FindStream("foo")
+	require.Nil(err)
+	assert.NotNil(stream)
+
+	require.Nil(dbh.DeleteStream("foo", "node2"))
+	stream, err = dbh.FindStream("foo")
+	assert.Nil(err)
+	assert.Nil(stream)
+}
+
+func TestStreamEvents(t *testing.T) {
+	dbh, dbraw, err := TempDB(t)
+	defer dbh.Close()
+	defer dbraw.Close()
+	assert := assert.New(t)
+	require := require.New(t)
+	require.Nil(err)
+
+	// Unknown stream
+	events, err := dbh.StreamEvents("foo", time.Time{})
+	assert.Nil(err)
+	assert.Empty(events)
+
+	assert.EqualError(dbh.InsertStreamEvent(nil), "must provide a stream event")
+
+	start := time.Now()
+	for i, typ := range []string{"ingest_started", "orchestrator_selected", "stream_ended"} {
+		require.Nil(dbh.InsertStreamEvent(&DBStreamEvent{
+			ManifestID: "foo",
+			NodeID:     "node1",
+			Type:       typ,
+			Details:    fmt.Sprintf(`{"i":%d}`, i),
+			CreatedAt:  start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	require.Nil(dbh.InsertStreamEvent(&DBStreamEvent{ManifestID: "bar", Type: "ingest_started", CreatedAt: start}))
+
+	// Events are returned in the order they were created
+	events, err = dbh.StreamEvents("foo", time.Time{})
+	require.Nil(err)
+	require.Len(events, 3)
+	assert.Equal("foo", events[0].ManifestID)
+	assert.Equal("node1", events[0].NodeID)
+	assert.Equal("ingest_started", events[0].Type)
+	assert.Equal(`{"i":0}`, events[0].Details)
+	assert.True(start.Equal(events[0].CreatedAt))
+	assert.Equal("orchestrator_selected", events[1].Type)
+	assert.Equal("stream_ended", events[2].Type)
+
+	events, err = dbh.StreamEvents("foo", start.Add(time.Minute))
+	require.Nil(err)
+	require.Len(events, 2)
+	assert.Equal("orchestrator_selected", events[0].Type)
+
+	// Events of all streams created before the retention are deleted
+	require.Nil(dbh.DeleteStreamEvents(start.Add(time.Minute)))
+	events, err = dbh.StreamEvents("foo", time.Time{})
+	require.Nil(err)
+	assert.Len(events, 2)
+	events, err = dbh.StreamEvents("bar", time.Time{})
+	require.Nil(err)
+	assert.Empty(events)
+}
+
+func TestContractEvents(t *testing.T) {
+	dbh, dbraw, err := TempDB(t)
+	defer dbh.Close()
+	defer dbraw.Close()
+	assert := assert.New(t)
+	require := require.New(t)
+	require.Nil(err)
+
+	assert.EqualError(dbh.InsertContractEvent(nil), "must provide a contract event")
+	assert.EqualError(dbh.InsertContractEvent(&DBContractEvent{Addresses: make([]ethcommon.Address, 4)}), "contract event has more than 3 indexed addresses")
+
+	contract, from, to := pm.RandAddress(), pm.RandAddress(), pm.RandAddress()
+	for i := uint64(1); i <= 3; i++ {
+		require.Nil(dbh.InsertContractEvent(&DBContractEvent{
+			BlockNumber: i,
+			BlockHash:   pm.RandHash(),
+			TxHash:      pm.RandHash(),
+			LogIndex:    uint(i),
+			Contract:    contract,
+			Name:        "Transfer",
+			Addresses:   []ethcommon.Address{from, to},
+			Data:        fmt.Sprintf(`{"value":%d}`, i),
+		}))
+	}
+	require.Nil(dbh.InsertContractEvent(&DBContractEvent{BlockNumber: 2, Contract: contract, Name: "Reward", Addresses: []ethcommon.Address{to}}))
+
+	// Events are returned in the order they were emitted
+	events, err := dbh.ContractEvents(nil)
+	require.Nil(err)
+	require.Len(events, 4)
+	assert.Equal(uint64(1), events[0].BlockNumber)
+	assert.Equal(contract, events[0].Contract)
+	assert.Equal([]ethcommon.Address{from, to}, events[0].Addresses)
+	assert.Equal(`{"value":1}`, events[0].Data)
+	assert.Equal("Reward", events[1].Name)
+	assert.Equal([]ethcommon.Address{to}, events[1].Addresses)
+	assert.Equal("Transfer", events[2].Name)
+
+	events, err = dbh.ContractEvents(&DBContractEventFilter{Name: "Transfer", FromBlock: big.NewInt(2), ToBlock: big.NewInt(2)})
+	require.Nil(err)
+	require.Len(events, 1)
+	assert.Equal(uint(2), events[0].LogIndex)
+	events, err = dbh.ContractEvents(&DBContractEventFilter{Address: &to})
+	require.Nil(err)
+	assert.Len(events, 4)
+	events, err = dbh.ContractEvents(&DBContractEventFilter{Address: &from, Name: "Reward"})
+	require.Nil(err)
+	assert.Empty(events)
+
+	require.Nil(dbh.DeleteContractEvents(big.NewInt(2)))
+	events, err = dbh.ContractEvents(nil)
+	require.Nil(err)
+	assert.Len(events, 1)
+
+	// Indexed blocks
+	latest, err := dbh.LatestIndexedBlock()
+	require.Nil(err)
+	assert.Nil(latest)
+	assert.EqualError(dbh.InsertIndexedBlock(nil), "must provide a block header with a number")
+
+	hashes := make(map[int64]ethcommon.Hash)
+	for i := int64(1); i <= 5; i++ {
+		hashes[i] = pm.RandHash()
+		require.Nil(dbh.InsertIndexedBlock(&blockwatch.MiniHeader{Number: big.NewInt(i), Hash: hashes[i]}))
+	}
+	latest, err = dbh.LatestIndexedBlock()
+	require.Nil(err)
+	assert.Equal(big.NewInt(5), latest.Number)
+	assert.Equal(hashes[5], latest.Hash)
+
+	require.Nil(dbh.DeleteIndexedBlocks(big.NewInt(4)))
+	latest, err = dbh.LatestIndexedBlock()
+	require.Nil(err)
+	assert.Equal(big.NewInt(3), latest.Number)
+
+	require.Nil(dbh.PruneIndexedBlocks(2))
+	assert.Equal(2, getRowCountOrFatal("SELECT count(*) FROM indexedBlocks", dbraw, t))
+	latest, err = dbh.LatestIndexedBlock()
+	require.Nil(err)
+	assert.Equal(big.NewInt(3), latest.Number)
+}
+
 func defaultWinningTicket(t *testing.T) (sessionID string, ticket *pm.Ticket, sig []byte, recipientRand *big.Int) {
 	sessionID = "foo bar"
 	ticket = &pm.Ticket{
@@ -1346,3 +1646,144 @@ func defaultMiniHeader() *blockwatch.MiniHeader {
 	block.Logs = []types.Log{log}
 	return block
 }
+
+func TestTxJournal(t *testing.T) {
+	dbh, dbraw, err := TempDB(t)
+	defer dbh.Close()
+	defer dbraw.Close()
+	assert := assert.New(t)
+	require := require.New(t)
+	require.Nil(err)
+
+	assert.EqualError(dbh.InsertTxJournalEntry(nil), "must provide a tx journal entry")
+
+	start := time.Now()
+	hash, replacement := pm.RandHash(), pm.RandHash()
+	require.Nil(dbh.InsertTxJournalEntry(&DBTxJournalEntry{
+		Hash:      hash,
+		Method:    "redeemWinningTicket",
+		Inputs:    "_ticket: {}",
+		Nonce:     7,
+		Gas:       500000,
+		GasPrice:  big.NewInt(1000000000),
+		Status:    "sent",
+		CreatedAt: start,
+	}))
+	require.Nil(dbh.InsertTxJournalEntry(&DBTxJournalEntry{Hash: replacement, Replaces: hash, Method: "redeemWinningTicket", Status: "sent", CreatedAt: start.Add(time.Second)}))
+	require.Nil(dbh.InsertTxJournalEntry(&DBTxJournalEntry{Hash: replacement, Method: "redeemWinningTicket", Status: "mined", BlockNumber: 10, GasUsed: 21000, CreatedAt: start.Add(2 * time.Second)}))
+	require.Nil(dbh.InsertTxJournalEntry(&DBTxJournalEntry{Hash: pm.RandHash(), Method: "reward", Status: "failed", Error: "transaction not mined", CreatedAt: start.Add(3 * time.Second)}))
+
+	// Entries are returned in the order they were created
+	entries, err := dbh.TxJournal(nil)
+	require.Nil(err)
+	require.Len(entries, 4)
+	assert.Equal(hash, entries[0].Hash)
+	assert.Equal(ethcommon.Hash{}, entries[0].Replaces)
+	assert.Equal("_ticket: {}", entries[0].Inputs)
+	assert.Equal(uint64(7), entries[0].Nonce)
+	assert.Equal(uint64(500000), entries[0].Gas)
+	assert.Equal(big.NewInt(1000000000), entries[0].GasPrice)
+	assert.Equal(start.UnixNano(), entries[0].CreatedAt.UnixNano())
+	assert.Equal(hash, entries[1].Replaces)
+	assert.Nil(entries[1].GasPrice)
+	assert.Equal(uint64(10), entries[2].BlockNumber)
+	assert.Equal(uint64(21000), entries[2].GasUsed)
+	assert.Equal("transaction not mined", entries[3].Error)
+
+	entries, err = dbh.TxJournal(&DBTxJournalFilter{Hash: &hash})
+	require.Nil(err)
+	assert.Len(entries, 2)
+	entries, err = dbh.TxJournal(&DBTxJournalFilter{Method: "reward"})
+	require.Nil(err)
+	assert.Len(entries, 1)
+	entries, err = dbh.TxJournal(&DBTxJournalFilter{Since: start.Add(time.Second), Method: "redeemWinningTicket"})
+	require.Nil(err)
+	assert.Len(entries, 2)
+
+	// A limit selects the latest entries
+	entries, err = dbh.TxJournal(&DBTxJournalFilter{Limit: 2})
+	require.Nil(err)
+	require.Len(entries, 2)
+	assert.Equal("mined", entries[0].Status)
+	assert.Equal("failed", entries[1].Status)
+}
+
+func TestBatchAssets(t *testing.T) {
+	dbh, dbraw, err := TempDB(t)
+	defer dbh.Close()
+	defer dbraw.Close()
+	assert := assert.New(t)
+	require := require.New(t)
+	require.Nil(err)
+
+	assert.EqualError(dbh.InsertBatchAsset(nil), "must provide a batch asset")
+
+	start := time.Now()
+	newAsset := func(batchID, assetID string, position int, created time.Time) *DBBatchAsset {
+		return &DBBatchAsset{BatchID: batchID, AssetID: assetID, Position: position, NodeID: "node", URL: "https://source/" + assetID,
+			Profiles: "[]", ObjectStore: "memory://batch", CallbackURL: "https://callback", State: BatchAssetQueued, CreatedAt: created, UpdatedAt: created}
+	}
+	require.Nil(dbh.InsertBatchAsset(newAsset("b2", "z", 0, start.Add(time.Second))))
+	require.Nil(dbh.InsertBatchAsset(newAsset("b1", "y", 1, start)))
+	require.Nil(dbh.InsertBatchAsset(newAsset("b1", "x", 0, start)))
+	assert.NotNil(dbh.InsertBatchAsset(newAsset("b1", "x", 2, start)))
+
+	// Assets are returned by batch in the order they were created, then by position
+	assets, err := dbh.BatchAssets(nil)
+	require.Nil(err)
+	require.Len(assets, 3)
+	assert.Equal("x", assets[0].AssetID)
+	assert.Equal("y", assets[1].AssetID)
+	assert.Equal("z", assets[2].AssetID)
+	assert.Equal("https://source/x", assets[0].URL)
+	assert.Equal("memory://batch", assets[0].ObjectStore)
+	assert.Equal(start.UnixNano(), assets[0].CreatedAt.UnixNano())
+
+	assets[0].State = BatchAssetCompleted
+	assets[0].Attempts = 1
+	assets[0].Result = `{"playlist":"index.m3u8"}`
+	assets[0].UpdatedAt = start.Add(time.Minute)
+	ok, err := dbh.UpdateBatchAsset(assets[0])
+	require.Nil(err)
+	assert.True(ok)
+	assets[1].State = BatchAssetQueued
+	assets[1].Attempts = 1
+	assets[1].Error = "no orchestrators"
+	assets[1].NextAttemptAt = start.Add(time.Minute)
+	ok, err = dbh.UpdateBatchAsset(assets[1])
+	require.Nil(err)
+	assert.True(ok)
+
+	assets, err = dbh.BatchAssets(&DBBatchAssetFilter{BatchID: "b1"})
+	require.Nil(err)
+	require.Len(assets, 2)
+	assert.Equal(BatchAssetCompleted, assets[0].State)
+	assert.Equal(1, assets[0].Attempts)
+	assert.Equal(`{"playlist":"index.m3u8"}`, assets[0].Result)
+	assert.Equal(start.Add(time.Minute).UnixNano(), assets[0].UpdatedAt.UnixNano())
+	assert.Equal("no orchestrators", assets[1].Error)
+	assert.Equal(start.Add(time.Minute).UnixNano(), assets[1].NextAttemptAt.UnixNano())
+
+	assets, err = dbh.BatchAssets(&DBBatchAssetFilter{NodeID: "node", States: BatchAssetUnfinished})
+	require.Nil(err)
+	require.Len(assets, 2)
+	assert.Equal("y", assets[0].AssetID)
+	assert.Equal("z", assets[1].AssetID)
+
+	// Only the unfinished assets are cancelled and cancelled assets are not updated anymore
+	n, err := dbh.CancelBatchAssets("b1", start.Add(2*time.Minute))
+	require.Nil(err)
+	assert.Equal(int64(1), n)
+	assets[0].State = BatchAssetCompleted
+	ok, err = dbh.UpdateBatchAsset(assets[0])
+	require.Nil(err)
+	assert.False(ok)
+	assets, err = dbh.BatchAssets(&DBBatchAssetFilter{BatchID: "b1", States: []string{BatchAssetCancelled}})
+	require.Nil(err)
+	require.Len(assets, 1)
+	assert.Equal("y", assets[0].AssetID)
+
+	n, err = dbh.CancelBatchAssets("unknown", time.Now())
+	require.Nil(err)
+	assert.Zero(n)
+}
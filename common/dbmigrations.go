@@ -0,0 +1,212 @@
+package common
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+)
+
+// dbMigration upgrades the DB schema from Version-1 to Version
+type dbMigration struct {
+	Version     int
+	Description string
+	Up          func(tx *sql.Tx) error
+}
+
+// baseDBVersion is the version of the schema that new DBs are created with before the migrations are applied
+const baseDBVersion = 1
+
+// dbMigrations are the forward migrations of the DB schema in order. To change the schema, append a migration with
+// the next version. Released migrations must never be edited or removed because nodes may already have applied them
+var dbMigrations = []dbMigration{
+	{
+		Version:     2,
+		Description: "create streams",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS streams (
+				manifestID TEXT PRIMARY KEY,
+				nodeID TEXT,
+				lastSeqNo BIGINT,
+				masterPlaylist TEXT,
+				mediaPlaylists TEXT,
+				updatedAt BIGINT
+			)`)
+			return err
+		},
+	},
+	{
+		Version:     3,
+		Description: "create streamEvents",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS streamEvents (
+				manifestID TEXT,
+				nodeID TEXT,
+				type TEXT,
+				details TEXT,
+				createdAt BIGINT
+			);
+			CREATE INDEX IF NOT EXISTS idx_streamevents_manifestid ON streamEvents(manifestID, createdAt);
+			CREATE INDEX IF NOT EXISTS idx_streamevents_createdat ON streamEvents(createdAt)`)
+			return err
+		},
+	},
+	{
+		Version:     4,
+		Description: "create contractEvents and indexedBlocks",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS contractEvents (
+				blockNumber BIGINT,
+				blockHash TEXT,
+				txHash TEXT,
+				logIndex BIGINT,
+				contract TEXT,
+				name TEXT,
+				addr1 TEXT,
+				addr2 TEXT,
+				addr3 TEXT,
+				data TEXT
+			);
+			CREATE INDEX IF NOT EXISTS idx_contractevents_blocknumber ON contractEvents(blockNumber);
+			CREATE INDEX IF NOT EXISTS idx_contractevents_name ON contractEvents(name, blockNumber);
+			CREATE TABLE IF NOT EXISTS indexedBlocks (
+				number BIGINT PRIMARY KEY,
+				hash TEXT
+			)`)
+			return err
+		},
+	},
+	{
+		Version:     5,
+		Description: "create txJournal",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS txJournal (
+				hash TEXT,
+				replaces TEXT,
+				method TEXT,
+				inputs TEXT,
+				nonce BIGINT,
+				gas BIGINT,
+				gasPrice TEXT,
+				status TEXT,
+				blockNumber BIGINT,
+				gasUsed BIGINT,
+				error TEXT,
+				createdAt BIGINT
+			);
+			CREATE INDEX IF NOT EXISTS idx_txjournal_hash ON txJournal(hash);
+			CREATE INDEX IF NOT EXISTS idx_txjournal_createdat ON txJournal(createdAt)`)
+			return err
+		},
+	},
+	{
+		Version:     6,
+		Description: "create batchAssets",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS batchAssets (
+				batchID TEXT,
+				assetID TEXT,
+				position BIGINT,
+				nodeID TEXT,
+				url TEXT,
+				profiles TEXT,
+				objectStore TEXT,
+				callbackURL TEXT,
+				state TEXT,
+				attempts BIGINT,
+				error TEXT,
+				result TEXT,
+				nextAttemptAt BIGINT,
+				createdAt BIGINT,
+				updatedAt BIGINT,
+				PRIMARY KEY (batchID, assetID)
+			);
+			CREATE INDEX IF NOT EXISTS idx_batchassets_state ON batchAssets(state, createdAt)`)
+			return err
+		},
+	},
+}
+
+// LivepeerDBVersion is the version of the DB schema used by this node
+var LivepeerDBVersion = latestDBVersion(dbMigrations)
+
+func latestDBVersion(migrations []dbMigration) int {
+	if len(migrations) == 0 {
+		return baseDBVersion
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// migrateDB applies the migrations newer than dbVersion in order. Each migration runs in a transaction together with
+// the update of the DB version, so a failed upgrade leaves the DB at the last version that was applied successfully
+func migrateDB(db *sql.DB, dialect dbDialect, dbVersion int, migrations []dbMigration) error {
+	for i, m := range migrations {
+		if m.Version != baseDBVersion+i+1 {
+			return fmt.Errorf("DB migration %q has version %d, expected %d", m.Description, m.Version, baseDBVersion+i+1)
+		}
+		if m.Version <= dbVersion {
+			continue
+		}
+
+		glog.Infof("Migrating DB from version %d to %d: %s", m.Version-1, m.Version, m.Description)
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		// Nodes sharing a DB might migrate it concurrently, only the first one applies the migration
+		var current int
+		if err := tx.QueryRow("SELECT value FROM kv WHERE key = 'dbVersion'" + dialect.forUpdate()).Scan(&current); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("DB migration to version %d failed: %v", m.Version, err)
+		}
+		if current >= m.Version {
+			tx.Rollback()
+			continue
+		}
+		if err := m.Up(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("DB migration to version %d failed: %v", m.Version, err)
+		}
+		if _, err := tx.Exec(dialect.rebind("UPDATE kv SET value=?, updatedAt=CURRENT_TIMESTAMP WHERE key='dbVersion'"), m.Version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("DB migration to version %d failed: %v", m.Version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("DB migration to version %d failed: %v", m.Version, err)
+		}
+	}
+	return nil
+}
+
+// CheckDBVersion returns the version of the DB at dbPath without modifying it. It returns ErrDBTooNew if the DB was
+// migrated by a newer node and cannot be used by this node, i.e. before downgrading the node
+func CheckDBVersion(dbPath string) (int, error) {
+	dialect, dsn := dialectFor(dbPath)
+	if _, ok := dialect.(sqliteDialect); ok {
+		// Open the DB read-only so that a missing DB is not created
+		if _, err := os.Stat(dbPath); err != nil {
+			return 0, err
+		}
+		dsn = "file:" + dbPath + "?mode=ro"
+	}
+	db, err := sql.Open(dialect.driver(), dsn)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	var dbVersion int
+	if err := db.QueryRow("SELECT value FROM kv WHERE key = 'dbVersion'").Scan(&dbVersion); err != nil {
+		return 0, err
+	}
+	if dbVersion > LivepeerDBVersion {
+		return dbVersion, ErrDBTooNew
+	}
+	return dbVersion, nil
+}
@@ -0,0 +1,181 @@
+package common
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setDBMigrations(migrations []dbMigration) func() {
+	prevMigrations, prevVersion := dbMigrations, LivepeerDBVersion
+	dbMigrations, LivepeerDBVersion = migrations, latestDBVersion(migrations)
+	return func() {
+		dbMigrations, LivepeerDBVersion = prevMigrations, prevVersion
+	}
+}
+
+// withDBMigrations returns the migrations of the node followed by migrations
+func withDBMigrations(migrations ...dbMigration) []dbMigration {
+	return append(append([]dbMigration{}, dbMigrations...), migrations...)
+}
+
+func execMigration(stmt string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(stmt)
+		return err
+	}
+}
+
+func dbVersionOf(t *testing.T, db *sql.DB) int {
+	var dbVersion int
+	require.Nil(t, db.QueryRow("SELECT value FROM kv WHERE key = 'dbVersion'").Scan(&dbVersion))
+	return dbVersion
+}
+
+func tableExists(db *sql.DB, table string) bool {
+	var name string
+	return db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", table).Scan(&name) == nil
+}
+
+func TestMigrateDB(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "dbmigrations")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+	dbFile := filepath.Join(dir, "lpdb.sqlite3")
+
+	// A new DB is migrated to the latest version
+	dbh, err := InitDB(dbFile)
+	require.Nil(err)
+	dbh.Close()
+
+	raw, err := sql.Open("sqlite3", dbFile)
+	require.Nil(err)
+	defer raw.Close()
+	latest := LivepeerDBVersion
+	assert.Equal(latest, dbVersionOf(t, raw))
+	assert.True(tableExists(raw, "streams"))
+
+	// Migrations are applied in order on startup
+	defer setDBMigrations(withDBMigrations(
+		dbMigration{Version: latest + 1, Description: "create foo", Up: execMigration("CREATE TABLE foo (id INTEGER)")},
+		dbMigration{Version: latest + 2, Description: "add foo.bar", Up: execMigration("ALTER TABLE foo ADD COLUMN bar STRING")},
+	))()
+	dbh, err = InitDB(dbFile)
+	require.Nil(err)
+	dbh.Close()
+	assert.Equal(latest+2, dbVersionOf(t, raw))
+	_, err = raw.Exec("INSERT INTO foo(id, bar) VALUES(1, 'baz')")
+	assert.Nil(err)
+
+	// Applied migrations are not applied again
+	dbMigrations = append(dbMigrations, dbMigration{Version: latest + 3, Description: "drop foo", Up: execMigration("DROP TABLE foo")})
+	LivepeerDBVersion = latest + 3
+	dbh, err = InitDB(dbFile)
+	require.Nil(err)
+	dbh.Close()
+	assert.Equal(latest+3, dbVersionOf(t, raw))
+	assert.False(tableExists(raw, "foo"))
+
+	// A failed migration is rolled back and leaves the DB at the last applied version
+	dbMigrations = append(dbMigrations,
+		dbMigration{Version: latest + 4, Description: "create qux", Up: execMigration("CREATE TABLE qux (id INTEGER)")},
+		dbMigration{Version: latest + 5, Description: "fail", Up: func(tx *sql.Tx) error {
+			if _, err := tx.Exec("CREATE TABLE quux (id INTEGER)"); err != nil {
+				return err
+			}
+			return errors.New("migration error")
+		}},
+	)
+	LivepeerDBVersion = latest + 5
+	_, err = InitDB(dbFile)
+	assert.EqualError(err, fmt.Sprintf("DB migration to version %d failed: migration error", latest+5))
+	assert.Equal(latest+4, dbVersionOf(t, raw))
+	assert.True(tableExists(raw, "qux"))
+	assert.False(tableExists(raw, "quux"))
+}
+
+func TestMigrateDB_InvalidVersions(t *testing.T) {
+	assert := assert.New(t)
+
+	dbh, raw, err := TempDB(t)
+	require.Nil(t, err)
+	defer dbh.Close()
+	defer raw.Close()
+
+	latest := LivepeerDBVersion
+	migrations := withDBMigrations(
+		dbMigration{Version: latest + 1, Description: "create foo", Up: execMigration("CREATE TABLE foo (id INTEGER)")},
+		dbMigration{Version: latest + 3, Description: "create bar", Up: execMigration("CREATE TABLE bar (id INTEGER)")},
+	)
+	err = migrateDB(raw, sqliteDialect{}, latest, migrations)
+	assert.EqualError(err, fmt.Sprintf(`DB migration "create bar" has version %d, expected %d`, latest+3, latest+2))
+	assert.Equal(latest+1, dbVersionOf(t, raw))
+	assert.True(tableExists(raw, "foo"))
+	assert.False(tableExists(raw, "bar"))
+}
+
+func TestCheckDBVersion(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "dbmigrations")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+	dbFile := filepath.Join(dir, "lpdb.sqlite3")
+
+	// Missing DB
+	_, err = CheckDBVersion(dbFile)
+	assert.True(os.IsNotExist(err))
+	_, err = os.Stat(dbFile)
+	assert.True(os.IsNotExist(err))
+
+	dbh, err := InitDB(dbFile)
+	require.Nil(err)
+	dbh.Close()
+
+	dbVersion, err := CheckDBVersion(dbFile)
+	assert.Nil(err)
+	assert.Equal(LivepeerDBVersion, dbVersion)
+
+	// DB migrated by a newer node
+	raw, err := sql.Open("sqlite3", dbFile)
+	require.Nil(err)
+	defer raw.Close()
+	_, err = raw.Exec("UPDATE kv SET value=? WHERE key='dbVersion'", LivepeerDBVersion+1)
+	require.Nil(err)
+
+	dbVersion, err = CheckDBVersion(dbFile)
+	assert.Equal(ErrDBTooNew, err)
+	assert.Equal(LivepeerDBVersion+1, dbVersion)
+	assert.Equal(LivepeerDBVersion+1, dbVersionOf(t, raw))
+}
+
+func TestMigrateDB_AlreadyMigrated(t *testing.T) {
+	assert := assert.New(t)
+
+	dbh, raw, err := TempDB(t)
+	require.Nil(t, err)
+	defer dbh.Close()
+	defer raw.Close()
+
+	latest := LivepeerDBVersion
+	migrations := withDBMigrations(
+		dbMigration{Version: latest + 1, Description: "create foo", Up: execMigration("CREATE TABLE foo (id INTEGER)")},
+	)
+	assert.Nil(migrateDB(raw, sqliteDialect{}, latest, migrations))
+	assert.Equal(latest+1, dbVersionOf(t, raw))
+
+	// Another node sharing the DB read the version before it was migrated
+	assert.Nil(migrateDB(raw, sqliteDialect{}, latest, migrations))
+	assert.Equal(latest+1, dbVersionOf(t, raw))
+}
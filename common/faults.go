@@ -0,0 +1,90 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+// Fault is a failure that can be injected into the node to validate its retry and failover behavior. Faults are only
+// injected in builds with the faults tag
+type Fault string
+
+const (
+	// FaultDropReceipt drops the receipts of ETH transactions so that they time out
+	FaultDropReceipt Fault = "dropReceipt"
+	// FaultRPCDelay delays the segment RPC of the broadcaster with orchestrators
+	FaultRPCDelay Fault = "rpcDelay"
+	// FaultGPUSession fails transcoding sessions on GPUs
+	FaultGPUSession Fault = "gpuSession"
+	// FaultStorage fails writes to the object store with a 500
+	FaultStorage Fault = "storage"
+)
+
+var faultMessages = map[Fault]string{
+	FaultDropReceipt: "transaction receipt dropped",
+	FaultRPCDelay:    "RPC delayed",
+	FaultGPUSession:  "GPU session failed",
+	FaultStorage:     "500 Internal Server Error",
+}
+
+// FaultConfig configures when a fault is injected. Faults are injected deterministically on every nth occurrence of
+// the faulty operation after the fault is set
+type FaultConfig struct {
+	Fault Fault `json:"fault"`
+	// Inject the fault on every nth occurrence of the operation. 0 and 1 inject the fault on every occurrence
+	Every int `json:"every,omitempty"`
+	// Stop injecting the fault after Count injections. 0 injects the fault indefinitely
+	Count int `json:"count,omitempty"`
+	// Delay in milliseconds for FaultRPCDelay
+	DelayMs int `json:"delayMs,omitempty"`
+}
+
+// FaultState is a fault that is set with the number of occurrences of the faulty operation and the number of
+// injections since it was set
+type FaultState struct {
+	FaultConfig
+	Occurrences int `json:"occurrences"`
+	Injected    int `json:"injected"`
+}
+
+// FaultError is the error returned by operations that failed because of an injected fault
+type FaultError struct {
+	Fault Fault
+}
+
+func (e *FaultError) Error() string {
+	return fmt.Sprintf("injected fault %s: %s", e.Fault, faultMessages[e.Fault])
+}
+
+// Validate checks that the fault is known and its config is valid
+func (c FaultConfig) Validate() error {
+	if _, ok := faultMessages[c.Fault]; !ok {
+		return fmt.Errorf("unknown fault %q", c.Fault)
+	}
+	if c.Every < 0 || c.Count < 0 || c.DelayMs < 0 {
+		return fmt.Errorf("invalid config for fault %s", c.Fault)
+	}
+	if c.Fault == FaultRPCDelay && c.DelayMs == 0 {
+		return fmt.Errorf("fault %s requires a delay", c.Fault)
+	}
+	return nil
+}
+
+// InjectFault returns a *FaultError if fault f is set and due to be injected on this occurrence of the operation and
+// nil otherwise
+func InjectFault(f Fault) error {
+	if injectFault(f) == nil {
+		return nil
+	}
+	return &FaultError{Fault: f}
+}
+
+// InjectedDelay returns the delay to inject if fault f is set and due to be injected on this occurrence of the
+// operation and 0 otherwise
+func InjectedDelay(f Fault) time.Duration {
+	c := injectFault(f)
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.DelayMs) * time.Millisecond
+}
@@ -0,0 +1,28 @@
+//go:build !faults
+// +build !faults
+
+package common
+
+import "errors"
+
+// FaultsEnabled is true in builds with the faults tag
+const FaultsEnabled = false
+
+var errFaultsUnavailable = errors.New("fault injection is not available in this build, rebuild with -tags faults")
+
+// SetFault returns an error because faults are only injected in builds with the faults tag
+func SetFault(c FaultConfig) error {
+	return errFaultsUnavailable
+}
+
+// ClearFault does nothing because faults are only injected in builds with the faults tag
+func ClearFault(f Fault) {}
+
+// Faults returns no faults because faults are only injected in builds with the faults tag
+func Faults() []FaultState {
+	return []FaultState{}
+}
+
+func injectFault(f Fault) *FaultConfig {
+	return nil
+}
@@ -0,0 +1,20 @@
+//go:build !faults
+// +build !faults
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaults_Unavailable(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(FaultsEnabled)
+	assert.EqualError(SetFault(FaultConfig{Fault: FaultStorage}), "fault injection is not available in this build, rebuild with -tags faults")
+	assert.Nil(InjectFault(FaultStorage))
+	assert.Zero(InjectedDelay(FaultRPCDelay))
+	assert.Empty(Faults())
+}
@@ -0,0 +1,79 @@
+//go:build faults
+// +build faults
+
+package common
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+// FaultsEnabled is true in builds with the faults tag
+const FaultsEnabled = true
+
+var (
+	faultsMu sync.Mutex
+	faults   = make(map[Fault]*FaultState)
+)
+
+// SetFault sets the fault described by c, replacing the previous config of the same fault and resetting its counters
+func SetFault(c FaultConfig) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.Every == 0 {
+		c.Every = 1
+	}
+
+	faultsMu.Lock()
+	defer faultsMu.Unlock()
+
+	faults[c.Fault] = &FaultState{FaultConfig: c}
+	glog.Warningf("Injecting fault=%s every=%d count=%d delayMs=%d", c.Fault, c.Every, c.Count, c.DelayMs)
+	return nil
+}
+
+// ClearFault stops injecting fault f. All faults are cleared if f is empty
+func ClearFault(f Fault) {
+	faultsMu.Lock()
+	defer faultsMu.Unlock()
+
+	if f == "" {
+		faults = make(map[Fault]*FaultState)
+		return
+	}
+	delete(faults, f)
+}
+
+// Faults returns the faults that are set sorted by name
+func Faults() []FaultState {
+	faultsMu.Lock()
+	defer faultsMu.Unlock()
+
+	states := []FaultState{}
+	for _, s := range faults {
+		states = append(states, *s)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Fault < states[j].Fault })
+	return states
+}
+
+func injectFault(f Fault) *FaultConfig {
+	faultsMu.Lock()
+	defer faultsMu.Unlock()
+
+	s, ok := faults[f]
+	if !ok {
+		return nil
+	}
+	s.Occurrences++
+	if s.Occurrences%s.Every != 0 || (s.Count > 0 && s.Injected >= s.Count) {
+		return nil
+	}
+	s.Injected++
+	glog.Warningf("Injected fault=%s occurrence=%d", f, s.Occurrences)
+	c := s.FaultConfig
+	return &c
+}
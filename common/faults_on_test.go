@@ -0,0 +1,59 @@
+//go:build faults
+// +build faults
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaults_Inject(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	defer ClearFault("")
+
+	assert.True(FaultsEnabled)
+	assert.Nil(InjectFault(FaultStorage))
+
+	// Inject on every 2nd occurrence at most twice
+	require.Nil(SetFault(FaultConfig{Fault: FaultStorage, Every: 2, Count: 2}))
+	var injected []int
+	for i := 1; i <= 8; i++ {
+		if err := InjectFault(FaultStorage); err != nil {
+			assert.Equal(&FaultError{Fault: FaultStorage}, err)
+			injected = append(injected, i)
+		}
+	}
+	assert.Equal([]int{2, 4}, injected)
+	// Other faults are not injected
+	assert.Nil(InjectFault(FaultGPUSession))
+
+	// Every occurrence by default
+	require.Nil(SetFault(FaultConfig{Fault: FaultRPCDelay, DelayMs: 50}))
+	assert.Equal(50*time.Millisecond, InjectedDelay(FaultRPCDelay))
+	assert.Equal(50*time.Millisecond, InjectedDelay(FaultRPCDelay))
+
+	assert.Equal([]FaultState{
+		{FaultConfig: FaultConfig{Fault: FaultRPCDelay, Every: 1, DelayMs: 50}, Occurrences: 2, Injected: 2},
+		{FaultConfig: FaultConfig{Fault: FaultStorage, Every: 2, Count: 2}, Occurrences: 8, Injected: 2},
+	}, Faults())
+
+	// Setting a fault again resets its counters
+	require.Nil(SetFault(FaultConfig{Fault: FaultStorage}))
+	assert.NotNil(InjectFault(FaultStorage))
+	assert.Equal(1, Faults()[1].Injected)
+
+	ClearFault(FaultStorage)
+	assert.Nil(InjectFault(FaultStorage))
+	assert.Len(Faults(), 1)
+
+	ClearFault("")
+	assert.Zero(InjectedDelay(FaultRPCDelay))
+	assert.Empty(Faults())
+
+	assert.EqualError(SetFault(FaultConfig{Fault: "foo"}), `unknown fault "foo"`)
+}
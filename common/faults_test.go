@@ -0,0 +1,25 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultConfig_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(FaultConfig{Fault: FaultStorage}.Validate())
+	assert.Nil(FaultConfig{Fault: FaultGPUSession, Every: 3, Count: 2}.Validate())
+	assert.Nil(FaultConfig{Fault: FaultRPCDelay, DelayMs: 100}.Validate())
+	assert.EqualError(FaultConfig{Fault: "foo"}.Validate(), `unknown fault "foo"`)
+	assert.EqualError(FaultConfig{Fault: FaultDropReceipt, Every: -1}.Validate(), "invalid config for fault dropReceipt")
+	assert.EqualError(FaultConfig{Fault: FaultRPCDelay}.Validate(), "fault rpcDelay requires a delay")
+}
+
+func TestFaultError(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.EqualError(&FaultError{Fault: FaultStorage}, "injected fault storage: 500 Internal Server Error")
+	assert.EqualError(&FaultError{Fault: FaultGPUSession}, "injected fault gpuSession: GPU session failed")
+}
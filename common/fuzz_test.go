@@ -0,0 +1,47 @@
+//go:build go1.18
+// +build go1.18
+
+package common
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func FuzzBytesToVideoProfile(f *testing.F) {
+	b, _ := hex.DecodeString("93c717e7c0a6517a")
+	f.Add(b)
+	f.Add([]byte("abcdefghijk"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		profiles, err := BytesToVideoProfile(data)
+		if err != nil {
+			if err != ErrProfile {
+				t.Fatalf("unexpected error type: %v", err)
+			}
+			return
+		}
+		// Every byte of valid input must be consumed by a profile
+		if len(profiles)*VideoProfileIDBytes != len(data) {
+			t.Fatalf("input was partially accepted len=%v profiles=%v", len(data), len(profiles))
+		}
+	})
+}
+
+func FuzzTxDataToVideoProfile(f *testing.F) {
+	f.Add("93c717e7c0a6517a")
+	f.Add("abcdefghijk")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		profiles, err := TxDataToVideoProfile(data)
+		if err != nil {
+			if err != ErrProfile {
+				t.Fatalf("unexpected error type: %v", err)
+			}
+			return
+		}
+		if len(profiles)*VideoProfileIDSize != len(data) {
+			t.Fatalf("input was partially accepted len=%v profiles=%v", len(data), len(profiles))
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// LogBuffer keeps the most recent logs written to it, up to size bytes of complete lines
+type LogBuffer struct {
+	mu   sync.Mutex
+	buf  []byte
+	size int
+}
+
+// NewLogBuffer creates a buffer that keeps up to size bytes of logs
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{size: size}
+}
+
+// Write appends p to the buffer and discards the oldest lines if the buffer is full
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.size; over > 0 {
+		// Cut at the end of a line so that the buffer does not start with a partial line
+		cut := over
+		if i := bytes.IndexByte(b.buf[over:], '\n'); i >= 0 {
+			cut = over + i + 1
+		}
+		if cut > len(b.buf) {
+			cut = len(b.buf)
+		}
+		b.buf = append([]byte(nil), b.buf[cut:]...)
+	}
+	return len(p), nil
+}
+
+// Bytes returns a copy of the logs in the buffer
+func (b *LogBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte(nil), b.buf...)
+}
+
+// CaptureStderr tees everything written to os.Stderr, which includes the glog output, to w
+func CaptureStderr(w io.Writer) error {
+	r, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	stderr := os.Stderr
+	os.Stderr = pw
+	go io.Copy(io.MultiWriter(stderr, w), r)
+	return nil
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogBuffer(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewLogBuffer(16)
+	assert.Empty(b.Bytes())
+
+	n, err := b.Write([]byte("line1\nline2\n"))
+	assert.Nil(err)
+	assert.Equal(12, n)
+	assert.Equal("line1\nline2\n", string(b.Bytes()))
+
+	// The oldest complete lines are discarded
+	b.Write([]byte("line3\n"))
+	assert.Equal("line2\nline3\n", string(b.Bytes()))
+
+	// A line that does not fit is truncated
+	b.Write([]byte("a very long line without newline"))
+	assert.Equal(" without newline", string(b.Bytes()))
+
+	// The returned bytes are a copy
+	b = NewLogBuffer(16)
+	b.Write([]byte("foo\n"))
+	logs := b.Bytes()
+	logs[0] = 'x'
+	assert.Equal("foo\n", string(b.Bytes()))
+}
@@ -0,0 +1,59 @@
+package common
+
+import (
+	"errors"
+	"io/ioutil"
+	"os/exec"
+	"regexp"
+)
+
+// NvidiaVersions are the version of the installed NVIDIA driver and the highest CUDA version supported by the driver
+type NvidiaVersions struct {
+	Driver string
+	CUDA   string
+}
+
+var (
+	nvidiaSmiDriverRegex  = regexp.MustCompile(`Driver Version:\s*([0-9.]+)`)
+	nvidiaSmiCUDARegex    = regexp.MustCompile(`CUDA Version:\s*([0-9.]+)`)
+	nvidiaProcDriverRegex = regexp.MustCompile(`Kernel Module\s+([0-9.]+)`)
+)
+
+// DetectNvidiaVersions detects the versions of the NVIDIA driver and CUDA using nvidia-smi. If nvidia-smi is not
+// available, the driver version is read from /proc/driver/nvidia/version and the CUDA version is left empty
+func DetectNvidiaVersions() (*NvidiaVersions, error) {
+	out, smiErr := exec.Command("nvidia-smi").Output()
+	if smiErr == nil {
+		return parseNvidiaSmiVersions(string(out))
+	}
+
+	proc, err := ioutil.ReadFile("/proc/driver/nvidia/version")
+	if err != nil {
+		return nil, smiErr
+	}
+	return parseNvidiaProcVersion(string(proc))
+}
+
+// parseNvidiaSmiVersions parses the versions from the header printed by nvidia-smi i.e.
+// "NVIDIA-SMI 460.39       Driver Version: 460.39       CUDA Version: 11.2"
+func parseNvidiaSmiVersions(out string) (*NvidiaVersions, error) {
+	driver := nvidiaSmiDriverRegex.FindStringSubmatch(out)
+	if driver == nil {
+		return nil, errors.New("driver version not found in nvidia-smi output")
+	}
+	versions := &NvidiaVersions{Driver: driver[1]}
+	if cuda := nvidiaSmiCUDARegex.FindStringSubmatch(out); cuda != nil {
+		versions.CUDA = cuda[1]
+	}
+	return versions, nil
+}
+
+// parseNvidiaProcVersion parses the driver version from /proc/driver/nvidia/version i.e.
+// "NVRM version: NVIDIA UNIX x86_64 Kernel Module  460.39  Thu Jan 21 21:54:06 UTC 2021"
+func parseNvidiaProcVersion(out string) (*NvidiaVersions, error) {
+	driver := nvidiaProcDriverRegex.FindStringSubmatch(out)
+	if driver == nil {
+		return nil, errors.New("driver version not found in /proc/driver/nvidia/version")
+	}
+	return &NvidiaVersions{Driver: driver[1]}, nil
+}
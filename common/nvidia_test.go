@@ -0,0 +1,31 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNvidiaVersions(t *testing.T) {
+	assert := assert.New(t)
+
+	smiOut := `
++-----------------------------------------------------------------------------+
+| NVIDIA-SMI 460.39       Driver Version: 460.39       CUDA Version: 11.2     |
+|-------------------------------+----------------------+----------------------+
+`
+	v, err := parseNvidiaSmiVersions(smiOut)
+	assert.Nil(err)
+	assert.Equal(&NvidiaVersions{Driver: "460.39", CUDA: "11.2"}, v)
+
+	_, err = parseNvidiaSmiVersions("NVIDIA-SMI has failed because it couldn't communicate with the NVIDIA driver")
+	assert.EqualError(err, "driver version not found in nvidia-smi output")
+
+	procOut := "NVRM version: NVIDIA UNIX x86_64 Kernel Module  470.57.02  Tue Jul 13 16:14:05 UTC 2021\nGCC version:  gcc version 9.3.0"
+	v, err = parseNvidiaProcVersion(procOut)
+	assert.Nil(err)
+	assert.Equal(&NvidiaVersions{Driver: "470.57.02"}, v)
+
+	_, err = parseNvidiaProcVersion("")
+	assert.EqualError(err, "driver version not found in /proc/driver/nvidia/version")
+}
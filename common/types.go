@@ -114,4 +114,20 @@ type DetectionWebhookRequest struct {
 	ManifestID          string                      `json:"manifestID"`
 	SeqNo               uint64                      `json:"seqNo"`
 	SceneClassification []SceneClassificationResult `json:"sceneClassification"`
+	Events              []DetectionEvent            `json:"events,omitempty"`
+}
+
+type DetectionEventType string
+
+const (
+	DetectionStart DetectionEventType = "start"
+	DetectionStop  DetectionEventType = "stop"
+)
+
+// DetectionEvent reports that a stream level classification started or stopped at segment SeqNo
+type DetectionEvent struct {
+	Class       string             `json:"class"`
+	Type        DetectionEventType `json:"type"`
+	Probability float64            `json:"probability"`
+	SeqNo       uint64             `json:"seqNo"`
 }
@@ -124,7 +124,7 @@ func TxDataToVideoProfile(txData string) ([]ffmpeg.VideoProfile, error) {
 	if len(txData) == 0 {
 		return profiles, nil
 	}
-	if len(txData) < VideoProfileIDSize {
+	if len(txData)%VideoProfileIDSize != 0 {
 		return nil, ErrProfile
 	}
 
@@ -148,7 +148,7 @@ func BytesToVideoProfile(txData []byte) ([]ffmpeg.VideoProfile, error) {
 	if len(txData) == 0 {
 		return profiles, nil
 	}
-	if len(txData) < VideoProfileIDBytes {
+	if len(txData)%VideoProfileIDBytes != 0 {
 		return nil, ErrProfile
 	}
 
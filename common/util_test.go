@@ -24,6 +24,9 @@ func TestTxDataToVideoProfile(t *testing.T) {
 	if _, err := TxDataToVideoProfile("abcdefghijk"); err != ErrProfile {
 		t.Error("Unexpected return on invalid input", err)
 	}
+	if _, err := TxDataToVideoProfile("93c717e7c0a6517aab"); err != ErrProfile {
+		t.Error("Unexpected return on input with trailing data", err)
+	}
 	res, err := TxDataToVideoProfile("93c717e7c0a6517a")
 	if err != nil || res[1] != ffmpeg.P240p30fps16x9 || res[0] != ffmpeg.P360p30fps16x9 {
 		t.Error("Unexpected profile! ", err, res)
@@ -46,7 +49,11 @@ func TestVideoProfileBytes(t *testing.T) {
 	if _, err := BytesToVideoProfile([]byte("abcdefghijk")); err != ErrProfile {
 		t.Error("Unexpected return on invalid input", err)
 	}
-	b, _ := hex.DecodeString("93c717e7c0a6517a")
+	b, _ := hex.DecodeString("93c717e7c0a6517aab")
+	if _, err := BytesToVideoProfile(b); err != ErrProfile {
+		t.Error("Unexpected return on input with trailing data", err)
+	}
+	b, _ = hex.DecodeString("93c717e7c0a6517a")
 	res, err := BytesToVideoProfile(b)
 	if err != nil || res[1] != ffmpeg.P240p30fps16x9 || res[0] != ffmpeg.P360p30fps16x9 {
 		t.Error("Unexpected profile! ", err, res)
@@ -0,0 +1,172 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Watchdog detects subsystems that stop making progress, i.e. an event loop that no longer polls for blocks or a
+// receipt waiter that is stuck on a transaction. Subsystems report their progress with the Heartbeat returned by
+// Watch. Once a busy subsystem did not make any progress within the threshold, the watchdog logs a dump of all
+// goroutines, calls onStall and restarts the subsystem if restarting is enabled
+type Watchdog struct {
+	threshold time.Duration
+	restart   bool
+	onStall   func(name string)
+
+	mu         sync.Mutex
+	heartbeats []*Heartbeat
+}
+
+// NewWatchdog creates a Watchdog that reports a subsystem as stalled if it did not make progress within threshold.
+// onStall is called with the name of the stalled subsystem, i.e. to record an alert metric, and can be nil
+func NewWatchdog(threshold time.Duration, restart bool, onStall func(name string)) *Watchdog {
+	return &Watchdog{
+		threshold: threshold,
+		restart:   restart,
+		onStall:   onStall,
+	}
+}
+
+// Watch returns the Heartbeat that the subsystem name reports its progress with. restart is called if the subsystem
+// stalls and restarting is enabled. It is nil if the subsystem cannot be restarted
+func (w *Watchdog) Watch(name string, restart func()) *Heartbeat {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	hb := &Heartbeat{name: name, restart: restart, last: time.Now()}
+	w.heartbeats = append(w.heartbeats, hb)
+	return hb
+}
+
+// Start checks the heartbeats of the watched subsystems until ctx is done
+func (w *Watchdog) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.threshold / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.check(time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check reports the subsystems that stalled as of now. A subsystem is only reported once until it makes progress
+// again
+func (w *Watchdog) check(now time.Time) []string {
+	w.mu.Lock()
+	heartbeats := make([]*Heartbeat, len(w.heartbeats))
+	copy(heartbeats, w.heartbeats)
+	w.mu.Unlock()
+
+	var stalled []string
+	for _, hb := range heartbeats {
+		since, ok := hb.stall(now, w.threshold)
+		if !ok {
+			continue
+		}
+		stalled = append(stalled, hb.name)
+
+		glog.Errorf("Watchdog detected stalled subsystem=%v without progress for %v, dumping goroutines:\n%s", hb.name, since, goroutineDump())
+		if w.onStall != nil {
+			w.onStall(hb.name)
+		}
+		if !w.restart {
+			continue
+		}
+		if hb.restart == nil {
+			glog.Errorf("Watchdog cannot restart subsystem=%v", hb.name)
+			continue
+		}
+		glog.Infof("Watchdog restarting subsystem=%v", hb.name)
+		hb.restart()
+	}
+	return stalled
+}
+
+func goroutineDump() []byte {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		glog.Errorf("Error dumping goroutines err=%q", err)
+	}
+	return buf.Bytes()
+}
+
+// Heartbeat tracks the progress of a subsystem watched by a Watchdog. A subsystem is only expected to make progress
+// while it is busy, i.e. a receipt waiter while there is a transaction to wait for. The methods of a nil Heartbeat
+// do nothing so that subsystems do not need to check whether they are watched
+type Heartbeat struct {
+	name    string
+	restart func()
+
+	mu      sync.Mutex
+	busy    int
+	last    time.Time
+	stalled bool
+}
+
+// Busy marks the start of work that the subsystem is expected to make progress on. Busy can be called again before
+// the work is done if the subsystem works on several things concurrently
+func (h *Heartbeat) Busy() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.busy == 0 {
+		h.progress()
+	}
+	h.busy++
+}
+
+// Beat records progress of the subsystem
+func (h *Heartbeat) Beat() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.progress()
+}
+
+// Idle marks the end of work started with Busy
+func (h *Heartbeat) Idle() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.busy > 0 {
+		h.busy--
+	}
+	h.progress()
+}
+
+func (h *Heartbeat) progress() {
+	h.last = time.Now()
+	h.stalled = false
+}
+
+// stall returns how long the subsystem has been without progress and true if it is busy and newly stalled as of now
+func (h *Heartbeat) stall(now time.Time, threshold time.Duration) (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	since := now.Sub(h.last)
+	if h.busy == 0 || h.stalled || since < threshold {
+		return since, false
+	}
+	h.stalled = true
+	return since, true
+}
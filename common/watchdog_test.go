@@ -0,0 +1,97 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdog_Check(t *testing.T) {
+	assert := assert.New(t)
+
+	var alerts []string
+	restarts := 0
+	wd := NewWatchdog(time.Minute, false, func(name string) { alerts = append(alerts, name) })
+	events := wd.Watch("events", func() { restarts++ })
+	receipts := wd.Watch("receipts", nil)
+
+	// Idle subsystems are not expected to make progress
+	assert.Empty(wd.check(time.Now().Add(2 * time.Minute)))
+
+	events.Busy()
+	receipts.Busy()
+	assert.Empty(wd.check(time.Now()))
+	assert.Equal([]string{"events", "receipts"}, wd.check(time.Now().Add(2*time.Minute)))
+	assert.Equal([]string{"events", "receipts"}, alerts)
+	assert.Equal(0, restarts)
+
+	// A stalled subsystem is only reported once until it makes progress again
+	assert.Empty(wd.check(time.Now().Add(2 * time.Minute)))
+	events.Beat()
+	receipts.Idle()
+	assert.Empty(wd.check(time.Now()))
+	assert.Equal([]string{"events"}, wd.check(time.Now().Add(2*time.Minute)))
+}
+
+func TestWatchdog_Restart(t *testing.T) {
+	assert := assert.New(t)
+
+	restarts := 0
+	wd := NewWatchdog(time.Minute, true, nil)
+	events := wd.Watch("events", func() { restarts++ })
+	segments := wd.Watch("segments", nil)
+
+	events.Busy()
+	segments.Busy()
+	assert.Equal([]string{"events", "segments"}, wd.check(time.Now().Add(2*time.Minute)))
+	assert.Equal(1, restarts)
+}
+
+func TestWatchdog_Start(t *testing.T) {
+	assert := assert.New(t)
+
+	stalled := make(chan string, 1)
+	wd := NewWatchdog(20*time.Millisecond, false, func(name string) { stalled <- name })
+	hb := wd.Watch("segments", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go wd.Start(ctx)
+
+	hb.Busy()
+	select {
+	case name := <-stalled:
+		assert.Equal("segments", name)
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for stall")
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	assert := assert.New(t)
+
+	// The methods of a nil heartbeat do nothing
+	var nilHb *Heartbeat
+	nilHb.Busy()
+	nilHb.Beat()
+	nilHb.Idle()
+
+	// Concurrent work keeps the subsystem busy until all of it is done
+	hb := &Heartbeat{}
+	hb.Busy()
+	hb.Busy()
+	hb.Idle()
+	_, stalled := hb.stall(time.Now().Add(time.Hour), time.Minute)
+	assert.True(stalled)
+	hb.Idle()
+	_, stalled = hb.stall(time.Now().Add(time.Hour), time.Minute)
+	assert.False(stalled)
+
+	// Idle is not counted below zero
+	hb.Idle()
+	hb.Busy()
+	_, stalled = hb.stall(time.Now().Add(time.Hour), time.Minute)
+	assert.True(stalled)
+}
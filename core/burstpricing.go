@@ -0,0 +1,108 @@
+package core
+
+import (
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// BurstPricing raises the price that an orchestrator quotes a broadcaster that sends more work than its normal rate
+// for a sustained period. Each broadcaster has a token bucket of pixels that refills at Rate pixels per second up to
+// Burst pixels and that is drained by the pixels transcoded for the broadcaster. Short bursts above Rate are covered by
+// the bucket and charged the standard price. Once the bucket is empty, the price is multiplied by 1 + overage / Burst
+// up to MaxMultiplier, where the overage is the number of pixels transcoded in excess of the bucket. The overage is
+// paid down at Rate, so the price returns to the standard price once the broadcaster slows down
+type BurstPricing struct {
+	Rate          float64
+	Burst         float64
+	MaxMultiplier float64
+
+	mu      sync.Mutex
+	buckets map[ethcommon.Address]*pixelBucket
+}
+
+type pixelBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewBurstPricing creates a BurstPricing for a normal rate of rate pixels per second with bursts of up to burst pixels
+func NewBurstPricing(rate, burst, maxMultiplier float64) *BurstPricing {
+	return &BurstPricing{
+		Rate:          rate,
+		Burst:         burst,
+		MaxMultiplier: maxMultiplier,
+		buckets:       make(map[ethcommon.Address]*pixelBucket),
+	}
+}
+
+// use drains the pixels transcoded for sender from the bucket of sender
+func (p *BurstPricing) use(sender ethcommon.Address, pixels int64, now time.Time) {
+	if p == nil || pixels <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b := p.refill(sender, now)
+	if b == nil {
+		b = &pixelBucket{tokens: p.Burst, updated: now}
+		p.buckets[sender] = b
+	}
+	b.tokens -= float64(pixels)
+
+	// Cap the overage at the overage of the max multiplier so that the price returns to the standard price in a
+	// bounded amount of time
+	if min := -p.Burst * (p.MaxMultiplier - 1); b.tokens < min {
+		b.tokens = min
+	}
+}
+
+// multiplier returns the factor that the price quoted to sender is multiplied with
+func (p *BurstPricing) multiplier(sender ethcommon.Address, now time.Time) float64 {
+	if p == nil {
+		return 1
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b := p.refill(sender, now)
+	if b == nil || b.tokens >= 0 {
+		return 1
+	}
+	return math.Min(p.MaxMultiplier, 1-b.tokens/p.Burst)
+}
+
+// price returns price multiplied with the multiplier for sender
+func (p *BurstPricing) price(sender ethcommon.Address, price *big.Rat, now time.Time) *big.Rat {
+	m := p.multiplier(sender, now)
+	if m <= 1 {
+		return price
+	}
+	return new(big.Rat).Mul(price, new(big.Rat).SetFloat64(m))
+}
+
+// refill adds the tokens for the time since the last update to the bucket of sender and returns the bucket, nil if the
+// bucket is full. Full buckets are forgotten so that only the buckets of active broadcasters are kept. The caller must
+// hold mu
+func (p *BurstPricing) refill(sender ethcommon.Address, now time.Time) *pixelBucket {
+	b, ok := p.buckets[sender]
+	if !ok {
+		return nil
+	}
+
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * p.Rate
+		b.updated = now
+	}
+	if b.tokens >= p.Burst {
+		delete(p.buckets, sender)
+		return nil
+	}
+	return b
+}
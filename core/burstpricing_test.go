@@ -0,0 +1,113 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/livepeer/go-livepeer/net"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBurstPricing_Multiplier(t *testing.T) {
+	assert := assert.New(t)
+
+	// Normal rate of 100 pixels/s with bursts of up to 1000 pixels
+	p := NewBurstPricing(100, 1000, 3)
+	sender := pm.RandAddress()
+	other := pm.RandAddress()
+	now := time.Now()
+
+	// Nil BurstPricing does not change the price
+	var nilPricing *BurstPricing
+	nilPricing.use(sender, 1000, now)
+	assert.Equal(1.0, nilPricing.multiplier(sender, now))
+
+	// Bursts within the bucket are charged the standard price
+	p.use(sender, 600, now)
+	p.use(sender, 400, now)
+	assert.Equal(1.0, p.multiplier(sender, now))
+
+	// Overage increases the price in proportion
+	p.use(sender, 500, now)
+	assert.Equal(1.5, p.multiplier(sender, now))
+	assert.Equal(1.0, p.multiplier(other, now))
+
+	// The overage is paid down at the normal rate
+	now = now.Add(2 * time.Second)
+	assert.Equal(1.3, p.multiplier(sender, now))
+	now = now.Add(3 * time.Second)
+	assert.Equal(1.0, p.multiplier(sender, now))
+
+	// The multiplier is capped and the overage beyond the cap is not counted
+	p.use(sender, 100000, now)
+	assert.Equal(3.0, p.multiplier(sender, now))
+	now = now.Add(10 * time.Second)
+	assert.Equal(2.0, p.multiplier(sender, now))
+
+	// Full buckets are forgotten
+	now = now.Add(time.Minute)
+	assert.Equal(1.0, p.multiplier(sender, now))
+	assert.Empty(p.buckets)
+
+	// Sending at the normal rate never increases the price
+	for i := 0; i < 100; i++ {
+		now = now.Add(time.Second)
+		p.use(sender, 100, now)
+		assert.Equal(1.0, p.multiplier(sender, now))
+	}
+}
+
+func TestBurstPricing_Price(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewBurstPricing(100, 1000, 2)
+	sender := pm.RandAddress()
+	now := time.Now()
+
+	price := big.NewRat(3, 2)
+	assert.Equal(price, p.price(sender, price, now))
+
+	p.use(sender, 1250, now)
+	assert.Zero(big.NewRat(15, 8).Cmp(p.price(sender, price, now)))
+	// The price is not modified
+	assert.Zero(big.NewRat(3, 2).Cmp(price))
+}
+
+func TestPriceInfo_BurstPricing(t *testing.T) {
+	assert := assert.New(t)
+
+	n, _ := NewLivepeerNode(nil, "", nil)
+	n.SetBasePrice(big.NewRat(1, 3))
+	n.AutoAdjustPrice = false
+	n.Balances = NewAddressBalances(5 * time.Second)
+	recipient := new(pm.MockRecipient)
+	recipient.On("TxCostMultiplier", mock.Anything).Return(big.NewRat(1, 1), nil)
+	n.Recipient = recipient
+	n.BurstPricing = NewBurstPricing(1, 1000, 2)
+	orch := NewOrchestrator(n, nil)
+
+	sender := pm.RandAddress()
+	priceInfo, err := orch.PriceInfo(sender)
+	assert.Nil(err)
+	assert.Zero(big.NewRat(333, 1000).Cmp(big.NewRat(priceInfo.PricePerUnit, priceInfo.PixelsPerUnit)))
+
+	// Transcoded pixels above the burst raise the quoted price
+	orch.DebitFees(sender, ManifestID("foo"), priceInfo, 1500)
+	priceInfo, err = orch.PriceInfo(sender)
+	assert.Nil(err)
+	assert.Zero(big.NewRat(499, 1000).Cmp(big.NewRat(priceInfo.PricePerUnit, priceInfo.PixelsPerUnit)))
+
+	// Other broadcasters are quoted the standard price
+	priceInfo, err = orch.PriceInfo(pm.RandAddress())
+	assert.Nil(err)
+	assert.Zero(big.NewRat(333, 1000).Cmp(big.NewRat(priceInfo.PricePerUnit, priceInfo.PixelsPerUnit)))
+
+	// Pixels are not counted offchain
+	n.Balances = nil
+	other := pm.RandAddress()
+	orch.DebitFees(other, ManifestID("foo"), &net.PriceInfo{PricePerUnit: 1, PixelsPerUnit: 1}, 1500)
+	assert.Equal(1.0, n.BurstPricing.multiplier(other, time.Now()))
+}
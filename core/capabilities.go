@@ -51,6 +51,7 @@ const (
 	Capability_H264_Decode_444_10bit
 	Capability_H264_Decode_422_10bit
 	Capability_H264_Decode_420_10bit
+	Capability_CustomFilters
 )
 
 var CapabilityNameLookup = map[Capability]string{
@@ -77,6 +78,7 @@ var CapabilityNameLookup = map[Capability]string{
 	Capability_VP9_Decode:                 "VP9 decode",
 	Capability_VP8_Encode:                 "VP8 encode",
 	Capability_VP9_Encode:                 "VP9 encode",
+	Capability_CustomFilters:              "Custom filters",
 }
 
 var CapabilityTestLookup = map[Capability]CapabilityTest{
@@ -290,6 +292,11 @@ func JobCapabilities(params *StreamParameters) (*Capabilities, error) {
 	}
 	caps[storageCap] = true
 
+	// custom filters are applied in the transcode pass
+	if len(params.Filters) > 0 {
+		caps[Capability_CustomFilters] = true
+	}
+
 	// capabilities based on detector profiles
 	for _, profile := range params.Detection.Profiles {
 		switch profile.Type() {
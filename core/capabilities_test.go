@@ -98,7 +98,7 @@ func TestCapability_TranscoderCapabilities(t *testing.T) {
 	devices, err := common.ParseNvidiaDevices("all")
 	devicesAvailable := err != nil && len(devices) > 0
 	if devicesAvailable {
-		nvidiaCaps, err := TestTranscoderCapabilities(devices)
+		nvidiaCaps, err := TestTranscoderCapabilities(devices, nil)
 		assert.Nil(t, err)
 		assert.False(t, InArray(Capability_H264_Decode_444_8bit, nvidiaCaps), "Nvidia device should not support decode of 444_8bit")
 		assert.False(t, InArray(Capability_H264_Decode_422_8bit, nvidiaCaps), "Nvidia device should not support decode of 422_8bit")
@@ -235,6 +235,18 @@ func TestCapability_JobCapabilities(t *testing.T) {
 		Capability_MPEG7VideoSignature,
 	}), "failed with fast verification enabled")
 
+	// check custom filters
+	params.Filters = []FilterStep{{Name: "fps", Args: map[string]string{"fps": "30"}}}
+	assert.True(checkSuccess(params, []Capability{
+		Capability_H264,
+		Capability_MPEGTS,
+		Capability_FractionalFramerates,
+		Capability_AuthToken,
+		Capability_MPEG7VideoSignature,
+		Capability_CustomFilters,
+	}), "failed with custom filters")
+	params.Filters = nil
+
 	// check error case with format
 	params.Profiles = []ffmpeg.VideoProfile{{Format: -1}}
 	_, err = JobCapabilities(params)
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+// CapabilityTestCache remembers which capabilities passed the capability test on which device so that only new
+// capabilities, capabilities that failed before and capabilities that are explicitly re-tested are tested at
+// startup. The cache is discarded if the node version or the driver version changes
+type CapabilityTestCache struct {
+	path   string
+	retest []Capability
+
+	mu   sync.Mutex
+	data capabilityTestCacheData
+}
+
+type capabilityTestCacheData struct {
+	Version string                  `json:"version"`
+	Driver  string                  `json:"driver"`
+	Passed  map[string][]Capability `json:"passed"` // device ID -> capabilities
+}
+
+// NewCapabilityTestCache loads the cache from path. Cached results for the capabilities in retest are ignored
+func NewCapabilityTestCache(path, driver string, retest []Capability) *CapabilityTestCache {
+	c := &CapabilityTestCache{
+		path:   path,
+		retest: retest,
+		data:   capabilityTestCacheData{Version: LivepeerVersion, Driver: driver, Passed: make(map[string][]Capability)},
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.Warningf("Error reading capability test cache path=%s err=%q", path, err)
+		}
+		return c
+	}
+
+	var data capabilityTestCacheData
+	if err := json.Unmarshal(b, &data); err != nil {
+		glog.Warningf("Ignoring invalid capability test cache path=%s err=%q", path, err)
+		return c
+	}
+	if data.Version != LivepeerVersion || data.Driver != driver || data.Passed == nil {
+		glog.Infof("Ignoring capability test cache for version=%s driver=%s", data.Version, data.Driver)
+		return c
+	}
+	c.data = data
+
+	return c
+}
+
+// passed returns true if capability passed the test on device before and does not need to be re-tested
+func (c *CapabilityTestCache) passed(device string, capability Capability) bool {
+	if c == nil || InArray(capability, c.retest) {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return InArray(capability, c.data.Passed[device])
+}
+
+// record records the result of testing capability on device
+func (c *CapabilityTestCache) record(device string, capability Capability, passed bool) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var caps []Capability
+	for _, cap := range c.data.Passed[device] {
+		if cap != capability {
+			caps = append(caps, cap)
+		}
+	}
+	if passed {
+		caps = append(caps, capability)
+	}
+	c.data.Passed[device] = caps
+}
+
+// Save writes the cache to disk
+func (c *CapabilityTestCache) Save() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	b, err := json.Marshal(c.data)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path, b, 0644)
+}
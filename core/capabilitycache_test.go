@@ -0,0 +1,71 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilityTestCache(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tempDir, err := ioutil.TempDir("", "TestCapabilityTestCache")
+	require.Nil(err)
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "capabilities_cache.json")
+
+	// Nothing is cached yet
+	cache := NewCapabilityTestCache(path, "460.39", nil)
+	assert.False(cache.passed("0", Capability_H264))
+
+	cache.record("0", Capability_H264, true)
+	cache.record("0", Capability_HEVC_Encode, true)
+	cache.record("1", Capability_H264, true)
+	cache.record("1", Capability_HEVC_Decode, false)
+	assert.True(cache.passed("0", Capability_H264))
+	assert.False(cache.passed("1", Capability_HEVC_Decode))
+
+	// A capability that fails is removed from the cache
+	cache.record("0", Capability_HEVC_Encode, false)
+	assert.False(cache.passed("0", Capability_HEVC_Encode))
+	require.Nil(cache.Save())
+
+	// The results are loaded from disk
+	cache = NewCapabilityTestCache(path, "460.39", nil)
+	assert.True(cache.passed("0", Capability_H264))
+	assert.True(cache.passed("1", Capability_H264))
+	assert.False(cache.passed("0", Capability_HEVC_Encode))
+	assert.False(cache.passed("2", Capability_H264))
+
+	// Capabilities that are re-tested ignore the cache
+	cache = NewCapabilityTestCache(path, "460.39", []Capability{Capability_H264})
+	assert.False(cache.passed("0", Capability_H264))
+
+	// The cache is discarded if the driver changes
+	cache = NewCapabilityTestCache(path, "470.57.02", nil)
+	assert.False(cache.passed("0", Capability_H264))
+
+	// The cache is discarded if the node version changes
+	oldVersion := LivepeerVersion
+	defer func() { LivepeerVersion = oldVersion }()
+	LivepeerVersion = "0.0.0-new"
+	cache = NewCapabilityTestCache(path, "460.39", nil)
+	assert.False(cache.passed("0", Capability_H264))
+	LivepeerVersion = oldVersion
+
+	// An invalid cache file is ignored
+	require.Nil(ioutil.WriteFile(path, []byte("foo"), 0644))
+	cache = NewCapabilityTestCache(path, "460.39", nil)
+	assert.False(cache.passed("0", Capability_H264))
+
+	// A nil cache never has results
+	var nilCache *CapabilityTestCache
+	nilCache.record("0", Capability_H264, true)
+	assert.False(nilCache.passed("0", Capability_H264))
+	assert.Nil(nilCache.Save())
+}
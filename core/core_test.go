@@ -127,6 +127,13 @@ func TestTranscodeSeg(t *testing.T) {
 	}
 	resHash := ethCrypto.Keccak256(resHashes...)
 	assert.Equal(resHash, res.Sig)
+
+	// Segments past their deadline are not transcoded
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	res = n.transcodeSeg(ctx, conf, seg, md)
+	assert.Equal(ErrSegmentDeadlineExceeded, res.Err)
+	assert.Nil(res.TranscodeData)
 }
 
 func TestTranscodeLoop_GivenNoSegmentsPastTimeout_CleansSegmentChan(t *testing.T) {
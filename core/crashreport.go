@@ -0,0 +1,156 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/monitor"
+)
+
+// CrashReportDir is the directory that the reports of panics while transcoding are written to. Reports are not
+// written if empty
+var CrashReportDir string
+
+// CrashReportWebhookURL is the URL that the reports of panics while transcoding are posted to. Reports are not posted
+// if nil
+var CrashReportWebhookURL *url.URL
+
+var crashReportClient = &http.Client{Timeout: 5 * time.Second}
+
+// maxCrashReports is the number of reports kept in CrashReportDir. The oldest reports are removed first
+const maxCrashReports = 100
+
+// CrashReport describes a panic while transcoding so that recurring GPU panics can be diagnosed
+type CrashReport struct {
+	Time    time.Time           `json:"time"`
+	Version string              `json:"version"`
+	Device  string              `json:"device"`
+	Panic   string              `json:"panic"`
+	Stack   string              `json:"stack"`
+	Segment *CrashReportSegment `json:"segment,omitempty"`
+}
+
+// CrashReportSegment is the segment that was being transcoded when the panic occurred
+type CrashReportSegment struct {
+	ManifestID string        `json:"manifestID"`
+	Seq        int64         `json:"seq"`
+	Fname      string        `json:"fname"`
+	Duration   time.Duration `json:"duration"`
+	Profiles   []string      `json:"profiles"`
+	Detector   bool          `json:"detector"`
+}
+
+// recoverFromPanic recovers from a panic while transcoding md on device, reports the crash and returns an
+// UnrecoverableError in retErr
+func recoverFromPanic(retErr *error, device string, md *SegTranscodingMetadata) {
+	if r := recover(); r != nil {
+		err, ok := r.(error)
+		if !ok {
+			err = errors.New("unrecoverable transcoding failure")
+		}
+		*retErr = NewUnrecoverableError(err)
+		reportCrash(newCrashReport(r, debug.Stack(), device, md))
+	}
+}
+
+func newCrashReport(r interface{}, stack []byte, device string, md *SegTranscodingMetadata) *CrashReport {
+	report := &CrashReport{
+		Time:    time.Now(),
+		Version: LivepeerVersion,
+		Device:  device,
+		Panic:   fmt.Sprint(r),
+		Stack:   string(stack),
+	}
+	if md != nil {
+		seg := &CrashReportSegment{
+			ManifestID: string(md.ManifestID),
+			Seq:        md.Seq,
+			Fname:      md.Fname,
+			Duration:   md.Duration,
+			Detector:   md.DetectorEnabled,
+		}
+		for _, p := range md.Profiles {
+			seg.Profiles = append(seg.Profiles, p.Name)
+		}
+		report.Segment = seg
+	}
+	return report
+}
+
+// reportCrash logs report, counts it in the transcode_panics metric, writes it to CrashReportDir and posts it to
+// CrashReportWebhookURL
+func reportCrash(report *CrashReport) {
+	var seq int64 = -1
+	if report.Segment != nil {
+		seq = report.Segment.Seq
+	}
+	glog.Errorf("Recovered from panic while transcoding device=%s seqNo=%d panic=%q\n%s", report.Device, seq, report.Panic, report.Stack)
+	if monitor.Enabled {
+		monitor.TranscodePanic(report.Device)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		glog.Errorf("Error encoding crash report err=%q", err)
+		return
+	}
+	if CrashReportDir != "" {
+		if fname, err := writeCrashReport(CrashReportDir, report.Time, data); err != nil {
+			glog.Errorf("Error writing crash report err=%q", err)
+		} else {
+			glog.Errorf("Wrote crash report file=%s", fname)
+		}
+	}
+	if CrashReportWebhookURL != nil {
+		go postCrashReport(data)
+	}
+}
+
+// writeCrashReport writes the report data to a new file in dir and removes the oldest reports in excess of
+// maxCrashReports
+func writeCrashReport(dir string, t time.Time, data []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	fname := filepath.Join(dir, fmt.Sprintf("crash-%s-%09d.json", t.UTC().Format("20060102T150405"), t.Nanosecond()))
+	if err := ioutil.WriteFile(fname, data, 0644); err != nil {
+		return "", err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "crash-*.json"))
+	if err != nil {
+		return fname, nil
+	}
+	// The names sort in the order the reports were written
+	sort.Strings(files)
+	for len(files) > maxCrashReports {
+		os.Remove(files[0])
+		files = files[1:]
+	}
+	return fname, nil
+}
+
+func postCrashReport(data []byte) {
+	resp, err := crashReportClient.Post(CrashReportWebhookURL.String(), "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		glog.Errorf("Unable to POST crash report on webhook url=%v err=%q", CrashReportWebhookURL.Redacted(), err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		glog.Errorf("Crash report webhook returned error status=%v err=%q", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
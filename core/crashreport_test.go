@@ -0,0 +1,88 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrashReport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	oldDir, oldURL := CrashReportDir, CrashReportWebhookURL
+	defer func() { CrashReportDir, CrashReportWebhookURL = oldDir, oldURL }()
+
+	posted := make(chan *CrashReport, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report CrashReport
+		assert.Nil(json.NewDecoder(r.Body).Decode(&report))
+		posted <- &report
+	}))
+	defer ts.Close()
+	CrashReportWebhookURL, _ = url.Parse(ts.URL)
+	CrashReportDir = filepath.Join(t.TempDir(), "crashes")
+
+	md := &SegTranscodingMetadata{
+		ManifestID: "stream1",
+		Seq:        7,
+		Fname:      "test.ts",
+		Profiles:   []ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9, ffmpeg.P240p30fps16x9},
+	}
+	f := func() (err error) {
+		defer recoverFromPanic(&err, "1", md)
+		panic(errors.New("CUDA_ERROR_ILLEGAL_ADDRESS"))
+	}
+	err := f()
+	assert.Equal(NewUnrecoverableError(errors.New("CUDA_ERROR_ILLEGAL_ADDRESS")), err)
+
+	// The report is written to disk
+	files, _ := filepath.Glob(filepath.Join(CrashReportDir, "crash-*.json"))
+	require.Len(files, 1)
+	data, err := ioutil.ReadFile(files[0])
+	require.Nil(err)
+	var report CrashReport
+	require.Nil(json.Unmarshal(data, &report))
+	assert.Equal("1", report.Device)
+	assert.Equal("CUDA_ERROR_ILLEGAL_ADDRESS", report.Panic)
+	assert.Contains(report.Stack, "TestCrashReport")
+	require.NotNil(report.Segment)
+	assert.Equal("stream1", report.Segment.ManifestID)
+	assert.Equal(int64(7), report.Segment.Seq)
+	assert.Equal([]string{"P144p30fps16x9", "P240p30fps16x9"}, report.Segment.Profiles)
+
+	// The report is posted to the webhook
+	select {
+	case p := <-posted:
+		assert.Equal(report.Stack, p.Stack)
+		assert.Equal("stream1", p.Segment.ManifestID)
+	case <-time.After(time.Second):
+		assert.Fail("crash report was not posted")
+	}
+}
+
+func TestWriteCrashReport(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	start := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < maxCrashReports+5; i++ {
+		_, err := writeCrashReport(dir, start.Add(time.Duration(i)*time.Second), []byte("{}"))
+		assert.Nil(err)
+	}
+
+	// The oldest reports are removed
+	files, _ := filepath.Glob(filepath.Join(dir, "crash-*.json"))
+	assert.Len(files, maxCrashReports)
+	assert.Equal("crash-20220301T000005-000000000.json", filepath.Base(files[0]))
+}
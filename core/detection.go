@@ -0,0 +1,97 @@
+package core
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/livepeer/go-livepeer/common"
+)
+
+const defaultDetectionSmoothing = 0.5
+
+// DetectionFee returns the fee in wei to run detection on duration of video at pricePerSecond. Segments without
+// renditions are priced by their duration as the cost of detection does not depend on output pixels
+func DetectionFee(pricePerSecond *big.Int, duration time.Duration) *big.Rat {
+	fee := new(big.Rat).SetInt(pricePerSecond)
+	return fee.Mul(fee, big.NewRat(duration.Milliseconds(), 1000))
+}
+
+// DetectionAggregator smooths the per segment scene classification probabilities of a stream into stream level
+// classifications with hysteresis, so that noisy per segment probabilities do not cause classifications to flap
+type DetectionAggregator struct {
+	classes   []string
+	start     float64
+	stop      float64
+	smoothing float64
+
+	mu     sync.Mutex
+	scores map[string]float64
+	active map[string]bool
+}
+
+// NewDetectionAggregator returns an aggregator for the selected classes of config or nil if stream level
+// classifications are disabled
+func NewDetectionAggregator(config DetectionConfig) *DetectionAggregator {
+	if config.StartThreshold <= 0 {
+		return nil
+	}
+	stop := config.StopThreshold
+	if stop <= 0 || stop > config.StartThreshold {
+		stop = config.StartThreshold
+	}
+	smoothing := config.Smoothing
+	if smoothing <= 0 || smoothing > 1 {
+		smoothing = defaultDetectionSmoothing
+	}
+	return &DetectionAggregator{
+		classes:   config.SelectedClassNames,
+		start:     config.StartThreshold,
+		stop:      stop,
+		smoothing: smoothing,
+		scores:    make(map[string]float64),
+		active:    make(map[string]bool),
+	}
+}
+
+// Add adds the class probabilities of segment seqNo and returns the stream level classifications that started or
+// stopped. Classes without a probability are treated as not detected
+func (a *DetectionAggregator) Add(seqNo uint64, probs map[string]float64) []common.DetectionEvent {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var events []common.DetectionEvent
+	for _, class := range a.classes {
+		score, ok := a.scores[class]
+		if ok {
+			score = a.smoothing*probs[class] + (1-a.smoothing)*score
+		} else {
+			score = probs[class]
+		}
+		a.scores[class] = score
+
+		switch {
+		case !a.active[class] && score >= a.start:
+			a.active[class] = true
+			events = append(events, common.DetectionEvent{Class: class, Type: common.DetectionStart, Probability: score, SeqNo: seqNo})
+		case a.active[class] && score < a.stop:
+			a.active[class] = false
+			events = append(events, common.DetectionEvent{Class: class, Type: common.DetectionStop, Probability: score, SeqNo: seqNo})
+		}
+	}
+	return events
+}
+
+// Active returns the stream level classifications that are currently active
+func (a *DetectionAggregator) Active() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var active []string
+	for _, class := range a.classes {
+		if a.active[class] {
+			active = append(active, class)
+		}
+	}
+	return active
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectionAggregator(t *testing.T) {
+	assert := assert.New(t)
+
+	// Disabled without a start threshold
+	assert.Nil(NewDetectionAggregator(DetectionConfig{SelectedClassNames: []string{"adult"}}))
+
+	a := NewDetectionAggregator(DetectionConfig{SelectedClassNames: []string{"adult", "soccer"}, StartThreshold: 0.8, StopThreshold: 0.4, Smoothing: 0.5})
+	assert.NotNil(a)
+
+	// A single noisy segment does not start a classification once the smoothed probability is established
+	assert.Empty(a.Add(1, map[string]float64{"adult": 0.1}))
+	assert.Empty(a.Add(2, map[string]float64{"adult": 0.9}))
+	assert.Empty(a.Active())
+
+	// Consecutive segments above the start threshold start the classification
+	assert.Empty(a.Add(3, map[string]float64{"adult": 1.0, "soccer": 0.3}))
+	events := a.Add(4, map[string]float64{"adult": 1.0, "soccer": 0.3})
+	assert.Len(events, 1)
+	assert.Equal(common.DetectionEvent{Class: "adult", Type: common.DetectionStart, Probability: 0.875, SeqNo: 4}, events[0])
+	assert.Equal([]string{"adult"}, a.Active())
+
+	// Hysteresis keeps the classification active between the thresholds
+	assert.Empty(a.Add(5, map[string]float64{"adult": 0.3}))
+	assert.Equal([]string{"adult"}, a.Active())
+
+	// Classes missing from a segment are treated as not detected
+	events = a.Add(6, map[string]float64{})
+	assert.Len(events, 1)
+	assert.Equal(common.DetectionStop, events[0].Type)
+	assert.Equal(uint64(6), events[0].SeqNo)
+	assert.InDelta(0.29375, events[0].Probability, 1e-9)
+	assert.Empty(a.Active())
+
+	// The stop threshold defaults to the start threshold and the smoothing to the default
+	a = NewDetectionAggregator(DetectionConfig{SelectedClassNames: []string{"adult"}, StartThreshold: 0.5})
+	assert.Equal(0.5, a.stop)
+	assert.Equal(defaultDetectionSmoothing, a.smoothing)
+}
+
+func TestDetectionFee(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Zero(DetectionFee(big.NewInt(10), 2500*time.Millisecond).Cmp(big.NewRat(25, 1)))
+	assert.Zero(DetectionFee(big.NewInt(3), 1001*time.Millisecond).Cmp(big.NewRat(3003, 1000)))
+	assert.Zero(DetectionFee(big.NewInt(0), 2*time.Second).Sign())
+}
+
+func TestStreamParameters_DetectionOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	params := &StreamParameters{Detection: DetectionConfig{Freq: 1}}
+	assert.True(params.DetectionOnly())
+
+	// Streams with renditions run detection alongside transcoding
+	params.Profiles = []ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9}
+	assert.False(params.DetectionOnly())
+
+	// Streams without renditions or detection are view-only
+	assert.False((&StreamParameters{}).DetectionOnly())
+}
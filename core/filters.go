@@ -0,0 +1,224 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/livepeer/go-livepeer/clog"
+	"github.com/livepeer/lpms/ffmpeg"
+)
+
+// MaxFilterSteps is the maximum number of custom filter steps of a stream
+const MaxFilterSteps = 8
+
+// allowedFilters are the ffmpeg filters that can be used in custom filter steps together with their allowed options
+var allowedFilters = map[string][]string{
+	"crop": {"w", "h", "x", "y"},
+	"pad":  {"w", "h", "x", "y", "color"},
+	"fps":  {"fps"},
+	"eq":   {"brightness", "contrast", "saturation", "gamma"},
+}
+
+// filterArgRegex restricts option values to numbers, colors and arithmetic expressions. Characters that separate
+// options, filters or graphs in the ffmpeg filter syntax are not allowed
+var filterArgRegex = regexp.MustCompile(`^[A-Za-z0-9_.+\-*/()@#]{1,64}$`)
+
+// FilterFFmpeg is the ffmpeg binary that transcodes the segments of streams with custom filters. Custom filters are
+// not supported if empty
+var FilterFFmpeg string
+
+var ErrFiltersUnsupported = errors.New("custom filters are not supported")
+
+// FilterStep is a custom ffmpeg filter that is applied to the source segment when it is transcoded, e.g. to
+// normalize contribution feeds before ABR encoding
+type FilterStep struct {
+	Name string            `json:"name"`
+	Args map[string]string `json:"args"`
+}
+
+// String returns the ffmpeg filter description of the step
+func (f FilterStep) String() string {
+	names := make([]string, 0, len(f.Args))
+	for name := range f.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := make([]string, len(names))
+	for i, name := range names {
+		args[i] = name + "=" + f.Args[name]
+	}
+	if len(args) == 0 {
+		return f.Name
+	}
+	return f.Name + "=" + strings.Join(args, ":")
+}
+
+// ValidateFilterSteps checks the filters and their options against the allowlist
+func ValidateFilterSteps(steps []FilterStep) error {
+	if len(steps) > MaxFilterSteps {
+		return fmt.Errorf("too many filter steps %d max=%d", len(steps), MaxFilterSteps)
+	}
+	for _, step := range steps {
+		opts, ok := allowedFilters[step.Name]
+		if !ok {
+			return fmt.Errorf("filter %q is not allowed", step.Name)
+		}
+		for name, value := range step.Args {
+			if !stringInArray(name, opts) {
+				return fmt.Errorf("option %q of filter %q is not allowed", name, step.Name)
+			}
+			if !filterArgRegex.MatchString(value) {
+				return fmt.Errorf("invalid value %q for option %q of filter %q", value, name, step.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// FilterGraph returns the ffmpeg filter graph that applies the steps in order
+func FilterGraph(steps []FilterStep) string {
+	filters := make([]string, len(steps))
+	for i, step := range steps {
+		filters[i] = step.String()
+	}
+	return strings.Join(filters, ",")
+}
+
+// ParseFilterGraph parses a filter graph built by FilterGraph back into filter steps and validates them
+func ParseFilterGraph(graph string) ([]FilterStep, error) {
+	if graph == "" {
+		return nil, nil
+	}
+	var steps []FilterStep
+	for _, filter := range strings.Split(graph, ",") {
+		step := FilterStep{Args: make(map[string]string)}
+		parts := strings.SplitN(filter, "=", 2)
+		step.Name = parts[0]
+		if len(parts) > 1 {
+			for _, arg := range strings.Split(parts[1], ":") {
+				kv := strings.SplitN(arg, "=", 2)
+				if len(kv) != 2 {
+					return nil, fmt.Errorf("invalid option %q of filter %q", arg, step.Name)
+				}
+				if _, ok := step.Args[kv[0]]; ok {
+					return nil, fmt.Errorf("duplicate option %q of filter %q", kv[0], step.Name)
+				}
+				step.Args[kv[0]] = kv[1]
+			}
+		}
+		steps = append(steps, step)
+	}
+	if err := ValidateFilterSteps(steps); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// transcodeWithFilters transcodes the segment of md with the FilterFFmpeg binary. The source is decoded once, the custom
+// filters are applied and the filtered video is scaled and encoded into every profile in the same pass. Audio and
+// timestamps are preserved
+func transcodeWithFilters(ctx context.Context, workDir string, md *SegTranscodingMetadata) (*TranscodeData, error) {
+	if FilterFFmpeg == "" {
+		return nil, ErrFiltersUnsupported
+	}
+	if err := ValidateFilterSteps(md.Filters); err != nil {
+		return nil, err
+	}
+	if md.DetectorEnabled {
+		return nil, errors.New("detection is not supported with custom filters")
+	}
+
+	dir, err := ioutil.TempDir(workDir, "filters")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	n := len(md.Profiles)
+	graph := fmt.Sprintf("[0:v]%s,split=%d", FilterGraph(md.Filters), n)
+	for i := 0; i < n; i++ {
+		graph += fmt.Sprintf("[f%d]", i)
+	}
+	var outArgs []string
+	outputs := make([]string, n)
+	for i, p := range md.Profiles {
+		if p.Encoder != ffmpeg.H264 || (p.Format != ffmpeg.FormatNone && p.Format != ffmpeg.FormatMPEGTS) {
+			return nil, fmt.Errorf("profile %s is not supported with custom filters", p.Name)
+		}
+		w, h, err := ffmpeg.VideoProfileResolution(p)
+		if err != nil {
+			return nil, err
+		}
+		outputs[i] = filepath.Join(dir, fmt.Sprintf("out_%d.ts", i))
+		graph += fmt.Sprintf(";[f%d]scale=%d:%d", i, w, h)
+		if p.Framerate > 0 {
+			den := p.FramerateDen
+			if den == 0 {
+				den = 1
+			}
+			graph += fmt.Sprintf(",fps=%d/%d", p.Framerate, den)
+		}
+		if md.CalcPerceptualHash {
+			graph += fmt.Sprintf(",signature=format=binary:filename=%s.bin", outputs[i])
+		}
+		graph += fmt.Sprintf("[v%d]", i)
+
+		outArgs = append(outArgs, "-map", fmt.Sprintf("[v%d]", i), "-map", "0:a?", "-c:v", "libx264", "-preset", "veryfast",
+			"-b:v", p.Bitrate)
+		if profile := ffmpeg.ProfileParameters[p.Profile]; profile != "" {
+			outArgs = append(outArgs, "-profile:v", profile)
+		}
+		if p.GOP == ffmpeg.GOPIntraOnly {
+			outArgs = append(outArgs, "-g", "1")
+		} else if p.GOP > 0 {
+			outArgs = append(outArgs, "-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%v)", p.GOP.Seconds()))
+		}
+		outArgs = append(outArgs, "-c:a", "copy", "-f", "mpegts", outputs[i])
+	}
+	args := append([]string{"-hide_banner", "-nostats", "-loglevel", "error", "-i", md.Fname, "-copyts",
+		"-filter_complex", graph}, outArgs...)
+
+	cmd := exec.CommandContext(ctx, FilterFFmpeg, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, output)
+	}
+
+	in, err := ioutil.ReadFile(md.Fname)
+	if err != nil {
+		return nil, err
+	}
+	td := &TranscodeData{Pixels: SegmentPixels(in)}
+	for _, out := range outputs {
+		data, err := ioutil.ReadFile(out)
+		if err != nil {
+			clog.Errorf(ctx, "Cannot read transcoded output for name=%s", out)
+			return nil, err
+		}
+		seg := &TranscodedSegmentData{Data: data, Pixels: SegmentPixels(data)}
+		if md.CalcPerceptualHash {
+			if seg.PHash, err = ioutil.ReadFile(out + ".bin"); err != nil {
+				clog.Errorf(ctx, "Cannot read perceptual hash at name=%s.bin", out)
+				return nil, err
+			}
+		}
+		td.Segments = append(td.Segments, seg)
+	}
+	return td, nil
+}
+
+func stringInArray(s string, arr []string) bool {
+	for _, a := range arr {
+		if a == s {
+			return true
+		}
+	}
+	return false
+}
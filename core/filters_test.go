@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFilterSteps(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(ValidateFilterSteps(nil))
+	assert.Nil(ValidateFilterSteps([]FilterStep{
+		{Name: "crop", Args: map[string]string{"w": "iw-20", "h": "ih-20"}},
+		{Name: "pad", Args: map[string]string{"w": "1920", "h": "1080", "x": "(ow-iw)/2", "y": "(oh-ih)/2", "color": "black"}},
+		{Name: "fps", Args: map[string]string{"fps": "30"}},
+		{Name: "eq", Args: map[string]string{"brightness": "0.06", "saturation": "1.2"}},
+	}))
+
+	// Filters that are not in the allowlist
+	assert.EqualError(ValidateFilterSteps([]FilterStep{{Name: "movie", Args: map[string]string{"filename": "/etc/passwd"}}}),
+		`filter "movie" is not allowed`)
+	// Options that are not in the allowlist
+	assert.EqualError(ValidateFilterSteps([]FilterStep{{Name: "crop", Args: map[string]string{"exact": "1"}}}),
+		`option "exact" of filter "crop" is not allowed`)
+	// Values cannot escape the filter
+	assert.EqualError(ValidateFilterSteps([]FilterStep{{Name: "fps", Args: map[string]string{"fps": "30,movie=x"}}}),
+		`invalid value "30,movie=x" for option "fps" of filter "fps"`)
+	assert.NotNil(ValidateFilterSteps([]FilterStep{{Name: "crop", Args: map[string]string{"w": "100:x=0"}}}))
+	assert.NotNil(ValidateFilterSteps([]FilterStep{{Name: "crop", Args: map[string]string{"w": ""}}}))
+	// Too many steps
+	steps := make([]FilterStep, MaxFilterSteps+1)
+	for i := range steps {
+		steps[i] = FilterStep{Name: "fps", Args: map[string]string{"fps": "30"}}
+	}
+	assert.NotNil(ValidateFilterSteps(steps))
+}
+
+func TestFilterGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", FilterGraph(nil))
+	assert.Equal("crop=h=ih-20:w=iw-20,fps=fps=30,eq", FilterGraph([]FilterStep{
+		{Name: "crop", Args: map[string]string{"w": "iw-20", "h": "ih-20"}},
+		{Name: "fps", Args: map[string]string{"fps": "30"}},
+		{Name: "eq"},
+	}))
+}
+
+func TestParseFilterGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	steps, err := ParseFilterGraph("")
+	assert.Nil(err)
+	assert.Nil(steps)
+
+	// Graphs built by FilterGraph round trip
+	want := []FilterStep{
+		{Name: "crop", Args: map[string]string{"w": "iw-20", "h": "ih-20"}},
+		{Name: "fps", Args: map[string]string{"fps": "30"}},
+		{Name: "eq", Args: map[string]string{}},
+	}
+	steps, err = ParseFilterGraph(FilterGraph(want))
+	assert.Nil(err)
+	assert.Equal(want, steps)
+
+	// Graphs are validated
+	_, err = ParseFilterGraph("movie=filename=/etc/passwd")
+	assert.EqualError(err, `filter "movie" is not allowed`)
+	_, err = ParseFilterGraph("crop=iw-20")
+	assert.EqualError(err, `invalid option "iw-20" of filter "crop"`)
+	_, err = ParseFilterGraph("fps=fps=30:fps=60")
+	assert.EqualError(err, `duplicate option "fps" of filter "fps"`)
+	_, err = ParseFilterGraph("fps=fps=30[out];[out]movie=x")
+	assert.NotNil(err)
+}
+
+func TestTranscodeWithFilters(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// The fake ffmpeg binary records its arguments and copies the input to every output
+	dir, err := ioutil.TempDir("", "filters")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+	oldFFmpeg := FilterFFmpeg
+	defer func() { FilterFFmpeg = oldFFmpeg }()
+	FilterFFmpeg = filepath.Join(dir, "ffmpeg")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + filepath.Join(dir, "args") + "\n" +
+		"for a; do\n[ \"$prev\" = -i ] && in=$a\n[ \"$prev\" = mpegts ] && cp $in $a && echo phash > $a.bin\nprev=$a\ndone\nexit 0\n"
+	require.Nil(ioutil.WriteFile(FilterFFmpeg, []byte(script), 0755))
+
+	in, err := ioutil.ReadFile("test.ts")
+	require.Nil(err)
+	p144, p240 := ffmpeg.P144p30fps16x9, ffmpeg.P240p30fps16x9
+	p240.GOP = 2 * time.Second
+	p240.Profile = ffmpeg.ProfileH264Main
+	md := &SegTranscodingMetadata{
+		Fname:    "test.ts",
+		Profiles: []ffmpeg.VideoProfile{p144, p240},
+		Filters:  []FilterStep{{Name: "fps", Args: map[string]string{"fps": "60"}}},
+	}
+	td, err := transcodeWithFilters(context.Background(), dir, md)
+	require.Nil(err)
+	require.Len(td.Segments, 2)
+	assert.Equal(SegmentPixels(in), td.Pixels)
+	assert.NotZero(td.Pixels)
+	for _, seg := range td.Segments {
+		assert.Equal(in, seg.Data)
+		assert.Equal(td.Pixels, seg.Pixels)
+		assert.Nil(seg.PHash)
+	}
+
+	// The filters, scaling and encoding of every profile are applied in a single pass
+	args, err := ioutil.ReadFile(filepath.Join(dir, "args"))
+	require.Nil(err)
+	argList := strings.Split(strings.TrimSpace(string(args)), "\n")
+	assert.Contains(argList, "[0:v]fps=fps=60,split=2[f0][f1];[f0]scale=256:144,fps=30/1[v0];[f1]scale=426:240,fps=30/1[v1]")
+	assert.Contains(string(args), "-map\n[v1]\n-map\n0:a?\n-c:v\nlibx264\n-preset\nveryfast\n-b:v\n600k\n-profile:v\nmain\n-force_key_frames\nexpr:gte(t,n_forced*2)\n")
+	assert.Equal(2, strings.Count(string(args), "\nmpegts\n"))
+
+	// Perceptual hashes are calculated from the filtered renditions
+	md.CalcPerceptualHash = true
+	td, err = transcodeWithFilters(context.Background(), dir, md)
+	require.Nil(err)
+	assert.Equal([]byte("phash\n"), td.Segments[0].PHash)
+	args, err = ioutil.ReadFile(filepath.Join(dir, "args"))
+	require.Nil(err)
+	assert.Contains(string(args), ",signature=format=binary:filename=")
+	md.CalcPerceptualHash = false
+
+	// Detection and outputs other than H.264 in MPEG-TS are not supported
+	md.DetectorEnabled = true
+	_, err = transcodeWithFilters(context.Background(), dir, md)
+	assert.NotNil(err)
+	md.DetectorEnabled = false
+	md.Profiles = []ffmpeg.VideoProfile{{Name: "mp4", Resolution: "256x144", Bitrate: "100k", Format: ffmpeg.FormatMP4}}
+	_, err = transcodeWithFilters(context.Background(), dir, md)
+	assert.EqualError(err, "profile mp4 is not supported with custom filters")
+	md.Profiles = []ffmpeg.VideoProfile{p144}
+
+	// ffmpeg errors are returned with the output
+	require.Nil(ioutil.WriteFile(FilterFFmpeg, []byte("#!/bin/sh\necho bad input >&2\nexit 1\n"), 0755))
+	_, err = transcodeWithFilters(context.Background(), dir, md)
+	assert.EqualError(err, "exit status 1: bad input\n")
+
+	// Custom filters are not supported without the ffmpeg binary
+	FilterFFmpeg = ""
+	_, err = transcodeWithFilters(context.Background(), dir, md)
+	assert.Equal(ErrFiltersUnsupported, err)
+	_, err = NewLocalTranscoder(dir).Transcode(context.Background(), md)
+	assert.Equal(ErrFiltersUnsupported, err)
+}
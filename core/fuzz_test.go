@@ -0,0 +1,33 @@
+//go:build go1.18
+// +build go1.18
+
+package core
+
+import (
+	"fmt"
+	"testing"
+)
+
+func FuzzParseURI(f *testing.F) {
+	f.Add("https://127.0.0.1:8935/stream/abc/12.ts")
+	f.Add("/stream//12.ts")
+	f.Add("/stream/abc/12.ts.bak")
+
+	f.Fuzz(func(t *testing.T, uri string) {
+		mid, seqNo, err := parseURI(uri)
+		if err != nil {
+			if err != errSegmentURI {
+				t.Fatalf("unexpected error type for %q: %v", uri, err)
+			}
+			return
+		}
+		if mid == "" {
+			t.Fatalf("empty manifest ID accepted for %q", uri)
+		}
+		// A valid URI must parse back to the same values
+		mid2, seqNo2, err := parseURI(fmt.Sprintf("/stream/%v/%v.ts", mid, seqNo))
+		if err != nil || mid2 != mid || seqNo2 != seqNo {
+			t.Fatalf("roundtrip failed for %q", uri)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"strings"
+)
+
+// gpuFault is the class of a transcoding error caused by the GPU device rather than by the input segment
+type gpuFault string
+
+const (
+	gpuFaultNone         gpuFault = ""
+	gpuFaultDriverReset  gpuFault = "driver_reset"
+	gpuFaultECC          gpuFault = "ecc"
+	gpuFaultOOM          gpuFault = "oom"
+	gpuFaultSessionLimit gpuFault = "session_limit"
+	gpuFaultWorkerCrash  gpuFault = "worker_crash"
+)
+
+// gpuFaultPatterns maps error message fragments (lower case) reported by the driver, CUDA or NVENC to GPU faults.
+// The order matters because NVENC reports hitting the session limit as running out of memory
+var gpuFaultPatterns = []struct {
+	fault    gpuFault
+	patterns []string
+}{
+	{gpuFaultSessionLimit, []string{"openencodesessionex failed", "incompatible client key", "no capable devices found"}},
+	{gpuFaultECC, []string{"cuda_error_ecc_uncorrectable", "uncorrectable ecc error"}},
+	{gpuFaultDriverReset, []string{
+		"cuda_error_launch_failed", "cuda_error_illegal_address", "cuda_error_device_unavailable",
+		"cuda_error_not_initialized", "cuda_error_deinitialized", "unspecified launch failure",
+		"gpu has fallen off the bus", "cannot init cuda",
+	}},
+	{gpuFaultOOM, []string{"cuda_error_out_of_memory", "out of memory", "cannot allocate memory"}},
+	{gpuFaultWorkerCrash, []string{"transcodeworkercrashed"}},
+}
+
+// classifyGPUError returns the GPU fault that caused err or gpuFaultNone if err was not caused by the GPU
+func classifyGPUError(err error) gpuFault {
+	if err == nil {
+		return gpuFaultNone
+	}
+	msg := strings.ToLower(err.Error())
+	for _, p := range gpuFaultPatterns {
+		for _, pattern := range p.patterns {
+			if strings.Contains(msg, pattern) {
+				return p.fault
+			}
+		}
+	}
+	return gpuFaultNone
+}
+
+// isDeviceFault returns true if the fault indicates that the device is unhealthy. Hitting the session limit only
+// means that the device is at capacity so it does not count towards quarantining the device
+func (f gpuFault) isDeviceFault() bool {
+	return f != gpuFaultNone && f != gpuFaultSessionLimit
+}
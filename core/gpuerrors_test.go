@@ -0,0 +1,37 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyGPUError(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		err         error
+		fault       gpuFault
+		deviceFault bool
+	}{
+		{nil, gpuFaultNone, false},
+		{ErrTranscode, gpuFaultNone, false},
+		{errors.New("Invalid data found when processing input"), gpuFaultNone, false},
+		{errors.New("CUDA_ERROR_LAUNCH_FAILED: unspecified launch failure"), gpuFaultDriverReset, true},
+		{errors.New("GPU has fallen off the bus"), gpuFaultDriverReset, true},
+		{errors.New("CUDA_ERROR_ECC_UNCORRECTABLE: uncorrectable ECC error encountered"), gpuFaultECC, true},
+		{errors.New("CUDA_ERROR_OUT_OF_MEMORY: out of memory"), gpuFaultOOM, true},
+		{errors.New("Cannot allocate memory"), gpuFaultOOM, true},
+		{errors.New("OpenEncodeSessionEx failed: out of memory (10)"), gpuFaultSessionLimit, false},
+		{errors.New("OpenEncodeSessionEx failed: incompatible client key (21)"), gpuFaultSessionLimit, false},
+		{fmt.Errorf("%w: EOF", ErrTranscodeWorkerCrashed), gpuFaultWorkerCrash, true},
+	}
+
+	for _, tt := range tests {
+		fault := classifyGPUError(tt.err)
+		assert.Equal(tt.fault, fault, "%v", tt.err)
+		assert.Equal(tt.deviceFault, fault.isDeviceFault(), "%v", tt.err)
+	}
+}
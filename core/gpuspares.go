@@ -0,0 +1,135 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/lpms/ffmpeg"
+)
+
+// GPUSpareSessions is the number of idle transcode sessions kept warm per device so that new streams do not wait for
+// a session to be created on their first segment. Spares are not kept if 0
+var GPUSpareSessions int
+
+// spareSessions are the warm idle sessions of a device. lpms fixes the number of outputs of a session on its first
+// segment and fails later segments with a different number of outputs, so spares are warmed with the ladder of the
+// stream that last created a session on the device and only claimed by streams with the same ladder
+type spareSessions struct {
+	ladder   []ffmpeg.VideoProfile
+	sessions []TranscoderSession
+}
+
+// warmTranscoderSession transcodes the H264 capability test segment to profiles with sess so that its GPU contexts and
+// outputs are initialized before it is assigned a stream with profiles
+func warmTranscoderSession(sess TranscoderSession, profiles []ffmpeg.VideoProfile) error {
+	capTest := CapabilityTestLookup[Capability_H264]
+	z, err := gzip.NewReader(bytes.NewReader(capTest.inVideoData))
+	if err != nil {
+		return err
+	}
+	seg, err := ioutil.ReadAll(z)
+	z.Close()
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(WorkDir, "warmup_*.tempfile")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(seg)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	md := &SegTranscodingMetadata{Fname: f.Name(), Profiles: profiles}
+	_, err = sess.Transcode(context.Background(), md)
+	return err
+}
+
+// takeSpare returns a spare session of device that was warmed with profiles, or nil if device has none.
+// Expects the mutex `lb.mu` to be locked by the caller.
+func (lb *LoadBalancingTranscoder) takeSpare(device string, profiles []ffmpeg.VideoProfile) TranscoderSession {
+	spares := lb.spares[device]
+	if spares == nil || len(spares.sessions) == 0 || !reflect.DeepEqual(spares.ladder, profiles) {
+		return nil
+	}
+	sess := spares.sessions[len(spares.sessions)-1]
+	spares.sessions = spares.sessions[:len(spares.sessions)-1]
+	return sess
+}
+
+// refillSpares starts refilling the spare sessions of device with sessions warmed with profiles in the background
+// unless they are already being refilled. The spares that were warmed with another ladder are stopped.
+// Expects the mutex `lb.mu` to be locked by the caller.
+func (lb *LoadBalancingTranscoder) refillSpares(device string, profiles []ffmpeg.VideoProfile) {
+	if GPUSpareSessions <= 0 || len(profiles) == 0 {
+		return
+	}
+	if spares := lb.spares[device]; spares == nil || !reflect.DeepEqual(spares.ladder, profiles) {
+		lb.dropSpares(device)
+		lb.spares[device] = &spareSessions{ladder: append([]ffmpeg.VideoProfile{}, profiles...)}
+	}
+	if lb.refilling[device] {
+		return
+	}
+	lb.refilling[device] = true
+	go lb.refill(device)
+}
+
+// refill creates and warms spare sessions of device one at a time until it has GPUSpareSessions of them. Sessions are
+// created outside of the lock since creating and warming a session takes a while
+func (lb *LoadBalancingTranscoder) refill(device string) {
+	for {
+		lb.mu.Lock()
+		spares := lb.spares[device]
+		if lb.isQuarantined(device) || spares == nil || len(spares.sessions) >= GPUSpareSessions {
+			lb.refilling[device] = false
+			lb.mu.Unlock()
+			return
+		}
+		ladder := spares.ladder
+		lb.mu.Unlock()
+
+		sess := lb.newT(device)
+		if err := lb.warm(sess, ladder); err != nil {
+			glog.Errorf("LB: Could not warm spare transcode session device=%s err=%q", device, err)
+			sess.Stop()
+			lb.mu.Lock()
+			lb.refilling[device] = false
+			lb.mu.Unlock()
+			return
+		}
+
+		lb.mu.Lock()
+		// The spares are warmed with another ladder now if a stream with another ladder was created meanwhile
+		if spares := lb.spares[device]; lb.isQuarantined(device) || spares == nil || !reflect.DeepEqual(spares.ladder, ladder) {
+			lb.mu.Unlock()
+			sess.Stop()
+			continue
+		}
+		lb.spares[device].sessions = append(lb.spares[device].sessions, sess)
+		lb.mu.Unlock()
+	}
+}
+
+// dropSpares stops the spare sessions of device.
+// Expects the mutex `lb.mu` to be locked by the caller.
+func (lb *LoadBalancingTranscoder) dropSpares(device string) int {
+	spares := lb.spares[device]
+	if spares == nil {
+		return 0
+	}
+	for _, sess := range spares.sessions {
+		sess.Stop()
+	}
+	delete(lb.spares, device)
+	return len(spares.sessions)
+}
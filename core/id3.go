@@ -0,0 +1,71 @@
+package core
+
+import (
+	"time"
+)
+
+// Metadata carried in PES packets, i.e. ID3 timed metadata
+const id3StreamType = 0x15
+
+// ID3Tag is an ID3 tag of the timed metadata of a segment
+type ID3Tag struct {
+	// Offset of the presentation time of the tag from the start of the segment
+	Offset time.Duration
+	Data   []byte
+}
+
+// TimedMetadata is the ID3 timed metadata stream of an MPEG-TS segment. The transcoder drops data streams, so the
+// packets of the stream are muxed back into the transcoded segments
+type TimedMetadata struct {
+	Tags []ID3Tag
+
+	dataStream
+}
+
+// ParseTimedMetadata returns the ID3 timed metadata of the MPEG-TS segment data or nil if the segment has none. Only
+// the first timed metadata stream of the segment is returned
+func ParseTimedMetadata(data []byte) *TimedMetadata {
+	var m *TimedMetadata
+	var tagPTS []uint64
+	var start uint64
+	hasStart := false
+
+	for _, u := range demuxTS(data) {
+		pts, hasPTS := pesPTS(u.payload)
+		if u.stream.streamType != id3StreamType {
+			// The segment starts with the earliest sample of the other streams
+			if hasPTS && (!hasStart || pts < start) {
+				start, hasStart = pts, true
+			}
+			continue
+		}
+		if m == nil {
+			m = &TimedMetadata{dataStream: dataStream{streamType: id3StreamType, pid: u.stream.pid, esInfo: u.stream.esInfo}}
+		}
+		if u.stream.pid != m.pid {
+			continue
+		}
+		m.packets = append(m.packets, u.packets...)
+		m.Tags = append(m.Tags, ID3Tag{Data: pesPayload(u.payload)})
+		tagPTS = append(tagPTS, pts)
+	}
+
+	if m != nil && hasStart {
+		for i, pts := range tagPTS {
+			if pts > start {
+				m.Tags[i].Offset = time.Duration(pts-start) * time.Second / 90000
+			}
+		}
+	}
+	return m
+}
+
+// Mux returns the MPEG-TS segment data with the timed metadata stream added to its PMT and the packets of the stream
+// inserted after the PMT. The data is returned unchanged if it is not MPEG-TS, already has a timed metadata stream or
+// its PMT does not fit into a single packet
+func (m *TimedMetadata) Mux(data []byte) []byte {
+	if m == nil {
+		return data
+	}
+	return m.mux(data)
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testID3PID = 0x102
+
+// metadata_descriptor of an ID3 stream
+var testID3Descriptor = []byte{0x26, 0x0D, 0xFF, 0xFF, 'I', 'D', '3', ' ', 0xFF, 'I', 'D', '3', ' ', 0x00, 0x0F}
+
+func pmtEntry(streamType byte, pid uint16, esInfo []byte) []byte {
+	e := []byte{streamType, 0xE0 | byte(pid>>8), byte(pid), 0xF0 | byte(len(esInfo)>>8), byte(len(esInfo))}
+	return append(e, esInfo...)
+}
+
+func pmtSection(entries ...[]byte) []byte {
+	s := []byte{pmtTableID, 0xB0, 0, 0x00, 0x01, 0xC1, 0x00, 0x00, 0xE0 | testVideoPID>>8, testVideoPID & 0xFF, 0xF0, 0x00}
+	for _, e := range entries {
+		s = append(s, e...)
+	}
+	s[2] = byte(len(s) + 4 - 3)
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32MPEG2(s))
+	return append(s, crc...)
+}
+
+func pesPacket(streamID byte, pts uint64, data []byte) []byte {
+	pes := []byte{0, 0, 1, streamID, 0, 0, 0x80, 0x80, 5,
+		0x21 | byte(pts>>29&0x0E), byte(pts >> 22), 0x01 | byte(pts>>14&0xFE), byte(pts >> 7), 0x01 | byte(pts<<1)}
+	pes = append(pes, data...)
+	binary.BigEndian.PutUint16(pes[4:], uint16(len(pes)-6))
+	return pes
+}
+
+// pesTSPacket returns a packet that starts a PES packet, which unlike a PSI section has no pointer field
+func pesTSPacket(pid uint16, pes []byte) []byte {
+	pkt := tsPacket(pid, false, pes)
+	pkt[1] |= 0x40
+	return pkt
+}
+
+func id3Segment(videoPID uint16, entries ...[]byte) []byte {
+	var data []byte
+	data = append(data, tsPacket(0, true, testPAT())...)
+	data = append(data, tsPacket(testPMTPID, true, pmtSection(entries...))...)
+	data = append(data, pesTSPacket(videoPID, pesPacket(0xE0, 900000, []byte{0, 0, 0, 1, 0x09}))...)
+	return data
+}
+
+func TestCRC32MPEG2(t *testing.T) {
+	assert.Equal(t, uint32(0x0376E6E7), crc32MPEG2([]byte("123456789")))
+}
+
+func TestParseTimedMetadata(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	assert.Nil(ParseTimedMetadata(nil))
+	assert.Nil(ParseTimedMetadata(id3Segment(testVideoPID, pmtEntry(0x1B, testVideoPID, nil))))
+
+	data := id3Segment(testVideoPID, pmtEntry(0x1B, testVideoPID, nil), pmtEntry(id3StreamType, testID3PID, testID3Descriptor))
+	data = append(data, pesTSPacket(testID3PID, pesPacket(0xBD, 900000+45000, []byte("ID3tag1")))...)
+	data = append(data, pesTSPacket(testID3PID, pesPacket(0xBD, 900000+90000, []byte("ID3tag2")))...)
+
+	tm := ParseTimedMetadata(data)
+	require.NotNil(tm)
+	assert.Equal([]ID3Tag{
+		{Offset: 500 * time.Millisecond, Data: []byte("ID3tag1")},
+		{Offset: time.Second, Data: []byte("ID3tag2")},
+	}, tm.Tags)
+	assert.Equal(uint16(testID3PID), tm.pid)
+	assert.Equal(testID3Descriptor, tm.esInfo)
+	assert.Len(tm.packets, 2)
+}
+
+func TestTimedMetadata_Mux(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	source := id3Segment(testVideoPID, pmtEntry(0x1B, testVideoPID, nil), pmtEntry(id3StreamType, testID3PID, testID3Descriptor))
+	source = append(source, pesTSPacket(testID3PID, pesPacket(0xBD, 900000+45000, []byte("ID3tag")))...)
+	tm := ParseTimedMetadata(source)
+	require.NotNil(tm)
+
+	// The metadata stream is added to the PMT and its packets follow the PMT
+	rendition := id3Segment(testVideoPID, pmtEntry(0x1B, testVideoPID, nil))
+	muxed := tm.Mux(rendition)
+	require.Len(muxed, len(rendition)+tsPacketSize)
+	assert.Equal(uint16(testID3PID), tsPID(muxed[2*tsPacketSize:]))
+	pmt := psiSection(tsPayload(muxed[tsPacketSize : 2*tsPacketSize]))
+	pmt = pmt[:psiSectionLen(pmt)]
+	// The CRC of a section with a valid CRC is 0
+	assert.Equal(uint32(0), crc32MPEG2(pmt))
+	streams := parsePMT(pmt)
+	require.Len(streams, 2)
+	assert.Equal(&tsStream{pid: testID3PID, streamType: id3StreamType, esInfo: testID3Descriptor}, streams[1])
+	remuxed := ParseTimedMetadata(muxed)
+	require.NotNil(remuxed)
+	assert.Equal(tm.Tags, remuxed.Tags)
+
+	// The metadata stream gets another PID if its PID is used
+	rendition = id3Segment(testID3PID, pmtEntry(0x1B, testID3PID, nil))
+	muxed = tm.Mux(rendition)
+	remuxed = ParseTimedMetadata(muxed)
+	require.NotNil(remuxed)
+	assert.Equal(uint16(testID3PID+1), remuxed.pid)
+	assert.Equal(tm.Tags, remuxed.Tags)
+
+	// Segments that already have a metadata stream are not changed
+	assert.Equal(source, tm.Mux(source))
+	// Other formats are not changed
+	assert.Equal([]byte("mp4"), tm.Mux([]byte("mp4")))
+	var none *TimedMetadata
+	assert.Equal(rendition, none.Mux(rendition))
+}
@@ -0,0 +1,241 @@
+package core
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/clog"
+	"github.com/livepeer/lpms/ffmpeg"
+)
+
+var ErrTranscodeWorkerCrashed = errors.New("TranscodeWorkerCrashed")
+
+// TranscodeWorkerCmd returns the command that runs a transcode worker for a GPU device with RunTranscodeWorker. The
+// node sets it to re-execute its own binary in worker mode when GPU process isolation is enabled
+var TranscodeWorkerCmd func(device string) *exec.Cmd
+
+// The worker reads requests from fd 3 and writes results to fd 4 so that anything the ffmpeg/CUDA layer prints to
+// stdout does not corrupt the results
+const (
+	workerRequestsFd = 3
+	workerResultsFd  = 4
+)
+
+// workerStopTimeout is how long a stopped worker has to stop its transcode session and exit before it is killed
+const workerStopTimeout = 10 * time.Second
+
+type workerRequest struct {
+	Fname              string
+	Profiles           []ffmpeg.VideoProfile
+	CalcPerceptualHash bool
+}
+
+type workerResult struct {
+	Data *TranscodeData
+	Err  string
+}
+
+// RunTranscodeWorker transcodes the segments requested by the parent node with a transcode session for device until
+// the parent closes the requests pipe. It runs in the child process of an IsolatedTranscoder
+func RunTranscodeWorker(device string, newT newTranscoderFn) error {
+	requests := os.NewFile(workerRequestsFd, "requests")
+	results := os.NewFile(workerResultsFd, "results")
+	if requests == nil || results == nil {
+		return errors.New("transcode worker must be started by the node")
+	}
+	return runTranscodeWorker(newT(device), requests, results)
+}
+
+func runTranscodeWorker(sess TranscoderSession, r io.Reader, w io.Writer) error {
+	defer sess.Stop()
+
+	dec := gob.NewDecoder(r)
+	enc := gob.NewEncoder(w)
+	for {
+		var req workerRequest
+		if err := dec.Decode(&req); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		md := &SegTranscodingMetadata{Fname: req.Fname, Profiles: req.Profiles, CalcPerceptualHash: req.CalcPerceptualHash}
+		td, err := sess.Transcode(context.Background(), md)
+		res := workerResult{Data: td}
+		if err != nil {
+			res.Err = err.Error()
+		}
+		if err := enc.Encode(&res); err != nil {
+			return err
+		}
+	}
+}
+
+// IsolatedTranscoder is a transcode session that runs in a child process of the node, so that a crash of the
+// ffmpeg/CUDA layer kills only the child instead of the whole node. A crashed child is respawned and the segment it
+// was transcoding is retried once in the new child
+type IsolatedTranscoder struct {
+	device string
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	exited  chan struct{}
+	enc     *gob.Encoder
+	dec     *gob.Decoder
+	pipes   []*os.File
+	stopped bool
+}
+
+func NewIsolatedTranscoder(device string) TranscoderSession {
+	return &IsolatedTranscoder{device: device}
+}
+
+func (it *IsolatedTranscoder) Transcode(ctx context.Context, md *SegTranscodingMetadata) (*TranscodeData, error) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	if it.stopped {
+		return nil, ErrTranscoderStopped
+	}
+
+	req := &workerRequest{Fname: md.Fname, Profiles: md.Profiles, CalcPerceptualHash: md.CalcPerceptualHash}
+	var res *workerResult
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		res, err = it.roundTrip(ctx, req)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		clog.Errorf(ctx, "Transcode worker crashed device=%s attempt=%d err=%q", it.device, attempt, err)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTranscodeWorkerCrashed, err)
+	}
+	if res.Err != "" {
+		return nil, errors.New(res.Err)
+	}
+	return res.Data, nil
+}
+
+// roundTrip sends req to the worker, starting one if there is none, and waits for its result. The worker is killed if
+// ctx is done first or if it does not respond. Expects the mutex `it.mu` to be locked by the caller
+func (it *IsolatedTranscoder) roundTrip(ctx context.Context, req *workerRequest) (*workerResult, error) {
+	if it.cmd == nil {
+		if err := it.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	done := make(chan error, 1)
+	var res workerResult
+	go func() {
+		if err := it.enc.Encode(req); err != nil {
+			done <- err
+			return
+		}
+		done <- it.dec.Decode(&res)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			it.kill()
+			return nil, err
+		}
+		return &res, nil
+	case <-ctx.Done():
+		it.kill()
+		<-done
+		return nil, ctx.Err()
+	}
+}
+
+// start spawns a worker. Expects the mutex `it.mu` to be locked by the caller
+func (it *IsolatedTranscoder) start() error {
+	if TranscodeWorkerCmd == nil {
+		return errors.New("transcode worker command is not set")
+	}
+	reqR, reqW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	resR, resW, err := os.Pipe()
+	if err != nil {
+		reqR.Close()
+		reqW.Close()
+		return err
+	}
+
+	cmd := TranscodeWorkerCmd(it.device)
+	// The pipes are passed as fds 3 and 4 of the worker
+	cmd.ExtraFiles = []*os.File{reqR, resW}
+	if err := cmd.Start(); err != nil {
+		for _, f := range []*os.File{reqR, reqW, resR, resW} {
+			f.Close()
+		}
+		return err
+	}
+	// The worker holds its own copies of its ends of the pipes, so that the results pipe reaches EOF when it exits
+	reqR.Close()
+	resW.Close()
+
+	exited := make(chan struct{})
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			glog.Errorf("Transcode worker exited device=%s pid=%d err=%q", it.device, cmd.Process.Pid, err)
+		}
+		close(exited)
+	}()
+
+	it.cmd = cmd
+	it.exited = exited
+	it.enc = gob.NewEncoder(reqW)
+	it.dec = gob.NewDecoder(resR)
+	it.pipes = []*os.File{reqW, resR}
+	return nil
+}
+
+// kill stops the worker and waits for it to exit. Expects the mutex `it.mu` to be locked by the caller
+func (it *IsolatedTranscoder) kill() {
+	if it.cmd == nil {
+		return
+	}
+	it.cmd.Process.Kill()
+	<-it.exited
+	for _, f := range it.pipes {
+		f.Close()
+	}
+	it.cmd = nil
+}
+
+// Stop closes the requests pipe of the worker so that it stops its transcode session and exits
+func (it *IsolatedTranscoder) Stop() {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	it.stopped = true
+	if it.cmd == nil {
+		return
+	}
+	it.pipes[0].Close()
+	select {
+	case <-it.exited:
+	case <-time.After(workerStopTimeout):
+		glog.Errorf("Killing transcode worker that did not exit device=%s pid=%d", it.device, it.cmd.Process.Pid)
+		it.cmd.Process.Kill()
+		<-it.exited
+	}
+	it.pipes[1].Close()
+	it.cmd = nil
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIsolatedTranscoder_Worker is not a test but the transcode worker that the tests spawn by re-executing the test
+// binary. The worker crashes on segments named crash and on segments named crash_once while the marker file exists
+func TestIsolatedTranscoder_Worker(t *testing.T) {
+	if os.Getenv("LP_TEST_TRANSCODE_WORKER") == "" {
+		return
+	}
+	sess := &workerTestTranscoder{TranscoderSession: &StubTranscoder{Profiles: []ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9}}}
+	err := runTranscodeWorker(sess, os.NewFile(workerRequestsFd, "requests"), os.NewFile(workerResultsFd, "results"))
+	if err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+type workerTestTranscoder struct {
+	TranscoderSession
+}
+
+func (w *workerTestTranscoder) Transcode(ctx context.Context, md *SegTranscodingMetadata) (*TranscodeData, error) {
+	switch filepath.Base(md.Fname) {
+	case "crash":
+		os.Exit(2)
+	case "crash_once":
+		if err := os.Remove(md.Fname); err == nil {
+			os.Exit(2)
+		}
+	case "hang":
+		time.Sleep(time.Minute)
+	case "fail":
+		return nil, errors.New("CUDA_ERROR_OUT_OF_MEMORY: out of memory")
+	}
+	return w.TranscoderSession.Transcode(ctx, md)
+}
+
+func TestIsolatedTranscoder(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	oldCmd := TranscodeWorkerCmd
+	defer func() { TranscodeWorkerCmd = oldCmd }()
+	spawned := 0
+	TranscodeWorkerCmd = func(device string) *exec.Cmd {
+		spawned++
+		cmd := exec.Command(os.Args[0], "-test.run=^TestIsolatedTranscoder_Worker$")
+		cmd.Env = append(os.Environ(), "LP_TEST_TRANSCODE_WORKER=1")
+		return cmd
+	}
+	dir := t.TempDir()
+	md := func(fname string) *SegTranscodingMetadata {
+		return &SegTranscodingMetadata{Fname: filepath.Join(dir, fname), Profiles: []ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9}}
+	}
+
+	it := NewIsolatedTranscoder("0")
+	td, err := it.Transcode(context.Background(), md("seg1"))
+	require.Nil(err)
+	assert.Len(td.Segments, 1)
+	_, err = it.Transcode(context.Background(), md("seg2"))
+	require.Nil(err)
+	assert.Equal(1, spawned)
+
+	// Transcoding errors are returned as they are
+	_, err = it.Transcode(context.Background(), md("fail"))
+	assert.EqualError(err, "CUDA_ERROR_OUT_OF_MEMORY: out of memory")
+	assert.Equal(1, spawned)
+
+	// A crashed worker is respawned and the segment is retried
+	require.Nil(os.WriteFile(filepath.Join(dir, "crash_once"), nil, 0644))
+	_, err = it.Transcode(context.Background(), md("crash_once"))
+	assert.Nil(err)
+	assert.Equal(2, spawned)
+
+	// Segments that crash the worker again fail with a device fault
+	_, err = it.Transcode(context.Background(), md("crash"))
+	assert.True(errors.Is(err, ErrTranscodeWorkerCrashed))
+	assert.Equal(gpuFaultWorkerCrash, classifyGPUError(err))
+	assert.Equal(3, spawned)
+
+	// A worker that does not respond before the deadline is killed
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, err = it.Transcode(ctx, md("hang"))
+	assert.Equal(context.DeadlineExceeded, err)
+	_, err = it.Transcode(context.Background(), md("seg3"))
+	assert.Nil(err)
+	assert.Equal(5, spawned)
+
+	it.Stop()
+	_, err = it.Transcode(context.Background(), md("seg4"))
+	assert.Equal(ErrTranscoderStopped, err)
+}
@@ -5,14 +5,25 @@ import (
 	"errors"
 	"math"
 	"sync"
+	"time"
 
+	"github.com/golang/glog"
 	"github.com/livepeer/go-livepeer/clog"
 	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/lperrors"
+	"github.com/livepeer/go-livepeer/monitor"
 	"github.com/livepeer/lpms/ffmpeg"
 )
 
-var ErrTranscoderBusy = errors.New("TranscoderBusy")
+var ErrTranscoderBusy = lperrors.Retryable(errors.New("TranscoderBusy"))
 var ErrTranscoderStopped = errors.New("TranscoderStopped")
+var ErrNoHealthyTranscoders = lperrors.Retryable(errors.New("NoHealthyTranscoders"))
+
+// GPUQuarantineThreshold is the number of consecutive device faults after which a device is quarantined
+var GPUQuarantineThreshold = 3
+
+// GPUQuarantineDuration is how long a quarantined device does not receive new sessions
+var GPUQuarantineDuration = 10 * time.Minute
 
 // This is for temporary convenience - as we currently
 // only support loading a single detection model.
@@ -33,25 +44,43 @@ type LoadBalancingTranscoder struct {
 	detectorModel string
 
 	// The following fields need to be protected by the mutex `mu`
-	mu       *sync.RWMutex
-	load     map[string]int
-	sessions map[string]*transcoderSession
-	idx      int // Ensures a non-tapered work distribution
+	mu        *sync.RWMutex
+	load      map[string]int
+	sessions  map[string]*transcoderSession
+	idx       int // Ensures a non-tapered work distribution
+	health    map[string]*deviceHealth
+	spares    map[string]*spareSessions // Warm idle sessions per device
+	refilling map[string]bool
+	warm      func(TranscoderSession, []ffmpeg.VideoProfile) error
+}
+
+type deviceHealth struct {
+	faults           int // consecutive device faults
+	quarantinedUntil time.Time
 }
 
 func NewLoadBalancingTranscoder(devices []string, newTranscoderFn newTranscoderFn,
 	newTranscoderWithDetectorFn newTranscoderWithDetectorFn) Transcoder {
-	return &LoadBalancingTranscoder{
+	lb := &LoadBalancingTranscoder{
 		transcoders:  devices,
 		newT:         newTranscoderFn,
 		newDetectorT: newTranscoderWithDetectorFn,
 		mu:           &sync.RWMutex{},
 		load:         make(map[string]int),
 		sessions:     make(map[string]*transcoderSession),
+		health:       make(map[string]*deviceHealth),
+		spares:       make(map[string]*spareSessions),
+		refilling:    make(map[string]bool),
+		warm:         warmTranscoderSession,
 	}
+	return lb
 }
 
 func (lb *LoadBalancingTranscoder) Transcode(ctx context.Context, md *SegTranscodingMetadata) (*TranscodeData, error) {
+	// Custom filters are applied by the FilterFFmpeg binary instead of a GPU session
+	if len(md.Filters) > 0 {
+		return transcodeWithFilters(ctx, WorkDir, md)
+	}
 
 	lb.mu.RLock()
 	session, exists := lb.sessions[string(md.AuthToken.SessionId)]
@@ -68,7 +97,73 @@ func (lb *LoadBalancingTranscoder) Transcode(ctx context.Context, md *SegTransco
 			return nil, err
 		}
 	}
-	return session.Transcode(ctx, md)
+	res, err := session.Transcode(ctx, md)
+	lb.recordResult(ctx, session.device, err)
+	return res, err
+}
+
+// recordResult updates the health of device after a transcode. A device that fails with GPUQuarantineThreshold
+// consecutive device faults is quarantined
+func (lb *LoadBalancingTranscoder) recordResult(ctx context.Context, device string, err error) {
+	fault := classifyGPUError(err)
+	if fault != gpuFaultNone {
+		clog.Errorf(ctx, "LB: GPU fault device=%s fault=%s err=%q", device, fault, err)
+		if monitor.Enabled {
+			monitor.GPUError(device, string(fault))
+		}
+	}
+	// Errors not caused by the device i.e. invalid input do not affect its health
+	if err != nil && !fault.isDeviceFault() {
+		return
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	h, ok := lb.health[device]
+	if !ok {
+		h = &deviceHealth{}
+		lb.health[device] = h
+	}
+	if err == nil {
+		h.faults = 0
+		return
+	}
+	h.faults++
+	if h.faults >= GPUQuarantineThreshold {
+		lb.quarantine(device)
+	}
+}
+
+// quarantine stops assigning new sessions to device for GPUQuarantineDuration and stops its existing sessions so
+// that subsequent segments of these sessions are rebalanced onto healthy devices.
+// Expects the mutex `lb.mu` to be locked by the caller.
+func (lb *LoadBalancingTranscoder) quarantine(device string) {
+	h := lb.health[device]
+	h.faults = 0
+	h.quarantinedUntil = time.Now().Add(GPUQuarantineDuration)
+
+	stopped := 0
+	for job, sess := range lb.sessions {
+		if sess.device != device {
+			continue
+		}
+		delete(lb.sessions, job)
+		lb.load[device] -= sess.cost
+		close(sess.quit)
+		stopped++
+	}
+	stopped += lb.dropSpares(device)
+
+	glog.Errorf("LB: Quarantined GPU after repeated faults device=%s until=%v stoppedSessions=%d", device, h.quarantinedUntil, stopped)
+	if monitor.Enabled {
+		monitor.GPUQuarantined(device)
+	}
+}
+
+func (lb *LoadBalancingTranscoder) isQuarantined(device string) bool {
+	h, ok := lb.health[device]
+	return ok && time.Now().Before(h.quarantinedUntil)
 }
 
 func (lb *LoadBalancingTranscoder) createSession(ctx context.Context, md *SegTranscodingMetadata) (*transcoderSession, error) {
@@ -84,6 +179,9 @@ func (lb *LoadBalancingTranscoder) createSession(ctx context.Context, md *SegTra
 
 	clog.V(common.DEBUG).Infof(ctx, "LB: Creating transcode session for job=%s", job)
 	transcoder := lb.leastLoaded()
+	if transcoder == "" {
+		return nil, ErrNoHealthyTranscoders
+	}
 
 	// Acquire transcode session. Map to job id + assigned transcoder
 	key := job + "_" + transcoder
@@ -96,12 +194,21 @@ func (lb *LoadBalancingTranscoder) createSession(ctx context.Context, md *SegTra
 			return nil, err
 		}
 	} else {
-		lpmsSession = lb.newT(transcoder)
+		// Claim a spare that was warmed with the ladder of the stream if there is one and refill the spares with
+		// sessions warmed with the ladder in the background
+		lpmsSession = lb.takeSpare(transcoder, md.Profiles)
+		if lpmsSession == nil {
+			lpmsSession = lb.newT(transcoder)
+		}
+		lb.refillSpares(transcoder, md.Profiles)
 	}
 	session := &transcoderSession{
 		transcoder:  lpmsSession,
 		key:         key,
+		device:      transcoder,
+		cost:        costEstimate,
 		done:        make(chan struct{}),
+		quit:        make(chan struct{}),
 		sender:      make(chan *transcoderParams, maxSegmentChannels),
 		makeContext: transcodeLoopContext,
 	}
@@ -113,8 +220,8 @@ func (lb *LoadBalancingTranscoder) createSession(ctx context.Context, md *SegTra
 	cleanupSession := func() {
 		lb.mu.Lock()
 		defer lb.mu.Unlock()
-		_, exists := lb.sessions[job]
-		if !exists {
+		// The session might have already been removed if its device was quarantined
+		if s, exists := lb.sessions[job]; !exists || s != session {
 			return
 		}
 		delete(lb.sessions, job)
@@ -131,17 +238,24 @@ func (lb *LoadBalancingTranscoder) createSession(ctx context.Context, md *SegTra
 	return session, nil
 }
 
-// Find the lowest loaded transcoder.
+// Find the lowest loaded transcoder that is not quarantined.
+// Returns an empty string if all transcoders are quarantined.
 // Expects the mutex `lb.mu` to be locked by the caller.
 func (lb *LoadBalancingTranscoder) leastLoaded() string {
-	min, idx := math.MaxInt64, 0
+	min, idx := math.MaxInt64, -1
 	for i := 0; i < len(lb.transcoders); i++ {
 		k := (i + lb.idx) % len(lb.transcoders)
+		if lb.isQuarantined(lb.transcoders[k]) {
+			continue
+		}
 		if lb.load[lb.transcoders[k]] < min {
 			min = lb.load[lb.transcoders[k]]
 			idx = k
 		}
 	}
+	if idx < 0 {
+		return ""
+	}
 	return lb.transcoders[idx]
 }
 
@@ -157,9 +271,12 @@ type transcoderParams struct {
 type transcoderSession struct {
 	transcoder TranscoderSession
 	key        string
+	device     string
+	cost       int
 
 	sender      chan *transcoderParams
 	done        chan struct{}
+	quit        chan struct{} // closed to stop the session i.e. when its device is quarantined
 	makeContext func() (context.Context, context.CancelFunc)
 }
 
@@ -180,6 +297,10 @@ func (sess *transcoderSession) loop(logCtx context.Context) {
 			// Terminate the session after a period of inactivity
 			clog.V(common.DEBUG).Infof(logCtx, "LB: Transcode loop timed out for key=%s", sess.key)
 			return
+		case <-sess.quit:
+			cancel()
+			clog.V(common.DEBUG).Infof(logCtx, "LB: Transcode loop stopped for key=%s", sess.key)
+			return
 		case params := <-sess.sender:
 			cancel()
 			res, err :=
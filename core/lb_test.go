@@ -2,7 +2,9 @@ package core
 
 import (
 	"context"
+	"errors"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -399,3 +401,185 @@ func (m *lbMachine) Check(t *rapid.T) {
 func TestLB_Machine(t *testing.T) {
 	rapid.Check(t, rapid.Run(&lbMachine{}))
 }
+
+func TestLB_Quarantine(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	oldThreshold := GPUQuarantineThreshold
+	defer func() { GPUQuarantineThreshold = oldThreshold }()
+	GPUQuarantineThreshold = 2
+
+	var failing int32
+	newT := func(d string) TranscoderSession {
+		return &StubTranscoder{TranscodeFn: func() error {
+			if d == "0" && atomic.LoadInt32(&failing) == 1 {
+				return errors.New("CUDA_ERROR_LAUNCH_FAILED: unspecified launch failure")
+			}
+			return nil
+		}}
+	}
+	lb := NewLoadBalancingTranscoder([]string{"0", "1"}, newT, newStubTranscoderWithDetector).(*LoadBalancingTranscoder)
+
+	// Sessions are spread across both devices
+	for _, sess := range []string{"a", "b", "c", "d", "e"} {
+		_, err := lb.Transcode(context.TODO(), stubMetadata(sess, ffmpeg.P144p30fps16x9))
+		require.Nil(err)
+	}
+	assert.Equal("0", lb.sessions["a"].device)
+	assert.Equal("0", lb.sessions["c"].device)
+	assert.Equal("0", lb.sessions["e"].device)
+
+	// Errors that are not caused by the device do not count towards quarantine
+	lb.recordResult(context.TODO(), "0", ErrTranscode)
+	lb.recordResult(context.TODO(), "0", ErrTranscoderBusy)
+	assert.Zero(lb.health["0"].faults)
+
+	// The device is quarantined after repeated faults
+	atomic.StoreInt32(&failing, 1)
+	_, err := lb.Transcode(context.TODO(), stubMetadata("a", ffmpeg.P144p30fps16x9))
+	assert.NotNil(err)
+	assert.False(lb.isQuarantined("0"))
+	_, err = lb.Transcode(context.TODO(), stubMetadata("c", ffmpeg.P144p30fps16x9))
+	assert.NotNil(err)
+	assert.True(lb.isQuarantined("0"))
+
+	// Sessions on the quarantined device are rebalanced onto healthy devices
+	lb.mu.RLock()
+	_, exists := lb.sessions["e"]
+	lb.mu.RUnlock()
+	assert.False(exists)
+	for _, sess := range []string{"a", "c", "e", "f"} {
+		_, err := lb.Transcode(context.TODO(), stubMetadata(sess, ffmpeg.P144p30fps16x9))
+		require.Nil(err)
+		assert.Equal("1", lb.sessions[sess].device)
+	}
+	time.Sleep(10 * time.Millisecond)
+	lb.mu.RLock()
+	assert.Zero(lb.load["0"])
+	lb.mu.RUnlock()
+
+	// No sessions are created if all devices are quarantined
+	lb.mu.Lock()
+	lb.health["1"] = &deviceHealth{quarantinedUntil: time.Now().Add(time.Minute)}
+	lb.mu.Unlock()
+	_, err = lb.Transcode(context.TODO(), stubMetadata("g", ffmpeg.P144p30fps16x9))
+	assert.Equal(ErrNoHealthyTranscoders, err)
+
+	// The device receives new sessions once the quarantine expires
+	atomic.StoreInt32(&failing, 0)
+	lb.mu.Lock()
+	lb.health["0"].quarantinedUntil = time.Now()
+	lb.mu.Unlock()
+	_, err = lb.Transcode(context.TODO(), stubMetadata("g", ffmpeg.P144p30fps16x9))
+	assert.Nil(err)
+	assert.Equal("0", lb.sessions["g"].device)
+}
+
+// outputsTranscoder fails segments with a different number of outputs than the first segment of the session, like
+// lpms does with lpms_ERR_OUTPUTS
+type outputsTranscoder struct {
+	StubTranscoder
+	outputs int
+}
+
+func (t *outputsTranscoder) Transcode(ctx context.Context, md *SegTranscodingMetadata) (*TranscodeData, error) {
+	if t.outputs == 0 {
+		t.outputs = len(md.Profiles)
+	} else if t.outputs != len(md.Profiles) {
+		return nil, errors.New("lpms_ERR_OUTPUTS")
+	}
+	return t.StubTranscoder.Transcode(ctx, md)
+}
+
+func TestLB_SpareSessions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	oldSpares, oldWorkDir := GPUSpareSessions, WorkDir
+	defer func() { GPUSpareSessions, WorkDir = oldSpares, oldWorkDir }()
+	GPUSpareSessions = 2
+	WorkDir = t.TempDir()
+
+	var created int32
+	newT := func(d string) TranscoderSession {
+		atomic.AddInt32(&created, 1)
+		return &outputsTranscoder{}
+	}
+	lb := NewLoadBalancingTranscoder([]string{"0"}, newT, newStubTranscoderWithDetector).(*LoadBalancingTranscoder)
+	spares := func(device string) []TranscoderSession {
+		lb.mu.RLock()
+		defer lb.mu.RUnlock()
+		if lb.spares[device] == nil {
+			return nil
+		}
+		return append([]TranscoderSession{}, lb.spares[device].sessions...)
+	}
+	ladder := []ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9, ffmpeg.P240p30fps16x9}
+
+	// No spares are kept before the ladder of the streams is known
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(spares("0"))
+	assert.Zero(atomic.LoadInt32(&created))
+
+	// The first stream creates a session, and spares are warmed with its ladder
+	_, err := lb.Transcode(context.TODO(), stubMetadata("a", ladder...))
+	require.Nil(err)
+	assert.Eventually(func() bool { return len(spares("0")) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(int32(3), atomic.LoadInt32(&created))
+	for _, sess := range spares("0") {
+		assert.Equal(1, sess.(*outputsTranscoder).SegCount)
+		assert.Equal(len(ladder), sess.(*outputsTranscoder).outputs)
+	}
+
+	// A spare warmed with a different number of outputs cannot transcode the segments of the stream
+	_, err = spares("0")[0].Transcode(context.TODO(), stubMetadata("", ffmpeg.P144p30fps16x9))
+	assert.EqualError(err, "lpms_ERR_OUTPUTS")
+
+	// Streams with the same ladder claim a spare which is refilled in the background
+	warm := spares("0")
+	_, err = lb.Transcode(context.TODO(), stubMetadata("b", ladder...))
+	require.Nil(err)
+	lb.mu.RLock()
+	assert.Equal(warm[1], lb.sessions["b"].transcoder)
+	lb.mu.RUnlock()
+	assert.Eventually(func() bool { return atomic.LoadInt32(&created) == 4 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(func() bool { return len(spares("0")) == 2 }, time.Second, 10*time.Millisecond)
+
+	// Streams with another ladder do not claim the spares, which are rewarmed with the new ladder
+	warm = spares("0")
+	_, err = lb.Transcode(context.TODO(), stubMetadata("c", ffmpeg.P144p30fps16x9))
+	require.Nil(err)
+	lb.mu.RLock()
+	assert.NotContains(warm, lb.sessions["c"].transcoder)
+	lb.mu.RUnlock()
+	for _, sess := range warm {
+		assert.Equal(1, sess.(*outputsTranscoder).StoppedCount)
+	}
+	assert.Eventually(func() bool { return len(spares("0")) == 2 }, time.Second, 10*time.Millisecond)
+	for _, sess := range spares("0") {
+		assert.Equal(1, sess.(*outputsTranscoder).outputs)
+	}
+
+	// Spares of a quarantined device are stopped and not refilled
+	warm = spares("0")
+	before := atomic.LoadInt32(&created)
+	lb.mu.Lock()
+	lb.health["0"] = &deviceHealth{}
+	lb.quarantine("0")
+	lb.mu.Unlock()
+	assert.Empty(spares("0"))
+	for _, sess := range warm {
+		assert.Equal(1, sess.(*outputsTranscoder).StoppedCount)
+	}
+	lb.mu.Lock()
+	lb.refillSpares("0", ladder)
+	lb.mu.Unlock()
+	assert.Eventually(func() bool {
+		lb.mu.RLock()
+		defer lb.mu.RUnlock()
+		return !lb.refilling["0"]
+	}, time.Second, 10*time.Millisecond)
+	assert.Empty(spares("0"))
+	assert.Equal(before, atomic.LoadInt32(&created))
+}
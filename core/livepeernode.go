@@ -22,6 +22,7 @@ import (
 
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/policy"
 )
 
 var ErrTranscoderAvail = errors.New("ErrTranscoderUnavailable")
@@ -75,11 +76,20 @@ type LivepeerNode struct {
 	Recipient         pm.Recipient
 	OrchestratorPool  common.OrchestratorPool
 	OrchSecret        string
+	ShardSecret       []byte
 	Transcoder        Transcoder
 	TranscoderManager *RemoteTranscoderManager
 	Balances          *AddressBalances
 	Capabilities      *Capabilities
 	AutoAdjustPrice   bool
+	StreamLimits      *StreamLimits
+	PricingPolicy     policy.PricingPolicy
+	BurstPricing      *BurstPricing
+	ParamsScheduler   *eth.ParamsScheduler
+	// DetectionPrice is the price in wei per second of video to run detection on segments without renditions
+	DetectionPrice *big.Int
+	// SegmentHeartbeat reports the progress of the segment transcode loops to a watchdog
+	SegmentHeartbeat *common.Heartbeat
 
 	// Broadcaster public fields
 	Sender pm.Sender
@@ -89,7 +99,9 @@ type LivepeerNode struct {
 	// Transcoder private fields
 	priceInfo    *big.Rat
 	serviceURI   url.URL
+	maintenance  bool
 	segmentMutex *sync.RWMutex
+	streamOwners map[ManifestID]streamOwner
 }
 
 //NewLivepeerNode creates a new Livepeer Node. Eth can be nil.
@@ -102,6 +114,7 @@ func NewLivepeerNode(e eth.LivepeerEthClient, wd string, dbh *common.DB) (*Livep
 		AutoAdjustPrice: true,
 		SegmentChans:    make(map[ManifestID]SegmentChan),
 		segmentMutex:    &sync.RWMutex{},
+		streamOwners:    make(map[ManifestID]streamOwner),
 	}, nil
 }
 
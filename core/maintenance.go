@@ -0,0 +1,46 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/livepeer/go-livepeer/lperrors"
+)
+
+// ErrOrchMaintenance is returned to broadcasters that try to start a stream while the orchestrator is in maintenance.
+// It is retryable so that broadcasters move the stream to another orchestrator and try this one again later
+var ErrOrchMaintenance = lperrors.Retryable(errors.New("OrchestratorInMaintenance"))
+
+// SetMaintenance puts the orchestrator into or takes it out of maintenance. In maintenance, the orchestrator stops
+// responding to discovery requests and refuses new streams, but keeps transcoding the streams that are running so that
+// it can be upgraded once they end
+func (n *LivepeerNode) SetMaintenance(maintenance bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.maintenance = maintenance
+}
+
+// InMaintenance returns whether the orchestrator is in maintenance
+func (n *LivepeerNode) InMaintenance() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.maintenance
+}
+
+// CheckMaintenance returns ErrOrchMaintenance if the orchestrator is in maintenance, unless mid is the session ID of a
+// running stream
+func (orch *orchestrator) CheckMaintenance(mid ManifestID) error {
+	return orch.node.checkMaintenance(mid)
+}
+
+func (n *LivepeerNode) checkMaintenance(mid ManifestID) error {
+	if !n.InMaintenance() {
+		return nil
+	}
+
+	n.segmentMutex.RLock()
+	defer n.segmentMutex.RUnlock()
+	if _, ok := n.SegmentChans[mid]; ok {
+		return nil
+	}
+	return ErrOrchMaintenance
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/livepeer/go-livepeer/lperrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMaintenance(t *testing.T) {
+	assert := assert.New(t)
+
+	n, _ := NewLivepeerNode(nil, "", nil)
+	orch := NewOrchestrator(n, nil)
+	running := ManifestID("running")
+	n.SegmentChans[running] = make(SegmentChan, 1)
+
+	assert.False(n.InMaintenance())
+	assert.Nil(orch.CheckMaintenance(""))
+	assert.Nil(orch.CheckMaintenance("new"))
+
+	// Running streams are finished while new streams are refused
+	n.SetMaintenance(true)
+	assert.True(n.InMaintenance())
+	assert.Equal(ErrOrchMaintenance, orch.CheckMaintenance(""))
+	assert.Equal(ErrOrchMaintenance, orch.CheckMaintenance("new"))
+	assert.Nil(orch.CheckMaintenance(running))
+	assert.True(lperrors.IsRetryable(ErrOrchMaintenance))
+
+	n.SetMaintenance(false)
+	assert.Nil(orch.CheckMaintenance("new"))
+}
@@ -0,0 +1,328 @@
+package core
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+const (
+	tsPacketSize = 188
+	tsSyncByte   = 0x47
+	patPID       = 0
+	patTableID   = 0x00
+	pmtTableID   = 0x02
+)
+
+// tsStream is an elementary stream listed in the PMT of an MPEG-TS segment
+type tsStream struct {
+	pid        uint16
+	streamType byte
+	// esInfo are the descriptors of the stream in the PMT
+	esInfo []byte
+}
+
+// tsUnit is a payload unit of an elementary stream, i.e. a PES packet or the PSI sections that start in a packet
+type tsUnit struct {
+	stream *tsStream
+	// offset of the first packet of the unit in the segment
+	offset  int
+	payload []byte
+	// packets that carry the payload
+	packets [][]byte
+}
+
+// dataStream is an elementary stream of an MPEG-TS segment that the transcoder drops, i.e. timed metadata or SCTE-35
+// cues, so that its packets can be muxed back into the transcoded segments
+type dataStream struct {
+	streamType byte
+	pid        uint16
+	esInfo     []byte
+	packets    [][]byte
+}
+
+// demuxTS splits the MPEG-TS segment data into the payload units of the elementary streams listed in the PMTs of the
+// segment. Units of streams that appear before their PMT are skipped. Returns nil if data is not MPEG-TS
+func demuxTS(data []byte) []*tsUnit {
+	if len(data) < tsPacketSize || data[0] != tsSyncByte {
+		return nil
+	}
+
+	var units []*tsUnit
+	pmtPIDs := make(map[uint16]bool)
+	streams := make(map[uint16]*tsStream)
+	// Units that are being reassembled by PID
+	pending := make(map[uint16]*tsUnit)
+
+	for off := 0; off+tsPacketSize <= len(data); off += tsPacketSize {
+		pkt := data[off : off+tsPacketSize]
+		if pkt[0] != tsSyncByte {
+			break
+		}
+		pid := tsPID(pkt)
+		pusi := pkt[1]&0x40 != 0
+		payload := tsPayload(pkt)
+		if payload == nil {
+			continue
+		}
+
+		switch {
+		case pid == patPID && pusi:
+			for _, pmtPID := range parsePAT(psiSection(payload)) {
+				pmtPIDs[pmtPID] = true
+			}
+		case pmtPIDs[pid] && pusi:
+			for _, s := range parsePMT(psiSection(payload)) {
+				streams[s.pid] = s
+			}
+		case streams[pid] != nil:
+			if pusi {
+				if u := pending[pid]; u != nil {
+					units = append(units, u)
+				}
+				pending[pid] = &tsUnit{stream: streams[pid], offset: off}
+			}
+			u := pending[pid]
+			if u == nil {
+				// The start of the unit is not in the segment
+				continue
+			}
+			u.payload = append(u.payload, payload...)
+			u.packets = append(u.packets, pkt)
+		}
+	}
+
+	// Flush the units at the end of the segment and return all units in the order they started
+	for _, u := range pending {
+		units = append(units, u)
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].offset < units[j].offset })
+	return units
+}
+
+func tsPID(pkt []byte) uint16 {
+	return uint16(pkt[1]&0x1F)<<8 | uint16(pkt[2])
+}
+
+// tsPayload returns the payload of a packet, skipping the adaptation field, or nil if the packet has no payload
+func tsPayload(pkt []byte) []byte {
+	afc := pkt[3] >> 4 & 0x3
+	if afc&0x1 == 0 {
+		return nil
+	}
+	payload := pkt[4:]
+	if afc&0x2 != 0 {
+		if len(payload) < 1 || int(payload[0])+1 >= len(payload) {
+			return nil
+		}
+		payload = payload[payload[0]+1:]
+	}
+	return payload
+}
+
+// psiSection returns the section that starts in the payload of a packet with the payload unit start indicator set
+func psiSection(payload []byte) []byte {
+	if len(payload) < 1 || int(payload[0])+1 > len(payload) {
+		return nil
+	}
+	return payload[payload[0]+1:]
+}
+
+// psiSectionLen returns the length of the PSI section s including its header or 0 if s is too short
+func psiSectionLen(s []byte) int {
+	if len(s) < 3 {
+		return 0
+	}
+	return 3 + (int(s[1]&0x0F)<<8 | int(s[2]))
+}
+
+// parsePAT returns the PMT PIDs of the programs in a program_association_section
+func parsePAT(s []byte) []uint16 {
+	if len(s) < 8 || s[0] != patTableID {
+		return nil
+	}
+	// Exclude the CRC
+	end := psiSectionLen(s) - 4
+	if end > len(s) {
+		end = len(s)
+	}
+	var pids []uint16
+	for i := 8; i+4 <= end; i += 4 {
+		program := binary.BigEndian.Uint16(s[i:])
+		if program == 0 {
+			// Network PID
+			continue
+		}
+		pids = append(pids, uint16(s[i+2]&0x1F)<<8|uint16(s[i+3]))
+	}
+	return pids
+}
+
+// parsePMT returns the elementary streams of a TS_program_map_section
+func parsePMT(s []byte) []*tsStream {
+	if len(s) < 12 || s[0] != pmtTableID {
+		return nil
+	}
+	end := psiSectionLen(s) - 4
+	if end > len(s) {
+		end = len(s)
+	}
+	var streams []*tsStream
+	for i := 12 + (int(s[10]&0x0F)<<8 | int(s[11])); i+5 <= end; {
+		esInfoLen := int(s[i+3]&0x0F)<<8 | int(s[i+4])
+		if i+5+esInfoLen > end {
+			break
+		}
+		streams = append(streams, &tsStream{
+			pid:        uint16(s[i+1]&0x1F)<<8 | uint16(s[i+2]),
+			streamType: s[i],
+			esInfo:     s[i+5 : i+5+esInfoLen],
+		})
+		i += 5 + esInfoLen
+	}
+	return streams
+}
+
+// pesPTS returns the presentation timestamp of a PES packet in 90kHz clock ticks
+func pesPTS(pes []byte) (uint64, bool) {
+	if len(pes) < 14 || pes[0] != 0 || pes[1] != 0 || pes[2] != 1 || pes[7]&0x80 == 0 {
+		return 0, false
+	}
+	p := pes[9:]
+	return uint64(p[0]>>1&0x07)<<30 | uint64(p[1])<<22 | uint64(p[2]>>1)<<15 | uint64(p[3])<<7 | uint64(p[4]>>1), true
+}
+
+// pesPayload returns the data of a PES packet without its header
+func pesPayload(pes []byte) []byte {
+	if len(pes) < 9 || pes[0] != 0 || pes[1] != 0 || pes[2] != 1 {
+		return nil
+	}
+	start := 9 + int(pes[8])
+	if start > len(pes) {
+		return nil
+	}
+	end := len(pes)
+	if n := int(binary.BigEndian.Uint16(pes[4:])); n > 0 && 6+n < end {
+		end = 6 + n
+	}
+	return pes[start:end]
+}
+
+// crc32MPEG2 returns the CRC of a PSI section
+func crc32MPEG2(data []byte) uint32 {
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range data {
+		crc ^= uint32(b) << 24
+		for i := 0; i < 8; i++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// mux returns the MPEG-TS segment data with the stream added to its PMT and the packets of the stream inserted after
+// the PMT. The stream keeps its PID unless the PID is used by the segment. The data is returned unchanged if it is not
+// MPEG-TS, already has a stream of the same type or its PMT does not fit into a single packet
+func (m *dataStream) mux(data []byte) []byte {
+	if len(m.packets) == 0 || len(data) < tsPacketSize || data[0] != tsSyncByte || len(data)%tsPacketSize != 0 {
+		return data
+	}
+
+	used := make(map[uint16]bool)
+	pmtPIDs := make(map[uint16]bool)
+	for off := 0; off < len(data); off += tsPacketSize {
+		pkt := data[off : off+tsPacketSize]
+		pid := tsPID(pkt)
+		used[pid] = true
+		payload := tsPayload(pkt)
+		if payload == nil || pkt[1]&0x40 == 0 {
+			continue
+		}
+		if pid == patPID {
+			for _, pmtPID := range parsePAT(psiSection(payload)) {
+				pmtPIDs[pmtPID] = true
+			}
+		} else if pmtPIDs[pid] {
+			for _, s := range parsePMT(psiSection(payload)) {
+				if s.streamType == m.streamType {
+					return data
+				}
+				used[s.pid] = true
+			}
+		}
+	}
+	pid := m.pid
+	for used[pid] {
+		pid++
+		if pid >= 0x1FFF {
+			return data
+		}
+	}
+
+	out := make([]byte, 0, len(data)+len(m.packets)*tsPacketSize)
+	inserted := false
+	for off := 0; off < len(data); off += tsPacketSize {
+		pkt := data[off : off+tsPacketSize]
+		if !pmtPIDs[tsPID(pkt)] || pkt[1]&0x40 == 0 {
+			out = append(out, pkt...)
+			continue
+		}
+		pmt := addPMTStream(pkt, pid, m.streamType, m.esInfo)
+		if pmt == nil {
+			return data
+		}
+		out = append(out, pmt...)
+		if !inserted {
+			for _, p := range m.packets {
+				p = append([]byte(nil), p...)
+				p[1] = p[1]&0xE0 | byte(pid>>8)&0x1F
+				p[2] = byte(pid)
+				out = append(out, p...)
+			}
+			inserted = true
+		}
+	}
+	if !inserted {
+		return data
+	}
+	return out
+}
+
+// addPMTStream returns a copy of the packet pkt that carries a PMT with an elementary stream added to the PMT or nil
+// if the PMT does not fit into the packet
+func addPMTStream(pkt []byte, pid uint16, streamType byte, esInfo []byte) []byte {
+	payload := tsPayload(pkt)
+	if payload == nil {
+		return nil
+	}
+	// Header, adaptation field and pointer field
+	hdrLen := tsPacketSize - len(payload) + 1 + int(payload[0])
+	section := psiSection(payload)
+	n := psiSectionLen(section)
+	if n < 16 || n > len(section) {
+		return nil
+	}
+
+	s := append([]byte(nil), section[:n-4]...)
+	s = append(s, streamType, 0xE0|byte(pid>>8), byte(pid), 0xF0|byte(len(esInfo)>>8), byte(len(esInfo)))
+	s = append(s, esInfo...)
+	sectionLen := len(s) + 4 - 3
+	s[1] = s[1]&0xF0 | byte(sectionLen>>8)&0x0F
+	s[2] = byte(sectionLen)
+	s = append(s, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(s[len(s)-4:], crc32MPEG2(s[:len(s)-4]))
+	if hdrLen+len(s) > tsPacketSize {
+		return nil
+	}
+
+	out := make([]byte, tsPacketSize)
+	copy(out, pkt[:hdrLen])
+	copy(out[hdrLen:], s)
+	for i := hdrLen + len(s); i < tsPacketSize; i++ {
+		out[i] = 0xFF
+	}
+	return out
+}
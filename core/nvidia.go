@@ -0,0 +1,134 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+)
+
+// nvidiaRequirement is the minimum NVIDIA driver and CUDA versions needed by the bundled ffmpeg for a set of features
+type nvidiaRequirement struct {
+	feature          string
+	minDriverLinux   string
+	minDriverWindows string
+	minCUDA          string
+	// caps are the capabilities that need the requirement. If empty, the requirement applies to all GPU transcoding
+	caps []Capability
+}
+
+// nvidiaRequirements is the matrix of driver and CUDA versions that are known to work with the NVENC/NVDEC features
+// used by the bundled ffmpeg
+var nvidiaRequirements = []nvidiaRequirement{
+	{
+		feature:          "NVENC/NVDEC transcoding",
+		minDriverLinux:   "418.30",
+		minDriverWindows: "418.81",
+		minCUDA:          "10.0",
+	},
+	{
+		feature:          "H.264 4:2:2, 4:4:4 and 10-bit decoding",
+		minDriverLinux:   "470.57.02",
+		minDriverWindows: "471.41",
+		minCUDA:          "11.4",
+		caps: []Capability{
+			Capability_H264_Decode_444_8bit,
+			Capability_H264_Decode_422_8bit,
+			Capability_H264_Decode_444_10bit,
+			Capability_H264_Decode_422_10bit,
+			Capability_H264_Decode_420_10bit,
+		},
+	},
+}
+
+// nvidiaKnownIssues are driver versions (prefixes) that work but have known issues
+var nvidiaKnownIssues = map[string]string{
+	"450.": "occasionally leads to stuck transcoding sessions",
+}
+
+// CheckNvidiaCompatibility compares the NVIDIA driver and CUDA versions against the versions needed by the bundled
+// ffmpeg. It returns an error if GPU transcoding is not supported at all and otherwise returns the capabilities
+// that are not supported and should be disabled
+func CheckNvidiaCompatibility(v *common.NvidiaVersions, goos string) (disabled []Capability, err error) {
+	for prefix, issue := range nvidiaKnownIssues {
+		if strings.HasPrefix(v.Driver, prefix) {
+			glog.Warningf("NVIDIA driver %s %s, consider switching to a different driver version", v.Driver, issue)
+		}
+	}
+
+	for _, req := range nvidiaRequirements {
+		minDriver := req.minDriverLinux
+		if goos == "windows" {
+			minDriver = req.minDriverWindows
+		}
+
+		ok, err := versionAtLeast(v.Driver, minDriver)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NVIDIA driver version: %v", err)
+		}
+		// The CUDA version is not always available
+		if ok && v.CUDA != "" {
+			if ok, err = versionAtLeast(v.CUDA, req.minCUDA); err != nil {
+				return nil, fmt.Errorf("invalid CUDA version: %v", err)
+			}
+		}
+		if ok {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s requires NVIDIA driver >= %s and CUDA >= %s, found driver=%s cuda=%s",
+			req.feature, minDriver, req.minCUDA, v.Driver, v.CUDA)
+		if len(req.caps) == 0 {
+			return nil, fmt.Errorf("unsupported NVIDIA driver: %s", msg)
+		}
+		for _, c := range req.caps {
+			if InArray(c, DefaultCapabilities()) {
+				return nil, fmt.Errorf("unsupported NVIDIA driver: %s", msg)
+			}
+		}
+		glog.Warningf("Disabling capabilities: %s", msg)
+		disabled = append(disabled, req.caps...)
+	}
+
+	return disabled, nil
+}
+
+// versionAtLeast returns true if the dot separated numeric version v is greater than or equal to min
+func versionAtLeast(v, min string) (bool, error) {
+	a, err := parseVersion(v)
+	if err != nil {
+		return false, err
+	}
+	b, err := parseVersion(min)
+	if err != nil {
+		return false, err
+	}
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x > y, nil
+		}
+	}
+	return true, nil
+}
+
+func parseVersion(v string) ([]int, error) {
+	parts := strings.Split(v, ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q", v)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionAtLeast(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		v, min string
+		ok     bool
+	}{
+		{"460.39", "418.30", true},
+		{"418.30", "418.30", true},
+		{"418.3", "418.30", false},
+		{"470.57.02", "470.57", true},
+		{"470.57", "470.57.02", false},
+		{"11.2", "11.4", false},
+		{"12", "11.4", true},
+	}
+	for _, tt := range tests {
+		ok, err := versionAtLeast(tt.v, tt.min)
+		assert.Nil(err)
+		assert.Equal(tt.ok, ok, "%v >= %v", tt.v, tt.min)
+	}
+
+	_, err := versionAtLeast("foo", "1.0")
+	assert.EqualError(err, `invalid version "foo"`)
+	_, err = versionAtLeast("1.0", "")
+	assert.EqualError(err, `invalid version ""`)
+}
+
+func TestCheckNvidiaCompatibility(t *testing.T) {
+	assert := assert.New(t)
+
+	h264DecodeCaps := []Capability{
+		Capability_H264_Decode_444_8bit,
+		Capability_H264_Decode_422_8bit,
+		Capability_H264_Decode_444_10bit,
+		Capability_H264_Decode_422_10bit,
+		Capability_H264_Decode_420_10bit,
+	}
+
+	// All features are supported
+	disabled, err := CheckNvidiaCompatibility(&common.NvidiaVersions{Driver: "470.57.02", CUDA: "11.4"}, "linux")
+	assert.Nil(err)
+	assert.Empty(disabled)
+
+	// Optional capabilities are disabled on older drivers
+	disabled, err = CheckNvidiaCompatibility(&common.NvidiaVersions{Driver: "460.39", CUDA: "11.2"}, "linux")
+	assert.Nil(err)
+	assert.Equal(h264DecodeCaps, disabled)
+
+	// Older CUDA versions disable the same capabilities
+	disabled, err = CheckNvidiaCompatibility(&common.NvidiaVersions{Driver: "470.57.02", CUDA: "11.2"}, "linux")
+	assert.Nil(err)
+	assert.Equal(h264DecodeCaps, disabled)
+
+	// Minimum driver versions differ on Windows
+	disabled, err = CheckNvidiaCompatibility(&common.NvidiaVersions{Driver: "470.57.02", CUDA: "11.4"}, "windows")
+	assert.Nil(err)
+	assert.Equal(h264DecodeCaps, disabled)
+
+	// The CUDA version is not checked if it is unknown
+	disabled, err = CheckNvidiaCompatibility(&common.NvidiaVersions{Driver: "470.57.02"}, "linux")
+	assert.Nil(err)
+	assert.Empty(disabled)
+
+	// GPU transcoding is not supported
+	_, err = CheckNvidiaCompatibility(&common.NvidiaVersions{Driver: "410.48", CUDA: "10.0"}, "linux")
+	assert.EqualError(err, "unsupported NVIDIA driver: NVENC/NVDEC transcoding requires NVIDIA driver >= 418.30 and CUDA >= 10.0, found driver=410.48 cuda=10.0")
+	_, err = CheckNvidiaCompatibility(&common.NvidiaVersions{Driver: "418.39", CUDA: "9.2"}, "linux")
+	assert.NotNil(err)
+
+	_, err = CheckNvidiaCompatibility(&common.NvidiaVersions{Driver: "foo"}, "linux")
+	assert.EqualError(err, `invalid NVIDIA driver version: invalid version "foo"`)
+}
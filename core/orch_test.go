@@ -26,6 +26,7 @@ import (
 	"github.com/livepeer/lpms/ffmpeg"
 
 	"github.com/livepeer/go-livepeer/net"
+	"github.com/livepeer/go-livepeer/policy"
 )
 
 var defaultRecipient = ethcommon.BytesToAddress([]byte("defaultRecipient"))
@@ -240,6 +241,90 @@ func TestManageTranscoders(t *testing.T) {
 	assert.Equal(0, m.RegisteredTranscodersCount())
 }
 
+func TestRemoteTranscoder_Deadline(t *testing.T) {
+	assert := assert.New(t)
+	m := NewRemoteTranscoderManager()
+	strm := &StubTranscoderServer{manager: m}
+	tc := NewRemoteTranscoder(m, strm, 5, nil)
+
+	// The time left until the deadline is passed on to the transcoder
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	md := &SegTranscodingMetadata{TimeToDeadline: time.Hour}
+	_, err := tc.Transcode(ctx, md)
+	assert.Nil(err)
+	assert.InDelta(time.Minute.Milliseconds(), strm.LastNotify.SegData.TimeToDeadline, 1000)
+	assert.Equal(time.Hour, md.TimeToDeadline)
+
+	// No deadline
+	_, err = tc.Transcode(context.Background(), md)
+	assert.Nil(err)
+	assert.Zero(strm.LastNotify.SegData.TimeToDeadline)
+
+	// The transcoder is not sent segments that are already past their deadline
+	strm.LastNotify = nil
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = tc.Transcode(expired, md)
+	assert.Equal(ErrSegmentDeadlineExceeded, err)
+	assert.Nil(strm.LastNotify)
+
+	// Waiting for the results stops at the deadline without a fatal error, so the transcoder is kept
+	strm.WithholdResults = true
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = tc.Transcode(ctx, md)
+	assert.Equal(ErrSegmentDeadlineExceeded, err)
+}
+
+func TestRemoteTranscoderManager_CapabilityUtilization(t *testing.T) {
+	assert := assert.New(t)
+	m := NewRemoteTranscoderManager()
+	strm := &StubTranscoderServer{}
+	strm2 := &StubTranscoderServer{manager: m}
+
+	assert.Empty(m.capabilityUtilization())
+
+	t1 := NewRemoteTranscoder(m, strm, 4, NewCapabilities(DefaultCapabilities(), nil))
+	t1.load = 1
+	t2 := NewRemoteTranscoder(m, strm2, 6, NewCapabilities(append(DefaultCapabilities(), Capability_HEVC_Encode), nil))
+	t2.load = 6
+	m.liveTranscoders[strm] = t1
+	m.liveTranscoders[strm2] = t2
+
+	// Utilization of capabilities supported by all transcoders and by a single transcoder
+	utilization := m.capabilityUtilization()
+	assert.Equal(0.7, utilization[Capability_H264])
+	assert.Equal(1.0, utilization[Capability_HEVC_Encode])
+	assert.NotContains(utilization, Capability_H264_Decode_444_8bit)
+	assert.NotContains(utilization, Capability_Unused)
+
+	// Capabilities that are no longer supported are reset
+	delete(m.liveTranscoders, strm2)
+	utilization = m.capabilityUtilization()
+	assert.Equal(0.25, utilization[Capability_H264])
+	assert.Contains(utilization, Capability_HEVC_Encode)
+	assert.Zero(utilization[Capability_HEVC_Encode])
+}
+
+func TestRemoteTranscoderManager_RealtimeHeadroom(t *testing.T) {
+	assert := assert.New(t)
+	m := NewRemoteTranscoderManager()
+
+	// Segments without a duration are ignored
+	m.updateRealtimeHeadroom(time.Second, 0)
+	assert.Zero(m.realtimeHeadroomSamples)
+
+	// The first segment sets the headroom
+	m.updateRealtimeHeadroom(500*time.Millisecond, 2*time.Second)
+	assert.Equal(0.75, m.realtimeHeadroom)
+
+	// Slower than realtime segments lower the headroom
+	m.updateRealtimeHeadroom(4*time.Second, 2*time.Second)
+	assert.InDelta(0.4, m.realtimeHeadroom, 1e-9)
+	assert.Equal(2, m.realtimeHeadroomSamples)
+}
+
 func TestSelectTranscoder(t *testing.T) {
 	m := NewRemoteTranscoderManager()
 	strm := &StubTranscoderServer{manager: m, WithholdResults: false}
@@ -564,11 +649,13 @@ type StubTranscoderServer struct {
 	SendError       error
 	TranscodeError  error
 	WithholdResults bool
+	LastNotify      *net.NotifySegment
 
 	common.StubServerStream
 }
 
 func (s *StubTranscoderServer) Send(n *net.NotifySegment) error {
+	s.LastNotify = n
 	res := RemoteTranscoderResult{
 		TranscodeData: &TranscodeData{
 			Segments: []*TranscodedSegmentData{
@@ -1573,6 +1660,51 @@ func TestPriceInfo_GivenNilRecipient_ReturnsNilError(t *testing.T) {
 	assert.Nil(t, priceInfo)
 }
 
+type stubPricingPolicy struct {
+	req *policy.PriceRequest
+	res *policy.PriceResponse
+	err error
+}
+
+func (p *stubPricingPolicy) Price(ctx context.Context, req *policy.PriceRequest) (*policy.PriceResponse, error) {
+	p.req = req
+	return p.res, p.err
+}
+
+func TestPriceInfo_PricingPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	n, _ := NewLivepeerNode(nil, "", nil)
+	n.SetBasePrice(big.NewRat(1, 1))
+	n.AutoAdjustPrice = false
+	recipient := new(pm.MockRecipient)
+	n.Recipient = recipient
+	pp := &stubPricingPolicy{res: &policy.PriceResponse{Price: policy.Price{PricePerUnit: 3, PixelsPerUnit: 2}}}
+	n.PricingPolicy = pp
+	orch := NewOrchestrator(n, nil)
+
+	sender := ethcommon.BytesToAddress([]byte("sender"))
+	priceInfo, err := orch.PriceInfo(sender)
+	assert.Nil(err)
+	assert.Zero(big.NewRat(3, 2).Cmp(big.NewRat(priceInfo.PricePerUnit, priceInfo.PixelsPerUnit)))
+	assert.Equal(sender.Hex(), pp.req.Broadcaster)
+	assert.Equal(policy.Price{PricePerUnit: 1, PixelsPerUnit: 1}, pp.req.BasePrice)
+	assert.Equal(policy.Price{PricePerUnit: 1, PixelsPerUnit: 1}, pp.req.Price)
+
+	// Fall back to the default price if the policy fails
+	pp.err = errors.New("policy error")
+	priceInfo, err = orch.PriceInfo(sender)
+	assert.Nil(err)
+	assert.Zero(big.NewRat(1, 1).Cmp(big.NewRat(priceInfo.PricePerUnit, priceInfo.PixelsPerUnit)))
+
+	// Fall back to the default price if the policy returns an invalid price
+	pp.err = nil
+	pp.res = &policy.PriceResponse{Price: policy.Price{PricePerUnit: 1, PixelsPerUnit: 0}}
+	priceInfo, err = orch.PriceInfo(sender)
+	assert.Nil(err)
+	assert.Zero(big.NewRat(1, 1).Cmp(big.NewRat(priceInfo.PricePerUnit, priceInfo.PixelsPerUnit)))
+}
+
 func TestPriceInfo_TxMultiplierError_ReturnsError(t *testing.T) {
 	expError := errors.New("TxMultiplier Error")
 
@@ -1619,6 +1751,26 @@ func TestDebitFees(t *testing.T) {
 	assert.Zero(orch.node.Balances.Balance(addr, manifestID).Cmp(big.NewRat(0, 1)))
 }
 
+func TestDebitDetectionFees(t *testing.T) {
+	assert := assert.New(t)
+	addr := ethcommon.Address{}
+	manifestID := ManifestID("some manifest")
+
+	n, _ := NewLivepeerNode(nil, "", nil)
+	orch := NewOrchestrator(n, nil)
+
+	// Offchain orchestrators do not debit
+	assert.Zero(orch.DetectionPrice().Sign())
+	assert.NotPanics(func() { orch.DebitDetectionFees(addr, manifestID, 2*time.Second) })
+
+	n.Balances = NewAddressBalances(5 * time.Second)
+	n.DetectionPrice = big.NewInt(10)
+	assert.Equal(big.NewInt(10), orch.DetectionPrice())
+
+	orch.DebitDetectionFees(addr, manifestID, 2500*time.Millisecond)
+	assert.Zero(n.Balances.Balance(addr, manifestID).Cmp(big.NewRat(-25, 1)))
+}
+
 func TestDebitFees_OffChain_Returns(t *testing.T) {
 	price := &net.PriceInfo{
 		PricePerUnit:  1,
@@ -1690,6 +1842,34 @@ func TestAuthToken(t *testing.T) {
 	assert.Equal(authToken0.Expiration, authToken5.Expiration)
 }
 
+func TestAuthToken_ShardSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	// Orchestrators with the same shard secret issue the same tokens
+	n0, err := NewLivepeerNode(nil, "", nil)
+	require.Nil(t, err)
+	n0.ShardSecret = []byte("foo")
+	n1, err := NewLivepeerNode(nil, "", nil)
+	require.Nil(t, err)
+	n1.ShardSecret = []byte("foo")
+
+	authToken0 := NewOrchestrator(n0, nil).AuthToken("bar", 100)
+	authToken1 := NewOrchestrator(n1, nil).AuthToken("bar", 100)
+	assert.Equal(authToken0.Token, authToken1.Token)
+
+	// The token is not signed with the shard secret itself
+	origFunc := common.RandomBytesGenerator
+	defer func() { common.RandomBytesGenerator = origFunc }()
+	n1.ShardSecret = nil
+	common.RandomBytesGenerator = func(length uint) []byte { return []byte("foo") }
+	authToken2 := NewOrchestrator(n1, nil).AuthToken("bar", 100)
+	assert.NotEqual(authToken0.Token, authToken2.Token)
+
+	// Different purposes derive different secrets
+	assert.NotEqual(DeriveShardSecret([]byte("foo"), "authToken"), DeriveShardSecret([]byte("foo"), "recipient"))
+	assert.NotEqual(DeriveShardSecret([]byte("foo"), "authToken"), DeriveShardSecret([]byte("notfoo"), "authToken"))
+}
+
 func defaultPayment(t *testing.T) net.Payment {
 	ticketSenderParams := &net.TicketSenderParams{
 		SenderNonce: 456,
@@ -1753,3 +1933,38 @@ func tempDBWithOrchs(t *testing.T, orchs []*common.DBOrch) (*common.DB, *sql.DB)
 
 	return dbh, dbraw
 }
+
+func TestKeepAlive(t *testing.T) {
+	assert := assert.New(t)
+
+	oldLoopTimeout, oldKeepaliveTimeout, oldMaxSessions := transcodeLoopTimeout, keepaliveTimeout, MaxSessions
+	defer func() {
+		transcodeLoopTimeout, keepaliveTimeout, MaxSessions = oldLoopTimeout, oldKeepaliveTimeout, oldMaxSessions
+	}()
+	MaxSessions = 10
+	transcodeLoopTimeout = 5 * time.Second
+	keepaliveTimeout = 100 * time.Millisecond
+
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+	n, _ := NewLivepeerNode(nil, "", nil)
+	orch := NewOrchestrator(n, nil)
+	md := StubSegTranscodingMetadata()
+	mid := ManifestID(md.AuthToken.SessionId)
+
+	assert.Equal(ErrSessionNotFound, orch.KeepAlive(mid))
+
+	_, err := n.getSegmentChan(context.TODO(), md)
+	require.Nil(t, err)
+
+	// The session is kept alive as long as keepalives are received
+	for i := 0; i < 6; i++ {
+		assert.Nil(orch.KeepAlive(mid))
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.NotNil(getSegChan(n, mid))
+
+	// The session is torn down well before transcodeLoopTimeout once keepalives stop
+	time.Sleep(300 * time.Millisecond)
+	assert.Nil(getSegChan(n, mid))
+	assert.Equal(ErrSessionNotFound, orch.KeepAlive(mid))
+}
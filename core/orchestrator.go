@@ -23,9 +23,11 @@ import (
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/drivers"
 	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/lperrors"
 	"github.com/livepeer/go-livepeer/monitor"
 	"github.com/livepeer/go-livepeer/net"
 	"github.com/livepeer/go-livepeer/pm"
+	"github.com/livepeer/go-livepeer/policy"
 
 	lpcrypto "github.com/livepeer/go-livepeer/crypto"
 	lpmon "github.com/livepeer/go-livepeer/monitor"
@@ -37,6 +39,20 @@ const maxSegmentChannels = 4
 
 var transcodeLoopTimeout = 1 * time.Minute
 
+// realtimeHeadroomWeight is the weight of the latest segment in the moving average of the realtime headroom of remote
+// transcoders
+const realtimeHeadroomWeight = 0.2
+
+// KeepaliveInterval is how often broadcasters send keepalives for the orchestrator sessions they are using
+var KeepaliveInterval = 5 * time.Second
+
+// keepaliveTimeout is the time after which a session that has received keepalives is considered orphaned if it
+// receives neither segments nor keepalives. Sessions that never received a keepalive (i.e. from older broadcasters)
+// are torn down after transcodeLoopTimeout
+var keepaliveTimeout = 3 * KeepaliveInterval
+
+var ErrSessionNotFound = errors.New("session not found")
+
 // Gives us more control of "timeout" / cancellation behavior during testing
 var transcodeLoopContext = func() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), transcodeLoopTimeout)
@@ -88,6 +104,11 @@ func (orch *orchestrator) CheckCapacity(mid ManifestID) error {
 	return nil
 }
 
+// KeepAlive prevents the session for mid from being torn down while the broadcaster is not sending segments
+func (orch *orchestrator) KeepAlive(mid ManifestID) error {
+	return orch.node.keepAlive(mid)
+}
+
 func (orch *orchestrator) TranscodeSeg(ctx context.Context, md *SegTranscodingMetadata, seg *stream.HLSSegment) (*TranscodeResult, error) {
 	return orch.node.sendToTranscodeLoop(ctx, md, seg)
 }
@@ -271,6 +292,51 @@ func (orch *orchestrator) PriceInfo(sender ethcommon.Address) (*net.PriceInfo, e
 
 // priceInfo returns price per pixel as a fixed point number wrapped in a big.Rat
 func (orch *orchestrator) priceInfo(sender ethcommon.Address) (*big.Rat, error) {
+	price, err := orch.defaultPriceInfo(sender)
+	if err != nil {
+		return nil, err
+	}
+	if orch.node.BurstPricing != nil {
+		fixedPrice, err := common.PriceToFixed(orch.node.BurstPricing.price(sender, price, time.Now()))
+		if err != nil {
+			return nil, err
+		}
+		price = common.FixedToPrice(fixedPrice)
+	}
+	if orch.node.PricingPolicy == nil {
+		return price, nil
+	}
+	return orch.policyPriceInfo(sender, price), nil
+}
+
+// policyPriceInfo returns the price per pixel set by the pricing policy or price if the policy fails
+func (orch *orchestrator) policyPriceInfo(sender ethcommon.Address, price *big.Rat) *big.Rat {
+	basePrice := orch.node.GetBasePrice()
+	req := &policy.PriceRequest{
+		Broadcaster: sender.Hex(),
+		BasePrice:   policy.Price{PricePerUnit: basePrice.Num().Int64(), PixelsPerUnit: basePrice.Denom().Int64()},
+		Price:       policy.Price{PricePerUnit: price.Num().Int64(), PixelsPerUnit: price.Denom().Int64()},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), policy.Timeout)
+	defer cancel()
+	res, err := orch.node.PricingPolicy.Price(ctx, req)
+	if err != nil {
+		glog.Errorf("Pricing policy failed, using the default price sender=%s err=%q", sender.Hex(), err)
+		return price
+	}
+	policyPrice, err := res.Price.Rat()
+	if err == nil {
+		var fixedPrice int64
+		if fixedPrice, err = common.PriceToFixed(policyPrice); err == nil {
+			return common.FixedToPrice(fixedPrice)
+		}
+	}
+	glog.Errorf("Pricing policy returned an invalid price, using the default price sender=%s price=%+v err=%q", sender.Hex(), res.Price, err)
+	return price
+}
+
+// defaultPriceInfo returns the base price per pixel adjusted for the overhead of redeeming tickets of sender
+func (orch *orchestrator) defaultPriceInfo(sender ethcommon.Address) (*big.Rat, error) {
 	basePrice := orch.node.GetBasePrice()
 
 	if !orch.node.AutoAdjustPrice {
@@ -319,10 +385,28 @@ func (orch *orchestrator) DebitFees(addr ethcommon.Address, manifestID ManifestI
 	if orch.node == nil || orch.node.Balances == nil {
 		return
 	}
+	orch.node.BurstPricing.use(addr, pixels, time.Now())
 	priceRat := big.NewRat(price.GetPricePerUnit(), price.GetPixelsPerUnit())
 	orch.node.Balances.Debit(addr, manifestID, priceRat.Mul(priceRat, big.NewRat(pixels, 1)))
 }
 
+// DetectionPrice returns the price in wei per second of video to run detection on segments without renditions
+func (orch *orchestrator) DetectionPrice() *big.Int {
+	if orch.node == nil || orch.node.DetectionPrice == nil {
+		return big.NewInt(0)
+	}
+	return orch.node.DetectionPrice
+}
+
+// DebitDetectionFees debits the balance for a ManifestID based on the duration of a detection-only segment * detection price
+func (orch *orchestrator) DebitDetectionFees(addr ethcommon.Address, manifestID ManifestID, duration time.Duration) {
+	// Don't debit in offchain mode
+	if orch.node == nil || orch.node.Balances == nil {
+		return
+	}
+	orch.node.Balances.Debit(addr, manifestID, DetectionFee(orch.DetectionPrice(), duration))
+}
+
 func (orch *orchestrator) Capabilities() *net.Capabilities {
 	if orch.node == nil {
 		return nil
@@ -362,18 +446,33 @@ func NewOrchestrator(n *LivepeerNode, rm common.RoundsManager) *orchestrator {
 	if n.Eth != nil {
 		addr = n.Eth.Account().Address
 	}
+	secret := common.RandomBytesGenerator(32)
+	if len(n.ShardSecret) > 0 {
+		secret = DeriveShardSecret(n.ShardSecret, "authToken")
+	}
 	return &orchestrator{
 		node:    n,
 		address: addr,
 		rm:      rm,
-		secret:  common.RandomBytesGenerator(32),
+		secret:  secret,
 	}
 }
 
+// DeriveShardSecret derives the secret used for purpose from the secret shared by sharded orchestrators
+func DeriveShardSecret(shardSecret []byte, purpose string) []byte {
+	h := hmac.New(sha256.New, shardSecret)
+	h.Write([]byte(purpose))
+	return h.Sum(nil)
+}
+
 // LivepeerNode transcode methods
 
-var ErrOrchBusy = errors.New("OrchestratorBusy")
-var ErrOrchCap = errors.New("OrchestratorCapped")
+var ErrOrchBusy = lperrors.Retryable(errors.New("OrchestratorBusy"))
+var ErrOrchCap = lperrors.Retryable(errors.New("OrchestratorCapped"))
+
+// ErrSegmentDeadlineExceeded is returned for segments whose deadline passes before they are transcoded. The broadcaster
+// has stopped waiting for the segment by then, so it is not retried
+var ErrSegmentDeadlineExceeded = lperrors.Fatal(errors.New("SegmentDeadlineExceeded"))
 
 type TranscodeResult struct {
 	Err           error
@@ -436,6 +535,9 @@ func (rtm *RemoteTranscoderManager) addTaskChan() (int64, TranscoderChan) {
 		return taskID, tc
 	}
 	rtm.taskChans[taskID] = make(TranscoderChan, 1)
+	if monitor.Enabled {
+		monitor.TranscodeTasksPending(len(rtm.taskChans))
+	}
 	return taskID, rtm.taskChans[taskID]
 }
 
@@ -447,6 +549,9 @@ func (rtm *RemoteTranscoderManager) removeTaskChan(taskID int64) {
 		return
 	}
 	delete(rtm.taskChans, taskID)
+	if monitor.Enabled {
+		monitor.TranscodeTasksPending(len(rtm.taskChans))
+	}
 }
 
 func (n *LivepeerNode) getSegmentChan(ctx context.Context, md *SegTranscodingMetadata) (SegmentChan, error) {
@@ -471,6 +576,22 @@ func (n *LivepeerNode) getSegmentChan(ctx context.Context, md *SegTranscodingMet
 	return sc, nil
 }
 
+func (n *LivepeerNode) keepAlive(mid ManifestID) error {
+	// Hold the lock while sending so that the segment loop cannot close the channel in the meantime
+	n.segmentMutex.RLock()
+	defer n.segmentMutex.RUnlock()
+	sc, ok := n.SegmentChans[mid]
+	if !ok {
+		return ErrSessionNotFound
+	}
+	select {
+	case sc <- &SegChanData{}:
+	default:
+		// The channel is full of segments which keep the session alive anyway
+	}
+	return nil
+}
+
 func (n *LivepeerNode) sendToTranscodeLoop(ctx context.Context, md *SegTranscodingMetadata, seg *stream.HLSSegment) (*TranscodeResult, error) {
 	clog.V(common.DEBUG).Infof(ctx, "Starting to transcode segment")
 	ch, err := n.getSegmentChan(ctx, md)
@@ -500,6 +621,12 @@ func (n *LivepeerNode) transcodeSeg(ctx context.Context, config transcodeConfig,
 		return &TranscodeResult{Err: err}
 	}
 
+	// The segment may have waited in the segment channel past its deadline
+	if ctx.Err() == context.DeadlineExceeded {
+		clog.Errorf(ctx, "Abandoning segment past its deadline")
+		return terr(ErrSegmentDeadlineExceeded)
+	}
+
 	// Prevent unnecessary work, check for replayed sequence numbers.
 	// NOTE: If we ever process segments from the same job concurrently,
 	// we may still end up doing work multiple times. But this is OK for now.
@@ -569,6 +696,19 @@ func (n *LivepeerNode) transcodeSeg(ctx context.Context, config transcodeConfig,
 		monitor.SegmentTranscoded(ctx, 0, seg.SeqNo, md.Duration, took, common.ProfilesNames(md.Profiles), true, true)
 	}
 
+	// The transcoder drops the timed metadata and the SCTE-35 cues of the segment, so they are muxed back into the
+	// renditions
+	if tm := ParseTimedMetadata(seg.Data); tm != nil {
+		for i := range tSegments {
+			tSegments[i].Data = tm.Mux(tSegments[i].Data)
+		}
+	}
+	if ss := ParseSpliceStream(seg.Data); ss != nil {
+		for i := range tSegments {
+			tSegments[i].Data = ss.Mux(tSegments[i].Data)
+		}
+	}
+
 	// Prepare the result object
 	var tr TranscodeResult
 	segHashes := make([][]byte, len(tSegments))
@@ -626,9 +766,10 @@ func (n *LivepeerNode) transcodeSegmentLoop(logCtx context.Context, md *SegTrans
 		LocalOS: los,
 	}
 	go func() {
+		timeout := transcodeLoopTimeout
 		for {
 			// XXX make context timeout configurable
-			ctx, cancel := context.WithTimeout(context.Background(), transcodeLoopTimeout)
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
 			select {
 			case <-ctx.Done():
 				// timeout; clean up goroutine here
@@ -639,10 +780,12 @@ func (n *LivepeerNode) transcodeSegmentLoop(logCtx context.Context, md *SegTrans
 					n.TranscoderManager.RTmutex.Lock()
 					n.TranscoderManager.completeStreamSession(md.AuthToken.SessionId)
 					n.TranscoderManager.RTmutex.Unlock()
+					n.TranscoderManager.recordLoad()
 				}
-				clog.V(common.DEBUG).Infof(logCtx, "Segment loop timed out; closing ")
+				clog.V(common.DEBUG).Infof(logCtx, "Segment loop timed out; closing timeout=%v", timeout)
 				n.segmentMutex.Lock()
 				mid := ManifestID(md.AuthToken.SessionId)
+				delete(n.streamOwners, mid)
 				if _, ok := n.SegmentChans[mid]; ok {
 					close(n.SegmentChans[mid])
 					delete(n.SegmentChans, mid)
@@ -653,7 +796,14 @@ func (n *LivepeerNode) transcodeSegmentLoop(logCtx context.Context, md *SegTrans
 				n.segmentMutex.Unlock()
 				return
 			case chanData := <-segChan:
-				chanData.res <- n.transcodeSeg(chanData.ctx, config, chanData.seg, chanData.md)
+				if chanData.seg == nil {
+					// The broadcaster sends keepalives so the session is orphaned as soon as they stop
+					timeout = keepaliveTimeout
+				} else {
+					n.SegmentHeartbeat.Busy()
+					chanData.res <- n.transcodeSeg(chanData.ctx, config, chanData.seg, chanData.md)
+					n.SegmentHeartbeat.Idle()
+				}
 			}
 			cancel()
 		}
@@ -696,9 +846,9 @@ func NewRemoteTranscoderFatalError(err error) error {
 	return RemoteTranscoderFatalError{err}
 }
 
-var ErrRemoteTranscoderTimeout = errors.New("Remote transcoder took too long")
-var ErrNoTranscodersAvailable = errors.New("no transcoders available")
-var ErrNoCompatibleTranscodersAvailable = errors.New("no transcoders can provide requested capabilities")
+var ErrRemoteTranscoderTimeout = lperrors.Retryable(errors.New("Remote transcoder took too long"))
+var ErrNoTranscodersAvailable = lperrors.Retryable(errors.New("no transcoders available"))
+var ErrNoCompatibleTranscodersAvailable = lperrors.Fatal(errors.New("no transcoders can provide requested capabilities"))
 
 func (rt *RemoteTranscoder) done() {
 	// select so we don't block indefinitely if there's no listener
@@ -723,10 +873,19 @@ func (rt *RemoteTranscoder) Transcode(logCtx context.Context, md *SegTranscoding
 	mdCopy := *md
 	mdCopy.OS = nil // remote transcoders currently upload directly back to O
 	mdCopy.Hash = ethcommon.Hash{}
+	mdCopy.TimeToDeadline = 0 // passed on from the deadline of logCtx instead
 	segData, err := NetSegData(&mdCopy)
 	if err != nil {
 		return nil, err
 	}
+	deadline, hasDeadline := logCtx.Deadline()
+	if hasDeadline {
+		// Pass on the time that is left until the deadline rather than the time as of when the segment was received
+		segData.TimeToDeadline = int64(time.Until(deadline) / time.Millisecond)
+		if segData.TimeToDeadline <= 0 {
+			return nil, ErrSegmentDeadlineExceeded
+		}
+	}
 
 	start := time.Now()
 	msg := &net.NotifySegment{
@@ -751,9 +910,19 @@ func (rt *RemoteTranscoder) Transcode(logCtx context.Context, md *SegTranscoding
 
 	ctx, cancel := context.WithTimeout(context.Background(), dur)
 	defer cancel()
+	// Stop waiting for the results at the deadline of the segment. The transcoder is not at fault, so it is kept
+	var deadlineC <-chan time.Time
+	if hasDeadline {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		deadlineC = timer.C
+	}
 	select {
 	case <-ctx.Done():
 		return signalEOF(ErrRemoteTranscoderTimeout)
+	case <-deadlineC:
+		clog.Errorf(logCtx, "Segment deadline exceeded waiting for remote transcoder=%s taskId=%d fname=%s", rt.addr, taskID, fname)
+		return nil, ErrSegmentDeadlineExceeded
 	case chanData := <-taskChan:
 		segmentLen := 0
 		if chanData.TranscodeData != nil {
@@ -761,6 +930,9 @@ func (rt *RemoteTranscoder) Transcode(logCtx context.Context, md *SegTranscoding
 		}
 		clog.InfofErr(logCtx, "Successfully received results from remote transcoder=%s segments=%d taskId=%d fname=%s dur=%v",
 			rt.addr, segmentLen, taskID, fname, time.Since(start), chanData.Err)
+		if chanData.Err == nil {
+			rt.manager.updateRealtimeHeadroom(time.Since(start), md.Duration)
+		}
 		return chanData.TranscodeData, chanData.Err
 	}
 }
@@ -785,6 +957,8 @@ func NewRemoteTranscoderManager() *RemoteTranscoderManager {
 		taskChans: make(map[int64]TranscoderChan),
 
 		streamSessions: make(map[string]*RemoteTranscoder),
+
+		utilizationCaps: make(map[Capability]bool),
 	}
 }
 
@@ -812,6 +986,11 @@ type RemoteTranscoderManager struct {
 
 	// Map for keeping track of sessions and their respective transcoders
 	streamSessions map[string]*RemoteTranscoder
+
+	// For the metrics that remote transcoders are autoscaled with
+	realtimeHeadroom        float64
+	realtimeHeadroomSamples int
+	utilizationCaps         map[Capability]bool
 }
 
 // RegisteredTranscodersCount returns number of registered transcoders
@@ -848,27 +1027,16 @@ func (rtm *RemoteTranscoderManager) Manage(stream net.Transcoder_RegisterTransco
 	rtm.liveTranscoders[transcoder.stream] = transcoder
 	rtm.remoteTranscoders = append(rtm.remoteTranscoders, transcoder)
 	sort.Sort(byLoadFactor(rtm.remoteTranscoders))
-	var totalLoad, totalCapacity, liveTranscodersNum int
-	if monitor.Enabled {
-		totalLoad, totalCapacity, liveTranscodersNum = rtm.totalLoadAndCapacity()
-	}
 	rtm.RTmutex.Unlock()
-	if monitor.Enabled {
-		monitor.SetTranscodersNumberAndLoad(totalLoad, totalCapacity, liveTranscodersNum)
-	}
+	rtm.recordLoad()
 
 	<-transcoder.eof
 	glog.Infof("Got transcoder=%s eof, removing from live transcoders map", from)
 
 	rtm.RTmutex.Lock()
 	delete(rtm.liveTranscoders, transcoder.stream)
-	if monitor.Enabled {
-		totalLoad, totalCapacity, liveTranscodersNum = rtm.totalLoadAndCapacity()
-	}
 	rtm.RTmutex.Unlock()
-	if monitor.Enabled {
-		monitor.SetTranscodersNumberAndLoad(totalLoad, totalCapacity, liveTranscodersNum)
-	}
+	rtm.recordLoad()
 }
 
 func removeFromRemoteTranscoders(rt *RemoteTranscoder, remoteTranscoders []*RemoteTranscoder) []*RemoteTranscoder {
@@ -973,17 +1141,86 @@ func (rtm *RemoteTranscoderManager) totalLoadAndCapacity() (int, int, int) {
 	return load, capacity, len(rtm.liveTranscoders)
 }
 
+// capabilityUtilization returns the load per capacity of the live transcoders that support each capability.
+// Capabilities that are no longer supported by any live transcoder have no utilization so that their metric is reset.
+// Caller of this function should hold RTmutex lock
+func (rtm *RemoteTranscoderManager) capabilityUtilization() map[Capability]float64 {
+	utilization := make(map[Capability]float64)
+	for c := range rtm.utilizationCaps {
+		utilization[c] = 0
+	}
+	for c := range CapabilityNameLookup {
+		if c <= Capability_Unused {
+			continue
+		}
+		capStr := NewCapabilityString([]Capability{c})
+		var load, capacity int
+		for _, t := range rtm.liveTranscoders {
+			if t.capabilities != nil && capStr.CompatibleWith(t.capabilities.bitstring) {
+				load += t.load
+				capacity += t.capacity
+			}
+		}
+		if capacity > 0 {
+			utilization[c] = float64(load) / float64(capacity)
+			rtm.utilizationCaps[c] = true
+		}
+	}
+	return utilization
+}
+
+// recordLoad records the load metrics of the live transcoders
+func (rtm *RemoteTranscoderManager) recordLoad() {
+	if !monitor.Enabled {
+		return
+	}
+	rtm.RTmutex.Lock()
+	totalLoad, totalCapacity, liveTranscodersNum := rtm.totalLoadAndCapacity()
+	utilization := rtm.capabilityUtilization()
+	rtm.RTmutex.Unlock()
+
+	monitor.SetTranscodersNumberAndLoad(totalLoad, totalCapacity, liveTranscodersNum)
+	for c, u := range utilization {
+		monitor.TranscodersCapabilityUtilization(CapabilityNameLookup[c], u)
+	}
+}
+
+// updateRealtimeHeadroom adds a segment of duration that was transcoded in transcodeDur to the moving average of
+// 1 - transcode time / segment duration. The average is negative if segments are transcoded slower than realtime
+func (rtm *RemoteTranscoderManager) updateRealtimeHeadroom(transcodeDur, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+	headroom := 1 - transcodeDur.Seconds()/duration.Seconds()
+
+	rtm.RTmutex.Lock()
+	if rtm.realtimeHeadroomSamples == 0 {
+		rtm.realtimeHeadroom = headroom
+	} else {
+		rtm.realtimeHeadroom = realtimeHeadroomWeight*headroom + (1-realtimeHeadroomWeight)*rtm.realtimeHeadroom
+	}
+	rtm.realtimeHeadroomSamples++
+	headroom = rtm.realtimeHeadroom
+	rtm.RTmutex.Unlock()
+
+	if monitor.Enabled {
+		monitor.TranscodersRealtimeHeadroom(headroom)
+	}
+}
+
 // Transcode does actual transcoding using remote transcoder from the pool
 func (rtm *RemoteTranscoderManager) Transcode(ctx context.Context, md *SegTranscodingMetadata) (*TranscodeData, error) {
 	currentTranscoder, err := rtm.selectTranscoder(md.AuthToken.SessionId, md.Caps)
 	if err != nil {
 		return nil, err
 	}
+	rtm.recordLoad()
 	res, err := currentTranscoder.Transcode(ctx, md)
 	if err != nil {
 		rtm.RTmutex.Lock()
 		rtm.completeStreamSession(md.AuthToken.SessionId)
 		rtm.RTmutex.Unlock()
+		rtm.recordLoad()
 	}
 	_, fatal := err.(RemoteTranscoderFatalError)
 	if fatal {
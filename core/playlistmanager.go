@@ -39,6 +39,18 @@ type PlaylistManager interface {
 
 	GetHLSMediaPlaylist(rendition string) *m3u8.MediaPlaylist
 
+	// Tags the segment with seqNo in all media playlists with an EXT-X-DATERANGE tag. A segment can have multiple
+	// tags with different IDs. Segments need to be tagged before they are inserted
+	TagHLSSegment(seqNo uint64, dateRange *DateRange)
+
+	// Sets the SCTE-35 cue tag of the segment with seqNo in all media playlists. Segments need to be cued before they
+	// are inserted
+	CueHLSSegment(seqNo uint64, cue *m3u8.SCTE)
+
+	// Marks the segment with seqNo as the first segment after a discontinuity, i.e. after the input stream
+	// reconnected, in all media playlists. Segments need to be marked before they are inserted
+	DiscontinueHLSSegment(seqNo uint64)
+
 	GetOSSession() drivers.OSSession
 
 	GetRecordOSSession() drivers.OSSession
@@ -59,6 +71,45 @@ type BasicPlaylistManager struct {
 	jsonList           *JsonPlaylist
 	jsonListWriteQueue *drivers.OverwriteQueue
 	jsonListSync       *sync.Mutex
+	// EXT-X-DATERANGE tags by segment seqNo
+	dateRanges map[uint64][]*DateRange
+	// SCTE-35 cue tags by segment seqNo
+	cues map[uint64]*m3u8.SCTE
+	// seqNos of the segments that follow a discontinuity
+	discontinuities map[uint64]bool
+}
+
+// DateRange is an EXT-X-DATERANGE tag that annotates a segment in the media playlists
+type DateRange struct {
+	ID        string
+	Class     string
+	StartDate time.Time
+	// Offset of StartDate from the start of the segment
+	Offset   time.Duration
+	Duration float64
+	// Client defined attributes. The names are prefixed with X- in the tag
+	Attributes map[string]string
+}
+
+func (d *DateRange) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `#EXT-X-DATERANGE:ID="%s"`, d.ID)
+	if d.Class != "" {
+		fmt.Fprintf(&b, `,CLASS="%s"`, d.Class)
+	}
+	fmt.Fprintf(&b, `,START-DATE="%s"`, d.StartDate.Format(m3u8.DATETIME))
+	if d.Duration > 0 {
+		fmt.Fprintf(&b, ",DURATION=%.3f", d.Duration)
+	}
+	names := make([]string, 0, len(d.Attributes))
+	for name := range d.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, `,X-%s="%s"`, strings.ToUpper(name), d.Attributes[name])
+	}
+	return b.String()
 }
 
 type jsonSeg struct {
@@ -223,12 +274,15 @@ func NewBasicPlaylistManager(manifestID ManifestID,
 	storageSession, recordSession drivers.OSSession) *BasicPlaylistManager {
 
 	bplm := &BasicPlaylistManager{
-		storageSession: storageSession,
-		recordSession:  recordSession,
-		manifestID:     manifestID,
-		masterPList:    m3u8.NewMasterPlaylist(),
-		mediaLists:     make(map[string]*m3u8.MediaPlaylist),
-		mapSync:        &sync.RWMutex{},
+		storageSession:  storageSession,
+		recordSession:   recordSession,
+		manifestID:      manifestID,
+		masterPList:     m3u8.NewMasterPlaylist(),
+		mediaLists:      make(map[string]*m3u8.MediaPlaylist),
+		mapSync:         &sync.RWMutex{},
+		dateRanges:      make(map[uint64][]*DateRange),
+		cues:            make(map[uint64]*m3u8.SCTE),
+		discontinuities: make(map[uint64]bool),
 	}
 	if recordSession != nil {
 		bplm.jsonList = NewJSONPlaylist()
@@ -328,6 +382,14 @@ func (mgr *BasicPlaylistManager) InsertHLSSegment(profile *ffmpeg.VideoProfile,
 		return err
 	}
 	mseg := newMediaSegment(uri, duration)
+	mgr.mapSync.RLock()
+	if drs := mgr.dateRanges[seqNo]; len(drs) > 0 {
+		// A playlist with EXT-X-DATERANGE tags needs EXT-X-PROGRAM-DATE-TIME tags
+		mseg.ProgramDateTime = drs[0].StartDate.Add(-drs[0].Offset)
+	}
+	mseg.SCTE = mgr.cues[seqNo]
+	mseg.Discontinuity = mgr.discontinuities[seqNo]
+	mgr.mapSync.RUnlock()
 	if mpl.Count() >= mpl.WinSize() {
 		mpl.Remove()
 	}
@@ -345,7 +407,94 @@ func (mgr *BasicPlaylistManager) GetHLSMasterPlaylist() *m3u8.MasterPlaylist {
 
 // GetHLSMediaPlaylist ...
 func (mgr *BasicPlaylistManager) GetHLSMediaPlaylist(rendition string) *m3u8.MediaPlaylist {
-	return mgr.getPL(rendition)
+	mpl := mgr.getPL(rendition)
+	if mpl != nil {
+		mgr.addDateRanges(mpl)
+	}
+	return mpl
+}
+
+func (mgr *BasicPlaylistManager) TagHLSSegment(seqNo uint64, dateRange *DateRange) {
+	mgr.mapSync.Lock()
+	defer mgr.mapSync.Unlock()
+
+	drs := mgr.dateRanges[seqNo]
+	replaced := false
+	for i, dr := range drs {
+		if dr.ID == dateRange.ID {
+			drs[i] = dateRange
+			replaced = true
+		}
+	}
+	if !replaced {
+		drs = append(drs, dateRange)
+	}
+	mgr.dateRanges[seqNo] = drs
+	// Forget about segments that dropped out of the live playlists
+	for n := range mgr.dateRanges {
+		if n+uint64(LIVE_LIST_LENGTH) < seqNo {
+			delete(mgr.dateRanges, n)
+		}
+	}
+}
+
+func (mgr *BasicPlaylistManager) CueHLSSegment(seqNo uint64, cue *m3u8.SCTE) {
+	mgr.mapSync.Lock()
+	defer mgr.mapSync.Unlock()
+
+	mgr.cues[seqNo] = cue
+	// Forget about segments that dropped out of the live playlists
+	for n := range mgr.cues {
+		if n+uint64(LIVE_LIST_LENGTH) < seqNo {
+			delete(mgr.cues, n)
+		}
+	}
+}
+
+func (mgr *BasicPlaylistManager) DiscontinueHLSSegment(seqNo uint64) {
+	mgr.mapSync.Lock()
+	defer mgr.mapSync.Unlock()
+
+	mgr.discontinuities[seqNo] = true
+	// Forget about segments that dropped out of the live playlists
+	for n := range mgr.discontinuities {
+		if n+uint64(LIVE_LIST_LENGTH) < seqNo {
+			delete(mgr.discontinuities, n)
+		}
+	}
+}
+
+// addDateRanges adds the EXT-X-DATERANGE tags of the tagged segments in mpl to its encoding. The m3u8 package does
+// not support EXT-X-DATERANGE, so the tags are added to the cached encoding of the playlist which is returned by
+// Encode until the playlist changes
+func (mgr *BasicPlaylistManager) addDateRanges(mpl *m3u8.MediaPlaylist) {
+	mgr.mapSync.Lock()
+	defer mgr.mapSync.Unlock()
+
+	if len(mgr.dateRanges) == 0 {
+		return
+	}
+	buf := mpl.Encode()
+	encoded := buf.String()
+	changed := false
+	for _, seg := range mpl.Segments {
+		if seg == nil || seg.ProgramDateTime.IsZero() {
+			continue
+		}
+		pdt := "#EXT-X-PROGRAM-DATE-TIME:" + seg.ProgramDateTime.Format(m3u8.DATETIME) + "\n"
+		for _, dr := range mgr.dateRanges[seg.SeqId] {
+			tag := dr.String() + "\n"
+			if strings.Contains(encoded, tag) {
+				continue
+			}
+			encoded = strings.Replace(encoded, pdt, tag+pdt, 1)
+			changed = true
+		}
+	}
+	if changed {
+		buf.Reset()
+		buf.WriteString(encoded)
+	}
 }
 
 func newMediaSegment(uri string, duration float64) *m3u8.MediaSegment {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"io/ioutil"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -278,6 +279,81 @@ func TestPlaylists(t *testing.T) {
 
 }
 
+func TestPlaylistDateRanges(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewBasicPlaylistManager(RandomManifestID(), nil, nil)
+	vProfile := &ffmpeg.P144p30fps16x9
+	start := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	dr := &DateRange{
+		ID:         "detection-2",
+		Class:      "io.livepeer.detection",
+		StartDate:  start,
+		Duration:   2,
+		Attributes: map[string]string{"scene-classification": "adult:0.900", "a": "b"},
+	}
+	assert.Equal(`#EXT-X-DATERANGE:ID="detection-2",CLASS="io.livepeer.detection",START-DATE="2022-03-01T12:00:00Z",DURATION=2.000,X-A="b",X-SCENE-CLASSIFICATION="adult:0.900"`, dr.String())
+
+	c.TagHLSSegment(2, dr)
+	for i := uint64(1); i <= 3; i++ {
+		assert.Nil(c.InsertHLSSegment(vProfile, i, "seg.ts", 2))
+	}
+
+	// The tag is added before the tagged segment only, together with its program date time
+	pl := c.GetHLSMediaPlaylist(vProfile.Name)
+	expected := "#EXTINF:2.000,\nseg.ts\n" + dr.String() + "\n#EXT-X-PROGRAM-DATE-TIME:2022-03-01T12:00:00Z\n#EXTINF:2.000,\nseg.ts\n#EXTINF:2.000,\nseg.ts\n"
+	assert.Contains(pl.Encode().String(), expected)
+	// Tags are not added twice
+	pl = c.GetHLSMediaPlaylist(vProfile.Name)
+	assert.Equal(1, strings.Count(pl.Encode().String(), "#EXT-X-DATERANGE"))
+
+	// The tag is restored after the playlist changes
+	assert.Nil(c.InsertHLSSegment(vProfile, 4, "seg.ts", 2))
+	pl = c.GetHLSMediaPlaylist(vProfile.Name)
+	assert.Equal(1, strings.Count(pl.Encode().String(), "#EXT-X-DATERANGE"))
+
+	// Segments can have several tags, which replace tags with the same ID, and tags can start after the segment
+	id3 := &DateRange{ID: "id3-5-0", Class: "io.livepeer.id3", StartDate: start.Add(500 * time.Millisecond), Offset: 500 * time.Millisecond}
+	id3Next := &DateRange{ID: "id3-5-1", Class: "io.livepeer.id3", StartDate: start.Add(time.Second), Offset: time.Second}
+	c.TagHLSSegment(5, &DateRange{ID: "id3-5-0"})
+	c.TagHLSSegment(5, id3)
+	c.TagHLSSegment(5, id3Next)
+	assert.Len(c.dateRanges[5], 2)
+	assert.Nil(c.InsertHLSSegment(vProfile, 5, "seg.ts", 2))
+	pl = c.GetHLSMediaPlaylist(vProfile.Name)
+	expected = id3.String() + "\n" + id3Next.String() + "\n#EXT-X-PROGRAM-DATE-TIME:2022-03-01T12:00:00Z\n#EXTINF:2.000,\nseg.ts\n"
+	assert.Contains(pl.Encode().String(), expected)
+
+	// Tags of segments that dropped out of the playlists are removed
+	c.TagHLSSegment(5+uint64(LIVE_LIST_LENGTH)+1, dr)
+	assert.Len(c.dateRanges, 1)
+}
+
+func TestPlaylistCues(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewBasicPlaylistManager(RandomManifestID(), nil, nil)
+	source := &ffmpeg.P144p30fps16x9
+	rendition := &ffmpeg.P240p30fps16x9
+	c.CueHLSSegment(2, &m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_Start, Cue: "/DA=", Time: 4})
+	c.CueHLSSegment(3, &m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_Mid, Cue: "/DA=", Time: 4, Elapsed: 2})
+	c.CueHLSSegment(4, &m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_End})
+	for i := uint64(1); i <= 4; i++ {
+		assert.Nil(c.InsertHLSSegment(source, i, "seg.ts", 2))
+		assert.Nil(c.InsertHLSSegment(rendition, i, "seg.ts", 2))
+	}
+
+	// All renditions are cued
+	expected := "#EXTINF:2.000,\nseg.ts\n#EXT-OATCLS-SCTE35:/DA=\n#EXT-X-CUE-OUT:4\n#EXTINF:2.000,\nseg.ts\n" +
+		"#EXT-X-CUE-OUT-CONT:ElapsedTime=2,Duration=4,SCTE35=/DA=\n#EXTINF:2.000,\nseg.ts\n#EXT-X-CUE-IN\n#EXTINF:2.000,\nseg.ts\n"
+	assert.Contains(c.GetHLSMediaPlaylist(source.Name).Encode().String(), expected)
+	assert.Contains(c.GetHLSMediaPlaylist(rendition.Name).Encode().String(), expected)
+
+	// Cues of segments that dropped out of the playlists are removed
+	c.CueHLSSegment(2+uint64(LIVE_LIST_LENGTH)+1, nil)
+	assert.Len(c.cues, 3)
+}
+
 func TestCleanup(t *testing.T) {
 	vProfile := ffmpeg.P144p30fps16x9
 	hlsStrmID := MakeStreamID(RandomManifestID(), &vProfile)
@@ -0,0 +1,317 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"sync"
+
+	"github.com/livepeer/m3u8"
+)
+
+const (
+	scte35StreamType          = 0x86
+	scte35TableID             = 0xFC
+	spliceInsertCommand       = 0x05
+	timeSignalCommand         = 0x06
+	segmentationDescriptorTag = 0x02
+	// SCTE-35 times are in 90kHz clock ticks
+	scte35Timescale = 90000
+)
+
+// segmentationTypes are the segmentation_type_id values of the segmentation descriptors that start (true) or end
+// (false) a break
+var segmentationTypes = map[byte]bool{
+	0x22: true, 0x23: false, // Break
+	0x30: true, 0x31: false, // Provider advertisement
+	0x32: true, 0x33: false, // Distributor advertisement
+	0x34: true, 0x35: false, // Provider placement opportunity
+	0x36: true, 0x37: false, // Distributor placement opportunity
+	0x44: true, 0x45: false, // Provider ad block
+	0x46: true, 0x47: false, // Distributor ad block
+}
+
+// SpliceCue is a SCTE-35 splice_insert command, or a time_signal command with a segmentation descriptor, that
+// signals the start or the end of a break, i.e. an ad break
+type SpliceCue struct {
+	// EventID is the splice_event_id or the segmentation_event_id of the cue
+	EventID uint32
+	// Out is set for cues that leave the network at the start of a break and unset for cues that return to it
+	Out bool
+	// Duration of the break in seconds, 0 if not signalled
+	Duration float64
+	// Raw is the splice_info_section that carried the command
+	Raw []byte
+}
+
+// SpliceStream is the SCTE-35 stream of an MPEG-TS segment. The transcoder drops data streams, so the packets of the
+// stream are muxed back into the transcoded segments
+type SpliceStream struct {
+	Cues []*SpliceCue
+
+	dataStream
+}
+
+// ParseSpliceStream returns the SCTE-35 stream of the MPEG-TS segment data or nil if the segment has none. The
+// SCTE-35 streams are found through the PAT and the PMTs of the segment, so commands before the PMT are not found.
+// Encrypted and cancelled commands and commands that do not signal a break are not returned as cues. Only the packets
+// of the first SCTE-35 stream of the segment are muxed
+func ParseSpliceStream(data []byte) *SpliceStream {
+	var ss *SpliceStream
+	for _, u := range demuxTS(data) {
+		if u.stream.streamType != scte35StreamType {
+			continue
+		}
+		if ss == nil {
+			ss = &SpliceStream{dataStream: dataStream{streamType: scte35StreamType, pid: u.stream.pid, esInfo: u.stream.esInfo}}
+		}
+		if u.stream.pid == ss.pid {
+			ss.packets = append(ss.packets, u.packets...)
+		}
+		section := psiSection(u.payload)
+		n := psiSectionLen(section)
+		if n == 0 || n > len(section) {
+			continue
+		}
+		if cue := parseSpliceInfo(section[:n]); cue != nil {
+			ss.Cues = append(ss.Cues, cue)
+		}
+	}
+	return ss
+}
+
+// ParseSCTE35 returns the SCTE-35 cues in the MPEG-TS segment data in stream order
+func ParseSCTE35(data []byte) []*SpliceCue {
+	if ss := ParseSpliceStream(data); ss != nil {
+		return ss.Cues
+	}
+	return nil
+}
+
+// Mux returns the MPEG-TS segment data with the SCTE-35 stream added to its PMT and the packets of the stream
+// inserted after the PMT. The splice times of the commands are presentation times, so the cues stay frame accurate as
+// long as the transcoded segment keeps the timestamps of the source. The data is returned unchanged if it is not
+// MPEG-TS, already has a SCTE-35 stream or its PMT does not fit into a single packet
+func (ss *SpliceStream) Mux(data []byte) []byte {
+	if ss == nil {
+		return data
+	}
+	return ss.mux(data)
+}
+
+// parseSpliceInfo returns the cue of a splice_info_section or nil if the section does not signal a break
+func parseSpliceInfo(s []byte) *SpliceCue {
+	if len(s) < 14 || s[0] != scte35TableID {
+		return nil
+	}
+	if s[4]&0x80 != 0 {
+		// Encrypted
+		return nil
+	}
+
+	var cue *SpliceCue
+	switch s[13] {
+	case spliceInsertCommand:
+		cue = parseSpliceInsert(s[14:])
+	case timeSignalCommand:
+		cmdLen := int(s[11]&0x0F)<<8 | int(s[12])
+		if cmdLen == 0xFFF {
+			// Legacy sections do not signal the command length, which is the length of the splice_time
+			cmdLen = 1
+			if len(s) > 14 && s[14]&0x80 != 0 {
+				cmdLen = 5
+			}
+		}
+		if 14+cmdLen > len(s) {
+			return nil
+		}
+		cue = parseSegmentationDescriptors(s[14+cmdLen:])
+	}
+	if cue != nil {
+		cue.Raw = s
+	}
+	return cue
+}
+
+// parseSpliceInsert returns the cue of a splice_insert command or nil if the command is cancelled
+func parseSpliceInsert(c []byte) *SpliceCue {
+	if len(c) < 5 {
+		return nil
+	}
+	cue := &SpliceCue{EventID: binary.BigEndian.Uint32(c)}
+	if c[4]&0x80 != 0 {
+		// Cancelled
+		return nil
+	}
+	if len(c) < 6 {
+		return nil
+	}
+	cue.Out = c[5]&0x80 != 0
+	programSplice := c[5]&0x40 != 0
+	hasDuration := c[5]&0x20 != 0
+	immediate := c[5]&0x10 != 0
+
+	i := 6
+	spliceTimeLen := func() int {
+		if i < len(c) && c[i]&0x80 != 0 {
+			return 5
+		}
+		return 1
+	}
+	if programSplice {
+		if !immediate {
+			i += spliceTimeLen()
+		}
+	} else {
+		if i >= len(c) {
+			return nil
+		}
+		components := int(c[i])
+		i++
+		for n := 0; n < components; n++ {
+			i++
+			if !immediate {
+				i += spliceTimeLen()
+			}
+		}
+	}
+	if hasDuration {
+		if i+5 > len(c) {
+			return nil
+		}
+		ticks := uint64(c[i]&0x01)<<32 | uint64(binary.BigEndian.Uint32(c[i+1:]))
+		cue.Duration = float64(ticks) / scte35Timescale
+	}
+	return cue
+}
+
+// parseSegmentationDescriptors returns the cue of the first segmentation descriptor that signals a break in the
+// descriptor loop d of a splice_info_section, which starts with the length of the loop
+func parseSegmentationDescriptors(d []byte) *SpliceCue {
+	if len(d) < 2 {
+		return nil
+	}
+	n := int(binary.BigEndian.Uint16(d))
+	d = d[2:]
+	if n > len(d) {
+		return nil
+	}
+	d = d[:n]
+	for len(d) >= 2 {
+		tag, n := d[0], int(d[1])
+		if 2+n > len(d) {
+			return nil
+		}
+		if tag == segmentationDescriptorTag {
+			if cue := parseSegmentationDescriptor(d[2 : 2+n]); cue != nil {
+				return cue
+			}
+		}
+		d = d[2+n:]
+	}
+	return nil
+}
+
+// parseSegmentationDescriptor returns the cue of a segmentation_descriptor without its tag and length or nil if the
+// descriptor is cancelled or does not start or end a break
+func parseSegmentationDescriptor(b []byte) *SpliceCue {
+	if len(b) < 9 || string(b[:4]) != "CUEI" {
+		return nil
+	}
+	cue := &SpliceCue{EventID: binary.BigEndian.Uint32(b[4:])}
+	if b[8]&0x80 != 0 {
+		// Cancelled
+		return nil
+	}
+	if len(b) < 10 {
+		return nil
+	}
+	programSegmentation := b[9]&0x80 != 0
+	hasDuration := b[9]&0x40 != 0
+
+	i := 10
+	if !programSegmentation {
+		if i >= len(b) {
+			return nil
+		}
+		i += 1 + 6*int(b[i])
+	}
+	if hasDuration {
+		if i+5 > len(b) {
+			return nil
+		}
+		ticks := uint64(b[i])<<32 | uint64(binary.BigEndian.Uint32(b[i+1:]))
+		cue.Duration = float64(ticks) / scte35Timescale
+		i += 5
+	}
+	// segmentation_upid_type and segmentation_upid_length, followed by the UPID
+	if i+2 > len(b) {
+		return nil
+	}
+	i += 2 + int(b[i+1])
+	if i >= len(b) {
+		return nil
+	}
+	out, ok := segmentationTypes[b[i]]
+	if !ok {
+		return nil
+	}
+	cue.Out = out
+	return cue
+}
+
+// SpliceTracker follows the breaks signalled by the SCTE-35 cues of a stream and returns the HLS cue tags of its
+// segments. Breaks start with an EXT-X-CUE-OUT tag on the segment with the out cue, continue with EXT-X-CUE-OUT-CONT
+// tags and end with an EXT-X-CUE-IN tag on the segment with the return cue or, if the break has a duration and no
+// return cue is signalled, on the first segment after the duration
+type SpliceTracker struct {
+	mu      sync.Mutex
+	cue     *SpliceCue
+	elapsed float64
+}
+
+// NewSpliceTracker returns a SpliceTracker for a stream that is not in a break
+func NewSpliceTracker() *SpliceTracker {
+	return &SpliceTracker{}
+}
+
+// Next returns the cue tag of the next segment of the stream, which has duration and contains cues, or nil if the
+// segment is not part of a break
+func (t *SpliceTracker) Next(cues []*SpliceCue, duration float64) *m3u8.SCTE {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var tag *m3u8.SCTE
+	for _, cue := range cues {
+		if cue.Out {
+			t.cue = cue
+			t.elapsed = 0
+			tag = &m3u8.SCTE{
+				Syntax:  m3u8.SCTE35_OATCLS,
+				CueType: m3u8.SCTE35Cue_Start,
+				Cue:     base64.StdEncoding.EncodeToString(cue.Raw),
+				Time:    cue.Duration,
+			}
+		} else if t.cue != nil {
+			t.cue = nil
+			tag = &m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_End}
+		}
+	}
+
+	if tag == nil && t.cue != nil {
+		if t.cue.Duration > 0 && t.elapsed >= t.cue.Duration {
+			t.cue = nil
+			return &m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_End}
+		}
+		tag = &m3u8.SCTE{
+			Syntax:  m3u8.SCTE35_OATCLS,
+			CueType: m3u8.SCTE35Cue_Mid,
+			Cue:     base64.StdEncoding.EncodeToString(t.cue.Raw),
+			Time:    t.cue.Duration,
+			Elapsed: t.elapsed,
+		}
+	}
+	if t.cue != nil {
+		t.elapsed += duration
+	}
+	return tag
+}
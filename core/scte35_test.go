@@ -0,0 +1,234 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+
+	"github.com/livepeer/m3u8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPMTPID    = 0x1000
+	testVideoPID  = 0x100
+	testSCTE35PID = 0x1F4
+)
+
+func tsPacket(pid uint16, pusi bool, payload []byte) []byte {
+	pkt := make([]byte, tsPacketSize)
+	for i := range pkt {
+		pkt[i] = 0xFF
+	}
+	pkt[0] = tsSyncByte
+	pkt[1] = byte(pid >> 8 & 0x1F)
+	if pusi {
+		pkt[1] |= 0x40
+		// Pointer field
+		payload = append([]byte{0}, payload...)
+	}
+	pkt[2] = byte(pid)
+	pkt[3] = 0x10
+	copy(pkt[4:], payload)
+	return pkt
+}
+
+func testPAT() []byte {
+	return []byte{0x00, 0xB0, 13, 0x00, 0x01, 0xC1, 0x00, 0x00,
+		0x00, 0x01, 0xE0 | testPMTPID>>8, testPMTPID & 0xFF,
+		0, 0, 0, 0}
+}
+
+func testPMT() []byte {
+	return []byte{0x02, 0xB0, 23, 0x00, 0x01, 0xC1, 0x00, 0x00,
+		0xE0 | testVideoPID>>8, testVideoPID & 0xFF, 0xF0, 0x00,
+		0x1B, 0xE0 | testVideoPID>>8, testVideoPID & 0xFF, 0xF0, 0x00,
+		scte35StreamType, 0xE0 | testSCTE35PID>>8, testSCTE35PID & 0xFF, 0xF0, 0x00,
+		0, 0, 0, 0}
+}
+
+// spliceInsert returns a splice_info_section with a program splice_insert command
+func spliceInsert(eventID uint32, out bool, durationTicks uint64) []byte {
+	cmd := make([]byte, 4)
+	binary.BigEndian.PutUint32(cmd, eventID)
+	flags := byte(0x40 | 0x0F)
+	if out {
+		flags |= 0x80
+	}
+	if durationTicks > 0 {
+		flags |= 0x20
+	}
+	cmd = append(cmd, 0x7F, flags)
+	// splice_time with time_specified_flag set
+	cmd = append(cmd, 0xFE, 0, 0, 0, 0)
+	if durationTicks > 0 {
+		cmd = append(cmd, 0xFE|byte(durationTicks>>32&0x01), 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(cmd[len(cmd)-4:], uint32(durationTicks))
+	}
+	cmd = append(cmd, 0, 1, 0, 0)
+
+	s := []byte{scte35TableID, 0x30, 0, 0, 0, 0, 0, 0, 0, 0, 0xFF, 0xF0 | byte(len(cmd)>>8), byte(len(cmd)), spliceInsertCommand}
+	s = append(s, cmd...)
+	// Empty descriptor loop and CRC
+	s = append(s, 0, 0, 0, 0, 0, 0)
+	s[2] = byte(len(s) - 3)
+	return s
+}
+
+// timeSignal returns a splice_info_section with a time_signal command and a program segmentation descriptor
+func timeSignal(eventID uint32, segmentationType byte, durationTicks uint64) []byte {
+	desc := []byte{'C', 'U', 'E', 'I', 0, 0, 0, 0, 0x7F}
+	binary.BigEndian.PutUint32(desc[4:], eventID)
+	if durationTicks > 0 {
+		desc = append(desc, 0xC0|0x3F, byte(durationTicks>>32), 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(desc[len(desc)-4:], uint32(durationTicks))
+	} else {
+		desc = append(desc, 0x80|0x3F)
+	}
+	// An ad ID UPID, the segmentation type, segment_num and segments_expected
+	desc = append(desc, 0x03, 4, 'A', 'D', 'I', 'D', segmentationType, 1, 1)
+	desc = append([]byte{segmentationDescriptorTag, byte(len(desc))}, desc...)
+
+	// splice_time with time_specified_flag set
+	cmd := []byte{0xFE, 0, 0, 0, 0}
+	s := []byte{scte35TableID, 0x30, 0, 0, 0, 0, 0, 0, 0, 0, 0xFF, 0xF0 | byte(len(cmd)>>8), byte(len(cmd)), timeSignalCommand}
+	s = append(s, cmd...)
+	s = append(s, byte(len(desc)>>8), byte(len(desc)))
+	s = append(s, desc...)
+	// CRC
+	s = append(s, 0, 0, 0, 0)
+	s[2] = byte(len(s) - 3)
+	return s
+}
+
+func testSegment(sections ...[]byte) []byte {
+	var data []byte
+	data = append(data, tsPacket(0, true, testPAT())...)
+	data = append(data, tsPacket(testPMTPID, true, testPMT())...)
+	data = append(data, tsPacket(testVideoPID, true, []byte{0, 0, 1, 0xE0})...)
+	for _, s := range sections {
+		data = append(data, tsPacket(testSCTE35PID, true, s)...)
+	}
+	return data
+}
+
+func TestParseSCTE35(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	assert.Nil(ParseSCTE35(nil))
+	assert.Nil(ParseSCTE35([]byte("not a transport stream")))
+	assert.Nil(ParseSCTE35(testSegment()))
+
+	out := spliceInsert(42, true, 30*scte35Timescale)
+	in := spliceInsert(42, false, 0)
+	cues := ParseSCTE35(testSegment(out, in))
+	require.Len(cues, 2)
+	assert.Equal(&SpliceCue{EventID: 42, Out: true, Duration: 30, Raw: out}, cues[0])
+	assert.Equal(&SpliceCue{EventID: 42, Raw: in}, cues[1])
+
+	// Cancelled commands are skipped
+	cancelled := spliceInsert(43, true, 0)
+	cancelled[18] = 0xFF
+	assert.Empty(ParseSCTE35(testSegment(cancelled)))
+
+	// Time signals with segmentation descriptors that start and end breaks are cues
+	tsOut := timeSignal(44, 0x34, 60*scte35Timescale)
+	tsIn := timeSignal(44, 0x35, 0)
+	cues = ParseSCTE35(testSegment(tsOut, tsIn))
+	require.Len(cues, 2)
+	assert.Equal(&SpliceCue{EventID: 44, Out: true, Duration: 60, Raw: tsOut}, cues[0])
+	assert.Equal(&SpliceCue{EventID: 44, Raw: tsIn}, cues[1])
+	// Legacy sections without the command length
+	legacy := timeSignal(45, 0x22, 0)
+	legacy[11], legacy[12] = 0xFF, 0xFF
+	cues = ParseSCTE35(testSegment(legacy))
+	require.Len(cues, 1)
+	assert.True(cues[0].Out)
+
+	// Time signals without segmentation descriptors, with other segmentation types or cancelled are skipped
+	noDesc := spliceInsert(46, true, 0)
+	noDesc[13] = timeSignalCommand
+	assert.Empty(ParseSCTE35(testSegment(noDesc)))
+	assert.Empty(ParseSCTE35(testSegment(timeSignal(47, 0x10, 0))))
+	cancelledSignal := timeSignal(48, 0x30, 0)
+	cancelledSignal[31] = 0xFF
+	assert.Empty(ParseSCTE35(testSegment(cancelledSignal)))
+
+	// Other commands are skipped
+	other := spliceInsert(49, true, 0)
+	other[13] = 0x07
+	assert.Empty(ParseSCTE35(testSegment(other)))
+
+	// SCTE-35 packets are only found through the PMT
+	data := append(tsPacket(testSCTE35PID, true, out), testSegment()...)
+	assert.Empty(ParseSCTE35(data))
+}
+
+func TestSpliceStream_Mux(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	assert.Nil(ParseSpliceStream(testSegment()))
+	out := spliceInsert(42, true, 30*scte35Timescale)
+	source := testSegment(out, timeSignal(42, 0x35, 0))
+	ss := ParseSpliceStream(source)
+	require.NotNil(ss)
+	require.Len(ss.Cues, 2)
+
+	// The SCTE-35 stream is added to the PMT of the rendition and its packets follow the PMT
+	rendition := id3Segment(testVideoPID, pmtEntry(0x1B, testVideoPID, nil))
+	assert.Empty(ParseSCTE35(rendition))
+	muxed := ss.Mux(rendition)
+	require.Len(muxed, len(rendition)+2*tsPacketSize)
+	assert.Equal(uint16(testSCTE35PID), tsPID(muxed[2*tsPacketSize:]))
+	assert.Equal(ss.Cues, ParseSCTE35(muxed))
+
+	// Timed metadata and SCTE-35 streams are muxed into the same rendition
+	tm := ParseTimedMetadata(append(id3Segment(testVideoPID, pmtEntry(0x1B, testVideoPID, nil), pmtEntry(id3StreamType, testID3PID, testID3Descriptor)),
+		pesTSPacket(testID3PID, pesPacket(0xBD, 900000, []byte("ID3tag")))...))
+	require.NotNil(tm)
+	muxed = ss.Mux(tm.Mux(rendition))
+	assert.Equal(ss.Cues, ParseSCTE35(muxed))
+	require.NotNil(ParseTimedMetadata(muxed))
+	assert.Equal(tm.Tags, ParseTimedMetadata(muxed).Tags)
+
+	// Segments that already have a SCTE-35 stream and other formats are not changed
+	assert.Equal(source, ss.Mux(source))
+	assert.Equal([]byte("mp4"), ss.Mux([]byte("mp4")))
+	var none *SpliceStream
+	assert.Equal(rendition, none.Mux(rendition))
+}
+
+func TestSpliceTracker(t *testing.T) {
+	assert := assert.New(t)
+
+	out := &SpliceCue{EventID: 1, Out: true, Duration: 5, Raw: []byte("out")}
+	in := &SpliceCue{EventID: 1}
+	cue := base64.StdEncoding.EncodeToString(out.Raw)
+
+	tr := NewSpliceTracker()
+	assert.Nil(tr.Next(nil, 2))
+	// Return cues outside of breaks are ignored
+	assert.Nil(tr.Next([]*SpliceCue{in}, 2))
+
+	assert.Equal(&m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_Start, Cue: cue, Time: 5}, tr.Next([]*SpliceCue{out}, 2))
+	assert.Equal(&m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_Mid, Cue: cue, Time: 5, Elapsed: 2}, tr.Next(nil, 2))
+	assert.Equal(&m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_Mid, Cue: cue, Time: 5, Elapsed: 4}, tr.Next(nil, 2))
+	// The break ends after its duration without a return cue
+	assert.Equal(&m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_End}, tr.Next(nil, 2))
+	assert.Nil(tr.Next(nil, 2))
+
+	// The break ends with the return cue
+	tr.Next([]*SpliceCue{out}, 2)
+	assert.Equal(&m3u8.SCTE{Syntax: m3u8.SCTE35_OATCLS, CueType: m3u8.SCTE35Cue_End}, tr.Next([]*SpliceCue{in}, 2))
+	assert.Nil(tr.Next(nil, 2))
+
+	// Breaks without a duration last until the return cue
+	tr.Next([]*SpliceCue{{Out: true}}, 2)
+	for i := 0; i < 10; i++ {
+		assert.Equal(m3u8.SCTE35Cue_Mid, tr.Next(nil, 2).CueType)
+	}
+	assert.Equal(m3u8.SCTE35Cue_End, tr.Next([]*SpliceCue{in}, 2).CueType)
+}
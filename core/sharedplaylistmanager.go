@@ -0,0 +1,224 @@
+package core
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/drivers"
+	ffmpeg "github.com/livepeer/lpms/ffmpeg"
+	"github.com/livepeer/m3u8"
+)
+
+// SharedStreamTimeout is the time after which the playlists of a stream are no longer served by the other broadcasters
+// if the stream is not updated, i.e. because its ingest node went down without cleaning up
+var SharedStreamTimeout = time.Minute
+
+// SharedPlaylistWriteInterval is the minimum time between two writes of the playlists of a stream to the StreamStore.
+// The segments inserted in between are written together by the next write
+var SharedPlaylistWriteInterval = time.Second
+
+// StreamStore stores the state of streams so that it can be shared by the broadcasters of a cluster
+type StreamStore interface {
+	UpdateStream(stream *common.DBStream) error
+	FindStream(manifestID string) (*common.DBStream, error)
+	DeleteStream(manifestID, nodeID string) error
+}
+
+// SharedPlaylistManager is a PlaylistManager that stores the live playlists of a stream in a StreamStore after
+// segments are inserted. The other broadcasters that use the store can then serve the playlists of the stream and take
+// over the stream if the broadcaster that ingests it goes down. The playlists are written in the background so that
+// inserting a segment does not wait for the store
+type SharedPlaylistManager struct {
+	*BasicPlaylistManager
+	store  StreamStore
+	nodeID string
+
+	mu        sync.Mutex
+	lastSeqNo uint64
+	resumed   bool
+
+	dirty    chan struct{}
+	quit     chan struct{}
+	done     chan struct{}
+	quitOnce sync.Once
+}
+
+// NewSharedPlaylistManager creates a SharedPlaylistManager for the stream ingested by nodeID. If the store has state
+// for the stream, i.e. it was ingested by another node before, the live playlists of the stream are restored
+func NewSharedPlaylistManager(manifestID ManifestID, storageSession, recordSession drivers.OSSession,
+	store StreamStore, nodeID string) (*SharedPlaylistManager, error) {
+
+	mgr := &SharedPlaylistManager{
+		BasicPlaylistManager: NewBasicPlaylistManager(manifestID, storageSession, recordSession),
+		store:                store,
+		nodeID:               nodeID,
+		dirty:                make(chan struct{}, 1),
+		quit:                 make(chan struct{}),
+		done:                 make(chan struct{}),
+	}
+
+	stream, err := store.FindStream(string(manifestID))
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil {
+		go mgr.writeLoop()
+		return mgr, nil
+	}
+
+	master, media, err := decodeSharedPlaylists(stream)
+	if err != nil {
+		return nil, err
+	}
+	mgr.masterPList = master
+	mgr.mediaLists = media
+	mgr.lastSeqNo = stream.LastSeqNo
+	mgr.resumed = true
+	glog.Infof("Resumed shared stream manifestID=%s lastSeqNo=%d previous nodeID=%s", manifestID, stream.LastSeqNo, stream.NodeID)
+
+	go mgr.writeLoop()
+	return mgr, nil
+}
+
+// LastSeqNo returns the sequence number of the last segment of the stream if the stream was resumed from the store
+func (mgr *SharedPlaylistManager) LastSeqNo() (uint64, bool) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	return mgr.lastSeqNo, mgr.resumed
+}
+
+func (mgr *SharedPlaylistManager) InsertHLSSegment(profile *ffmpeg.VideoProfile, seqNo uint64, uri string,
+	duration float64) error {
+
+	if err := mgr.BasicPlaylistManager.InsertHLSSegment(profile, seqNo, uri, duration); err != nil {
+		return err
+	}
+
+	mgr.mu.Lock()
+	if seqNo > mgr.lastSeqNo {
+		mgr.lastSeqNo = seqNo
+	}
+	mgr.mu.Unlock()
+
+	select {
+	case mgr.dirty <- struct{}{}:
+	default:
+		// A write is already pending and includes this segment
+	}
+	return nil
+}
+
+// writeLoop writes the playlists to the store after segments are inserted, at most once per
+// SharedPlaylistWriteInterval
+func (mgr *SharedPlaylistManager) writeLoop() {
+	defer close(mgr.done)
+	for {
+		select {
+		case <-mgr.dirty:
+		case <-mgr.quit:
+			return
+		}
+		mgr.write()
+
+		select {
+		case <-time.After(SharedPlaylistWriteInterval):
+		case <-mgr.quit:
+			return
+		}
+	}
+}
+
+func (mgr *SharedPlaylistManager) write() {
+	mgr.mu.Lock()
+	lastSeqNo := mgr.lastSeqNo
+	mgr.mu.Unlock()
+
+	stream := &common.DBStream{
+		ManifestID:     string(mgr.manifestID),
+		NodeID:         mgr.nodeID,
+		LastSeqNo:      lastSeqNo,
+		MasterPlaylist: mgr.GetHLSMasterPlaylist().String(),
+		MediaPlaylists: make(map[string]string),
+	}
+	mgr.mapSync.RLock()
+	renditions := make([]string, 0, len(mgr.mediaLists))
+	for rendition := range mgr.mediaLists {
+		renditions = append(renditions, rendition)
+	}
+	mgr.mapSync.RUnlock()
+	for _, rendition := range renditions {
+		stream.MediaPlaylists[rendition] = mgr.GetHLSMediaPlaylist(rendition).String()
+	}
+
+	if err := mgr.store.UpdateStream(stream); err != nil {
+		// The stream continues on this node even if the other nodes cannot see it
+		glog.Errorf("Error storing shared stream manifestID=%s seqNo=%d err=%q", mgr.manifestID, lastSeqNo, err)
+	}
+}
+
+func (mgr *SharedPlaylistManager) Cleanup() {
+	mgr.quitOnce.Do(func() { close(mgr.quit) })
+	<-mgr.done
+	mgr.BasicPlaylistManager.Cleanup()
+	if err := mgr.store.DeleteStream(string(mgr.manifestID), mgr.nodeID); err != nil {
+		glog.Errorf("Error deleting shared stream manifestID=%s err=%q", mgr.manifestID, err)
+	}
+}
+
+// SharedMasterPlaylist returns the master playlist of the stream with manifestID from store or nil if the stream is
+// unknown
+func SharedMasterPlaylist(store StreamStore, manifestID ManifestID) (*m3u8.MasterPlaylist, error) {
+	stream, err := findLiveStream(store, manifestID)
+	if err != nil || stream == nil {
+		return nil, err
+	}
+	master, _, err := decodeSharedPlaylists(stream)
+	return master, err
+}
+
+// SharedMediaPlaylist returns the media playlist of a rendition of the stream with manifestID from store or nil if the
+// stream or the rendition is unknown
+func SharedMediaPlaylist(store StreamStore, manifestID ManifestID, rendition string) (*m3u8.MediaPlaylist, error) {
+	stream, err := findLiveStream(store, manifestID)
+	if err != nil || stream == nil {
+		return nil, err
+	}
+	_, media, err := decodeSharedPlaylists(stream)
+	if err != nil {
+		return nil, err
+	}
+	return media[rendition], nil
+}
+
+func findLiveStream(store StreamStore, manifestID ManifestID) (*common.DBStream, error) {
+	stream, err := store.FindStream(string(manifestID))
+	if err != nil || stream == nil {
+		return nil, err
+	}
+	if time.Since(stream.UpdatedAt) > SharedStreamTimeout {
+		return nil, nil
+	}
+	return stream, nil
+}
+
+func decodeSharedPlaylists(stream *common.DBStream) (*m3u8.MasterPlaylist, map[string]*m3u8.MediaPlaylist, error) {
+	master := m3u8.NewMasterPlaylist()
+	if err := master.DecodeFrom(strings.NewReader(stream.MasterPlaylist), true); err != nil {
+		return nil, nil, err
+	}
+	media := make(map[string]*m3u8.MediaPlaylist)
+	for rendition, encoded := range stream.MediaPlaylists {
+		mpl, err := m3u8.NewMediaPlaylist(LIVE_LIST_LENGTH, LIVE_LIST_LENGTH)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := mpl.DecodeFrom(strings.NewReader(encoded), true); err != nil {
+			return nil, nil, err
+		}
+		media[rendition] = mpl
+	}
+	return master, media, nil
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/livepeer/go-livepeer/common"
+	ffmpeg "github.com/livepeer/lpms/ffmpeg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedPlaylistManager(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	mid := ManifestID("shared")
+	vProfile := ffmpeg.P144p30fps16x9
+	vProfile.Name = "source"
+
+	// The ingest node stores the playlists of the stream
+	pl1, err := NewSharedPlaylistManager(mid, nil, nil, dbh, "node1")
+	require.Nil(err)
+	_, resumed := pl1.LastSeqNo()
+	assert.False(resumed)
+	require.Nil(pl1.InsertHLSSegment(&vProfile, 1, "http://store/shared/source/1.ts", 2))
+	require.Nil(pl1.InsertHLSSegment(&vProfile, 2, "http://store/shared/source/2.ts", 2))
+	waitForSharedStream(t, dbh, mid, "node1", 2)
+
+	// Other nodes serve the playlists
+	master, err := SharedMasterPlaylist(dbh, mid)
+	require.Nil(err)
+	require.Len(master.Variants, 1)
+	assert.Equal("shared/source.m3u8", master.Variants[0].URI)
+
+	mpl, err := SharedMediaPlaylist(dbh, mid, "source")
+	require.Nil(err)
+	assert.Equal(pl1.GetHLSMediaPlaylist("source").String(), mpl.String())
+
+	mpl, err = SharedMediaPlaylist(dbh, mid, "unknown")
+	assert.Nil(err)
+	assert.Nil(mpl)
+	master, err = SharedMasterPlaylist(dbh, "unknown")
+	assert.Nil(err)
+	assert.Nil(master)
+
+	// Playlists of streams that are not updated are not served
+	defer func(timeout time.Duration) { SharedStreamTimeout = timeout }(SharedStreamTimeout)
+	SharedStreamTimeout = -time.Second
+	master, err = SharedMasterPlaylist(dbh, mid)
+	assert.Nil(err)
+	assert.Nil(master)
+	mpl, err = SharedMediaPlaylist(dbh, mid, "source")
+	assert.Nil(err)
+	assert.Nil(mpl)
+
+	// Another node takes over the stream and continues the playlists
+	pl2, err := NewSharedPlaylistManager(mid, nil, nil, dbh, "node2")
+	require.Nil(err)
+	lastSeqNo, resumed := pl2.LastSeqNo()
+	assert.True(resumed)
+	assert.Equal(uint64(2), lastSeqNo)
+	require.Nil(pl2.InsertHLSSegment(&vProfile, 3, "http://store/shared/source/3.ts", 2))
+	waitForSharedStream(t, dbh, mid, "node2", 3)
+
+	mpl = pl2.GetHLSMediaPlaylist("source")
+	require.NotNil(mpl)
+	assert.Equal(uint(3), mpl.Count())
+	assert.Equal("http://store/shared/source/3.ts", mpl.Segments[2].URI)
+	assert.Len(pl2.GetHLSMasterPlaylist().Variants, 1)
+
+	// The previous ingest node does not delete the stream when it cleans up
+	pl1.Cleanup()
+	stream, err := dbh.FindStream(string(mid))
+	require.Nil(err)
+	assert.NotNil(stream)
+
+	pl2.Cleanup()
+	stream, err = dbh.FindStream(string(mid))
+	assert.Nil(err)
+	assert.Nil(stream)
+}
+
+type countingStreamStore struct {
+	StreamStore
+	mu      sync.Mutex
+	updates []*common.DBStream
+}
+
+func (s *countingStreamStore) UpdateStream(stream *common.DBStream) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updates = append(s.updates, stream)
+	return s.StreamStore.UpdateStream(stream)
+}
+
+func (s *countingStreamStore) updateCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.updates)
+}
+
+func TestSharedPlaylistManager_CoalescesWrites(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+	store := &countingStreamStore{StreamStore: dbh}
+
+	defer func(interval time.Duration) { SharedPlaylistWriteInterval = interval }(SharedPlaylistWriteInterval)
+	SharedPlaylistWriteInterval = 200 * time.Millisecond
+
+	mid := ManifestID("coalesced")
+	vProfile := ffmpeg.P144p30fps16x9
+	vProfile.Name = "source"
+	pl, err := NewSharedPlaylistManager(mid, nil, nil, store, "node1")
+	require.Nil(err)
+
+	// Segments inserted within the write interval are written together
+	require.Nil(pl.InsertHLSSegment(&vProfile, 1, "http://store/coalesced/source/1.ts", 2))
+	require.Eventually(func() bool { return store.updateCount() == 1 }, time.Second, 10*time.Millisecond)
+	for seqNo := uint64(2); seqNo <= 5; seqNo++ {
+		require.Nil(pl.InsertHLSSegment(&vProfile, seqNo, fmt.Sprintf("http://store/coalesced/source/%d.ts", seqNo), 2))
+	}
+	assert.Equal(1, store.updateCount())
+	waitForSharedStream(t, dbh, mid, "node1", 5)
+	assert.Equal(2, store.updateCount())
+
+	// Cleaning up does not wait for the write interval
+	start := time.Now()
+	pl.Cleanup()
+	assert.Less(int64(time.Since(start)), int64(SharedPlaylistWriteInterval))
+	stream, err := dbh.FindStream(string(mid))
+	assert.Nil(err)
+	assert.Nil(stream)
+}
+
+func waitForSharedStream(t *testing.T, store StreamStore, mid ManifestID, nodeID string, lastSeqNo uint64) {
+	require.Eventually(t, func() bool {
+		stream, err := store.FindStream(string(mid))
+		return err == nil && stream != nil && stream.NodeID == nodeID && stream.LastSeqNo == lastSeqNo
+	}, 3*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,296 @@
+package core
+
+import (
+	"sort"
+	"time"
+)
+
+// H.264 video in MPEG-TS
+const h264StreamType = 0x1B
+
+// SourceInfo describes the video of a source segment
+type SourceInfo struct {
+	Width  int
+	Height int
+	// Framerate in frames per second, 0 if unknown
+	Framerate float64
+	// Bitrate of the segment in bits per second, 0 if unknown
+	Bitrate int
+}
+
+// ProbeSegment returns the resolution and framerate of the H.264 video of the MPEG-TS segment data and the bitrate of
+// the segment, given its duration in seconds. The resolution is read from the first sequence parameter set of the
+// video and the framerate is estimated from the timestamps of its frames. Returns nil if the segment has no H.264
+// video with a sequence parameter set
+func ProbeSegment(data []byte, duration float64) *SourceInfo {
+	var info *SourceInfo
+	var pid uint16
+	var pts []uint64
+
+	for _, u := range demuxTS(data) {
+		if u.stream.streamType != h264StreamType || (info != nil && u.stream.pid != pid) {
+			continue
+		}
+		if info == nil {
+			w, h, ok := h264Resolution(pesPayload(u.payload))
+			if !ok {
+				continue
+			}
+			info = &SourceInfo{Width: w, Height: h}
+			pid = u.stream.pid
+		}
+		if t, ok := pesPTS(u.payload); ok {
+			pts = append(pts, t)
+		}
+	}
+	if info == nil {
+		return nil
+	}
+
+	// Frames are reordered, so the frame interval is estimated from the span of the sorted timestamps
+	sort.Slice(pts, func(i, j int) bool { return pts[i] < pts[j] })
+	if n := len(pts); n > 1 && pts[n-1] > pts[0] {
+		info.Framerate = float64(n-1) * 90000 / float64(pts[n-1]-pts[0])
+	}
+	if duration > 0 {
+		info.Bitrate = int(float64(len(data)*8) / duration)
+	}
+	return info
+}
+
+// SegmentDuration returns the duration of the H.264 video of the MPEG-TS segment data, which is the span of the
+// timestamps of its frames plus the interval of one frame. Returns false if the segment has no H.264 video with at
+// least two timestamped frames
+func SegmentDuration(data []byte) (time.Duration, bool) {
+	var pid uint16
+	var pts []uint64
+	for _, u := range demuxTS(data) {
+		if u.stream.streamType != h264StreamType || (len(pts) > 0 && u.stream.pid != pid) {
+			continue
+		}
+		if t, ok := pesPTS(u.payload); ok {
+			pid = u.stream.pid
+			pts = append(pts, t)
+		}
+	}
+
+	sort.Slice(pts, func(i, j int) bool { return pts[i] < pts[j] })
+	n := len(pts)
+	if n < 2 || pts[n-1] <= pts[0] {
+		return 0, false
+	}
+	span := pts[n-1] - pts[0]
+	ticks := span + span/uint64(n-1)
+	return time.Duration(ticks) * time.Second / 90000, true
+}
+
+// SegmentPixels returns the number of pixels of the H.264 video of the MPEG-TS segment data, which is the number of
+// its timestamped frames times the resolution of its first sequence parameter set. Returns 0 if the segment has no
+// H.264 video with a sequence parameter set
+func SegmentPixels(data []byte) int64 {
+	var pid uint16
+	var w, h int
+	var frames int64
+	for _, u := range demuxTS(data) {
+		if u.stream.streamType != h264StreamType || (w > 0 && u.stream.pid != pid) {
+			continue
+		}
+		if w == 0 {
+			var ok bool
+			if w, h, ok = h264Resolution(pesPayload(u.payload)); !ok {
+				w = 0
+				continue
+			}
+			pid = u.stream.pid
+		}
+		if _, ok := pesPTS(u.payload); ok {
+			frames++
+		}
+	}
+	return frames * int64(w) * int64(h)
+}
+
+// h264Resolution returns the cropped resolution of the first sequence parameter set in the H.264 Annex B stream data
+func h264Resolution(data []byte) (int, int, bool) {
+	for _, nal := range splitNALUnits(data) {
+		if len(nal) > 1 && nal[0]&0x1F == 7 {
+			return parseSPSResolution(unescapeRBSP(nal[1:]))
+		}
+	}
+	return 0, 0, false
+}
+
+// splitNALUnits splits H.264 Annex B data into NAL units at the start codes
+func splitNALUnits(data []byte) [][]byte {
+	var nals [][]byte
+	start := -1
+	for i := 0; i+2 < len(data); i++ {
+		if data[i] != 0 || data[i+1] != 0 || data[i+2] != 1 {
+			continue
+		}
+		if start >= 0 {
+			end := i
+			// The zero byte of a 4 byte start code belongs to the next start code
+			if end > start && data[end-1] == 0 {
+				end--
+			}
+			nals = append(nals, data[start:end])
+		}
+		start = i + 3
+		i += 2
+	}
+	if start >= 0 && start < len(data) {
+		nals = append(nals, data[start:])
+	}
+	return nals
+}
+
+// unescapeRBSP removes the emulation prevention bytes of a NAL unit
+func unescapeRBSP(data []byte) []byte {
+	rbsp := make([]byte, 0, len(data))
+	zeros := 0
+	for _, b := range data {
+		if zeros >= 2 && b == 3 {
+			zeros = 0
+			continue
+		}
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		rbsp = append(rbsp, b)
+	}
+	return rbsp
+}
+
+// parseSPSResolution returns the cropped resolution of the H.264 sequence parameter set sps, without its NAL header
+func parseSPSResolution(sps []byte) (int, int, bool) {
+	r := &bitReader{data: sps}
+	profileIdc := r.bits(8)
+	r.bits(16) // constraint flags and level
+	r.ue()     // seq_parameter_set_id
+
+	chromaFormatIdc := uint(1)
+	separateColourPlane := false
+	switch profileIdc {
+	case 100, 110, 122, 244, 44, 83, 86, 118, 128, 138, 139, 134, 135:
+		chromaFormatIdc = r.ue()
+		if chromaFormatIdc == 3 {
+			separateColourPlane = r.bits(1) == 1
+		}
+		r.ue()    // bit_depth_luma_minus8
+		r.ue()    // bit_depth_chroma_minus8
+		r.bits(1) // qpprime_y_zero_transform_bypass_flag
+		if r.bits(1) == 1 {
+			lists := 8
+			if chromaFormatIdc == 3 {
+				lists = 12
+			}
+			for i := 0; i < lists; i++ {
+				if r.bits(1) == 0 {
+					continue
+				}
+				size := 16
+				if i >= 6 {
+					size = 64
+				}
+				// Skip the scaling list
+				last, next := 8, 8
+				for j := 0; j < size; j++ {
+					if next != 0 {
+						next = (last + r.se() + 256) % 256
+					}
+					if next != 0 {
+						last = next
+					}
+				}
+			}
+		}
+	}
+
+	r.ue() // log2_max_frame_num_minus4
+	switch r.ue() {
+	case 0:
+		r.ue() // log2_max_pic_order_cnt_lsb_minus4
+	case 1:
+		r.bits(1) // delta_pic_order_always_zero_flag
+		r.se()    // offset_for_non_ref_pic
+		r.se()    // offset_for_top_to_bottom_field
+		for i := r.ue(); i > 0 && !r.err; i-- {
+			r.se() // offset_for_ref_frame
+		}
+	}
+	r.ue()    // max_num_ref_frames
+	r.bits(1) // gaps_in_frame_num_value_allowed_flag
+	widthInMbs := r.ue() + 1
+	heightInMapUnits := r.ue() + 1
+	frameMbsOnly := r.bits(1)
+	if frameMbsOnly == 0 {
+		r.bits(1) // mb_adaptive_frame_field_flag
+	}
+	r.bits(1) // direct_8x8_inference_flag
+
+	width := int(widthInMbs) * 16
+	height := int(2-frameMbsOnly) * int(heightInMapUnits) * 16
+	if r.bits(1) == 1 {
+		left, right, top, bottom := r.ue(), r.ue(), r.ue(), r.ue()
+		cropX, cropY := 1, int(2-frameMbsOnly)
+		if chromaFormatIdc != 0 && !separateColourPlane {
+			if chromaFormatIdc < 3 {
+				cropX = 2
+			}
+			if chromaFormatIdc == 1 {
+				cropY *= 2
+			}
+		}
+		width -= int(left+right) * cropX
+		height -= int(top+bottom) * cropY
+	}
+	if r.err || width <= 0 || height <= 0 {
+		return 0, 0, false
+	}
+	return width, height, true
+}
+
+// bitReader reads the fields of an H.264 RBSP. err is set if a read goes past the end of data
+type bitReader struct {
+	data []byte
+	pos  int
+	err  bool
+}
+
+func (r *bitReader) bits(n int) uint {
+	var v uint
+	for i := 0; i < n; i++ {
+		if r.pos >= len(r.data)*8 {
+			r.err = true
+			return 0
+		}
+		v = v<<1 | uint(r.data[r.pos/8]>>(7-r.pos%8)&1)
+		r.pos++
+	}
+	return v
+}
+
+// ue reads an unsigned Exp-Golomb coded field
+func (r *bitReader) ue() uint {
+	zeros := 0
+	for r.bits(1) == 0 {
+		if r.err || zeros >= 32 {
+			r.err = true
+			return 0
+		}
+		zeros++
+	}
+	return 1<<uint(zeros) - 1 + r.bits(zeros)
+}
+
+// se reads a signed Exp-Golomb coded field
+func (r *bitReader) se() int {
+	v := r.ue()
+	if v%2 == 1 {
+		return int(v+1) / 2
+	}
+	return -int(v / 2)
+}
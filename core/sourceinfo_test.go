@@ -0,0 +1,110 @@
+package core
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SPS of a 1920x1080 H.264 High profile stream, which is coded as 1920x1088 and cropped
+var testSPS1080p, _ = hex.DecodeString("67640028acd940780227e5c044000003000400000300f03c60c658")
+
+// h264Segment returns an MPEG-TS segment with one H.264 frame per PES packet at the pts, in 90kHz clock ticks, and an
+// SPS in the first frame
+func h264Segment(pts ...uint64) []byte {
+	var data []byte
+	data = append(data, tsPacket(0, true, testPAT())...)
+	data = append(data, tsPacket(testPMTPID, true, pmtSection(pmtEntry(h264StreamType, testVideoPID, nil)))...)
+	for i, t := range pts {
+		frame := []byte{0, 0, 0, 1, 0x09, 0xF0}
+		if i == 0 {
+			frame = append(append(frame, 0, 0, 0, 1), testSPS1080p...)
+			frame = append(frame, 0, 0, 1, 0x65, 0x88)
+		}
+		data = append(data, pesTSPacket(testVideoPID, pesPacket(0xE0, t, frame))...)
+	}
+	return data
+}
+
+func TestH264Resolution(t *testing.T) {
+	assert := assert.New(t)
+
+	w, h, ok := h264Resolution(append([]byte{0, 0, 0, 1}, testSPS1080p...))
+	assert.True(ok)
+	assert.Equal(1920, w)
+	assert.Equal(1080, h)
+
+	// No SPS
+	_, _, ok = h264Resolution([]byte{0, 0, 0, 1, 0x09, 0xF0, 0, 0, 1, 0x65, 0x88})
+	assert.False(ok)
+	// Truncated SPS
+	_, _, ok = h264Resolution(append([]byte{0, 0, 1}, testSPS1080p[:6]...))
+	assert.False(ok)
+}
+
+func TestSplitNALUnits(t *testing.T) {
+	assert.Equal(t, [][]byte{{0x09, 0xF0}, {0x67, 0x42}, {0x65}},
+		splitNALUnits([]byte{0, 0, 0, 1, 0x09, 0xF0, 0, 0, 0, 1, 0x67, 0x42, 0, 0, 1, 0x65}))
+	assert.Nil(t, splitNALUnits([]byte{0x09, 0xF0}))
+}
+
+func TestUnescapeRBSP(t *testing.T) {
+	assert.Equal(t, []byte{0, 0, 1, 0, 0, 3}, unescapeRBSP([]byte{0, 0, 3, 1, 0, 0, 3, 3}))
+}
+
+func TestProbeSegment(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	assert.Nil(ProbeSegment(nil, 2))
+	assert.Nil(ProbeSegment([]byte("mp4"), 2))
+	// Segments without H.264 video are not probed
+	assert.Nil(ProbeSegment(id3Segment(testVideoPID, pmtEntry(0x24, testVideoPID, nil)), 2))
+
+	// Frames at 30 fps in decoding order, which is not the presentation order
+	data := h264Segment(0, 9000, 3000, 6000, 12000)
+	info := ProbeSegment(data, 2)
+	require.NotNil(info)
+	assert.Equal(1920, info.Width)
+	assert.Equal(1080, info.Height)
+	assert.InDelta(30, info.Framerate, 0.01)
+	assert.Equal(len(data)*8/2, info.Bitrate)
+
+	// The framerate and bitrate are unknown for a single frame without a duration
+	info = ProbeSegment(h264Segment(0), 0)
+	require.NotNil(info)
+	assert.Equal(1080, info.Height)
+	assert.Zero(info.Framerate)
+	assert.Zero(info.Bitrate)
+}
+
+func TestSegmentDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := SegmentDuration(nil)
+	assert.False(ok)
+	_, ok = SegmentDuration([]byte("mp4"))
+	assert.False(ok)
+	// A single frame has no duration
+	_, ok = SegmentDuration(h264Segment(0))
+	assert.False(ok)
+
+	// Five frames at 30 fps in decoding order last 5/30 seconds
+	d, ok := SegmentDuration(h264Segment(0, 9000, 3000, 6000, 12000))
+	assert.True(ok)
+	assert.Equal(5*time.Second/30, d)
+}
+
+func TestSegmentPixels(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(int64(0), SegmentPixels(nil))
+	assert.Equal(int64(0), SegmentPixels([]byte("mp4")))
+	// No sequence parameter set
+	assert.Equal(int64(0), SegmentPixels(id3Segment(testVideoPID, pmtEntry(h264StreamType, testVideoPID, nil))))
+
+	assert.Equal(int64(3*1920*1080), SegmentPixels(h264Segment(0, 6000, 3000)))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
@@ -45,6 +46,11 @@ func TestSegmentFlatten(t *testing.T) {
 	if !bytes.Equal(ethcrypto.Keccak256(md.Flatten()), sHash) {
 		t.Error("Flattened segment + hash did not match expected hash")
 	}
+
+	// Filters are signed
+	flat := md.Flatten()
+	md.Filters = []FilterStep{{Name: "fps", Args: map[string]string{"fps": "30"}}}
+	assert.Equal(t, append(flat, []byte("fps=fps=30")...), md.Flatten())
 }
 
 func TestRandomIdGenerator(t *testing.T) {
@@ -55,6 +61,24 @@ func TestRandomIdGenerator(t *testing.T) {
 	}
 }
 
+func TestDeriveManifestID(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC)
+	mid := DeriveManifestID([]byte("secret"), "streamkey", start)
+	// printf 'streamkey/2026-10-15' | openssl dgst -sha256 -hmac secret
+	assert.Equal(ManifestID("dfa2c0a09935a713"), mid)
+	assert.Len(string(mid), DerivedManifestIDLength)
+
+	// The ID is the same for the whole UTC date
+	assert.Equal(mid, DeriveManifestID([]byte("secret"), "streamkey", start.Add(-23*time.Hour)))
+	assert.Equal(mid, DeriveManifestID([]byte("secret"), "streamkey", start.In(time.FixedZone("UTC+2", 2*3600))))
+	assert.NotEqual(mid, DeriveManifestID([]byte("secret"), "streamkey", start.Add(time.Hour)))
+
+	assert.NotEqual(mid, DeriveManifestID([]byte("secret"), "otherkey", start))
+	assert.NotEqual(mid, DeriveManifestID([]byte("other"), "streamkey", start))
+}
+
 func TestStreamID(t *testing.T) {
 	rand.Seed(123)
 	mid := RandomManifestID()
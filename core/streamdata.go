@@ -1,6 +1,9 @@
 package core
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
@@ -11,12 +14,13 @@ import (
 
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/lperrors"
 	"github.com/livepeer/go-livepeer/net"
 
 	"github.com/livepeer/lpms/ffmpeg"
 )
 
-var ErrManifestID = errors.New("ErrManifestID")
+var ErrManifestID = lperrors.User(errors.New("ErrManifestID"))
 
 const (
 	DefaultManifestIDLength = 4
@@ -26,6 +30,16 @@ type DetectionConfig struct {
 	Freq               uint
 	SelectedClassNames []string
 	Profiles           []ffmpeg.DetectorProfile
+	// Minimum probability of a selected class for a detection to be reported. 0 reports all detections
+	Threshold float64
+	// Tag segments with detections above the threshold in the playlists
+	TagPlaylist bool
+	// Stream level classifications start when the smoothed probability of a class rises to StartThreshold and stop
+	// when it falls below StopThreshold. 0 disables stream level classifications
+	StartThreshold float64
+	StopThreshold  float64
+	// Weight of the latest segment in the smoothed probability
+	Smoothing float64
 }
 
 type StreamParameters struct {
@@ -40,16 +54,42 @@ type StreamParameters struct {
 	RecordOS         drivers.OSSession
 	Capabilities     *Capabilities
 	Detection        DetectionConfig
+	Filters          []FilterStep
 	VerificationFreq uint
 	Nonce            uint64
 	Codec            ffmpeg.VideoCodec
 	PixelFormat      ffmpeg.PixelFormat
+	// Backup is set for the backup ingest of a stream, which takes over the stream when the primary ingest disconnects
+	Backup bool
+	// AutoLadder is set if the profiles are to be replaced by a ladder generated from the first segment of the stream
+	AutoLadder bool
+	// OutputGroups are the ladders that the stream is simulcast to. Profiles are the profiles of all groups if set
+	OutputGroups []OutputGroup
+	// MaxPrice is the max price per pixel of the orchestrators that transcode the stream, in addition to the max price
+	// of the broadcaster. Not limited if nil
+	MaxPrice *big.Rat
+	// DerivedFrom is the stream key that ManifestID was derived from, empty if the manifest ID was not derived
+	DerivedFrom string
+}
+
+// OutputGroup is a ladder of a stream that is transcoded independently of the other ladders of the stream, by
+// orchestrators of its own, i.e. a premium ladder and a low cost ladder for mobile viewers
+type OutputGroup struct {
+	Name     string
+	Profiles []ffmpeg.VideoProfile
+	// MaxPrice is the max price per pixel of the orchestrators of the group. Not limited if nil
+	MaxPrice *big.Rat
 }
 
 func (s *StreamParameters) StreamID() string {
 	return string(s.ManifestID) + "/" + s.RtmpKey
 }
 
+// DetectionOnly returns true if the stream only requests content detection without any renditions
+func (s *StreamParameters) DetectionOnly() bool {
+	return len(s.Profiles) == 0 && s.Detection.Freq != 0
+}
+
 type SegTranscodingMetadata struct {
 	ManifestID         ManifestID
 	Fname              string
@@ -63,6 +103,11 @@ type SegTranscodingMetadata struct {
 	DetectorEnabled    bool
 	DetectorProfiles   []ffmpeg.DetectorProfile
 	CalcPerceptualHash bool
+	// TimeToDeadline is the time until the transcoded segment must be returned to the broadcaster, as of when the
+	// metadata is sent or received. No deadline if 0
+	TimeToDeadline time.Duration
+	// Filters are the custom filters that are applied to the segment when it is transcoded
+	Filters []FilterStep
 }
 
 func (md *SegTranscodingMetadata) Flatten() []byte {
@@ -74,6 +119,10 @@ func (md *SegTranscodingMetadata) Flatten() []byte {
 	i += copy(buf[i:], md.Hash.Bytes())
 	i += copy(buf[i:], []byte(profiles))
 	// i += copy(buf[i:], []byte(s.OS))
+	// The filters are only signed if set so that the signatures of unfiltered segments do not change
+	if len(md.Filters) > 0 {
+		buf = append(buf, []byte(FilterGraph(md.Filters))...)
+	}
 	return buf
 }
 
@@ -124,8 +173,13 @@ func NetSegData(md *SegTranscodingMetadata) (*net.SegData, error) {
 		DetectorEnabled:    md.DetectorEnabled,
 		DetectorProfiles:   detectorProfiles,
 		CalcPerceptualHash: md.CalcPerceptualHash,
-		// Triggers failure on Os that don't know how to use FullProfiles/2/3
-		Profiles: []byte("invalid"),
+		TimeToDeadline:     int64(md.TimeToDeadline / time.Millisecond),
+		FilterGraph:        FilterGraph(md.Filters),
+	}
+	// Triggers failure on Os that don't know how to use FullProfiles/2/3. Detection-only segments have no profiles
+	// that could be misread
+	if len(md.Profiles) > 0 || !md.DetectorEnabled {
+		segData.Profiles = []byte("invalid")
 	}
 
 	// If all outputs are mpegts, use the older SegData.FullProfiles field
@@ -207,3 +261,15 @@ func (id StreamID) String() string {
 func RandomManifestID() ManifestID {
 	return ManifestID(common.RandomIDGenerator(DefaultManifestIDLength))
 }
+
+// DerivedManifestIDLength is the number of hex characters of a manifest ID derived from a stream key
+const DerivedManifestIDLength = 16
+
+// DeriveManifestID returns the manifest ID of the stream with streamKey that starts on the UTC date of t, so that the
+// playback URLs of the stream can be predicted with secret. The ID is the first DerivedManifestIDLength hex characters
+// of HMAC-SHA256(secret, "<streamKey>/<YYYY-MM-DD>")
+func DeriveManifestID(secret []byte, streamKey string, t time.Time) ManifestID {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(streamKey + "/" + t.UTC().Format("2006-01-02")))
+	return ManifestID(hex.EncodeToString(mac.Sum(nil))[:DerivedManifestIDLength])
+}
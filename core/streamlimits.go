@@ -0,0 +1,93 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/lperrors"
+)
+
+var ErrStreamLimit = lperrors.Retryable(errors.New("StreamLimitReached"))
+
+// pendingStreamTimeout is how long a stream that passed the stream limits check counts against the limits
+// before its first segment starts the segment loop
+var pendingStreamTimeout = 1 * time.Minute
+
+// StreamLimits caps the number of concurrent streams an orchestrator accepts from a single broadcaster so that
+// one broadcaster cannot use up all of the orchestrator's capacity. A zero limit means no limit
+type StreamLimits struct {
+	MaxPerSender int
+	MaxPerIP     int
+
+	// SenderOverrides and IPOverrides replace the default limits for specific broadcasters i.e. trusted partners
+	SenderOverrides map[ethcommon.Address]int
+	IPOverrides     map[string]int
+}
+
+func (l *StreamLimits) senderLimit(sender ethcommon.Address) int {
+	if max, ok := l.SenderOverrides[sender]; ok {
+		return max
+	}
+	return l.MaxPerSender
+}
+
+func (l *StreamLimits) ipLimit(ip string) int {
+	if max, ok := l.IPOverrides[ip]; ok {
+		return max
+	}
+	return l.MaxPerIP
+}
+
+type streamOwner struct {
+	sender ethcommon.Address
+	ip     string
+	added  time.Time
+}
+
+// CheckStreamLimits returns an error if starting a new stream with session ID mid would exceed the stream limits for
+// the broadcaster with address sender and IP address ip
+func (orch *orchestrator) CheckStreamLimits(mid ManifestID, sender ethcommon.Address, ip string) error {
+	return orch.node.checkStreamLimits(mid, sender, ip)
+}
+
+func (n *LivepeerNode) checkStreamLimits(mid ManifestID, sender ethcommon.Address, ip string) error {
+	if n.StreamLimits == nil {
+		return nil
+	}
+
+	n.segmentMutex.Lock()
+	defer n.segmentMutex.Unlock()
+
+	if _, ok := n.SegmentChans[mid]; ok {
+		return nil
+	}
+	if _, ok := n.streamOwners[mid]; ok {
+		return nil
+	}
+
+	senderStreams, ipStreams := 0, 0
+	for m, owner := range n.streamOwners {
+		// Forget streams that ended or never started
+		if _, ok := n.SegmentChans[m]; !ok && time.Since(owner.added) > pendingStreamTimeout {
+			delete(n.streamOwners, m)
+			continue
+		}
+		if owner.sender == sender {
+			senderStreams++
+		}
+		if owner.ip == ip {
+			ipStreams++
+		}
+	}
+
+	if max := n.StreamLimits.senderLimit(sender); max > 0 && senderStreams >= max {
+		return ErrStreamLimit
+	}
+	if max := n.StreamLimits.ipLimit(ip); max > 0 && ipStreams >= max {
+		return ErrStreamLimit
+	}
+
+	n.streamOwners[mid] = streamOwner{sender: sender, ip: ip, added: time.Now()}
+	return nil
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/lperrors"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStreamLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	n, _ := NewLivepeerNode(nil, "", nil)
+	orch := NewOrchestrator(n, nil)
+
+	sender := pm.RandAddress()
+	other := pm.RandAddress()
+	trusted := pm.RandAddress()
+	randMid := func() ManifestID { return ManifestID(RandomManifestID()) }
+
+	// No limits configured
+	for i := 0; i < 5; i++ {
+		assert.Nil(orch.CheckStreamLimits(randMid(), sender, "1.1.1.1"))
+	}
+	assert.Empty(n.streamOwners)
+
+	n.StreamLimits = &StreamLimits{
+		MaxPerSender:    2,
+		MaxPerIP:        3,
+		SenderOverrides: map[ethcommon.Address]int{trusted: 0},
+		IPOverrides:     map[string]int{"3.3.3.3": 5},
+	}
+
+	// Per sender limit
+	mid := randMid()
+	assert.Nil(orch.CheckStreamLimits(mid, sender, "1.1.1.1"))
+	assert.Nil(orch.CheckStreamLimits(randMid(), sender, "1.1.1.1"))
+	err := orch.CheckStreamLimits(randMid(), sender, "2.2.2.2")
+	assert.Equal(ErrStreamLimit, err)
+	assert.True(lperrors.IsRetryable(err))
+
+	// Streams that were already accepted are not limited
+	assert.Nil(orch.CheckStreamLimits(mid, sender, "1.1.1.1"))
+
+	// Per IP limit
+	assert.Nil(orch.CheckStreamLimits(randMid(), other, "1.1.1.1"))
+	assert.Equal(ErrStreamLimit, orch.CheckStreamLimits(randMid(), pm.RandAddress(), "1.1.1.1"))
+
+	// Overrides
+	for i := 0; i < 5; i++ {
+		assert.Nil(orch.CheckStreamLimits(randMid(), trusted, "3.3.3.3"))
+	}
+	assert.Equal(ErrStreamLimit, orch.CheckStreamLimits(randMid(), trusted, "3.3.3.3"))
+}
+
+func TestCheckStreamLimits_ExpiredStreams(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	oldPendingTimeout, oldLoopTimeout, oldMaxSessions := pendingStreamTimeout, transcodeLoopTimeout, MaxSessions
+	defer func() {
+		pendingStreamTimeout, transcodeLoopTimeout, MaxSessions = oldPendingTimeout, oldLoopTimeout, oldMaxSessions
+	}()
+	pendingStreamTimeout = 50 * time.Millisecond
+	transcodeLoopTimeout = 300 * time.Millisecond
+	MaxSessions = 10
+
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+	n, _ := NewLivepeerNode(nil, "", nil)
+	n.StreamLimits = &StreamLimits{MaxPerSender: 1}
+	orch := NewOrchestrator(n, nil)
+	sender := pm.RandAddress()
+
+	// A stream that never sends a segment stops counting against the limit
+	assert.Nil(orch.CheckStreamLimits(ManifestID(RandomManifestID()), sender, "1.1.1.1"))
+	assert.Equal(ErrStreamLimit, orch.CheckStreamLimits(ManifestID(RandomManifestID()), sender, "1.1.1.1"))
+	time.Sleep(100 * time.Millisecond)
+
+	// A stream with an active segment loop keeps counting against the limit
+	md := StubSegTranscodingMetadata()
+	mid := ManifestID(md.AuthToken.SessionId)
+	assert.Nil(orch.CheckStreamLimits(mid, sender, "1.1.1.1"))
+	_, err := n.getSegmentChan(context.TODO(), md)
+	require.Nil(err)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(ErrStreamLimit, orch.CheckStreamLimits(ManifestID(RandomManifestID()), sender, "1.1.1.1"))
+
+	// The stream is forgotten once the segment loop ends
+	time.Sleep(300 * time.Millisecond)
+	assert.Nil(getSegChan(n, mid))
+	assert.Nil(orch.CheckStreamLimits(ManifestID(RandomManifestID()), sender, "1.1.1.1"))
+}
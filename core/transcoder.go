@@ -16,6 +16,7 @@ import (
 
 	"github.com/livepeer/go-livepeer/clog"
 	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/lperrors"
 	"github.com/livepeer/go-livepeer/monitor"
 	"github.com/livepeer/lpms/ffmpeg"
 )
@@ -38,9 +39,20 @@ func NewUnrecoverableError(err error) UnrecoverableError {
 
 var WorkDir string
 
+// ProfileAccel overrides the acceleration used to encode specific profiles on GPU transcoders i.e. to encode cheap
+// renditions on the CPU and leave the GPU encoders for expensive renditions. Keys are either profile names or
+// resolutions in the "<height>p" form where the height is the smaller dimension of the profile's resolution
+var ProfileAccel map[string]ffmpeg.Acceleration
+
+var errSegmentURI = lperrors.User(errors.New("BadURI"))
+
 func (lt *LocalTranscoder) Transcode(ctx context.Context, md *SegTranscodingMetadata) (td *TranscodeData, retErr error) {
 	// Returns UnrecoverableError instead of panicking to gracefully notify orchestrator about transcoder's failure
-	defer recoverFromPanic(&retErr)
+	defer recoverFromPanic(&retErr, "cpu", md)
+
+	if len(md.Filters) > 0 {
+		return transcodeWithFilters(ctx, lt.workDir, md)
+	}
 
 	// Set up in / out config
 	in := &ffmpeg.TranscodeOptionsIn{
@@ -76,14 +88,30 @@ func NewLocalTranscoder(workDir string) Transcoder {
 	return &LocalTranscoder{workDir: workDir}
 }
 
+// ffmpegSession is the lpms transcoding session used by NvidiaTranscoder
+type ffmpegSession interface {
+	Transcode(input *ffmpeg.TranscodeOptionsIn, ps []ffmpeg.TranscodeOptions) (*ffmpeg.TranscodeResults, error)
+	StopTranscoder()
+}
+
+// newFFmpegSession and newFFmpegSessionWithDetector create the lpms sessions of Nvidia transcoders. Builds with the
+// fakegpu tag replace them with fakes so that the GPU code paths can be tested without GPUs
+var newFFmpegSession = func() ffmpegSession {
+	return ffmpeg.NewTranscoder()
+}
+
+var newFFmpegSessionWithDetector = func(detector ffmpeg.DetectorProfile, gpu string) (ffmpegSession, error) {
+	return ffmpeg.NewTranscoderWithDetector(detector, gpu)
+}
+
 type NvidiaTranscoder struct {
 	device  string
-	session *ffmpeg.Transcoder
+	session ffmpegSession
 }
 
 func (nv *NvidiaTranscoder) Transcode(ctx context.Context, md *SegTranscodingMetadata) (td *TranscodeData, retErr error) {
 	// Returns UnrecoverableError instead of panicking to gracefully notify orchestrator about transcoder's failure
-	defer recoverFromPanic(&retErr)
+	defer recoverFromPanic(&retErr, nv.device, md)
 
 	in := &ffmpeg.TranscodeOptionsIn{
 		Fname:  md.Fname,
@@ -99,6 +127,9 @@ func (nv *NvidiaTranscoder) Transcode(ctx context.Context, md *SegTranscodingMet
 	_, seqNo, parseErr := parseURI(md.Fname)
 	start := time.Now()
 
+	if err := common.InjectFault(common.FaultGPUSession); err != nil {
+		return nil, err
+	}
 	res, err := nv.session.Transcode(in, out)
 	if err != nil {
 		return nil, err
@@ -193,17 +224,47 @@ func testNvidiaTranscode(device string, fname string, profile ffmpeg.VideoProfil
 	return outputProduced, outputValid, err
 }
 
-// Test which capabilities transcoder supports
-func TestTranscoderCapabilities(devices []string) (caps []Capability, fatalError error) {
+// CapabilityTestResult is the result of testing whether the transcoding hardware supports a capability
+type CapabilityTestResult struct {
+	Capability Capability
+	Name       string
+	Required   bool
+	// Tested is false if there is no test for the capability in which case the capability is assumed to be supported
+	Tested    bool
+	Supported bool
+	// Err is the reason the capability is not supported
+	Err error
+}
+
+// Test which capabilities transcoder supports. Capabilities that passed on a device according to cache are not
+// tested again on that device. cache can be nil
+func TestTranscoderCapabilities(devices []string, cache *CapabilityTestCache) (caps []Capability, fatalError error) {
+	results, fatalError := TestTranscoderCapabilityResults(devices, cache)
+	for _, res := range results {
+		if res.Supported {
+			caps = append(caps, res.Capability)
+		}
+	}
+	return caps, fatalError
+}
+
+// TestTranscoderCapabilityResults tests which capabilities are supported on all devices and returns the result for
+// every capability that was tested. Testing stops on the first fatal error i.e. if a required capability is not supported.
+// The results are recorded in cache which can be nil
+func TestTranscoderCapabilityResults(devices []string, cache *CapabilityTestCache) (results []CapabilityTestResult, fatalError error) {
 	fatalError = nil
 	forEachTranscoderSample(func(params *transcodeTestParams) continueLoop {
+		res := CapabilityTestResult{Capability: params.Cap, Name: params.Name(), Required: params.IsRequired(), Tested: params.TestAvailable}
 		if !params.TestAvailable {
 			// Assume capability is supported if we do not have test for it
-			caps = append(caps, params.Cap)
+			res.Supported = true
+			results = append(results, res)
 			return true
 		}
 		runRestrictedSessionTest := true
-		transcodingFailed := func() {
+		transcodingFailed := func(err error) {
+			res.Err = err
+			results = append(results, res)
 			// check GeForce limit
 			if runRestrictedSessionTest {
 				// do it only once
@@ -222,26 +283,31 @@ func TestTranscoderCapabilities(devices []string) (caps []Capability, fatalError
 		}
 		// check that capability is supported on all devices
 		for _, device := range devices {
+			if cache.passed(device, params.Cap) {
+				continue
+			}
 			outputProduced, outputValid, err := testNvidiaTranscode(device, params.SegmentPath, params.OutProfile, 4)
+			cache.record(device, params.Cap, err == nil && outputProduced && outputValid)
 			if err != nil {
 				glog.Infof("%s %q is not supported on device %s, see other error messages for details", params.Kind(), params.Name(), device)
 				// likely means capability is not supported, don't check on other devices
-				transcodingFailed()
+				transcodingFailed(fmt.Errorf("device %s: %w", device, err))
 				return fatalError == nil
 			}
 			if !outputProduced || !outputValid {
 				// abnormal behavior
 				glog.Errorf("Empty result segment when testing for %s %q", params.Kind(), params.Name())
-				transcodingFailed()
+				transcodingFailed(fmt.Errorf("device %s: empty result segment", device))
 				return fatalError == nil
 			}
 			// no error creating 4 renditions - disable 3 renditions test, as restriction is on driver level, not device
 			runRestrictedSessionTest = false
 		}
-		caps = append(caps, params.Cap)
+		res.Supported = true
+		results = append(results, res)
 		return true
 	})
-	return caps, fatalError
+	return results, fatalError
 }
 
 func testSoftwareTranscode(tmpdir string, fname string, profile ffmpeg.VideoProfile, renditionCount int) (outputProduced, outputValid bool, err error) {
@@ -288,14 +354,14 @@ func TestSoftwareTranscoderCapabilities(tmpdir string) (caps []Capability, fatal
 func NewNvidiaTranscoder(gpu string) TranscoderSession {
 	return &NvidiaTranscoder{
 		device:  gpu,
-		session: ffmpeg.NewTranscoder(),
+		session: newFFmpegSession(),
 	}
 }
 
 func NewNvidiaTranscoderWithDetector(detector ffmpeg.DetectorProfile, gpu string) (TranscoderSession, error) {
 	// Hardcode detection to device 0 for now
 	// Transcoding can still run on a separate GPU as we copy frames to CPU before detection
-	session, err := ffmpeg.NewTranscoderWithDetector(detector, gpu)
+	session, err := newFFmpegSessionWithDetector(detector, gpu)
 	return &NvidiaTranscoder{
 		device:  gpu,
 		session: session,
@@ -306,17 +372,25 @@ func (nv *NvidiaTranscoder) Stop() {
 	nv.session.StopTranscoder()
 }
 
+// parseURI extracts the manifest ID and sequence number from a segment URI of the form .../<manifestID>/<seqNo>.<ext>
 func parseURI(uri string) (string, uint64, error) {
-	var mid string
-	var seqNo uint64
 	parts := strings.Split(uri, "/")
 	if len(parts) < 3 {
-		return mid, seqNo, fmt.Errorf("BadURI")
+		return "", 0, errSegmentURI
+	}
+	mid := parts[len(parts)-2]
+	if mid == "" {
+		return "", 0, errSegmentURI
 	}
-	mid = parts[len(parts)-2]
-	parts = strings.Split(parts[len(parts)-1], ".")
-	seqNo, err := strconv.ParseUint(parts[0], 10, 64)
-	return mid, seqNo, err
+	name := strings.Split(parts[len(parts)-1], ".")
+	if len(name) != 2 || name[1] == "" {
+		return "", 0, errSegmentURI
+	}
+	seqNo, err := strconv.ParseUint(name[0], 10, 64)
+	if err != nil {
+		return "", 0, errSegmentURI
+	}
+	return mid, seqNo, nil
 }
 
 func resToTranscodeData(ctx context.Context, res *ffmpeg.TranscodeResults, opts []ffmpeg.TranscodeOptions) (*TranscodeData, error) {
@@ -370,7 +444,7 @@ func profilesToTranscodeOptions(workDir string, accel ffmpeg.Acceleration, profi
 		o := ffmpeg.TranscodeOptions{
 			Oname:        fmt.Sprintf("%s/out_%s.tempfile", workDir, common.RandName()),
 			Profile:      profiles[i],
-			Accel:        accel,
+			Accel:        profileAccel(profiles[i], accel),
 			AudioEncoder: ffmpeg.ComponentOptions{Name: "copy"},
 			CalcSign:     calcPHash,
 		}
@@ -379,6 +453,28 @@ func profilesToTranscodeOptions(workDir string, accel ffmpeg.Acceleration, profi
 	return opts
 }
 
+// profileAccel returns the acceleration used to encode profile on a transcoder that decodes with accel. Only GPU
+// transcoders can encode profiles on the CPU so the overrides are ignored for software transcoders
+func profileAccel(profile ffmpeg.VideoProfile, accel ffmpeg.Acceleration) ffmpeg.Acceleration {
+	if accel == ffmpeg.Software || len(ProfileAccel) == 0 {
+		return accel
+	}
+	if a, ok := ProfileAccel[profile.Name]; ok {
+		return a
+	}
+	w, h, err := ffmpeg.VideoProfileResolution(profile)
+	if err != nil {
+		return accel
+	}
+	if w < h {
+		h = w
+	}
+	if a, ok := ProfileAccel[fmt.Sprintf("%dp", h)]; ok {
+		return a
+	}
+	return accel
+}
+
 func detectorsToTranscodeOptions(workDir string, accel ffmpeg.Acceleration, profiles []ffmpeg.DetectorProfile) []ffmpeg.TranscodeOptions {
 	opts := make([]ffmpeg.TranscodeOptions, len(profiles))
 	for i := range profiles {
@@ -399,12 +495,3 @@ func detectorsToTranscodeOptions(workDir string, accel ffmpeg.Acceleration, prof
 	return opts
 }
 
-func recoverFromPanic(retErr *error) {
-	if r := recover(); r != nil {
-		err, ok := r.(error)
-		if !ok {
-			err = errors.New("unrecoverable transcoding failure")
-		}
-		*retErr = NewUnrecoverableError(err)
-	}
-}
@@ -0,0 +1,140 @@
+//go:build fakegpu
+// +build fakegpu
+
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/livepeer/lpms/ffmpeg"
+)
+
+// Every segment transcoded by a fake GPU is a 720p source with fakeGPUFrames frames
+const (
+	fakeGPUFrames       = 60
+	fakeGPUSourcePixels = 1280 * 720 * fakeGPUFrames
+)
+
+// FakeGPU replaces the lpms sessions of Nvidia transcoders in builds with the fakegpu tag so that session pooling,
+// error handling and capability tests can be exercised without NVIDIA hardware. Renditions contain the name of their
+// profile and report the pixels of fakeGPUFrames frames at the profile's resolution
+var FakeGPU = NewFakeGPUTranscoder()
+
+func init() {
+	newFFmpegSession = func() ffmpegSession {
+		return FakeGPU.newSession()
+	}
+	newFFmpegSessionWithDetector = func(detector ffmpeg.DetectorProfile, gpu string) (ffmpegSession, error) {
+		return FakeGPU.newSession(), nil
+	}
+}
+
+// FakeGPUTranscoder keeps track of the fake lpms sessions and the transcodes they ran on each device
+type FakeGPUTranscoder struct {
+	mu sync.Mutex
+	// Err returns the error of a transcode on device, if set
+	Err        func(device string, out []ffmpeg.TranscodeOptions) error
+	sessions   int
+	transcodes map[string]int
+}
+
+func NewFakeGPUTranscoder() *FakeGPUTranscoder {
+	return &FakeGPUTranscoder{transcodes: make(map[string]int)}
+}
+
+// Reset forgets all sessions and transcodes and clears Err
+func (f *FakeGPUTranscoder) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Err = nil
+	f.sessions = 0
+	f.transcodes = make(map[string]int)
+}
+
+// Sessions returns the number of sessions that are not stopped
+func (f *FakeGPUTranscoder) Sessions() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.sessions
+}
+
+// Transcodes returns the number of transcodes that ran on device
+func (f *FakeGPUTranscoder) Transcodes(device string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.transcodes[device]
+}
+
+func (f *FakeGPUTranscoder) newSession() *fakeGPUSession {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sessions++
+	return &fakeGPUSession{gpu: f}
+}
+
+type fakeGPUSession struct {
+	gpu *FakeGPUTranscoder
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+func (s *fakeGPUSession) Transcode(in *ffmpeg.TranscodeOptionsIn, out []ffmpeg.TranscodeOptions) (*ffmpeg.TranscodeResults, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return nil, ffmpeg.ErrTranscoderStp
+	}
+
+	s.gpu.mu.Lock()
+	s.gpu.transcodes[in.Device]++
+	errFn := s.gpu.Err
+	s.gpu.mu.Unlock()
+	if errFn != nil {
+		if err := errFn(in.Device, out); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &ffmpeg.TranscodeResults{}
+	for _, o := range out {
+		if o.Detector != nil {
+			res.Encoded = append(res.Encoded, ffmpeg.MediaInfo{Frames: fakeGPUFrames})
+			continue
+		}
+		var w, h int64
+		if _, err := fmt.Sscanf(o.Profile.Resolution, "%dx%d", &w, &h); err != nil {
+			return nil, ffmpeg.ErrTranscoderRes
+		}
+		if err := ioutil.WriteFile(o.Oname, []byte(o.Profile.Name), 0644); err != nil {
+			return nil, err
+		}
+		if o.CalcSign {
+			if err := ioutil.WriteFile(o.Oname+".bin", []byte(o.Profile.Name), 0644); err != nil {
+				return nil, err
+			}
+		}
+		res.Encoded = append(res.Encoded, ffmpeg.MediaInfo{Frames: fakeGPUFrames, Pixels: w * h * fakeGPUFrames})
+	}
+	res.Decoded = ffmpeg.MediaInfo{Frames: fakeGPUFrames, Pixels: fakeGPUSourcePixels}
+	return res, nil
+}
+
+func (s *fakeGPUSession) StopTranscoder() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+
+	s.gpu.mu.Lock()
+	s.gpu.sessions--
+	s.gpu.mu.Unlock()
+}
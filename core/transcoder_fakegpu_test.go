@@ -0,0 +1,165 @@
+//go:build fakegpu
+// +build fakegpu
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupFakeGPU(t *testing.T) func() {
+	tmp, err := ioutil.TempDir("", "fakegpu")
+	require.Nil(t, err)
+	WorkDir = tmp
+	FakeGPU.Reset()
+	return func() {
+		FakeGPU.Reset()
+		WorkDir = ""
+		os.RemoveAll(tmp)
+	}
+}
+
+func TestFakeGPU_Transcode(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	defer setupFakeGPU(t)()
+
+	tc := NewNvidiaTranscoder("0")
+	assert.Equal(1, FakeGPU.Sessions())
+
+	md := stubMetadata("", videoProfiles...)
+	md.Fname = "test.ts"
+	res, err := tc.Transcode(context.Background(), md)
+	require.Nil(err)
+	require.Len(res.Segments, 2)
+	assert.Equal(videoProfiles[0].Name, string(res.Segments[0].Data))
+	assert.Equal(int64(256*144*fakeGPUFrames), res.Segments[0].Pixels)
+	assert.Equal(videoProfiles[1].Name, string(res.Segments[1].Data))
+	assert.Equal(int64(fakeGPUSourcePixels), res.Pixels)
+	assert.Equal(1, FakeGPU.Transcodes("0"))
+
+	// Transcoding errors are returned
+	FakeGPU.Err = func(device string, out []ffmpeg.TranscodeOptions) error {
+		return ffmpeg.ErrTranscoderInp
+	}
+	_, err = tc.Transcode(context.Background(), md)
+	assert.Equal(ffmpeg.ErrTranscoderInp, err)
+
+	// Stopped sessions do not transcode
+	tc.Stop()
+	assert.Equal(0, FakeGPU.Sessions())
+	FakeGPU.Err = nil
+	_, err = tc.Transcode(context.Background(), md)
+	assert.Equal(ffmpeg.ErrTranscoderStp, err)
+}
+
+func TestFakeGPU_LoadBalancing(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	defer setupFakeGPU(t)()
+
+	lb := NewLoadBalancingTranscoder([]string{"0", "1"}, NewNvidiaTranscoder, NewNvidiaTranscoderWithDetector).(*LoadBalancingTranscoder)
+
+	// Sessions are spread over the devices and reused for subsequent segments
+	for i := 0; i < 4; i++ {
+		for _, sess := range []string{"a", "b"} {
+			md := stubMetadata(sess, videoProfiles...)
+			md.Fname = fmt.Sprintf("%s/%d.ts", sess, i)
+			_, err := lb.Transcode(context.Background(), md)
+			require.Nil(err)
+		}
+	}
+	assert.Equal(2, FakeGPU.Sessions())
+	assert.Equal(4, FakeGPU.Transcodes("0"))
+	assert.Equal(4, FakeGPU.Transcodes("1"))
+
+	// A failed transcode stops the lpms session
+	FakeGPU.Err = func(device string, out []ffmpeg.TranscodeOptions) error {
+		return ffmpeg.ErrTranscoderInp
+	}
+	_, err := lb.Transcode(context.Background(), stubMetadata("a", videoProfiles...))
+	assert.Equal(ffmpeg.ErrTranscoderInp, err)
+	assert.Eventually(func() bool { return FakeGPU.Sessions() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestFakeGPU_Quarantine(t *testing.T) {
+	assert := assert.New(t)
+	defer setupFakeGPU(t)()
+
+	lb := NewLoadBalancingTranscoder([]string{"0", "1"}, NewNvidiaTranscoder, NewNvidiaTranscoderWithDetector).(*LoadBalancingTranscoder)
+
+	// Device 0 keeps failing with a device fault
+	FakeGPU.Err = func(device string, out []ffmpeg.TranscodeOptions) error {
+		if device == "0" {
+			return errors.New("CUDA_ERROR_ILLEGAL_ADDRESS")
+		}
+		return nil
+	}
+	for i := 0; i < 2*GPUQuarantineThreshold; i++ {
+		lb.Transcode(context.Background(), stubMetadata(fmt.Sprintf("sess%d", i), videoProfiles...))
+	}
+	assert.Equal(GPUQuarantineThreshold, FakeGPU.Transcodes("0"))
+	assert.Equal(GPUQuarantineThreshold, FakeGPU.Transcodes("1"))
+
+	// New sessions only go to the healthy device
+	for i := 0; i < 3; i++ {
+		_, err := lb.Transcode(context.Background(), stubMetadata(fmt.Sprintf("new%d", i), videoProfiles...))
+		assert.Nil(err)
+	}
+	assert.Equal(GPUQuarantineThreshold, FakeGPU.Transcodes("0"))
+	assert.Equal(GPUQuarantineThreshold+3, FakeGPU.Transcodes("1"))
+}
+
+func TestFakeGPU_Capabilities(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	defer setupFakeGPU(t)()
+
+	// Device 1 does not support HEVC encoding
+	FakeGPU.Err = func(device string, out []ffmpeg.TranscodeOptions) error {
+		if device == "1" && out[0].Profile.Encoder == ffmpeg.H265 {
+			return ffmpeg.ErrTranscoderHw
+		}
+		return nil
+	}
+	devices := []string{"0", "1"}
+	results, err := TestTranscoderCapabilityResults(devices, nil)
+	require.Nil(err)
+	for _, res := range results {
+		if res.Capability == Capability_HEVC_Encode {
+			assert.False(res.Supported)
+			assert.EqualError(res.Err, "device 1: "+ffmpeg.ErrTranscoderHw.Error())
+		} else {
+			assert.True(res.Supported, res.Name)
+		}
+	}
+	assert.Equal(0, FakeGPU.Sessions())
+
+	// A required capability that is not supported is a fatal error
+	FakeGPU.Err = func(device string, out []ffmpeg.TranscodeOptions) error {
+		return ffmpeg.ErrTranscoderHw
+	}
+	caps, err := TestTranscoderCapabilities(devices, nil)
+	assert.EqualError(err, `required capability "H.264" is not supported on hardware`)
+	assert.NotContains(caps, Capability_H264)
+
+	// Passed capabilities are not tested again
+	FakeGPU.Reset()
+	cache := NewCapabilityTestCache(WorkDir+"/cache.json", "", nil)
+	_, err = TestTranscoderCapabilities(devices, cache)
+	require.Nil(err)
+	tested := FakeGPU.Transcodes("0")
+	_, err = TestTranscoderCapabilities(devices, cache)
+	require.Nil(err)
+	assert.Equal(tested, FakeGPU.Transcodes("0"))
+}
@@ -224,6 +224,28 @@ func TestProfilesToTranscodeOptions(t *testing.T) {
 		assert.Equal(p, opts[i].Profile)
 		assert.Equal("copy", opts[i].AudioEncoder.Name)
 	}
+
+	// Test per profile acceleration overrides
+	defer func() { ProfileAccel = nil }()
+	ProfileAccel = map[string]ffmpeg.Acceleration{"P144p30fps16x9": ffmpeg.Software}
+	opts = profilesToTranscodeOptions(workDir, ffmpeg.Nvidia, profiles, false)
+	assert.Equal(ffmpeg.Software, opts[0].Accel)
+	assert.Equal(ffmpeg.Nvidia, opts[1].Accel)
+
+	ProfileAccel = map[string]ffmpeg.Acceleration{"240p": ffmpeg.Software}
+	opts = profilesToTranscodeOptions(workDir, ffmpeg.Nvidia, profiles, false)
+	assert.Equal(ffmpeg.Nvidia, opts[0].Accel)
+	assert.Equal(ffmpeg.Software, opts[1].Accel)
+
+	// The resolution matches portrait profiles
+	portrait := ffmpeg.VideoProfile{Name: "portrait", Resolution: "240x426", Bitrate: "250k"}
+	opts = profilesToTranscodeOptions(workDir, ffmpeg.Nvidia, []ffmpeg.VideoProfile{portrait}, false)
+	assert.Equal(ffmpeg.Software, opts[0].Accel)
+
+	// Overrides are ignored by software transcoders
+	ProfileAccel = map[string]ffmpeg.Acceleration{"240p": ffmpeg.Nvidia}
+	opts = profilesToTranscodeOptions(workDir, ffmpeg.Software, profiles, false)
+	assert.Equal(ffmpeg.Software, opts[1].Accel)
 }
 
 func TestAudioCopy(t *testing.T) {
@@ -289,7 +311,7 @@ func TestRecoverFromPanic(t *testing.T) {
 	assert := assert.New(t)
 
 	f := func() (err error) {
-		defer recoverFromPanic(&err)
+		defer recoverFromPanic(&err, "0", nil)
 		panic(struct{}{})
 	}
 
@@ -305,7 +327,7 @@ func TestRecoverFromPanic_WithError(t *testing.T) {
 	sampleErr := errors.New("sample error")
 
 	f := func() (err error) {
-		defer recoverFromPanic(&err)
+		defer recoverFromPanic(&err, "0", nil)
 		panic(sampleErr)
 	}
 
@@ -313,3 +335,28 @@ func TestRecoverFromPanic_WithError(t *testing.T) {
 
 	assert.Equal(NewUnrecoverableError(sampleErr), err)
 }
+
+func TestParseURI(t *testing.T) {
+	assert := assert.New(t)
+
+	mid, seqNo, err := parseURI("https://127.0.0.1:8935/stream/abc/12.ts")
+	assert.Nil(err)
+	assert.Equal("abc", mid)
+	assert.Equal(uint64(12), seqNo)
+
+	for _, uri := range []string{
+		"",
+		"12.ts",
+		"abc/12.ts",
+		"/stream//12.ts",
+		"/stream/abc/12",
+		"/stream/abc/12.",
+		"/stream/abc/12.ts.bak",
+		"/stream/abc/-1.ts",
+		"/stream/abc/1a.ts",
+		"/stream/abc/99999999999999999999.ts",
+	} {
+		_, _, err := parseURI(uri)
+		assert.Equal(errSegmentURI, err, uri)
+	}
+}
@@ -0,0 +1,66 @@
+package discovery
+
+import (
+	"sync"
+	"time"
+
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/net"
+)
+
+// capabilitiesTTL is how long the capabilities advertised by an orchestrator are used to skip it for incompatible jobs
+// before its info is requested again
+var capabilitiesTTL = 10 * time.Minute
+
+// capabilitiesCache holds the capabilities that orchestrators advertised in their latest info by URL, so that the
+// orchestrators that do not support the capabilities of a job are skipped without requesting their info. A nil
+// capabilitiesCache does not skip any orchestrators
+type capabilitiesCache struct {
+	mu      sync.RWMutex
+	entries map[string]advertisedCapabilities
+}
+
+type advertisedCapabilities struct {
+	// caps is nil for orchestrators that do not advertise capabilities
+	caps    *net.Capabilities
+	updated time.Time
+}
+
+func newCapabilitiesCache() *capabilitiesCache {
+	return &capabilitiesCache{entries: make(map[string]advertisedCapabilities)}
+}
+
+func (c *capabilitiesCache) update(uri string, caps *net.Capabilities) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[uri] = advertisedCapabilities{caps: caps, updated: time.Now()}
+}
+
+// incompatible returns true if the orchestrator at uri advertised capabilities in the last capabilitiesTTL that are
+// not compatible with caps
+func (c *capabilitiesCache) incompatible(uri string, caps common.CapabilityComparator) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.RLock()
+	entry, ok := c.entries[uri]
+	c.mu.RUnlock()
+	if !ok || time.Since(entry.updated) > capabilitiesTTL {
+		return false
+	}
+	return !compatibleCapabilities(entry.caps, caps)
+}
+
+// compatibleCapabilities returns true if an orchestrator with the capabilities orch can run a job with caps
+func compatibleCapabilities(orch *net.Capabilities, caps common.CapabilityComparator) bool {
+	// Legacy features already have support on the orchestrator.
+	// Capabilities can be omitted in this case for older orchestrators.
+	// Otherwise, capabilities are required to be present.
+	if orch == nil {
+		return caps.LegacyOnly()
+	}
+	return caps.CompatibleWith(orch)
+}
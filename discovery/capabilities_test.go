@@ -0,0 +1,30 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/livepeer/go-livepeer/net"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilitiesCache(t *testing.T) {
+	assert := assert.New(t)
+
+	caps := newStubCapabilities()
+	caps.isLegacy = false
+
+	var nilCache *capabilitiesCache
+	nilCache.update("https://orch", &net.Capabilities{})
+	assert.False(nilCache.incompatible("https://orch", caps))
+
+	cache := newCapabilitiesCache()
+	assert.False(cache.incompatible("https://orch", caps))
+	cache.update("https://orch", &net.Capabilities{})
+	assert.True(cache.incompatible("https://orch", caps))
+	cache.update("https://orch", &net.Capabilities{Bitstring: capCompatString})
+	assert.False(cache.incompatible("https://orch", caps))
+	cache.update("https://orch", nil)
+	assert.True(cache.incompatible("https://orch", caps))
+	caps.isLegacy = true
+	assert.False(cache.incompatible("https://orch", caps))
+}
@@ -36,6 +36,8 @@ type DBOrchestratorPoolCache struct {
 	ticketParamsValidator ticketParamsValidator
 	rm                    common.RoundsManager
 	bcast                 common.Broadcaster
+	// caps are the capabilities advertised by the orchestrators, kept across the pools of the requests
+	caps *capabilitiesCache
 }
 
 func NewDBOrchestratorPoolCache(ctx context.Context, node *core.LivepeerNode, rm common.RoundsManager) (*DBOrchestratorPoolCache, error) {
@@ -49,9 +51,10 @@ func NewDBOrchestratorPoolCache(ctx context.Context, node *core.LivepeerNode, rm
 		ticketParamsValidator: node.Sender,
 		rm:                    rm,
 		bcast:                 core.NewBroadcaster(node),
+		caps:                  newCapabilitiesCache(),
 	}
 
-	if err := dbo.cacheTranscoderPool(); err != nil {
+	if err := dbo.cacheTranscoderPool(ctx); err != nil {
 		return nil, err
 	}
 
@@ -66,7 +69,8 @@ func NewDBOrchestratorPoolCache(ctx context.Context, node *core.LivepeerNode, rm
 	return dbo, nil
 }
 
-func (dbo *DBOrchestratorPoolCache) getURLs() ([]*url.URL, error) {
+// getURLs returns the URIs of the orchestrators in the DB and their registered ETH addresses keyed by URI
+func (dbo *DBOrchestratorPoolCache) getURLs() ([]*url.URL, map[string]ethcommon.Address, error) {
 	orchs, err := dbo.store.SelectOrchs(
 		&common.DBOrchFilter{
 			MaxPrice:       server.BroadcastCfg.MaxPrice(),
@@ -75,20 +79,24 @@ func (dbo *DBOrchestratorPoolCache) getURLs() ([]*url.URL, error) {
 		},
 	)
 	if err != nil || len(orchs) <= 0 {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var uris []*url.URL
+	addrs := make(map[string]ethcommon.Address)
 	for _, orch := range orchs {
 		if uri, err := url.Parse(orch.ServiceURI); err == nil {
 			uris = append(uris, uri)
+			if ethcommon.IsHexAddress(orch.EthereumAddr) {
+				addrs[uri.String()] = ethcommon.HexToAddress(orch.EthereumAddr)
+			}
 		}
 	}
-	return uris, nil
+	return uris, addrs, nil
 }
 
 func (dbo *DBOrchestratorPoolCache) GetInfos() []common.OrchestratorLocalInfo {
-	uris, _ := dbo.getURLs()
+	uris, _, _ := dbo.getURLs()
 	infos := make([]common.OrchestratorLocalInfo, 0, len(uris))
 	for _, uri := range uris {
 		infos = append(infos, common.OrchestratorLocalInfo{URL: uri, Score: common.Score_Untrusted})
@@ -97,7 +105,7 @@ func (dbo *DBOrchestratorPoolCache) GetInfos() []common.OrchestratorLocalInfo {
 }
 
 func (dbo *DBOrchestratorPoolCache) GetInfo(uri string) common.OrchestratorLocalInfo {
-	uris, _ := dbo.getURLs()
+	uris, _, _ := dbo.getURLs()
 	res := common.OrchestratorLocalInfo{
 		Score: common.Score_Untrusted,
 	}
@@ -114,7 +122,7 @@ func (dbo *DBOrchestratorPoolCache) GetInfo(uri string) common.OrchestratorLocal
 func (dbo *DBOrchestratorPoolCache) GetOrchestrators(ctx context.Context, numOrchestrators int, suspender common.Suspender, caps common.CapabilityComparator,
 	scorePred common.ScorePred) ([]*net.OrchestratorInfo, error) {
 
-	uris, err := dbo.getURLs()
+	uris, addrs, err := dbo.getURLs()
 	if err != nil || len(uris) <= 0 {
 		return nil, err
 	}
@@ -156,6 +164,8 @@ func (dbo *DBOrchestratorPoolCache) GetOrchestrators(ctx context.Context, numOrc
 	}
 
 	orchPool := NewOrchestratorPoolWithPred(dbo.bcast, uris, pred, common.Score_Untrusted)
+	orchPool.caps = dbo.caps
+	orchPool.addrs = addrs
 	orchInfos, err := orchPool.GetOrchestrators(ctx, numOrchestrators, suspender, caps, scorePred)
 	if err != nil || len(orchInfos) <= 0 {
 		return nil, err
@@ -182,8 +192,8 @@ func (dbo *DBOrchestratorPoolCache) SizeWith(scorePred common.ScorePred) int {
 	return 0
 }
 
-func (dbo *DBOrchestratorPoolCache) cacheTranscoderPool() error {
-	orchestrators, err := dbo.lpEth.TranscoderPool()
+func (dbo *DBOrchestratorPoolCache) cacheTranscoderPool(ctx context.Context) error {
+	orchestrators, err := dbo.lpEth.TranscoderPool(ctx)
 	if err != nil {
 		return fmt.Errorf("Could not refresh DB list of orchestrators: %v", err)
 	}
@@ -214,7 +224,7 @@ func (dbo *DBOrchestratorPoolCache) cacheOrchestratorStake() error {
 	currentRound := dbo.rm.LastInitializedRound()
 
 	getStake := func(o *common.DBOrch) {
-		ep, err := dbo.lpEth.GetTranscoderEarningsPoolForRound(ethcommon.HexToAddress(o.EthereumAddr), currentRound)
+		ep, err := dbo.lpEth.GetTranscoderEarningsPoolForRound(ctx, ethcommon.HexToAddress(o.EthereumAddr), currentRound)
 		if err != nil {
 			errc <- err
 			return
@@ -295,10 +305,14 @@ func (dbo *DBOrchestratorPoolCache) cacheDBOrchs() error {
 		}
 
 		info, err := serverGetOrchInfo(ctx, dbo.bcast, uri)
+		if err == nil && ethcommon.IsHexAddress(dbOrch.EthereumAddr) {
+			err = server.VerifyOrchestratorAddress(info, ethcommon.HexToAddress(dbOrch.EthereumAddr))
+		}
 		if err != nil {
 			errc <- err
 			return
 		}
+		dbo.caps.update(uri.String(), info.Capabilities)
 
 		// Return early if no ETH address is specified
 		if len(info.Address) == 0 {
@@ -368,6 +382,9 @@ func parseURI(addr string) (*url.URL, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Could not parse orchestrator URI: %v", err)
 	}
+	if (uri.Scheme != "http" && uri.Scheme != "https") || uri.Host == "" {
+		return nil, fmt.Errorf("Could not parse orchestrator URI: invalid scheme or host in %v", addr)
+	}
 	return uri, nil
 }
 
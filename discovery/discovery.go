@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"time"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/livepeer/go-livepeer/clog"
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/monitor"
@@ -27,6 +28,9 @@ type orchestratorPool struct {
 	infos []common.OrchestratorLocalInfo
 	pred  func(info *net.OrchestratorInfo) bool
 	bcast common.Broadcaster
+	caps  *capabilitiesCache
+	// addrs are the registered ETH addresses of the orchestrators keyed by URI, if known
+	addrs map[string]ethcommon.Address
 }
 
 func NewOrchestratorPool(bcast common.Broadcaster, uris []*url.URL, score float32) *orchestratorPool {
@@ -39,7 +43,7 @@ func NewOrchestratorPool(bcast common.Broadcaster, uris []*url.URL, score float3
 		infos = append(infos, common.OrchestratorLocalInfo{URL: uri, Score: score})
 	}
 
-	return &orchestratorPool{infos: infos, bcast: bcast}
+	return &orchestratorPool{infos: infos, bcast: bcast, caps: newCapabilitiesCache()}
 }
 
 func NewOrchestratorPoolWithPred(bcast common.Broadcaster, addresses []*url.URL,
@@ -68,11 +72,19 @@ func (o *orchestratorPool) GetInfo(uri string) common.OrchestratorLocalInfo {
 func (o *orchestratorPool) GetOrchestrators(ctx context.Context, numOrchestrators int, suspender common.Suspender, caps common.CapabilityComparator,
 	scorePred common.ScorePred) ([]*net.OrchestratorInfo, error) {
 
+	// The orchestrators that recently advertised capabilities that are not compatible with the job are not asked for
+	// their info again, they would be filtered out anyway
 	linfos := make([]common.OrchestratorLocalInfo, 0, len(o.infos))
+	incompatible := 0
 	for _, info := range o.infos {
-		if scorePred(info.Score) {
-			linfos = append(linfos, info)
+		if !scorePred(info.Score) {
+			continue
+		}
+		if o.caps.incompatible(info.URL.String(), caps) {
+			incompatible++
+			continue
 		}
+		linfos = append(linfos, info)
 	}
 
 	numAvailableOrchs := len(linfos)
@@ -82,34 +94,29 @@ func (o *orchestratorPool) GetOrchestrators(ctx context.Context, numOrchestrator
 	infoCh := make(chan *net.OrchestratorInfo, numAvailableOrchs)
 	errCh := make(chan error, numAvailableOrchs)
 
-	// The following allows us to avoid capability check for jobs that only
-	// depend on "legacy" features, since older orchestrators support these
-	// features without capability discovery. This enables interop between
-	// older orchestrators and newer orchestrators as long as the job only
-	// requires the legacy feature set.
+	// The capability check is skipped for jobs that only depend on "legacy"
+	// features, since older orchestrators support these features without
+	// capability discovery. This enables interop between older orchestrators
+	// and newer orchestrators as long as the job only requires the legacy
+	// feature set.
 	//
 	// When / if it's justified to completely break interop with older
 	// orchestrators, then we can probably remove this check and work with
 	// the assumption that all orchestrators support capability discovery.
-	legacyCapsOnly := caps.LegacyOnly()
-
 	isCompatible := func(info *net.OrchestratorInfo) bool {
 		if o.pred != nil && !o.pred(info) {
 			return false
 		}
-		// Legacy features already have support on the orchestrator.
-		// Capabilities can be omitted in this case for older orchestrators.
-		// Otherwise, capabilities are required to be present.
-		if info.Capabilities == nil {
-			if legacyCapsOnly {
-				return true
-			}
-			return false
-		}
-		return caps.CompatibleWith(info.Capabilities)
+		return compatibleCapabilities(info.Capabilities, caps)
 	}
 	getOrchInfo := func(uri *url.URL) {
 		info, err := serverGetOrchInfo(ctx, o.bcast, uri)
+		if err == nil {
+			err = server.VerifyOrchestratorAddress(info, o.addrs[uri.String()])
+		}
+		if err == nil {
+			o.caps.update(uri.String(), info.Capabilities)
+		}
 		if err == nil && isCompatible(info) {
 			infoCh <- info
 			return
@@ -162,8 +169,8 @@ func (o *orchestratorPool) GetOrchestrators(ctx context.Context, numOrchestrator
 		}
 	}
 
-	clog.Infof(ctx, "Done fetching orch info numOrch=%d responses=%d/%d timeout=%t",
-		len(infos), nbResp, len(uris), timeout)
+	clog.Infof(ctx, "Done fetching orch info numOrch=%d responses=%d/%d incompatible=%d timeout=%t",
+		len(infos), nbResp, len(uris), incompatible, timeout)
 	return infos, nil
 }
 
@@ -301,7 +301,7 @@ func TestNewDBOrchestratorPoolCache_GivenListOfOrchs_CreatesPoolCacheCorrectly(t
 		}
 		mu.Unlock()
 		return &net.OrchestratorInfo{
-			Address:    pm.RandBytes(20),
+			Address:    stubOrchAddress(orchestratorServer),
 			Transcoder: expTranscoder,
 			PriceInfo:  expPriceInfo,
 		}, nil
@@ -491,7 +491,9 @@ func TestNewDBOrchestorPoolCache_PollOrchestratorInfo(t *testing.T) {
 			first = false
 		}
 		callCount++
-		return returnInfo, nil
+		info := *returnInfo
+		info.Address = stubOrchAddress(orchestratorServer)
+		return &info, nil
 	}
 
 	dbh, dbraw, err := common.TempDB(t)
@@ -641,7 +643,7 @@ func TestCachedPool_AllOrchestratorsTooExpensive_ReturnsEmptyList(t *testing.T)
 		}
 		mu.Unlock()
 		return &net.OrchestratorInfo{
-			Address:    pm.RandBytes(20),
+			Address:    stubOrchAddress(orchestratorServer),
 			Transcoder: expTranscoder,
 			PriceInfo:  expPriceInfo,
 		}, nil
@@ -729,7 +731,7 @@ func TestCachedPool_GetOrchestrators_MaxBroadcastPriceNotSet(t *testing.T) {
 		}
 		mu.Unlock()
 		return &net.OrchestratorInfo{
-			Address:    pm.RandBytes(20),
+			Address:    stubOrchAddress(orchestratorServer),
 			Transcoder: expTranscoder,
 			PriceInfo:  expPriceInfo,
 		}, nil
@@ -834,12 +836,13 @@ func TestCachedPool_N_OrchestratorsGoodPricing_ReturnsNOrchestrators(t *testing.
 			first = false
 		}
 		mu.Unlock()
+		info := *badTranscoder
 		if i, _ := strconv.Atoi(orchestratorServer.Port()); i > 8960 {
 			// Return valid pricing
-			return goodTranscoder, nil
+			info = *goodTranscoder
 		}
-		// Return invalid pricing
-		return badTranscoder, nil
+		info.Address = stubOrchAddress(orchestratorServer)
+		return &info, nil
 	}
 	addresses := []string{}
 	for i := 0; i < 50; i++ {
@@ -926,7 +929,7 @@ func TestCachedPool_GetOrchestrators_TicketParamsValidation(t *testing.T) {
 
 	serverGetOrchInfo = func(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL) (*net.OrchestratorInfo, error) {
 		return &net.OrchestratorInfo{
-			Address:      pm.RandBytes(20),
+			Address:      stubOrchAddress(orchestratorServer),
 			Transcoder:   "transcoder",
 			TicketParams: &net.TicketParams{},
 			PriceInfo: &net.PriceInfo{
@@ -1006,7 +1009,7 @@ func TestCachedPool_GetOrchestrators_OnlyActiveOrchestrators(t *testing.T) {
 		}
 		mu.Unlock()
 		return &net.OrchestratorInfo{
-			Address:    pm.RandBytes(20),
+			Address:    stubOrchAddress(orchestratorServer),
 			Transcoder: expTranscoder,
 			PriceInfo:  expPriceInfo,
 		}, nil
@@ -1323,6 +1326,36 @@ func TestOrchestratorPool_GetOrchestrators(t *testing.T) {
 
 }
 
+func TestOrchestratorPool_SkipsOrchestratorsWithUnexpectedAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	addresses := stringsToURIs([]string{"https://127.0.0.1:8936", "https://127.0.0.1:8937", "https://127.0.0.1:8938"})
+	spoofed := addresses[1].String()
+
+	oldOrchInfo := serverGetOrchInfo
+	defer func() { serverGetOrchInfo = oldOrchInfo }()
+	serverGetOrchInfo = func(ctx context.Context, bcast common.Broadcaster, server *url.URL) (*net.OrchestratorInfo, error) {
+		info := &net.OrchestratorInfo{Transcoder: server.String(), Address: stubOrchAddress(server)}
+		if server.String() == spoofed {
+			info.Address = pm.RandBytes(20)
+		}
+		return info, nil
+	}
+
+	pool := NewOrchestratorPool(nil, addresses, common.Score_Trusted)
+	pool.addrs = make(map[string]ethcommon.Address)
+	for _, uri := range addresses {
+		pool.addrs[uri.String()] = ethcommon.BytesToAddress(stubOrchAddress(uri))
+	}
+
+	res, err := pool.GetOrchestrators(context.TODO(), len(addresses), newStubSuspender(), newStubCapabilities(), common.ScoreAtLeast(0))
+	assert.Nil(err)
+	assert.Len(res, 2)
+	for _, info := range res {
+		assert.NotEqual(spoofed, info.Transcoder)
+	}
+}
+
 func TestOrchestratorPool_GetOrchestrators_SuspendedOrchs(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -1567,7 +1600,7 @@ func TestOrchestratorPool_Capabilities(t *testing.T) {
 	i4 := &net.OrchestratorInfo{Capabilities: &net.Capabilities{Bitstring: capCompatString}}
 
 	responses := []*net.OrchestratorInfo{i1, i2, i3, i4}
-	addresses := stringsToURIs([]string{"a://b", "a://b", "a://b", "a://b"})
+	addresses := stringsToURIs([]string{"a://b1", "a://b2", "a://b3", "a://b4"})
 	pool := NewOrchestratorPool(nil, addresses, common.Score_Trusted)
 
 	// some sanity checks
@@ -1577,17 +1610,16 @@ func TestOrchestratorPool_Capabilities(t *testing.T) {
 	assert.Equal(capCompatString, i4.Capabilities.Bitstring)
 	assert.True(newStubCapabilities().CompatibleWith(i4.Capabilities))
 
-	mu := &sync.Mutex{}
-	calls := 0
+	// Each orchestrator always advertises the same capabilities
 	oldOrchInfo := serverGetOrchInfo
 	defer func() { serverGetOrchInfo = oldOrchInfo }()
 	serverGetOrchInfo = func(ctx context.Context, bcast common.Broadcaster, server *url.URL) (*net.OrchestratorInfo, error) {
-		mu.Lock()
-		defer func() {
-			calls = (calls + 1) % len(responses)
-			mu.Unlock()
-		}()
-		return responses[calls], nil
+		for i, addr := range addresses {
+			if addr.String() == server.String() {
+				return responses[i], nil
+			}
+		}
+		return nil, errors.New("unknown orchestrator")
 	}
 	sus := newStubSuspender()
 
@@ -1618,3 +1650,81 @@ func TestOrchestratorPool_Capabilities(t *testing.T) {
 	assert.Len(infos, 1)
 	assert.Equal(i4, infos[0])
 }
+
+func TestOrchestratorPool_SkipsIncompatibleOrchestrators(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	legacy := &net.OrchestratorInfo{Transcoder: "legacy"}
+	incompatible := &net.OrchestratorInfo{Transcoder: "incompatible", Capabilities: &net.Capabilities{Bitstring: []uint64{1}}}
+	compatible := &net.OrchestratorInfo{Transcoder: "compatible", Capabilities: &net.Capabilities{Bitstring: capCompatString}}
+	responses := map[string]*net.OrchestratorInfo{"https://legacy": legacy, "https://incompatible": incompatible, "https://compatible": compatible}
+	pool := NewOrchestratorPool(nil, stringsToURIs([]string{"https://legacy", "https://incompatible", "https://compatible"}), common.Score_Trusted)
+
+	mu := &sync.Mutex{}
+	var requested []string
+	oldOrchInfo, oldTTL := serverGetOrchInfo, capabilitiesTTL
+	defer func() { serverGetOrchInfo, capabilitiesTTL = oldOrchInfo, oldTTL }()
+	serverGetOrchInfo = func(ctx context.Context, bcast common.Broadcaster, server *url.URL) (*net.OrchestratorInfo, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		requested = append(requested, server.String())
+		return responses[server.String()], nil
+	}
+	getOrchestrators := func(caps common.CapabilityComparator) ([]*net.OrchestratorInfo, []string) {
+		mu.Lock()
+		requested = nil
+		mu.Unlock()
+		infos, err := pool.GetOrchestrators(context.TODO(), 3, newStubSuspender(), caps, common.ScoreAtLeast(0))
+		require.Nil(err)
+		mu.Lock()
+		defer mu.Unlock()
+		return infos, requested
+	}
+
+	// All orchestrators are asked for their info until their capabilities are known
+	caps := newStubCapabilities()
+	caps.isLegacy = false
+	infos, requested := getOrchestrators(caps)
+	assert.Equal([]*net.OrchestratorInfo{compatible}, infos)
+	assert.Len(requested, 3)
+
+	infos, requested = getOrchestrators(caps)
+	assert.Equal([]*net.OrchestratorInfo{compatible}, infos)
+	assert.Equal([]string{"https://compatible"}, requested)
+
+	// Orchestrators without capabilities support jobs with legacy capabilities only
+	caps.isLegacy = true
+	infos, requested = getOrchestrators(caps)
+	assert.ElementsMatch([]*net.OrchestratorInfo{legacy, compatible}, infos)
+	assert.ElementsMatch([]string{"https://legacy", "https://compatible"}, requested)
+
+	// The orchestrators are asked again once their capabilities expire
+	capabilitiesTTL = 0
+	caps.isLegacy = false
+	infos, requested = getOrchestrators(caps)
+	assert.Equal([]*net.OrchestratorInfo{compatible}, infos)
+	assert.Len(requested, 3)
+}
+
+func TestParseURI(t *testing.T) {
+	assert := assert.New(t)
+
+	uri, err := parseURI("127.0.0.1:8935")
+	assert.Nil(err)
+	assert.Equal("https://127.0.0.1:8935", uri.String())
+
+	uri, err = parseURI("http://127.0.0.1:8935")
+	assert.Nil(err)
+	assert.Equal("http://127.0.0.1:8935", uri.String())
+
+	for _, addr := range []string{"https://", "httpx://127.0.0.1:8935", "badUrl\\://127.0.0.1:8936", "https://127.0.0.1:8935/%zz"} {
+		_, err := parseURI(addr)
+		assert.Error(err, addr)
+	}
+}
+
+// stubOrchAddress returns the registered address of the orchestrator at uri created by StubOrchestrators
+func stubOrchAddress(uri *url.URL) []byte {
+	return ethcommon.BytesToAddress([]byte(uri.String())).Bytes()
+}
@@ -0,0 +1,22 @@
+//go:build go1.18
+// +build go1.18
+
+package discovery
+
+import "testing"
+
+func FuzzParseURI(f *testing.F) {
+	f.Add("127.0.0.1:8935")
+	f.Add("https://127.0.0.1:8935")
+	f.Add("https://")
+
+	f.Fuzz(func(t *testing.T, addr string) {
+		uri, err := parseURI(addr)
+		if err != nil {
+			return
+		}
+		if (uri.Scheme != "http" && uri.Scheme != "https") || uri.Host == "" {
+			t.Fatalf("invalid URI accepted for %q: %v", addr, uri)
+		}
+	})
+}
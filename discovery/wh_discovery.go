@@ -31,6 +31,8 @@ type webhookPool struct {
 	lastRequest  time.Time
 	mu           *sync.RWMutex
 	bcast        common.Broadcaster
+	// caps are the capabilities advertised by the orchestrators, kept across the responses of the webhook
+	caps *capabilitiesCache
 }
 
 func NewWebhookPool(bcast common.Broadcaster, callback *url.URL) *webhookPool {
@@ -38,6 +40,7 @@ func NewWebhookPool(bcast common.Broadcaster, callback *url.URL) *webhookPool {
 		callback: callback,
 		mu:       &sync.RWMutex{},
 		bcast:    bcast,
+		caps:     newCapabilitiesCache(),
 	}
 	go p.getInfos()
 	return p
@@ -75,7 +78,7 @@ func (w *webhookPool) getInfos() ([]common.OrchestratorLocalInfo, error) {
 	}
 
 	// pool = NewOrchestratorPool(w.bcast, addrs)
-	pool = &orchestratorPool{infos: infos, bcast: w.bcast}
+	pool = &orchestratorPool{infos: infos, bcast: w.bcast, caps: w.caps}
 
 	w.mu.Lock()
 	w.responseHash = hash
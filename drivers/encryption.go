@@ -0,0 +1,322 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+// RecordEncryption is the KMS that wraps the keys that recordings are encrypted with. Recordings are not encrypted if
+// nil
+var RecordEncryption KMS
+
+// KMS generates the data keys that objects are encrypted with and wraps them with a master key that never leaves the
+// KMS, so that only the wrapped data keys are stored together with the objects
+type KMS interface {
+	// GenerateDataKey returns a new AES-256 data key and the data key wrapped with the master key
+	GenerateDataKey(ctx context.Context) (key, wrappedKey []byte, err error)
+	// DecryptDataKey returns the data key of wrappedKey
+	DecryptDataKey(ctx context.Context, wrappedKey []byte) ([]byte, error)
+}
+
+// encryptedMagic starts the objects that are encrypted by encryptedSession. It is followed by the big endian length of
+// the wrapped data key, the wrapped data key, the AES-GCM nonce and the sealed data
+var encryptedMagic = []byte("LPENC1")
+
+// maxCachedDataKeys is the number of decrypted data keys that are kept so that reading the objects of a stream does
+// not call the KMS for every object
+const maxCachedDataKeys = 1000
+
+// kmsTimeout is how long a call to the KMS may take
+var kmsTimeout = 10 * time.Second
+
+var dataKeys = struct {
+	sync.Mutex
+	keys map[string][]byte
+}{keys: make(map[string][]byte)}
+
+// ParseKMSURL returns the KMS of the URL. Supported are local master keys, file:///path/to/key where the file holds a
+// hex encoded 32 byte key, and AWS KMS keys, awskms://<key ID, ARN or alias>?region=<region>. The region can be left
+// out if it is part of the ARN or set in the environment
+func ParseKMSURL(input string) (KMS, error) {
+	switch {
+	case strings.HasPrefix(input, "file://"):
+		u, err := url.Parse(input)
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadFile(u.Path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("master key is not hex encoded: %v", err)
+		}
+		return NewLocalKMS(key)
+	case strings.HasPrefix(input, "awskms://"):
+		keyID := strings.TrimPrefix(input, "awskms://")
+		var region string
+		if i := strings.Index(keyID, "?"); i >= 0 {
+			query, err := url.ParseQuery(keyID[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			region = query.Get("region")
+			keyID = keyID[:i]
+		}
+		if keyID == "" {
+			return nil, errors.New("missing AWS KMS key ID")
+		}
+		if parts := strings.Split(keyID, ":"); region == "" && len(parts) > 3 && parts[0] == "arn" {
+			region = parts[3]
+		}
+		return NewAWSKMS(keyID, region)
+	}
+	return nil, fmt.Errorf("unsupported KMS URL %q", input)
+}
+
+type localKMS struct {
+	master cipher.AEAD
+}
+
+// NewLocalKMS returns a KMS that wraps the data keys with the 32 byte master key using AES-GCM
+func NewLocalKMS(masterKey []byte) (KMS, error) {
+	if len(masterKey) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(masterKey))
+	}
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &localKMS{master: aead}, nil
+}
+
+func (k *localKMS) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, err
+	}
+	wrapped, err := seal(k.master, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, wrapped, nil
+}
+
+func (k *localKMS) DecryptDataKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	return open(k.master, wrappedKey, nil)
+}
+
+type awsKMS struct {
+	keyID string
+	svc   kmsiface.KMSAPI
+}
+
+// NewAWSKMS returns a KMS that generates and decrypts the data keys with the AWS KMS key keyID. The credentials are
+// taken from the environment
+func NewAWSKMS(keyID, region string) (KMS, error) {
+	cfg := aws.NewConfig()
+	if region != "" {
+		cfg = cfg.WithRegion(region)
+	}
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &awsKMS{keyID: keyID, svc: kms.New(sess)}, nil
+}
+
+func (k *awsKMS) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	out, err := k.svc.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(k.keyID),
+		KeySpec: aws.String(kms.DataKeySpecAes256),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return out.Plaintext, out.CiphertextBlob, nil
+}
+
+func (k *awsKMS) DecryptDataKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	out, err := k.svc.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(k.keyID),
+		CiphertextBlob: wrappedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+// encryptedSession encrypts the objects that are saved with a data key of its own and decrypts the encrypted objects
+// that are read. Objects that are not encrypted are read as they are
+type encryptedSession struct {
+	OSSession
+	kms KMS
+
+	mu         sync.Mutex
+	key        []byte
+	wrappedKey []byte
+}
+
+// NewEncryptedSession returns a session that encrypts the objects saved to sess with AES-GCM. The data key of the
+// session is generated by kms when the first object is saved, so each session, i.e. the recording of a stream, has
+// a key of its own. The wrapped data key is stored with each object
+func NewEncryptedSession(sess OSSession, kms KMS) OSSession {
+	return &encryptedSession{OSSession: sess, kms: kms}
+}
+
+func (s *encryptedSession) SaveData(ctx context.Context, name string, data []byte, meta map[string]string, timeout time.Duration) (string, error) {
+	key, wrappedKey, err := s.dataKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not generate data key: %w", err)
+	}
+	encrypted, err := encryptObject(key, wrappedKey, data)
+	if err != nil {
+		return "", err
+	}
+	return s.OSSession.SaveData(ctx, name, encrypted, meta, timeout)
+}
+
+func (s *encryptedSession) ReadData(ctx context.Context, name string) (*FileInfoReader, error) {
+	fi, err := s.OSSession.ReadData(ctx, name)
+	if err != nil || fi == nil || fi.Body == nil {
+		return fi, err
+	}
+	data, err := ioutil.ReadAll(fi.Body)
+	fi.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if data, err = DecryptObject(ctx, s.kms, data); err != nil {
+		return nil, fmt.Errorf("could not decrypt name=%s: %w", name, err)
+	}
+	fi.Body = ioutil.NopCloser(bytes.NewReader(data))
+	fi.Size = int64(len(data))
+	return fi, nil
+}
+
+func (s *encryptedSession) dataKey(ctx context.Context) ([]byte, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key == nil {
+		ctx, cancel := context.WithTimeout(ctx, kmsTimeout)
+		defer cancel()
+		key, wrappedKey, err := s.kms.GenerateDataKey(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.key, s.wrappedKey = key, wrappedKey
+	}
+	return s.key, s.wrappedKey, nil
+}
+
+func encryptObject(key, wrappedKey, data []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(encryptedMagic)+2, len(encryptedMagic)+2+len(wrappedKey))
+	copy(header, encryptedMagic)
+	binary.BigEndian.PutUint16(header[len(encryptedMagic):], uint16(len(wrappedKey)))
+	header = append(header, wrappedKey...)
+	// The header is authenticated so that the wrapped key of an object cannot be swapped
+	sealed, err := seal(aead, data, header)
+	if err != nil {
+		return nil, err
+	}
+	return append(header, sealed...), nil
+}
+
+// DecryptObject returns the data of an object encrypted by a session of NewEncryptedSession, decrypting its data key
+// with kms. Data that is not encrypted is returned as it is
+func DecryptObject(ctx context.Context, kms KMS, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, encryptedMagic) {
+		return data, nil
+	}
+	n := len(encryptedMagic)
+	if len(data) < n+2 {
+		return nil, errors.New("truncated encryption header")
+	}
+	headerLen := n + 2 + int(binary.BigEndian.Uint16(data[n:]))
+	if len(data) < headerLen {
+		return nil, errors.New("truncated encryption header")
+	}
+	header, wrappedKey := data[:headerLen], data[n+2:headerLen]
+
+	key, err := decryptDataKey(ctx, kms, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt data key: %w", err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, data[headerLen:], header)
+}
+
+func decryptDataKey(ctx context.Context, kms KMS, wrappedKey []byte) ([]byte, error) {
+	dataKeys.Lock()
+	key, ok := dataKeys.keys[string(wrappedKey)]
+	dataKeys.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kmsTimeout)
+	defer cancel()
+	key, err := kms.DecryptDataKey(ctx, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKeys.Lock()
+	if len(dataKeys.keys) >= maxCachedDataKeys {
+		dataKeys.keys = make(map[string][]byte)
+	}
+	dataKeys.keys[string(wrappedKey)] = key
+	dataKeys.Unlock()
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns the random nonce followed by the sealed data
+func seal(aead cipher.AEAD, data, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("truncated encrypted data")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
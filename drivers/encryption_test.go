@@ -0,0 +1,159 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingKMS struct {
+	KMS
+	generated, decrypted int
+	err                  error
+}
+
+func (k *countingKMS) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	k.generated++
+	if k.err != nil {
+		return nil, nil, k.err
+	}
+	return k.KMS.GenerateDataKey(ctx)
+}
+
+func (k *countingKMS) DecryptDataKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	k.decrypted++
+	return k.KMS.DecryptDataKey(ctx, wrappedKey)
+}
+
+func TestLocalKMS(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, err := NewLocalKMS([]byte("short"))
+	assert.EqualError(err, "master key must be 32 bytes, got 5")
+
+	kms, err := NewLocalKMS(bytes.Repeat([]byte{1}, 32))
+	require.Nil(err)
+	key, wrappedKey, err := kms.GenerateDataKey(context.Background())
+	require.Nil(err)
+	assert.Len(key, 32)
+	assert.NotContains(string(wrappedKey), string(key))
+
+	decrypted, err := kms.DecryptDataKey(context.Background(), wrappedKey)
+	assert.Nil(err)
+	assert.Equal(key, decrypted)
+
+	// Keys wrapped with another master key cannot be decrypted
+	other, err := NewLocalKMS(bytes.Repeat([]byte{2}, 32))
+	require.Nil(err)
+	_, err = other.DecryptDataKey(context.Background(), wrappedKey)
+	assert.NotNil(err)
+}
+
+func TestParseKMSURL(t *testing.T) {
+	assert := assert.New(t)
+
+	keyFile := filepath.Join(t.TempDir(), "master.key")
+	assert.Nil(ioutil.WriteFile(keyFile, []byte("0101010101010101010101010101010101010101010101010101010101010101\n"), 0600))
+	kms, err := ParseKMSURL("file://" + keyFile)
+	assert.Nil(err)
+	assert.IsType(&localKMS{}, kms)
+
+	assert.Nil(ioutil.WriteFile(keyFile, []byte("foo"), 0600))
+	_, err = ParseKMSURL("file://" + keyFile)
+	assert.Contains(err.Error(), "master key is not hex encoded")
+
+	_, err = ParseKMSURL("file://" + filepath.Join(t.TempDir(), "missing.key"))
+	assert.True(errors.Is(err, os.ErrNotExist))
+
+	kms, err = ParseKMSURL("awskms://arn:aws:kms:us-east-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab")
+	assert.Nil(err)
+	assert.Equal("arn:aws:kms:us-east-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab", kms.(*awsKMS).keyID)
+
+	kms, err = ParseKMSURL("awskms://alias/recordings?region=eu-west-1")
+	assert.Nil(err)
+	assert.Equal("alias/recordings", kms.(*awsKMS).keyID)
+
+	_, err = ParseKMSURL("awskms://")
+	assert.EqualError(err, "missing AWS KMS key ID")
+
+	_, err = ParseKMSURL("foo://bar")
+	assert.EqualError(err, `unsupported KMS URL "foo://bar"`)
+}
+
+func TestEncryptedSession(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	local, err := NewLocalKMS(bytes.Repeat([]byte{1}, 32))
+	require.Nil(err)
+	kms := &countingKMS{KMS: local}
+	store := NewMemoryDriver(nil)
+
+	// Objects are stored encrypted with the data key of the session
+	sess := NewEncryptedSession(store.NewSession("stream1"), kms)
+	_, err = sess.SaveData(context.Background(), "source/1.ts", []byte("segment 1"), nil, 0)
+	require.Nil(err)
+	_, err = sess.SaveData(context.Background(), "source/2.ts", []byte("segment 2"), nil, 0)
+	require.Nil(err)
+	assert.Equal(1, kms.generated)
+
+	plain := store.NewSession("stream1").(*MemorySession)
+	stored := plain.GetData("stream1/source/1.ts")
+	assert.True(bytes.HasPrefix(stored, encryptedMagic))
+	assert.NotContains(string(stored), "segment 1")
+
+	// Objects are decrypted when read, calling the KMS once per data key
+	fi, err := sess.ReadData(context.Background(), "stream1/source/1.ts")
+	require.Nil(err)
+	data, _ := ioutil.ReadAll(fi.Body)
+	assert.Equal("segment 1", string(data))
+	assert.Equal(int64(9), fi.Size)
+	fi, err = NewEncryptedSession(plain, kms).ReadData(context.Background(), "stream1/source/2.ts")
+	require.Nil(err)
+	data, _ = ioutil.ReadAll(fi.Body)
+	assert.Equal("segment 2", string(data))
+	assert.Equal(1, kms.decrypted)
+
+	// Each session has a data key of its own
+	sess2 := NewEncryptedSession(store.NewSession("stream2"), kms)
+	_, err = sess2.SaveData(context.Background(), "source/1.ts", []byte("segment 1"), nil, 0)
+	require.Nil(err)
+	assert.Equal(2, kms.generated)
+	stored2 := store.NewSession("stream2").(*MemorySession).GetData("stream2/source/1.ts")
+	require.NotNil(stored2)
+	headerLen := len(encryptedMagic) + 2 + 60
+	assert.NotEqual(stored[:headerLen], stored2[:headerLen])
+
+	// Objects that are not encrypted are read as they are
+	_, err = plain.SaveData(context.Background(), "source/3.ts", []byte("plain"), nil, 0)
+	require.Nil(err)
+	fi, err = sess.ReadData(context.Background(), "stream1/source/3.ts")
+	require.Nil(err)
+	data, _ = ioutil.ReadAll(fi.Body)
+	assert.Equal("plain", string(data))
+
+	// Tampered objects are not decrypted
+	tampered := append([]byte{}, stored...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = plain.SaveData(context.Background(), "source/4.ts", tampered, nil, 0)
+	require.Nil(err)
+	_, err = sess.ReadData(context.Background(), "stream1/source/4.ts")
+	assert.Contains(err.Error(), "could not decrypt name=stream1/source/4.ts")
+
+	_, err = DecryptObject(context.Background(), kms, encryptedMagic)
+	assert.EqualError(err, "truncated encryption header")
+
+	// Objects are not saved if the data key cannot be generated
+	kms.err = errors.New("access denied")
+	_, err = NewEncryptedSession(store.NewSession("stream3"), kms).SaveData(context.Background(), "source/1.ts", []byte("segment 1"), nil, 0)
+	assert.EqualError(err, "could not generate data key: access denied")
+	assert.Nil(store.NewSession("stream3").(*MemorySession).GetData("stream3/source/1.ts"))
+}
@@ -154,6 +154,9 @@ func (os *gsSession) createClient() error {
 }
 
 func (os *gsSession) SaveData(ctx context.Context, name string, data []byte, meta map[string]string, timeout time.Duration) (string, error) {
+	if err := common.InjectFault(common.FaultStorage); err != nil {
+		return "", err
+	}
 	if os.useFullAPI {
 		if os.client == nil {
 			if err := os.createClient(); err != nil {
@@ -311,6 +311,9 @@ func (os *s3Session) saveDataPut(ctx context.Context, name string, data []byte,
 }
 
 func (os *s3Session) SaveData(ctx context.Context, name string, data []byte, meta map[string]string, timeout time.Duration) (string, error) {
+	if err := common.InjectFault(common.FaultStorage); err != nil {
+		return "", err
+	}
 	if os.s3svc != nil {
 		return os.saveDataPut(ctx, name, data, meta, timeout)
 	}
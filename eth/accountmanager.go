@@ -14,12 +14,13 @@ import (
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/golang/glog"
 	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/lperrors"
 )
 
 var (
-	ErrAccountNotFound    = fmt.Errorf("ETH account not found")
-	ErrLocked             = fmt.Errorf("account locked")
-	ErrPassphraseMismatch = fmt.Errorf("passphrases do not match")
+	ErrAccountNotFound    = lperrors.User(fmt.Errorf("ETH account not found"))
+	ErrLocked             = lperrors.User(fmt.Errorf("account locked"))
+	ErrPassphraseMismatch = lperrors.User(fmt.Errorf("passphrases do not match"))
 )
 
 type AccountManager interface {
@@ -7,6 +7,7 @@ import (
 	"math/big"
 	"strings"
 	"sync"
+	"time"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -16,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/golang/glog"
 	"github.com/livepeer/go-livepeer/eth/contracts"
+	"github.com/livepeer/go-livepeer/lperrors"
 )
 
 var abis = []string{
@@ -46,6 +48,9 @@ type Backend interface {
 	ChainID(ctx context.Context) (*big.Int, error)
 	GasPriceMonitor() *GasPriceMonitor
 	SuggestGasTipCap(context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	// ResyncNonce resets the local nonce of account to the pending nonce of the ethereum client
+	ResyncNonce(account common.Address) error
 }
 
 type backend struct {
@@ -54,17 +59,20 @@ type backend struct {
 	signer       types.Signer
 	gpm          *GasPriceMonitor
 	tm           *TransactionManager
+	// rpcTimeout bounds each contract call, so that a call without a deadline does not block on an unresponsive node
+	rpcTimeout time.Duration
 
 	sync.RWMutex
 }
 
-func NewBackend(client *ethclient.Client, signer types.Signer, gpm *GasPriceMonitor, tm *TransactionManager) Backend {
+func NewBackend(client *ethclient.Client, signer types.Signer, gpm *GasPriceMonitor, tm *TransactionManager, rpcTimeout time.Duration) Backend {
 	return &backend{
 		Client:       client,
 		nonceManager: NewNonceManager(client),
 		signer:       signer,
 		gpm:          gpm,
 		tm:           tm,
+		rpcTimeout:   rpcTimeout,
 	}
 }
 
@@ -75,14 +83,27 @@ func (b *backend) PendingNonceAt(ctx context.Context, account common.Address) (u
 	return b.nonceManager.Next(account)
 }
 
+func (b *backend) ResyncNonce(account common.Address) error {
+	b.nonceManager.Lock(account)
+	defer b.nonceManager.Unlock(account)
+
+	return b.nonceManager.Resync(account)
+}
+
 func (b *backend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	// Use the transaction manager instead of the ethereum client
-	if err := b.tm.SendTransaction(ctx, tx); err != nil {
+	sender, err := types.Sender(b.signer, tx)
+	if err != nil {
 		return err
 	}
 
-	sender, err := types.Sender(b.signer, tx)
-	if err != nil {
+	// Use the transaction manager instead of the ethereum client
+	if err := b.tm.SendTransaction(ctx, tx); err != nil {
+		if isNonceTooLow(err) {
+			// The nonce was used by another transaction, skip it for the next transaction
+			b.nonceManager.Lock(sender)
+			b.nonceManager.Update(sender, tx.Nonce())
+			b.nonceManager.Unlock(sender)
+		}
 		return err
 	}
 
@@ -101,10 +122,10 @@ func (b *backend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
 	maxGp := b.gpm.MaxGasPrice()
 
 	if maxGp != nil && gp.Cmp(maxGp) > 0 {
-		return nil, fmt.Errorf("current gas price exceeds maximum gas price max=%v GWei current=%v GWei",
+		return nil, lperrors.Retryable(fmt.Errorf("current gas price exceeds maximum gas price max=%v GWei current=%v GWei",
 			FromWei(maxGp, params.GWei),
 			FromWei(gp, params.GWei),
-		)
+		))
 	}
 
 	return gp, nil
@@ -149,17 +170,31 @@ type txLog struct {
 }
 
 func (b *backend) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	ctx, cancel := b.callContext(ctx)
+	defer cancel()
+
 	return b.retryRemoteCall(func() ([]byte, error) {
 		return b.Client.CallContract(ctx, msg, blockNumber)
 	})
 }
 
 func (b *backend) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	ctx, cancel := b.callContext(ctx)
+	defer cancel()
+
 	return b.retryRemoteCall(func() ([]byte, error) {
 		return b.Client.PendingCallContract(ctx, msg)
 	})
 }
 
+// callContext returns ctx with the RPC timeout of the backend applied. The timeout is not applied if it is 0
+func (b *backend) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b.rpcTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, b.rpcTimeout)
+}
+
 func (b *backend) retryRemoteCall(remoteCall func() ([]byte, error)) (out []byte, err error) {
 	count := 3    // consider making this a package-level global constant
 	retry := true // consider making this a package-level global constant
@@ -176,6 +211,11 @@ func (b *backend) retryRemoteCall(remoteCall func() ([]byte, error)) (out []byte
 	return out, err
 }
 
+// isNonceTooLow returns true if the ethereum client rejected a transaction because its nonce was already used
+func isNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "nonce too low")
+}
+
 func makeABIMap() map[string]*abi.ABI {
 	abiMap := make(map[string]*abi.ABI)
 
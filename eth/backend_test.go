@@ -79,9 +79,9 @@ func TestSendTransaction_SendErr_DontUpdateNonce(t *testing.T) {
 	}, 1*time.Second, big.NewInt(0), nil)
 	gpm.gasPrice = big.NewInt(1)
 
-	tm := NewTransactionManager(client, gpm, &accountManager{}, 3*time.Second, 0)
+	tm := NewTransactionManager(client, gpm, &accountManager{}, 3*time.Second, 0, 0, 0)
 
-	bi := NewBackend(client, signer, gpm, tm)
+	bi := NewBackend(client, signer, gpm, tm, 0)
 
 	nonceLockBefore := bi.(*backend).nonceManager.getNonceLock(fromAddress)
 
@@ -93,3 +93,32 @@ func TestSendTransaction_SendErr_DontUpdateNonce(t *testing.T) {
 
 	assert.Equal(t, nonceLockBefore.nonce, nonceLockAfter.nonce)
 }
+
+func TestBackend_CallContext(t *testing.T) {
+	assert := assert.New(t)
+
+	// Calls are bounded by the RPC timeout
+	b := &backend{rpcTimeout: time.Minute}
+	ctx, cancel := b.callContext(context.Background())
+	deadline, ok := ctx.Deadline()
+	assert.True(ok)
+	assert.WithinDuration(time.Now().Add(time.Minute), deadline, time.Second)
+	cancel()
+
+	// An earlier deadline of the caller is kept
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Second)
+	defer parentCancel()
+	ctx, cancel = b.callContext(parent)
+	deadline, _ = ctx.Deadline()
+	parentDeadline, _ := parent.Deadline()
+	assert.Equal(parentDeadline, deadline)
+	cancel()
+
+	// Calls are not bounded without an RPC timeout
+	b = &backend{}
+	ctx, cancel = b.callContext(context.Background())
+	_, ok = ctx.Deadline()
+	assert.False(ok)
+	cancel()
+	assert.NotNil(ctx.Err())
+}
@@ -0,0 +1,218 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/monitor"
+)
+
+// ErrBalanceLow is returned for the transactions that are halted while the ETH balance is below the min balance
+var ErrBalanceLow = errors.New("transaction halted because the ETH balance is below the min balance")
+
+// balanceWatchInterval is how often the ETH balance is checked
+var balanceWatchInterval = time.Minute
+
+// BalanceReader reads the ETH balance of accounts, i.e. an ethclient.Client
+type BalanceReader interface {
+	BalanceAt(ctx context.Context, account ethcommon.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// TxSpendReader reads the journal of the submitted txs, i.e. the DB
+type TxSpendReader interface {
+	TxJournal(filter *common.DBTxJournalFilter) ([]*common.DBTxJournalEntry, error)
+}
+
+// BalanceWatcher estimates the runway of the ETH balance of an account, which is how long the balance lasts at the
+// rate that the txs mined during the last SpendWindow spent on gas. An alert is logged when the balance drops below
+// MinBalance, and the txs of the halted methods are refused while it is below MinBalance so that the remaining ETH is
+// left for the other txs
+type BalanceWatcher struct {
+	MinBalance  *big.Int
+	SpendWindow time.Duration
+
+	addr     ethcommon.Address
+	balances BalanceReader
+	journal  TxSpendReader
+	halted   map[string]bool
+
+	mu   sync.Mutex
+	low  bool
+	quit chan struct{}
+}
+
+// BalanceStatus is the state of the ETH balance of the account
+type BalanceStatus struct {
+	Balance    *big.Int `json:"balance"`
+	MinBalance *big.Int `json:"minBalance"`
+	// Wei spent on the gas of mined txs per hour over the spend window
+	SpendRate *big.Int `json:"spendRate"`
+	// Seconds until the balance is spent, -1 if nothing was spent
+	RunwaySeconds float64 `json:"runwaySeconds"`
+	Low           bool    `json:"low"`
+	// Halted are the methods whose txs are refused while the balance is low
+	Halted []string `json:"halted"`
+}
+
+// NewBalanceWatcher creates a BalanceWatcher for the ETH balance of addr. The spend is read from journal, nothing is
+// spent if it is nil. The txs of haltMethods, i.e. "reward", are refused while the balance is below minBalance. The
+// balance is never low if minBalance is nil
+func NewBalanceWatcher(addr ethcommon.Address, balances BalanceReader, journal TxSpendReader, minBalance *big.Int, spendWindow time.Duration, haltMethods []string) *BalanceWatcher {
+	halted := make(map[string]bool)
+	for _, m := range haltMethods {
+		halted[m] = true
+	}
+	return &BalanceWatcher{
+		MinBalance:  minBalance,
+		SpendWindow: spendWindow,
+		addr:        addr,
+		balances:    balances,
+		journal:     journal,
+		halted:      halted,
+		quit:        make(chan struct{}),
+	}
+}
+
+// Start checks the balance every balanceWatchInterval until Stop is called
+func (bw *BalanceWatcher) Start() {
+	go func() {
+		ticker := time.NewTicker(balanceWatchInterval)
+		defer ticker.Stop()
+		for {
+			bw.check(time.Now())
+			select {
+			case <-ticker.C:
+			case <-bw.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops checking the balance
+func (bw *BalanceWatcher) Stop() {
+	close(bw.quit)
+}
+
+// spend returns the wei spent on the gas of the txs mined since since. Reverted txs use gas too
+func (bw *BalanceWatcher) spend(since time.Time) (*big.Int, error) {
+	spent := big.NewInt(0)
+	if bw.journal == nil {
+		return spent, nil
+	}
+	entries, err := bw.journal.TxJournal(&common.DBTxJournalFilter{Since: since})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if (e.Status != txStatusMined && e.Status != txStatusReverted) || e.GasPrice == nil {
+			continue
+		}
+		spent.Add(spent, new(big.Int).Mul(e.GasPrice, new(big.Int).SetUint64(e.GasUsed)))
+	}
+	return spent, nil
+}
+
+// Status returns the ETH balance of the account and its runway
+func (bw *BalanceWatcher) Status(ctx context.Context, now time.Time) (*BalanceStatus, error) {
+	balance, err := bw.balances.BalanceAt(ctx, bw.addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	spent, err := bw.spend(now.Add(-bw.SpendWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &BalanceStatus{
+		Balance:       balance,
+		MinBalance:    bw.MinBalance,
+		SpendRate:     new(big.Int).Div(new(big.Int).Mul(spent, big.NewInt(int64(time.Hour))), big.NewInt(int64(bw.SpendWindow))),
+		RunwaySeconds: -1,
+		Low:           bw.MinBalance != nil && balance.Cmp(bw.MinBalance) < 0,
+		Halted:        []string{},
+	}
+	if spent.Sign() > 0 {
+		rate := new(big.Rat).SetFrac(spent, big.NewInt(int64(bw.SpendWindow/time.Second)))
+		status.RunwaySeconds, _ = new(big.Rat).Quo(new(big.Rat).SetInt(balance), rate).Float64()
+	}
+	for m := range bw.halted {
+		status.Halted = append(status.Halted, m)
+	}
+	sort.Strings(status.Halted)
+	return status, nil
+}
+
+// check logs an alert when the balance drops below the min balance and records the balance and its runway
+func (bw *BalanceWatcher) check(now time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), balanceWatchInterval)
+	defer cancel()
+	status, err := bw.Status(ctx, now)
+	if err != nil {
+		glog.Errorf("Unable to get ETH balance err=%q", err)
+		return
+	}
+
+	bw.mu.Lock()
+	wasLow := bw.low
+	bw.low = status.Low
+	bw.mu.Unlock()
+
+	if status.Low && !wasLow {
+		glog.Errorf("ETH balance is below the min balance, top up the account addr=%v balance=%v minBalance=%v runway=%.0fs halted=%v",
+			bw.addr.Hex(), FormatUnits(status.Balance, "ETH"), FormatUnits(bw.MinBalance, "ETH"), status.RunwaySeconds, status.Halted)
+	} else if !status.Low && wasLow {
+		glog.Infof("ETH balance recovered addr=%v balance=%v", bw.addr.Hex(), FormatUnits(status.Balance, "ETH"))
+	}
+
+	if monitor.Enabled {
+		monitor.EthBalance(status.Balance)
+		if status.RunwaySeconds >= 0 {
+			monitor.EthBalanceRunway(status.RunwaySeconds)
+		}
+	}
+}
+
+// Halted returns true if the txs of method are refused because the balance was below the min balance when it was
+// last checked
+func (bw *BalanceWatcher) Halted(method string) bool {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	return bw.low && bw.halted[method]
+}
+
+type balanceCheckedAccountManager struct {
+	AccountManager
+	bw *BalanceWatcher
+}
+
+// NewBalanceCheckedAccountManager wraps am so that the txs of the methods halted by bw are refused while the ETH
+// balance is below the min balance. Replacements of txs that were already sent are still signed
+func NewBalanceCheckedAccountManager(am AccountManager, bw *BalanceWatcher) AccountManager {
+	return &balanceCheckedAccountManager{AccountManager: am, bw: bw}
+}
+
+func (am *balanceCheckedAccountManager) CreateTransactOpts(gasLimit uint64) (*bind.TransactOpts, error) {
+	opts, err := am.AccountManager.CreateTransactOpts(gasLimit)
+	if err != nil {
+		return nil, err
+	}
+	signer := opts.Signer
+	opts.Signer = func(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if txLog, err := newTxLog(tx); err == nil && am.bw.Halted(txLog.method) {
+			return nil, fmt.Errorf("%w method=%v", ErrBalanceLow, txLog.method)
+		}
+		return signer(addr, tx)
+	}
+	return opts, nil
+}
@@ -0,0 +1,134 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/eth/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubBalanceReader struct {
+	balance *big.Int
+	err     error
+}
+
+func (r *stubBalanceReader) BalanceAt(ctx context.Context, account ethcommon.Address, blockNumber *big.Int) (*big.Int, error) {
+	return r.balance, r.err
+}
+
+func (j *stubTxJournal) TxJournal(filter *common.DBTxJournalFilter) ([]*common.DBTxJournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var entries []*common.DBTxJournalEntry
+	for _, e := range j.entries {
+		if !e.CreatedAt.Before(filter.Since) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func TestBalanceWatcher_Status(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	now := time.Now()
+	balances := &stubBalanceReader{balance: big.NewInt(300000000000000)}
+	journal := &stubTxJournal{}
+	bw := NewBalanceWatcher(ethcommon.Address{}, balances, journal, big.NewInt(100000000000000), time.Hour, []string{"reward", "redeemWinningTicket"})
+
+	// Nothing was spent
+	status, err := bw.Status(context.Background(), now)
+	require.Nil(err)
+	assert.Equal(big.NewInt(300000000000000), status.Balance)
+	assert.Equal(big.NewInt(0), status.SpendRate)
+	assert.Equal(float64(-1), status.RunwaySeconds)
+	assert.False(status.Low)
+	assert.Equal([]string{"redeemWinningTicket", "reward"}, status.Halted)
+
+	gasPrice := big.NewInt(1000000000)
+	journal.entries = []*common.DBTxJournalEntry{
+		{Status: txStatusMined, GasPrice: gasPrice, GasUsed: 100000, CreatedAt: now.Add(-time.Minute)},
+		// Reverted txs use gas too
+		{Status: txStatusReverted, GasPrice: gasPrice, GasUsed: 50000, CreatedAt: now.Add(-time.Minute)},
+		// Pending and failed txs did not use gas
+		{Status: txStatusSent, GasPrice: gasPrice, CreatedAt: now.Add(-time.Minute)},
+		{Status: txStatusFailed, GasPrice: gasPrice, GasUsed: 100000, CreatedAt: now.Add(-time.Minute)},
+		// Txs mined before the spend window are ignored
+		{Status: txStatusMined, GasPrice: gasPrice, GasUsed: 100000, CreatedAt: now.Add(-2 * time.Hour)},
+	}
+	status, err = bw.Status(context.Background(), now)
+	require.Nil(err)
+	assert.Equal(big.NewInt(150000000000000), status.SpendRate)
+	assert.Equal(float64(7200), status.RunwaySeconds)
+
+	balances.balance = big.NewInt(50000000000000)
+	status, err = bw.Status(context.Background(), now)
+	require.Nil(err)
+	assert.True(status.Low)
+	assert.Equal(float64(1200), status.RunwaySeconds)
+
+	// The balance is never low without a min balance
+	bw.MinBalance = nil
+	status, err = bw.Status(context.Background(), now)
+	require.Nil(err)
+	assert.False(status.Low)
+
+	balances.err = errors.New("balance error")
+	_, err = bw.Status(context.Background(), now)
+	assert.EqualError(err, "balance error")
+}
+
+func TestBalanceCheckedAccountManager(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	addr := ethcommon.HexToAddress("0x1234")
+	readOnly, err := NewReadOnlyAccountManager(addr)
+	require.Nil(err)
+	balances := &stubBalanceReader{balance: big.NewInt(1000)}
+	bw := NewBalanceWatcher(addr, balances, nil, big.NewInt(500), time.Hour, []string{"reward"})
+	am := NewBalanceCheckedAccountManager(readOnly, bw)
+
+	bondingManagerABI, err := abi.JSON(strings.NewReader(contracts.BondingManagerABI))
+	require.Nil(err)
+	rewardData, err := bondingManagerABI.Pack("reward")
+	require.Nil(err)
+	unbondData, err := bondingManagerABI.Pack("unbond", big.NewInt(500))
+	require.Nil(err)
+	to := ethcommon.HexToAddress("0x5678")
+	rewardTx := types.NewTx(&types.DynamicFeeTx{To: &to, Gas: 21000, GasFeeCap: big.NewInt(1000000000), Data: rewardData})
+	unbondTx := types.NewTx(&types.DynamicFeeTx{To: &to, Gas: 21000, GasFeeCap: big.NewInt(1000000000), Data: unbondData})
+
+	// Txs are left to the wrapped account manager while the balance is above the min balance
+	bw.check(time.Now())
+	assert.False(bw.Halted("reward"))
+	opts, err := am.CreateTransactOpts(100)
+	require.Nil(err)
+	_, err = opts.Signer(addr, rewardTx)
+	assert.Equal(ErrReadOnly, err)
+
+	// Txs of the halted methods are refused while the balance is below the min balance
+	balances.balance = big.NewInt(100)
+	bw.check(time.Now())
+	assert.True(bw.Halted("reward"))
+	assert.False(bw.Halted("unbond"))
+	_, err = opts.Signer(addr, rewardTx)
+	assert.True(errors.Is(err, ErrBalanceLow))
+	_, err = opts.Signer(addr, unbondTx)
+	assert.Equal(ErrReadOnly, err)
+
+	balances.balance = big.NewInt(500)
+	bw.check(time.Now())
+	assert.False(bw.Halted("reward"))
+}
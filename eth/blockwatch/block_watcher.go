@@ -49,6 +49,9 @@ type Config struct {
 	WithLogs            bool
 	Topics              []common.Hash
 	Client              Client
+	// Heartbeat is called after every poll for new blocks, i.e. to detect a Watcher that is stuck sending events to
+	// slow subscribers. Optional
+	Heartbeat func()
 }
 
 // Watcher maintains a consistent representation of the latest `blockRetentionLimit` blocks,
@@ -66,6 +69,7 @@ type Watcher struct {
 	ticker              *time.Ticker
 	withLogs            bool
 	topics              []common.Hash
+	heartbeat           func()
 	sync.RWMutex
 }
 
@@ -81,6 +85,7 @@ func New(config Config) *Watcher {
 		client:              config.Client,
 		withLogs:            config.WithLogs,
 		topics:              config.Topics,
+		heartbeat:           config.Heartbeat,
 	}
 	return bs
 }
@@ -120,6 +125,9 @@ func (w *Watcher) Watch(ctx context.Context) error {
 			if err := w.syncToLatestBlock(); err != nil {
 				glog.Errorf("blockwatch.Watcher error encountered - trying again on next polling interval err=%q", err)
 			}
+			if w.heartbeat != nil {
+				w.heartbeat()
+			}
 		}
 	}
 }
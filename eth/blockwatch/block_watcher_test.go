@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -99,6 +100,26 @@ func TestWatcherStartStop(t *testing.T) {
 	}
 }
 
+func TestWatcherHeartbeat(t *testing.T) {
+	fakeClient, err := newFakeClient(basicFakeClientFixture)
+	require.NoError(t, err)
+
+	cfg := config
+	cfg.Store = &stubMiniHeaderStore{}
+	cfg.Client = fakeClient
+	cfg.PollingInterval = 10 * time.Millisecond
+	var beats int32
+	cfg.Heartbeat = func() { atomic.AddInt32(&beats, 1) }
+	watcher := New(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.NoError(t, watcher.Watch(ctx))
+
+	// The heartbeat is called after every poll
+	assert.Greater(t, atomic.LoadInt32(&beats), int32(0))
+}
+
 type blockRangeChunksTestCase struct {
 	from                int
 	to                  int
@@ -45,6 +45,11 @@ func NewRPCClient(rpcURL string, requestTimeout time.Duration) (*RPCClient, erro
 	return &RPCClient{rpcClient: rpcClient, client: ethClient, requestTimeout: requestTimeout}, nil
 }
 
+// NewRPCClientWithRPC returns a new Client for fetching Ethereum blocks using an already connected rpc.Client.
+func NewRPCClientWithRPC(rpcClient *rpc.Client, requestTimeout time.Duration) *RPCClient {
+	return &RPCClient{rpcClient: rpcClient, client: ethclient.NewClient(rpcClient), requestTimeout: requestTimeout}
+}
+
 type getHeaderResponse struct {
 	Hash          common.Hash `json:"hash"`
 	ParentHash    common.Hash `json:"parentHash"`
@@ -0,0 +1,38 @@
+package eth
+
+import (
+	"math/big"
+)
+
+// Chain describes how the protocol deployment on a chain behaves
+type Chain struct {
+	ID   *big.Int
+	Name string
+	// L1 is set for the Ethereum chains that the protocol was deployed to before its migration to Arbitrum. These
+	// chains run the L1 contracts, i.e. fees are withdrawn in full and the total supply is the L1 supply. All other
+	// chains, including devnets, run the L2 contracts
+	L1 bool
+	// Arbitrum is set for Arbitrum chains. Rounds and ticket expirations are measured in L1 blocks and the sequencer
+	// orders transactions first come, first served so priority fees are not paid
+	Arbitrum bool
+	// Testnet is set for test networks and devnets, on which ETH and LPT have no value
+	Testnet bool
+}
+
+var knownChains = []*Chain{
+	{ID: big.NewInt(1), Name: "mainnet", L1: true},
+	{ID: big.NewInt(4), Name: "rinkeby", L1: true, Testnet: true},
+	{ID: big.NewInt(42161), Name: "arbitrum-one-mainnet", Arbitrum: true},
+	{ID: big.NewInt(421611), Name: "arbitrum-one-rinkeby", Arbitrum: true, Testnet: true},
+}
+
+// ChainByID returns the chain with chainID. Unknown chains are treated as devnets running the L2 contracts
+func ChainByID(chainID *big.Int) *Chain {
+	for _, c := range knownChains {
+		if chainID != nil && c.ID.Cmp(chainID) == 0 {
+			chain := *c
+			return &chain
+		}
+	}
+	return &Chain{ID: chainID, Name: "devnet", Testnet: true}
+}
@@ -0,0 +1,54 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainByID(t *testing.T) {
+	assert := assert.New(t)
+
+	chain := ChainByID(big.NewInt(1))
+	assert.Equal("mainnet", chain.Name)
+	assert.True(chain.L1)
+	assert.False(chain.Arbitrum)
+	assert.False(chain.Testnet)
+
+	chain = ChainByID(big.NewInt(42161))
+	assert.Equal("arbitrum-one-mainnet", chain.Name)
+	assert.False(chain.L1)
+	assert.True(chain.Arbitrum)
+	assert.False(chain.Testnet)
+
+	assert.True(ChainByID(big.NewInt(421611)).Testnet)
+
+	// Unknown chains are devnets with the L2 contracts
+	chain = ChainByID(big.NewInt(54321))
+	assert.Equal("devnet", chain.Name)
+	assert.Equal(big.NewInt(54321), chain.ID)
+	assert.False(chain.L1)
+	assert.False(chain.Arbitrum)
+	assert.True(chain.Testnet)
+
+	assert.Equal("devnet", ChainByID(nil).Name)
+
+	// The known chains cannot be modified through the returned chain
+	ChainByID(big.NewInt(4)).L1 = false
+	assert.True(ChainByID(big.NewInt(4)).L1)
+}
+
+func TestNewClient_Chain(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewClient(LivepeerEthClientConfig{ChainID: big.NewInt(421611)})
+	assert.Nil(err)
+	assert.Equal("arbitrum-one-rinkeby", c.Chain().Name)
+	assert.True(c.(*client).gm.noPriorityFee)
+
+	c, err = NewClient(LivepeerEthClientConfig{ChainID: big.NewInt(1)})
+	assert.Nil(err)
+	assert.True(c.Chain().L1)
+	assert.False(c.(*client).gm.noPriorityFee)
+}
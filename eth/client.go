@@ -18,11 +18,13 @@ import (
 	"math/big"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -31,131 +33,208 @@ import (
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/eth/contracts"
 	lpTypes "github.com/livepeer/go-livepeer/eth/types"
+	"github.com/livepeer/go-livepeer/lperrors"
 	"github.com/livepeer/go-livepeer/pm"
 	"github.com/pkg/errors"
 )
 
 var (
-	ErrReplacingMinedTx   = fmt.Errorf("trying to replace already mined tx")
-	ErrCurrentRoundLocked = fmt.Errorf("current round locked")
-	ErrMissingBackend     = fmt.Errorf("missing Ethereum client backend")
+	ErrReplacingMinedTx   = lperrors.Fatal(fmt.Errorf("trying to replace already mined tx"))
+	ErrCurrentRoundLocked = lperrors.Retryable(fmt.Errorf("current round locked"))
+	ErrMissingBackend     = lperrors.Fatal(fmt.Errorf("missing Ethereum client backend"))
+	// ErrReceiptReverted is reported for a tx that was mined but is no longer part of the chain after a reorg
+	ErrReceiptReverted = lperrors.Retryable(fmt.Errorf("transaction receipt reverted by chain reorg"))
 )
 
+// transcoderPoolPageSize is the number of transcoders that TranscoderPool reads per page of the transcoder pool
+const transcoderPoolPageSize = 25
+
+// LivepeerEthClient is the client for the Livepeer protocol contracts. The methods that send a transaction stop
+// estimating gas, looking up the nonce and submitting the transaction when their ctx is done
 type LivepeerEthClient interface {
 	Account() accounts.Account
+	// Accounts returns the accounts that transactions can be sent from
+	Accounts() []accounts.Account
+	// WithAccount returns a client that sends transactions from the account addr instead of the active account
+	WithAccount(addr ethcommon.Address) (LivepeerEthClient, error)
+	// SetAccount makes the account addr the active account
+	SetAccount(addr ethcommon.Address) error
 	Backend() Backend
+	// Chain returns the chain that the client is connected to
+	Chain() *Chain
 
 	// Rounds
-	InitializeRound() (*types.Transaction, error)
-	CurrentRound() (*big.Int, error)
-	LastInitializedRound() (*big.Int, error)
-	BlockHashForRound(round *big.Int) ([32]byte, error)
-	CurrentRoundInitialized() (bool, error)
-	CurrentRoundLocked() (bool, error)
-	CurrentRoundStartBlock() (*big.Int, error)
+	InitializeRound(ctx context.Context) (*types.Transaction, error)
+	CurrentRound(ctx context.Context) (*big.Int, error)
+	LastInitializedRound(ctx context.Context) (*big.Int, error)
+	BlockHashForRound(ctx context.Context, round *big.Int) ([32]byte, error)
+	CurrentRoundInitialized(ctx context.Context) (bool, error)
+	CurrentRoundLocked(ctx context.Context) (bool, error)
+	CurrentRoundStartBlock(ctx context.Context) (*big.Int, error)
 
 	// Token
-	Transfer(toAddr ethcommon.Address, amount *big.Int) (*types.Transaction, error)
-	Request() (*types.Transaction, error)
-	NextValidRequest(addr ethcommon.Address) (*big.Int, error)
-	BalanceOf(ethcommon.Address) (*big.Int, error)
-	TotalSupply() (*big.Int, error)
+	Transfer(ctx context.Context, toAddr ethcommon.Address, amount *big.Int) (*types.Transaction, error)
+	// Allowance returns the amount of the tokens of owner that spender is allowed to transfer
+	Allowance(ctx context.Context, owner ethcommon.Address, spender ethcommon.Address) (*big.Int, error)
+	// Approve allows spender to transfer amount of the tokens of the account, replacing the previous allowance
+	Approve(ctx context.Context, spender ethcommon.Address, amount *big.Int) (*types.Transaction, error)
+	Request(ctx context.Context) (*types.Transaction, error)
+	NextValidRequest(ctx context.Context, addr ethcommon.Address) (*big.Int, error)
+	BalanceOf(ctx context.Context, addr ethcommon.Address) (*big.Int, error)
+	TotalSupply(ctx context.Context) (*big.Int, error)
 
 	// Service Registry
-	SetServiceURI(serviceURI string) (*types.Transaction, error)
-	GetServiceURI(addr ethcommon.Address) (string, error)
+	SetServiceURI(ctx context.Context, serviceURI string) (*types.Transaction, error)
+	GetServiceURI(ctx context.Context, addr ethcommon.Address) (string, error)
 
 	// Staking
-	Transcoder(blockRewardCut, feeShare *big.Int) (*types.Transaction, error)
-	Reward() (*types.Transaction, error)
-	Bond(amount *big.Int, toAddr ethcommon.Address) (*types.Transaction, error)
-	Rebond(unbondingLockID *big.Int) (*types.Transaction, error)
-	RebondFromUnbonded(toAddr ethcommon.Address, unbondingLockID *big.Int) (*types.Transaction, error)
-	Unbond(amount *big.Int) (*types.Transaction, error)
-	WithdrawStake(unbondingLockID *big.Int) (*types.Transaction, error)
-	WithdrawFees(addr ethcommon.Address, amount *big.Int) (*types.Transaction, error)
+	Transcoder(ctx context.Context, blockRewardCut, feeShare *big.Int) (*types.Transaction, error)
+	Reward(ctx context.Context) (*types.Transaction, error)
+	Bond(ctx context.Context, amount *big.Int, toAddr ethcommon.Address) (*types.Transaction, error)
+	Rebond(ctx context.Context, unbondingLockID *big.Int) (*types.Transaction, error)
+	RebondFromUnbonded(ctx context.Context, toAddr ethcommon.Address, unbondingLockID *big.Int) (*types.Transaction, error)
+	Unbond(ctx context.Context, amount *big.Int) (*types.Transaction, error)
+	WithdrawStake(ctx context.Context, unbondingLockID *big.Int) (*types.Transaction, error)
+	WithdrawFees(ctx context.Context, addr ethcommon.Address, amount *big.Int) (*types.Transaction, error)
 	// for L1 contracts backwards-compatibility
-	L1WithdrawFees() (*types.Transaction, error)
-	ClaimEarnings(endRound *big.Int) (*types.Transaction, error)
-	GetTranscoder(addr ethcommon.Address) (*lpTypes.Transcoder, error)
-	GetDelegator(addr ethcommon.Address) (*lpTypes.Delegator, error)
-	GetDelegatorUnbondingLock(addr ethcommon.Address, unbondingLockId *big.Int) (*lpTypes.UnbondingLock, error)
-	GetTranscoderEarningsPoolForRound(addr ethcommon.Address, round *big.Int) (*lpTypes.TokenPools, error)
-	TranscoderPool() ([]*lpTypes.Transcoder, error)
-	IsActiveTranscoder() (bool, error)
-	GetTotalBonded() (*big.Int, error)
-	GetTranscoderPoolSize() (*big.Int, error)
+	L1WithdrawFees(ctx context.Context) (*types.Transaction, error)
+	ClaimEarnings(ctx context.Context, endRound *big.Int) (*types.Transaction, error)
+	GetTranscoder(ctx context.Context, addr ethcommon.Address) (*lpTypes.Transcoder, error)
+	GetDelegator(ctx context.Context, addr ethcommon.Address) (*lpTypes.Delegator, error)
+	GetDelegatorUnbondingLock(ctx context.Context, addr ethcommon.Address, unbondingLockId *big.Int) (*lpTypes.UnbondingLock, error)
+	GetDelegatorUnbondingLocks(ctx context.Context, addr ethcommon.Address) ([]*lpTypes.UnbondingLock, error)
+	// DelegatorInfo returns the position of a delegator with its unclaimed earnings up to the current round and its
+	// pending unbonding locks
+	DelegatorInfo(ctx context.Context, addr ethcommon.Address) (*lpTypes.DelegatorInfo, error)
+	GetTranscoderEarningsPoolForRound(ctx context.Context, addr ethcommon.Address, round *big.Int) (*lpTypes.TokenPools, error)
+	// RegisteredTranscoders returns a page of at most limit transcoders of the transcoder pool in the order of the pool,
+	// starting with the transcoder start or with the first transcoder of the pool if start is the null address. It also
+	// returns the address to start the next page with, which is the null address after the last page
+	RegisteredTranscoders(ctx context.Context, start ethcommon.Address, limit int) ([]*lpTypes.Transcoder, ethcommon.Address, error)
+	// TranscoderPool returns all transcoders of the transcoder pool
+	TranscoderPool(ctx context.Context) ([]*lpTypes.Transcoder, error)
+	IsActiveTranscoder(ctx context.Context) (bool, error)
+	GetTotalBonded(ctx context.Context) (*big.Int, error)
+	GetTranscoderPoolSize(ctx context.Context) (*big.Int, error)
 
 	// TicketBroker
-	FundDepositAndReserve(depositAmount, penaltyEscrowAmount *big.Int) (*types.Transaction, error)
-	FundDeposit(amount *big.Int) (*types.Transaction, error)
-	FundReserve(amount *big.Int) (*types.Transaction, error)
-	Unlock() (*types.Transaction, error)
-	CancelUnlock() (*types.Transaction, error)
-	Withdraw() (*types.Transaction, error)
-	RedeemWinningTicket(ticket *pm.Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error)
-	IsUsedTicket(ticket *pm.Ticket) (bool, error)
-	GetSenderInfo(addr ethcommon.Address) (*pm.SenderInfo, error)
-	UnlockPeriod() (*big.Int, error)
-	ClaimedReserve(reserveHolder ethcommon.Address, claimant ethcommon.Address) (*big.Int, error)
+	FundDepositAndReserve(ctx context.Context, depositAmount, penaltyEscrowAmount *big.Int) (*types.Transaction, error)
+	FundDeposit(ctx context.Context, amount *big.Int) (*types.Transaction, error)
+	FundReserve(ctx context.Context, amount *big.Int) (*types.Transaction, error)
+	Unlock(ctx context.Context) (*types.Transaction, error)
+	CancelUnlock(ctx context.Context) (*types.Transaction, error)
+	Withdraw(ctx context.Context) (*types.Transaction, error)
+	RedeemWinningTicket(ctx context.Context, ticket *pm.Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error)
+	BatchRedeemWinningTickets(ctx context.Context, tickets []*pm.SignedTicket) (*types.Transaction, error)
+	IsUsedTicket(ctx context.Context, ticket *pm.Ticket) (bool, error)
+	GetSenderInfo(ctx context.Context, addr ethcommon.Address) (*pm.SenderInfo, error)
+	UnlockPeriod(ctx context.Context) (*big.Int, error)
+	ClaimedReserve(ctx context.Context, reserveHolder ethcommon.Address, claimant ethcommon.Address) (*big.Int, error)
 
 	// Parameters
-	GetTranscoderPoolMaxSize() (*big.Int, error)
-	RoundLength() (*big.Int, error)
-	RoundLockAmount() (*big.Int, error)
-	UnbondingPeriod() (uint64, error)
-	Inflation() (*big.Int, error)
-	InflationChange() (*big.Int, error)
-	TargetBondingRate() (*big.Int, error)
-	GetGlobalTotalSupply() (*big.Int, error)
-	Paused() (bool, error)
+	GetTranscoderPoolMaxSize(ctx context.Context) (*big.Int, error)
+	RoundLength(ctx context.Context) (*big.Int, error)
+	RoundLockAmount(ctx context.Context) (*big.Int, error)
+	UnbondingPeriod(ctx context.Context) (uint64, error)
+	Inflation(ctx context.Context) (*big.Int, error)
+	InflationChange(ctx context.Context) (*big.Int, error)
+	TargetBondingRate(ctx context.Context) (*big.Int, error)
+	GetGlobalTotalSupply(ctx context.Context) (*big.Int, error)
+	Paused(ctx context.Context) (bool, error)
+	// ForceRefresh drops the cached protocol parameters so that they are read from the contracts again. The parameters
+	// of the RoundsManager, BondingManager, Minter and TicketBroker are cached until it is called
+	ForceRefresh()
 
 	// Governance
-	Vote(ethcommon.Address, *big.Int) (*types.Transaction, error)
+	Vote(ctx context.Context, pollAddr ethcommon.Address, choiceID *big.Int) (*types.Transaction, error)
+	Polls(ctx context.Context, fromBlock *big.Int) ([]*lpTypes.Poll, error)
 
 	// Helpers
 	ContractAddresses() map[string]ethcommon.Address
-	CheckTx(*types.Transaction) error
+	// ReloadContracts resolves the addresses of the contracts registered in the Controller again and rebuilds the
+	// bindings of the contracts, i.e. after a protocol upgrade replaced a contract
+	ReloadContracts() error
+	// CheckTx waits for tx to be mined and returns an error if it failed. It stops waiting when ctx is done
+	CheckTx(ctx context.Context, tx *types.Transaction) error
 	Sign([]byte) ([]byte, error)
 	SignTypedData(apitypes.TypedData) ([]byte, error)
 	SetGasInfo(uint64) error
 	SetMaxGasPrice(*big.Int) error
+	// WithGasFees returns a client that sends transactions with the fees in fees instead of the suggested fees
+	WithGasFees(fees GasFees) LivepeerEthClient
+	// EstimateTxCost returns the estimated cost of the transactions that send submits with client without
+	// submitting them
+	EstimateTxCost(ctx context.Context, send func(client LivepeerEthClient) (*types.Transaction, error)) (*TxCost, error)
+	// ResyncNonce resets the nonce of the next transaction to the pending nonce of the account
+	ResyncNonce() error
+	// ReplaceTransaction rebroadcasts the pending transaction with txHash with its gas price multiplied by
+	// gasPriceMultiplier
+	ReplaceTransaction(ctx context.Context, txHash ethcommon.Hash, gasPriceMultiplier float64) (*types.Transaction, error)
+	// PendingApprovals returns the transactions that are held because they exceed the spend limits
+	PendingApprovals() []*PendingTx
+	// ApproveTransaction sends the transaction pending approval with id regardless of the spend limits
+	ApproveTransaction(ctx context.Context, id uint64) (*types.Transaction, error)
+	// RejectTransaction drops the transaction pending approval with id without sending it
+	RejectTransaction(id uint64) error
 }
 
 type client struct {
 	accountManager AccountManager
 	backend        Backend
 	tm             *TransactionManager
-	transOpts      bind.TransactOpts
-	transOptsMu    sync.RWMutex
-
-	controllerAddr      ethcommon.Address
-	tokenAddr           ethcommon.Address
-	serviceRegistryAddr ethcommon.Address
-	bondingManagerAddr  ethcommon.Address
-	ticketBrokerAddr    ethcommon.Address
-	roundsManagerAddr   ethcommon.Address
-	minterAddr          ethcommon.Address
-	verifierAddr        ethcommon.Address
-	faucetAddr          ethcommon.Address
-
-	// Contract sessions
-	controllerSess          *contracts.ControllerSession
-	livepeerTokenSess       *contracts.LivepeerTokenSession
-	serviceRegistrySess     *contracts.ServiceRegistrySession
-	bondingManagerSess      *contracts.BondingManagerSession
-	ticketBrokerSess        *contracts.TicketBrokerSession
-	roundsManagerSess       *contracts.RoundsManagerSession
-	minterSess              *contracts.MinterSession
-	livepeerTokenFaucetSess *contracts.LivepeerTokenFaucetSession
-
-	// for L1 contracts backwards-compatibility
-	l1BondingManagerSess *contracts.L1BondingManagerSession
+	gm             *GasManager
+	// transOpts is shared with the clients returned by WithGasFees
+	transOpts   *bind.TransactOpts
+	transOptsMu *sync.RWMutex
+	// gasFees override the fees set by the gas manager
+	gasFees GasFees
+	// txMu serializes the transactions of the accounts so that concurrent transactions are assigned consecutive
+	// nonces. It is shared with the clients returned by WithGasFees and WithAccount
+	txMu *sync.Mutex
+
+	controllerAddr ethcommon.Address
+
+	// Contract addresses that take precedence over the addresses registered in the Controller
+	contractOverrides map[string]ethcommon.Address
+	// contractCache stores the addresses resolved from the Controller, nil if they are not cached
+	contractCache ContractAddressCache
+
+	controllerSess *contracts.ControllerSession
+	// bound holds the *contractSet with the bindings of the contracts registered in the Controller. It is shared with
+	// the clients returned by WithGasFees and WithAccount so that they use the reloaded contracts too
+	bound *atomic.Value
+
+	chain *Chain
 
 	gasLimit uint64
 	gasPrice *big.Int
 
 	txTimeout time.Duration
+
+	// multiAccountManager manages the accounts of the client if there are several accounts, nil otherwise. It is
+	// shared with the clients returned by WithAccount
+	multiAccountManager MultiAccountManager
+
+	// estimates collects the transactions that are built but not sent by the clients used by EstimateTxCost, nil
+	// if transactions are sent
+	estimates *[]*types.Transaction
+
+	// infiniteApproval is set if bonding approves the transfer of any amount of tokens instead of the bonded amount
+	infiniteApproval bool
+
+	// params caches the protocol parameters, they are read from the contracts every time if nil
+	params *paramCache
+
+	// spend holds the transactions that exceed the spend limits, nil if the spend is not limited
+	spend *spendGuard
+	// spendApproved is set for the clients that send approved transactions regardless of the spend limits
+	spendApproved bool
+}
+
+// ContractAddressCache stores the contract addresses resolved from a Controller
+type ContractAddressCache interface {
+	ContractAddresses(controllerAddr ethcommon.Address) (map[string]ethcommon.Address, error)
+	SetContractAddresses(controllerAddr ethcommon.Address, addrs map[string]ethcommon.Address) error
 }
 
 type LivepeerEthClientConfig struct {
@@ -165,17 +244,57 @@ type LivepeerEthClientConfig struct {
 	TransactionManager *TransactionManager
 	Signer             types.Signer
 	ControllerAddr     ethcommon.Address
+	// ChainID is the ID of the chain that EthClient is connected to
+	ChainID *big.Int
+	// ContractOverrides maps contract names (i.e. "BondingManager") to addresses that should be used
+	// instead of the addresses registered in the Controller
+	ContractOverrides map[string]ethcommon.Address
+	// ContractCache stores the addresses resolved from the Controller so that they are not looked up again on the
+	// next start. The addresses are not cached if nil
+	ContractCache ContractAddressCache
+	// InfiniteApproval makes bonding approve the transfer of any amount of tokens by the BondingManager, so that
+	// later bonds do not need another approval
+	InfiniteApproval bool
+	// SpendLimits caps the cost of the transactions, transactions that exceed it are held until they are approved.
+	// The spend is not limited if nil
+	SpendLimits *SpendLimits
+	// RPCTimeout bounds each read call of a contract on top of the context passed by the caller. Not bounded if 0
+	RPCTimeout time.Duration
 }
 
 func NewClient(cfg LivepeerEthClientConfig) (LivepeerEthClient, error) {
 
-	backend := NewBackend(cfg.EthClient, cfg.Signer, cfg.GasPriceMonitor, cfg.TransactionManager)
+	backend := NewBackend(cfg.EthClient, cfg.Signer, cfg.GasPriceMonitor, cfg.TransactionManager, cfg.RPCTimeout)
+
+	mam, _ := cfg.AccountManager.(MultiAccountManager)
+
+	chain := ChainByID(cfg.ChainID)
+	gm := NewGasManager(backend)
+	gm.noPriorityFee = chain.Arbitrum
+
+	var spend *spendGuard
+	if cfg.SpendLimits != nil {
+		spend = newSpendGuard(*cfg.SpendLimits)
+	}
 
 	return &client{
-		accountManager: cfg.AccountManager,
-		backend:        backend,
-		tm:             cfg.TransactionManager,
-		controllerAddr: cfg.ControllerAddr,
+		accountManager:    cfg.AccountManager,
+		backend:           backend,
+		tm:                cfg.TransactionManager,
+		gm:                gm,
+		chain:             chain,
+		transOpts:         &bind.TransactOpts{},
+		transOptsMu:       &sync.RWMutex{},
+		txMu:              &sync.Mutex{},
+		controllerAddr:    cfg.ControllerAddr,
+		contractOverrides: cfg.ContractOverrides,
+		contractCache:     cfg.ContractCache,
+		bound:             &atomic.Value{},
+
+		multiAccountManager: mam,
+		infiniteApproval:    cfg.InfiniteApproval,
+		params:              newParamCache(),
+		spend:               spend,
 	}, nil
 }
 
@@ -194,61 +313,103 @@ func (c *client) setContracts(opts *bind.TransactOpts) error {
 
 	glog.V(common.SHORT).Infof("Controller: %v", c.controllerAddr.Hex())
 
-	tokenAddr, err := c.GetContract(crypto.Keccak256Hash([]byte("LivepeerToken")))
+	var cached map[string]ethcommon.Address
+	if c.contractCache != nil {
+		cached, err = c.contractCache.ContractAddresses(c.controllerAddr)
+		if err != nil {
+			glog.Errorf("Error reading cached contract addresses: %v", err)
+			return err
+		}
+	}
+
+	cs, err := c.loadContracts(cached)
 	if err != nil {
-		glog.Errorf("Error getting LivepeerToken address: %v", err)
 		return err
 	}
+	c.bound.Store(cs)
+
+	return c.cacheContracts(cs)
+}
+
+// cacheContracts stores the addresses of the contracts in cs that were resolved from the Controller in the contract
+// cache, if any. Overridden addresses are not cached so that they are looked up again once the override is removed
+func (c *client) cacheContracts(cs *contractSet) error {
+	if c.contractCache == nil {
+		return nil
+	}
+
+	addrs := cs.addresses()
+	for name := range c.contractOverrides {
+		delete(addrs, name)
+	}
+
+	if err := c.contractCache.SetContractAddresses(c.controllerAddr, addrs); err != nil {
+		glog.Errorf("Error caching contract addresses: %v", err)
+		return err
+	}
+	return nil
+}
+
+// loadContracts resolves the addresses of the contracts registered in the Controller and creates their bindings.
+// The addresses in cached are used instead of looking them up in the Controller
+func (c *client) loadContracts(cached map[string]ethcommon.Address) (*contractSet, error) {
+	cs := &contractSet{}
 
-	c.tokenAddr = tokenAddr
+	tokenAddr, err := c.resolveContract("LivepeerToken", cached)
+	if err != nil {
+		glog.Errorf("Error getting LivepeerToken address: %v", err)
+		return nil, err
+	}
+
+	cs.tokenAddr = tokenAddr
 
 	token, err := contracts.NewLivepeerToken(tokenAddr, c.backend)
 	if err != nil {
 		glog.Errorf("Error creating LivpeerToken binding: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.livepeerTokenSess = &contracts.LivepeerTokenSession{
+	cs.livepeerTokenSess = &contracts.LivepeerTokenSession{
 		Contract: token,
 	}
 
-	glog.V(common.SHORT).Infof("LivepeerToken: %v", c.tokenAddr.Hex())
+	glog.V(common.SHORT).Infof("LivepeerToken: %v", cs.tokenAddr.Hex())
 
-	serviceRegistryAddr, err := c.GetContract(crypto.Keccak256Hash([]byte("ServiceRegistry")))
+	serviceRegistryAddr, err := c.resolveContract("ServiceRegistry", cached)
 	if err != nil {
 		glog.Errorf("Error getting ServiceRegistry address: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.serviceRegistryAddr = serviceRegistryAddr
+	cs.serviceRegistryAddr = serviceRegistryAddr
 
 	serviceRegistry, err := contracts.NewServiceRegistry(serviceRegistryAddr, c.backend)
 	if err != nil {
 		glog.Errorf("Error creating ServiceRegistry binding: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.serviceRegistrySess = &contracts.ServiceRegistrySession{
+	cs.serviceRegistrySess = &contracts.ServiceRegistrySession{
 		Contract: serviceRegistry,
 	}
 
-	glog.V(common.SHORT).Infof("ServiceRegistry: %v", c.serviceRegistryAddr.Hex())
+	glog.V(common.SHORT).Infof("ServiceRegistry: %v", cs.serviceRegistryAddr.Hex())
 
-	bondingManagerAddr, err := c.GetContract(crypto.Keccak256Hash([]byte("BondingManager")))
+	bondingManagerAddr, err := c.resolveContract("BondingManager", cached)
 	if err != nil {
 		glog.Errorf("Error getting BondingManager address: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.bondingManagerAddr = bondingManagerAddr
+	cs.bondingManagerAddr = bondingManagerAddr
 
 	bondingManager, err := contracts.NewBondingManager(bondingManagerAddr, c.backend)
 	if err != nil {
 		glog.Errorf("Error creating BondingManager binding: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.bondingManagerSess = &contracts.BondingManagerSession{
+	cs.bondingManagerSess = &contracts.BondingManagerSession{
 		Contract: bondingManager,
 	}
 
@@ -256,97 +417,112 @@ func (c *client) setContracts(opts *bind.TransactOpts) error {
 	l1BondingManager, err := contracts.NewL1BondingManager(bondingManagerAddr, c.backend)
 	if err != nil {
 		glog.Errorf("Error creating L1BondingManager binding: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.l1BondingManagerSess = &contracts.L1BondingManagerSession{
+	cs.l1BondingManagerSess = &contracts.L1BondingManagerSession{
 		Contract: l1BondingManager,
 	}
 
-	glog.V(common.SHORT).Infof("BondingManager: %v", c.bondingManagerAddr.Hex())
+	glog.V(common.SHORT).Infof("BondingManager: %v", cs.bondingManagerAddr.Hex())
 
-	brokerAddr, err := c.GetContract(crypto.Keccak256Hash([]byte("TicketBroker")))
+	brokerAddr, err := c.resolveContract("TicketBroker", cached)
 	if err != nil {
 		glog.Errorf("Error getting TicketBroker address: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.ticketBrokerAddr = brokerAddr
+	cs.ticketBrokerAddr = brokerAddr
 
 	broker, err := contracts.NewTicketBroker(brokerAddr, c.backend)
 	if err != nil {
 		glog.Errorf("Error creating TicketBroker binding: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.ticketBrokerSess = &contracts.TicketBrokerSession{
+	cs.ticketBrokerSess = &contracts.TicketBrokerSession{
 		Contract: broker,
 	}
 
-	glog.V(common.SHORT).Infof("TicketBroker: %v", c.ticketBrokerAddr.Hex())
+	glog.V(common.SHORT).Infof("TicketBroker: %v", cs.ticketBrokerAddr.Hex())
 
-	roundsManagerAddr, err := c.GetContract(crypto.Keccak256Hash([]byte("RoundsManager")))
+	roundsManagerAddr, err := c.resolveContract("RoundsManager", cached)
 	if err != nil {
 		glog.Errorf("Error getting RoundsManager address: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.roundsManagerAddr = roundsManagerAddr
+	cs.roundsManagerAddr = roundsManagerAddr
 
 	roundsManager, err := contracts.NewRoundsManager(roundsManagerAddr, c.backend)
 	if err != nil {
 		glog.Errorf("Error creating RoundsManager binding: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.roundsManagerSess = &contracts.RoundsManagerSession{
+	cs.roundsManagerSess = &contracts.RoundsManagerSession{
 		Contract: roundsManager,
 	}
 
-	glog.V(common.SHORT).Infof("RoundsManager: %v", c.roundsManagerAddr.Hex())
+	glog.V(common.SHORT).Infof("RoundsManager: %v", cs.roundsManagerAddr.Hex())
 
-	minterAddr, err := c.GetContract(crypto.Keccak256Hash([]byte("Minter")))
+	minterAddr, err := c.resolveContract("Minter", cached)
 	if err != nil {
 		glog.Errorf("Error getting Minter address: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.minterAddr = minterAddr
+	cs.minterAddr = minterAddr
 
 	minter, err := contracts.NewMinter(minterAddr, c.backend)
 	if err != nil {
 		glog.Errorf("Error creating Minter binding: %v", err)
-		return err
+		return nil, err
 	}
 
 	// Client should never transact with the Minter directly so we don't include transact opts
-	c.minterSess = &contracts.MinterSession{
+	cs.minterSess = &contracts.MinterSession{
 		Contract: minter,
 	}
 
-	glog.V(common.SHORT).Infof("Minter: %v", c.minterAddr.Hex())
+	glog.V(common.SHORT).Infof("Minter: %v", cs.minterAddr.Hex())
 
-	faucetAddr, err := c.GetContract(crypto.Keccak256Hash([]byte("LivepeerTokenFaucet")))
+	faucetAddr, err := c.resolveContract("LivepeerTokenFaucet", cached)
 	if err != nil {
 		glog.Errorf("Error getting LivepeerTokenFaucet address: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.faucetAddr = faucetAddr
+	cs.faucetAddr = faucetAddr
 
 	faucet, err := contracts.NewLivepeerTokenFaucet(faucetAddr, c.backend)
 	if err != nil {
 		glog.Errorf("Error creating LivepeerTokenFaucet binding: %v", err)
-		return err
+		return nil, err
 	}
 
-	c.livepeerTokenFaucetSess = &contracts.LivepeerTokenFaucetSession{
+	cs.livepeerTokenFaucetSess = &contracts.LivepeerTokenFaucetSession{
 		Contract: faucet,
 	}
 
-	glog.V(common.SHORT).Infof("LivepeerTokenFaucet: %v", c.faucetAddr.Hex())
+	glog.V(common.SHORT).Infof("LivepeerTokenFaucet: %v", cs.faucetAddr.Hex())
 
-	return nil
+	return cs, nil
+}
+
+// resolveContract returns the address of the contract registered under name in the Controller
+// unless an override is configured for the contract or the address is in cached
+func (c *client) resolveContract(name string, cached map[string]ethcommon.Address) (ethcommon.Address, error) {
+	if addr, ok := c.contractOverrides[name]; ok {
+		glog.Infof("Using override address for %v: %v", name, addr.Hex())
+		return addr, nil
+	}
+
+	if addr, ok := cached[name]; ok {
+		return addr, nil
+	}
+
+	return c.GetContract(crypto.Keccak256Hash([]byte(name)))
 }
 
 func (c *client) SetGasInfo(gasLimit uint64) error {
@@ -382,18 +558,148 @@ func (c *client) SetMaxGasPrice(maxGasPrice *big.Int) error {
 	return nil
 }
 
+func (c *client) WithGasFees(fees GasFees) LivepeerEthClient {
+	cp := *c
+	cp.gasFees = fees
+	return &cp
+}
+
 func (c *client) setTransactOpts(opts bind.TransactOpts) {
 	c.transOptsMu.Lock()
-	c.transOpts = opts
+	*c.transOpts = opts
 	c.transOptsMu.Unlock()
 }
 
-func (c *client) transactOpts() *bind.TransactOpts {
+func (c *client) Accounts() []accounts.Account {
+	if c.multiAccountManager == nil {
+		return []accounts.Account{c.Account()}
+	}
+	return c.multiAccountManager.Accounts()
+}
+
+func (c *client) WithAccount(addr ethcommon.Address) (LivepeerEthClient, error) {
+	if c.multiAccountManager == nil {
+		if addr != c.Account().Address {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownAccount, addr.Hex())
+		}
+		return c, nil
+	}
+
+	am, err := c.multiAccountManager.ForAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	cp := *c
+	cp.accountManager = am
+	return &cp, nil
+}
+
+func (c *client) SetAccount(addr ethcommon.Address) error {
+	if c.multiAccountManager == nil {
+		if addr != c.Account().Address {
+			return fmt.Errorf("%w: %v", ErrUnknownAccount, addr.Hex())
+		}
+		return nil
+	}
+	return c.multiAccountManager.SetActive(addr)
+}
+
+// transactOpts returns the options for the next transaction. The gas estimation, nonce lookup and submission of the
+// transaction are cancelled when ctx is done
+func (c *client) transactOpts(ctx context.Context) (*bind.TransactOpts, error) {
 	c.transOptsMu.RLock()
-	opts := c.transOpts
+	opts := *c.transOpts
 	c.transOptsMu.RUnlock()
 
-	return &opts
+	opts.Context = ctx
+
+	// The signer of a multi-account manager signs with whichever account the transaction is sent from
+	if c.multiAccountManager != nil {
+		opts.From = c.accountManager.Account().Address
+	}
+
+	if c.estimates != nil {
+		// Build the transaction to estimate its cost without signing or sending it
+		opts.NoSend = true
+		opts.Signer = func(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		}
+	} else if c.spend != nil && !c.spendApproved {
+		opts.Signer = c.guardSigner(opts.Signer)
+	}
+
+	if err := c.gm.SetFees(ctx, &opts, c.gasFees); err != nil {
+		return nil, err
+	}
+
+	return &opts, nil
+}
+
+// transact sends the transaction created by send with the options for the next transaction. If the nonce of the
+// transaction was already used, i.e. by another node using the same account, the transaction is sent once more with
+// the next nonce. Transactions that exceed the spend limits are held until they are approved
+func (c *client) transact(ctx context.Context, send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+
+	// The gas estimate, fees, nonce and submission of the tx are not throttled by the RPC budget
+	ctx = WithCriticalRPC(ctx)
+
+	tx, err := c.sendTx(ctx, send)
+	if c.spend == nil || c.estimates != nil {
+		return tx, err
+	}
+
+	var limitErr *spendLimitError
+	if errors.As(err, &limitErr) {
+		return nil, c.holdTx(limitErr, send)
+	}
+	if err == nil && tx != nil {
+		c.spend.record(tx.Cost())
+	}
+	return tx, err
+}
+
+func (c *client) sendTx(ctx context.Context, send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	opts, err := c.transactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := send(opts)
+	if c.estimates != nil {
+		if err != nil && len(*c.estimates) > 0 {
+			// i.e. bonding fails the estimation when the approval of the token transfer is not mined
+			return nil, fmt.Errorf("could not estimate transaction that depends on a previous transaction: %w", err)
+		}
+		if err == nil {
+			*c.estimates = append(*c.estimates, tx)
+		}
+		return tx, err
+	}
+	if !isNonceTooLow(err) {
+		return tx, err
+	}
+
+	glog.Warningf("Retrying transaction with the next nonce err=%q", err)
+
+	opts, err = c.transactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return send(opts)
+}
+
+func (c *client) ResyncNonce() error {
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+
+	return c.backend.ResyncNonce(c.Account().Address)
+}
+
+func (c *client) ReplaceTransaction(ctx context.Context, txHash ethcommon.Hash, gasPriceMultiplier float64) (*types.Transaction, error) {
+	return c.tm.ReplaceTransaction(ctx, txHash, gasPriceMultiplier)
 }
 
 func (c *client) Account() accounts.Account {
@@ -404,18 +710,27 @@ func (c *client) Backend() Backend {
 	return c.backend
 }
 
+func (c *client) Chain() *Chain {
+	return c.chain
+}
+
+// callOpts returns the options for a read call of a contract, which is cancelled when ctx is done
+func (c *client) callOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx}
+}
+
 // Controller
 func (c *client) GetContract(hash ethcommon.Hash) (ethcommon.Address, error) {
 	return c.controllerSess.GetContract(hash)
 }
 
-func (c *client) Paused() (bool, error) {
-	return c.controllerSess.Paused()
+func (c *client) Paused(ctx context.Context) (bool, error) {
+	return c.controllerSess.Contract.Paused(c.callOpts(ctx))
 }
 
 // Rounds
-func (c *client) InitializeRound() (*types.Transaction, error) {
-	i, err := c.roundsManagerSess.CurrentRoundInitialized()
+func (c *client) InitializeRound(ctx context.Context) (*types.Transaction, error) {
+	i, err := c.bindings().roundsManagerSess.Contract.CurrentRoundInitialized(c.callOpts(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -423,100 +738,114 @@ func (c *client) InitializeRound() (*types.Transaction, error) {
 		glog.V(common.SHORT).Infof("Round already initialized")
 		return nil, errors.New("ErrRoundInitialized")
 	} else {
-		return c.roundsManagerSess.Contract.InitializeRound(c.transactOpts())
+		return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return c.bindings().roundsManagerSess.Contract.InitializeRound(opts)
+		})
 	}
 }
 
-func (c *client) CurrentRound() (*big.Int, error) {
-	return c.roundsManagerSess.CurrentRound()
+func (c *client) CurrentRound(ctx context.Context) (*big.Int, error) {
+	return c.bindings().roundsManagerSess.Contract.CurrentRound(c.callOpts(ctx))
 }
 
-func (c *client) CurrentRoundLocked() (bool, error) {
-	return c.roundsManagerSess.CurrentRoundLocked()
+func (c *client) CurrentRoundLocked(ctx context.Context) (bool, error) {
+	return c.bindings().roundsManagerSess.Contract.CurrentRoundLocked(c.callOpts(ctx))
 }
 
-func (c *client) LastInitializedRound() (*big.Int, error) {
-	return c.roundsManagerSess.LastInitializedRound()
+func (c *client) LastInitializedRound(ctx context.Context) (*big.Int, error) {
+	return c.bindings().roundsManagerSess.Contract.LastInitializedRound(c.callOpts(ctx))
 }
 
-func (c *client) BlockHashForRound(round *big.Int) ([32]byte, error) {
-	return c.roundsManagerSess.BlockHashForRound(round)
+func (c *client) BlockHashForRound(ctx context.Context, round *big.Int) ([32]byte, error) {
+	return c.bindings().roundsManagerSess.Contract.BlockHashForRound(c.callOpts(ctx), round)
 }
 
-func (c *client) CurrentRoundInitialized() (bool, error) {
-	return c.roundsManagerSess.CurrentRoundInitialized()
+func (c *client) CurrentRoundInitialized(ctx context.Context) (bool, error) {
+	return c.bindings().roundsManagerSess.Contract.CurrentRoundInitialized(c.callOpts(ctx))
 }
 
-func (c *client) CurrentRoundStartBlock() (*big.Int, error) {
-	return c.roundsManagerSess.CurrentRoundStartBlock()
+func (c *client) CurrentRoundStartBlock(ctx context.Context) (*big.Int, error) {
+	return c.bindings().roundsManagerSess.Contract.CurrentRoundStartBlock(c.callOpts(ctx))
 }
 
-func (c *client) RoundLength() (*big.Int, error) {
-	return c.roundsManagerSess.RoundLength()
+func (c *client) RoundLength(ctx context.Context) (*big.Int, error) {
+	return c.cachedParam(ctx, "roundLength", c.bindings().roundsManagerSess.Contract.RoundLength)
 }
 
-func (c *client) RoundLockAmount() (*big.Int, error) {
-	return c.roundsManagerSess.RoundLockAmount()
+func (c *client) RoundLockAmount(ctx context.Context) (*big.Int, error) {
+	return c.cachedParam(ctx, "roundLockAmount", c.bindings().roundsManagerSess.Contract.RoundLockAmount)
 }
 
 // Minter
-func (c *client) Inflation() (*big.Int, error) {
-	return c.minterSess.Inflation()
+func (c *client) Inflation(ctx context.Context) (*big.Int, error) {
+	return c.cachedParam(ctx, "inflation", c.bindings().minterSess.Contract.Inflation)
 }
 
-func (c *client) InflationChange() (*big.Int, error) {
-	return c.minterSess.InflationChange()
+func (c *client) InflationChange(ctx context.Context) (*big.Int, error) {
+	return c.cachedParam(ctx, "inflationChange", c.bindings().minterSess.Contract.InflationChange)
 }
 
-func (c *client) TargetBondingRate() (*big.Int, error) {
-	return c.minterSess.TargetBondingRate()
+func (c *client) TargetBondingRate(ctx context.Context) (*big.Int, error) {
+	return c.cachedParam(ctx, "targetBondingRate", c.bindings().minterSess.Contract.TargetBondingRate)
 }
 
-func (c *client) GetGlobalTotalSupply() (*big.Int, error) {
-	return c.minterSess.GetGlobalTotalSupply()
+func (c *client) GetGlobalTotalSupply(ctx context.Context) (*big.Int, error) {
+	return c.bindings().minterSess.Contract.GetGlobalTotalSupply(c.callOpts(ctx))
 }
 
-func (c *client) CurrentMintableTokens() (*big.Int, error) {
-	return c.minterSess.CurrentMintableTokens()
+func (c *client) CurrentMintableTokens(ctx context.Context) (*big.Int, error) {
+	return c.bindings().minterSess.Contract.CurrentMintableTokens(c.callOpts(ctx))
 }
 
 // Token
-func (c *client) Transfer(toAddr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
-	return c.livepeerTokenSess.Contract.Transfer(c.transactOpts(), toAddr, amount)
+func (c *client) Transfer(ctx context.Context, toAddr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().livepeerTokenSess.Contract.Transfer(opts, toAddr, amount)
+	})
 }
 
-func (c *client) Allowance(owner ethcommon.Address, spender ethcommon.Address) (*big.Int, error) {
-	return c.livepeerTokenSess.Allowance(owner, spender)
+func (c *client) Allowance(ctx context.Context, owner ethcommon.Address, spender ethcommon.Address) (*big.Int, error) {
+	return c.bindings().livepeerTokenSess.Contract.Allowance(c.callOpts(ctx), owner, spender)
+}
+
+func (c *client) Approve(ctx context.Context, spender ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().livepeerTokenSess.Contract.Approve(opts, spender, amount)
+	})
 }
 
-func (c *client) Request() (*types.Transaction, error) {
-	return c.livepeerTokenFaucetSess.Contract.Request(c.transactOpts())
+func (c *client) Request(ctx context.Context) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().livepeerTokenFaucetSess.Contract.Request(opts)
+	})
 }
 
-func (c *client) BalanceOf(address ethcommon.Address) (*big.Int, error) {
-	return c.livepeerTokenSess.BalanceOf(address)
+func (c *client) BalanceOf(ctx context.Context, address ethcommon.Address) (*big.Int, error) {
+	return c.bindings().livepeerTokenSess.Contract.BalanceOf(c.callOpts(ctx), address)
 }
 
-func (c *client) TotalSupply() (*big.Int, error) {
-	return c.livepeerTokenSess.TotalSupply()
+func (c *client) TotalSupply(ctx context.Context) (*big.Int, error) {
+	return c.bindings().livepeerTokenSess.Contract.TotalSupply(c.callOpts(ctx))
 }
 
-func (c *client) NextValidRequest(addr ethcommon.Address) (*big.Int, error) {
-	return c.livepeerTokenFaucetSess.NextValidRequest(addr)
+func (c *client) NextValidRequest(ctx context.Context, addr ethcommon.Address) (*big.Int, error) {
+	return c.bindings().livepeerTokenFaucetSess.Contract.NextValidRequest(c.callOpts(ctx), addr)
 }
 
 // Service Registry
-func (c *client) SetServiceURI(serviceURI string) (*types.Transaction, error) {
-	return c.serviceRegistrySess.Contract.SetServiceURI(c.transactOpts(), serviceURI)
+func (c *client) SetServiceURI(ctx context.Context, serviceURI string) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().serviceRegistrySess.Contract.SetServiceURI(opts, serviceURI)
+	})
 }
 
-func (c *client) GetServiceURI(addr ethcommon.Address) (string, error) {
-	return c.serviceRegistrySess.GetServiceURI(addr)
+func (c *client) GetServiceURI(ctx context.Context, addr ethcommon.Address) (string, error) {
+	return c.bindings().serviceRegistrySess.Contract.GetServiceURI(c.callOpts(ctx), addr)
 }
 
 // Staking
-func (c *client) Transcoder(blockRewardCut, feeShare *big.Int) (*types.Transaction, error) {
-	locked, err := c.CurrentRoundLocked()
+func (c *client) Transcoder(ctx context.Context, blockRewardCut, feeShare *big.Int) (*types.Transaction, error) {
+	locked, err := c.CurrentRoundLocked(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -524,13 +853,15 @@ func (c *client) Transcoder(blockRewardCut, feeShare *big.Int) (*types.Transacti
 	if locked {
 		return nil, ErrCurrentRoundLocked
 	} else {
-		return c.bondingManagerSess.Contract.Transcoder(c.transactOpts(), blockRewardCut, feeShare)
+		return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return c.bindings().bondingManagerSess.Contract.Transcoder(opts, blockRewardCut, feeShare)
+		})
 	}
 }
 
-func (c *client) Bond(amount *big.Int, to ethcommon.Address) (*types.Transaction, error) {
+func (c *client) Bond(ctx context.Context, amount *big.Int, to ethcommon.Address) (*types.Transaction, error) {
 	sender := c.Account().Address
-	allowance, err := c.Allowance(sender, c.bondingManagerAddr)
+	allowance, err := c.Allowance(ctx, sender, c.bindings().bondingManagerAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -538,31 +869,35 @@ func (c *client) Bond(amount *big.Int, to ethcommon.Address) (*types.Transaction
 	// If existing allowance set by account for BondingManager is
 	// less than the bond amount, approve the necessary amount
 	if allowance.Cmp(amount) == -1 {
-		tx, err := c.livepeerTokenSess.Contract.Approve(c.transactOpts(), c.bondingManagerAddr, amount)
+		approval := amount
+		if c.infiniteApproval {
+			approval = math.MaxBig256
+		}
+		tx, err := c.Approve(ctx, c.bindings().bondingManagerAddr, approval)
 		if err != nil {
 			return nil, err
 		}
 
-		err = c.CheckTx(tx)
+		err = c.CheckTx(ctx, tx)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// Get transcoder pool
-	transcoders, err := c.TranscoderPool()
+	transcoders, err := c.TranscoderPool(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool")
 	}
 
 	// Get max pool size
-	maxSize, err := c.GetTranscoderPoolMaxSize()
+	maxSize, err := c.GetTranscoderPoolMaxSize(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool max size")
 	}
 
 	// Get delegator
-	delegator, err := c.GetDelegator(sender)
+	delegator, err := c.GetDelegator(ctx, sender)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get delegator")
 	}
@@ -572,12 +907,12 @@ func (c *client) Bond(amount *big.Int, to ethcommon.Address) (*types.Transaction
 	// Switching delegate's calculate old delegate positions
 	var oldHints lpTypes.TranscoderPoolHints
 	if delegator.DelegateAddress != to && delegator.DelegateAddress != (ethcommon.Address{}) {
-		currentRound, err := c.CurrentRound()
+		currentRound, err := c.CurrentRound(ctx)
 		if err != nil {
 			return nil, err
 		}
 
-		delegatorTotalStake, err := c.PendingStake(sender, currentRound)
+		delegatorTotalStake, err := c.PendingStake(ctx, sender, currentRound)
 		if err != nil {
 			return nil, err
 		}
@@ -586,7 +921,7 @@ func (c *client) Bond(amount *big.Int, to ethcommon.Address) (*types.Transaction
 		// the delegator's current pending stake plus the amount
 		amount = new(big.Int).Add(delegatorTotalStake, amount)
 		// Get total bonded
-		totalBonded, err := c.TranscoderTotalStake(delegator.DelegateAddress)
+		totalBonded, err := c.TranscoderTotalStake(ctx, delegator.DelegateAddress)
 		if err != nil {
 			return nil, err
 		}
@@ -596,7 +931,7 @@ func (c *client) Bond(amount *big.Int, to ethcommon.Address) (*types.Transaction
 	}
 
 	// Get total bonded
-	totalBonded, err := c.TranscoderTotalStake(to)
+	totalBonded, err := c.TranscoderTotalStake(ctx, to)
 	if err != nil {
 		return nil, err
 	}
@@ -604,40 +939,42 @@ func (c *client) Bond(amount *big.Int, to ethcommon.Address) (*types.Transaction
 
 	newHints := simulateTranscoderPoolUpdate(to, newStake, transcoders, isFull)
 
-	return c.bondingManagerSess.Contract.BondWithHint(
-		c.transactOpts(),
-		amount,
-		to,
-		oldHints.PosPrev,
-		oldHints.PosNext,
-		newHints.PosPrev,
-		newHints.PosNext,
-	)
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().bondingManagerSess.Contract.BondWithHint(
+			opts,
+			amount,
+			to,
+			oldHints.PosPrev,
+			oldHints.PosNext,
+			newHints.PosPrev,
+			newHints.PosNext,
+		)
+	})
 }
 
-func (c *client) Unbond(amount *big.Int) (*types.Transaction, error) {
+func (c *client) Unbond(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
 	sender := c.Account().Address
 
 	// Get delegator
-	delegator, err := c.GetDelegator(sender)
+	delegator, err := c.GetDelegator(ctx, sender)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get delegator")
 	}
 
 	// Get transcoder pool
-	transcoders, err := c.TranscoderPool()
+	transcoders, err := c.TranscoderPool(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool")
 	}
 
 	// Get max pool size
-	maxSize, err := c.GetTranscoderPoolMaxSize()
+	maxSize, err := c.GetTranscoderPoolMaxSize(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool max size")
 	}
 
 	// Get total bonded
-	totalBonded, err := c.TranscoderTotalStake(delegator.DelegateAddress)
+	totalBonded, err := c.TranscoderTotalStake(ctx, delegator.DelegateAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -648,30 +985,32 @@ func (c *client) Unbond(amount *big.Int) (*types.Transaction, error) {
 
 	hints := simulateTranscoderPoolUpdate(delegator.DelegateAddress, newStake, transcoders, isFull)
 
-	return c.bondingManagerSess.Contract.UnbondWithHint(c.transactOpts(), amount, hints.PosPrev, hints.PosNext)
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().bondingManagerSess.Contract.UnbondWithHint(opts, amount, hints.PosPrev, hints.PosNext)
+	})
 }
 
-func (c *client) RebondFromUnbonded(to ethcommon.Address, unbondingLockID *big.Int) (*types.Transaction, error) {
+func (c *client) RebondFromUnbonded(ctx context.Context, to ethcommon.Address, unbondingLockID *big.Int) (*types.Transaction, error) {
 	sender := c.Account().Address
 
 	// Get transcoder pool
-	transcoders, err := c.TranscoderPool()
+	transcoders, err := c.TranscoderPool(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool")
 	}
 
 	// Get max pool size
-	maxSize, err := c.GetTranscoderPoolMaxSize()
+	maxSize, err := c.GetTranscoderPoolMaxSize(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool max size")
 	}
 
-	totalBonded, err := c.TranscoderTotalStake(to)
+	totalBonded, err := c.TranscoderTotalStake(ctx, to)
 	if err != nil {
 		return nil, err
 	}
 
-	lock, err := c.GetDelegatorUnbondingLock(sender, unbondingLockID)
+	lock, err := c.GetDelegatorUnbondingLock(ctx, sender, unbondingLockID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get unbonding lock")
 	}
@@ -682,36 +1021,38 @@ func (c *client) RebondFromUnbonded(to ethcommon.Address, unbondingLockID *big.I
 
 	hints := simulateTranscoderPoolUpdate(to, newStake, transcoders, isFull)
 
-	return c.bondingManagerSess.Contract.RebondFromUnbondedWithHint(c.transactOpts(), to, unbondingLockID, hints.PosPrev, hints.PosNext)
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().bondingManagerSess.Contract.RebondFromUnbondedWithHint(opts, to, unbondingLockID, hints.PosPrev, hints.PosNext)
+	})
 }
 
-func (c *client) Rebond(unbondingLockID *big.Int) (*types.Transaction, error) {
+func (c *client) Rebond(ctx context.Context, unbondingLockID *big.Int) (*types.Transaction, error) {
 	sender := c.Account().Address
 
 	// Get delegator
-	delegator, err := c.GetDelegator(sender)
+	delegator, err := c.GetDelegator(ctx, sender)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get delegator")
 	}
 
 	// Get transcoder pool
-	transcoders, err := c.TranscoderPool()
+	transcoders, err := c.TranscoderPool(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool")
 	}
 
 	// Get max pool size
-	maxSize, err := c.GetTranscoderPoolMaxSize()
+	maxSize, err := c.GetTranscoderPoolMaxSize(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool max size")
 	}
 
-	lock, err := c.GetDelegatorUnbondingLock(sender, unbondingLockID)
+	lock, err := c.GetDelegatorUnbondingLock(ctx, sender, unbondingLockID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get unbonding lock")
 	}
 
-	transcoderStake, err := c.TranscoderTotalStake(delegator.DelegateAddress)
+	transcoderStake, err := c.TranscoderTotalStake(ctx, delegator.DelegateAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -722,76 +1063,94 @@ func (c *client) Rebond(unbondingLockID *big.Int) (*types.Transaction, error) {
 
 	hints := simulateTranscoderPoolUpdate(delegator.DelegateAddress, newStake, transcoders, isFull)
 
-	return c.bondingManagerSess.Contract.RebondWithHint(c.transactOpts(), unbondingLockID, hints.PosPrev, hints.PosNext)
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().bondingManagerSess.Contract.RebondWithHint(opts, unbondingLockID, hints.PosPrev, hints.PosNext)
+	})
 }
 
-func (c *client) WithdrawStake(unbondingLockID *big.Int) (*types.Transaction, error) {
-	return c.bondingManagerSess.Contract.WithdrawStake(c.transactOpts(), unbondingLockID)
+func (c *client) WithdrawStake(ctx context.Context, unbondingLockID *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().bondingManagerSess.Contract.WithdrawStake(opts, unbondingLockID)
+	})
 }
 
-func (c *client) L1WithdrawFees() (*types.Transaction, error) {
-	return c.l1BondingManagerSess.Contract.WithdrawFees(c.transactOpts())
+func (c *client) L1WithdrawFees(ctx context.Context) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().l1BondingManagerSess.Contract.WithdrawFees(opts)
+	})
 }
 
-func (c *client) ClaimEarnings(endRound *big.Int) (*types.Transaction, error) {
-	return c.bondingManagerSess.Contract.ClaimEarnings(c.transactOpts(), endRound)
+func (c *client) ClaimEarnings(ctx context.Context, endRound *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().bondingManagerSess.Contract.ClaimEarnings(opts, endRound)
+	})
 }
 
-func (c *client) GetTranscoderPoolMaxSize() (*big.Int, error) {
-	return c.bondingManagerSess.GetTranscoderPoolMaxSize()
+func (c *client) GetTranscoderPoolMaxSize(ctx context.Context) (*big.Int, error) {
+	return c.cachedParam(ctx, "numActiveTranscoders", c.bindings().bondingManagerSess.Contract.GetTranscoderPoolMaxSize)
 }
 
-func (c *client) TranscoderTotalStake(to ethcommon.Address) (*big.Int, error) {
-	return c.bondingManagerSess.TranscoderTotalStake(to)
+func (c *client) TranscoderTotalStake(ctx context.Context, to ethcommon.Address) (*big.Int, error) {
+	return c.bindings().bondingManagerSess.Contract.TranscoderTotalStake(c.callOpts(ctx), to)
 }
 
-func (c *client) GetTotalBonded() (*big.Int, error) {
-	return c.bondingManagerSess.GetTotalBonded()
+func (c *client) GetTotalBonded(ctx context.Context) (*big.Int, error) {
+	return c.bindings().bondingManagerSess.Contract.GetTotalBonded(c.callOpts(ctx))
 }
 
-func (c *client) PendingStake(delegator ethcommon.Address, endRound *big.Int) (*big.Int, error) {
-	return c.bondingManagerSess.PendingStake(delegator, endRound)
+func (c *client) PendingStake(ctx context.Context, delegator ethcommon.Address, endRound *big.Int) (*big.Int, error) {
+	return c.bindings().bondingManagerSess.Contract.PendingStake(c.callOpts(ctx), delegator, endRound)
 }
 
-func (c *client) TranscoderStatus(transcoder ethcommon.Address) (uint8, error) {
-	return c.bondingManagerSess.TranscoderStatus(transcoder)
+func (c *client) TranscoderStatus(ctx context.Context, transcoder ethcommon.Address) (uint8, error) {
+	return c.bindings().bondingManagerSess.Contract.TranscoderStatus(c.callOpts(ctx), transcoder)
 }
 
-func (c *client) DelegatorStatus(delegator ethcommon.Address) (uint8, error) {
-	return c.bondingManagerSess.DelegatorStatus(delegator)
+func (c *client) DelegatorStatus(ctx context.Context, delegator ethcommon.Address) (uint8, error) {
+	return c.bindings().bondingManagerSess.Contract.DelegatorStatus(c.callOpts(ctx), delegator)
 }
 
-func (c *client) GetFirstTranscoderInPool() (ethcommon.Address, error) {
-	return c.bondingManagerSess.GetFirstTranscoderInPool()
+func (c *client) GetFirstTranscoderInPool(ctx context.Context) (ethcommon.Address, error) {
+	return c.bindings().bondingManagerSess.Contract.GetFirstTranscoderInPool(c.callOpts(ctx))
 }
 
-func (c *client) PendingFees(delegator ethcommon.Address, endRound *big.Int) (*big.Int, error) {
-	return c.bondingManagerSess.PendingFees(delegator, endRound)
+func (c *client) PendingFees(ctx context.Context, delegator ethcommon.Address, endRound *big.Int) (*big.Int, error) {
+	return c.bindings().bondingManagerSess.Contract.PendingFees(c.callOpts(ctx), delegator, endRound)
 }
 
-func (c *client) GetNextTranscoderInPool(transcoder ethcommon.Address) (ethcommon.Address, error) {
-	return c.bondingManagerSess.GetNextTranscoderInPool(transcoder)
+func (c *client) GetNextTranscoderInPool(ctx context.Context, transcoder ethcommon.Address) (ethcommon.Address, error) {
+	return c.bindings().bondingManagerSess.Contract.GetNextTranscoderInPool(c.callOpts(ctx), transcoder)
 }
 
-func (c *client) GetTranscoderPoolSize() (*big.Int, error) {
-	return c.bondingManagerSess.GetTranscoderPoolSize()
+func (c *client) GetTranscoderPoolSize(ctx context.Context) (*big.Int, error) {
+	return c.bindings().bondingManagerSess.Contract.GetTranscoderPoolSize(c.callOpts(ctx))
 }
 
-func (c *client) UnbondingPeriod() (uint64, error) {
-	return c.bondingManagerSess.UnbondingPeriod()
+func (c *client) UnbondingPeriod(ctx context.Context) (uint64, error) {
+	period, err := c.cachedParam(ctx, "unbondingPeriod", func(opts *bind.CallOpts) (*big.Int, error) {
+		period, err := c.bindings().bondingManagerSess.Contract.UnbondingPeriod(opts)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetUint64(period), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return period.Uint64(), nil
 }
 
-func (c *client) IsActiveTranscoder() (bool, error) {
-	return c.bondingManagerSess.IsActiveTranscoder(c.Account().Address)
+func (c *client) IsActiveTranscoder(ctx context.Context) (bool, error) {
+	return c.bindings().bondingManagerSess.Contract.IsActiveTranscoder(c.callOpts(ctx), c.Account().Address)
 }
 
-func (c *client) GetTranscoder(addr ethcommon.Address) (*lpTypes.Transcoder, error) {
-	tInfo, err := c.bondingManagerSess.GetTranscoder(addr)
+func (c *client) GetTranscoder(ctx context.Context, addr ethcommon.Address) (*lpTypes.Transcoder, error) {
+	tInfo, err := c.bindings().bondingManagerSess.Contract.GetTranscoder(c.callOpts(ctx), addr)
 	if err != nil {
 		return nil, err
 	}
 
-	tStatus, err := c.TranscoderStatus(addr)
+	tStatus, err := c.TranscoderStatus(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
@@ -801,17 +1160,17 @@ func (c *client) GetTranscoder(addr ethcommon.Address) (*lpTypes.Transcoder, err
 		return nil, err
 	}
 
-	delegatedStake, err := c.TranscoderTotalStake(addr)
+	delegatedStake, err := c.TranscoderTotalStake(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
 
-	active, err := c.bondingManagerSess.IsActiveTranscoder(addr)
+	active, err := c.bindings().bondingManagerSess.Contract.IsActiveTranscoder(c.callOpts(ctx), addr)
 	if err != nil {
 		return nil, err
 	}
 
-	serviceURI, err := c.GetServiceURI(addr)
+	serviceURI, err := c.GetServiceURI(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
@@ -831,8 +1190,8 @@ func (c *client) GetTranscoder(addr ethcommon.Address) (*lpTypes.Transcoder, err
 	}, nil
 }
 
-func (c *client) GetTranscoderEarningsPoolForRound(addr ethcommon.Address, round *big.Int) (*lpTypes.TokenPools, error) {
-	tp, err := c.bondingManagerSess.GetTranscoderEarningsPoolForRound(addr, round)
+func (c *client) GetTranscoderEarningsPoolForRound(ctx context.Context, addr ethcommon.Address, round *big.Int) (*lpTypes.TokenPools, error) {
+	tp, err := c.bindings().bondingManagerSess.Contract.GetTranscoderEarningsPoolForRound(c.callOpts(ctx), addr, round)
 	if err != nil {
 		return nil, err
 	}
@@ -846,14 +1205,14 @@ func (c *client) GetTranscoderEarningsPoolForRound(addr ethcommon.Address, round
 	}, nil
 }
 
-func (c *client) GetDelegator(addr ethcommon.Address) (*lpTypes.Delegator, error) {
-	dInfo, err := c.bondingManagerSess.GetDelegator(addr)
+func (c *client) GetDelegator(ctx context.Context, addr ethcommon.Address) (*lpTypes.Delegator, error) {
+	dInfo, err := c.bindings().bondingManagerSess.Contract.GetDelegator(c.callOpts(ctx), addr)
 	if err != nil {
 		glog.Errorf("Error getting delegator from bonding manager: %v", err)
 		return nil, err
 	}
 
-	dStatus, err := c.DelegatorStatus(addr)
+	dStatus, err := c.DelegatorStatus(ctx, addr)
 	if err != nil {
 		glog.Errorf("Error getting status: %v", err)
 		return nil, err
@@ -863,13 +1222,13 @@ func (c *client) GetDelegator(addr ethcommon.Address) (*lpTypes.Delegator, error
 	if err != nil {
 		return nil, err
 	}
-	currentRound, err := c.CurrentRound()
+	currentRound, err := c.CurrentRound(ctx)
 	if err != nil {
 		glog.Errorf("Error getting current round: %v", err)
 		return nil, err
 	}
 
-	pendingStake, err := c.PendingStake(addr, currentRound)
+	pendingStake, err := c.PendingStake(ctx, addr, currentRound)
 	if err != nil {
 		if err.Error() == "abi: unmarshalling empty output" {
 			pendingStake = big.NewInt(-1)
@@ -879,7 +1238,7 @@ func (c *client) GetDelegator(addr ethcommon.Address) (*lpTypes.Delegator, error
 		}
 	}
 
-	pendingFees, err := c.PendingFees(addr, currentRound)
+	pendingFees, err := c.PendingFees(ctx, addr, currentRound)
 	if err != nil {
 		if err.Error() == "abi: unmarshalling empty output" {
 			pendingFees = big.NewInt(-1)
@@ -904,8 +1263,8 @@ func (c *client) GetDelegator(addr ethcommon.Address) (*lpTypes.Delegator, error
 	}, nil
 }
 
-func (c *client) GetDelegatorUnbondingLock(addr ethcommon.Address, unbondingLockId *big.Int) (*lpTypes.UnbondingLock, error) {
-	lock, err := c.bondingManagerSess.GetDelegatorUnbondingLock(addr, unbondingLockId)
+func (c *client) GetDelegatorUnbondingLock(ctx context.Context, addr ethcommon.Address, unbondingLockId *big.Int) (*lpTypes.UnbondingLock, error) {
+	lock, err := c.bindings().bondingManagerSess.Contract.GetDelegatorUnbondingLock(c.callOpts(ctx), addr, unbondingLockId)
 	if err != nil {
 		return nil, err
 	}
@@ -918,82 +1277,211 @@ func (c *client) GetDelegatorUnbondingLock(addr ethcommon.Address, unbondingLock
 	}, nil
 }
 
-// TicketBroker
-func (c *client) Unlock() (*types.Transaction, error) {
-	return c.ticketBrokerSess.Contract.Unlock(c.transactOpts())
+// GetDelegatorUnbondingLocks returns the pending unbonding locks of a delegator. Locks that were already withdrawn or
+// rebonded are skipped
+func (c *client) GetDelegatorUnbondingLocks(ctx context.Context, addr ethcommon.Address) ([]*lpTypes.UnbondingLock, error) {
+	dInfo, err := c.bindings().bondingManagerSess.Contract.GetDelegator(c.callOpts(ctx), addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var locks []*lpTypes.UnbondingLock
+	for id := big.NewInt(0); id.Cmp(dInfo.NextUnbondingLockId) < 0; id = new(big.Int).Add(id, big.NewInt(1)) {
+		lock, err := c.GetDelegatorUnbondingLock(ctx, addr, id)
+		if err != nil {
+			return nil, err
+		}
+		if lock.Amount.Sign() == 0 {
+			continue
+		}
+		locks = append(locks, lock)
+	}
+
+	return locks, nil
 }
 
-func (c *client) CancelUnlock() (*types.Transaction, error) {
-	return c.ticketBrokerSess.Contract.CancelUnlock(c.transactOpts())
+func (c *client) DelegatorInfo(ctx context.Context, addr ethcommon.Address) (*lpTypes.DelegatorInfo, error) {
+	d, err := c.GetDelegator(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	locks, err := c.GetDelegatorUnbondingLocks(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &lpTypes.DelegatorInfo{
+		Delegator:       d,
+		UnbondingLocks:  locks,
+		UnbondingAmount: big.NewInt(0),
+	}
+	for _, lock := range locks {
+		info.UnbondingAmount.Add(info.UnbondingAmount, lock.Amount)
+	}
+
+	if IsNullAddress(d.DelegateAddress) {
+		d.PendingStake = new(big.Int).Set(d.BondedAmount)
+		d.PendingFees = new(big.Int).Set(d.Fees)
+	} else {
+		currentRound, err := c.CurrentRound(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tInfo, err := c.bindings().bondingManagerSess.Contract.GetTranscoder(c.callOpts(ctx), d.DelegateAddress)
+		if err != nil {
+			return nil, err
+		}
+		t := &earningsTranscoder{
+			LastRewardRound:   tInfo.LastRewardRound,
+			LastFeeRound:      tInfo.LastFeeRound,
+			CumulativeRewards: tInfo.CumulativeRewards,
+			CumulativeFees:    tInfo.CumulativeFees,
+		}
+		pool := func(round *big.Int) (*lpTypes.TokenPools, error) {
+			return c.GetTranscoderEarningsPoolForRound(ctx, d.DelegateAddress, round)
+		}
+
+		d.PendingStake, d.PendingFees, err = pendingStakeAndFees(d, t, currentRound, pool)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	info.UnclaimedRewards = new(big.Int).Sub(d.PendingStake, d.BondedAmount)
+	info.UnclaimedFees = new(big.Int).Sub(d.PendingFees, d.Fees)
+
+	return info, nil
+}
+
+// TicketBroker
+func (c *client) Unlock(ctx context.Context) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().ticketBrokerSess.Contract.Unlock(opts)
+	})
 }
 
-func (c *client) Withdraw() (*types.Transaction, error) {
-	return c.ticketBrokerSess.Contract.Withdraw(c.transactOpts())
+func (c *client) CancelUnlock(ctx context.Context) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().ticketBrokerSess.Contract.CancelUnlock(opts)
+	})
 }
 
-func (c *client) UnlockPeriod() (*big.Int, error) {
-	return c.ticketBrokerSess.UnlockPeriod()
+func (c *client) Withdraw(ctx context.Context) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().ticketBrokerSess.Contract.Withdraw(opts)
+	})
 }
 
-func (c *client) ClaimedReserve(reserveHolder ethcommon.Address, claimant ethcommon.Address) (*big.Int, error) {
-	return c.ticketBrokerSess.ClaimedReserve(reserveHolder, claimant)
+func (c *client) UnlockPeriod(ctx context.Context) (*big.Int, error) {
+	return c.cachedParam(ctx, "unlockPeriod", c.bindings().ticketBrokerSess.Contract.UnlockPeriod)
 }
 
-func (c *client) TranscoderPool() ([]*lpTypes.Transcoder, error) {
-	var transcoders []*lpTypes.Transcoder
+func (c *client) ClaimedReserve(ctx context.Context, reserveHolder ethcommon.Address, claimant ethcommon.Address) (*big.Int, error) {
+	return c.bindings().ticketBrokerSess.Contract.ClaimedReserve(c.callOpts(ctx), reserveHolder, claimant)
+}
 
-	tAddr, err := c.GetFirstTranscoderInPool()
+func (c *client) RegisteredTranscoders(ctx context.Context, start ethcommon.Address, limit int) ([]*lpTypes.Transcoder, ethcommon.Address, error) {
+	first := func() (ethcommon.Address, error) { return c.GetFirstTranscoderInPool(ctx) }
+	nextInPool := func(addr ethcommon.Address) (ethcommon.Address, error) { return c.GetNextTranscoderInPool(ctx, addr) }
+	addrs, next, err := transcoderPoolPage(first, nextInPool, start, limit)
 	if err != nil {
-		return nil, err
+		return nil, ethcommon.Address{}, err
 	}
 
-	for !IsNullAddress(tAddr) {
-		t, err := c.GetTranscoder(tAddr)
+	transcoders := make([]*lpTypes.Transcoder, 0, len(addrs))
+	for _, addr := range addrs {
+		t, err := c.GetTranscoder(ctx, addr)
 		if err != nil {
-			return nil, err
+			return nil, ethcommon.Address{}, err
 		}
 
 		transcoders = append(transcoders, t)
+	}
+
+	return transcoders, next, nil
+}
 
-		tAddr, err = c.GetNextTranscoderInPool(tAddr)
+func (c *client) TranscoderPool(ctx context.Context) ([]*lpTypes.Transcoder, error) {
+	var transcoders []*lpTypes.Transcoder
+
+	var start ethcommon.Address
+	for {
+		page, next, err := c.RegisteredTranscoders(ctx, start, transcoderPoolPageSize)
 		if err != nil {
 			return nil, err
 		}
+
+		transcoders = append(transcoders, page...)
+
+		if IsNullAddress(next) {
+			return transcoders, nil
+		}
+		start = next
+	}
+}
+
+// transcoderPoolPage walks the linked list of the transcoder pool from start, or from the first transcoder if start is
+// the null address, and returns the addresses of at most limit transcoders and the address of the transcoder after them.
+// A limit <= 0 returns the rest of the pool
+func transcoderPoolPage(first func() (ethcommon.Address, error), next func(ethcommon.Address) (ethcommon.Address, error),
+	start ethcommon.Address, limit int) ([]ethcommon.Address, ethcommon.Address, error) {
+
+	addr := start
+	if IsNullAddress(addr) {
+		var err error
+		addr, err = first()
+		if err != nil {
+			return nil, ethcommon.Address{}, err
+		}
 	}
 
-	return transcoders, nil
+	var addrs []ethcommon.Address
+	for !IsNullAddress(addr) && (limit <= 0 || len(addrs) < limit) {
+		addrs = append(addrs, addr)
+
+		var err error
+		addr, err = next(addr)
+		if err != nil {
+			return nil, ethcommon.Address{}, err
+		}
+	}
+
+	return addrs, addr, nil
 }
 
-func (c *client) Vote(pollAddr ethcommon.Address, choiceID *big.Int) (*types.Transaction, error) {
+func (c *client) Vote(ctx context.Context, pollAddr ethcommon.Address, choiceID *big.Int) (*types.Transaction, error) {
 	poll, err := contracts.NewPoll(pollAddr, c.backend)
 	if err != nil {
 		return nil, err
 	}
 
-	opts := c.transactOpts()
-	return poll.Vote(opts, choiceID)
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return poll.Vote(opts, choiceID)
+	})
 }
 
-func (c *client) Reward() (*types.Transaction, error) {
+func (c *client) Reward(ctx context.Context) (*types.Transaction, error) {
 	addr := c.accountManager.Account().Address
 
-	tr, err := c.GetTranscoder(addr)
+	tr, err := c.GetTranscoder(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
 
-	ep, err := c.GetTranscoderEarningsPoolForRound(addr, tr.LastActiveStakeUpdateRound)
+	ep, err := c.GetTranscoderEarningsPoolForRound(ctx, addr, tr.LastActiveStakeUpdateRound)
 	if err != nil {
 		return nil, err
 	}
 	activeTotalStake := ep.TotalStake
 
-	mintable, err := c.CurrentMintableTokens()
+	mintable, err := c.CurrentMintableTokens(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get current mintable tokens")
 	}
 
-	totalBonded, err := c.GetTotalBonded()
+	totalBonded, err := c.GetTotalBonded(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get total bonded")
 	}
@@ -1006,24 +1494,28 @@ func (c *client) Reward() (*types.Transaction, error) {
 	reward := new(big.Int).Div(new(big.Int).Mul(mintable, activeTotalStake), totalBonded)
 
 	// get the transcoder pool
-	transcoders, err := c.TranscoderPool()
+	transcoders, err := c.TranscoderPool(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool")
 	}
 
 	// get max pool size
-	maxSize, err := c.GetTranscoderPoolMaxSize()
+	maxSize, err := c.GetTranscoderPoolMaxSize(ctx)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get transcoder pool max size")
 	}
 
 	hints := simulateTranscoderPoolUpdate(addr, reward.Add(reward, tr.DelegatedStake), transcoders, len(transcoders) == int(maxSize.Int64()))
 
-	return c.bondingManagerSess.Contract.RewardWithHint(c.transactOpts(), hints.PosPrev, hints.PosNext)
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().bondingManagerSess.Contract.RewardWithHint(opts, hints.PosPrev, hints.PosNext)
+	})
 }
 
-func (c *client) WithdrawFees(addr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
-	return c.bondingManagerSess.Contract.WithdrawFees(c.transactOpts(), addr, amount)
+func (c *client) WithdrawFees(ctx context.Context, addr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().bondingManagerSess.Contract.WithdrawFees(opts, addr, amount)
+	})
 }
 
 // Helpers
@@ -1084,32 +1576,46 @@ func findTranscoderHints(del ethcommon.Address, transcoders []*lpTypes.Transcode
 func (c *client) ContractAddresses() map[string]ethcommon.Address {
 	addrMap := make(map[string]ethcommon.Address)
 	addrMap["Controller"] = c.controllerAddr
-	addrMap["LivepeerToken"] = c.tokenAddr
-	addrMap["LivepeerTokenFaucet"] = c.faucetAddr
-	addrMap["TicketBroker"] = c.ticketBrokerAddr
-	addrMap["RoundsManager"] = c.roundsManagerAddr
-	addrMap["BondingManager"] = c.bondingManagerAddr
-	addrMap["Minter"] = c.minterAddr
+	addrMap["LivepeerToken"] = c.bindings().tokenAddr
+	addrMap["ServiceRegistry"] = c.bindings().serviceRegistryAddr
+	addrMap["LivepeerTokenFaucet"] = c.bindings().faucetAddr
+	addrMap["TicketBroker"] = c.bindings().ticketBrokerAddr
+	addrMap["RoundsManager"] = c.bindings().roundsManagerAddr
+	addrMap["BondingManager"] = c.bindings().bondingManagerAddr
+	addrMap["Minter"] = c.bindings().minterAddr
 
 	return addrMap
 }
 
-func (c *client) CheckTx(tx *types.Transaction) error {
+func (c *client) CheckTx(ctx context.Context, tx *types.Transaction) error {
+	if c.estimates != nil {
+		// The transaction was not sent
+		return nil
+	}
+
 	receipts := make(chan *transactionReceipt, 10)
 	txSub := c.tm.Subscribe(receipts)
 	defer txSub.Unsubscribe()
 
 	for {
 		select {
+		case <-ctx.Done():
+			return ctx.Err()
 		case err := <-txSub.Err():
 			return err
 		case receipt := <-receipts:
 			if tx.Hash() == receipt.originTxHash {
+				if receipt.err == ErrReceiptReverted {
+					return fmt.Errorf("%w txHash=%v", ErrReceiptReverted, receipt.TxHash.Hex())
+				}
+				if receipt.err == context.DeadlineExceeded {
+					return lperrors.Retryable(fmt.Errorf("transaction timed out txHash=%v: %w", tx.Hash().Hex(), receipt.err))
+				}
 				if receipt.err != nil {
 					return receipt.err
 				}
 				if receipt.Status == uint64(0) {
-					return fmt.Errorf("transaction failed txHash=%v", receipt.TxHash.Hex())
+					return lperrors.Fatal(fmt.Errorf("transaction failed txHash=%v", receipt.TxHash.Hex()))
 				}
 				return nil
 			}
@@ -1,10 +1,17 @@
 package eth
 
 import (
+	"context"
+	"errors"
 	"math/big"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	lpTypes "github.com/livepeer/go-livepeer/eth/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -161,3 +168,359 @@ func TestFindTranscoderHints(t *testing.T) {
 	assert.Equal(hints.PosPrev, ethcommon.HexToAddress("bbb"))
 	assert.Equal(hints.PosNext, ethcommon.HexToAddress("ddd"))
 }
+
+func TestTranscoderPoolPage(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := []ethcommon.Address{ethcommon.HexToAddress("aaa"), ethcommon.HexToAddress("bbb"), ethcommon.HexToAddress("ccc")}
+	first := func() (ethcommon.Address, error) { return pool[0], nil }
+	next := func(addr ethcommon.Address) (ethcommon.Address, error) {
+		for i := range pool[:len(pool)-1] {
+			if pool[i] == addr {
+				return pool[i+1], nil
+			}
+		}
+		return ethcommon.Address{}, nil
+	}
+
+	// Pages start with the first transcoder
+	addrs, nextAddr, err := transcoderPoolPage(first, next, ethcommon.Address{}, 2)
+	assert.Nil(err)
+	assert.Equal(pool[:2], addrs)
+	assert.Equal(pool[2], nextAddr)
+
+	// The last page returns the null address
+	addrs, nextAddr, err = transcoderPoolPage(first, next, nextAddr, 2)
+	assert.Nil(err)
+	assert.Equal(pool[2:], addrs)
+	assert.True(IsNullAddress(nextAddr))
+
+	// Without a limit the rest of the pool is returned
+	addrs, nextAddr, err = transcoderPoolPage(first, next, pool[1], 0)
+	assert.Nil(err)
+	assert.Equal(pool[1:], addrs)
+	assert.True(IsNullAddress(nextAddr))
+
+	// An empty pool has no transcoders
+	empty := func() (ethcommon.Address, error) { return ethcommon.Address{}, nil }
+	addrs, nextAddr, err = transcoderPoolPage(empty, next, ethcommon.Address{}, 2)
+	assert.Nil(err)
+	assert.Empty(addrs)
+	assert.True(IsNullAddress(nextAddr))
+
+	// Errors are returned
+	expErr := errors.New("GetNextTranscoderInPool error")
+	failing := func(ethcommon.Address) (ethcommon.Address, error) { return ethcommon.Address{}, expErr }
+	_, _, err = transcoderPoolPage(first, failing, ethcommon.Address{}, 2)
+	assert.Equal(expErr, err)
+}
+
+func TestStubClient_RegisteredTranscoders(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &StubClient{Orchestrators: []*lpTypes.Transcoder{
+		{Address: ethcommon.HexToAddress("aaa")},
+		{Address: ethcommon.HexToAddress("bbb")},
+		{Address: ethcommon.HexToAddress("ccc")},
+	}}
+
+	var all []*lpTypes.Transcoder
+	var start ethcommon.Address
+	for {
+		page, next, err := c.RegisteredTranscoders(context.Background(), start, 2)
+		assert.Nil(err)
+		all = append(all, page...)
+		if IsNullAddress(next) {
+			break
+		}
+		start = next
+	}
+	assert.Equal(c.Orchestrators, all)
+}
+
+func TestResolveContract_Override(t *testing.T) {
+	assert := assert.New(t)
+
+	addr := ethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	c := &client{
+		contractOverrides: map[string]ethcommon.Address{"BondingManager": addr},
+	}
+
+	// Override is returned without querying the Controller
+	resolved, err := c.resolveContract("BondingManager", nil)
+	assert.Nil(err)
+	assert.Equal(addr, resolved)
+
+	// Override takes precedence over the cached address
+	cached := map[string]ethcommon.Address{
+		"BondingManager": ethcommon.HexToAddress("0x2222222222222222222222222222222222222222"),
+		"TicketBroker":   ethcommon.HexToAddress("0x3333333333333333333333333333333333333333"),
+	}
+	resolved, err = c.resolveContract("BondingManager", cached)
+	assert.Nil(err)
+	assert.Equal(addr, resolved)
+
+	// Cached address is returned without querying the Controller
+	resolved, err = c.resolveContract("TicketBroker", cached)
+	assert.Nil(err)
+	assert.Equal(cached["TicketBroker"], resolved)
+}
+
+type stubContractCache struct {
+	addrs map[ethcommon.Address]map[string]ethcommon.Address
+	err   error
+}
+
+func (c *stubContractCache) ContractAddresses(controllerAddr ethcommon.Address) (map[string]ethcommon.Address, error) {
+	return c.addrs[controllerAddr], c.err
+}
+
+func (c *stubContractCache) SetContractAddresses(controllerAddr ethcommon.Address, addrs map[string]ethcommon.Address) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.addrs[controllerAddr] = addrs
+	return nil
+}
+
+func TestCacheContracts(t *testing.T) {
+	assert := assert.New(t)
+
+	controller := ethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	cs := &contractSet{
+		bondingManagerAddr: ethcommon.HexToAddress("0x2222222222222222222222222222222222222222"),
+		ticketBrokerAddr:   ethcommon.HexToAddress("0x3333333333333333333333333333333333333333"),
+	}
+
+	// Nothing is cached without a cache
+	c := &client{controllerAddr: controller}
+	assert.Nil(c.cacheContracts(cs))
+
+	// Overridden addresses are not cached
+	cache := &stubContractCache{addrs: make(map[ethcommon.Address]map[string]ethcommon.Address)}
+	c = &client{
+		controllerAddr:    controller,
+		contractOverrides: map[string]ethcommon.Address{"BondingManager": cs.bondingManagerAddr},
+		contractCache:     cache,
+	}
+	assert.Nil(c.cacheContracts(cs))
+	assert.Equal(cs.ticketBrokerAddr, cache.addrs[controller]["TicketBroker"])
+	_, ok := cache.addrs[controller]["BondingManager"]
+	assert.False(ok)
+
+	cache.err = errors.New("cache error")
+	assert.EqualError(c.cacheContracts(cs), "cache error")
+}
+
+func TestWithGasFees(t *testing.T) {
+	assert := assert.New(t)
+
+	backend := &stubGasBackend{head: &types.Header{BaseFee: big.NewInt(100)}, tip: big.NewInt(2)}
+	c := &client{
+		backend:     backend,
+		gm:          NewGasManager(backend),
+		transOpts:   &bind.TransactOpts{},
+		transOptsMu: &sync.RWMutex{},
+	}
+	c.setTransactOpts(bind.TransactOpts{GasLimit: 1000})
+
+	opts, err := c.transactOpts(context.Background())
+	assert.Nil(err)
+	assert.Equal(uint64(1000), opts.GasLimit)
+	assert.Equal(big.NewInt(202), opts.GasFeeCap)
+	assert.Equal(big.NewInt(2), opts.GasTipCap)
+
+	fc := c.WithGasFees(GasFees{MaxFee: big.NewInt(500), MaxPriorityFee: big.NewInt(10)}).(*client)
+	opts, err = fc.transactOpts(context.Background())
+	assert.Nil(err)
+	assert.Equal(uint64(1000), opts.GasLimit)
+	assert.Equal(big.NewInt(500), opts.GasFeeCap)
+	assert.Equal(big.NewInt(10), opts.GasTipCap)
+
+	// The original client is unaffected while the transact opts are shared
+	c.setTransactOpts(bind.TransactOpts{GasLimit: 2000})
+	opts, err = c.transactOpts(context.Background())
+	assert.Nil(err)
+	assert.Equal(big.NewInt(202), opts.GasFeeCap)
+	opts, err = fc.transactOpts(context.Background())
+	assert.Nil(err)
+	assert.Equal(uint64(2000), opts.GasLimit)
+}
+
+func TestTransact(t *testing.T) {
+	assert := assert.New(t)
+
+	backend := &stubGasBackend{head: &types.Header{}}
+	c := &client{
+		backend:     backend,
+		gm:          NewGasManager(backend),
+		transOpts:   &bind.TransactOpts{},
+		transOptsMu: &sync.RWMutex{},
+		txMu:        &sync.Mutex{},
+	}
+
+	// Concurrent transactions are sent one at a time
+	var sending, overlaps int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.WithGasFees(GasFees{}).(*client).transact(context.Background(), func(opts *bind.TransactOpts) (*types.Transaction, error) {
+				if atomic.AddInt32(&sending, 1) > 1 {
+					atomic.AddInt32(&overlaps, 1)
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&sending, -1)
+				return nil, nil
+			})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(int32(0), overlaps)
+
+	// A transaction with a used nonce is sent once more
+	calls := 0
+	tx := types.NewTransaction(1, ethcommon.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	res, err := c.transact(context.Background(), func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("nonce too low")
+		}
+		return tx, nil
+	})
+	assert.Nil(err)
+	assert.Equal(tx, res)
+	assert.Equal(2, calls)
+
+	calls = 0
+	_, err = c.transact(context.Background(), func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		calls++
+		return nil, errors.New("nonce too low")
+	})
+	assert.EqualError(err, "nonce too low")
+	assert.Equal(2, calls)
+
+	// Other errors are returned
+	calls = 0
+	_, err = c.transact(context.Background(), func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		calls++
+		return nil, errors.New("execution reverted")
+	})
+	assert.EqualError(err, "execution reverted")
+	assert.Equal(1, calls)
+}
+
+func TestTransact_Context(t *testing.T) {
+	assert := assert.New(t)
+
+	backend := &stubGasBackend{head: &types.Header{}}
+	c := &client{
+		backend:     backend,
+		gm:          NewGasManager(backend),
+		transOpts:   &bind.TransactOpts{},
+		transOptsMu: &sync.RWMutex{},
+		txMu:        &sync.Mutex{},
+		tm:          &TransactionManager{},
+	}
+
+	// The transaction is sent with the ctx of the caller, marked so that the RPC budget does not throttle it
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		assert.Equal(ctx.Done(), opts.Context.Done())
+		assert.True(isCriticalRPC(opts.Context))
+		return nil, nil
+	})
+	assert.Nil(err)
+
+	// Waiting for the receipt stops when ctx is done
+	cancel()
+	tx := types.NewTransaction(1, ethcommon.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	assert.Equal(context.Canceled, c.CheckTx(ctx, tx))
+}
+
+func TestEstimateTxCost(t *testing.T) {
+	assert := assert.New(t)
+
+	backend := &stubGasBackend{head: &types.Header{BaseFee: big.NewInt(100)}, tip: big.NewInt(2)}
+	c := &client{
+		backend:     backend,
+		gm:          NewGasManager(backend),
+		transOpts:   &bind.TransactOpts{},
+		transOptsMu: &sync.RWMutex{},
+		txMu:        &sync.Mutex{},
+	}
+
+	dynamicTx := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		assert.True(opts.NoSend)
+		return opts.Signer(opts.From, types.NewTx(&types.DynamicFeeTx{
+			Gas:       1000,
+			GasFeeCap: opts.GasFeeCap,
+			GasTipCap: opts.GasTipCap,
+			Value:     big.NewInt(5),
+		}))
+	}
+
+	// The transactions are collected instead of being sent
+	cost, err := c.EstimateTxCost(context.Background(), func(cl LivepeerEthClient) (*types.Transaction, error) {
+		tx, err := cl.(*client).transact(context.Background(), dynamicTx)
+		if err != nil {
+			return nil, err
+		}
+		if err := cl.CheckTx(context.Background(), tx); err != nil {
+			return nil, err
+		}
+		return cl.(*client).transact(context.Background(), dynamicTx)
+	})
+	assert.Nil(err)
+	assert.Equal(uint64(2000), cost.Gas)
+	assert.Equal(big.NewInt(102), cost.GasPrice)
+	assert.Equal(big.NewInt(10), cost.Value)
+	assert.Equal(big.NewInt(2*(1000*102+5)), cost.Cost)
+	assert.Equal(big.NewInt(2*(1000*202+5)), cost.MaxCost)
+	assert.Nil(c.estimates)
+
+	// A transaction that depends on a previous one cannot be estimated
+	_, err = c.EstimateTxCost(context.Background(), func(cl LivepeerEthClient) (*types.Transaction, error) {
+		if _, err := cl.(*client).transact(context.Background(), dynamicTx); err != nil {
+			return nil, err
+		}
+		return cl.(*client).transact(context.Background(), func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return nil, errors.New("execution reverted")
+		})
+	})
+	assert.EqualError(err, "could not estimate transaction that depends on a previous transaction: execution reverted")
+
+	// Estimation errors are returned
+	_, err = c.EstimateTxCost(context.Background(), func(cl LivepeerEthClient) (*types.Transaction, error) {
+		return cl.(*client).transact(context.Background(), func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return nil, errors.New("execution reverted")
+		})
+	})
+	assert.EqualError(err, "execution reverted")
+
+	_, err = c.EstimateTxCost(context.Background(), func(cl LivepeerEthClient) (*types.Transaction, error) {
+		return nil, nil
+	})
+	assert.EqualError(err, "no transaction to estimate")
+}
+
+func TestTxCost(t *testing.T) {
+	assert := assert.New(t)
+
+	// The gas price of dynamic fee transactions is capped at the max fee
+	tx := types.NewTx(&types.DynamicFeeTx{Gas: 10, GasFeeCap: big.NewInt(50), GasTipCap: big.NewInt(2)})
+	cost := txCost([]*types.Transaction{tx}, big.NewInt(100))
+	assert.Equal(big.NewInt(50), cost.GasPrice)
+	assert.Equal(big.NewInt(500), cost.Cost)
+	assert.Equal(big.NewInt(500), cost.MaxCost)
+
+	// Legacy transactions pay their gas price
+	tx = types.NewTransaction(1, ethcommon.Address{}, big.NewInt(7), 10, big.NewInt(30), nil)
+	cost = txCost([]*types.Transaction{tx}, big.NewInt(100))
+	assert.Equal(uint64(10), cost.Gas)
+	assert.Equal(big.NewInt(30), cost.GasPrice)
+	assert.Equal(big.NewInt(7), cost.Value)
+	assert.Equal(big.NewInt(307), cost.Cost)
+	assert.Equal(big.NewInt(307), cost.MaxCost)
+}
@@ -1,8 +1,10 @@
 package eth
 
 import (
+	"context"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/livepeer/go-livepeer/eth/contracts"
@@ -12,58 +14,80 @@ import (
 // FundDepositAndReserve funds a sender's deposit and reserve
 // This method wraps the underlying contract method in order to set the transaction options
 // value to the sum of the provided deposit and penalty escrow amounts
-func (c *client) FundDepositAndReserve(depositAmount, reserveAmount *big.Int) (*types.Transaction, error) {
-	opts := c.transactOpts()
-	opts.Value = new(big.Int).Add(depositAmount, reserveAmount)
+func (c *client) FundDepositAndReserve(ctx context.Context, depositAmount, reserveAmount *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		opts.Value = new(big.Int).Add(depositAmount, reserveAmount)
 
-	return c.ticketBrokerSess.Contract.FundDepositAndReserve(opts, depositAmount, reserveAmount)
+		return c.bindings().ticketBrokerSess.Contract.FundDepositAndReserve(opts, depositAmount, reserveAmount)
+	})
 }
 
 // FundDeposit funds a sender's deposit
 // This method wraps the underlying contract method in order to set the transaction options
 // value to the provided deposit amount
-func (c *client) FundDeposit(amount *big.Int) (*types.Transaction, error) {
-	opts := c.transactOpts()
-	opts.Value = amount
+func (c *client) FundDeposit(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		opts.Value = amount
 
-	return c.ticketBrokerSess.Contract.FundDeposit(opts)
+		return c.bindings().ticketBrokerSess.Contract.FundDeposit(opts)
+	})
 }
 
 // FundReserve funds a sender's reserve
 // This method wraps the underlying contract method in order to set the transaction options
 // value to the provided reserve amount
-func (c *client) FundReserve(amount *big.Int) (*types.Transaction, error) {
-	opts := c.transactOpts()
-	opts.Value = amount
+func (c *client) FundReserve(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		opts.Value = amount
 
-	return c.ticketBrokerSess.Contract.FundReserve(opts)
+		return c.bindings().ticketBrokerSess.Contract.FundReserve(opts)
+	})
 }
 
 // RedeemWinningTicket submits a ticket to be validated by the broker and if a valid winning ticket
 // the broker pays the ticket's face value to the ticket's recipient
-func (c *client) RedeemWinningTicket(ticket *pm.Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error) {
+func (c *client) RedeemWinningTicket(ctx context.Context, ticket *pm.Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().ticketBrokerSess.Contract.RedeemWinningTicket(opts, contractTicket(ticket), sig, recipientRand)
+	})
+}
+
+// BatchRedeemWinningTickets submits several tickets to be validated by the broker in a single transaction
+// The broker pays the face value of each valid winning ticket to its recipient and skips the others
+func (c *client) BatchRedeemWinningTickets(ctx context.Context, tickets []*pm.SignedTicket) (*types.Transaction, error) {
+	contractTickets := make([]contracts.MTicketBrokerCoreTicket, len(tickets))
+	sigs := make([][]byte, len(tickets))
+	recipientRands := make([]*big.Int, len(tickets))
+	for i, ticket := range tickets {
+		contractTickets[i] = contractTicket(ticket.Ticket)
+		sigs[i] = ticket.Sig
+		recipientRands[i] = ticket.RecipientRand
+	}
+
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.bindings().ticketBrokerSess.Contract.BatchRedeemWinningTickets(opts, contractTickets, sigs, recipientRands)
+	})
+}
+
+// contractTicket converts a ticket to its representation in the TicketBroker contract
+func contractTicket(ticket *pm.Ticket) contracts.MTicketBrokerCoreTicket {
 	var recipientRandHash [32]byte
 	copy(recipientRandHash[:], ticket.RecipientRandHash.Bytes()[:32])
 
-	return c.ticketBrokerSess.Contract.RedeemWinningTicket(
-		c.transactOpts(),
-		contracts.MTicketBrokerCoreTicket{
-			Recipient:         ticket.Recipient,
-			Sender:            ticket.Sender,
-			FaceValue:         ticket.FaceValue,
-			WinProb:           ticket.WinProb,
-			SenderNonce:       new(big.Int).SetUint64(uint64(ticket.SenderNonce)),
-			RecipientRandHash: recipientRandHash,
-			AuxData:           ticket.AuxData(),
-		},
-		sig,
-		recipientRand,
-	)
+	return contracts.MTicketBrokerCoreTicket{
+		Recipient:         ticket.Recipient,
+		Sender:            ticket.Sender,
+		FaceValue:         ticket.FaceValue,
+		WinProb:           ticket.WinProb,
+		SenderNonce:       new(big.Int).SetUint64(uint64(ticket.SenderNonce)),
+		RecipientRandHash: recipientRandHash,
+		AuxData:           ticket.AuxData(),
+	}
 }
 
 // GetSenderInfo returns the info for a sender
-func (c *client) GetSenderInfo(addr ethcommon.Address) (*pm.SenderInfo, error) {
-	info, err := c.ticketBrokerSess.GetSenderInfo(addr)
+func (c *client) GetSenderInfo(ctx context.Context, addr ethcommon.Address) (*pm.SenderInfo, error) {
+	info, err := c.bindings().ticketBrokerSess.Contract.GetSenderInfo(c.callOpts(ctx), addr)
 	if err != nil {
 		return nil, err
 	}
@@ -81,9 +105,9 @@ func (c *client) GetSenderInfo(addr ethcommon.Address) (*pm.SenderInfo, error) {
 // IsUsedTicket checks if a ticket has been used
 // This method wraps the underlying contract method UsedTickets to allow callers to pass in
 // a ticket object
-func (c *client) IsUsedTicket(ticket *pm.Ticket) (bool, error) {
+func (c *client) IsUsedTicket(ctx context.Context, ticket *pm.Ticket) (bool, error) {
 	var ticketHash [32]byte
 	copy(ticketHash[:], ticket.Hash().Bytes()[:32])
 
-	return c.ticketBrokerSess.UsedTickets(ticketHash)
+	return c.bindings().ticketBrokerSess.Contract.UsedTickets(c.callOpts(ctx), ticketHash)
 }
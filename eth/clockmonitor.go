@@ -0,0 +1,185 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/lperrors"
+)
+
+// clockDriftSamples is the number of recent blocks that the drift of the local clock is estimated from
+const clockDriftSamples = 10
+
+// HeaderReader reads block headers, i.e. an ethclient.Client
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// ClockMonitor compares the local clock with the timestamps of new blocks and warns if the local clock drifted from
+// the chain by more than maxDrift. Blocks are only seen once they are mined, so a block observed right after it is
+// mined is late by at most the polling interval. The drift is estimated as the smallest difference between the local
+// time at which a new block was seen and its timestamp over the last blocks
+type ClockMonitor struct {
+	reader          HeaderReader
+	pollingInterval time.Duration
+	maxDrift        time.Duration
+
+	mu        sync.RWMutex
+	lastBlock *big.Int
+	samples   []time.Duration
+	drift     time.Duration
+	skewed    bool
+
+	polling bool
+	cancel  context.CancelFunc
+}
+
+// NewClockMonitor returns a ClockMonitor that polls reader for new blocks every pollingInterval
+func NewClockMonitor(reader HeaderReader, pollingInterval, maxDrift time.Duration) *ClockMonitor {
+	return &ClockMonitor{
+		reader:          reader,
+		pollingInterval: pollingInterval,
+		maxDrift:        maxDrift,
+	}
+}
+
+// Start starts polling for new blocks until ctx is done or Stop is called
+func (cm *ClockMonitor) Start(ctx context.Context) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.polling {
+		return errors.New("already polling")
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cm.cancel = cancel
+	cm.polling = true
+
+	go func() {
+		ticker := time.NewTicker(cm.pollingInterval)
+		defer ticker.Stop()
+		for {
+			if err := cm.check(cctx); err != nil {
+				glog.Errorf("Error checking clock drift err=%q", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-cctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops polling for new blocks
+func (cm *ClockMonitor) Stop() error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if !cm.polling {
+		return errors.New("not polling")
+	}
+
+	cm.cancel()
+	cm.cancel = nil
+	cm.polling = false
+
+	return nil
+}
+
+// Drift returns the estimated drift of the local clock, positive if the local clock is ahead of the chain. It is 0
+// until a new block was seen
+func (cm *ClockMonitor) Drift() time.Duration {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.drift
+}
+
+// Err returns an error if the local clock drifted from the chain by more than maxDrift
+func (cm *ClockMonitor) Err() error {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if !cm.skewed {
+		return nil
+	}
+	return lperrors.Retryable(fmt.Errorf("local clock drifted from the chain by %v, more than the max drift %v", cm.drift, cm.maxDrift))
+}
+
+func (cm *ClockMonitor) check(ctx context.Context) error {
+	head, err := cm.reader.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	cm.observe(head, time.Now())
+	return nil
+}
+
+// observe records the drift of now from the timestamp of head if head is a block that was mined since the previous
+// observation. The first block observed can be an old block, i.e. on a devnet that does not mine blocks, so it is not
+// used
+func (cm *ClockMonitor) observe(head *types.Header, now time.Time) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	lastBlock := cm.lastBlock
+	cm.lastBlock = head.Number
+	if lastBlock == nil || head.Number.Cmp(lastBlock) <= 0 {
+		return
+	}
+
+	cm.samples = append(cm.samples, now.Sub(time.Unix(int64(head.Time), 0)))
+	if len(cm.samples) > clockDriftSamples {
+		cm.samples = cm.samples[1:]
+	}
+	drift := cm.samples[0]
+	for _, s := range cm.samples[1:] {
+		if s < drift {
+			drift = s
+		}
+	}
+	cm.drift = drift
+
+	skewed := drift > cm.maxDrift || drift < -cm.maxDrift
+	if skewed && !cm.skewed {
+		glog.Errorf("Local clock drifted from the chain by %v, more than the max drift %v. Check that the clock is synchronized, i.e. with NTP", drift, cm.maxDrift)
+	} else if !skewed && cm.skewed {
+		glog.Infof("Local clock is synchronized with the chain again drift=%v", drift)
+	}
+	cm.skewed = skewed
+}
+
+type clockCheckedAccountManager struct {
+	AccountManager
+	cm *ClockMonitor
+}
+
+// NewClockCheckedAccountManager wraps am so that messages, i.e. tickets and segments, are not signed while the local
+// clock drifted from the chain by more than the max drift of cm. Transactions are still signed
+func NewClockCheckedAccountManager(am AccountManager, cm *ClockMonitor) AccountManager {
+	return &clockCheckedAccountManager{AccountManager: am, cm: cm}
+}
+
+func (am *clockCheckedAccountManager) Sign(msg []byte) ([]byte, error) {
+	if err := am.cm.Err(); err != nil {
+		return nil, err
+	}
+	return am.AccountManager.Sign(msg)
+}
+
+func (am *clockCheckedAccountManager) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	if err := am.cm.Err(); err != nil {
+		return nil, err
+	}
+	return am.AccountManager.SignTypedData(typedData)
+}
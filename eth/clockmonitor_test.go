@@ -0,0 +1,118 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubHeaderReader struct {
+	mu   sync.Mutex
+	head *types.Header
+}
+
+func (r *stubHeaderReader) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.head, nil
+}
+
+type stubSigningAccountManager struct {
+	AccountManager
+}
+
+func (am *stubSigningAccountManager) Sign(msg []byte) ([]byte, error) {
+	return []byte("sig"), nil
+}
+
+func clockHeader(number int64, t time.Time) *types.Header {
+	return &types.Header{Number: big.NewInt(number), Time: uint64(t.Unix())}
+}
+
+func TestClockMonitor_Observe(t *testing.T) {
+	assert := assert.New(t)
+
+	cm := NewClockMonitor(nil, time.Second, time.Minute)
+	now := time.Unix(time.Now().Unix(), 0)
+
+	// The first block can be old and is not used
+	cm.observe(clockHeader(1, now.Add(-time.Hour)), now)
+	assert.Equal(time.Duration(0), cm.Drift())
+	assert.Nil(cm.Err())
+
+	// Blocks that were already seen are not used
+	cm.observe(clockHeader(1, now.Add(-time.Hour)), now)
+	assert.Equal(time.Duration(0), cm.Drift())
+
+	// The drift is the smallest delay of the new blocks
+	cm.observe(clockHeader(2, now.Add(-5*time.Second)), now)
+	assert.Equal(5*time.Second, cm.Drift())
+	cm.observe(clockHeader(3, now.Add(-2*time.Second)), now)
+	cm.observe(clockHeader(4, now.Add(-4*time.Second)), now)
+	assert.Equal(2*time.Second, cm.Drift())
+	assert.Nil(cm.Err())
+
+	// The local clock is behind the chain
+	for i := int64(5); i < 5+clockDriftSamples; i++ {
+		cm.observe(clockHeader(i, now.Add(2*time.Minute)), now)
+	}
+	assert.Equal(-2*time.Minute, cm.Drift())
+	assert.EqualError(cm.Err(), "local clock drifted from the chain by -2m0s, more than the max drift 1m0s")
+
+	// The local clock is ahead of the chain
+	for i := int64(20); i < 20+clockDriftSamples; i++ {
+		cm.observe(clockHeader(i, now.Add(-2*time.Minute)), now)
+	}
+	assert.Equal(2*time.Minute, cm.Drift())
+	assert.NotNil(cm.Err())
+
+	// The local clock is synchronized again once the old samples are replaced
+	cm.observe(clockHeader(30, now), now)
+	assert.Equal(time.Duration(0), cm.Drift())
+	assert.Nil(cm.Err())
+}
+
+func TestClockMonitor_StartStop(t *testing.T) {
+	assert := assert.New(t)
+
+	reader := &stubHeaderReader{head: clockHeader(1, time.Now())}
+	cm := NewClockMonitor(reader, 10*time.Millisecond, time.Minute)
+
+	assert.EqualError(cm.Stop(), "not polling")
+	assert.Nil(cm.Start(context.Background()))
+	assert.EqualError(cm.Start(context.Background()), "already polling")
+	time.Sleep(50 * time.Millisecond)
+
+	// New blocks are polled
+	reader.mu.Lock()
+	reader.head = clockHeader(2, time.Now().Add(-2*time.Minute))
+	reader.mu.Unlock()
+	time.Sleep(100 * time.Millisecond)
+	assert.NotNil(cm.Err())
+
+	assert.Nil(cm.Stop())
+}
+
+func TestClockCheckedAccountManager(t *testing.T) {
+	assert := assert.New(t)
+
+	cm := NewClockMonitor(nil, time.Second, time.Minute)
+	am := NewClockCheckedAccountManager(&stubSigningAccountManager{}, cm)
+
+	sig, err := am.Sign([]byte("foo"))
+	assert.Nil(err)
+	assert.Equal([]byte("sig"), sig)
+
+	now := time.Now()
+	cm.observe(clockHeader(1, now), now)
+	cm.observe(clockHeader(2, now.Add(-2*time.Minute)), now)
+
+	// Messages are not signed while the clock drifted
+	_, err = am.Sign([]byte("foo"))
+	assert.Contains(err.Error(), "local clock drifted from the chain")
+}
@@ -0,0 +1,73 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/eth/contracts"
+)
+
+// contractSet holds the addresses and bindings of the contracts registered in the Controller. It is replaced as a
+// whole when the contracts are reloaded so that a call never uses a mix of the old and the new contracts
+type contractSet struct {
+	tokenAddr           common.Address
+	serviceRegistryAddr common.Address
+	bondingManagerAddr  common.Address
+	ticketBrokerAddr    common.Address
+	roundsManagerAddr   common.Address
+	minterAddr          common.Address
+	faucetAddr          common.Address
+
+	livepeerTokenSess       *contracts.LivepeerTokenSession
+	serviceRegistrySess     *contracts.ServiceRegistrySession
+	bondingManagerSess      *contracts.BondingManagerSession
+	ticketBrokerSess        *contracts.TicketBrokerSession
+	roundsManagerSess       *contracts.RoundsManagerSession
+	minterSess              *contracts.MinterSession
+	livepeerTokenFaucetSess *contracts.LivepeerTokenFaucetSession
+
+	// for L1 contracts backwards-compatibility
+	l1BondingManagerSess *contracts.L1BondingManagerSession
+}
+
+// addresses returns the addresses of the contracts by name
+func (cs *contractSet) addresses() map[string]common.Address {
+	return map[string]common.Address{
+		"LivepeerToken":       cs.tokenAddr,
+		"ServiceRegistry":     cs.serviceRegistryAddr,
+		"BondingManager":      cs.bondingManagerAddr,
+		"TicketBroker":        cs.ticketBrokerAddr,
+		"RoundsManager":       cs.roundsManagerAddr,
+		"Minter":              cs.minterAddr,
+		"LivepeerTokenFaucet": cs.faucetAddr,
+	}
+}
+
+// bindings returns the current contracts of the client. The contracts are empty until they are set by SetGasInfo
+func (c *client) bindings() *contractSet {
+	if c.bound != nil {
+		if cs, ok := c.bound.Load().(*contractSet); ok {
+			return cs
+		}
+	}
+	return &contractSet{}
+}
+
+func (c *client) ReloadContracts() error {
+	// The cached addresses are the ones being replaced so they are looked up in the Controller again
+	cs, err := c.loadContracts(nil)
+	if err != nil {
+		return err
+	}
+
+	prev := c.bindings().addresses()
+	for name, addr := range cs.addresses() {
+		if prev[name] != addr {
+			glog.Infof("Contract address changed name=%v from=%v to=%v", name, prev[name].Hex(), addr.Hex())
+		}
+	}
+
+	c.bound.Store(cs)
+	// The parameters of the new contracts may differ
+	c.ForceRefresh()
+	return c.cacheContracts(cs)
+}
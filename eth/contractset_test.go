@@ -0,0 +1,32 @@
+package eth
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindings(t *testing.T) {
+	assert := assert.New(t)
+
+	// Clients without contracts have empty bindings
+	c := &client{}
+	assert.Equal(ethcommon.Address{}, c.bindings().bondingManagerAddr)
+	c = &client{bound: &atomic.Value{}, transOpts: &bind.TransactOpts{}, transOptsMu: &sync.RWMutex{}}
+	assert.Nil(c.bindings().bondingManagerSess)
+
+	addr1 := ethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	c.bound.Store(&contractSet{bondingManagerAddr: addr1})
+	assert.Equal(addr1, c.ContractAddresses()["BondingManager"])
+
+	// Reloaded contracts are used by the clients derived from the client
+	cp := c.WithGasFees(GasFees{}).(*client)
+	addr2 := ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	c.bound.Store(&contractSet{bondingManagerAddr: addr2})
+	assert.Equal(addr2, cp.ContractAddresses()["BondingManager"])
+	assert.Equal(addr2, cp.bindings().addresses()["BondingManager"])
+}
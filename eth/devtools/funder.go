@@ -0,0 +1,172 @@
+// Package devtools helps integration tests and development environments to bootstrap accounts on private and test
+// networks
+package devtools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/eth"
+)
+
+var (
+	errNoUnlockedAccount = errors.New("no unlocked account on the ETH node to send ETH from")
+	txPollInterval       = 500 * time.Millisecond
+)
+
+// rpcCaller is the subset of the rpc.Client API used to fund accounts
+type rpcCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// Funder funds accounts with ETH and LPT on private and test networks. ETH is sent from an account that is unlocked
+// on the ETH node, i.e. the miner of a devnet. LPT is requested from the LivepeerTokenFaucet or, if LPTSource is set,
+// transferred from a pre-funded account
+type Funder struct {
+	rpc   rpcCaller
+	chain *eth.Chain
+
+	// ETHSource is the unlocked account that ETH is sent from. It defaults to the first account of the ETH node
+	ETHSource *ethcommon.Address
+	// LPTSource is the client of a pre-funded account that LPT is transferred from instead of the faucet
+	LPTSource eth.LivepeerEthClient
+}
+
+// NewFunder creates a Funder for the ETH node that rpc is connected to. It returns an error if the node is connected
+// to a chain that is not a test network
+func NewFunder(ctx context.Context, rpc rpcCaller) (*Funder, error) {
+	var chainID hexutil.Big
+	if err := rpc.CallContext(ctx, &chainID, "eth_chainId"); err != nil {
+		return nil, fmt.Errorf("error getting chain ID: %v", err)
+	}
+	chain := eth.ChainByID(chainID.ToInt())
+	if !chain.Testnet {
+		return nil, fmt.Errorf("refusing to fund accounts on %v, which is not a test network", chain.Name)
+	}
+
+	f := &Funder{rpc: rpc, chain: chain}
+	var accounts []ethcommon.Address
+	if err := rpc.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		glog.Warningf("Error getting unlocked accounts, accounts cannot be funded with ETH err=%q", err)
+	} else if len(accounts) > 0 {
+		f.ETHSource = &accounts[0]
+	}
+	return f, nil
+}
+
+// Chain returns the chain that accounts are funded on
+func (f *Funder) Chain() *eth.Chain {
+	return f.chain
+}
+
+// FundETH sends amount ETH to addr and waits for the transaction to be mined
+func (f *Funder) FundETH(ctx context.Context, addr ethcommon.Address, amount *big.Int) error {
+	if f.ETHSource == nil {
+		return errNoUnlockedAccount
+	}
+
+	var hash ethcommon.Hash
+	err := f.rpc.CallContext(ctx, &hash, "eth_sendTransaction", map[string]interface{}{
+		"from":  f.ETHSource,
+		"to":    addr,
+		"value": (*hexutil.Big)(amount),
+	})
+	if err != nil {
+		return fmt.Errorf("error sending ETH: %v", err)
+	}
+	glog.Infof("Funding account with ETH addr=%v amount=%v tx=%v", addr.Hex(), amount, hash.Hex())
+
+	return f.waitMined(ctx, hash)
+}
+
+// FundLPT makes sure that the account of client holds at least amount LPT. Missing LPT is transferred from LPTSource
+// if it is set and requested from the faucet otherwise. The faucet hands out a fixed amount, so an error is returned
+// if the account still holds less than amount after the request
+func (f *Funder) FundLPT(ctx context.Context, client eth.LivepeerEthClient, amount *big.Int) error {
+	addr := client.Account().Address
+	balance, err := client.BalanceOf(ctx, addr)
+	if err != nil {
+		return fmt.Errorf("error getting LPT balance: %v", err)
+	}
+	if balance.Cmp(amount) >= 0 {
+		return nil
+	}
+
+	var tx *types.Transaction
+	if f.LPTSource != nil {
+		missing := new(big.Int).Sub(amount, balance)
+		glog.Infof("Funding account with LPT addr=%v amount=%v from=%v", addr.Hex(), missing, f.LPTSource.Account().Address.Hex())
+		tx, err = f.LPTSource.Transfer(ctx, addr, missing)
+		if err != nil {
+			return fmt.Errorf("error transferring LPT: %v", err)
+		}
+		if err := f.LPTSource.CheckTx(ctx, tx); err != nil {
+			return fmt.Errorf("error transferring LPT: %v", err)
+		}
+		return nil
+	}
+
+	glog.Infof("Requesting LPT from the faucet addr=%v", addr.Hex())
+	tx, err = client.Request(ctx)
+	if err != nil {
+		return fmt.Errorf("error requesting LPT from the faucet: %v", err)
+	}
+	if err := client.CheckTx(ctx, tx); err != nil {
+		return fmt.Errorf("error requesting LPT from the faucet: %v", err)
+	}
+
+	balance, err = client.BalanceOf(ctx, addr)
+	if err != nil {
+		return fmt.Errorf("error getting LPT balance: %v", err)
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("LPT balance %v is less than %v after requesting LPT from the faucet", balance, amount)
+	}
+	return nil
+}
+
+// Fund funds the account of client with ETH and LPT. Either amount can be nil to skip funding it
+func (f *Funder) Fund(ctx context.Context, client eth.LivepeerEthClient, ethAmount, lptAmount *big.Int) error {
+	if ethAmount != nil {
+		if err := f.FundETH(ctx, client.Account().Address, ethAmount); err != nil {
+			return err
+		}
+	}
+	if lptAmount != nil {
+		if err := f.FundLPT(ctx, client, lptAmount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Funder) waitMined(ctx context.Context, hash ethcommon.Hash) error {
+	for {
+		// Only the status of the receipt is needed, which also works with nodes that omit other receipt fields
+		var receipt *struct {
+			Status hexutil.Uint64 `json:"status"`
+		}
+		if err := f.rpc.CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+			return fmt.Errorf("error getting receipt for tx %v: %v", hash.Hex(), err)
+		}
+		if receipt != nil {
+			if uint64(receipt.Status) != types.ReceiptStatusSuccessful {
+				return fmt.Errorf("tx %v failed", hash.Hex())
+			}
+			return nil
+		}
+
+		select {
+		case <-time.After(txPollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
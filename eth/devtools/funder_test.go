@@ -0,0 +1,172 @@
+package devtools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/livepeer/go-livepeer/eth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRPCCall struct {
+	method string
+	args   []interface{}
+}
+
+// stubRPC returns the JSON results for each method in order. The last result of a method is repeated
+type stubRPC struct {
+	results map[string][]string
+	errs    map[string]error
+	calls   []stubRPCCall
+}
+
+func (r *stubRPC) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	r.calls = append(r.calls, stubRPCCall{method, args})
+	if err := r.errs[method]; err != nil {
+		return err
+	}
+	results := r.results[method]
+	if len(results) == 0 {
+		return errors.New("method not found")
+	}
+	res := results[0]
+	if len(results) > 1 {
+		r.results[method] = results[1:]
+	}
+	return json.Unmarshal([]byte(res), result)
+}
+
+type stubTokenClient struct {
+	eth.StubClient
+	balances    map[ethcommon.Address]*big.Int
+	faucetLPT   *big.Int
+	requests    int
+	transferErr error
+}
+
+func (c *stubTokenClient) BalanceOf(ctx context.Context, addr ethcommon.Address) (*big.Int, error) {
+	if b, ok := c.balances[addr]; ok {
+		return b, nil
+	}
+	return big.NewInt(0), nil
+}
+
+func (c *stubTokenClient) Request(ctx context.Context) (*types.Transaction, error) {
+	c.requests++
+	c.balances[c.TranscoderAddress] = new(big.Int).Add(c.balances[c.TranscoderAddress], c.faucetLPT)
+	return nil, nil
+}
+
+func (c *stubTokenClient) Transfer(ctx context.Context, to ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
+	if c.transferErr != nil {
+		return nil, c.transferErr
+	}
+	c.balances[to] = amount
+	return nil, nil
+}
+
+func newStubTokenClient(addr ethcommon.Address, balance int64) *stubTokenClient {
+	return &stubTokenClient{
+		StubClient: eth.StubClient{TranscoderAddress: addr},
+		balances:   map[ethcommon.Address]*big.Int{addr: big.NewInt(balance)},
+		faucetLPT:  big.NewInt(0),
+	}
+}
+
+func TestNewFunder(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// Mainnets are refused
+	rpc := &stubRPC{results: map[string][]string{"eth_chainId": {`"0x1"`}}}
+	_, err := NewFunder(ctx, rpc)
+	assert.EqualError(err, "refusing to fund accounts on mainnet, which is not a test network")
+	rpc = &stubRPC{results: map[string][]string{"eth_chainId": {`"0xa4b1"`}}}
+	_, err = NewFunder(ctx, rpc)
+	assert.EqualError(err, "refusing to fund accounts on arbitrum-one-mainnet, which is not a test network")
+
+	rpc = &stubRPC{errs: map[string]error{"eth_chainId": errors.New("connection refused")}}
+	_, err = NewFunder(ctx, rpc)
+	assert.EqualError(err, "error getting chain ID: connection refused")
+
+	// Devnets without unlocked accounts can only be funded with LPT
+	rpc = &stubRPC{results: map[string][]string{"eth_chainId": {`"0xd431"`}, "eth_accounts": {`[]`}}}
+	f, err := NewFunder(ctx, rpc)
+	require.Nil(t, err)
+	assert.Equal("devnet", f.Chain().Name)
+	assert.Equal(errNoUnlockedAccount, f.FundETH(ctx, ethcommon.Address{}, big.NewInt(1)))
+}
+
+func TestFundETH(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	txPollInterval = 0
+
+	miner := ethcommon.HexToAddress("0x87da6a8c6e9eff15d703fc2773e32f6af8dbe301")
+	rpc := &stubRPC{results: map[string][]string{
+		"eth_chainId":               {`"0x4"`},
+		"eth_accounts":              {`["` + miner.Hex() + `"]`},
+		"eth_sendTransaction":       {`"0x0000000000000000000000000000000000000000000000000000000000000001"`},
+		"eth_getTransactionReceipt": {`null`, `null`, `{"status": "0x1"}`},
+	}}
+	f, err := NewFunder(ctx, rpc)
+	require.Nil(t, err)
+
+	addr := ethcommon.HexToAddress("0x1")
+	assert.Nil(f.FundETH(ctx, addr, big.NewInt(100)))
+	tx := rpc.calls[2]
+	assert.Equal("eth_sendTransaction", tx.method)
+	params := tx.args[0].(map[string]interface{})
+	assert.Equal(&miner, params["from"])
+	assert.Equal(addr, params["to"])
+	// The receipt is polled until the transaction is mined
+	assert.Len(rpc.calls, 6)
+
+	// Failed transactions are reported
+	rpc.results["eth_getTransactionReceipt"] = []string{`{"status": "0x0"}`}
+	assert.EqualError(f.FundETH(ctx, addr, big.NewInt(100)), "tx 0x0000000000000000000000000000000000000000000000000000000000000001 failed")
+
+	rpc.errs = map[string]error{"eth_sendTransaction": errors.New("insufficient funds")}
+	assert.EqualError(f.FundETH(ctx, addr, big.NewInt(100)), "error sending ETH: insufficient funds")
+}
+
+func TestFundLPT(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	f := &Funder{}
+	addr := ethcommon.HexToAddress("0x1")
+
+	// Accounts with enough LPT are not funded
+	client := newStubTokenClient(addr, 100)
+	client.faucetLPT = big.NewInt(1000)
+	assert.Nil(f.FundLPT(ctx, client, big.NewInt(100)))
+	assert.Equal(0, client.requests)
+
+	// LPT is requested from the faucet
+	assert.Nil(f.FundLPT(ctx, client, big.NewInt(500)))
+	assert.Equal(1, client.requests)
+	assert.Equal(big.NewInt(1100), client.balances[addr])
+
+	// The faucet does not hand out enough LPT
+	assert.EqualError(f.FundLPT(ctx, client, big.NewInt(5000)), "LPT balance 2100 is less than 5000 after requesting LPT from the faucet")
+
+	client.CheckTxErr = errors.New("reverted")
+	assert.EqualError(f.FundLPT(ctx, client, big.NewInt(5000)), "error requesting LPT from the faucet: reverted")
+
+	// The missing LPT is transferred from the pre-funded account
+	client = newStubTokenClient(addr, 100)
+	source := newStubTokenClient(ethcommon.HexToAddress("0x2"), 10000)
+	f.LPTSource = source
+	assert.Nil(f.FundLPT(ctx, client, big.NewInt(500)))
+	assert.Equal(big.NewInt(400), source.balances[addr])
+	assert.Equal(0, client.requests)
+
+	source.transferErr = errors.New("insufficient balance")
+	assert.EqualError(f.FundLPT(ctx, client, big.NewInt(500)), "error transferring LPT: insufficient balance")
+}
@@ -0,0 +1,58 @@
+package eth
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/lperrors"
+)
+
+var ErrDryRun = lperrors.User(errors.New("transaction not submitted in dry-run mode"))
+
+type dryRunAccountManager struct {
+	AccountManager
+}
+
+// NewDryRunAccountManager wraps am so that transactions are logged instead of being signed and submitted. Gas is still
+// estimated for each transaction, so transactions that would revert fail as usual. Messages are still signed with am
+func NewDryRunAccountManager(am AccountManager) AccountManager {
+	glog.Infof("Dry-run mode enabled, transactions will be logged instead of being submitted")
+
+	return &dryRunAccountManager{AccountManager: am}
+}
+
+// CreateTransactOpts creates transact opts with a signer that logs the transaction instead of signing it
+func (am *dryRunAccountManager) CreateTransactOpts(gasLimit uint64) (*bind.TransactOpts, error) {
+	opts, err := am.AccountManager.CreateTransactOpts(gasLimit)
+	if err != nil {
+		return nil, err
+	}
+	opts.Signer = am.logTx
+	return opts, nil
+}
+
+// SignTx always fails so that transactions are not replaced either
+func (am *dryRunAccountManager) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	return nil, ErrDryRun
+}
+
+func (am *dryRunAccountManager) logTx(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+	if addr != am.Account().Address {
+		return nil, bind.ErrNotAuthorized
+	}
+
+	txLog, err := newTxLog(tx)
+	if err != nil {
+		txLog.method = "unknown"
+	}
+
+	// The cost is the maximum that the transaction can cost, the actual cost depends on the gas used and the base fee
+	glog.Infof("Dry-run transaction: \"%v\". Inputs: \"%v\" Value: %v ETH Gas: %v Max fee per gas: %v GWei Max cost: %v ETH",
+		txLog.method, txLog.inputs, FromWei(tx.Value(), params.Ether), tx.Gas(), FromWei(tx.GasFeeCap(), params.GWei), FromWei(tx.Cost(), params.Ether))
+
+	return nil, ErrDryRun
+}
@@ -0,0 +1,55 @@
+package eth
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/eth/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunAccountManager(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	addr := ethcommon.HexToAddress("0x1234")
+	readOnly, err := NewReadOnlyAccountManager(addr)
+	require.Nil(err)
+	am := NewDryRunAccountManager(readOnly)
+	assert.Equal(addr, am.Account().Address)
+
+	opts, err := am.CreateTransactOpts(100)
+	require.Nil(err)
+	assert.Equal(addr, opts.From)
+	assert.Equal(uint64(100), opts.GasLimit)
+
+	bondingManagerABI, err := abi.JSON(strings.NewReader(contracts.BondingManagerABI))
+	require.Nil(err)
+	data, err := bondingManagerABI.Pack("unbond", big.NewInt(500))
+	require.Nil(err)
+	to := ethcommon.HexToAddress("0x5678")
+	tx := types.NewTx(&types.DynamicFeeTx{To: &to, Gas: 21000, GasFeeCap: big.NewInt(1000000000), Data: data})
+
+	// Transactions are logged instead of being signed
+	infoLogsBefore := glog.Stats.Info.Lines()
+	signedTx, err := opts.Signer(addr, tx)
+	assert.Nil(signedTx)
+	assert.Equal(ErrDryRun, err)
+	assert.Equal(int64(1), glog.Stats.Info.Lines()-infoLogsBefore)
+
+	_, err = opts.Signer(ethcommon.HexToAddress("0x9999"), tx)
+	assert.Equal(bind.ErrNotAuthorized, err)
+	_, err = am.SignTx(tx)
+	assert.Equal(ErrDryRun, err)
+
+	// Signing messages is left to the wrapped account manager
+	_, err = am.Sign([]byte("foo"))
+	assert.Equal(ErrReadOnly, err)
+}
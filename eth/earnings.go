@@ -0,0 +1,105 @@
+package eth
+
+import (
+	"math/big"
+
+	lpTypes "github.com/livepeer/go-livepeer/eth/types"
+)
+
+// cumulativeFactorPrecision is the precision of the cumulative reward and fee factors of the earnings pools, i.e.
+// PreciseMathUtils.percPoints(1, 1)
+var cumulativeFactorPrecision = new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+
+// earningsTranscoder is the state of a transcoder that the earnings of its delegators depend on
+type earningsTranscoder struct {
+	LastRewardRound   *big.Int
+	LastFeeRound      *big.Int
+	CumulativeRewards *big.Int
+	CumulativeFees    *big.Int
+}
+
+// pendingStakeAndFees returns the stake and fees of the delegator d after claiming its earnings up to endRound, as
+// computed by BondingManager.pendingStakeAndFees. pool returns the earnings pool of the delegate of d for a round.
+// Rounds before the LIP-36 upgrade, which were not earned with cumulative factors, are not supported
+func pendingStakeAndFees(d *lpTypes.Delegator, t *earningsTranscoder, endRound *big.Int,
+	pool func(round *big.Int) (*lpTypes.TokenPools, error)) (*big.Int, *big.Int, error) {
+
+	stake := new(big.Int).Set(d.BondedAmount)
+	fees := new(big.Int).Set(d.Fees)
+
+	if d.LastClaimRound.Cmp(endRound) < 0 {
+		startPool, err := pool(d.LastClaimRound)
+		if err != nil {
+			return nil, nil, err
+		}
+		endPool, err := latestCumulativeFactorsPool(t, endRound, pool)
+		if err != nil {
+			return nil, nil, err
+		}
+		stake, fees = delegatorCumulativeStakeAndFees(startPool, endPool, stake, fees)
+	}
+
+	// A transcoder also earns its reward cut and fee share of the rewards and fees of its delegators
+	if d.Address == d.DelegateAddress {
+		stake.Add(stake, t.CumulativeRewards)
+		fees.Add(fees, t.CumulativeFees)
+	}
+
+	return stake, fees, nil
+}
+
+// latestCumulativeFactorsPool returns the cumulative factors for round, which are the factors of the last round with
+// rewards or fees if no rewards were called or no fees were earned in round
+func latestCumulativeFactorsPool(t *earningsTranscoder, round *big.Int,
+	pool func(round *big.Int) (*lpTypes.TokenPools, error)) (*lpTypes.TokenPools, error) {
+
+	p, err := pool(round)
+	if err != nil {
+		return nil, err
+	}
+	factors := &lpTypes.TokenPools{
+		CumulativeRewardFactor: p.CumulativeRewardFactor,
+		CumulativeFeeFactor:    p.CumulativeFeeFactor,
+	}
+
+	if factors.CumulativeRewardFactor.Sign() == 0 && t.LastRewardRound.Cmp(round) < 0 {
+		p, err := pool(t.LastRewardRound)
+		if err != nil {
+			return nil, err
+		}
+		factors.CumulativeRewardFactor = p.CumulativeRewardFactor
+	}
+	if factors.CumulativeFeeFactor.Sign() == 0 && t.LastFeeRound.Cmp(round) < 0 {
+		p, err := pool(t.LastFeeRound)
+		if err != nil {
+			return nil, err
+		}
+		factors.CumulativeFeeFactor = p.CumulativeFeeFactor
+	}
+
+	return factors, nil
+}
+
+// delegatorCumulativeStakeAndFees returns the stake and fees of a delegator after earning rewards and fees with the
+// cumulative factors of startPool, the pool of its last claim round, up to the cumulative factors of endPool
+func delegatorCumulativeStakeAndFees(startPool, endPool *lpTypes.TokenPools, stake, fees *big.Int) (*big.Int, *big.Int) {
+	startRewardFactor := startPool.CumulativeRewardFactor
+	if startRewardFactor.Sign() == 0 {
+		startRewardFactor = cumulativeFactorPrecision
+	}
+	endRewardFactor := endPool.CumulativeRewardFactor
+	if endRewardFactor.Sign() == 0 {
+		endRewardFactor = cumulativeFactorPrecision
+	}
+
+	feeFactor := new(big.Int).Sub(endPool.CumulativeFeeFactor, startPool.CumulativeFeeFactor)
+	cFees := new(big.Int).Add(fees, percOf(stake, feeFactor, startRewardFactor))
+	cStake := percOf(stake, endRewardFactor, startRewardFactor)
+	return cStake, cFees
+}
+
+// percOf returns a * b / c rounded down like PreciseMathUtils.percOf
+func percOf(a, b, c *big.Int) *big.Int {
+	v := new(big.Int).Mul(a, b)
+	return v.Div(v, c)
+}
@@ -0,0 +1,96 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	lpTypes "github.com/livepeer/go-livepeer/eth/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// factor returns a cumulative factor of f/1000
+func factor(f int64) *big.Int {
+	v := new(big.Int).Mul(big.NewInt(f), cumulativeFactorPrecision)
+	return v.Div(v, big.NewInt(1000))
+}
+
+func TestPendingStakeAndFees(t *testing.T) {
+	assert := assert.New(t)
+
+	delegate := ethcommon.HexToAddress("aaa")
+	d := &lpTypes.Delegator{
+		Address:         ethcommon.HexToAddress("bbb"),
+		DelegateAddress: delegate,
+		BondedAmount:    big.NewInt(1000),
+		Fees:            big.NewInt(10),
+		LastClaimRound:  big.NewInt(5),
+	}
+	tr := &earningsTranscoder{
+		LastRewardRound:   big.NewInt(10),
+		LastFeeRound:      big.NewInt(10),
+		CumulativeRewards: big.NewInt(7),
+		CumulativeFees:    big.NewInt(3),
+	}
+	pools := map[int64]*lpTypes.TokenPools{
+		5:  {CumulativeRewardFactor: factor(1000), CumulativeFeeFactor: factor(1)},
+		8:  {CumulativeRewardFactor: factor(1050), CumulativeFeeFactor: factor(2)},
+		9:  {CumulativeRewardFactor: big.NewInt(0), CumulativeFeeFactor: factor(2)},
+		10: {CumulativeRewardFactor: factor(1100), CumulativeFeeFactor: factor(3)},
+		11: {CumulativeRewardFactor: big.NewInt(0), CumulativeFeeFactor: big.NewInt(0)},
+	}
+	var requested []int64
+	pool := func(round *big.Int) (*lpTypes.TokenPools, error) {
+		requested = append(requested, round.Int64())
+		return pools[round.Int64()], nil
+	}
+
+	// Rewards and fees are earned with the cumulative factors of the last claim round and the end round
+	stake, fees, err := pendingStakeAndFees(d, tr, big.NewInt(10), pool)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(1100), stake)
+	assert.Equal(big.NewInt(12), fees)
+	assert.Equal([]int64{5, 10}, requested)
+
+	// The factors of the last reward and fee rounds are used for rounds without rewards or fees
+	requested = nil
+	tr.LastRewardRound = big.NewInt(8)
+	tr.LastFeeRound = big.NewInt(9)
+	stake, fees, err = pendingStakeAndFees(d, tr, big.NewInt(11), pool)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(1050), stake)
+	assert.Equal(big.NewInt(11), fees)
+	assert.Equal([]int64{5, 11, 8, 9}, requested)
+
+	// Delegators that claimed in the end round have no unclaimed earnings
+	requested = nil
+	d.LastClaimRound = big.NewInt(10)
+	stake, fees, err = pendingStakeAndFees(d, tr, big.NewInt(10), pool)
+	assert.Nil(err)
+	assert.Equal(d.BondedAmount, stake)
+	assert.Equal(d.Fees, fees)
+	assert.Empty(requested)
+	// The stake and fees of the delegator are not changed
+	stake.Add(stake, big.NewInt(1))
+	assert.Equal(big.NewInt(1000), d.BondedAmount)
+
+	// A missing reward factor in the last claim round counts as 1
+	d.LastClaimRound = big.NewInt(9)
+	stake, fees, err = pendingStakeAndFees(d, tr, big.NewInt(10), pool)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(1100), stake)
+	assert.Equal(big.NewInt(11), fees)
+
+	// Transcoders also earn their cumulative rewards and fees
+	d.Address = delegate
+	stake, fees, err = pendingStakeAndFees(d, tr, big.NewInt(10), pool)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(1107), stake)
+	assert.Equal(big.NewInt(14), fees)
+
+	// Errors are returned
+	expErr := errors.New("GetTranscoderEarningsPoolForRound error")
+	_, _, err = pendingStakeAndFees(d, tr, big.NewInt(10), func(*big.Int) (*lpTypes.TokenPools, error) { return nil, expErr })
+	assert.Equal(expErr, err)
+}
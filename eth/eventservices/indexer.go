@@ -0,0 +1,341 @@
+package eventservices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
+	"github.com/livepeer/go-livepeer/eth/contracts"
+)
+
+const (
+	// defaultChunkSize is the number of blocks for which logs are requested at once when indexing events
+	defaultChunkSize = 10000
+	// indexedBlocksRetention is the number of indexed blocks that are kept to detect reorgs
+	indexedBlocksRetention = 128
+)
+
+// indexedContracts maps the names of the contracts whose events are indexed to their ABI and the indexed events.
+// NewJob was emitted by the JobsManager contract which is not part of the current protocol, so it is not indexed
+var indexedContracts = map[string]struct {
+	abi    string
+	events []string
+}{
+	"BondingManager": {abi: contracts.BondingManagerABI, events: []string{"Bond", "Reward"}},
+	"LivepeerToken":  {abi: contracts.LivepeerTokenABI, events: []string{"Transfer"}},
+}
+
+type eventStore interface {
+	InsertContractEvent(event *common.DBContractEvent) error
+	DeleteContractEvents(fromBlock *big.Int) error
+	InsertIndexedBlock(header *blockwatch.MiniHeader) error
+	LatestIndexedBlock() (*blockwatch.MiniHeader, error)
+	DeleteIndexedBlocks(fromBlock *big.Int) error
+	PruneIndexedBlocks(keep int) error
+}
+
+type indexedContract struct {
+	name   string
+	events map[ethcommon.Hash]abi.Event
+}
+
+// EventIndexer indexes the historical and new events of the protocol contracts into a store so that they can be
+// queried without re-scanning the chain. The progress of the indexer is persisted, so indexing resumes from the
+// last indexed block after a restart. Events of blocks that are reorged are removed from the store and indexed again
+type EventIndexer struct {
+	client     blockwatch.Client
+	store      eventStore
+	mu         sync.RWMutex
+	contracts  map[ethcommon.Address]*indexedContract
+	topics     []ethcommon.Hash
+	startBlock *big.Int
+
+	ChunkSize       uint64
+	PollingInterval time.Duration
+
+	quit chan struct{}
+}
+
+// NewEventIndexer creates an EventIndexer for the contracts in addrMap which indexes events starting at startBlock.
+// startBlock is only used if no blocks have been indexed yet
+func NewEventIndexer(client blockwatch.Client, store eventStore, addrMap map[string]ethcommon.Address, startBlock *big.Int, pollingInterval time.Duration) (*EventIndexer, error) {
+	idx := &EventIndexer{
+		client:          client,
+		store:           store,
+		contracts:       make(map[ethcommon.Address]*indexedContract),
+		startBlock:      startBlock,
+		ChunkSize:       defaultChunkSize,
+		PollingInterval: pollingInterval,
+		quit:            make(chan struct{}),
+	}
+
+	for name, c := range indexedContracts {
+		addr, ok := addrMap[name]
+		if !ok {
+			return nil, fmt.Errorf("missing address for contract %v", name)
+		}
+
+		contractABI, err := abi.JSON(strings.NewReader(c.abi))
+		if err != nil {
+			return nil, err
+		}
+
+		ic := &indexedContract{name: name, events: make(map[ethcommon.Hash]abi.Event)}
+		for _, eventName := range c.events {
+			event, ok := contractABI.Events[eventName]
+			if !ok {
+				return nil, fmt.Errorf("unknown event %v for contract %v", eventName, name)
+			}
+			ic.events[event.ID] = event
+			idx.topics = append(idx.topics, event.ID)
+		}
+		idx.contracts[addr] = ic
+	}
+
+	return idx, nil
+}
+
+// Watch indexes new events every PollingInterval until Stop is called
+func (idx *EventIndexer) Watch() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-idx.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(idx.PollingInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := idx.Index(ctx); err != nil && ctx.Err() == nil {
+			glog.Errorf("Error indexing contract events err=%q", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Stop signals the indexer loop to exit gracefully
+func (idx *EventIndexer) Stop() {
+	close(idx.quit)
+}
+
+// SetContractAddresses makes the indexer index the events of the contracts in addrs as well, i.e. after the contracts
+// were upgraded. The events of the previous addresses are still indexed so that the events of blocks before the
+// upgrade are indexed if the indexer is behind
+func (idx *EventIndexer) SetContractAddresses(addrs map[string]ethcommon.Address) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, c := range idx.contracts {
+		addr, ok := addrs[c.name]
+		if !ok || addr == (ethcommon.Address{}) {
+			continue
+		}
+		if _, ok := idx.contracts[addr]; !ok {
+			idx.contracts[addr] = c
+		}
+	}
+}
+
+// Index indexes the events emitted since the last indexed block up to the latest block
+func (idx *EventIndexer) Index(ctx context.Context) error {
+	head, err := idx.client.HeaderByNumber(nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the latest block: %v", err)
+	}
+
+	from, err := idx.resumeBlock()
+	if err != nil {
+		return err
+	}
+	if from.Cmp(head.Number) > 0 {
+		return nil
+	}
+
+	// Remove the events of reorged blocks and the events of a chunk that was only partially indexed
+	if err := idx.store.DeleteContractEvents(from); err != nil {
+		return err
+	}
+
+	chunkSize := new(big.Int).SetUint64(idx.ChunkSize)
+	for start := from; start.Cmp(head.Number) <= 0; start = new(big.Int).Add(start, chunkSize) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := new(big.Int).Add(start, chunkSize)
+		end.Sub(end, big.NewInt(1))
+		if end.Cmp(head.Number) > 0 {
+			end.Set(head.Number)
+		}
+
+		if err := idx.indexChunk(start, end); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resumeBlock returns the block to continue indexing from. Indexed blocks that are no longer part of the chain are
+// deleted until an indexed block that is part of the chain is found
+func (idx *EventIndexer) resumeBlock() (*big.Int, error) {
+	for {
+		latest, err := idx.store.LatestIndexedBlock()
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil {
+			return idx.startBlock, nil
+		}
+
+		header, err := idx.client.HeaderByNumber(latest.Number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block %v: %v", latest.Number, err)
+		}
+		if header.Hash == latest.Hash {
+			return new(big.Int).Add(latest.Number, big.NewInt(1)), nil
+		}
+
+		glog.Warningf("Detected reorg of indexed block number=%v hash=%v newHash=%v", latest.Number, latest.Hash.Hex(), header.Hash.Hex())
+
+		if err := idx.store.DeleteIndexedBlocks(latest.Number); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (idx *EventIndexer) indexChunk(start, end *big.Int) error {
+	// Fetch the header before the logs so that the logs are never older than the recorded block hash
+	header, err := idx.client.HeaderByNumber(end)
+	if err != nil {
+		return fmt.Errorf("failed to get block %v: %v", end, err)
+	}
+
+	idx.mu.RLock()
+	addrs := make([]ethcommon.Address, 0, len(idx.contracts))
+	for addr := range idx.contracts {
+		addrs = append(addrs, addr)
+	}
+	idx.mu.RUnlock()
+
+	logs, err := idx.client.FilterLogs(ethereum.FilterQuery{
+		FromBlock: start,
+		ToBlock:   end,
+		Addresses: addrs,
+		Topics:    [][]ethcommon.Hash{idx.topics},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch logs for blocks %v-%v: %v", start, end, err)
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	indexed := 0
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+
+		event, err := idx.decodeLog(log)
+		if err != nil {
+			return fmt.Errorf("failed to decode log block=%v tx=%v index=%v: %v", log.BlockNumber, log.TxHash.Hex(), log.Index, err)
+		}
+		if event == nil {
+			continue
+		}
+
+		if err := idx.store.InsertContractEvent(event); err != nil {
+			return err
+		}
+		indexed++
+	}
+
+	if err := idx.store.InsertIndexedBlock(header); err != nil {
+		return err
+	}
+	if err := idx.store.PruneIndexedBlocks(indexedBlocksRetention); err != nil {
+		return err
+	}
+
+	glog.V(common.DEBUG).Infof("Indexed contract events fromBlock=%v toBlock=%v events=%v", start, end, indexed)
+
+	return nil
+}
+
+// decodeLog decodes a log into a contract event, nil if the log is not an indexed event
+func (idx *EventIndexer) decodeLog(log types.Log) (*common.DBContractEvent, error) {
+	idx.mu.RLock()
+	c, ok := idx.contracts[log.Address]
+	idx.mu.RUnlock()
+	if !ok || len(log.Topics) == 0 {
+		return nil, nil
+	}
+	event, ok := c.events[log.Topics[0]]
+	if !ok {
+		return nil, nil
+	}
+
+	args := make(map[string]interface{})
+	if err := event.Inputs.UnpackIntoMap(args, log.Data); err != nil {
+		return nil, err
+	}
+
+	var indexedArgs abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexedArgs = append(indexedArgs, arg)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexedArgs, log.Topics[1:]); err != nil {
+		return nil, err
+	}
+
+	var addrs []ethcommon.Address
+	for _, arg := range indexedArgs {
+		if addr, ok := args[arg.Name].(ethcommon.Address); ok {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.DBContractEvent{
+		BlockNumber: log.BlockNumber,
+		BlockHash:   log.BlockHash,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Contract:    log.Address,
+		Name:        event.Name,
+		Addresses:   addrs,
+		Data:        string(data),
+	}, nil
+}
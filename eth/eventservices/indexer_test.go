@@ -0,0 +1,308 @@
+package eventservices
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
+	"github.com/livepeer/go-livepeer/eth/contracts"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	stubBondingManagerAddr = pm.RandAddress()
+	stubTokenAddr          = pm.RandAddress()
+	stubAddrMap            = map[string]ethcommon.Address{"BondingManager": stubBondingManagerAddr, "LivepeerToken": stubTokenAddr}
+)
+
+type stubChainClient struct {
+	head    uint64
+	forks   map[uint64]byte
+	logs    []types.Log
+	queries []ethereum.FilterQuery
+	err     error
+}
+
+func (c *stubChainClient) blockHash(number uint64) ethcommon.Hash {
+	return ethcommon.BytesToHash([]byte{c.forks[number], byte(number)})
+}
+
+func (c *stubChainClient) HeaderByNumber(number *big.Int) (*blockwatch.MiniHeader, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	n := c.head
+	if number != nil {
+		n = number.Uint64()
+	}
+	return &blockwatch.MiniHeader{Number: new(big.Int).SetUint64(n), Hash: c.blockHash(n)}, nil
+}
+
+func (c *stubChainClient) HeaderByHash(hash ethcommon.Hash) (*blockwatch.MiniHeader, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *stubChainClient) FilterLogs(q ethereum.FilterQuery) ([]types.Log, error) {
+	c.queries = append(c.queries, q)
+
+	var logs []types.Log
+	// Return logs in reverse order to check that the indexer sorts them
+	for i := len(c.logs) - 1; i >= 0; i-- {
+		l := c.logs[i]
+		if l.BlockNumber < q.FromBlock.Uint64() || l.BlockNumber > q.ToBlock.Uint64() || l.BlockHash != c.blockHash(l.BlockNumber) {
+			continue
+		}
+		for _, addr := range q.Addresses {
+			if l.Address == addr {
+				logs = append(logs, l)
+				break
+			}
+		}
+	}
+	return logs, nil
+}
+
+// newLog returns a log of an event in block that belongs to the fork of the block
+func (c *stubChainClient) newLog(t *testing.T, contractAddr ethcommon.Address, abiJSON, eventName string, block uint64, index uint, args ...interface{}) types.Log {
+	contractABI, err := abi.JSON(strings.NewReader(abiJSON))
+	require.Nil(t, err)
+	event := contractABI.Events[eventName]
+
+	topics := []ethcommon.Hash{event.ID}
+	var nonIndexed []interface{}
+	for i, arg := range event.Inputs {
+		if arg.Indexed {
+			topics = append(topics, ethcommon.BytesToHash(args[i].(ethcommon.Address).Bytes()))
+		} else {
+			nonIndexed = append(nonIndexed, args[i])
+		}
+	}
+	data, err := event.Inputs.NonIndexed().Pack(nonIndexed...)
+	require.Nil(t, err)
+
+	return types.Log{
+		Address:     contractAddr,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: block,
+		BlockHash:   c.blockHash(block),
+		TxHash:      ethcommon.BytesToHash([]byte{c.forks[block], byte(block), byte(index)}),
+		Index:       index,
+	}
+}
+
+func TestNewEventIndexer(t *testing.T) {
+	_, err := NewEventIndexer(&stubChainClient{}, nil, map[string]ethcommon.Address{"BondingManager": stubBondingManagerAddr}, big.NewInt(0), time.Second)
+	assert.EqualError(t, err, "missing address for contract LivepeerToken")
+
+	idx, err := NewEventIndexer(&stubChainClient{}, nil, stubAddrMap, big.NewInt(0), time.Second)
+	require.Nil(t, err)
+	assert.Len(t, idx.contracts, 2)
+	assert.Len(t, idx.topics, 3)
+}
+
+func TestEventIndexer_Index(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	delegator, orch, oldOrch := pm.RandAddress(), pm.RandAddress(), pm.RandAddress()
+	client := &stubChainClient{head: 5, forks: make(map[uint64]byte)}
+	client.logs = []types.Log{
+		// Before the start block
+		client.newLog(t, stubTokenAddr, contracts.LivepeerTokenABI, "Transfer", 0, 0, delegator, orch, big.NewInt(1)),
+		client.newLog(t, stubTokenAddr, contracts.LivepeerTokenABI, "Transfer", 1, 1, orch, delegator, big.NewInt(100)),
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "Bond", 1, 0, orch, oldOrch, delegator, big.NewInt(10), big.NewInt(50)),
+		// Not an indexed event
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "TranscoderUpdate", 3, 0, orch, big.NewInt(1), big.NewInt(2)),
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "Reward", 4, 0, orch, big.NewInt(7)),
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "Reward", 5, 0, orch, big.NewInt(8)),
+	}
+	// Not an indexed contract
+	client.logs = append(client.logs, client.newLog(t, pm.RandAddress(), contracts.LivepeerTokenABI, "Transfer", 2, 0, orch, delegator, big.NewInt(1)))
+
+	idx, err := NewEventIndexer(client, dbh, stubAddrMap, big.NewInt(1), time.Second)
+	require.Nil(err)
+	idx.ChunkSize = 2
+
+	require.Nil(idx.Index(context.Background()))
+	require.Len(client.queries, 3)
+	assert.Equal(big.NewInt(1), client.queries[0].FromBlock)
+	assert.Equal(big.NewInt(5), client.queries[2].ToBlock)
+
+	events, err := dbh.ContractEvents(nil)
+	require.Nil(err)
+	require.Len(events, 4)
+	assert.Equal("Bond", events[0].Name)
+	assert.Equal(stubBondingManagerAddr, events[0].Contract)
+	assert.Equal([]ethcommon.Address{orch, oldOrch, delegator}, events[0].Addresses)
+	var bond map[string]interface{}
+	require.Nil(json.Unmarshal([]byte(events[0].Data), &bond))
+	assert.Equal(float64(50), bond["bondedAmount"])
+	assert.Equal("Transfer", events[1].Name)
+	assert.Equal([]ethcommon.Address{orch, delegator}, events[1].Addresses)
+	assert.Equal(uint(1), events[1].LogIndex)
+	assert.Equal("Reward", events[2].Name)
+	assert.Equal(uint64(4), events[2].BlockNumber)
+
+	latest, err := dbh.LatestIndexedBlock()
+	require.Nil(err)
+	assert.Equal(big.NewInt(5), latest.Number)
+	assert.Equal(client.blockHash(5), latest.Hash)
+
+	// Indexing resumes after the last indexed block
+	client.queries = nil
+	require.Nil(idx.Index(context.Background()))
+	assert.Len(client.queries, 0)
+
+	client.head = 6
+	client.logs = append(client.logs, client.newLog(t, stubTokenAddr, contracts.LivepeerTokenABI, "Transfer", 6, 0, delegator, orch, big.NewInt(3)))
+	idx, err = NewEventIndexer(client, dbh, stubAddrMap, big.NewInt(1), time.Second)
+	require.Nil(err)
+	require.Nil(idx.Index(context.Background()))
+	require.Len(client.queries, 1)
+	assert.Equal(big.NewInt(6), client.queries[0].FromBlock)
+	events, err = dbh.ContractEvents(nil)
+	require.Nil(err)
+	assert.Len(events, 5)
+
+	// Queries
+	events, err = dbh.ContractEvents(&common.DBContractEventFilter{Name: "Reward"})
+	require.Nil(err)
+	assert.Len(events, 2)
+	events, err = dbh.ContractEvents(&common.DBContractEventFilter{Address: &oldOrch})
+	require.Nil(err)
+	assert.Len(events, 1)
+	events, err = dbh.ContractEvents(&common.DBContractEventFilter{Name: "Transfer", Address: &delegator, FromBlock: big.NewInt(2), ToBlock: big.NewInt(6)})
+	require.Nil(err)
+	require.Len(events, 1)
+	assert.Equal(uint64(6), events[0].BlockNumber)
+
+	// Error fetching the latest block
+	client.err = errors.New("boom")
+	assert.EqualError(idx.Index(context.Background()), "failed to get the latest block: boom")
+}
+
+func TestEventIndexer_SetContractAddresses(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	orch := pm.RandAddress()
+	upgraded := pm.RandAddress()
+	client := &stubChainClient{head: 2, forks: make(map[uint64]byte)}
+	client.logs = []types.Log{
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "Reward", 1, 0, orch, big.NewInt(7)),
+		client.newLog(t, upgraded, contracts.BondingManagerABI, "Reward", 2, 0, orch, big.NewInt(8)),
+	}
+
+	idx, err := NewEventIndexer(client, dbh, stubAddrMap, big.NewInt(0), time.Second)
+	require.Nil(err)
+
+	// The events of both the previous and the upgraded contract are indexed
+	idx.SetContractAddresses(map[string]ethcommon.Address{"BondingManager": upgraded, "LivepeerToken": stubTokenAddr})
+	assert.Len(idx.contracts, 3)
+	require.Nil(idx.Index(context.Background()))
+
+	events, err := dbh.ContractEvents(&common.DBContractEventFilter{Name: "Reward"})
+	require.Nil(err)
+	require.Len(events, 2)
+	assert.Equal(stubBondingManagerAddr, events[0].Contract)
+	assert.Equal(upgraded, events[1].Contract)
+}
+
+func TestEventIndexer_Reorg(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	orch := pm.RandAddress()
+	client := &stubChainClient{head: 6, forks: make(map[uint64]byte)}
+	client.logs = []types.Log{
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "Reward", 2, 0, orch, big.NewInt(1)),
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "Reward", 5, 0, orch, big.NewInt(2)),
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "Reward", 6, 0, orch, big.NewInt(3)),
+	}
+
+	idx, err := NewEventIndexer(client, dbh, stubAddrMap, big.NewInt(0), time.Second)
+	require.Nil(err)
+	idx.ChunkSize = 3
+	require.Nil(idx.Index(context.Background()))
+	events, err := dbh.ContractEvents(nil)
+	require.Nil(err)
+	require.Len(events, 3)
+
+	// Blocks 5 and 6 are reorged and the Reward event of block 6 is mined in block 7 instead
+	client.head = 7
+	client.forks[5], client.forks[6] = 1, 1
+	client.logs = append(client.logs,
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "Reward", 5, 0, orch, big.NewInt(2)),
+		client.newLog(t, stubBondingManagerAddr, contracts.BondingManagerABI, "Reward", 7, 0, orch, big.NewInt(3)),
+	)
+
+	client.queries = nil
+	require.Nil(idx.Index(context.Background()))
+	// Indexing resumes after block 2, the last indexed block that was not reorged
+	require.Len(client.queries, 2)
+	assert.Equal(big.NewInt(3), client.queries[0].FromBlock)
+
+	events, err = dbh.ContractEvents(nil)
+	require.Nil(err)
+	require.Len(events, 3)
+	assert.Equal(uint64(2), events[0].BlockNumber)
+	assert.Equal(uint64(5), events[1].BlockNumber)
+	assert.Equal(client.blockHash(5), events[1].BlockHash)
+	assert.Equal(uint64(7), events[2].BlockNumber)
+
+	latest, err := dbh.LatestIndexedBlock()
+	require.Nil(err)
+	assert.Equal(big.NewInt(7), latest.Number)
+}
+
+func TestEventIndexer_Watch(t *testing.T) {
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(t, err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	client := &stubChainClient{head: 3, forks: make(map[uint64]byte)}
+	idx, err := NewEventIndexer(client, dbh, stubAddrMap, big.NewInt(0), 10*time.Millisecond)
+	require.Nil(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		idx.Watch()
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	idx.Stop()
+	<-done
+
+	latest, err := dbh.LatestIndexedBlock()
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(3), latest.Number)
+}
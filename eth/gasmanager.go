@@ -0,0 +1,78 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// GasFees override the fees of a transaction. A nil fee is set by the GasManager
+type GasFees struct {
+	// MaxFee is the max fee per gas of dynamic fee transactions and the gas price of legacy transactions
+	MaxFee *big.Int
+	// MaxPriorityFee is the max priority fee per gas of dynamic fee transactions
+	MaxPriorityFee *big.Int
+}
+
+// GasManager sets the fees of transactions. Once the London hard fork is active transactions are sent as dynamic fee
+// (type 2) transactions with the base fee of the latest block and the priority fee suggested by the backend
+type GasManager struct {
+	backend Backend
+	// noPriorityFee is set for chains that do not pay priority fees, i.e. Arbitrum. Transactions are sent without a
+	// priority fee instead of the suggested one
+	noPriorityFee bool
+}
+
+// NewGasManager returns a GasManager that queries fees from backend
+func NewGasManager(backend Backend) *GasManager {
+	return &GasManager{backend: backend}
+}
+
+// SetFees sets the fees of opts. The fees in overrides take precedence over the suggested fees. If a max fee per gas
+// is already set in opts, i.e. the max gas price of the node, it caps the suggested max fee per gas
+func (gm *GasManager) SetFees(ctx context.Context, opts *bind.TransactOpts, overrides GasFees) error {
+	head, err := gm.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if head.BaseFee == nil {
+		// legacy tx, not London ready
+		if overrides.MaxFee != nil {
+			opts.GasPrice = overrides.MaxFee
+		}
+		return nil
+	}
+
+	tip := overrides.MaxPriorityFee
+	if tip == nil && gm.noPriorityFee {
+		tip = big.NewInt(0)
+	}
+	if tip == nil {
+		tip, err = gm.backend.SuggestGasTipCap(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	feeCap := overrides.MaxFee
+	if feeCap == nil {
+		// Leave room for the base fee to double before the tx is included
+		feeCap = new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+		if opts.GasFeeCap != nil && feeCap.Cmp(opts.GasFeeCap) > 0 {
+			feeCap = opts.GasFeeCap
+		}
+	}
+
+	if tip.Cmp(feeCap) > 0 {
+		return fmt.Errorf("max priority fee per gas higher than max fee per gas maxPriorityFee=%v maxFee=%v", tip, feeCap)
+	}
+
+	opts.GasPrice = nil
+	opts.GasFeeCap = feeCap
+	opts.GasTipCap = tip
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubGasBackend struct {
+	Backend
+	head    *types.Header
+	headErr error
+	tip     *big.Int
+	tipErr  error
+}
+
+func (b *stubGasBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return b.head, b.headErr
+}
+
+func (b *stubGasBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return b.tip, b.tipErr
+}
+
+func TestGasManager_SetFees_Legacy(t *testing.T) {
+	assert := assert.New(t)
+
+	backend := &stubGasBackend{head: &types.Header{}}
+	gm := NewGasManager(backend)
+
+	// Gas price is left to the backend
+	opts := &bind.TransactOpts{}
+	assert.Nil(gm.SetFees(context.Background(), opts, GasFees{}))
+	assert.Nil(opts.GasPrice)
+	assert.Nil(opts.GasFeeCap)
+	assert.Nil(opts.GasTipCap)
+
+	// Max fee overrides the gas price
+	opts = &bind.TransactOpts{GasPrice: big.NewInt(10)}
+	assert.Nil(gm.SetFees(context.Background(), opts, GasFees{MaxFee: big.NewInt(5), MaxPriorityFee: big.NewInt(1)}))
+	assert.Equal(big.NewInt(5), opts.GasPrice)
+	assert.Nil(opts.GasFeeCap)
+	assert.Nil(opts.GasTipCap)
+}
+
+func TestGasManager_SetFees_DynamicFee(t *testing.T) {
+	assert := assert.New(t)
+
+	backend := &stubGasBackend{head: &types.Header{BaseFee: big.NewInt(100)}, tip: big.NewInt(2)}
+	gm := NewGasManager(backend)
+
+	// max fee = 2 * base fee + priority fee
+	opts := &bind.TransactOpts{}
+	assert.Nil(gm.SetFees(context.Background(), opts, GasFees{}))
+	assert.Nil(opts.GasPrice)
+	assert.Equal(big.NewInt(202), opts.GasFeeCap)
+	assert.Equal(big.NewInt(2), opts.GasTipCap)
+
+	// The max gas price caps the max fee
+	opts = &bind.TransactOpts{GasFeeCap: big.NewInt(150)}
+	assert.Nil(gm.SetFees(context.Background(), opts, GasFees{}))
+	assert.Equal(big.NewInt(150), opts.GasFeeCap)
+	assert.Equal(big.NewInt(2), opts.GasTipCap)
+
+	// Overrides take precedence
+	opts = &bind.TransactOpts{GasFeeCap: big.NewInt(150)}
+	assert.Nil(gm.SetFees(context.Background(), opts, GasFees{MaxFee: big.NewInt(300), MaxPriorityFee: big.NewInt(5)}))
+	assert.Equal(big.NewInt(300), opts.GasFeeCap)
+	assert.Equal(big.NewInt(5), opts.GasTipCap)
+
+	opts = &bind.TransactOpts{}
+	assert.Nil(gm.SetFees(context.Background(), opts, GasFees{MaxPriorityFee: big.NewInt(5)}))
+	assert.Equal(big.NewInt(205), opts.GasFeeCap)
+	assert.Equal(big.NewInt(5), opts.GasTipCap)
+
+	// Priority fee higher than max fee
+	opts = &bind.TransactOpts{}
+	err := gm.SetFees(context.Background(), opts, GasFees{MaxFee: big.NewInt(1)})
+	assert.EqualError(err, "max priority fee per gas higher than max fee per gas maxPriorityFee=2 maxFee=1")
+	assert.Nil(opts.GasFeeCap)
+}
+
+func TestGasManager_SetFees_NoPriorityFee(t *testing.T) {
+	assert := assert.New(t)
+
+	backend := &stubGasBackend{head: &types.Header{BaseFee: big.NewInt(100)}, tipErr: errors.New("not called")}
+	gm := NewGasManager(backend)
+	gm.noPriorityFee = true
+
+	// max fee = 2 * base fee without a priority fee
+	opts := &bind.TransactOpts{}
+	assert.Nil(gm.SetFees(context.Background(), opts, GasFees{}))
+	assert.Equal(big.NewInt(200), opts.GasFeeCap)
+	assert.Equal(big.NewInt(0), opts.GasTipCap)
+
+	// A priority fee can still be set explicitly
+	opts = &bind.TransactOpts{}
+	assert.Nil(gm.SetFees(context.Background(), opts, GasFees{MaxPriorityFee: big.NewInt(3)}))
+	assert.Equal(big.NewInt(203), opts.GasFeeCap)
+	assert.Equal(big.NewInt(3), opts.GasTipCap)
+}
+
+func TestGasManager_SetFees_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	backend := &stubGasBackend{headErr: errors.New("head error")}
+	gm := NewGasManager(backend)
+	assert.EqualError(gm.SetFees(context.Background(), &bind.TransactOpts{}, GasFees{}), "head error")
+
+	backend.head = &types.Header{BaseFee: big.NewInt(100)}
+	backend.headErr = nil
+	backend.tipErr = errors.New("tip error")
+	assert.EqualError(gm.SetFees(context.Background(), &bind.TransactOpts{}, GasFees{}), "tip error")
+
+	// The priority fee is not queried if it is overridden
+	assert.Nil(gm.SetFees(context.Background(), &bind.TransactOpts{}, GasFees{MaxPriorityFee: big.NewInt(1)}))
+}
@@ -0,0 +1,27 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+)
+
+// LightClientConfig configures an embedded light client
+type LightClientConfig struct {
+	DataDir    string
+	ChainID    *big.Int
+	MaxPeers   int
+	ListenAddr string
+}
+
+// LightClient is an Ethereum light client running in-process. It exposes its JSON-RPC API on a local
+// endpoint so it can be used in place of an external ETH node URL
+type LightClient interface {
+	// Start starts the light client and its P2P networking
+	Start() error
+	// Stop stops the light client
+	Stop() error
+	// URL returns the endpoint of the light client that can be dialed with ethclient.Dial
+	URL() string
+	// WaitForSync blocks until the light client has connected to a peer and synced headers to the head of the chain
+	WaitForSync(ctx context.Context) error
+}
@@ -0,0 +1,11 @@
+//go:build !lightclient
+// +build !lightclient
+
+package eth
+
+import "errors"
+
+// NewLightClient returns an error because the embedded light client is only available in builds with the lightclient tag
+func NewLightClient(cfg LightClientConfig) (LightClient, error) {
+	return nil, errors.New("the embedded light client is not available in this build, rebuild with -tags lightclient")
+}
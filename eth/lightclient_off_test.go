@@ -0,0 +1,19 @@
+//go:build !lightclient
+// +build !lightclient
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLightClient_Unavailable(t *testing.T) {
+	assert := assert.New(t)
+
+	lc, err := NewLightClient(LightClientConfig{ChainID: big.NewInt(1)})
+	assert.Nil(lc)
+	assert.EqualError(err, "the embedded light client is not available in this build, rebuild with -tags lightclient")
+}
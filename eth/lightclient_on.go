@@ -0,0 +1,159 @@
+//go:build lightclient
+// +build lightclient
+
+package eth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/les"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/golang/glog"
+)
+
+var lightClientSyncPollInterval = 5 * time.Second
+
+type lightClientNetwork struct {
+	genesis   func() *core.Genesis
+	bootnodes []string
+}
+
+// lightClientNetworks are the networks supported by the embedded light client keyed by chain ID.
+// Light clients are served by L1 nodes only, so L2 networks are not supported
+var lightClientNetworks = map[int64]lightClientNetwork{
+	1: {genesis: core.DefaultGenesisBlock, bootnodes: params.MainnetBootnodes},
+	4: {genesis: core.DefaultRinkebyGenesisBlock, bootnodes: params.RinkebyBootnodes},
+	5: {genesis: core.DefaultGoerliGenesisBlock, bootnodes: params.GoerliBootnodes},
+}
+
+type lesLightClient struct {
+	stack *node.Node
+}
+
+// NewLightClient creates an embedded LES light client for the network with the configured chain ID
+func NewLightClient(cfg LightClientConfig) (LightClient, error) {
+	if cfg.ChainID == nil || !cfg.ChainID.IsInt64() {
+		return nil, fmt.Errorf("a chain ID is required for the embedded light client")
+	}
+
+	network, ok := lightClientNetworks[cfg.ChainID.Int64()]
+	if !ok {
+		return nil, fmt.Errorf("the embedded light client does not support chainID %v", cfg.ChainID)
+	}
+
+	bootnodes, err := parseBootnodes(network.bootnodes)
+	if err != nil {
+		return nil, err
+	}
+	v5Bootnodes, err := parseBootnodes(params.V5Bootnodes)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeCfg := node.DefaultConfig
+	nodeCfg.Name = "livepeer"
+	nodeCfg.DataDir = cfg.DataDir
+	nodeCfg.IPCPath = "light.ipc"
+	nodeCfg.HTTPHost = ""
+	nodeCfg.WSHost = ""
+	nodeCfg.P2P.BootstrapNodes = bootnodes
+	nodeCfg.P2P.BootstrapNodesV5 = v5Bootnodes
+	nodeCfg.P2P.DiscoveryV5 = true
+	if cfg.MaxPeers > 0 {
+		nodeCfg.P2P.MaxPeers = cfg.MaxPeers
+	}
+	if cfg.ListenAddr != "" {
+		nodeCfg.P2P.ListenAddr = cfg.ListenAddr
+	}
+
+	stack, err := node.New(&nodeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ethCfg := ethconfig.Defaults
+	ethCfg.SyncMode = downloader.LightSync
+	ethCfg.NetworkId = cfg.ChainID.Uint64()
+	ethCfg.Genesis = network.genesis()
+
+	if _, err := les.New(stack, &ethCfg); err != nil {
+		stack.Close()
+		return nil, err
+	}
+
+	return &lesLightClient{stack: stack}, nil
+}
+
+func (lc *lesLightClient) Start() error {
+	if err := lc.stack.Start(); err != nil {
+		return err
+	}
+
+	glog.Infof("Started embedded Ethereum light client endpoint=%v", lc.URL())
+
+	return nil
+}
+
+func (lc *lesLightClient) Stop() error {
+	return lc.stack.Close()
+}
+
+// URL returns the IPC endpoint of the light client
+func (lc *lesLightClient) URL() string {
+	return lc.stack.IPCEndpoint()
+}
+
+func (lc *lesLightClient) WaitForSync(ctx context.Context) error {
+	rpcClient, err := lc.stack.Attach()
+	if err != nil {
+		return err
+	}
+	client := ethclient.NewClient(rpcClient)
+	defer client.Close()
+
+	ticker := time.NewTicker(lightClientSyncPollInterval)
+	defer ticker.Stop()
+
+	for {
+		progress, err := client.SyncProgress(ctx)
+		if err != nil {
+			return err
+		}
+
+		if progress == nil && lc.stack.Server().PeerCount() > 0 {
+			return nil
+		}
+
+		if progress != nil {
+			glog.Infof("Embedded Ethereum light client syncing currentBlock=%v highestBlock=%v", progress.CurrentBlock, progress.HighestBlock)
+		} else {
+			glog.Infof("Embedded Ethereum light client waiting for peers")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func parseBootnodes(urls []string) ([]*enode.Node, error) {
+	nodes := make([]*enode.Node, 0, len(urls))
+	for _, url := range urls {
+		n, err := enode.Parse(enode.ValidSchemes, url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bootnode %v: %v", url, err)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,37 @@
+//go:build lightclient
+// +build lightclient
+
+package eth
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLightClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", t.Name())
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	// Missing chain ID
+	_, err = NewLightClient(LightClientConfig{DataDir: dir})
+	assert.EqualError(err, "a chain ID is required for the embedded light client")
+
+	// L2 chain ID
+	_, err = NewLightClient(LightClientConfig{DataDir: dir, ChainID: big.NewInt(42161)})
+	assert.EqualError(err, "the embedded light client does not support chainID 42161")
+
+	lc, err := NewLightClient(LightClientConfig{DataDir: dir, ChainID: big.NewInt(4)})
+	require.Nil(err)
+	assert.Equal(filepath.Join(dir, "light.ipc"), lc.URL())
+	assert.Nil(lc.Stop())
+}
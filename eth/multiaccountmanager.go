@@ -0,0 +1,162 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/golang/glog"
+)
+
+var ErrUnknownAccount = errors.New("unknown ETH account")
+
+// MultiAccountManager is an AccountManager for several accounts. Transactions and messages are signed with the active
+// account, unless a transaction is sent from another account with LivepeerEthClient.WithAccount
+type MultiAccountManager interface {
+	AccountManager
+	// Accounts returns the managed accounts, the first account is the active account on start
+	Accounts() []accounts.Account
+	// ForAccount returns the AccountManager of the managed account addr
+	ForAccount(addr ethcommon.Address) (AccountManager, error)
+	// SetActive makes the managed account addr the active account
+	SetActive(addr ethcommon.Address) error
+	// SignTxFor signs tx with the managed account addr
+	SignTxFor(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error)
+}
+
+type multiAccountManager struct {
+	managers []AccountManager
+	active   AccountManager
+	mu       sync.RWMutex
+}
+
+// NewMultiAccountManager creates a MultiAccountManager for the accounts of managers. The account of the first manager
+// is the active account
+func NewMultiAccountManager(managers ...AccountManager) (MultiAccountManager, error) {
+	if len(managers) == 0 {
+		return nil, errors.New("at least one ETH account is required")
+	}
+
+	seen := make(map[ethcommon.Address]bool)
+	for _, am := range managers {
+		addr := am.Account().Address
+		if seen[addr] {
+			return nil, fmt.Errorf("duplicate ETH account %v", addr.Hex())
+		}
+		seen[addr] = true
+	}
+
+	return &multiAccountManager{
+		managers: managers,
+		active:   managers[0],
+	}, nil
+}
+
+// Unlock unlocks all of the accounts with pass
+func (am *multiAccountManager) Unlock(pass string) error {
+	for _, m := range am.managers {
+		if err := m.Unlock(pass); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lock locks all of the accounts
+func (am *multiAccountManager) Lock() error {
+	for _, m := range am.managers {
+		if err := m.Lock(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateTransactOpts creates transact opts for the active account with a signer that signs with whichever managed
+// account the transaction is sent from
+func (am *multiAccountManager) CreateTransactOpts(gasLimit uint64) (*bind.TransactOpts, error) {
+	opts, err := am.activeManager().CreateTransactOpts(gasLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Signer = func(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+		m, err := am.ForAccount(addr)
+		if err != nil {
+			return nil, bind.ErrNotAuthorized
+		}
+		mOpts, err := m.CreateTransactOpts(gasLimit)
+		if err != nil {
+			return nil, err
+		}
+		return mOpts.Signer(addr, tx)
+	}
+	return opts, nil
+}
+
+func (am *multiAccountManager) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	return am.activeManager().SignTx(tx)
+}
+
+func (am *multiAccountManager) SignTxFor(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+	m, err := am.ForAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	return m.SignTx(tx)
+}
+
+func (am *multiAccountManager) Sign(msg []byte) ([]byte, error) {
+	return am.activeManager().Sign(msg)
+}
+
+func (am *multiAccountManager) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	return am.activeManager().SignTypedData(typedData)
+}
+
+func (am *multiAccountManager) Account() accounts.Account {
+	return am.activeManager().Account()
+}
+
+func (am *multiAccountManager) Accounts() []accounts.Account {
+	accts := make([]accounts.Account, 0, len(am.managers))
+	for _, m := range am.managers {
+		accts = append(accts, m.Account())
+	}
+	return accts
+}
+
+func (am *multiAccountManager) ForAccount(addr ethcommon.Address) (AccountManager, error) {
+	for _, m := range am.managers {
+		if m.Account().Address == addr {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrUnknownAccount, addr.Hex())
+}
+
+func (am *multiAccountManager) SetActive(addr ethcommon.Address) error {
+	m, err := am.ForAccount(addr)
+	if err != nil {
+		return err
+	}
+
+	am.mu.Lock()
+	am.active = m
+	am.mu.Unlock()
+
+	glog.Infof("Using Ethereum account: %v", addr.Hex())
+
+	return nil
+}
+
+func (am *multiAccountManager) activeManager() AccountManager {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	return am.active
+}
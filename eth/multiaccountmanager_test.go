@@ -0,0 +1,127 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiAccountManager(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	chainID := big.NewInt(777)
+	newAm := func() AccountManager {
+		a, err := ks.NewAccount("foo")
+		require.Nil(err)
+		am, err := NewAccountManager(a.Address, dir, chainID)
+		require.Nil(err)
+		return am
+	}
+	hot, cold := newAm(), newAm()
+	hotAddr, coldAddr := hot.Account().Address, cold.Account().Address
+
+	_, err := NewMultiAccountManager()
+	assert.EqualError(err, "at least one ETH account is required")
+	_, err = NewMultiAccountManager(hot, hot)
+	assert.EqualError(err, "duplicate ETH account "+hotAddr.Hex())
+
+	am, err := NewMultiAccountManager(hot, cold)
+	require.Nil(err)
+	assert.Equal([]accounts.Account{hot.Account(), cold.Account()}, am.Accounts())
+	assert.Equal(hotAddr, am.Account().Address)
+	require.Nil(am.Unlock("foo"))
+
+	opts, err := am.CreateTransactOpts(100)
+	require.Nil(err)
+	assert.Equal(hotAddr, opts.From)
+
+	// The signer signs with the account that the transaction is sent from
+	signer := types.LatestSignerForChainID(chainID)
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, GasFeeCap: big.NewInt(1)})
+	for _, addr := range []ethcommon.Address{hotAddr, coldAddr} {
+		signedTx, err := opts.Signer(addr, tx)
+		require.Nil(err)
+		sender, err := types.Sender(signer, signedTx)
+		require.Nil(err)
+		assert.Equal(addr, sender)
+
+		signedTx, err = am.SignTxFor(addr, tx)
+		require.Nil(err)
+		sender, err = types.Sender(signer, signedTx)
+		require.Nil(err)
+		assert.Equal(addr, sender)
+	}
+	_, err = opts.Signer(ethcommon.HexToAddress("0x1234"), tx)
+	assert.Equal(bind.ErrNotAuthorized, err)
+	_, err = am.SignTxFor(ethcommon.HexToAddress("0x1234"), tx)
+	assert.True(errors.Is(err, ErrUnknownAccount))
+
+	// Switch the active account
+	assert.True(errors.Is(am.SetActive(ethcommon.HexToAddress("0x1234")), ErrUnknownAccount))
+	require.Nil(am.SetActive(coldAddr))
+	assert.Equal(coldAddr, am.Account().Address)
+	signedTx, err := am.SignTx(tx)
+	require.Nil(err)
+	sender, err := types.Sender(signer, signedTx)
+	require.Nil(err)
+	assert.Equal(coldAddr, sender)
+
+	coldAm, err := am.ForAccount(coldAddr)
+	require.Nil(err)
+	assert.Equal(cold, coldAm)
+
+	require.Nil(am.Lock())
+	_, err = am.SignTxFor(hotAddr, tx)
+	assert.NotNil(err)
+}
+
+func TestClient_WithAccount(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	hot, err := NewReadOnlyAccountManager(ethcommon.HexToAddress("0x1111"))
+	require.Nil(err)
+	cold, err := NewReadOnlyAccountManager(ethcommon.HexToAddress("0x2222"))
+	require.Nil(err)
+	unknown := ethcommon.HexToAddress("0x3333")
+
+	// Single account
+	c := &client{accountManager: hot}
+	assert.Equal([]accounts.Account{hot.Account()}, c.Accounts())
+	withAccount, err := c.WithAccount(hot.Account().Address)
+	require.Nil(err)
+	assert.Equal(c, withAccount)
+	_, err = c.WithAccount(unknown)
+	assert.True(errors.Is(err, ErrUnknownAccount))
+	assert.Nil(c.SetAccount(hot.Account().Address))
+	assert.True(errors.Is(c.SetAccount(unknown), ErrUnknownAccount))
+
+	// Multiple accounts
+	am, err := NewMultiAccountManager(hot, cold)
+	require.Nil(err)
+	c = &client{accountManager: am, multiAccountManager: am}
+	assert.Len(c.Accounts(), 2)
+
+	withAccount, err = c.WithAccount(cold.Account().Address)
+	require.Nil(err)
+	assert.Equal(cold.Account(), withAccount.Account())
+	assert.Equal(hot.Account(), c.Account())
+	_, err = c.WithAccount(unknown)
+	assert.True(errors.Is(err, ErrUnknownAccount))
+
+	require.Nil(c.SetAccount(cold.Account().Address))
+	assert.Equal(cold.Account(), c.Account())
+	assert.True(errors.Is(c.SetAccount(unknown), ErrUnknownAccount))
+}
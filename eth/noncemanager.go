@@ -35,13 +35,13 @@ func NewNonceManager(remoteReader RemoteNonceReader) *NonceManager {
 }
 
 // Lock locks the provided address. The caller should always call Lock before
-// calling Next or Update
+// calling Next, Update or Resync
 func (m *NonceManager) Lock(addr ethcommon.Address) {
 	m.getNonceLock(addr).mu.Lock()
 }
 
 // Unlock unlocks the provided address. The caller should always call Unlock
-// after finishing calls to Next or Update
+// after finishing calls to Next, Update or Resync
 func (m *NonceManager) Unlock(addr ethcommon.Address) {
 	m.getNonceLock(addr).mu.Unlock()
 }
@@ -79,6 +79,19 @@ func (m *NonceManager) Update(addr ethcommon.Address, lastNonce uint64) {
 	nonceLock.nonce = lastNonce + 1
 }
 
+// Resync resets the next transaction nonce for the provided address to the pending nonce of the remote source,
+// i.e. if transactions that were submitted with the local nonce were dropped
+func (m *NonceManager) Resync(addr ethcommon.Address) error {
+	remoteNonce, err := m.remoteReader.PendingNonceAt(context.Background(), addr)
+	if err != nil {
+		return err
+	}
+
+	m.getNonceLock(addr).nonce = remoteNonce
+
+	return nil
+}
+
 func (m *NonceManager) getNonceLock(addr ethcommon.Address) *nonceLock {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -213,3 +213,47 @@ func TestNextAndUpdate_ConcurrentSingleAddr(t *testing.T) {
 		assert.True(usedNonces[uint64(i)])
 	}
 }
+
+func TestResync(t *testing.T) {
+	r := &mockRemoteNonceReader{}
+	nm := NewNonceManager(r)
+	addr := pm.RandAddress()
+
+	assert := assert.New(t)
+	require := require.New(t)
+
+	r.On("PendingNonceAt", mock.Anything, addr).Return(uint64(5), nil).Once()
+	require.Nil(nm.Resync(addr))
+	nm.Update(addr, uint64(5))
+
+	// Transactions with nonces 6 and 7 were dropped
+	nm.Update(addr, uint64(7))
+	r.On("PendingNonceAt", mock.Anything, addr).Return(uint64(6), nil)
+	nonce, err := nm.Next(addr)
+	require.Nil(err)
+	require.Equal(uint64(8), nonce)
+
+	assert.Nil(nm.Resync(addr))
+	nonce, err = nm.Next(addr)
+	assert.Nil(err)
+	assert.Equal(uint64(6), nonce)
+}
+
+func TestResync_PendingNonceAtError(t *testing.T) {
+	r := &mockRemoteNonceReader{}
+	nm := NewNonceManager(r)
+	addr := pm.RandAddress()
+
+	nm.Update(addr, uint64(10))
+	r.On("PendingNonceAt", mock.Anything, addr).Return(uint64(0), errors.New("PendingNonceAt error")).Once()
+
+	assert := assert.New(t)
+
+	assert.EqualError(nm.Resync(addr), "PendingNonceAt error")
+
+	// The local nonce is unchanged
+	r.On("PendingNonceAt", mock.Anything, addr).Return(uint64(0), nil)
+	nonce, err := nm.Next(addr)
+	assert.Nil(err)
+	assert.Equal(uint64(11), nonce)
+}
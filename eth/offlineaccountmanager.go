@@ -0,0 +1,160 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/lperrors"
+)
+
+var ErrOfflineSigning = lperrors.User(errors.New("signing is not available for an offline account"))
+
+// OfflineTxError is returned by the transact opts of an offline account manager
+// once an unsigned transaction has been written to disk instead of being signed
+type OfflineTxError struct {
+	Path string
+}
+
+func (e *OfflineTxError) Error() string {
+	return fmt.Sprintf("unsigned transaction written to %v; sign it offline and submit it with /broadcastSignedTx", e.Path)
+}
+
+// UnsignedTx is the on-disk representation of a transaction prepared for offline signing
+type UnsignedTx struct {
+	From    ethcommon.Address `json:"from"`
+	ChainID *big.Int          `json:"chainId"`
+	Nonce   uint64            `json:"nonce"`
+	Method  string            `json:"method"`
+	Inputs  string            `json:"inputs"`
+	// RawTx is the binary encoding of the unsigned transaction
+	RawTx hexutil.Bytes `json:"rawTx"`
+}
+
+type offlineAccountManager struct {
+	account accounts.Account
+	chainID *big.Int
+	txDir   string
+}
+
+// NewOfflineAccountManager creates an AccountManager for an account whose keys are not available on this host.
+// Instead of signing transactions, it serializes them to txDir so they can be signed on an offline machine
+func NewOfflineAccountManager(accountAddr ethcommon.Address, txDir string, chainID *big.Int) (AccountManager, error) {
+	if (accountAddr == ethcommon.Address{}) {
+		return nil, errors.New("an ETH account address is required for offline transaction preparation")
+	}
+
+	if err := os.MkdirAll(txDir, 0700); err != nil {
+		return nil, err
+	}
+
+	glog.Infof("Using offline Ethereum account: %v, unsigned transactions will be written to %v", accountAddr.Hex(), txDir)
+
+	return &offlineAccountManager{
+		account: accounts.Account{Address: accountAddr},
+		chainID: chainID,
+		txDir:   txDir,
+	}, nil
+}
+
+// Unlock is a no-op because there is no key to unlock
+func (am *offlineAccountManager) Unlock(pass string) error {
+	return nil
+}
+
+// Lock is a no-op because there is no key to lock
+func (am *offlineAccountManager) Lock() error {
+	return nil
+}
+
+// CreateTransactOpts creates transact opts with a signer that writes the unsigned transaction to disk
+func (am *offlineAccountManager) CreateTransactOpts(gasLimit uint64) (*bind.TransactOpts, error) {
+	return &bind.TransactOpts{
+		From:     am.account.Address,
+		Signer:   am.writeUnsignedTx,
+		Context:  context.Background(),
+		GasLimit: gasLimit,
+	}, nil
+}
+
+// SignTx always fails because there is no key available
+func (am *offlineAccountManager) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	return nil, ErrOfflineSigning
+}
+
+// Sign always fails because there is no key available
+func (am *offlineAccountManager) Sign(msg []byte) ([]byte, error) {
+	return nil, ErrOfflineSigning
+}
+
+// SignTypedData always fails because there is no key available
+func (am *offlineAccountManager) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	return nil, ErrOfflineSigning
+}
+
+func (am *offlineAccountManager) Account() accounts.Account {
+	return am.account
+}
+
+func (am *offlineAccountManager) writeUnsignedTx(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+	if addr != am.account.Address {
+		return nil, bind.ErrNotAuthorized
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	txLog, err := newTxLog(tx)
+	if err != nil {
+		txLog.method = "unknown"
+	}
+
+	data, err := json.MarshalIndent(&UnsignedTx{
+		From:    addr,
+		ChainID: am.chainID,
+		Nonce:   tx.Nonce(),
+		Method:  txLog.method,
+		Inputs:  txLog.inputs,
+		RawTx:   raw,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(am.txDir, fmt.Sprintf("%d-%v.json", tx.Nonce(), txLog.method))
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return nil, err
+	}
+
+	glog.Infof("Prepared unsigned transaction: \"%v\". Inputs: \"%v\" Nonce: %v Path: %v", txLog.method, txLog.inputs, tx.Nonce(), path)
+
+	return nil, &OfflineTxError{Path: path}
+}
+
+// SendSignedTransaction decodes a transaction that was signed on an offline machine and submits it
+func SendSignedTransaction(ctx context.Context, b Backend, rawTx []byte) (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return nil, fmt.Errorf("invalid signed transaction: %v", err)
+	}
+
+	if err := b.SendTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
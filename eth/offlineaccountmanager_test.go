@@ -0,0 +1,94 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineAccountManager_MissingAddress(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewOfflineAccountManager(ethcommon.Address{}, dir, big.NewInt(1))
+	assert.EqualError(t, err, "an ETH account address is required for offline transaction preparation")
+}
+
+func TestOfflineAccountManager_Signing(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", t.Name())
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	am, err := NewOfflineAccountManager(ethcommon.HexToAddress("0x1234"), dir, big.NewInt(1))
+	require.Nil(t, err)
+
+	assert.Nil(am.Unlock("foo"))
+	assert.Equal(ethcommon.HexToAddress("0x1234"), am.Account().Address)
+
+	_, err = am.SignTx(types.NewTx(&types.LegacyTx{}))
+	assert.Equal(ErrOfflineSigning, err)
+	_, err = am.Sign([]byte("foo"))
+	assert.Equal(ErrOfflineSigning, err)
+}
+
+func TestOfflineAccountManager_WritesUnsignedTx(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", t.Name())
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	addr := ethcommon.HexToAddress("0x1234")
+	am, err := NewOfflineAccountManager(addr, dir, big.NewInt(1))
+	require.Nil(err)
+
+	opts, err := am.CreateTransactOpts(100)
+	require.Nil(err)
+	assert.Equal(addr, opts.From)
+	assert.Equal(uint64(100), opts.GasLimit)
+
+	to := ethcommon.HexToAddress("0xabcd")
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    7,
+		GasPrice: big.NewInt(10),
+		Gas:      100,
+		To:       &to,
+		Value:    big.NewInt(0),
+	})
+
+	// Signing for a different address is not authorized
+	_, err = opts.Signer(to, tx)
+	assert.Equal(bind.ErrNotAuthorized, err)
+
+	signed, err := opts.Signer(addr, tx)
+	assert.Nil(signed)
+	var offlineErr *OfflineTxError
+	require.True(errors.As(err, &offlineErr))
+
+	data, err := ioutil.ReadFile(offlineErr.Path)
+	require.Nil(err)
+
+	var utx UnsignedTx
+	require.Nil(json.Unmarshal(data, &utx))
+	assert.Equal(addr, utx.From)
+	assert.Equal(uint64(7), utx.Nonce)
+	assert.Equal(big.NewInt(1), utx.ChainID)
+	assert.Equal("unknown", utx.Method)
+
+	decoded := new(types.Transaction)
+	require.Nil(decoded.UnmarshalBinary(utx.RawTx))
+	assert.Equal(tx.Hash(), decoded.Hash())
+}
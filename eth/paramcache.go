@@ -0,0 +1,65 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// paramCache caches the protocol parameters read from the contracts so that callers that poll them do not make an RPC
+// call every time. The parameters only change with governance updates, which emit ParameterUpdate events, and with new
+// rounds, so the cache is cleared with ForceRefresh when either is seen. It is shared with the clients returned by
+// WithGasFees and WithAccount
+type paramCache struct {
+	mu     sync.Mutex
+	values map[string]*big.Int
+}
+
+func newParamCache() *paramCache {
+	return &paramCache{values: make(map[string]*big.Int)}
+}
+
+// get returns the cached value of the parameter name, fetching it if it is not cached. The returned value is a copy
+// that the caller may modify
+func (p *paramCache) get(name string, fetch func() (*big.Int, error)) (*big.Int, error) {
+	p.mu.Lock()
+	v, ok := p.values[name]
+	p.mu.Unlock()
+	if ok {
+		return new(big.Int).Set(v), nil
+	}
+
+	v, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.values[name] = new(big.Int).Set(v)
+	p.mu.Unlock()
+	return v, nil
+}
+
+func (p *paramCache) clear() {
+	p.mu.Lock()
+	p.values = make(map[string]*big.Int)
+	p.mu.Unlock()
+}
+
+// cachedParam returns the parameter name from the cache of the client, or fetches it with a read call that is
+// cancelled when ctx is done if it is not cached or the client has no cache
+func (c *client) cachedParam(ctx context.Context, name string, fetch func(opts *bind.CallOpts) (*big.Int, error)) (*big.Int, error) {
+	read := func() (*big.Int, error) { return fetch(c.callOpts(ctx)) }
+	if c.params == nil {
+		return read()
+	}
+	return c.params.get(name, read)
+}
+
+func (c *client) ForceRefresh() {
+	if c.params != nil {
+		c.params.clear()
+	}
+}
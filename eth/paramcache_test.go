@@ -0,0 +1,67 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedParam(t *testing.T) {
+	assert := assert.New(t)
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "caller")
+
+	c := &client{params: newParamCache()}
+	calls := 0
+	var err error
+	fetch := func(opts *bind.CallOpts) (*big.Int, error) {
+		calls++
+		// The read call uses the context of the caller
+		assert.Equal("caller", opts.Context.Value(ctxKey{}))
+		return big.NewInt(100), err
+	}
+
+	// Parameters are fetched once until they are refreshed
+	v, err := c.cachedParam(ctx, "roundLength", fetch)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(100), v)
+	v, _ = c.cachedParam(ctx, "roundLength", fetch)
+	assert.Equal(big.NewInt(100), v)
+	assert.Equal(1, calls)
+
+	// Cached values cannot be modified by the caller
+	v.SetInt64(5)
+	v, _ = c.cachedParam(ctx, "roundLength", fetch)
+	assert.Equal(big.NewInt(100), v)
+
+	// Each parameter is cached separately
+	c.cachedParam(ctx, "unlockPeriod", fetch)
+	assert.Equal(2, calls)
+
+	c.ForceRefresh()
+	c.cachedParam(ctx, "roundLength", fetch)
+	c.cachedParam(ctx, "unlockPeriod", fetch)
+	assert.Equal(4, calls)
+
+	// Errors are not cached
+	c.ForceRefresh()
+	err = errors.New("rpc error")
+	_, err2 := c.cachedParam(ctx, "roundLength", fetch)
+	assert.EqualError(err2, "rpc error")
+	err = nil
+	v, _ = c.cachedParam(ctx, "roundLength", fetch)
+	assert.Equal(big.NewInt(100), v)
+	assert.Equal(6, calls)
+
+	// Clients without a cache fetch the parameters every time
+	c = &client{}
+	c.cachedParam(ctx, "roundLength", fetch)
+	c.cachedParam(ctx, "roundLength", fetch)
+	c.ForceRefresh()
+	assert.Equal(8, calls)
+}
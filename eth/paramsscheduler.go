@@ -0,0 +1,300 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+)
+
+var (
+	ErrParamsSchedulerStarted = fmt.Errorf("params scheduler already started")
+	ErrParamsSchedulerStopped = fmt.Errorf("params scheduler already stopped")
+)
+
+// paramsCheckInterval is how often the params scheduler checks whether the on-chain params of a due change can be
+// updated, i.e. once the orchestrator called reward for the current round
+var paramsCheckInterval = 1 * time.Minute
+
+// ScheduledParams are orchestrator params that take effect at Round. Params that are nil are not changed
+type ScheduledParams struct {
+	Round         *big.Int
+	RewardCut     *big.Int
+	FeeShare      *big.Int
+	PricePerPixel *big.Rat
+}
+
+func (p *ScheduledParams) onChain() bool {
+	return p.RewardCut != nil || p.FeeShare != nil
+}
+
+// ParamsScheduler applies scheduled changes of the reward cut, fee share and price of the orchestrator once the round
+// that they are scheduled for is initialized. The price is changed right away, the reward cut and fee share are
+// updated with a transaction as soon as the protocol allows it: the round must not be locked and an active
+// orchestrator must have called reward for the round. If the round locks before the transaction could be sent, the
+// update is sent in the next round instead
+type ParamsScheduler struct {
+	client   LivepeerEthClient
+	tw       timeWatcher
+	setPrice func(price *big.Rat)
+
+	working      bool
+	cancelWorker context.CancelFunc
+
+	mu        sync.Mutex
+	scheduled []*ScheduledParams
+	// applyMu serializes the application of due params which can wait for a transaction to be mined
+	applyMu sync.Mutex
+}
+
+// NewParamsScheduler creates a ParamsScheduler that changes the price of the orchestrator with setPrice
+func NewParamsScheduler(client LivepeerEthClient, tw timeWatcher, setPrice func(price *big.Rat)) *ParamsScheduler {
+	return &ParamsScheduler{
+		client:   client,
+		tw:       tw,
+		setPrice: setPrice,
+	}
+}
+
+// Schedule schedules params to take effect at params.Round. It replaces the params that were already scheduled for
+// the same round
+func (s *ParamsScheduler) Schedule(params *ScheduledParams) error {
+	if params.Round == nil {
+		return errors.New("missing round")
+	}
+	if !params.onChain() && params.PricePerPixel == nil {
+		return errors.New("no params to change")
+	}
+	if params.RewardCut != nil && (params.RewardCut.Sign() < 0 || params.RewardCut.Cmp(FromPerc(100)) > 0) {
+		return fmt.Errorf("invalid reward cut %v", params.RewardCut)
+	}
+	if params.FeeShare != nil && (params.FeeShare.Sign() < 0 || params.FeeShare.Cmp(FromPerc(100)) > 0) {
+		return fmt.Errorf("invalid fee share %v", params.FeeShare)
+	}
+	if params.PricePerPixel != nil && params.PricePerPixel.Sign() < 0 {
+		return fmt.Errorf("invalid price per pixel %v", params.PricePerPixel.FloatString(3))
+	}
+	if round := s.tw.LastInitializedRound(); params.Round.Cmp(round) <= 0 {
+		return fmt.Errorf("round %v is not after the current round %v", params.Round, round)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(params.Round)
+	s.scheduled = append(s.scheduled, params)
+	sort.Slice(s.scheduled, func(i, j int) bool {
+		return s.scheduled[i].Round.Cmp(s.scheduled[j].Round) < 0
+	})
+
+	glog.Infof("Scheduled orchestrator params change for round %v", params.Round)
+
+	return nil
+}
+
+// Cancel removes the params scheduled for round. It returns false if no params are scheduled for round
+func (s *ParamsScheduler) Cancel(round *big.Int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.remove(round)
+}
+
+func (s *ParamsScheduler) remove(round *big.Int) bool {
+	for i, params := range s.scheduled {
+		if params.Round.Cmp(round) == 0 {
+			s.scheduled = append(s.scheduled[:i], s.scheduled[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Scheduled returns the params that are scheduled and not applied yet ordered by round
+func (s *ParamsScheduler) Scheduled() []*ScheduledParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scheduled := make([]*ScheduledParams, len(s.scheduled))
+	for i, params := range s.scheduled {
+		p := *params
+		scheduled[i] = &p
+	}
+	return scheduled
+}
+
+func (s *ParamsScheduler) Start(ctx context.Context) error {
+	if s.working {
+		return ErrParamsSchedulerStarted
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	s.cancelWorker = cancel
+
+	roundSink := make(chan types.Log, 10)
+	sub := s.tw.SubscribeRounds(roundSink)
+	defer sub.Unsubscribe()
+
+	ticker := time.NewTicker(paramsCheckInterval)
+	defer ticker.Stop()
+
+	s.working = true
+	defer func() {
+		s.working = false
+	}()
+
+	for {
+		select {
+		case err := <-sub.Err():
+			if err != nil {
+				glog.Errorf("Round subscription error err=%q", err)
+			}
+		case <-roundSink:
+			s.tryApply(cancelCtx)
+		case <-ticker.C:
+			s.tryApply(cancelCtx)
+		case <-cancelCtx.Done():
+			glog.V(5).Infof("Params scheduler done")
+			return nil
+		}
+	}
+}
+
+func (s *ParamsScheduler) Stop() error {
+	if !s.working {
+		return ErrParamsSchedulerStopped
+	}
+
+	s.cancelWorker()
+	s.working = false
+
+	return nil
+}
+
+// tryApply applies the params that are due in the current round. Params whose on-chain update fails are tried again
+// on the next check
+func (s *ParamsScheduler) tryApply(ctx context.Context) {
+	s.applyMu.Lock()
+	defer s.applyMu.Unlock()
+
+	round := s.tw.LastInitializedRound()
+	due := s.popDue(round)
+	if due == nil {
+		return
+	}
+
+	if due.PricePerPixel != nil {
+		s.setPrice(due.PricePerPixel)
+		glog.Infof("Applied scheduled price per pixel=%v wei for round %v", due.PricePerPixel.FloatString(3), round)
+		due.PricePerPixel = nil
+	}
+
+	if !due.onChain() {
+		return
+	}
+	err := s.updateOnChain(ctx, due)
+	if err == nil {
+		return
+	}
+	if err != errParamsNotReady {
+		glog.Errorf("Error applying scheduled reward cut and fee share for round %v err=%q", round, err)
+	}
+
+	// Try again on the next check. Params that were scheduled for the same round in the meantime take precedence
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, params := range s.scheduled {
+		if params.Round.Cmp(due.Round) == 0 {
+			s.scheduled[i] = mergeParams(due, params)
+			return
+		}
+	}
+	s.scheduled = append([]*ScheduledParams{due}, s.scheduled...)
+}
+
+// popDue removes the params that are due in round from the schedule and returns them merged into a single change
+func (s *ParamsScheduler) popDue(round *big.Int) *ScheduledParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due *ScheduledParams
+	for len(s.scheduled) > 0 && s.scheduled[0].Round.Cmp(round) <= 0 {
+		// Later changes that are due override earlier ones
+		due = mergeParams(due, s.scheduled[0])
+		s.scheduled = s.scheduled[1:]
+	}
+	return due
+}
+
+// errParamsNotReady indicates that the protocol does not allow to update the params yet
+var errParamsNotReady = errors.New("params cannot be updated yet")
+
+func (s *ParamsScheduler) updateOnChain(ctx context.Context, params *ScheduledParams) error {
+	t, err := s.client.GetTranscoder(ctx, s.client.Account().Address)
+	if err != nil {
+		return err
+	}
+
+	rewardCut, feeShare := t.RewardCut, t.FeeShare
+	if params.RewardCut != nil {
+		rewardCut = params.RewardCut
+	}
+	if params.FeeShare != nil {
+		feeShare = params.FeeShare
+	}
+	if rewardCut.Cmp(t.RewardCut) == 0 && feeShare.Cmp(t.FeeShare) == 0 {
+		return nil
+	}
+
+	locked, err := s.client.CurrentRoundLocked(ctx)
+	if err != nil {
+		return err
+	}
+	if locked {
+		glog.V(common.DEBUG).Infof("Current round is locked, updating reward cut and fee share in the next round")
+		return errParamsNotReady
+	}
+	if t.Active && t.LastRewardRound.Cmp(s.tw.LastInitializedRound()) < 0 {
+		glog.V(common.DEBUG).Infof("Waiting for reward to be called before updating reward cut and fee share")
+		return errParamsNotReady
+	}
+
+	tx, err := s.client.Transcoder(ctx, rewardCut, feeShare)
+	if err != nil {
+		return err
+	}
+	if err := s.client.CheckTx(ctx, tx); err != nil {
+		return err
+	}
+
+	glog.Infof("Applied scheduled reward cut=%v fee share=%v", ToPerc(rewardCut), ToPerc(feeShare))
+
+	return nil
+}
+
+// mergeParams returns the params of prev overridden by the params that are set in next
+func mergeParams(prev, next *ScheduledParams) *ScheduledParams {
+	if prev == nil {
+		p := *next
+		return &p
+	}
+	p := *prev
+	p.Round = next.Round
+	if next.RewardCut != nil {
+		p.RewardCut = next.RewardCut
+	}
+	if next.FeeShare != nil {
+		p.FeeShare = next.FeeShare
+	}
+	if next.PricePerPixel != nil {
+		p.PricePerPixel = next.PricePerPixel
+	}
+	return &p
+}
@@ -0,0 +1,112 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/core/types"
+	lpTypes "github.com/livepeer/go-livepeer/eth/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamsScheduler_Schedule(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tw := &stubTimeWatcher{lastInitializedRound: big.NewInt(100)}
+	ps := NewParamsScheduler(&MockClient{}, tw, func(*big.Rat) {})
+
+	assert.EqualError(ps.Schedule(&ScheduledParams{RewardCut: FromPerc(10)}), "missing round")
+	assert.EqualError(ps.Schedule(&ScheduledParams{Round: big.NewInt(101)}), "no params to change")
+	assert.EqualError(ps.Schedule(&ScheduledParams{Round: big.NewInt(101), RewardCut: FromPerc(101)}), "invalid reward cut 1010000")
+	assert.EqualError(ps.Schedule(&ScheduledParams{Round: big.NewInt(101), FeeShare: big.NewInt(-1)}), "invalid fee share -1")
+	assert.EqualError(ps.Schedule(&ScheduledParams{Round: big.NewInt(101), PricePerPixel: big.NewRat(-1, 1)}), "invalid price per pixel -1.000")
+	assert.EqualError(ps.Schedule(&ScheduledParams{Round: big.NewInt(100), FeeShare: FromPerc(10)}), "round 100 is not after the current round 100")
+
+	// Scheduled params are ordered by round and replace the params of the same round
+	require.Nil(ps.Schedule(&ScheduledParams{Round: big.NewInt(103), FeeShare: FromPerc(10)}))
+	require.Nil(ps.Schedule(&ScheduledParams{Round: big.NewInt(102), FeeShare: FromPerc(20)}))
+	require.Nil(ps.Schedule(&ScheduledParams{Round: big.NewInt(103), FeeShare: FromPerc(30)}))
+	scheduled := ps.Scheduled()
+	require.Len(scheduled, 2)
+	assert.Equal(big.NewInt(102), scheduled[0].Round)
+	assert.Equal(big.NewInt(103), scheduled[1].Round)
+	assert.Equal(FromPerc(30), scheduled[1].FeeShare)
+
+	assert.False(ps.Cancel(big.NewInt(104)))
+	assert.True(ps.Cancel(big.NewInt(102)))
+	scheduled = ps.Scheduled()
+	require.Len(scheduled, 1)
+	assert.Equal(big.NewInt(103), scheduled[0].Round)
+}
+
+func TestParamsScheduler_TryApply(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	client := &MockClient{}
+	client.On("Account").Return(accounts.Account{})
+	tw := &stubTimeWatcher{lastInitializedRound: big.NewInt(100)}
+	var price *big.Rat
+	ps := NewParamsScheduler(client, tw, func(p *big.Rat) { price = p })
+	ctx := context.Background()
+
+	require.Nil(ps.Schedule(&ScheduledParams{Round: big.NewInt(101), PricePerPixel: big.NewRat(1, 1)}))
+	require.Nil(ps.Schedule(&ScheduledParams{Round: big.NewInt(102), RewardCut: FromPerc(10), PricePerPixel: big.NewRat(2, 1)}))
+
+	// Nothing is due
+	ps.tryApply(ctx)
+	assert.Nil(price)
+	assert.Len(ps.Scheduled(), 2)
+
+	// The price is applied once the round is initialized
+	tw.lastInitializedRound = big.NewInt(101)
+	ps.tryApply(ctx)
+	assert.Equal(big.NewRat(1, 1), price)
+	assert.Len(ps.Scheduled(), 1)
+
+	// The price is applied right away while the reward cut waits until reward is called
+	tw.lastInitializedRound = big.NewInt(102)
+	client.On("GetTranscoder").Return(&lpTypes.Transcoder{
+		RewardCut:       FromPerc(5),
+		FeeShare:        FromPerc(50),
+		LastRewardRound: big.NewInt(101),
+		Active:          true,
+	}, nil).Once()
+	client.On("CurrentRoundLocked").Return(false, nil).Once()
+	ps.tryApply(ctx)
+	assert.Equal(big.NewRat(2, 1), price)
+	client.AssertNotCalled(t, "Transcoder", FromPerc(10), FromPerc(50))
+	scheduled := ps.Scheduled()
+	require.Len(scheduled, 1)
+	assert.Nil(scheduled[0].PricePerPixel)
+
+	// The reward cut waits while the round is locked
+	client.On("GetTranscoder").Return(&lpTypes.Transcoder{
+		RewardCut:       FromPerc(5),
+		FeeShare:        FromPerc(50),
+		LastRewardRound: big.NewInt(102),
+		Active:          true,
+	}, nil)
+	client.On("CurrentRoundLocked").Return(true, nil).Once()
+	ps.tryApply(ctx)
+	client.AssertNotCalled(t, "Transcoder", FromPerc(10), FromPerc(50))
+	assert.Len(ps.Scheduled(), 1)
+
+	// Failed updates are tried again
+	client.On("CurrentRoundLocked").Return(false, nil)
+	client.On("Transcoder", FromPerc(10), FromPerc(50)).Return(nil, errors.New("connection refused")).Once()
+	ps.tryApply(ctx)
+	assert.Len(ps.Scheduled(), 1)
+
+	// The fee share is kept when only the reward cut changes
+	client.On("Transcoder", FromPerc(10), FromPerc(50)).Return(&types.Transaction{}, nil).Once()
+	client.On("CheckTx").Return(nil).Once()
+	ps.tryApply(ctx)
+	client.AssertNumberOfCalls(t, "Transcoder", 2)
+	assert.Empty(ps.Scheduled())
+}
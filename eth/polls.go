@@ -0,0 +1,104 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/eth/contracts"
+	lpTypes "github.com/livepeer/go-livepeer/eth/types"
+)
+
+// pollCreatorABI is the subset of the PollCreator ABI that governance polls are listed with
+const pollCreatorABI = `[
+	{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"poll","type":"address"},{"indexed":false,"internalType":"bytes","name":"proposal","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"endBlock","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"quorum","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"quota","type":"uint256"}],"name":"PollCreated","type":"event"}
+]`
+
+// Polls returns the governance polls created by the PollCreator since fromBlock, oldest first, with the latest vote
+// of the account of the client. The PollCreator is not registered with the Controller, so its address is read from
+// the contract overrides
+func (c *client) Polls(ctx context.Context, fromBlock *big.Int) ([]*lpTypes.Poll, error) {
+	creator, err := c.resolveContract("PollCreator", nil)
+	if err != nil {
+		return nil, err
+	}
+	if creator == (ethcommon.Address{}) {
+		return nil, fmt.Errorf("unknown PollCreator address, set it with -contractOverrides PollCreator=<address>")
+	}
+	blk, err := c.backend.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fetchPolls(ctx, c.backend, creator, c.accountManager.Account().Address, fromBlock, new(big.Int).SetUint64(blk))
+}
+
+// fetchPolls reads the polls created by the PollCreator at creator and the latest vote of voter in each of them.
+// Polls are active until their end block, inclusive
+func fetchPolls(ctx context.Context, filterer bind.ContractFilterer, creator, voter ethcommon.Address, fromBlock, currentBlock *big.Int) ([]*lpTypes.Poll, error) {
+	parsed, err := abi.JSON(strings.NewReader(pollCreatorABI))
+	if err != nil {
+		return nil, err
+	}
+	event := parsed.Events["PollCreated"]
+
+	query := ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		Addresses: []ethcommon.Address{creator},
+		Topics:    [][]ethcommon.Hash{{event.ID}},
+	}
+	logs, err := filterer.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var polls []*lpTypes.Poll
+	for _, log := range logs {
+		if len(log.Topics) < 2 {
+			return nil, fmt.Errorf("invalid PollCreated log tx=%v", log.TxHash.Hex())
+		}
+		out, err := event.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return nil, err
+		}
+		poll := &lpTypes.Poll{
+			Address:  ethcommon.BytesToAddress(log.Topics[1].Bytes()),
+			Proposal: string(out[0].([]byte)),
+			EndBlock: out[1].(*big.Int),
+			Quorum:   out[2].(*big.Int),
+			Quota:    out[3].(*big.Int),
+		}
+		poll.Active = currentBlock.Cmp(poll.EndBlock) <= 0
+
+		if poll.Vote, err = latestVote(ctx, filterer, poll.Address, voter, log.BlockNumber); err != nil {
+			return nil, err
+		}
+		polls = append(polls, poll)
+	}
+	return polls, nil
+}
+
+// latestVote returns the latest choice of voter in the poll at pollAddr, nil if voter did not vote. Voters can change
+// their vote until the end of the poll
+func latestVote(ctx context.Context, filterer bind.ContractFilterer, pollAddr, voter ethcommon.Address, start uint64) (*lpTypes.VoteChoice, error) {
+	poll, err := contracts.NewPollFilterer(pollAddr, filterer)
+	if err != nil {
+		return nil, err
+	}
+	it, err := poll.FilterVote(&bind.FilterOpts{Start: start, Context: ctx}, []ethcommon.Address{voter})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var vote *lpTypes.VoteChoice
+	for it.Next() {
+		choice := lpTypes.VoteChoice(it.Event.ChoiceID.Int64())
+		vote = &choice
+	}
+	return vote, it.Error()
+}
@@ -0,0 +1,106 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/livepeer/go-livepeer/eth/contracts"
+	lpTypes "github.com/livepeer/go-livepeer/eth/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubLogFilterer struct {
+	logs []types.Log
+	err  error
+}
+
+func (f *stubLogFilterer) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var logs []types.Log
+	for _, l := range f.logs {
+		if len(q.Addresses) > 0 && l.Address != q.Addresses[0] {
+			continue
+		}
+		if len(q.Topics) > 0 && len(q.Topics[0]) > 0 && l.Topics[0] != q.Topics[0][0] {
+			continue
+		}
+		if len(q.Topics) > 1 && len(q.Topics[1]) > 0 && l.Topics[1] != q.Topics[1][0] {
+			continue
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
+func (f *stubLogFilterer) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestFetchPolls(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	creatorABI, err := abi.JSON(strings.NewReader(pollCreatorABI))
+	require.Nil(err)
+	pollABI, err := abi.JSON(strings.NewReader(contracts.PollABI))
+	require.Nil(err)
+
+	creator := ethcommon.HexToAddress("aaa")
+	voter := ethcommon.HexToAddress("bbb")
+	other := ethcommon.HexToAddress("ccc")
+	pollA := ethcommon.HexToAddress("111")
+	pollB := ethcommon.HexToAddress("222")
+
+	created := func(poll ethcommon.Address, proposal string, endBlock int64) types.Log {
+		event := creatorABI.Events["PollCreated"]
+		data, err := event.Inputs.NonIndexed().Pack([]byte(proposal), big.NewInt(endBlock), big.NewInt(333300), big.NewInt(500000))
+		require.Nil(err)
+		return types.Log{Address: creator, Topics: []ethcommon.Hash{event.ID, ethcommon.BytesToHash(poll.Bytes())}, Data: data, BlockNumber: 10}
+	}
+	vote := func(poll, voter ethcommon.Address, choice lpTypes.VoteChoice) types.Log {
+		event := pollABI.Events["Vote"]
+		data, err := event.Inputs.NonIndexed().Pack(big.NewInt(int64(choice)))
+		require.Nil(err)
+		return types.Log{Address: poll, Topics: []ethcommon.Hash{event.ID, ethcommon.BytesToHash(voter.Bytes())}, Data: data, BlockNumber: 20}
+	}
+
+	filterer := &stubLogFilterer{logs: []types.Log{
+		created(pollA, "QmA", 100),
+		created(pollB, "QmB", 1000),
+		// The latest vote counts
+		vote(pollA, voter, lpTypes.Yes),
+		vote(pollA, voter, lpTypes.No),
+		vote(pollB, other, lpTypes.Yes),
+	}}
+
+	polls, err := fetchPolls(context.Background(), filterer, creator, voter, big.NewInt(0), big.NewInt(500))
+	require.Nil(err)
+	require.Len(polls, 2)
+
+	assert.Equal(pollA, polls[0].Address)
+	assert.Equal("QmA", polls[0].Proposal)
+	assert.Equal(big.NewInt(100), polls[0].EndBlock)
+	assert.Equal(big.NewInt(333300), polls[0].Quorum)
+	assert.Equal(big.NewInt(500000), polls[0].Quota)
+	assert.False(polls[0].Active)
+	require.NotNil(polls[0].Vote)
+	assert.Equal(lpTypes.No, *polls[0].Vote)
+
+	assert.Equal(pollB, polls[1].Address)
+	assert.True(polls[1].Active)
+	assert.Nil(polls[1].Vote)
+
+	filterer.err = errors.New("filter error")
+	_, err = fetchPolls(context.Background(), filterer, creator, voter, big.NewInt(0), big.NewInt(500))
+	assert.EqualError(err, "filter error")
+}
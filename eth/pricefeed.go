@@ -0,0 +1,234 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/glog"
+)
+
+var (
+	ErrFiatPriceUpdaterStarted = fmt.Errorf("fiat price updater already started")
+	ErrFiatPriceUpdaterStopped = fmt.Errorf("fiat price updater already stopped")
+)
+
+// priceFeedMaxAge is the age after which the answer of a price feed is considered stale
+var priceFeedMaxAge = 24 * time.Hour
+
+// aggregatorV3ABI is the subset of the Chainlink AggregatorV3Interface that price feeds are read with
+const aggregatorV3ABI = `[
+	{"inputs":[],"name":"decimals","outputs":[{"internalType":"uint8","name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"latestRoundData","outputs":[{"internalType":"uint80","name":"roundId","type":"uint80"},{"internalType":"int256","name":"answer","type":"int256"},{"internalType":"uint256","name":"startedAt","type":"uint256"},{"internalType":"uint256","name":"updatedAt","type":"uint256"},{"internalType":"uint80","name":"answeredInRound","type":"uint80"}],"stateMutability":"view","type":"function"}
+]`
+
+// PriceFeed reads the price of a Chainlink price feed, i.e. the ETH / USD feed
+type PriceFeed struct {
+	caller ethereum.ContractCaller
+	addr   ethcommon.Address
+	abi    abi.ABI
+
+	mu       sync.Mutex
+	decimals *uint8
+}
+
+// NewPriceFeed creates a PriceFeed for the aggregator contract at addr
+func NewPriceFeed(caller ethereum.ContractCaller, addr ethcommon.Address) (*PriceFeed, error) {
+	parsed, err := abi.JSON(strings.NewReader(aggregatorV3ABI))
+	if err != nil {
+		return nil, err
+	}
+	return &PriceFeed{caller: caller, addr: addr, abi: parsed}, nil
+}
+
+// Price returns the latest price of the feed. Returns an error if the price is not positive or is stale
+func (f *PriceFeed) Price(ctx context.Context) (*big.Rat, error) {
+	decimals, err := f.getDecimals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := f.call(ctx, "latestRoundData")
+	if err != nil {
+		return nil, err
+	}
+	answer := out[1].(*big.Int)
+	updatedAt := out[3].(*big.Int)
+	if answer.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid price feed answer %v", answer)
+	}
+	if age := time.Since(time.Unix(updatedAt.Int64(), 0)); age > priceFeedMaxAge {
+		return nil, fmt.Errorf("stale price feed answer updated %v ago", age.Round(time.Second))
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Rat).SetFrac(answer, scale), nil
+}
+
+func (f *PriceFeed) getDecimals(ctx context.Context) (uint8, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.decimals != nil {
+		return *f.decimals, nil
+	}
+	out, err := f.call(ctx, "decimals")
+	if err != nil {
+		return 0, err
+	}
+	decimals := out[0].(uint8)
+	f.decimals = &decimals
+	return decimals, nil
+}
+
+func (f *PriceFeed) call(ctx context.Context, method string) ([]interface{}, error) {
+	data, err := f.abi.Pack(method)
+	if err != nil {
+		return nil, err
+	}
+	res, err := f.caller.CallContract(ctx, ethereum.CallMsg{To: &f.addr, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f.abi.Unpack(method, res)
+}
+
+type fiatPriceSource interface {
+	Price(ctx context.Context) (*big.Rat, error)
+}
+
+// FiatPriceUpdater keeps the price of the orchestrator at a fiat price per pixel. The fiat price is converted to wei
+// with the price of ETH in the fiat currency, i.e. of the ETH / USD price feed, and the price of the orchestrator is
+// only changed once the converted price drifts from it by more than the tolerance, so that the price is not changed
+// for every update of the feed
+type FiatPriceUpdater struct {
+	feed      fiatPriceSource
+	fiatPrice *big.Rat
+	tolerance float64
+	interval  time.Duration
+	setPrice  func(price *big.Rat)
+
+	// workerMu guards working and cancelWorker, which are set by Start and read by Stop from other goroutines
+	workerMu     sync.Mutex
+	working      bool
+	cancelWorker context.CancelFunc
+
+	mu    sync.Mutex
+	price *big.Rat
+}
+
+// NewFiatPriceUpdater creates a FiatPriceUpdater that sets the price of the orchestrator to fiatPrice per pixel with
+// setPrice whenever the price in wei drifts by more than tolerance, a fraction of the current price
+func NewFiatPriceUpdater(feed fiatPriceSource, fiatPrice *big.Rat, tolerance float64, interval time.Duration, setPrice func(price *big.Rat)) *FiatPriceUpdater {
+	return &FiatPriceUpdater{
+		feed:      feed,
+		fiatPrice: fiatPrice,
+		tolerance: tolerance,
+		interval:  interval,
+		setPrice:  setPrice,
+	}
+}
+
+// Start updates the price once and then checks the price feed every interval until ctx is done or Stop is called
+func (u *FiatPriceUpdater) Start(ctx context.Context) error {
+	u.workerMu.Lock()
+	if u.working {
+		u.workerMu.Unlock()
+		return ErrFiatPriceUpdaterStarted
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	u.cancelWorker = cancel
+	u.working = true
+	u.workerMu.Unlock()
+
+	defer func() {
+		u.workerMu.Lock()
+		u.working = false
+		u.workerMu.Unlock()
+		cancel()
+	}()
+
+	ticker := time.NewTicker(u.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.Update(cancelCtx); err != nil {
+			glog.Errorf("Unable to update fiat price err=%q", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-cancelCtx.Done():
+			glog.V(5).Infof("Fiat price updater done")
+			return nil
+		}
+	}
+}
+
+func (u *FiatPriceUpdater) Stop() error {
+	u.workerMu.Lock()
+	defer u.workerMu.Unlock()
+
+	if !u.working {
+		return ErrFiatPriceUpdaterStopped
+	}
+
+	u.cancelWorker()
+	u.working = false
+
+	return nil
+}
+
+// Price returns the price per pixel in wei that was last set, nil if it was not set yet
+func (u *FiatPriceUpdater) Price() *big.Rat {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.price == nil {
+		return nil
+	}
+	return new(big.Rat).Set(u.price)
+}
+
+// Update converts the fiat price to wei and sets it if it drifted by more than the tolerance from the current price
+func (u *FiatPriceUpdater) Update(ctx context.Context) error {
+	ethPrice, err := u.feed.Price(ctx)
+	if err != nil {
+		return err
+	}
+	if ethPrice.Sign() <= 0 {
+		return errors.New("invalid ETH price")
+	}
+	// wei per pixel = fiat per pixel / (fiat per ETH) * wei per ETH
+	price := new(big.Rat).Quo(u.fiatPrice, ethPrice)
+	price.Mul(price, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)))
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.price != nil && !priceDrifted(u.price, price, u.tolerance) {
+		return nil
+	}
+	u.setPrice(price)
+	glog.Infof("Set fiat price per pixel=%v wei ethPrice=%v", price.FloatString(3), ethPrice.FloatString(2))
+	u.price = price
+	return nil
+}
+
+// priceDrifted returns whether price differs from current by more than tolerance, a fraction of current
+func priceDrifted(current, price *big.Rat, tolerance float64) bool {
+	if current.Sign() == 0 {
+		return price.Sign() != 0
+	}
+	diff := new(big.Rat).Sub(price, current)
+	drift, _ := diff.Quo(diff, current).Float64()
+	return math.Abs(drift) > tolerance
+}
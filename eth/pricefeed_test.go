@@ -0,0 +1,150 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAggregator struct {
+	abi       abi.ABI
+	decimals  uint8
+	answer    *big.Int
+	updatedAt time.Time
+	err       error
+	calls     map[string]int
+}
+
+func newStubAggregator(t *testing.T) *stubAggregator {
+	parsed, err := abi.JSON(strings.NewReader(aggregatorV3ABI))
+	require.Nil(t, err)
+	return &stubAggregator{abi: parsed, decimals: 8, answer: big.NewInt(200000000000), updatedAt: time.Now(), calls: make(map[string]int)}
+}
+
+func (a *stubAggregator) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	method, err := a.abi.MethodById(call.Data)
+	if err != nil {
+		return nil, err
+	}
+	a.calls[method.Name]++
+	if method.Name == "decimals" {
+		return method.Outputs.Pack(a.decimals)
+	}
+	return method.Outputs.Pack(big.NewInt(1), a.answer, big.NewInt(0), big.NewInt(a.updatedAt.Unix()), big.NewInt(1))
+}
+
+func TestPriceFeed_Price(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	agg := newStubAggregator(t)
+	feed, err := NewPriceFeed(agg, ethcommon.HexToAddress("aaa"))
+	require.Nil(err)
+
+	price, err := feed.Price(context.Background())
+	require.Nil(err)
+	assert.Equal(big.NewRat(2000, 1), price)
+
+	// The decimals are only read once
+	_, err = feed.Price(context.Background())
+	require.Nil(err)
+	assert.Equal(1, agg.calls["decimals"])
+	assert.Equal(2, agg.calls["latestRoundData"])
+
+	agg.answer = big.NewInt(0)
+	_, err = feed.Price(context.Background())
+	assert.EqualError(err, "invalid price feed answer 0")
+
+	agg.answer = big.NewInt(200000000000)
+	agg.updatedAt = time.Now().Add(-2 * priceFeedMaxAge)
+	_, err = feed.Price(context.Background())
+	assert.Contains(err.Error(), "stale price feed answer")
+
+	agg.err = errors.New("call error")
+	_, err = feed.Price(context.Background())
+	assert.EqualError(err, "call error")
+}
+
+type stubFiatPriceSource struct {
+	price *big.Rat
+	err   error
+}
+
+func (s *stubFiatPriceSource) Price(ctx context.Context) (*big.Rat, error) {
+	return s.price, s.err
+}
+
+func TestFiatPriceUpdater_Update(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	feed := &stubFiatPriceSource{price: big.NewRat(2000, 1)}
+	var prices []*big.Rat
+	setPrice := func(price *big.Rat) { prices = append(prices, price) }
+	// 0.000000002 USD per pixel
+	u := NewFiatPriceUpdater(feed, big.NewRat(2, 1000000000), 0.05, time.Minute, setPrice)
+	assert.Nil(u.Price())
+
+	require.Nil(u.Update(context.Background()))
+	require.Len(prices, 1)
+	assert.Equal(big.NewRat(1000000, 1), prices[0])
+	assert.Equal(big.NewRat(1000000, 1), u.Price())
+
+	// The price is not changed while it drifts within the tolerance
+	feed.price = big.NewRat(2080, 1)
+	require.Nil(u.Update(context.Background()))
+	assert.Len(prices, 1)
+
+	feed.price = big.NewRat(1800, 1)
+	require.Nil(u.Update(context.Background()))
+	require.Len(prices, 2)
+	assert.Zero(big.NewRat(10000000, 9).Cmp(prices[1]))
+
+	feed.err = errors.New("feed error")
+	assert.EqualError(u.Update(context.Background()), "feed error")
+	assert.Len(prices, 2)
+}
+
+func TestFiatPriceUpdater_StartStop(t *testing.T) {
+	assert := assert.New(t)
+
+	feed := &stubFiatPriceSource{price: big.NewRat(2000, 1)}
+	set := make(chan *big.Rat, 1)
+	u := NewFiatPriceUpdater(feed, big.NewRat(1, 1000000000), 0.05, time.Minute, func(price *big.Rat) { set <- price })
+	assert.Equal(ErrFiatPriceUpdaterStopped, u.Stop())
+
+	errCh := make(chan error)
+	go func() { errCh <- u.Start(context.Background()) }()
+	select {
+	case price := <-set:
+		assert.Equal(big.NewRat(500000, 1), price)
+	case <-time.After(time.Second):
+		t.Fatal("price was not set on start")
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(ErrFiatPriceUpdaterStarted, u.Start(context.Background()))
+	assert.Nil(u.Stop())
+	assert.Nil(<-errCh)
+}
+
+func TestPriceDrifted(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(priceDrifted(big.NewRat(100, 1), big.NewRat(105, 1), 0.05))
+	assert.True(priceDrifted(big.NewRat(100, 1), big.NewRat(106, 1), 0.05))
+	assert.True(priceDrifted(big.NewRat(100, 1), big.NewRat(94, 1), 0.05))
+	assert.True(priceDrifted(big.NewRat(0, 1), big.NewRat(1, 1), 0.05))
+	assert.False(priceDrifted(big.NewRat(0, 1), big.NewRat(0, 1), 0.05))
+}
@@ -0,0 +1,73 @@
+package eth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/lperrors"
+)
+
+var ErrReadOnly = lperrors.User(errors.New("transacting is disabled for a read-only ETH account"))
+
+type readOnlyAccountManager struct {
+	account accounts.Account
+}
+
+// NewReadOnlyAccountManager creates an AccountManager that only knows the address of an account.
+// It can be used to query on-chain state for the account, but any attempt to sign will fail
+func NewReadOnlyAccountManager(accountAddr ethcommon.Address) (AccountManager, error) {
+	if (accountAddr == ethcommon.Address{}) {
+		return nil, errors.New("an ETH account address is required for read-only mode")
+	}
+
+	glog.Infof("Using read-only Ethereum account: %v", accountAddr.Hex())
+
+	return &readOnlyAccountManager{
+		account: accounts.Account{Address: accountAddr},
+	}, nil
+}
+
+// Unlock is a no-op because there is no key to unlock
+func (am *readOnlyAccountManager) Unlock(pass string) error {
+	return nil
+}
+
+// Lock is a no-op because there is no key to lock
+func (am *readOnlyAccountManager) Lock() error {
+	return nil
+}
+
+// CreateTransactOpts creates transact opts with a signer that always fails so that
+// contract sessions can still be bound for calls
+func (am *readOnlyAccountManager) CreateTransactOpts(gasLimit uint64) (*bind.TransactOpts, error) {
+	return &bind.TransactOpts{
+		From: am.account.Address,
+		Signer: func(ethcommon.Address, *types.Transaction) (*types.Transaction, error) {
+			return nil, ErrReadOnly
+		},
+		Context:  context.Background(),
+		GasLimit: gasLimit,
+	}, nil
+}
+
+func (am *readOnlyAccountManager) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	return nil, ErrReadOnly
+}
+
+func (am *readOnlyAccountManager) Sign(msg []byte) ([]byte, error) {
+	return nil, ErrReadOnly
+}
+
+func (am *readOnlyAccountManager) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	return nil, ErrReadOnly
+}
+
+func (am *readOnlyAccountManager) Account() accounts.Account {
+	return am.account
+}
@@ -0,0 +1,44 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyAccountManager_MissingAddress(t *testing.T) {
+	_, err := NewReadOnlyAccountManager(ethcommon.Address{})
+	assert.EqualError(t, err, "an ETH account address is required for read-only mode")
+}
+
+func TestReadOnlyAccountManager(t *testing.T) {
+	assert := assert.New(t)
+
+	addr := ethcommon.HexToAddress("0x1234")
+	am, err := NewReadOnlyAccountManager(addr)
+	require.Nil(t, err)
+
+	assert.Nil(am.Unlock(""))
+	assert.Nil(am.Lock())
+	assert.Equal(addr, am.Account().Address)
+
+	opts, err := am.CreateTransactOpts(100)
+	require.Nil(t, err)
+	assert.Equal(addr, opts.From)
+	assert.Equal(uint64(100), opts.GasLimit)
+
+	tx := types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(1)})
+	_, err = opts.Signer(addr, tx)
+	assert.Equal(ErrReadOnly, err)
+	_, err = am.SignTx(tx)
+	assert.Equal(ErrReadOnly, err)
+	_, err = am.Sign([]byte("foo"))
+	assert.Equal(ErrReadOnly, err)
+	_, err = am.SignTypedData(apitypes.TypedData{})
+	assert.Equal(ErrReadOnly, err)
+}
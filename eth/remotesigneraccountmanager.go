@@ -0,0 +1,217 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/lperrors"
+)
+
+var (
+	ErrRemoteSignerDenied  = lperrors.User(errors.New("request denied by remote signer"))
+	ErrRemoteSignerTimeout = lperrors.Retryable(errors.New("remote signer did not respond in time"))
+)
+
+// errRequestDenied is the error that Clef responds with when a request is rejected
+const errRequestDenied = "request denied"
+
+type remoteSignerAccountManager struct {
+	account  accounts.Account
+	chainID  *big.Int
+	client   *rpc.Client
+	timeout  time.Duration
+	unlocked bool
+}
+
+// signTransactionResult is the response of the account_signTransaction method of the remote signer
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// NewRemoteSignerAccountManager creates an AccountManager that signs with the account of an external signer (i.e. Clef)
+// at endpoint, which is a HTTP(S), WS(S) URL or an IPC path. The key of the account never has to be on the node. Each
+// request to the signer has to be approved, either manually or by the rules of the signer, within timeout
+func NewRemoteSignerAccountManager(accountAddr ethcommon.Address, endpoint string, chainID *big.Int, timeout time.Duration) (AccountManager, error) {
+	if (accountAddr == ethcommon.Address{}) {
+		return nil, errors.New("an ETH account address is required for a remote signer")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to remote signer endpoint=%v err=%q", endpoint, err)
+	}
+
+	glog.Infof("Using Ethereum account: %v", accountAddr.Hex())
+
+	return &remoteSignerAccountManager{
+		account: accounts.Account{Address: accountAddr},
+		chainID: chainID,
+		client:  client,
+		timeout: timeout,
+	}, nil
+}
+
+// Unlock checks that the remote signer manages the account. The passphrase is not used, the remote signer holds the
+// keys of its accounts
+func (am *remoteSignerAccountManager) Unlock(pass string) error {
+	var addrs []ethcommon.Address
+	if err := am.call(&addrs, "account_list"); err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		if addr == am.account.Address {
+			am.unlocked = true
+
+			glog.Infof("Unlocked ETH account: %v in remote signer", am.account.Address.Hex())
+
+			return nil
+		}
+	}
+
+	return ErrAccountNotFound
+}
+
+func (am *remoteSignerAccountManager) Lock() error {
+	am.unlocked = false
+	return nil
+}
+
+// CreateTransactOpts creates transact opts that sign with the remote signer - account must be unlocked
+func (am *remoteSignerAccountManager) CreateTransactOpts(gasLimit uint64) (*bind.TransactOpts, error) {
+	if !am.unlocked {
+		return nil, ErrLocked
+	}
+
+	return &bind.TransactOpts{
+		From: am.account.Address,
+		Signer: func(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != am.account.Address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return am.SignTx(tx)
+		},
+		Context:  context.Background(),
+		GasLimit: gasLimit,
+	}, nil
+}
+
+// Sign a transaction. Account must be unlocked
+func (am *remoteSignerAccountManager) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	if !am.unlocked {
+		return nil, ErrLocked
+	}
+
+	data := hexutil.Bytes(tx.Data())
+	var to *ethcommon.MixedcaseAddress
+	if tx.To() != nil {
+		t := ethcommon.NewMixedcaseAddress(*tx.To())
+		to = &t
+	}
+	args := &apitypes.SendTxArgs{
+		From:    ethcommon.NewMixedcaseAddress(am.account.Address),
+		To:      to,
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    &data,
+		ChainID: (*hexutil.Big)(am.chainID),
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+
+	var res signTransactionResult
+	if err := am.call(&res, "account_signTransaction", args); err != nil {
+		return nil, err
+	}
+
+	// Make sure that the remote signer signed the requested transaction with the account
+	if res.Tx == nil {
+		return nil, errors.New("remote signer did not return a signed transaction")
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(am.chainID), res.Tx)
+	if err != nil {
+		return nil, err
+	}
+	if sender != am.account.Address || res.Tx.Nonce() != tx.Nonce() {
+		return nil, fmt.Errorf("remote signer signed a different transaction sender=%v nonce=%v", sender.Hex(), res.Tx.Nonce())
+	}
+
+	return res.Tx, nil
+}
+
+// Sign byte array message. Account must be unlocked
+func (am *remoteSignerAccountManager) Sign(msg []byte) ([]byte, error) {
+	if !am.unlocked {
+		return nil, ErrLocked
+	}
+
+	var sig hexutil.Bytes
+	if err := am.call(&sig, "account_signData", accounts.MimetypeTextPlain, am.account.Address, hexutil.Encode(msg)); err != nil {
+		return nil, err
+	}
+
+	return toRecoverableSig(sig), nil
+}
+
+// SignTypedData signs EIP-712 typed data. Account must be unlocked
+func (am *remoteSignerAccountManager) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	if !am.unlocked {
+		return nil, ErrLocked
+	}
+
+	var sig hexutil.Bytes
+	if err := am.call(&sig, "account_signTypedData", am.account.Address, typedData); err != nil {
+		return nil, err
+	}
+
+	return toRecoverableSig(sig), nil
+}
+
+func (am *remoteSignerAccountManager) Account() accounts.Account {
+	return am.account
+}
+
+// call sends a request to the remote signer and waits for it to be approved for at most the timeout of the account
+// manager
+func (am *remoteSignerAccountManager) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), am.timeout)
+	defer cancel()
+
+	err := am.client.CallContext(ctx, result, method, args...)
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		glog.Errorf("Timed out waiting for remote signer method=%v timeout=%v", method, am.timeout)
+		return ErrRemoteSignerTimeout
+	}
+	if err.Error() == errRequestDenied {
+		return ErrRemoteSignerDenied
+	}
+	return err
+}
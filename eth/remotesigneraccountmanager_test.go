@@ -0,0 +1,212 @@
+package eth
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/livepeer/go-livepeer/crypto"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRemoteSigner serves the account API of Clef
+type stubRemoteSigner struct {
+	key       *ecdsa.PrivateKey
+	chainID   *big.Int
+	err       error
+	delay     time.Duration
+	typedData apitypes.TypedData
+}
+
+func (s *stubRemoteSigner) List() ([]ethcommon.Address, error) {
+	return []ethcommon.Address{pm.RandAddress(), ethcrypto.PubkeyToAddress(s.key.PublicKey)}, s.err
+}
+
+func (s *stubRemoteSigner) SignTransaction(args apitypes.SendTxArgs) (*signTransactionResult, error) {
+	time.Sleep(s.delay)
+	if s.err != nil {
+		return nil, s.err
+	}
+	tx, err := types.SignTx(args.ToTransaction(), types.LatestSignerForChainID(s.chainID), s.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &signTransactionResult{Raw: raw, Tx: tx}, nil
+}
+
+func (s *stubRemoteSigner) SignData(contentType string, addr ethcommon.MixedcaseAddress, data string) (hexutil.Bytes, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if contentType != accounts.MimetypeTextPlain {
+		return nil, errors.New("unsupported content type")
+	}
+	msg, err := hexutil.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := ethcrypto.Sign(accounts.TextHash(msg), s.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+func (s *stubRemoteSigner) SignTypedData(addr ethcommon.MixedcaseAddress, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.typedData = typedData
+	// Clef returns V as 27 or 28, but signatures with V as 0 or 1 are normalized
+	return ethcrypto.Sign(ethcrypto.Keccak256([]byte(typedData.PrimaryType)), s.key)
+}
+
+func newStubRemoteSigner(t *testing.T, chainID *big.Int) (*stubRemoteSigner, *httptest.Server) {
+	key, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+	signer := &stubRemoteSigner{key: key, chainID: chainID}
+
+	srv := rpc.NewServer()
+	require.Nil(t, srv.RegisterName("account", signer))
+	return signer, httptest.NewServer(srv)
+}
+
+func TestRemoteSignerAccountManager_Unlock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := big.NewInt(777)
+	signer, ts := newStubRemoteSigner(t, chainID)
+	defer ts.Close()
+	addr := ethcrypto.PubkeyToAddress(signer.key.PublicKey)
+
+	_, err := NewRemoteSignerAccountManager(ethcommon.Address{}, ts.URL, chainID, time.Second)
+	assert.EqualError(err, "an ETH account address is required for a remote signer")
+
+	// Account not managed by the remote signer
+	am, err := NewRemoteSignerAccountManager(pm.RandAddress(), ts.URL, chainID, time.Second)
+	require.Nil(err)
+	assert.Equal(ErrAccountNotFound, am.Unlock(""))
+
+	am, err = NewRemoteSignerAccountManager(addr, ts.URL, chainID, time.Second)
+	require.Nil(err)
+	assert.Equal(addr, am.Account().Address)
+
+	// Locked
+	_, err = am.CreateTransactOpts(100)
+	assert.Equal(ErrLocked, err)
+	_, err = am.SignTx(types.NewTx(&types.LegacyTx{}))
+	assert.Equal(ErrLocked, err)
+	_, err = am.Sign([]byte("foo"))
+	assert.Equal(ErrLocked, err)
+	_, err = am.SignTypedData(apitypes.TypedData{})
+	assert.Equal(ErrLocked, err)
+
+	// Listing the accounts is denied
+	signer.err = errors.New(errRequestDenied)
+	assert.Equal(ErrRemoteSignerDenied, am.Unlock(""))
+
+	signer.err = nil
+	require.Nil(am.Unlock(""))
+
+	assert.Nil(am.Lock())
+	_, err = am.Sign([]byte("foo"))
+	assert.Equal(ErrLocked, err)
+}
+
+func TestRemoteSignerAccountManager_Sign(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := big.NewInt(777)
+	signer, ts := newStubRemoteSigner(t, chainID)
+	defer ts.Close()
+	addr := ethcrypto.PubkeyToAddress(signer.key.PublicKey)
+
+	am, err := NewRemoteSignerAccountManager(addr, ts.URL, chainID, time.Second)
+	require.Nil(err)
+	require.Nil(am.Unlock(""))
+
+	sig, err := am.Sign([]byte("foo"))
+	assert.Nil(err)
+	assert.True(crypto.VerifySig(addr, []byte("foo"), sig))
+
+	sig, err = am.SignTypedData(apitypes.TypedData{PrimaryType: "Ticket"})
+	assert.Nil(err)
+	assert.Equal("Ticket", signer.typedData.PrimaryType)
+	assert.Len(sig, 65)
+	assert.Contains([]byte{27, 28}, sig[64])
+
+	to := pm.RandAddress()
+	txs := []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to}),
+		types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &to}),
+	}
+	for _, tx := range txs {
+		signedTx, err := am.SignTx(tx)
+		require.Nil(err)
+		assert.Equal(tx.Type(), signedTx.Type())
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+		assert.Nil(err)
+		assert.Equal(addr, sender)
+	}
+
+	opts, err := am.CreateTransactOpts(100)
+	require.Nil(err)
+	assert.Equal(addr, opts.From)
+	assert.Equal(uint64(100), opts.GasLimit)
+	_, err = opts.Signer(pm.RandAddress(), txs[0])
+	assert.Equal(bind.ErrNotAuthorized, err)
+	signedTx, err := opts.Signer(addr, txs[0])
+	require.Nil(err)
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	assert.Nil(err)
+	assert.Equal(addr, sender)
+
+	// Signing is denied
+	signer.err = errors.New(errRequestDenied)
+	_, err = am.SignTx(txs[0])
+	assert.Equal(ErrRemoteSignerDenied, err)
+	_, err = am.Sign([]byte("foo"))
+	assert.Equal(ErrRemoteSignerDenied, err)
+	signer.err = errors.New("unknown account")
+	_, err = am.SignTx(txs[0])
+	assert.EqualError(err, "unknown account")
+}
+
+func TestRemoteSignerAccountManager_Timeout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := big.NewInt(777)
+	signer, ts := newStubRemoteSigner(t, chainID)
+	defer ts.Close()
+	addr := ethcrypto.PubkeyToAddress(signer.key.PublicKey)
+
+	am, err := NewRemoteSignerAccountManager(addr, ts.URL, chainID, 50*time.Millisecond)
+	require.Nil(err)
+	require.Nil(am.Unlock(""))
+
+	// The request is not approved in time
+	signer.delay = 200 * time.Millisecond
+	_, err = am.SignTx(types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000}))
+	assert.Equal(ErrRemoteSignerTimeout, err)
+}
@@ -3,10 +3,13 @@ package eth
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/monitor"
 )
 
 var (
@@ -14,18 +17,34 @@ var (
 	ErrRewardServiceStopped = fmt.Errorf("reward service already stopped")
 )
 
+// rewardMaxRetries is the number of times that a failed reward call is retried within a round
+var rewardMaxRetries = 5
+
+// rewardRetryInterval is how long the reward service waits before retrying a failed reward call
+var rewardRetryInterval = 1 * time.Minute
+
+// RewardService calls reward once per round when the orchestrator is active. Failed reward calls, i.e. because of
+// transient RPC errors, are retried until the reward is called or the round ends. Rounds for which the orchestrator was
+// active but did not call reward are recorded as missed
 type RewardService struct {
 	client       LivepeerEthClient
 	working      bool
 	cancelWorker context.CancelFunc
 	tw           timeWatcher
 	mu           sync.Mutex
+
+	maxRetries    int
+	retryInterval time.Duration
+	// eligibleRound is the last round in which the orchestrator was active and had not called reward yet
+	eligibleRound *big.Int
 }
 
 func NewRewardService(client LivepeerEthClient, tw timeWatcher) *RewardService {
 	return &RewardService{
-		client: client,
-		tw:     tw,
+		client:        client,
+		tw:            tw,
+		maxRetries:    rewardMaxRetries,
+		retryInterval: rewardRetryInterval,
 	}
 }
 
@@ -54,9 +73,12 @@ func (s *RewardService) Start(ctx context.Context) error {
 			}
 		case <-roundSink:
 			go func() {
-				err := s.tryReward()
+				err := s.tryRewardWithRetry(cancelCtx)
 				if err != nil {
 					glog.Errorf("Error trying to call reward err=%q", err)
+					if monitor.Enabled {
+						monitor.RewardCallError()
+					}
 				}
 			}()
 		case <-cancelCtx.Done():
@@ -81,24 +103,56 @@ func (s *RewardService) IsWorking() bool {
 	return s.working
 }
 
-func (s *RewardService) tryReward() error {
+// tryRewardWithRetry tries to call reward and retries up to maxRetries times while the round has not ended. Each
+// retry checks LastRewardRound again so that reward is not called twice if a failed call was mined after all
+func (s *RewardService) tryRewardWithRetry(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	round := s.tw.LastInitializedRound()
+	err := s.tryReward(ctx)
+	for i := 0; err != nil && i < s.maxRetries; i++ {
+		glog.Errorf("Error trying to call reward, retrying in %v round=%v err=%q", s.retryInterval, round, err)
+		select {
+		case <-time.After(s.retryInterval):
+		case <-ctx.Done():
+			return err
+		}
+		if s.tw.LastInitializedRound().Cmp(round) != 0 {
+			// The reward for the next round is tried when its round event is received
+			return err
+		}
+		err = s.tryReward(ctx)
+	}
+	return err
+}
+
+func (s *RewardService) tryReward(ctx context.Context) error {
 	currentRound := s.tw.LastInitializedRound()
 
-	t, err := s.client.GetTranscoder(s.client.Account().Address)
+	t, err := s.client.GetTranscoder(ctx, s.client.Account().Address)
 	if err != nil {
 		return err
 	}
 
+	if s.eligibleRound != nil && s.eligibleRound.Cmp(currentRound) < 0 {
+		if t.LastRewardRound.Cmp(s.eligibleRound) < 0 {
+			glog.Errorf("Missed reward for round %v", s.eligibleRound)
+			if monitor.Enabled {
+				monitor.MissedRewardRound()
+			}
+		}
+		s.eligibleRound = nil
+	}
+
 	if t.LastRewardRound.Cmp(currentRound) == -1 && t.Active {
-		tx, err := s.client.Reward()
+		s.eligibleRound = currentRound
+		tx, err := s.client.Reward(ctx)
 		if err != nil {
 			return err
 		}
 
-		if err := s.client.CheckTx(tx); err != nil {
+		if err := s.client.CheckTx(ctx, tx); err != nil {
 			return err
 		}
 
@@ -2,6 +2,7 @@ package eth
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/golang/glog"
 	lpTypes "github.com/livepeer/go-livepeer/eth/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -125,3 +127,89 @@ func TestRewardService_ReceiveRoundEvent_TryReward(t *testing.T) {
 	assert.Equal(int64(1), errorLogsAfter-errorLogsBefore)
 	assert.Equal(int64(0), infoLogsAfter-infoLogsBefore)
 }
+
+func TestRewardService_TryRewardWithRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &MockClient{}
+	tw := &stubTimeWatcher{
+		lastInitializedRound: big.NewInt(100),
+	}
+	rs := NewRewardService(client, tw)
+	rs.retryInterval = 10 * time.Millisecond
+	rs.maxRetries = 2
+
+	client.On("Account").Return(accounts.Account{})
+	client.On("GetTranscoder").Return(&lpTypes.Transcoder{
+		LastRewardRound: big.NewInt(99),
+		Active:          true,
+	}, nil)
+
+	// Transient error is retried
+	client.On("Reward").Return(nil, errors.New("connection refused")).Once()
+	client.On("Reward").Return(&types.Transaction{}, nil).Once()
+	client.On("CheckTx").Return(nil).Once()
+	assert.Nil(rs.tryRewardWithRetry(context.Background()))
+	client.AssertNumberOfCalls(t, "Reward", 2)
+	client.AssertNumberOfCalls(t, "CheckTx", 1)
+
+	// Retries are limited
+	client.On("Reward").Return(nil, errors.New("connection refused")).Times(3)
+	assert.EqualError(rs.tryRewardWithRetry(context.Background()), "connection refused")
+	client.AssertNumberOfCalls(t, "Reward", 5)
+
+	// Retries stop once the round ends
+	client.On("Reward").Return(nil, errors.New("connection refused")).Run(func(args mock.Arguments) {
+		tw.lastInitializedRound = big.NewInt(101)
+	}).Once()
+	assert.EqualError(rs.tryRewardWithRetry(context.Background()), "connection refused")
+	client.AssertNumberOfCalls(t, "Reward", 6)
+
+	// Retries stop once the service stops
+	ctx, cancel := context.WithCancel(context.Background())
+	client.On("Reward").Return(nil, errors.New("connection refused")).Run(func(args mock.Arguments) {
+		cancel()
+	}).Once()
+	assert.EqualError(rs.tryRewardWithRetry(ctx), "connection refused")
+	client.AssertNumberOfCalls(t, "Reward", 7)
+}
+
+func TestRewardService_MissedRewardRound(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &MockClient{}
+	tw := &stubTimeWatcher{
+		lastInitializedRound: big.NewInt(100),
+	}
+	rs := NewRewardService(client, tw)
+	client.On("Account").Return(accounts.Account{})
+
+	// Not active
+	client.On("GetTranscoder").Return(&lpTypes.Transcoder{LastRewardRound: big.NewInt(99)}, nil).Once()
+	assert.Nil(rs.tryReward(context.Background()))
+	assert.Nil(rs.eligibleRound)
+
+	// Active but the reward call fails
+	client.On("GetTranscoder").Return(&lpTypes.Transcoder{LastRewardRound: big.NewInt(99), Active: true}, nil).Once()
+	client.On("Reward").Return(nil, errors.New("connection refused")).Once()
+	assert.EqualError(rs.tryReward(context.Background()), "connection refused")
+	assert.Equal(big.NewInt(100), rs.eligibleRound)
+
+	// The missed round is recorded in the next round
+	tw.lastInitializedRound = big.NewInt(101)
+	client.On("GetTranscoder").Return(&lpTypes.Transcoder{LastRewardRound: big.NewInt(99), Active: true}, nil).Once()
+	client.On("Reward").Return(&types.Transaction{}, nil).Once()
+	client.On("CheckTx").Return(nil).Once()
+	errorLogsBefore := glog.Stats.Error.Lines()
+	assert.Nil(rs.tryReward(context.Background()))
+	assert.Equal(int64(1), glog.Stats.Error.Lines()-errorLogsBefore)
+	assert.Equal(big.NewInt(101), rs.eligibleRound)
+
+	// Rewarded rounds are not recorded as missed
+	tw.lastInitializedRound = big.NewInt(102)
+	client.On("GetTranscoder").Return(&lpTypes.Transcoder{LastRewardRound: big.NewInt(101)}, nil).Once()
+	errorLogsBefore = glog.Stats.Error.Lines()
+	assert.Nil(rs.tryReward(context.Background()))
+	assert.Equal(int64(0), glog.Stats.Error.Lines()-errorLogsBefore)
+	assert.Nil(rs.eligibleRound)
+}
@@ -1,6 +1,7 @@
 package eth
 
 import (
+	"context"
 	"math/big"
 	"sync"
 
@@ -56,12 +57,16 @@ func (r *RoundInitializer) Start() error {
 	roundSub := r.tw.SubscribeRounds(roundSink)
 	defer roundSub.Unsubscribe()
 
-	roundLength, err := r.client.RoundLength()
+	// Pending calls and round initialization txs are abandoned when the loop exits
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	roundLength, err := r.client.RoundLength(ctx)
 	if err != nil {
 		return err
 	}
 
-	currentRoundStartL1Block, err := r.client.CurrentRoundStartBlock()
+	currentRoundStartL1Block, err := r.client.CurrentRoundStartBlock(ctx)
 	if err != nil {
 		return err
 	}
@@ -86,7 +91,7 @@ func (r *RoundInitializer) Start() error {
 		case l1Block := <-l1BlockSink:
 			if l1Block.Cmp(r.nextRoundStartL1Block) >= 0 {
 				go func() {
-					if err := r.tryInitialize(); err != nil {
+					if err := r.tryInitialize(ctx); err != nil {
 						glog.Error(err)
 					}
 				}()
@@ -100,7 +105,7 @@ func (r *RoundInitializer) Stop() {
 	close(r.quit)
 }
 
-func (r *RoundInitializer) tryInitialize() error {
+func (r *RoundInitializer) tryInitialize(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -109,7 +114,7 @@ func (r *RoundInitializer) tryInitialize() error {
 
 	epochSeed := r.currentEpochSeed(currentL1Blk, r.nextRoundStartL1Block, lastInitializedL1BlkHash)
 
-	ok, err := r.shouldInitialize(epochSeed)
+	ok, err := r.shouldInitialize(ctx, epochSeed)
 	if err != nil {
 		return err
 	}
@@ -123,12 +128,12 @@ func (r *RoundInitializer) tryInitialize() error {
 
 	glog.Infof("New round - preparing to initialize round to join active set, current round is %d", currentRound)
 
-	tx, err := r.client.InitializeRound()
+	tx, err := r.client.InitializeRound(ctx)
 	if err != nil {
 		return err
 	}
 
-	if err := r.client.CheckTx(tx); err != nil {
+	if err := r.client.CheckTx(ctx, tx); err != nil {
 		return err
 	}
 
@@ -137,8 +142,8 @@ func (r *RoundInitializer) tryInitialize() error {
 	return nil
 }
 
-func (r *RoundInitializer) shouldInitialize(epochSeed *big.Int) (bool, error) {
-	transcoders, err := r.client.TranscoderPool()
+func (r *RoundInitializer) shouldInitialize(ctx context.Context, epochSeed *big.Int) (bool, error) {
+	transcoders, err := r.client.TranscoderPool(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -1,6 +1,7 @@
 package eth
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"testing"
@@ -54,13 +55,13 @@ func TestRoundInitializer_ShouldInitialize(t *testing.T) {
 	expErr := errors.New("TranscoderPool error")
 	client.On("TranscoderPool").Return(nil, expErr).Once()
 
-	ok, err := initializer.shouldInitialize(nil)
+	ok, err := initializer.shouldInitialize(context.Background(), nil)
 	assert.EqualError(err, expErr.Error())
 	assert.False(ok)
 
 	// Test active set is empty because no registered transcoders
 	client.On("TranscoderPool").Return([]*lpTypes.Transcoder{}, nil).Once()
-	ok, err = initializer.shouldInitialize(nil)
+	ok, err = initializer.shouldInitialize(context.Background(), nil)
 	assert.Nil(err)
 	assert.False(ok)
 
@@ -74,7 +75,7 @@ func TestRoundInitializer_ShouldInitialize(t *testing.T) {
 	}
 	client.On("TranscoderPool").Return(registered, nil).Once()
 
-	ok, err = initializer.shouldInitialize(nil)
+	ok, err = initializer.shouldInitialize(context.Background(), nil)
 	assert.Nil(err)
 	assert.False(ok)
 
@@ -83,13 +84,13 @@ func TestRoundInitializer_ShouldInitialize(t *testing.T) {
 	client.On("TranscoderPool").Return(registered, nil)
 
 	seed := big.NewInt(3)
-	ok, err = initializer.shouldInitialize(seed)
+	ok, err = initializer.shouldInitialize(context.Background(), seed)
 	assert.Nil(err)
 	assert.False(ok)
 
 	// Test caller selected
 	seed = big.NewInt(5)
-	ok, err = initializer.shouldInitialize(seed)
+	ok, err = initializer.shouldInitialize(context.Background(), seed)
 	assert.Nil(err)
 	assert.True(ok)
 }
@@ -109,7 +110,7 @@ func TestRoundInitializer_TryInitialize(t *testing.T) {
 	expErr := errors.New("shouldInitialize error")
 	client.On("TranscoderPool").Return(nil, expErr).Once()
 
-	err := initializer.tryInitialize()
+	err := initializer.tryInitialize(context.Background())
 	assert.EqualError(err, expErr.Error())
 
 	// Test should not initialize
@@ -121,7 +122,7 @@ func TestRoundInitializer_TryInitialize(t *testing.T) {
 	}
 	client.On("TranscoderPool").Return(registered, nil).Once()
 
-	err = initializer.tryInitialize()
+	err = initializer.tryInitialize(context.Background())
 	assert.Nil(err)
 
 	// Test error when submitting initialization tx
@@ -130,7 +131,7 @@ func TestRoundInitializer_TryInitialize(t *testing.T) {
 	expErr = errors.New("InitializeRound error")
 	client.On("InitializeRound").Return(nil, expErr).Once()
 
-	err = initializer.tryInitialize()
+	err = initializer.tryInitialize(context.Background())
 	assert.EqualError(err, expErr.Error())
 
 	// Test error checking initialization tx
@@ -139,13 +140,13 @@ func TestRoundInitializer_TryInitialize(t *testing.T) {
 	expErr = errors.New("CheckTx error")
 	client.On("CheckTx", mock.Anything).Return(expErr).Once()
 
-	err = initializer.tryInitialize()
+	err = initializer.tryInitialize(context.Background())
 	assert.EqualError(err, expErr.Error())
 
 	// Test success
 	client.On("CheckTx", mock.Anything).Return(nil)
 
-	err = initializer.tryInitialize()
+	err = initializer.tryInitialize(context.Background())
 	assert.Nil(err)
 }
 
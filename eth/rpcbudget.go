@@ -0,0 +1,197 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/monitor"
+)
+
+// rpcThrottleThreshold is the fraction of the RPC call limit after which non-critical calls are throttled
+var rpcThrottleThreshold = 0.8
+
+// criticalRPCMethods are never throttled because delaying them could cause transactions to be
+// submitted late or their confirmations to be missed
+var criticalRPCMethods = map[string]bool{
+	"eth_chainId":               true,
+	"eth_sendRawTransaction":    true,
+	"eth_getTransactionReceipt": true,
+	"eth_getTransactionByHash":  true,
+	"eth_getTransactionCount":   true,
+}
+
+type criticalRPCKey struct{}
+
+// WithCriticalRPC marks the calls made with ctx as critical so that they are never throttled. It is used for the calls
+// made to build, submit and confirm a tx, i.e. the gas estimate and price, which are also made by other callers
+func WithCriticalRPC(ctx context.Context) context.Context {
+	return context.WithValue(ctx, criticalRPCKey{}, true)
+}
+
+func isCriticalRPC(ctx context.Context) bool {
+	critical, _ := ctx.Value(criticalRPCKey{}).(bool)
+	return critical
+}
+
+// RPCBudget tracks the JSON-RPC calls made to an ETH node provider against the provider's rate limit.
+// Once the calls in the current window approach the limit, non-critical calls (i.e. polling for blocks and logs or
+// reading contract state) are delayed until the next window while critical calls (i.e. building and submitting
+// transactions and checking receipts) go through
+type RPCBudget struct {
+	limit  int
+	window time.Duration
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowCalls int
+	throttled   bool
+	counts      map[string]uint64
+}
+
+// NewRPCBudget creates an RPCBudget allowing limit calls per window
+func NewRPCBudget(limit int, window time.Duration) *RPCBudget {
+	return &RPCBudget{
+		limit:       limit,
+		window:      window,
+		windowStart: time.Now(),
+		counts:      make(map[string]uint64),
+	}
+}
+
+// Wait records calls to methods, blocking first until the next window if the calls are not critical and the
+// current window is close to the limit. Calls are critical if ctx is marked with WithCriticalRPC or one of the
+// methods is always critical
+func (b *RPCBudget) Wait(ctx context.Context, methods []string) error {
+	critical := isCriticalRPC(ctx)
+	for _, m := range methods {
+		if criticalRPCMethods[m] {
+			critical = true
+			break
+		}
+	}
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		if now.Sub(b.windowStart) >= b.window {
+			b.windowStart = now
+			b.windowCalls = 0
+			b.throttled = false
+		}
+
+		// A batch larger than the threshold is let through at the start of a window so that it does not wait forever
+		if critical || b.windowCalls == 0 || float64(b.windowCalls+len(methods)) <= float64(b.limit)*rpcThrottleThreshold {
+			b.windowCalls += len(methods)
+			for _, m := range methods {
+				b.counts[m]++
+			}
+			b.mu.Unlock()
+
+			if monitor.Enabled {
+				for _, m := range methods {
+					monitor.EthRPCCall(m)
+				}
+			}
+			return nil
+		}
+
+		if !b.throttled {
+			b.throttled = true
+			glog.Warningf("Throttling non-critical ETH RPC calls calls=%v limit=%v window=%v", b.windowCalls, b.limit, b.window)
+		}
+		wait := b.windowStart.Add(b.window).Sub(now)
+		b.mu.Unlock()
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Counts returns the total number of calls made per JSON-RPC method
+func (b *RPCBudget) Counts() map[string]uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	counts := make(map[string]uint64, len(b.counts))
+	for m, c := range b.counts {
+		counts[m] = c
+	}
+	return counts
+}
+
+// RoundTripper wraps next so that every JSON-RPC request sent through it is accounted for by the budget
+func (b *RPCBudget) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return &rpcBudgetTransport{budget: b, next: next}
+}
+
+type rpcBudgetTransport struct {
+	budget *RPCBudget
+	next   http.RoundTripper
+}
+
+func (t *rpcBudgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		if err := t.budget.Wait(req.Context(), rpcMethods(body)); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// rpcMethods returns the methods of a single or batch JSON-RPC request
+func rpcMethods(body []byte) []string {
+	type rpcRequest struct {
+		Method string `json:"method"`
+	}
+
+	var batch []rpcRequest
+	if err := json.Unmarshal(body, &batch); err != nil {
+		var single rpcRequest
+		if err := json.Unmarshal(body, &single); err != nil {
+			return []string{"unknown"}
+		}
+		batch = []rpcRequest{single}
+	}
+
+	methods := make([]string, 0, len(batch))
+	for _, r := range batch {
+		methods = append(methods, r.Method)
+	}
+	return methods
+}
+
+// DialRPC connects to the ETH node at rawurl. If a budget is provided and the node is reached over HTTP,
+// all calls are accounted for by the budget
+func DialRPC(rawurl string, budget *RPCBudget) (*rpc.Client, error) {
+	if budget == nil {
+		return rpc.Dial(rawurl)
+	}
+
+	if !strings.HasPrefix(rawurl, "http://") && !strings.HasPrefix(rawurl, "https://") {
+		glog.Warningf("ETH RPC call budgeting is only supported for HTTP endpoints, calls to %v will not be budgeted", rawurl)
+		return rpc.Dial(rawurl)
+	}
+
+	return rpc.DialHTTPWithClient(rawurl, &http.Client{
+		Transport: budget.RoundTripper(http.DefaultTransport),
+	})
+}
@@ -0,0 +1,97 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPCMethods(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"eth_blockNumber"}, rpcMethods([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`)))
+	assert.Equal([]string{"eth_call", "eth_getLogs"}, rpcMethods([]byte(`[{"method":"eth_call"},{"method":"eth_getLogs"}]`)))
+	assert.Equal([]string{"unknown"}, rpcMethods([]byte(`foo`)))
+}
+
+func TestRPCBudget_ThrottlesNonCritical(t *testing.T) {
+	assert := assert.New(t)
+
+	window := 200 * time.Millisecond
+	b := NewRPCBudget(5, window)
+	ctx := context.Background()
+
+	// 4 calls (80% of the limit) go through immediately
+	for i := 0; i < 4; i++ {
+		assert.Nil(b.Wait(ctx, []string{"eth_getLogs"}))
+	}
+
+	// Critical calls are never throttled
+	start := time.Now()
+	assert.Nil(b.Wait(ctx, []string{"eth_sendRawTransaction"}))
+	assert.Nil(b.Wait(ctx, []string{"eth_getTransactionReceipt"}))
+	// and neither are the calls made to build a tx
+	assert.Nil(b.Wait(WithCriticalRPC(ctx), []string{"eth_call", "eth_estimateGas"}))
+	assert.True(time.Since(start) < window/2)
+
+	// Non-critical calls wait for the next window, including contract reads of other callers
+	assert.Nil(b.Wait(ctx, []string{"eth_call"}))
+	assert.True(time.Since(start) >= window/2)
+
+	assert.Equal(1, b.windowCalls)
+	assert.Equal(map[string]uint64{
+		"eth_getLogs":               4,
+		"eth_sendRawTransaction":    1,
+		"eth_getTransactionReceipt": 1,
+		"eth_call":                  2,
+		"eth_estimateGas":           1,
+	}, b.Counts())
+
+	// Waiting respects context cancellation
+	for i := 0; i < 3; i++ {
+		assert.Nil(b.Wait(ctx, []string{"eth_getLogs"}))
+	}
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(context.Canceled, b.Wait(cancelCtx, []string{"eth_getLogs"}))
+}
+
+func TestRPCBudget_RoundTripper(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var received string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	defer ts.Close()
+
+	b := NewRPCBudget(100, time.Second)
+	c, err := DialRPC(ts.URL, b)
+	require.Nil(err)
+	defer c.Close()
+
+	var res string
+	require.Nil(c.Call(&res, "eth_chainId"))
+	assert.Equal("0x1", res)
+	// Request body is forwarded unchanged
+	assert.Contains(received, `"method":"eth_chainId"`)
+	assert.Equal(map[string]uint64{"eth_chainId": 1}, b.Counts())
+
+	// The critical mark of the context of a call reaches the budget
+	b.limit = 1
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Nil(c.CallContext(WithCriticalRPC(ctx), &res, "eth_call"))
+	assert.True(errors.Is(c.CallContext(ctx, &res, "eth_call"), context.DeadlineExceeded))
+}
@@ -0,0 +1,792 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	lpTypes "github.com/livepeer/go-livepeer/eth/types"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/pkg/errors"
+)
+
+// Protocol parameters of the SimulatedClient
+var (
+	SimRoundLength        = big.NewInt(5760)
+	SimRoundLockAmount    = big.NewInt(100000)
+	SimUnbondingPeriod    = uint64(7)
+	SimUnlockPeriod       = big.NewInt(2)
+	SimTranscoderPoolSize = big.NewInt(100)
+	SimInflation          = big.NewInt(137)
+	SimInflationChange    = big.NewInt(3)
+	SimTargetBondingRate  = big.NewInt(500000)
+)
+
+// simTicketValidator checks redeemed tickets like the ticket broker, which recovers the signer of the ticket hash with
+// the same message prefix as DefaultSigVerifier
+var simTicketValidator = pm.NewValidator(&pm.DefaultSigVerifier{}, nil)
+
+// SimulatedClient is an in-memory LivepeerEthClient that keeps the state of the token, bonding, rounds, service
+// registry and ticket broker contracts without a chain, so that the orchestrator and broadcaster logic can run without
+// an RPC endpoint. Transactions are applied when they are sent and CheckTx always succeeds. ETH balances are not
+// simulated, so deposits and reserves are funded without spending ETH. Methods that are not simulated are served by
+// the embedded StubClient
+type SimulatedClient struct {
+	*StubClient
+
+	account ethcommon.Address
+	state   *simState
+}
+
+type simState struct {
+	mu sync.Mutex
+
+	nonce            uint64
+	round            *big.Int
+	initializedRound *big.Int
+
+	balances    map[ethcommon.Address]*big.Int
+	allowances  map[ethcommon.Address]map[ethcommon.Address]*big.Int
+	serviceURIs map[ethcommon.Address]string
+
+	transcoders map[ethcommon.Address]*lpTypes.Transcoder
+	delegators  map[ethcommon.Address]*lpTypes.Delegator
+	locks       map[ethcommon.Address][]*lpTypes.UnbondingLock
+
+	senders     map[ethcommon.Address]*pm.SenderInfo
+	claimed     map[ethcommon.Address]map[ethcommon.Address]*big.Int
+	usedTickets map[ethcommon.Hash]bool
+}
+
+// NewSimulatedClient returns a SimulatedClient that sends transactions from account. The chain starts at round 1,
+// which is initialized, and all balances are 0
+func NewSimulatedClient(account ethcommon.Address) *SimulatedClient {
+	return &SimulatedClient{
+		StubClient: &StubClient{TranscoderAddress: account},
+		account:    account,
+		state: &simState{
+			round:            big.NewInt(1),
+			initializedRound: big.NewInt(1),
+			balances:         make(map[ethcommon.Address]*big.Int),
+			allowances:       make(map[ethcommon.Address]map[ethcommon.Address]*big.Int),
+			serviceURIs:      make(map[ethcommon.Address]string),
+			transcoders:      make(map[ethcommon.Address]*lpTypes.Transcoder),
+			delegators:       make(map[ethcommon.Address]*lpTypes.Delegator),
+			locks:            make(map[ethcommon.Address][]*lpTypes.UnbondingLock),
+			senders:          make(map[ethcommon.Address]*pm.SenderInfo),
+			claimed:          make(map[ethcommon.Address]map[ethcommon.Address]*big.Int),
+			usedTickets:      make(map[ethcommon.Hash]bool),
+		},
+	}
+}
+
+// Mint credits amount tokens to addr
+func (c *SimulatedClient) Mint(addr ethcommon.Address, amount *big.Int) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	c.state.credit(addr, amount)
+}
+
+// AdvanceRound moves the chain to the next round, which is not initialized until InitializeRound is called
+func (c *SimulatedClient) AdvanceRound() {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	c.state.round = new(big.Int).Add(c.state.round, big.NewInt(1))
+}
+
+// tx returns a transaction for the next nonce. Expects the mutex `s.mu` to be locked by the caller
+func (s *simState) tx() *types.Transaction {
+	tx := types.NewTx(&types.LegacyTx{Nonce: s.nonce})
+	s.nonce++
+	return tx
+}
+
+func (s *simState) balance(addr ethcommon.Address) *big.Int {
+	if b, ok := s.balances[addr]; ok {
+		return b
+	}
+	return big.NewInt(0)
+}
+
+func (s *simState) credit(addr ethcommon.Address, amount *big.Int) {
+	s.balances[addr] = new(big.Int).Add(s.balance(addr), amount)
+}
+
+func (s *simState) debit(addr ethcommon.Address, amount *big.Int) error {
+	b := s.balance(addr)
+	if b.Cmp(amount) < 0 {
+		return fmt.Errorf("insufficient balance addr=%v balance=%v amount=%v", addr.Hex(), b, amount)
+	}
+	s.balances[addr] = new(big.Int).Sub(b, amount)
+	return nil
+}
+
+func (s *simState) delegator(addr ethcommon.Address) *lpTypes.Delegator {
+	d, ok := s.delegators[addr]
+	if !ok {
+		d = &lpTypes.Delegator{
+			Address:             addr,
+			BondedAmount:        big.NewInt(0),
+			Fees:                big.NewInt(0),
+			DelegatedAmount:     big.NewInt(0),
+			StartRound:          big.NewInt(0),
+			LastClaimRound:      big.NewInt(0),
+			NextUnbondingLockId: big.NewInt(0),
+			Status:              "Unbonded",
+		}
+		s.delegators[addr] = d
+	}
+	return d
+}
+
+func (s *simState) sender(addr ethcommon.Address) *pm.SenderInfo {
+	info, ok := s.senders[addr]
+	if !ok {
+		info = &pm.SenderInfo{
+			Deposit:       big.NewInt(0),
+			WithdrawRound: big.NewInt(0),
+			Reserve:       &pm.ReserveInfo{FundsRemaining: big.NewInt(0), ClaimedInCurrentRound: big.NewInt(0)},
+		}
+		s.senders[addr] = info
+	}
+	return info
+}
+
+// delegate moves amount of the stake delegated to from from to to
+func (s *simState) delegate(from, to ethcommon.Address, amount *big.Int) {
+	if t, ok := s.transcoders[from]; ok {
+		t.DelegatedStake = new(big.Int).Sub(t.DelegatedStake, amount)
+	}
+	if d, ok := s.delegators[from]; ok && !IsNullAddress(from) {
+		d.DelegatedAmount = new(big.Int).Sub(d.DelegatedAmount, amount)
+	}
+	if IsNullAddress(to) {
+		return
+	}
+	if t, ok := s.transcoders[to]; ok {
+		t.DelegatedStake = new(big.Int).Add(t.DelegatedStake, amount)
+	}
+	d := s.delegator(to)
+	d.DelegatedAmount = new(big.Int).Add(d.DelegatedAmount, amount)
+}
+
+// pool returns the registered transcoders sorted by their delegated stake
+func (s *simState) pool() []*lpTypes.Transcoder {
+	var pool []*lpTypes.Transcoder
+	for _, t := range s.transcoders {
+		cp := *t
+		cp.ServiceURI = s.serviceURIs[t.Address]
+		cp.Active = cp.DelegatedStake.Sign() > 0
+		pool = append(pool, &cp)
+	}
+	sort.Slice(pool, func(i, j int) bool {
+		if c := pool[i].DelegatedStake.Cmp(pool[j].DelegatedStake); c != 0 {
+			return c > 0
+		}
+		return pool[i].Address.Hex() < pool[j].Address.Hex()
+	})
+	if len(pool) > int(SimTranscoderPoolSize.Int64()) {
+		pool = pool[:SimTranscoderPoolSize.Int64()]
+	}
+	return pool
+}
+
+// Accounts
+
+func (c *SimulatedClient) Account() accounts.Account {
+	return accounts.Account{Address: c.account}
+}
+
+func (c *SimulatedClient) Accounts() []accounts.Account {
+	return []accounts.Account{c.Account()}
+}
+
+func (c *SimulatedClient) WithAccount(addr ethcommon.Address) (LivepeerEthClient, error) {
+	return &SimulatedClient{StubClient: &StubClient{TranscoderAddress: addr}, account: addr, state: c.state}, nil
+}
+
+func (c *SimulatedClient) SetAccount(addr ethcommon.Address) error {
+	c.account = addr
+	c.StubClient.TranscoderAddress = addr
+	return nil
+}
+
+func (c *SimulatedClient) WithGasFees(fees GasFees) LivepeerEthClient { return c }
+
+func (c *SimulatedClient) CheckTx(ctx context.Context, tx *types.Transaction) error { return nil }
+
+// Rounds
+
+func (c *SimulatedClient) InitializeRound(ctx context.Context) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if c.state.initializedRound.Cmp(c.state.round) == 0 {
+		return nil, errors.New("current round is already initialized")
+	}
+	c.state.initializedRound = new(big.Int).Set(c.state.round)
+	for _, info := range c.state.senders {
+		info.Reserve.ClaimedInCurrentRound = big.NewInt(0)
+	}
+	c.state.claimed = make(map[ethcommon.Address]map[ethcommon.Address]*big.Int)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) CurrentRound(ctx context.Context) (*big.Int, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	return new(big.Int).Set(c.state.round), nil
+}
+
+func (c *SimulatedClient) LastInitializedRound(ctx context.Context) (*big.Int, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	return new(big.Int).Set(c.state.initializedRound), nil
+}
+
+func (c *SimulatedClient) CurrentRoundInitialized(ctx context.Context) (bool, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	return c.state.initializedRound.Cmp(c.state.round) == 0, nil
+}
+
+func (c *SimulatedClient) CurrentRoundStartBlock(ctx context.Context) (*big.Int, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	return new(big.Int).Mul(c.state.round, SimRoundLength), nil
+}
+
+// Token
+
+func (c *SimulatedClient) Transfer(ctx context.Context, toAddr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if err := c.state.debit(c.account, amount); err != nil {
+		return nil, err
+	}
+	c.state.credit(toAddr, amount)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) Allowance(ctx context.Context, owner ethcommon.Address, spender ethcommon.Address) (*big.Int, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if a, ok := c.state.allowances[owner][spender]; ok {
+		return new(big.Int).Set(a), nil
+	}
+	return big.NewInt(0), nil
+}
+
+func (c *SimulatedClient) Approve(ctx context.Context, spender ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if c.state.allowances[c.account] == nil {
+		c.state.allowances[c.account] = make(map[ethcommon.Address]*big.Int)
+	}
+	c.state.allowances[c.account][spender] = new(big.Int).Set(amount)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) BalanceOf(ctx context.Context, addr ethcommon.Address) (*big.Int, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	return new(big.Int).Set(c.state.balance(addr)), nil
+}
+
+func (c *SimulatedClient) TotalSupply(ctx context.Context) (*big.Int, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	total := big.NewInt(0)
+	for _, b := range c.state.balances {
+		total.Add(total, b)
+	}
+	for _, d := range c.state.delegators {
+		total.Add(total, d.BondedAmount)
+	}
+	for _, locks := range c.state.locks {
+		for _, lock := range locks {
+			total.Add(total, lock.Amount)
+		}
+	}
+	return total, nil
+}
+
+func (c *SimulatedClient) GetGlobalTotalSupply(ctx context.Context) (*big.Int, error) {
+	return c.TotalSupply(ctx)
+}
+
+// Service Registry
+
+func (c *SimulatedClient) SetServiceURI(ctx context.Context, serviceURI string) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	c.state.serviceURIs[c.account] = serviceURI
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) GetServiceURI(ctx context.Context, addr ethcommon.Address) (string, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	return c.state.serviceURIs[addr], nil
+}
+
+// Staking
+
+func (c *SimulatedClient) Transcoder(ctx context.Context, blockRewardCut, feeShare *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	t, ok := c.state.transcoders[c.account]
+	if !ok {
+		stake := big.NewInt(0)
+		if d, ok := c.state.delegators[c.account]; ok {
+			stake = new(big.Int).Set(d.DelegatedAmount)
+		}
+		t = &lpTypes.Transcoder{
+			Address:                    c.account,
+			LastRewardRound:            big.NewInt(0),
+			DelegatedStake:             stake,
+			ActivationRound:            new(big.Int).Add(c.state.round, big.NewInt(1)),
+			DeactivationRound:          big.NewInt(0),
+			LastActiveStakeUpdateRound: big.NewInt(0),
+			Status:                     "Registered",
+		}
+		c.state.transcoders[c.account] = t
+	}
+	t.RewardCut = new(big.Int).Set(blockRewardCut)
+	t.FeeShare = new(big.Int).Set(feeShare)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) Bond(ctx context.Context, amount *big.Int, toAddr ethcommon.Address) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if err := c.state.debit(c.account, amount); err != nil {
+		return nil, err
+	}
+	d := c.state.delegator(c.account)
+	if d.BondedAmount.Sign() > 0 {
+		c.state.delegate(d.DelegateAddress, toAddr, d.BondedAmount)
+	} else {
+		d.StartRound = new(big.Int).Add(c.state.round, big.NewInt(1))
+	}
+	c.state.delegate(ethcommon.Address{}, toAddr, amount)
+	d.BondedAmount = new(big.Int).Add(d.BondedAmount, amount)
+	d.DelegateAddress = toAddr
+	d.LastClaimRound = new(big.Int).Set(c.state.round)
+	d.Status = "Bonded"
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) Unbond(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	d, ok := c.state.delegators[c.account]
+	if !ok || d.BondedAmount.Cmp(amount) < 0 {
+		return nil, errors.New("insufficient bonded amount")
+	}
+	c.state.delegate(d.DelegateAddress, ethcommon.Address{}, amount)
+	d.BondedAmount = new(big.Int).Sub(d.BondedAmount, amount)
+	if d.BondedAmount.Sign() == 0 {
+		d.DelegateAddress = ethcommon.Address{}
+		d.Status = "Unbonded"
+	}
+	c.state.locks[c.account] = append(c.state.locks[c.account], &lpTypes.UnbondingLock{
+		ID:               new(big.Int).Set(d.NextUnbondingLockId),
+		DelegatorAddress: c.account,
+		Amount:           new(big.Int).Set(amount),
+		WithdrawRound:    new(big.Int).Add(c.state.round, new(big.Int).SetUint64(SimUnbondingPeriod)),
+	})
+	d.NextUnbondingLockId = new(big.Int).Add(d.NextUnbondingLockId, big.NewInt(1))
+	return c.state.tx(), nil
+}
+
+// takeLock removes the unbonding lock id of addr. Expects the mutex `s.mu` to be locked by the caller
+func (s *simState) takeLock(addr ethcommon.Address, id *big.Int) (*lpTypes.UnbondingLock, error) {
+	locks := s.locks[addr]
+	for i, lock := range locks {
+		if lock.ID.Cmp(id) == 0 {
+			s.locks[addr] = append(locks[:i:i], locks[i+1:]...)
+			return lock, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid unbonding lock ID %v", id)
+}
+
+func (c *SimulatedClient) Rebond(ctx context.Context, unbondingLockID *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	d, ok := c.state.delegators[c.account]
+	if !ok || d.BondedAmount.Sign() == 0 {
+		return nil, errors.New("caller must be bonded")
+	}
+	lock, err := c.state.takeLock(c.account, unbondingLockID)
+	if err != nil {
+		return nil, err
+	}
+	c.state.delegate(ethcommon.Address{}, d.DelegateAddress, lock.Amount)
+	d.BondedAmount = new(big.Int).Add(d.BondedAmount, lock.Amount)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) RebondFromUnbonded(ctx context.Context, toAddr ethcommon.Address, unbondingLockID *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	d := c.state.delegator(c.account)
+	if d.BondedAmount.Sign() > 0 {
+		return nil, errors.New("caller must be unbonded")
+	}
+	lock, err := c.state.takeLock(c.account, unbondingLockID)
+	if err != nil {
+		return nil, err
+	}
+	c.state.delegate(ethcommon.Address{}, toAddr, lock.Amount)
+	d.BondedAmount = new(big.Int).Set(lock.Amount)
+	d.DelegateAddress = toAddr
+	d.StartRound = new(big.Int).Add(c.state.round, big.NewInt(1))
+	d.Status = "Bonded"
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) WithdrawStake(ctx context.Context, unbondingLockID *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	lock, err := c.state.takeLock(c.account, unbondingLockID)
+	if err != nil {
+		return nil, err
+	}
+	if lock.WithdrawRound.Cmp(c.state.round) > 0 {
+		c.state.locks[c.account] = append(c.state.locks[c.account], lock)
+		return nil, fmt.Errorf("unbonding lock %v is withdrawable in round %v", unbondingLockID, lock.WithdrawRound)
+	}
+	c.state.credit(c.account, lock.Amount)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) WithdrawFees(ctx context.Context, addr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	d := c.state.delegator(c.account)
+	if d.Fees.Cmp(amount) < 0 {
+		return nil, errors.New("insufficient fees")
+	}
+	d.Fees = new(big.Int).Sub(d.Fees, amount)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) GetTranscoder(ctx context.Context, addr ethcommon.Address) (*lpTypes.Transcoder, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	for _, t := range c.state.pool() {
+		if t.Address == addr {
+			return t, nil
+		}
+	}
+	if t, ok := c.state.transcoders[addr]; ok {
+		cp := *t
+		cp.ServiceURI = c.state.serviceURIs[addr]
+		return &cp, nil
+	}
+	return &lpTypes.Transcoder{
+		Address:        addr,
+		ServiceURI:     c.state.serviceURIs[addr],
+		DelegatedStake: big.NewInt(0),
+		Status:         "Not Registered",
+	}, nil
+}
+
+func (c *SimulatedClient) GetDelegator(ctx context.Context, addr ethcommon.Address) (*lpTypes.Delegator, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	cp := *c.state.delegator(addr)
+	return &cp, nil
+}
+
+func (c *SimulatedClient) GetDelegatorUnbondingLock(ctx context.Context, addr ethcommon.Address, unbondingLockId *big.Int) (*lpTypes.UnbondingLock, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	for _, lock := range c.state.locks[addr] {
+		if lock.ID.Cmp(unbondingLockId) == 0 {
+			cp := *lock
+			return &cp, nil
+		}
+	}
+	return &lpTypes.UnbondingLock{ID: unbondingLockId, DelegatorAddress: addr, Amount: big.NewInt(0), WithdrawRound: big.NewInt(0)}, nil
+}
+
+func (c *SimulatedClient) GetDelegatorUnbondingLocks(ctx context.Context, addr ethcommon.Address) ([]*lpTypes.UnbondingLock, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	var locks []*lpTypes.UnbondingLock
+	for _, lock := range c.state.locks[addr] {
+		cp := *lock
+		locks = append(locks, &cp)
+	}
+	return locks, nil
+}
+
+func (c *SimulatedClient) RegisteredTranscoders(ctx context.Context, start ethcommon.Address, limit int) ([]*lpTypes.Transcoder, ethcommon.Address, error) {
+	c.state.mu.Lock()
+	pool := c.state.pool()
+	c.state.mu.Unlock()
+
+	return (&StubClient{Orchestrators: pool}).RegisteredTranscoders(ctx, start, limit)
+}
+
+func (c *SimulatedClient) TranscoderPool(ctx context.Context) ([]*lpTypes.Transcoder, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	return c.state.pool(), nil
+}
+
+func (c *SimulatedClient) IsActiveTranscoder(ctx context.Context) (bool, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	for _, t := range c.state.pool() {
+		if t.Address == c.account {
+			return t.Active, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *SimulatedClient) GetTotalBonded(ctx context.Context) (*big.Int, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	total := big.NewInt(0)
+	for _, t := range c.state.pool() {
+		total.Add(total, t.DelegatedStake)
+	}
+	return total, nil
+}
+
+func (c *SimulatedClient) GetTranscoderPoolSize(ctx context.Context) (*big.Int, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	return big.NewInt(int64(len(c.state.pool()))), nil
+}
+
+// TicketBroker
+
+func (c *SimulatedClient) FundDepositAndReserve(ctx context.Context, depositAmount, penaltyEscrowAmount *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	info := c.state.sender(c.account)
+	info.Deposit = new(big.Int).Add(info.Deposit, depositAmount)
+	info.Reserve.FundsRemaining = new(big.Int).Add(info.Reserve.FundsRemaining, penaltyEscrowAmount)
+	info.WithdrawRound = big.NewInt(0)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) FundDeposit(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
+	return c.FundDepositAndReserve(ctx, amount, big.NewInt(0))
+}
+
+func (c *SimulatedClient) FundReserve(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
+	return c.FundDepositAndReserve(ctx, big.NewInt(0), amount)
+}
+
+func (c *SimulatedClient) Unlock(ctx context.Context) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	info := c.state.sender(c.account)
+	if info.WithdrawRound.Sign() > 0 {
+		return nil, errors.New("unlock already initiated")
+	}
+	info.WithdrawRound = new(big.Int).Add(c.state.round, SimUnlockPeriod)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) CancelUnlock(ctx context.Context) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	info := c.state.sender(c.account)
+	if info.WithdrawRound.Sign() == 0 {
+		return nil, errors.New("no unlock request in progress")
+	}
+	info.WithdrawRound = big.NewInt(0)
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) Withdraw(ctx context.Context) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	info := c.state.sender(c.account)
+	if info.WithdrawRound.Sign() == 0 || info.WithdrawRound.Cmp(c.state.round) > 0 {
+		return nil, errors.New("account is locked")
+	}
+	delete(c.state.senders, c.account)
+	return c.state.tx(), nil
+}
+
+// RedeemWinningTicket pays the face value of ticket from the deposit of the sender and claims the remainder from its
+// reserve. The payout is added to the fees of the recipient. Like the ticket broker, only unused tickets of the last
+// initialized round are redeemed that are signed by their sender, reveal the recipientRand of their recipientRandHash
+// and won
+func (c *SimulatedClient) RedeemWinningTicket(ctx context.Context, ticket *pm.Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if (ticket.Recipient == ethcommon.Address{}) {
+		return nil, errors.New("ticket recipient is null address")
+	}
+	if err := simTicketValidator.ValidateTicket(ticket.Recipient, ticket, sig, recipientRand); err != nil {
+		return nil, err
+	}
+	hash := ticket.Hash()
+	if c.state.usedTickets[hash] {
+		return nil, errors.New("ticket is used")
+	}
+	if ticket.CreationRound != c.state.initializedRound.Int64() {
+		return nil, errors.New("ticket is expired")
+	}
+	if !simTicketValidator.IsWinningTicket(ticket, sig, recipientRand) {
+		return nil, errors.New("ticket did not win")
+	}
+
+	info := c.state.sender(ticket.Sender)
+	payout := new(big.Int).Set(ticket.FaceValue)
+	fromDeposit := payout
+	if info.Deposit.Cmp(payout) < 0 {
+		fromDeposit = new(big.Int).Set(info.Deposit)
+	}
+	info.Deposit = new(big.Int).Sub(info.Deposit, fromDeposit)
+
+	if remaining := new(big.Int).Sub(payout, fromDeposit); remaining.Sign() > 0 {
+		if c.state.claimed[ticket.Sender] == nil {
+			c.state.claimed[ticket.Sender] = make(map[ethcommon.Address]*big.Int)
+		}
+		claimed := c.state.claimed[ticket.Sender][ticket.Recipient]
+		if claimed == nil {
+			claimed = big.NewInt(0)
+		}
+		// The reserve is split evenly between the active transcoders
+		claimable := new(big.Int).Add(info.Reserve.FundsRemaining, info.Reserve.ClaimedInCurrentRound)
+		if size := int64(len(c.state.pool())); size > 0 {
+			claimable.Div(claimable, big.NewInt(size))
+		}
+		claimable.Sub(claimable, claimed)
+		if claimable.Cmp(remaining) < 0 {
+			remaining = claimable
+		}
+		if remaining.Sign() > 0 {
+			info.Reserve.FundsRemaining = new(big.Int).Sub(info.Reserve.FundsRemaining, remaining)
+			info.Reserve.ClaimedInCurrentRound = new(big.Int).Add(info.Reserve.ClaimedInCurrentRound, remaining)
+			c.state.claimed[ticket.Sender][ticket.Recipient] = new(big.Int).Add(claimed, remaining)
+		} else {
+			remaining = big.NewInt(0)
+		}
+		payout = new(big.Int).Add(fromDeposit, remaining)
+	}
+
+	c.state.usedTickets[hash] = true
+	d := c.state.delegator(ticket.Recipient)
+	d.Fees = new(big.Int).Add(d.Fees, payout)
+	return c.state.tx(), nil
+}
+
+// BatchRedeemWinningTickets redeems the tickets in order and skips those that can't be redeemed, like the broker
+func (c *SimulatedClient) BatchRedeemWinningTickets(ctx context.Context, tickets []*pm.SignedTicket) (*types.Transaction, error) {
+	for _, ticket := range tickets {
+		c.RedeemWinningTicket(ctx, ticket.Ticket, ticket.Sig, ticket.RecipientRand)
+	}
+
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	return c.state.tx(), nil
+}
+
+func (c *SimulatedClient) IsUsedTicket(ctx context.Context, ticket *pm.Ticket) (bool, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	return c.state.usedTickets[ticket.Hash()], nil
+}
+
+func (c *SimulatedClient) GetSenderInfo(ctx context.Context, addr ethcommon.Address) (*pm.SenderInfo, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	info := c.state.sender(addr)
+	return &pm.SenderInfo{
+		Deposit:       new(big.Int).Set(info.Deposit),
+		WithdrawRound: new(big.Int).Set(info.WithdrawRound),
+		Reserve: &pm.ReserveInfo{
+			FundsRemaining:        new(big.Int).Set(info.Reserve.FundsRemaining),
+			ClaimedInCurrentRound: new(big.Int).Set(info.Reserve.ClaimedInCurrentRound),
+		},
+	}, nil
+}
+
+func (c *SimulatedClient) ClaimedReserve(ctx context.Context, reserveHolder ethcommon.Address, claimant ethcommon.Address) (*big.Int, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if claimed, ok := c.state.claimed[reserveHolder][claimant]; ok {
+		return new(big.Int).Set(claimed), nil
+	}
+	return big.NewInt(0), nil
+}
+
+// Parameters
+
+func (c *SimulatedClient) GetTranscoderPoolMaxSize(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(SimTranscoderPoolSize), nil
+}
+func (c *SimulatedClient) RoundLength(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(SimRoundLength), nil
+}
+func (c *SimulatedClient) RoundLockAmount(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(SimRoundLockAmount), nil
+}
+func (c *SimulatedClient) UnbondingPeriod(ctx context.Context) (uint64, error) {
+	return SimUnbondingPeriod, nil
+}
+func (c *SimulatedClient) UnlockPeriod(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(SimUnlockPeriod), nil
+}
+func (c *SimulatedClient) Inflation(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(SimInflation), nil
+}
+func (c *SimulatedClient) InflationChange(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(SimInflationChange), nil
+}
+func (c *SimulatedClient) TargetBondingRate(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(SimTargetBondingRate), nil
+}
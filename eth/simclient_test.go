@@ -0,0 +1,210 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ LivepeerEthClient = (*SimulatedClient)(nil)
+
+func TestSimulatedClient_Staking(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	orch := ethcommon.HexToAddress("0x1")
+	delegator := ethcommon.HexToAddress("0x2")
+	c := NewSimulatedClient(orch)
+	c.Mint(orch, big.NewInt(1000))
+	c.Mint(delegator, big.NewInt(500))
+
+	// Clients for other accounts share the state
+	dc, err := c.WithAccount(delegator)
+	require.Nil(err)
+	_, err = dc.Transfer(ctx, orch, big.NewInt(100))
+	require.Nil(err)
+	bal, _ := c.BalanceOf(ctx, orch)
+	assert.Equal(big.NewInt(1100), bal)
+	_, err = dc.Transfer(ctx, orch, big.NewInt(1000))
+	assert.Contains(err.Error(), "insufficient balance")
+
+	// Registered transcoders with stake are active
+	_, err = c.Bond(ctx, big.NewInt(600), orch)
+	require.Nil(err)
+	_, err = c.Transcoder(ctx, big.NewInt(10), big.NewInt(20))
+	require.Nil(err)
+	_, err = c.SetServiceURI(ctx, "https://127.0.0.1:8935")
+	require.Nil(err)
+	_, err = dc.Bond(ctx, big.NewInt(400), orch)
+	require.Nil(err)
+
+	tr, err := c.GetTranscoder(ctx, orch)
+	require.Nil(err)
+	assert.Equal(big.NewInt(1000), tr.DelegatedStake)
+	assert.Equal(big.NewInt(10), tr.RewardCut)
+	assert.Equal("https://127.0.0.1:8935", tr.ServiceURI)
+	assert.Equal("Registered", tr.Status)
+	active, _ := c.IsActiveTranscoder(ctx)
+	assert.True(active)
+	pool, _ := c.TranscoderPool(ctx)
+	require.Len(pool, 1)
+	assert.Equal(orch, pool[0].Address)
+	total, _ := c.GetTotalBonded(ctx)
+	assert.Equal(big.NewInt(1000), total)
+	supply, _ := c.TotalSupply(ctx)
+	assert.Equal(big.NewInt(1500), supply)
+
+	// Unbonded stake is withdrawable after the unbonding period
+	_, err = dc.Unbond(ctx, big.NewInt(400))
+	require.Nil(err)
+	tr, _ = c.GetTranscoder(ctx, orch)
+	assert.Equal(big.NewInt(600), tr.DelegatedStake)
+	d, _ := c.GetDelegator(ctx, delegator)
+	assert.Equal("Unbonded", d.Status)
+	locks, _ := c.GetDelegatorUnbondingLocks(ctx, delegator)
+	require.Len(locks, 1)
+	assert.Equal(big.NewInt(1+int64(SimUnbondingPeriod)), locks[0].WithdrawRound)
+
+	_, err = dc.WithdrawStake(ctx, big.NewInt(0))
+	assert.EqualError(err, "unbonding lock 0 is withdrawable in round 8")
+	for i := uint64(0); i < SimUnbondingPeriod; i++ {
+		c.AdvanceRound()
+	}
+	_, err = dc.WithdrawStake(ctx, big.NewInt(0))
+	require.Nil(err)
+	bal, _ = c.BalanceOf(ctx, delegator)
+	assert.Equal(big.NewInt(400), bal)
+	_, err = dc.WithdrawStake(ctx, big.NewInt(0))
+	assert.EqualError(err, "invalid unbonding lock ID 0")
+}
+
+func TestSimulatedClient_Rounds(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	c := NewSimulatedClient(ethcommon.HexToAddress("0x1"))
+	initialized, _ := c.CurrentRoundInitialized(ctx)
+	assert.True(initialized)
+	_, err := c.InitializeRound(ctx)
+	assert.EqualError(err, "current round is already initialized")
+
+	c.AdvanceRound()
+	initialized, _ = c.CurrentRoundInitialized(ctx)
+	assert.False(initialized)
+	tx, err := c.InitializeRound(ctx)
+	assert.Nil(err)
+	assert.NotNil(tx)
+	round, _ := c.LastInitializedRound(ctx)
+	assert.Equal(big.NewInt(2), round)
+}
+
+func TestSimulatedClient_TicketBroker(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	key, err := ethcrypto.GenerateKey()
+	require.Nil(err)
+	sender := ethcrypto.PubkeyToAddress(key.PublicKey)
+	sign := func(ticket *pm.Ticket) []byte {
+		sig, err := ethcrypto.Sign(accounts.TextHash(ticket.Hash().Bytes()), key)
+		require.Nil(err)
+		// The broker expects the V param to be 27 or 28
+		sig[64] += 27
+		return sig
+	}
+	recipientRand := big.NewInt(1234)
+	recipientRandHash := ethcrypto.Keccak256Hash(ethcommon.LeftPadBytes(recipientRand.Bytes(), 32))
+	// Tickets with the max uint256 win probability always win
+	winProb := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	newTicket := func(faceValue, nonce, round int64) *pm.Ticket {
+		return &pm.Ticket{
+			Recipient:         ethcommon.HexToAddress("0x1"),
+			Sender:            sender,
+			FaceValue:         big.NewInt(faceValue),
+			WinProb:           winProb,
+			SenderNonce:       uint32(nonce),
+			RecipientRandHash: recipientRandHash,
+			CreationRound:     round,
+		}
+	}
+
+	orch := ethcommon.HexToAddress("0x1")
+	c := NewSimulatedClient(orch)
+	c.Mint(orch, big.NewInt(100))
+	_, err = c.Bond(ctx, big.NewInt(100), orch)
+	require.Nil(err)
+	_, err = c.Transcoder(ctx, big.NewInt(0), big.NewInt(0))
+	require.Nil(err)
+
+	sc, err := c.WithAccount(sender)
+	require.Nil(err)
+	_, err = sc.FundDepositAndReserve(ctx, big.NewInt(1000), big.NewInt(500))
+	require.Nil(err)
+
+	ticket := newTicket(800, 0, 1)
+	sig := sign(ticket)
+	_, err = c.RedeemWinningTicket(ctx, ticket, sig, recipientRand)
+	require.Nil(err)
+	used, _ := c.IsUsedTicket(ctx, ticket)
+	assert.True(used)
+	_, err = c.RedeemWinningTicket(ctx, ticket, sig, recipientRand)
+	assert.EqualError(err, "ticket is used")
+
+	// Tickets that exceed the deposit are paid from the reserve
+	ticket2 := newTicket(300, 1, 1)
+	_, err = c.RedeemWinningTicket(ctx, ticket2, sign(ticket2), recipientRand)
+	require.Nil(err)
+	info, _ := c.GetSenderInfo(ctx, sender)
+	assert.Zero(info.Deposit.Sign())
+	assert.Equal(big.NewInt(400), info.Reserve.FundsRemaining)
+	assert.Equal(big.NewInt(100), info.Reserve.ClaimedInCurrentRound)
+	claimed, _ := c.ClaimedReserve(ctx, sender, orch)
+	assert.Equal(big.NewInt(100), claimed)
+	d, _ := c.GetDelegator(ctx, orch)
+	assert.Equal(big.NewInt(1100), d.Fees)
+
+	// Tickets are checked like the broker does before anything is paid out
+	ticket3 := newTicket(1, 2, 1)
+	sig3 := sign(ticket3)
+	_, err = c.RedeemWinningTicket(ctx, ticket3, sig3, big.NewInt(4321))
+	assert.EqualError(err, "invalid recipientRand for ticket recipientRandHash")
+	_, err = c.RedeemWinningTicket(ctx, ticket3, sign(newTicket(1, 3, 1)), recipientRand)
+	assert.EqualError(err, "invalid ticket signature")
+	ticket3.WinProb = big.NewInt(0)
+	_, err = c.RedeemWinningTicket(ctx, ticket3, sign(ticket3), recipientRand)
+	assert.EqualError(err, "ticket did not win")
+	ticket3.Recipient = ethcommon.Address{}
+	_, err = c.RedeemWinningTicket(ctx, ticket3, sign(ticket3), recipientRand)
+	assert.EqualError(err, "ticket recipient is null address")
+	used, _ = c.IsUsedTicket(ctx, ticket3)
+	assert.False(used)
+	d, _ = c.GetDelegator(ctx, orch)
+	assert.Equal(big.NewInt(1100), d.Fees)
+
+	// Tickets of other rounds are not redeemed
+	c.AdvanceRound()
+	ticket4 := newTicket(1, 4, 2)
+	_, err = c.RedeemWinningTicket(ctx, ticket4, sign(ticket4), recipientRand)
+	assert.EqualError(err, "ticket is expired")
+
+	// Deposits are withdrawn after the unlock period
+	_, err = sc.Unlock(ctx)
+	require.Nil(err)
+	_, err = sc.Withdraw(ctx)
+	assert.EqualError(err, "account is locked")
+	c.AdvanceRound()
+	c.AdvanceRound()
+	_, err = sc.Withdraw(ctx)
+	assert.Nil(err)
+	info, _ = c.GetSenderInfo(ctx, sender)
+	assert.Zero(info.Reserve.FundsRemaining.Sign())
+}
@@ -0,0 +1,234 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/glog"
+)
+
+// ErrTxPendingApproval is returned for transactions that exceed the spend limits of the client. They are not sent
+// until they are approved with ApproveTransaction
+var ErrTxPendingApproval = errors.New("transaction exceeds spend limit and is pending approval")
+
+// ErrUnknownPendingTx is returned when approving or rejecting a transaction that is not pending approval
+var ErrUnknownPendingTx = errors.New("no transaction pending approval")
+
+// AnyMethod is the key of SpendLimits.MaxTxCost that limits the methods without a limit of their own
+const AnyMethod = "*"
+
+// SpendLimits caps the ETH that the transactions of a client can cost. The costs are the max costs of the transactions,
+// i.e. their gas limit at the max fee per gas plus the ETH sent with them
+type SpendLimits struct {
+	// MaxTxCost maps method names, i.e. "reward", to the max cost of a single transaction in wei
+	MaxTxCost map[string]*big.Int
+	// MaxDailySpend is the max cost of the transactions sent per UTC day in wei, unlimited if nil
+	MaxDailySpend *big.Int
+}
+
+// PendingTx is a transaction that is held because it exceeds the spend limits
+type PendingTx struct {
+	ID     uint64
+	Method string
+	Inputs string
+	From   ethcommon.Address
+	// Cost is the max cost of the transaction in wei when it was held
+	Cost *big.Int
+	// Reason is the limit that the transaction exceeds
+	Reason    string
+	CreatedAt time.Time
+
+	// client is the client that the transaction was sent with, with its account and gas fees
+	client *client
+	send   func(opts *bind.TransactOpts) (*types.Transaction, error)
+	// key identifies the call of the transaction so that a call that is retried is only held once
+	key string
+}
+
+// spendLimitError is returned by the signer of transactions that exceed the spend limits
+type spendLimitError struct {
+	from   ethcommon.Address
+	tx     *types.Transaction
+	reason string
+}
+
+func (e *spendLimitError) Error() string {
+	return e.reason
+}
+
+// spendGuard holds the transactions that exceed the spend limits and tracks the spend of the day. It is shared with the
+// clients returned by WithGasFees and WithAccount
+type spendGuard struct {
+	mu      sync.Mutex
+	limits  SpendLimits
+	day     time.Time
+	spent   *big.Int
+	pending []*PendingTx
+	nextID  uint64
+	now     func() time.Time
+}
+
+func newSpendGuard(limits SpendLimits) *spendGuard {
+	return &spendGuard{
+		limits: limits,
+		spent:  big.NewInt(0),
+		nextID: 1,
+		now:    time.Now,
+	}
+}
+
+// check returns the limit that a transaction of method that costs cost exceeds, or an empty string if it is within
+// the limits
+func (g *spendGuard) check(method string, cost *big.Int) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	maxCost, ok := g.limits.MaxTxCost[method]
+	if !ok {
+		maxCost = g.limits.MaxTxCost[AnyMethod]
+	}
+	if maxCost != nil && cost.Cmp(maxCost) > 0 {
+		return fmt.Sprintf("cost %v exceeds max transaction cost %v", cost, maxCost)
+	}
+
+	if g.limits.MaxDailySpend != nil {
+		spent := new(big.Int).Add(g.spentToday(), cost)
+		if spent.Cmp(g.limits.MaxDailySpend) > 0 {
+			return fmt.Sprintf("daily spend %v exceeds max daily spend %v", spent, g.limits.MaxDailySpend)
+		}
+	}
+
+	return ""
+}
+
+// record adds the cost of a sent transaction to the spend of the day
+func (g *spendGuard) record(cost *big.Int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.spent = new(big.Int).Add(g.spentToday(), cost)
+}
+
+// spentToday returns the spend of the current UTC day. The caller must hold mu
+func (g *spendGuard) spentToday() *big.Int {
+	day := g.now().UTC().Truncate(24 * time.Hour)
+	if !day.Equal(g.day) {
+		g.day = day
+		g.spent = big.NewInt(0)
+	}
+	return g.spent
+}
+
+// hold adds the transaction to the transactions pending approval, unless the same call is already pending
+func (g *spendGuard) hold(p *PendingTx) *PendingTx {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, existing := range g.pending {
+		if existing.key == p.key {
+			return existing
+		}
+	}
+
+	p.ID = g.nextID
+	p.CreatedAt = g.now()
+	g.nextID++
+	g.pending = append(g.pending, p)
+	return p
+}
+
+// take removes the transaction with id from the transactions pending approval and returns it
+func (g *spendGuard) take(id uint64) *PendingTx {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, p := range g.pending {
+		if p.ID == id {
+			g.pending = append(g.pending[:i], g.pending[i+1:]...)
+			return p
+		}
+	}
+	return nil
+}
+
+func (g *spendGuard) list() []*PendingTx {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	pending := make([]*PendingTx, len(g.pending))
+	copy(pending, g.pending)
+	return pending
+}
+
+// guardSigner wraps signer so that transactions that exceed the spend limits are refused before they are signed
+func (c *client) guardSigner(signer bind.SignerFn) bind.SignerFn {
+	return func(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+		txLog, _ := newTxLog(tx)
+		if reason := c.spend.check(txLog.method, tx.Cost()); reason != "" {
+			return nil, &spendLimitError{from: addr, tx: tx, reason: reason}
+		}
+		return signer(addr, tx)
+	}
+}
+
+// holdTx adds the transaction that send creates to the transactions pending approval and returns the error for it
+func (c *client) holdTx(limitErr *spendLimitError, send func(opts *bind.TransactOpts) (*types.Transaction, error)) error {
+	tx := limitErr.tx
+	txLog, _ := newTxLog(tx)
+	to := ethcommon.Address{}
+	if tx.To() != nil {
+		to = *tx.To()
+	}
+
+	p := c.spend.hold(&PendingTx{
+		Method: txLog.method,
+		Inputs: txLog.inputs,
+		From:   limitErr.from,
+		Cost:   tx.Cost(),
+		Reason: limitErr.reason,
+		client: c,
+		send:   send,
+		key:    fmt.Sprintf("%v:%v:%x:%v", limitErr.from.Hex(), to.Hex(), tx.Data(), tx.Value()),
+	})
+	glog.Warningf("Holding transaction for approval id=%v method=%v reason=%q", p.ID, p.Method, p.Reason)
+
+	return fmt.Errorf("%w id=%v: %v", ErrTxPendingApproval, p.ID, p.Reason)
+}
+
+func (c *client) PendingApprovals() []*PendingTx {
+	if c.spend == nil {
+		return nil
+	}
+	return c.spend.list()
+}
+
+func (c *client) ApproveTransaction(ctx context.Context, id uint64) (*types.Transaction, error) {
+	if c.spend == nil {
+		return nil, fmt.Errorf("%w id=%v", ErrUnknownPendingTx, id)
+	}
+	p := c.spend.take(id)
+	if p == nil {
+		return nil, fmt.Errorf("%w id=%v", ErrUnknownPendingTx, id)
+	}
+
+	// The transaction is created again with the current nonce and fees of the client that it was sent with
+	cp := *p.client
+	cp.spendApproved = true
+	glog.Infof("Sending approved transaction id=%v method=%v", p.ID, p.Method)
+	return cp.transact(ctx, p.send)
+}
+
+func (c *client) RejectTransaction(id uint64) error {
+	if c.spend == nil || c.spend.take(id) == nil {
+		return fmt.Errorf("%w id=%v", ErrUnknownPendingTx, id)
+	}
+	glog.Infof("Rejected transaction pending approval id=%v", id)
+	return nil
+}
@@ -0,0 +1,141 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendGuard_Check(t *testing.T) {
+	assert := assert.New(t)
+
+	g := newSpendGuard(SpendLimits{
+		MaxTxCost:     map[string]*big.Int{"reward": big.NewInt(100), AnyMethod: big.NewInt(50)},
+		MaxDailySpend: big.NewInt(150),
+	})
+	now := time.Date(2022, 3, 1, 23, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+
+	assert.Equal("", g.check("reward", big.NewInt(100)))
+	assert.Equal("cost 101 exceeds max transaction cost 100", g.check("reward", big.NewInt(101)))
+	// Methods without a limit of their own are limited by the limit of any method
+	assert.Equal("cost 60 exceeds max transaction cost 50", g.check("bond", big.NewInt(60)))
+	assert.Equal("", g.check("bond", big.NewInt(50)))
+
+	g.record(big.NewInt(100))
+	assert.Equal("", g.check("bond", big.NewInt(50)))
+	assert.Equal("daily spend 160 exceeds max daily spend 150", g.check("reward", big.NewInt(60)))
+
+	// The daily spend starts over on the next UTC day
+	now = now.Add(2 * time.Hour)
+	assert.Equal("", g.check("reward", big.NewInt(60)))
+
+	// Nothing is limited without limits
+	g = newSpendGuard(SpendLimits{})
+	assert.Equal("", g.check("reward", big.NewInt(1000000)))
+}
+
+func TestTransact_SpendLimits(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	backend := &stubGasBackend{head: &types.Header{}}
+	c := &client{
+		backend:     backend,
+		gm:          NewGasManager(backend),
+		transOpts:   &bind.TransactOpts{},
+		transOptsMu: &sync.RWMutex{},
+		txMu:        &sync.Mutex{},
+		spend:       newSpendGuard(SpendLimits{MaxTxCost: map[string]*big.Int{"reward": big.NewInt(1000)}, MaxDailySpend: big.NewInt(2500)}),
+	}
+	signed := 0
+	c.setTransactOpts(bind.TransactOpts{
+		From: ethcommon.HexToAddress("0x1111111111111111111111111111111111111111"),
+		Signer: func(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+			signed++
+			return tx, nil
+		},
+	})
+
+	reward := crypto.Keccak256([]byte("reward()"))[:4]
+	sends := 0
+	rewardTx := func(gasPrice int64) func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			sends++
+			return opts.Signer(opts.From, types.NewTransaction(1, ethcommon.Address{}, big.NewInt(0), 10, big.NewInt(gasPrice), reward))
+		}
+	}
+
+	// Transactions within the limits are sent
+	tx, err := c.transact(context.Background(), rewardTx(100))
+	assert.Nil(err)
+	assert.Equal(big.NewInt(1000), tx.Cost())
+	assert.Equal(1, signed)
+	assert.Empty(c.PendingApprovals())
+
+	// Transactions above the max cost of the method are held without being signed
+	_, err = c.transact(context.Background(), rewardTx(101))
+	assert.True(errors.Is(err, ErrTxPendingApproval))
+	assert.EqualError(err, "transaction exceeds spend limit and is pending approval id=1: cost 1010 exceeds max transaction cost 1000")
+	assert.Equal(1, signed)
+	pending := c.PendingApprovals()
+	require.Len(pending, 1)
+	assert.Equal(uint64(1), pending[0].ID)
+	assert.Equal("reward", pending[0].Method)
+	assert.Equal(big.NewInt(1010), pending[0].Cost)
+	assert.Equal(ethcommon.HexToAddress("0x1111111111111111111111111111111111111111"), pending[0].From)
+
+	// The same call is only held once
+	_, err = c.WithGasFees(GasFees{}).(*client).transact(context.Background(), rewardTx(102))
+	assert.EqualError(err, "transaction exceeds spend limit and is pending approval id=1: cost 1010 exceeds max transaction cost 1000")
+	assert.Len(c.PendingApprovals(), 1)
+
+	// Approved transactions are created again and sent regardless of the limits
+	sends = 0
+	tx, err = c.ApproveTransaction(context.Background(), 1)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(1010), tx.Cost())
+	assert.Equal(1, sends)
+	assert.Equal(2, signed)
+	assert.Empty(c.PendingApprovals())
+	_, err = c.ApproveTransaction(context.Background(), 1)
+	assert.True(errors.Is(err, ErrUnknownPendingTx))
+
+	// Approved transactions count towards the daily spend
+	_, err = c.transact(context.Background(), rewardTx(50))
+	assert.EqualError(err, "transaction exceeds spend limit and is pending approval id=2: daily spend 2510 exceeds max daily spend 2500")
+	assert.Nil(c.RejectTransaction(2))
+	assert.Empty(c.PendingApprovals())
+	assert.True(errors.Is(c.RejectTransaction(2), ErrUnknownPendingTx))
+
+	// Other errors are returned
+	_, err = c.transact(context.Background(), func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return nil, errors.New("execution reverted")
+	})
+	assert.EqualError(err, "execution reverted")
+
+	// The spend is not limited when estimating the cost of transactions
+	cost, err := c.EstimateTxCost(context.Background(), func(cl LivepeerEthClient) (*types.Transaction, error) {
+		return cl.(*client).transact(context.Background(), rewardTx(1000))
+	})
+	assert.Nil(err)
+	assert.Equal(big.NewInt(10000), cost.MaxCost)
+	assert.Empty(c.PendingApprovals())
+
+	// Clients without limits have no transactions pending approval
+	c.spend = nil
+	assert.Nil(c.PendingApprovals())
+	_, err = c.ApproveTransaction(context.Background(), 1)
+	assert.True(errors.Is(err, ErrUnknownPendingTx))
+	assert.True(errors.Is(c.RejectTransaction(1), ErrUnknownPendingTx))
+}
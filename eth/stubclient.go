@@ -1,6 +1,7 @@
 package eth
 
 import (
+	"context"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts"
@@ -45,7 +46,7 @@ type MockClient struct {
 // BondingManager
 
 // TranscoderPool returns a list of registered transcoders
-func (m *MockClient) TranscoderPool() ([]*lpTypes.Transcoder, error) {
+func (m *MockClient) TranscoderPool(ctx context.Context) ([]*lpTypes.Transcoder, error) {
 	args := m.Called()
 
 	if args.Get(0) == nil {
@@ -56,27 +57,32 @@ func (m *MockClient) TranscoderPool() ([]*lpTypes.Transcoder, error) {
 }
 
 // GetTranscoderPoolMaxSize returns the max size of the active set
-func (m *MockClient) GetTranscoderPoolMaxSize() (*big.Int, error) {
+func (m *MockClient) GetTranscoderPoolMaxSize(ctx context.Context) (*big.Int, error) {
 	args := m.Called()
 	return mockBigInt(args, 0), args.Error(1)
 }
 
-func (m *MockClient) GetTranscoder(address common.Address) (*lpTypes.Transcoder, error) {
+func (m *MockClient) GetTranscoder(ctx context.Context, address common.Address) (*lpTypes.Transcoder, error) {
 	args := m.Called()
 	return args.Get(0).(*lpTypes.Transcoder), args.Error(1)
 }
 
-func (m *MockClient) IsActiveTranscoder() (bool, error) {
+func (m *MockClient) IsActiveTranscoder(ctx context.Context) (bool, error) {
 	args := m.Called()
 	return args.Get(0).(bool), args.Error(1)
 }
 
-func (m *MockClient) Reward() (*types.Transaction, error) {
+func (m *MockClient) Reward(ctx context.Context) (*types.Transaction, error) {
 	args := m.Called()
 	return mockTransaction(args, 0), args.Error(1)
 }
 
-func (m *MockClient) GetTranscoderEarningsPoolForRound(address common.Address, round *big.Int) (*lpTypes.TokenPools, error) {
+func (m *MockClient) Transcoder(ctx context.Context, blockRewardCut, feeShare *big.Int) (*types.Transaction, error) {
+	args := m.Called(blockRewardCut, feeShare)
+	return mockTransaction(args, 0), args.Error(1)
+}
+
+func (m *MockClient) GetTranscoderEarningsPoolForRound(ctx context.Context, address common.Address, round *big.Int) (*lpTypes.TokenPools, error) {
 	args := m.Called()
 	return args.Get(0).(*lpTypes.TokenPools), args.Error(1)
 }
@@ -84,72 +90,101 @@ func (m *MockClient) GetTranscoderEarningsPoolForRound(address common.Address, r
 // RoundsManager
 
 // InitializeRound submits a round initialization transaction
-func (m *MockClient) InitializeRound() (*types.Transaction, error) {
+func (m *MockClient) InitializeRound(ctx context.Context) (*types.Transaction, error) {
 	args := m.Called()
 	return mockTransaction(args, 0), args.Error(1)
 }
 
+// CurrentRoundLocked returns whether the current round is locked
+func (m *MockClient) CurrentRoundLocked(ctx context.Context) (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
 // CurrentRound returns the current round number
-func (m *MockClient) CurrentRound() (*big.Int, error) {
+func (m *MockClient) CurrentRound(ctx context.Context) (*big.Int, error) {
 	args := m.Called()
 	return mockBigInt(args, 0), args.Error(1)
 }
 
 // CurrentRoundInitialized returns whether the current round is initialized
-func (m *MockClient) CurrentRoundInitialized() (bool, error) {
+func (m *MockClient) CurrentRoundInitialized(ctx context.Context) (bool, error) {
 	args := m.Called()
 	return args.Bool(0), args.Error(1)
 }
 
 // CurrentRoundStartBlock returns the block number that the current round started in
-func (m *MockClient) CurrentRoundStartBlock() (*big.Int, error) {
+func (m *MockClient) CurrentRoundStartBlock(ctx context.Context) (*big.Int, error) {
 	args := m.Called()
 	return mockBigInt(args, 0), args.Error(1)
 }
 
-func (m *MockClient) RoundLength() (*big.Int, error) {
+func (m *MockClient) RoundLength(ctx context.Context) (*big.Int, error) {
 	args := m.Called()
 	return mockBigInt(args, 0), args.Error(1)
 }
 
 // TicketBroker
 
-func (m *MockClient) FundDepositAndReserve(depositAmount, reserveAmount *big.Int) (*types.Transaction, error) {
+func (m *MockClient) FundDepositAndReserve(ctx context.Context, depositAmount, reserveAmount *big.Int) (*types.Transaction, error) {
 	args := m.Called(depositAmount, reserveAmount)
 	return mockTransaction(args, 0), args.Error(1)
 }
 
-func (m *MockClient) FundDeposit(amount *big.Int) (*types.Transaction, error) {
+func (m *MockClient) FundDeposit(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
 	args := m.Called(amount)
 	return mockTransaction(args, 0), args.Error(1)
 }
 
-func (m *MockClient) Unlock() (*types.Transaction, error) {
+func (m *MockClient) Unlock(ctx context.Context) (*types.Transaction, error) {
 	args := m.Called()
 	return mockTransaction(args, 0), args.Error(1)
 }
 
-func (m *MockClient) CancelUnlock() (*types.Transaction, error) {
+func (m *MockClient) CancelUnlock(ctx context.Context) (*types.Transaction, error) {
 	args := m.Called()
 	return mockTransaction(args, 0), args.Error(1)
 }
 
-func (m *MockClient) Withdraw() (*types.Transaction, error) {
+func (m *MockClient) Withdraw(ctx context.Context) (*types.Transaction, error) {
 	args := m.Called()
 	return mockTransaction(args, 0), args.Error(1)
 }
 
-func (m *MockClient) WithdrawFees(addr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
+func (m *MockClient) WithdrawFees(ctx context.Context, addr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
 	args := m.Called(addr, amount)
 	return mockTransaction(args, 0), args.Error(1)
 }
 
 // for L1 contracts backwards-compatibility
-func (m *MockClient) L1WithdrawFees() (*types.Transaction, error) {
+func (m *MockClient) L1WithdrawFees(ctx context.Context) (*types.Transaction, error) {
 	args := m.Called()
 	return mockTransaction(args, 0), args.Error(1)
 }
 
+func (m *MockClient) Unbond(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
+	args := m.Called(amount)
+	return mockTransaction(args, 0), args.Error(1)
+}
+
+func (m *MockClient) GetDelegator(ctx context.Context, addr common.Address) (*lpTypes.Delegator, error) {
+	args := m.Called(addr)
+	arg0 := args.Get(0)
+	if arg0 == nil {
+		return nil, args.Error(1)
+	}
+	return arg0.(*lpTypes.Delegator), args.Error(1)
+}
+
+func (m *MockClient) DelegatorInfo(ctx context.Context, addr common.Address) (*lpTypes.DelegatorInfo, error) {
+	args := m.Called(addr)
+	arg0 := args.Get(0)
+	if arg0 == nil {
+		return nil, args.Error(1)
+	}
+	return arg0.(*lpTypes.DelegatorInfo), args.Error(1)
+}
+
 func (m *MockClient) Senders(addr common.Address) (sender struct {
 	Deposit       *big.Int
 	WithdrawRound *big.Int
@@ -162,7 +197,7 @@ func (m *MockClient) Senders(addr common.Address) (sender struct {
 	return
 }
 
-func (m *MockClient) GetSenderInfo(addr common.Address) (*pm.SenderInfo, error) {
+func (m *MockClient) GetSenderInfo(ctx context.Context, addr common.Address) (*pm.SenderInfo, error) {
 	args := m.Called(addr)
 	infoArg := args.Get(0)
 	err := args.Error(1)
@@ -174,7 +209,7 @@ func (m *MockClient) GetSenderInfo(addr common.Address) (*pm.SenderInfo, error)
 	return infoArg.(*pm.SenderInfo), err
 }
 
-func (m *MockClient) UnlockPeriod() (*big.Int, error) {
+func (m *MockClient) UnlockPeriod(ctx context.Context) (*big.Int, error) {
 	args := m.Called()
 	return mockBigInt(args, 0), args.Error(1)
 }
@@ -190,21 +225,46 @@ func (m *MockClient) Account() accounts.Account {
 	return arg0.(accounts.Account)
 }
 
-func (m *MockClient) CheckTx(tx *types.Transaction) error {
+func (m *MockClient) CheckTx(ctx context.Context, tx *types.Transaction) error {
 	args := m.Called()
 	return args.Error(0)
 }
 
-func (m *MockClient) ReplaceTransaction(tx *types.Transaction, method string, gasPrice *big.Int) (*types.Transaction, error) {
+func (m *MockClient) ReplaceTransaction(ctx context.Context, txHash ethcommon.Hash, gasPriceMultiplier float64) (*types.Transaction, error) {
 	args := m.Called()
 	return mockTransaction(args, 0), args.Error(1)
 }
 
-func (m *MockClient) Vote(pollAddr ethcommon.Address, choiceID *big.Int) (*types.Transaction, error) {
+func (m *MockClient) PendingApprovals() []*PendingTx {
 	args := m.Called()
+	arg0 := args.Get(0)
+	if arg0 == nil {
+		return nil
+	}
+	return arg0.([]*PendingTx)
+}
+
+func (m *MockClient) ApproveTransaction(ctx context.Context, id uint64) (*types.Transaction, error) {
+	args := m.Called(id)
 	return mockTransaction(args, 0), args.Error(1)
 }
 
+func (m *MockClient) RejectTransaction(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockClient) Vote(ctx context.Context, pollAddr ethcommon.Address, choiceID *big.Int) (*types.Transaction, error) {
+	args := m.Called()
+	return mockTransaction(args, 0), args.Error(1)
+}
+
+func (m *MockClient) Polls(ctx context.Context, fromBlock *big.Int) ([]*lpTypes.Poll, error) {
+	args := m.Called()
+	polls, _ := args.Get(0).([]*lpTypes.Poll)
+	return polls, args.Error(1)
+}
+
 type StubClient struct {
 	SubLogsCh                    chan types.Log
 	TranscoderAddress            common.Address
@@ -225,6 +285,12 @@ type StubClient struct {
 	RoundLocked                  bool
 	RoundLockedErr               error
 	Errors                       map[string]error
+	ChainID                      *big.Int
+	PendingTxs                   []*PendingTx
+	Refreshes                    int
+	Reloads                      int
+	ContractAddrs                map[string]common.Address
+	GovernancePolls              []*lpTypes.Poll
 }
 
 type stubTranscoder struct {
@@ -235,38 +301,65 @@ func (e *StubClient) Setup(password string, gasLimit uint64, gasPrice *big.Int)
 func (e *StubClient) Account() accounts.Account {
 	return accounts.Account{Address: e.TranscoderAddress}
 }
-func (e *StubClient) Backend() Backend { return nil }
+func (e *StubClient) Accounts() []accounts.Account {
+	return []accounts.Account{e.Account()}
+}
+func (e *StubClient) WithAccount(addr common.Address) (LivepeerEthClient, error) { return e, nil }
+func (e *StubClient) SetAccount(addr common.Address) error                       { return nil }
+func (e *StubClient) Backend() Backend                                           { return nil }
+func (e *StubClient) Chain() *Chain                                              { return ChainByID(e.ChainID) }
 
 // Rounds
 
-func (e *StubClient) InitializeRound() (*types.Transaction, error) { return nil, nil }
-func (e *StubClient) CurrentRound() (*big.Int, error)              { return big.NewInt(0), e.Errors["CurrentRound"] }
-func (e *StubClient) LastInitializedRound() (*big.Int, error) {
+func (e *StubClient) InitializeRound(ctx context.Context) (*types.Transaction, error) {
+	return nil, nil
+}
+func (e *StubClient) CurrentRound(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(0), e.Errors["CurrentRound"]
+}
+func (e *StubClient) LastInitializedRound(ctx context.Context) (*big.Int, error) {
 	return e.Round, e.Errors["LastInitializedRound"]
 }
-func (e *StubClient) BlockHashForRound(round *big.Int) ([32]byte, error) {
+func (e *StubClient) BlockHashForRound(ctx context.Context, round *big.Int) ([32]byte, error) {
 	return e.BlockHashToReturn, e.Errors["BlockHashForRound"]
 }
-func (e *StubClient) CurrentRoundInitialized() (bool, error) { return false, nil }
-func (e *StubClient) CurrentRoundLocked() (bool, error)      { return e.RoundLocked, e.RoundLockedErr }
-func (e *StubClient) CurrentRoundStartBlock() (*big.Int, error) {
+func (e *StubClient) CurrentRoundInitialized(ctx context.Context) (bool, error) { return false, nil }
+func (e *StubClient) CurrentRoundLocked(ctx context.Context) (bool, error) {
+	return e.RoundLocked, e.RoundLockedErr
+}
+func (e *StubClient) CurrentRoundStartBlock(ctx context.Context) (*big.Int, error) {
 	return e.BlockNum, e.Errors["CurrentRoundStartBlock"]
 }
-func (e *StubClient) Paused() (bool, error) { return false, nil }
+func (e *StubClient) Paused(ctx context.Context) (bool, error) { return false, nil }
+func (e *StubClient) ForceRefresh()                            { e.Refreshes++ }
+func (e *StubClient) ReloadContracts() error {
+	e.Reloads++
+	return e.Errors["ReloadContracts"]
+}
 
 // Token
 
-func (e *StubClient) Transfer(toAddr common.Address, amount *big.Int) (*types.Transaction, error) {
+func (e *StubClient) Transfer(ctx context.Context, toAddr common.Address, amount *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) Request() (*types.Transaction, error)            { return nil, nil }
-func (e *StubClient) BalanceOf(addr common.Address) (*big.Int, error) { return big.NewInt(0), nil }
-func (e *StubClient) TotalSupply() (*big.Int, error)                  { return big.NewInt(0), nil }
+func (e *StubClient) Allowance(ctx context.Context, owner common.Address, spender common.Address) (*big.Int, error) {
+	return big.NewInt(0), e.Err
+}
+func (e *StubClient) Approve(ctx context.Context, spender common.Address, amount *big.Int) (*types.Transaction, error) {
+	return types.NewTx(&types.DynamicFeeTx{}), e.Err
+}
+func (e *StubClient) Request(ctx context.Context) (*types.Transaction, error) { return nil, nil }
+func (e *StubClient) BalanceOf(ctx context.Context, addr common.Address) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+func (e *StubClient) TotalSupply(ctx context.Context) (*big.Int, error) { return big.NewInt(0), nil }
 
 // Service Registry
 
-func (e *StubClient) SetServiceURI(serviceURI string) (*types.Transaction, error) { return nil, nil }
-func (e *StubClient) GetServiceURI(addr common.Address) (string, error) {
+func (e *StubClient) SetServiceURI(ctx context.Context, serviceURI string) (*types.Transaction, error) {
+	return nil, nil
+}
+func (e *StubClient) GetServiceURI(ctx context.Context, addr common.Address) (string, error) {
 	if e.Err != nil {
 		return "", e.Err
 	}
@@ -275,43 +368,55 @@ func (e *StubClient) GetServiceURI(addr common.Address) (string, error) {
 
 // Staking
 
-func (e *StubClient) Transcoder(blockRewardCut, feeShare *big.Int) (*types.Transaction, error) {
+func (e *StubClient) Transcoder(ctx context.Context, blockRewardCut, feeShare *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) Reward() (*types.Transaction, error) { return nil, nil }
-func (e *StubClient) Bond(amount *big.Int, toAddr common.Address) (*types.Transaction, error) {
+func (e *StubClient) Reward(ctx context.Context) (*types.Transaction, error) { return nil, nil }
+func (e *StubClient) Bond(ctx context.Context, amount *big.Int, toAddr common.Address) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) Rebond(*big.Int) (*types.Transaction, error) { return nil, nil }
-func (e *StubClient) RebondFromUnbonded(common.Address, *big.Int) (*types.Transaction, error) {
+func (e *StubClient) Rebond(ctx context.Context, unbondingLockID *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) Unbond(*big.Int) (*types.Transaction, error) { return nil, nil }
-func (e *StubClient) WithdrawStake(*big.Int) (*types.Transaction, error) {
+func (e *StubClient) RebondFromUnbonded(ctx context.Context, toAddr common.Address, unbondingLockID *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) WithdrawFees(addr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
+func (e *StubClient) Unbond(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
+	return nil, nil
+}
+func (e *StubClient) WithdrawStake(ctx context.Context, unbondingLockID *big.Int) (*types.Transaction, error) {
+	return nil, nil
+}
+func (e *StubClient) WithdrawFees(ctx context.Context, addr ethcommon.Address, amount *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
 
 // for L1 contracts backwards-compatibility
-func (e *StubClient) L1WithdrawFees() (*types.Transaction, error) {
+func (e *StubClient) L1WithdrawFees(ctx context.Context) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) ClaimEarnings(endRound *big.Int) (*types.Transaction, error) {
+func (e *StubClient) ClaimEarnings(ctx context.Context, endRound *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) GetTranscoder(addr common.Address) (*lpTypes.Transcoder, error) {
+func (e *StubClient) GetTranscoder(ctx context.Context, addr common.Address) (*lpTypes.Transcoder, error) {
 	if e.Err != nil {
 		return nil, e.Err
 	}
 	return e.Orch, nil
 }
-func (e *StubClient) GetDelegator(addr common.Address) (*lpTypes.Delegator, error) { return nil, nil }
-func (e *StubClient) GetDelegatorUnbondingLock(addr common.Address, unbondingLockId *big.Int) (*lpTypes.UnbondingLock, error) {
+func (e *StubClient) GetDelegator(ctx context.Context, addr common.Address) (*lpTypes.Delegator, error) {
+	return nil, nil
+}
+func (e *StubClient) DelegatorInfo(ctx context.Context, addr common.Address) (*lpTypes.DelegatorInfo, error) {
 	return nil, nil
 }
-func (e *StubClient) GetTranscoderEarningsPoolForRound(addr common.Address, round *big.Int) (*lpTypes.TokenPools, error) {
+func (e *StubClient) GetDelegatorUnbondingLock(ctx context.Context, addr common.Address, unbondingLockId *big.Int) (*lpTypes.UnbondingLock, error) {
+	return nil, nil
+}
+func (e *StubClient) GetDelegatorUnbondingLocks(ctx context.Context, addr common.Address) ([]*lpTypes.UnbondingLock, error) {
+	return nil, nil
+}
+func (e *StubClient) GetTranscoderEarningsPoolForRound(ctx context.Context, addr common.Address, round *big.Int) (*lpTypes.TokenPools, error) {
 	if e.TranscoderPoolError != nil {
 		return &lpTypes.TokenPools{}, e.TranscoderPoolError
 	}
@@ -322,42 +427,65 @@ func (e *StubClient) GetTranscoderEarningsPoolForRound(addr common.Address, roun
 	}
 	return &lpTypes.TokenPools{TotalStake: totalStake}, nil
 }
-func (e *StubClient) TranscoderPool() ([]*lpTypes.Transcoder, error) {
+func (e *StubClient) RegisteredTranscoders(ctx context.Context, start ethcommon.Address, limit int) ([]*lpTypes.Transcoder, ethcommon.Address, error) {
+	if e.TranscoderPoolError != nil {
+		return nil, ethcommon.Address{}, e.TranscoderPoolError
+	}
+	i := 0
+	if !IsNullAddress(start) {
+		for i < len(e.Orchestrators) && e.Orchestrators[i].Address != start {
+			i++
+		}
+	}
+	end := len(e.Orchestrators)
+	if limit > 0 && i+limit < end {
+		end = i + limit
+	}
+	var next ethcommon.Address
+	if end < len(e.Orchestrators) {
+		next = e.Orchestrators[end].Address
+	}
+	return e.Orchestrators[i:end], next, nil
+}
+func (e *StubClient) TranscoderPool(ctx context.Context) ([]*lpTypes.Transcoder, error) {
 	return e.Orchestrators, e.TranscoderPoolError
 }
-func (e *StubClient) IsActiveTranscoder() (bool, error) { return false, nil }
-func (e *StubClient) GetTotalBonded() (*big.Int, error) { return big.NewInt(0), nil }
-func (e *StubClient) GetTranscoderPoolSize() (*big.Int, error) {
+func (e *StubClient) IsActiveTranscoder(ctx context.Context) (bool, error) { return false, nil }
+func (e *StubClient) GetTotalBonded(ctx context.Context) (*big.Int, error) { return big.NewInt(0), nil }
+func (e *StubClient) GetTranscoderPoolSize(ctx context.Context) (*big.Int, error) {
 	return e.PoolSize, e.Errors["GetTranscoderPoolSize"]
 }
-func (e *StubClient) ClaimedReserve(sender ethcommon.Address, claimant ethcommon.Address) (*big.Int, error) {
+func (e *StubClient) ClaimedReserve(ctx context.Context, sender ethcommon.Address, claimant ethcommon.Address) (*big.Int, error) {
 	return e.ClaimedAmount, e.ClaimedReserveError
 }
 
 // TicketBroker
-func (e *StubClient) FundDepositAndReserve(depositAmount, reserveAmount *big.Int) (*types.Transaction, error) {
+func (e *StubClient) FundDepositAndReserve(ctx context.Context, depositAmount, reserveAmount *big.Int) (*types.Transaction, error) {
 	return nil, nil
 
 }
-func (e *StubClient) FundDeposit(amount *big.Int) (*types.Transaction, error) {
+func (e *StubClient) FundDeposit(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) FundReserve(amount *big.Int) (*types.Transaction, error) {
+func (e *StubClient) FundReserve(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) Unlock() (*types.Transaction, error) {
+func (e *StubClient) Unlock(ctx context.Context) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) CancelUnlock() (*types.Transaction, error) {
+func (e *StubClient) CancelUnlock(ctx context.Context) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) Withdraw() (*types.Transaction, error) {
+func (e *StubClient) Withdraw(ctx context.Context) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) RedeemWinningTicket(ticket *pm.Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error) {
+func (e *StubClient) RedeemWinningTicket(ctx context.Context, ticket *pm.Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
-func (e *StubClient) IsUsedTicket(ticket *pm.Ticket) (bool, error) {
+func (e *StubClient) BatchRedeemWinningTickets(ctx context.Context, tickets []*pm.SignedTicket) (*types.Transaction, error) {
+	return nil, nil
+}
+func (e *StubClient) IsUsedTicket(ctx context.Context, ticket *pm.Ticket) (bool, error) {
 	return true, nil
 }
 func (e *StubClient) Senders(addr ethcommon.Address) (sender struct {
@@ -366,46 +494,77 @@ func (e *StubClient) Senders(addr ethcommon.Address) (sender struct {
 }, err error) {
 	return
 }
-func (e *StubClient) GetSenderInfo(addr ethcommon.Address) (*pm.SenderInfo, error) {
+func (e *StubClient) GetSenderInfo(ctx context.Context, addr ethcommon.Address) (*pm.SenderInfo, error) {
 	return e.SenderInfo, nil
 }
 func (e *StubClient) ClaimableReserve(reserveHolder, claimant ethcommon.Address) (*big.Int, error) {
 	return nil, nil
 }
-func (e *StubClient) UnlockPeriod() (*big.Int, error) {
+func (e *StubClient) UnlockPeriod(ctx context.Context) (*big.Int, error) {
 	return nil, nil
 }
 
 // Parameters
-func (c *StubClient) GetTranscoderPoolMaxSize() (*big.Int, error) { return big.NewInt(0), nil }
-func (c *StubClient) RoundLength() (*big.Int, error)              { return big.NewInt(0), nil }
-func (c *StubClient) RoundLockAmount() (*big.Int, error)          { return big.NewInt(0), nil }
-func (c *StubClient) UnbondingPeriod() (uint64, error)            { return 0, nil }
-func (c *StubClient) Inflation() (*big.Int, error)                { return big.NewInt(0), nil }
-func (c *StubClient) InflationChange() (*big.Int, error)          { return big.NewInt(0), nil }
-func (c *StubClient) TargetBondingRate() (*big.Int, error)        { return big.NewInt(0), nil }
-func (c *StubClient) GetGlobalTotalSupply() (*big.Int, error)     { return big.NewInt(0), nil }
+func (c *StubClient) GetTranscoderPoolMaxSize(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+func (c *StubClient) RoundLength(ctx context.Context) (*big.Int, error) { return big.NewInt(0), nil }
+func (c *StubClient) RoundLockAmount(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+func (c *StubClient) UnbondingPeriod(ctx context.Context) (uint64, error) { return 0, nil }
+func (c *StubClient) Inflation(ctx context.Context) (*big.Int, error)     { return big.NewInt(0), nil }
+func (c *StubClient) InflationChange(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+func (c *StubClient) TargetBondingRate(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+func (c *StubClient) GetGlobalTotalSupply(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
 
 // Helpers
 
-func (c *StubClient) ContractAddresses() map[string]common.Address { return nil }
-func (c *StubClient) CheckTx(tx *types.Transaction) error {
+func (c *StubClient) ContractAddresses() map[string]common.Address { return c.ContractAddrs }
+func (c *StubClient) CheckTx(ctx context.Context, tx *types.Transaction) error {
 	return c.CheckTxErr
 }
-func (c *StubClient) ReplaceTransaction(tx *types.Transaction, method string, gasPrice *big.Int) (*types.Transaction, error) {
+func (c *StubClient) ReplaceTransaction(ctx context.Context, txHash common.Hash, gasPriceMultiplier float64) (*types.Transaction, error) {
 	return nil, nil
 }
-func (c *StubClient) Sign(msg []byte) ([]byte, error) { return msg, c.Err }
+func (c *StubClient) PendingApprovals() []*PendingTx { return c.PendingTxs }
+func (c *StubClient) ApproveTransaction(ctx context.Context, id uint64) (*types.Transaction, error) {
+	return types.NewTx(&types.DynamicFeeTx{}), c.Err
+}
+func (c *StubClient) RejectTransaction(id uint64) error { return c.Err }
+func (c *StubClient) Sign(msg []byte) ([]byte, error)   { return msg, c.Err }
 func (c *StubClient) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
 	return []byte("foo"), c.Err
 }
-func (c *StubClient) SetGasInfo(uint64) error       { return nil }
-func (c *StubClient) SetMaxGasPrice(*big.Int) error { return nil }
+func (c *StubClient) SetGasInfo(uint64) error               { return nil }
+func (c *StubClient) SetMaxGasPrice(*big.Int) error         { return nil }
+func (c *StubClient) WithGasFees(GasFees) LivepeerEthClient { return c }
+func (c *StubClient) ResyncNonce() error                    { return nil }
+func (c *StubClient) EstimateTxCost(ctx context.Context, send func(client LivepeerEthClient) (*types.Transaction, error)) (*TxCost, error) {
+	if _, err := send(c); err != nil {
+		return nil, err
+	}
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	return &TxCost{GasPrice: big.NewInt(0), Value: big.NewInt(0), Cost: big.NewInt(0), MaxCost: big.NewInt(0)}, nil
+}
 
 // Faucet
-func (c *StubClient) NextValidRequest(common.Address) (*big.Int, error) { return nil, nil }
+func (c *StubClient) NextValidRequest(ctx context.Context, addr common.Address) (*big.Int, error) {
+	return nil, nil
+}
 
 // Governance
-func (c *StubClient) Vote(pollAddr ethcommon.Address, choiceID *big.Int) (*types.Transaction, error) {
+func (c *StubClient) Vote(ctx context.Context, pollAddr ethcommon.Address, choiceID *big.Int) (*types.Transaction, error) {
 	return types.NewTx(&types.DynamicFeeTx{}), c.Err
 }
+func (c *StubClient) Polls(ctx context.Context, fromBlock *big.Int) ([]*lpTypes.Poll, error) {
+	return c.GovernancePolls, c.Err
+}
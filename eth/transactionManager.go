@@ -2,7 +2,9 @@ package eth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"strings"
 	"sync"
@@ -15,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/golang/glog"
 	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
 )
 
 // The default price bump required by geth is 10%
@@ -22,20 +25,66 @@ import (
 // priceBump is a % value from 0-100
 const priceBump uint64 = 11
 
+// blockPollInterval is the interval at which the block number is polled to replace txs that are not mined within
+// bumpBlocks blocks and to confirm receipts if the block watcher is not set
+var blockPollInterval = 5 * time.Second
+
+// errTxReplaced is returned by wait if the tx was replaced by ReplaceTransaction
+var errTxReplaced = errors.New("transaction replaced")
+
+// errTxNotMined is returned by wait if the tx was not mined within bumpBlocks blocks
+var errTxNotMined = errors.New("transaction not mined")
+
+// errReceiptAbandoned is returned by wait if waiting for the receipt was abandoned with AbandonReceipt
+var errReceiptAbandoned = errors.New("stopped waiting for transaction receipt")
+
 type transactionSenderReader interface {
 	ethereum.TransactionSender
 	ethereum.TransactionReader
 	// Required for bind.DeployBackend argument in bind.WaitMined
 	CodeAt(context.Context, ethcommon.Address, *big.Int) ([]byte, error)
+	BlockNumber(context.Context) (uint64, error)
 }
 
+// blockSubscriber notifies of the blocks seen by the block watcher, i.e. the block watcher shared with the event
+// watchers
+type blockSubscriber interface {
+	Subscribe(sink chan<- []*blockwatch.Event) event.Subscription
+}
+
+// TxJournal records the txs submitted by the TransactionManager, i.e. the DB
+type TxJournal interface {
+	InsertTxJournalEntry(entry *common.DBTxJournalEntry) error
+}
+
+// The statuses of the entries of the TxJournal
+const (
+	txStatusSent       = "sent"
+	txStatusSendFailed = "send_failed"
+	txStatusMined      = "mined"
+	txStatusReverted   = "reverted"
+	txStatusFailed     = "failed"
+)
+
 type transactionSigner interface {
 	SignTx(tx *types.Transaction) (*types.Transaction, error)
 }
 
+// accountTransactionSigner is a transactionSigner for several accounts, i.e. a MultiAccountManager
+type accountTransactionSigner interface {
+	SignTxFor(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error)
+}
+
 type TransactionManager struct {
 	txTimeout       time.Duration
 	maxReplacements int
+	// bumpBlocks is the number of blocks after which a pending tx is replaced. If 0, txs are only replaced after txTimeout
+	bumpBlocks uint64
+	// confirmations is the number of blocks that must be mined on top of the block of a tx before its receipt is
+	// reported. If 0, receipts are reported as soon as the tx is mined
+	confirmations uint64
+	// methodConfirmations overrides confirmations for the txs of the contract methods in it, i.e. "bond"
+	methodConfirmations map[string]uint64
 
 	queue transactionQueue
 
@@ -49,6 +98,22 @@ type TransactionManager struct {
 
 	cond *sync.Cond
 
+	// replaced maps the hashes of the txs replaced by ReplaceTransaction to their replacements
+	replaced map[ethcommon.Hash]*types.Transaction
+	// waiting is the hash of the tx that is waited for by checkTxLoop and cancelWait stops waiting for it
+	waiting    ethcommon.Hash
+	cancelWait context.CancelFunc
+	// abandoned is set if waiting for the receipt of the tx that is waited for was abandoned with AbandonReceipt
+	abandoned bool
+	mu        sync.Mutex
+
+	// heartbeat reports the progress of checkTxLoop to a watchdog
+	heartbeat *common.Heartbeat
+	// journal records the submitted txs and their outcome
+	journal TxJournal
+	// blocks notifies of new blocks instead of polling the block number, if set
+	blocks blockSubscriber
+
 	quit chan struct{}
 }
 
@@ -84,11 +149,13 @@ func (tq transactionQueue) peek() *types.Transaction {
 	return tq[0]
 }
 
-func NewTransactionManager(eth transactionSenderReader, gpm *GasPriceMonitor, signer transactionSigner, txTimeout time.Duration, maxReplacements int) *TransactionManager {
+func NewTransactionManager(eth transactionSenderReader, gpm *GasPriceMonitor, signer transactionSigner, txTimeout time.Duration, maxReplacements int, bumpBlocks, confirmations uint64) *TransactionManager {
 	return &TransactionManager{
 		cond:            sync.NewCond(&sync.Mutex{}),
 		txTimeout:       txTimeout,
 		maxReplacements: maxReplacements,
+		bumpBlocks:      bumpBlocks,
+		confirmations:   confirmations,
 		eth:             eth,
 		gpm:             gpm,
 		sig:             signer,
@@ -98,7 +165,7 @@ func NewTransactionManager(eth transactionSenderReader, gpm *GasPriceMonitor, si
 }
 
 func (tm *TransactionManager) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	sendErr := tm.eth.SendTransaction(ctx, tx)
+	sendErr := tm.eth.SendTransaction(WithCriticalRPC(ctx), tx)
 
 	txLog, err := newTxLog(tx)
 	if err != nil {
@@ -107,8 +174,10 @@ func (tm *TransactionManager) SendTransaction(ctx context.Context, tx *types.Tra
 
 	if sendErr != nil {
 		glog.Infof("\n%vEth Transaction%v\n\nInvoking transaction: \"%v\". Inputs: \"%v\"   \nTransaction Failed: %v\n\n%v\n", strings.Repeat("*", 30), strings.Repeat("*", 30), txLog.method, txLog.inputs, sendErr, strings.Repeat("*", 75))
+		tm.record(tx, txLog, ethcommon.Hash{}, txStatusSendFailed, nil, sendErr)
 		return sendErr
 	}
+	tm.record(tx, txLog, ethcommon.Hash{}, txStatusSent, nil, nil)
 
 	// Add transaction to queue
 	tm.cond.L.Lock()
@@ -134,15 +203,286 @@ func (tm *TransactionManager) Stop() {
 	close(tm.quit)
 }
 
-func (tm *TransactionManager) wait(tx *types.Transaction) (*types.Receipt, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), tm.txTimeout)
+// SetHeartbeat sets the heartbeat that reports the progress of waiting for receipts to a watchdog. It must be called
+// before Start
+func (tm *TransactionManager) SetHeartbeat(hb *common.Heartbeat) {
+	tm.heartbeat = hb
+}
+
+// SetJournal sets the journal that records the submitted txs. It must be called before Start
+func (tm *TransactionManager) SetJournal(journal TxJournal) {
+	tm.journal = journal
+}
+
+// SetMethodConfirmations sets the number of confirmations of the txs of the contract methods in confirmations, i.e.
+// "initializeRound", instead of the number of confirmations of the txs of the other methods. It must be called before
+// Start
+func (tm *TransactionManager) SetMethodConfirmations(confirmations map[string]uint64) {
+	tm.methodConfirmations = confirmations
+}
+
+// txConfirmations returns the number of blocks that must be mined on top of the block of tx before its receipt is
+// reported
+func (tm *TransactionManager) txConfirmations(tx *types.Transaction) uint64 {
+	if len(tm.methodConfirmations) > 0 {
+		if txLog, err := newTxLog(tx); err == nil {
+			if confirmations, ok := tm.methodConfirmations[txLog.method]; ok {
+				return confirmations
+			}
+		}
+	}
+	return tm.confirmations
+}
+
+// SetBlockWatcher sets the block watcher that new blocks are read from to replace and confirm txs instead of polling
+// the block number. It can be called after Start, the txs that are already waited for keep polling
+func (tm *TransactionManager) SetBlockWatcher(blocks blockSubscriber) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.blocks = blocks
+}
+
+// record appends an entry for tx to the journal if it is set. replaces is the hash of the tx that tx replaced, if any
+func (tm *TransactionManager) record(tx *types.Transaction, txLog txLog, replaces ethcommon.Hash, status string, receipt *types.Receipt, txErr error) {
+	if tm.journal == nil {
+		return
+	}
+
+	entry := &common.DBTxJournalEntry{
+		Hash:      tx.Hash(),
+		Replaces:  replaces,
+		Method:    txLog.method,
+		Inputs:    txLog.inputs,
+		Nonce:     tx.Nonce(),
+		Gas:       tx.Gas(),
+		GasPrice:  calcGasPrice(tx),
+		Status:    status,
+		CreatedAt: time.Now(),
+	}
+	if receipt != nil {
+		if receipt.BlockNumber != nil {
+			entry.BlockNumber = receipt.BlockNumber.Uint64()
+		}
+		entry.GasUsed = receipt.GasUsed
+	}
+	if txErr != nil {
+		entry.Error = txErr.Error()
+	}
+	if err := tm.journal.InsertTxJournalEntry(entry); err != nil {
+		glog.Errorf("Error recording tx in journal tx=%v err=%q", tx.Hash().Hex(), err)
+	}
+}
+
+// AbandonReceipt stops waiting for the receipt of the tx that is waited for, i.e. if the ETH node stopped responding.
+// The tx is reported as failed so that the txs queued after it are not held up
+func (tm *TransactionManager) AbandonReceipt() {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if tm.cancelWait == nil {
+		return
+	}
+	glog.Errorf("Abandoning receipt of tx=%v", tm.waiting.Hex())
+	tm.abandoned = true
+	tm.cancelWait()
+}
+
+// wait waits for tx to be mined. If bump is true and bumpBlocks is set, it returns errTxNotMined if tx is not mined
+// within bumpBlocks blocks
+func (tm *TransactionManager) wait(tx *types.Transaction, bump bool) (*types.Receipt, error) {
+	// Checking for the receipt is not throttled by the RPC budget
+	ctx, cancel := context.WithTimeout(WithCriticalRPC(context.Background()), tm.txTimeout)
 	defer cancel()
 
-	return bind.WaitMined(ctx, tm.eth, tx)
+	if common.InjectFault(common.FaultDropReceipt) != nil {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	waitCtx, cancelWait := context.WithCancel(ctx)
+	defer cancelWait()
+
+	tm.mu.Lock()
+	if _, ok := tm.replaced[tx.Hash()]; ok {
+		tm.mu.Unlock()
+		return nil, errTxReplaced
+	}
+	tm.waiting = tx.Hash()
+	tm.cancelWait = cancelWait
+	tm.mu.Unlock()
+	defer func() {
+		tm.mu.Lock()
+		tm.waiting = ethcommon.Hash{}
+		tm.cancelWait = nil
+		tm.abandoned = false
+		tm.mu.Unlock()
+	}()
+
+	notMined := make(chan struct{})
+	if bump && tm.bumpBlocks > 0 {
+		go tm.watchBlocks(waitCtx, cancelWait, notMined)
+	}
+
+	receipt, err := bind.WaitMined(waitCtx, tm.eth, tx)
+	if err != nil && ctx.Err() == nil {
+		// Stopped waiting before the timeout
+		select {
+		case <-notMined:
+			return nil, errTxNotMined
+		default:
+		}
+		if tm.replacement(tx) != nil {
+			return nil, errTxReplaced
+		}
+		tm.mu.Lock()
+		abandoned := tm.abandoned
+		tm.mu.Unlock()
+		if abandoned {
+			return nil, errReceiptAbandoned
+		}
+	}
+	return receipt, err
+}
+
+// watchBlocks closes notMined and stops waiting if bumpBlocks blocks are mined before ctx is done
+func (tm *TransactionManager) watchBlocks(ctx context.Context, cancelWait context.CancelFunc, notMined chan struct{}) {
+	var start uint64
+	blocks := tm.blockNumbers(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case blk := <-blocks:
+			if start == 0 {
+				start = blk
+			} else if blk >= start+tm.bumpBlocks {
+				close(notMined)
+				cancelWait()
+				return
+			}
+		}
+	}
+}
+
+// confirm waits until confirmations blocks are mined on top of the block of receipt and returns the receipt of the tx
+// at that depth. If the tx is reorged into another block, it waits for the confirmations of the new block instead. If
+// the tx is no longer part of the chain, it returns receipt and ErrReceiptReverted
+func (tm *TransactionManager) confirm(receipt *types.Receipt, confirmations uint64) (*types.Receipt, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-tm.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	blocks := tm.blockNumbers(ctx)
+	for {
+		var blk uint64
+		select {
+		case <-ctx.Done():
+			return receipt, ctx.Err()
+		case blk = <-blocks:
+		}
+
+		if receipt.BlockNumber != nil && blk < receipt.BlockNumber.Uint64()+confirmations {
+			continue
+		}
+		current, err := tm.eth.TransactionReceipt(ctx, receipt.TxHash)
+		if err == ethereum.NotFound {
+			glog.Errorf("Transaction reverted by chain reorg txHash=%v block=%v", receipt.TxHash.Hex(), receipt.BlockNumber)
+			return receipt, ErrReceiptReverted
+		}
+		if err != nil {
+			glog.V(common.DEBUG).Infof("Error getting transaction receipt txHash=%v err=%q", receipt.TxHash.Hex(), err)
+		} else if current.BlockHash == receipt.BlockHash {
+			return current, nil
+		} else {
+			glog.Warningf("Transaction reorged into another block txHash=%v block=%v newBlock=%v", receipt.TxHash.Hex(), receipt.BlockNumber, current.BlockNumber)
+			receipt = current
+		}
+	}
+}
+
+// blockNumbers returns a channel of the numbers of the new blocks until ctx is done. The numbers are read from the
+// block watcher if it is set, otherwise the block number is polled every blockPollInterval
+func (tm *TransactionManager) blockNumbers(ctx context.Context) <-chan uint64 {
+	tm.mu.Lock()
+	blocks := tm.blocks
+	tm.mu.Unlock()
+
+	numbers := make(chan uint64)
+	if blocks == nil {
+		go tm.pollBlockNumbers(ctx, numbers)
+	} else {
+		go tm.watchBlockNumbers(ctx, blocks, numbers)
+	}
+	return numbers
+}
+
+func (tm *TransactionManager) pollBlockNumbers(ctx context.Context, numbers chan<- uint64) {
+	ticker := time.NewTicker(blockPollInterval)
+	defer ticker.Stop()
+	for {
+		blk, err := tm.eth.BlockNumber(ctx)
+		if err != nil {
+			glog.V(common.DEBUG).Infof("Error getting block number err=%q", err)
+		} else {
+			select {
+			case numbers <- blk:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchBlockNumbers sends the number of the latest block of each batch of block events. The number goes down when
+// blocks are removed by a reorg. The block number is polled instead if the subscription fails
+func (tm *TransactionManager) watchBlockNumbers(ctx context.Context, blocks blockSubscriber, numbers chan<- uint64) {
+	events := make(chan []*blockwatch.Event, 10)
+	sub := blocks.Subscribe(events)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-sub.Err():
+			glog.Errorf("Block event subscription failed, polling the block number instead err=%q", err)
+			tm.pollBlockNumbers(ctx, numbers)
+			return
+		case evts := <-events:
+			if len(evts) == 0 {
+				continue
+			}
+			last := evts[len(evts)-1].BlockHeader
+			if last == nil || last.Number == nil {
+				continue
+			}
+			blk := last.Number.Uint64()
+			if evts[len(evts)-1].Type == blockwatch.Removed && blk > 0 {
+				blk--
+			}
+			select {
+			case numbers <- blk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
 }
 
 func (tm *TransactionManager) replace(tx *types.Transaction) (*types.Transaction, error) {
-	_, pending, err := tm.eth.TransactionByHash(context.Background(), tx.Hash())
+	ctx := WithCriticalRPC(context.Background())
+	_, pending, err := tm.eth.TransactionByHash(ctx, tx.Hash())
 	// Only return here if the error is not related to the tx not being found
 	// Presumably the provided tx was already broadcasted at some point, so even if for some reason the
 	// node being used cannot find it, the originally broadcasted tx is still valid and might be sitting somewhere
@@ -155,7 +495,57 @@ func (tm *TransactionManager) replace(tx *types.Transaction) (*types.Transaction
 		return nil, ErrReplacingMinedTx
 	}
 
-	newRawTx := newReplacementTx(tx)
+	return tm.sendReplacement(ctx, tx, priceBump)
+}
+
+// ReplaceTransaction replaces the pending tx with txHash, which must have been sent by the account of the node, with a
+// tx that has the same nonce and a gas price multiplied by gasPriceMultiplier. The receipt of the replacement is
+// reported for the replaced tx
+func (tm *TransactionManager) ReplaceTransaction(ctx context.Context, txHash ethcommon.Hash, gasPriceMultiplier float64) (*types.Transaction, error) {
+	bump := math.Round((gasPriceMultiplier - 1) * 100)
+	if bump < float64(priceBump) {
+		return nil, fmt.Errorf("gas price multiplier too low multiplier=%v min=%v", gasPriceMultiplier, 1+float64(priceBump)/100)
+	}
+
+	ctx = WithCriticalRPC(ctx)
+
+	tx, pending, err := tm.eth.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if !pending {
+		return nil, ErrReplacingMinedTx
+	}
+
+	newTx, err := tm.sendReplacement(ctx, tx, uint64(bump))
+	if err != nil {
+		return nil, err
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.replaced == nil {
+		tm.replaced = make(map[ethcommon.Hash]*types.Transaction)
+	}
+	tm.replaced[txHash] = newTx
+	if tm.waiting == txHash && tm.cancelWait != nil {
+		// Wait for the replacement instead
+		tm.cancelWait()
+	}
+
+	return newTx, nil
+}
+
+// replacement returns the tx that replaced tx with ReplaceTransaction or nil if tx was not replaced
+func (tm *TransactionManager) replacement(tx *types.Transaction) *types.Transaction {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.replaced[tx.Hash()]
+}
+
+// sendReplacement sends a replacement for tx with a gas price bumped by bump %
+func (tm *TransactionManager) sendReplacement(ctx context.Context, tx *types.Transaction, bump uint64) (*types.Transaction, error) {
+	newRawTx := newBumpedTx(tx, bump)
 
 	// Bump gas price exceeds max gas price, return early
 	max := tm.gpm.MaxGasPrice()
@@ -164,20 +554,22 @@ func (tm *TransactionManager) replace(tx *types.Transaction) (*types.Transaction
 		return nil, fmt.Errorf("replacement gas price exceeds max gas price suggested=%v max=%v", newGasPrice, max)
 	}
 
-	newSignedTx, err := tm.sig.SignTx(newRawTx)
+	newSignedTx, err := tm.signReplacement(tx, newRawTx)
 	if err != nil {
 		return nil, err
 	}
 
-	sendErr := tm.eth.SendTransaction(context.Background(), newSignedTx)
+	sendErr := tm.eth.SendTransaction(ctx, newSignedTx)
 	txLog, err := newTxLog(tx)
 	if err != nil {
 		txLog.method = "unknown"
 	}
 	if sendErr != nil {
 		glog.Infof("\n%vEth Transaction%v\n\nReplacement transaction: \"%v\". \nTransaction Failed: %v\n\n%v\n", strings.Repeat("*", 30), strings.Repeat("*", 30), txLog.method, sendErr, strings.Repeat("*", 75))
+		tm.record(newSignedTx, txLog, tx.Hash(), txStatusSendFailed, nil, sendErr)
 	} else {
 		glog.Infof("\n%vEth Transaction%v\n\nReplacement transaction: \"%v\".  Hash: \"%v\". \n\n%v\n", strings.Repeat("*", 30), strings.Repeat("*", 30), txLog.method, newSignedTx.Hash().String(), strings.Repeat("*", 75))
+		tm.record(newSignedTx, txLog, tx.Hash(), txStatusSent, nil, nil)
 	}
 
 	return newSignedTx, sendErr
@@ -201,22 +593,51 @@ func (tm *TransactionManager) checkTxLoop() {
 		tx := tm.queue.pop()
 		tm.cond.L.Unlock()
 
+		tm.heartbeat.Busy()
+
 		originHash := tx.Hash()
 
 		var txReceipt types.Receipt
 
-		receipt, err := tm.wait(tx)
+		replaced := []ethcommon.Hash{}
 
-		// context.DeadlineExceeded indicates that we hit the txTimeout
-		// If we hit the txTimeout, replace the tx up to maxReplacements times
-		for i := 0; err == context.DeadlineExceeded && i < tm.maxReplacements; i++ {
-			tx, err = tm.replace(tx)
+		receipt, err := tm.wait(tx, tm.maxReplacements > 0)
+
+		// context.DeadlineExceeded indicates that we hit the txTimeout and errTxNotMined that the tx was not mined
+		// within bumpBlocks blocks. In both cases replace the tx up to maxReplacements times
+		for i := 0; ; {
+			if err == errTxReplaced {
+				// Wait for the tx that replaced the tx with ReplaceTransaction
+				replaced = append(replaced, tx.Hash())
+				tx = tm.replacement(tx)
+				receipt, err = tm.wait(tx, i < tm.maxReplacements)
+				continue
+			}
+			if (err != context.DeadlineExceeded && err != errTxNotMined) || i >= tm.maxReplacements {
+				break
+			}
+			i++
+			tm.heartbeat.Beat()
+			newTx, replaceErr := tm.replace(tx)
 			// Do not attempt additional replacements if there was an error submitting this
 			// replacement tx
-			if err != nil {
+			if replaceErr != nil {
+				err = replaceErr
 				break
 			}
-			receipt, err = tm.wait(tx)
+			tx = newTx
+			receipt, err = tm.wait(tx, i < tm.maxReplacements)
+		}
+
+		tm.mu.Lock()
+		for _, h := range replaced {
+			delete(tm.replaced, h)
+		}
+		tm.mu.Unlock()
+
+		if confirmations := tm.txConfirmations(tx); err == nil && confirmations > 0 {
+			tm.heartbeat.Beat()
+			receipt, err = tm.confirm(receipt, confirmations)
 		}
 
 		if receipt == nil {
@@ -225,12 +646,27 @@ func (tm *TransactionManager) checkTxLoop() {
 			txReceipt = *(receipt)
 		}
 
+		if tm.journal != nil {
+			txLog, logErr := newTxLog(tx)
+			if logErr != nil {
+				txLog.method = "unknown"
+			}
+			status := txStatusMined
+			if err != nil {
+				status = txStatusFailed
+			} else if txReceipt.Status == types.ReceiptStatusFailed {
+				status = txStatusReverted
+			}
+			tm.record(tx, txLog, ethcommon.Hash{}, status, receipt, err)
+		}
+
 		tm.feed.Send(&transactionReceipt{
 			originTxHash: originHash,
 			Receipt:      txReceipt,
 			err:          err,
 		})
 
+		tm.heartbeat.Idle()
 	}
 }
 
@@ -254,12 +690,17 @@ func calcGasPrice(tx *types.Transaction) *big.Int {
 }
 
 func newReplacementTx(tx *types.Transaction) *types.Transaction {
+	return newBumpedTx(tx, priceBump)
+}
+
+// newBumpedTx returns a tx with the same nonce as tx and a gas price bumped by bump %
+func newBumpedTx(tx *types.Transaction, bump uint64) *types.Transaction {
 	var baseTx types.TxData
 	if tx.GasFeeCap() == nil {
 		// legacy tx, not London ready
 		baseTx = &types.LegacyTx{
 			Nonce:    tx.Nonce(),
-			GasPrice: applyPriceBump(tx.GasPrice(), priceBump),
+			GasPrice: applyPriceBump(tx.GasPrice(), bump),
 			Gas:      tx.Gas(),
 			To:       tx.To(),
 			Value:    tx.Value(),
@@ -269,8 +710,8 @@ func newReplacementTx(tx *types.Transaction) *types.Transaction {
 		baseTx = &types.DynamicFeeTx{
 			Nonce: tx.Nonce(),
 			// geth requires the price bump to be applied to both the gas tip cap and gas fee cap
-			GasFeeCap: applyPriceBump(tx.GasFeeCap(), priceBump),
-			GasTipCap: applyPriceBump(tx.GasTipCap(), priceBump),
+			GasFeeCap: applyPriceBump(tx.GasFeeCap(), bump),
+			GasTipCap: applyPriceBump(tx.GasTipCap(), bump),
 			Gas:       tx.Gas(),
 			Value:     tx.Value(),
 			Data:      tx.Data(),
@@ -280,3 +721,17 @@ func newReplacementTx(tx *types.Transaction) *types.Transaction {
 
 	return types.NewTx(baseTx)
 }
+
+// signReplacement signs newRawTx, the replacement for tx, with the account that sent tx
+func (tm *TransactionManager) signReplacement(tx, newRawTx *types.Transaction) (*types.Transaction, error) {
+	sig, ok := tm.sig.(accountTransactionSigner)
+	if !ok {
+		return tm.sig.SignTx(newRawTx)
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, err
+	}
+	return sig.SignTxFor(sender, newRawTx)
+}
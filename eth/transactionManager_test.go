@@ -5,16 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/golang/glog"
+	lpcommon "github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
+	"github.com/livepeer/go-livepeer/eth/contracts"
 	"github.com/livepeer/go-livepeer/pm"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type stubTransactionSenderReader struct {
@@ -23,6 +31,11 @@ type stubTransactionSenderReader struct {
 	tx              *types.Transaction
 	receipt         *types.Receipt
 	callsToTxByHash int //reflects number of calls to replace()
+	blockNumber     uint64
+	// mined are the txs for which a successful receipt is returned
+	mined map[common.Hash]bool
+	// droppedAt is the block number from which the mined txs are no longer part of the chain. Ignored if 0
+	droppedAt uint64
 }
 
 func (stm *stubTransactionSenderReader) SendTransaction(ctx context.Context, tx *types.Transaction) error {
@@ -35,6 +48,15 @@ func (stm *stubTransactionSenderReader) TransactionByHash(ctx context.Context, t
 }
 
 func (stm *stubTransactionSenderReader) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if stm.mined[txHash] {
+		if stm.droppedAt > 0 && atomic.LoadUint64(&stm.blockNumber) >= stm.droppedAt {
+			return nil, ethereum.NotFound
+		}
+		receipt := types.NewReceipt(pm.RandHash().Bytes(), false, 100000)
+		receipt.TxHash = txHash
+		receipt.BlockNumber = big.NewInt(1)
+		return receipt, nil
+	}
 	return stm.receipt, stm.err["TransactionReceipt"]
 }
 
@@ -42,6 +64,29 @@ func (stm *stubTransactionSenderReader) CodeAt(ctx context.Context, account comm
 	return []byte{}, stm.err["CodeAt"]
 }
 
+func (stm *stubTransactionSenderReader) BlockNumber(ctx context.Context) (uint64, error) {
+	// Every call mines a block
+	return atomic.AddUint64(&stm.blockNumber, 1), nil
+}
+
+type stubTxJournal struct {
+	mu      sync.Mutex
+	entries []*lpcommon.DBTxJournalEntry
+}
+
+func (j *stubTxJournal) InsertTxJournalEntry(entry *lpcommon.DBTxJournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+func (j *stubTxJournal) Entries() []*lpcommon.DBTxJournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]*lpcommon.DBTxJournalEntry{}, j.entries...)
+}
+
 type stubTransactionSigner struct {
 	err error
 }
@@ -133,7 +178,7 @@ func TestTransactionManager_Wait(t *testing.T) {
 
 	tx := types.NewTransaction(1, pm.RandAddress(), big.NewInt(100), 100000, big.NewInt(100), pm.RandBytes(68))
 
-	receipt, err := tm.wait(tx)
+	receipt, err := tm.wait(tx, false)
 	assert.Nil(receipt)
 	assert.EqualError(err, expErr.Error())
 
@@ -141,7 +186,7 @@ func TestTransactionManager_Wait(t *testing.T) {
 	eth.receipt = types.NewReceipt(pm.RandHash().Bytes(), false, 100000)
 	eth.err = nil
 
-	receipt, err = tm.wait(tx)
+	receipt, err = tm.wait(tx, false)
 	assert.Equal(receipt.Status, uint64(1))
 	assert.Equal(receipt.CumulativeGasUsed, uint64(100000))
 	assert.Nil(err)
@@ -356,6 +401,455 @@ func TestTransactionManager_CheckTxLoop(t *testing.T) {
 	sub.Unsubscribe()
 }
 
+func TestTransactionManager_ReplaceTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	eth := &stubTransactionSenderReader{
+		err: make(map[string]error),
+	}
+	gpm := &GasPriceMonitor{
+		minGasPrice: big.NewInt(0),
+		maxGasPrice: big.NewInt(99999999),
+		gasPrice:    big.NewInt(1),
+	}
+	tm := &TransactionManager{
+		cond:      sync.NewCond(&sync.Mutex{}),
+		eth:       eth,
+		txTimeout: 2 * time.Second,
+		gpm:       gpm,
+		sig:       &stubTransactionSigner{},
+	}
+
+	stubTx := newStubLegacyTx(big.NewInt(100))
+	eth.tx = stubTx
+
+	// Multiplier lower than the minimum price bump
+	tx, err := tm.ReplaceTransaction(context.Background(), stubTx.Hash(), 1.1)
+	assert.Nil(tx)
+	assert.EqualError(err, "gas price multiplier too low multiplier=1.1 min=1.11")
+
+	// TransactionByHash error, including unknown txs
+	eth.err["TransactionByHash"] = ethereum.NotFound
+	tx, err = tm.ReplaceTransaction(context.Background(), stubTx.Hash(), 1.5)
+	assert.Nil(tx)
+	assert.Equal(ethereum.NotFound, err)
+	eth.err["TransactionByHash"] = nil
+
+	// Tx already mined
+	eth.pending = false
+	tx, err = tm.ReplaceTransaction(context.Background(), stubTx.Hash(), 1.5)
+	assert.Nil(tx)
+	assert.Equal(ErrReplacingMinedTx, err)
+
+	// Replacement gas price exceeds max gas price
+	eth.pending = true
+	gpm.maxGasPrice = big.NewInt(149)
+	tx, err = tm.ReplaceTransaction(context.Background(), stubTx.Hash(), 1.5)
+	assert.Nil(tx)
+	assert.EqualError(err, "replacement gas price exceeds max gas price suggested=150 max=149")
+	assert.Nil(tm.replacement(stubTx))
+
+	// Success
+	gpm.maxGasPrice = big.NewInt(99999999)
+	tx, err = tm.ReplaceTransaction(context.Background(), stubTx.Hash(), 1.5)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(150), tx.GasPrice())
+	assert.Equal(stubTx.Nonce(), tx.Nonce())
+	assert.Equal(tx, tm.replacement(stubTx))
+}
+
+func TestTransactionManager_CheckTxLoop_ReplaceTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	stubTx := newStubLegacyTx(big.NewInt(100))
+	replacement := newBumpedTx(stubTx, 100)
+	eth := &stubTransactionSenderReader{
+		err:     make(map[string]error),
+		pending: true,
+		tx:      stubTx,
+		mined:   map[common.Hash]bool{replacement.Hash(): true},
+	}
+	tm := &TransactionManager{
+		cond:      sync.NewCond(&sync.Mutex{}),
+		eth:       eth,
+		txTimeout: time.Minute,
+		gpm: &GasPriceMonitor{
+			minGasPrice: big.NewInt(0),
+			gasPrice:    big.NewInt(1),
+		},
+		sig:  &stubTransactionSigner{},
+		quit: make(chan struct{}),
+	}
+
+	go tm.Start()
+	defer tm.Stop()
+
+	sink := make(chan *transactionReceipt)
+	sub := tm.Subscribe(sink)
+	defer sub.Unsubscribe()
+
+	// The loop waits for the replacement and reports its receipt for the original tx
+	assert.Nil(tm.SendTransaction(context.Background(), stubTx))
+	time.Sleep(100 * time.Millisecond)
+	tx, err := tm.ReplaceTransaction(context.Background(), stubTx.Hash(), 2)
+	assert.Nil(err)
+	assert.Equal(replacement.Hash(), tx.Hash())
+
+	select {
+	case event := <-sink:
+		assert.Nil(event.err)
+		assert.Equal(stubTx.Hash(), event.originTxHash)
+		assert.Equal(replacement.Hash(), event.TxHash)
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for receipt")
+	}
+	assert.Nil(tm.replacement(stubTx))
+}
+
+func TestTransactionManager_Journal(t *testing.T) {
+	assert := assert.New(t)
+
+	stubTx := newStubLegacyTx(big.NewInt(100))
+	replacement := newBumpedTx(stubTx, 100)
+	eth := &stubTransactionSenderReader{
+		err:     make(map[string]error),
+		pending: true,
+		tx:      stubTx,
+		mined:   map[common.Hash]bool{replacement.Hash(): true},
+	}
+	tm := &TransactionManager{
+		cond:      sync.NewCond(&sync.Mutex{}),
+		eth:       eth,
+		txTimeout: time.Minute,
+		gpm: &GasPriceMonitor{
+			minGasPrice: big.NewInt(0),
+			gasPrice:    big.NewInt(1),
+		},
+		sig:  &stubTransactionSigner{},
+		quit: make(chan struct{}),
+	}
+	journal := &stubTxJournal{}
+	tm.SetJournal(journal)
+
+	go tm.Start()
+	defer tm.Stop()
+
+	sink := make(chan *transactionReceipt)
+	sub := tm.Subscribe(sink)
+	defer sub.Unsubscribe()
+
+	// The tx, its replacement and the receipt of the replacement are recorded
+	assert.Nil(tm.SendTransaction(context.Background(), stubTx))
+	time.Sleep(100 * time.Millisecond)
+	_, err := tm.ReplaceTransaction(context.Background(), stubTx.Hash(), 2)
+	assert.Nil(err)
+
+	select {
+	case <-sink:
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for receipt")
+	}
+
+	entries := journal.Entries()
+	if assert.Len(entries, 3) {
+		assert.Equal(stubTx.Hash(), entries[0].Hash)
+		assert.Equal(txStatusSent, entries[0].Status)
+		assert.Equal(stubTx.Nonce(), entries[0].Nonce)
+		assert.Equal(stubTx.Gas(), entries[0].Gas)
+		assert.Equal(big.NewInt(100), entries[0].GasPrice)
+		assert.Equal((common.Hash{}), entries[0].Replaces)
+		assert.Equal(replacement.Hash(), entries[1].Hash)
+		assert.Equal(stubTx.Hash(), entries[1].Replaces)
+		assert.Equal(txStatusSent, entries[1].Status)
+		assert.Equal(big.NewInt(200), entries[1].GasPrice)
+		assert.Equal(replacement.Hash(), entries[2].Hash)
+		assert.Equal(txStatusMined, entries[2].Status)
+		assert.Equal(uint64(1), entries[2].BlockNumber)
+		assert.Empty(entries[2].Error)
+	}
+
+	// Txs that could not be sent are recorded with the error
+	eth.err["SendTransaction"] = errors.New("nonce too low")
+	assert.EqualError(tm.SendTransaction(context.Background(), stubTx), "nonce too low")
+	entries = journal.Entries()
+	if assert.Len(entries, 4) {
+		assert.Equal(stubTx.Hash(), entries[3].Hash)
+		assert.Equal(txStatusSendFailed, entries[3].Status)
+		assert.Equal("nonce too low", entries[3].Error)
+	}
+}
+
+func TestTransactionManager_CheckTxLoop_AbandonReceipt(t *testing.T) {
+	assert := assert.New(t)
+
+	stubTx := newStubLegacyTx(big.NewInt(100))
+	eth := &stubTransactionSenderReader{
+		err:     map[string]error{"TransactionReceipt": ethereum.NotFound},
+		pending: true,
+	}
+	tm := &TransactionManager{
+		cond:      sync.NewCond(&sync.Mutex{}),
+		eth:       eth,
+		txTimeout: time.Minute,
+		sig:       &stubTransactionSigner{},
+		quit:      make(chan struct{}),
+	}
+
+	// Nothing is waited for
+	tm.AbandonReceipt()
+
+	go tm.Start()
+	defer tm.Stop()
+
+	sink := make(chan *transactionReceipt)
+	sub := tm.Subscribe(sink)
+	defer sub.Unsubscribe()
+
+	// The tx is reported as failed once its receipt is abandoned
+	assert.Nil(tm.SendTransaction(context.Background(), stubTx))
+	time.Sleep(100 * time.Millisecond)
+	tm.AbandonReceipt()
+
+	select {
+	case event := <-sink:
+		assert.Equal(errReceiptAbandoned, event.err)
+		assert.Equal(stubTx.Hash(), event.originTxHash)
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for receipt")
+	}
+	tm.mu.Lock()
+	assert.False(tm.abandoned)
+	tm.mu.Unlock()
+}
+
+func TestTransactionManager_CheckTxLoop_BumpBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(interval time.Duration) { blockPollInterval = interval }(blockPollInterval)
+	blockPollInterval = 10 * time.Millisecond
+
+	stubTx := newStubLegacyTx(big.NewInt(100))
+	replacement := newReplacementTx(stubTx)
+	eth := &stubTransactionSenderReader{
+		err:     make(map[string]error),
+		pending: true,
+		mined:   map[common.Hash]bool{replacement.Hash(): true},
+	}
+	tm := &TransactionManager{
+		maxReplacements: 1,
+		bumpBlocks:      3,
+		cond:            sync.NewCond(&sync.Mutex{}),
+		eth:             eth,
+		txTimeout:       time.Minute,
+		gpm: &GasPriceMonitor{
+			minGasPrice: big.NewInt(0),
+			gasPrice:    big.NewInt(1),
+		},
+		sig:  &stubTransactionSigner{},
+		quit: make(chan struct{}),
+	}
+
+	go tm.Start()
+	defer tm.Stop()
+
+	sink := make(chan *transactionReceipt)
+	sub := tm.Subscribe(sink)
+	defer sub.Unsubscribe()
+
+	// The tx is replaced once it is not mined within bumpBlocks blocks instead of after txTimeout
+	assert.Nil(tm.SendTransaction(context.Background(), stubTx))
+	select {
+	case event := <-sink:
+		assert.Nil(event.err)
+		assert.Equal(stubTx.Hash(), event.originTxHash)
+		assert.Equal(replacement.Hash(), event.TxHash)
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for receipt")
+	}
+	assert.GreaterOrEqual(atomic.LoadUint64(&eth.blockNumber), uint64(4))
+}
+
+func TestTransactionManager_CheckTxLoop_Confirmations(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(interval time.Duration) { blockPollInterval = interval }(blockPollInterval)
+	blockPollInterval = 10 * time.Millisecond
+
+	stubTx := newStubLegacyTx(big.NewInt(100))
+	eth := &stubTransactionSenderReader{
+		err:   make(map[string]error),
+		mined: map[common.Hash]bool{stubTx.Hash(): true},
+	}
+	tm := &TransactionManager{
+		confirmations: 5,
+		cond:          sync.NewCond(&sync.Mutex{}),
+		eth:           eth,
+		txTimeout:     time.Minute,
+		quit:          make(chan struct{}),
+	}
+
+	go tm.Start()
+	defer tm.Stop()
+
+	sink := make(chan *transactionReceipt)
+	sub := tm.Subscribe(sink)
+	defer sub.Unsubscribe()
+
+	// The receipt is reported once 5 blocks are mined on top of the block of the tx
+	assert.Nil(tm.SendTransaction(context.Background(), stubTx))
+	select {
+	case event := <-sink:
+		assert.Nil(event.err)
+		assert.Equal(stubTx.Hash(), event.TxHash)
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for receipt")
+	}
+	assert.GreaterOrEqual(atomic.LoadUint64(&eth.blockNumber), uint64(6))
+
+	// The tx is dropped by a reorg before it is confirmed
+	stubTx = newStubLegacyTx(big.NewInt(200))
+	eth.mined[stubTx.Hash()] = true
+	atomic.StoreUint64(&eth.blockNumber, 0)
+	eth.droppedAt = 3
+	assert.Nil(tm.SendTransaction(context.Background(), stubTx))
+	select {
+	case event := <-sink:
+		assert.Equal(ErrReceiptReverted, event.err)
+		assert.Equal(stubTx.Hash(), event.originTxHash)
+		assert.Equal(stubTx.Hash(), event.TxHash)
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for receipt")
+	}
+}
+
+type stubBlockSubscriber struct {
+	feed event.Feed
+}
+
+func (s *stubBlockSubscriber) Subscribe(sink chan<- []*blockwatch.Event) event.Subscription {
+	return s.feed.Subscribe(sink)
+}
+
+// send sends a block added event for blk once there is a subscriber
+func (s *stubBlockSubscriber) send(blk int64) {
+	events := []*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: &blockwatch.MiniHeader{Number: big.NewInt(blk)}}}
+	for s.feed.Send(events) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTransactionManager_CheckTxLoop_BlockWatcher(t *testing.T) {
+	assert := assert.New(t)
+
+	stubTx := newStubLegacyTx(big.NewInt(100))
+	eth := &stubTransactionSenderReader{
+		err:   make(map[string]error),
+		mined: map[common.Hash]bool{stubTx.Hash(): true},
+	}
+	tm := &TransactionManager{
+		confirmations: 5,
+		cond:          sync.NewCond(&sync.Mutex{}),
+		eth:           eth,
+		txTimeout:     time.Minute,
+		quit:          make(chan struct{}),
+	}
+	blocks := &stubBlockSubscriber{}
+	tm.SetBlockWatcher(blocks)
+
+	go tm.Start()
+	defer tm.Stop()
+
+	sink := make(chan *transactionReceipt)
+	sub := tm.Subscribe(sink)
+	defer sub.Unsubscribe()
+
+	// The receipt is reported once the block watcher sees 5 blocks on top of the block of the tx
+	assert.Nil(tm.SendTransaction(context.Background(), stubTx))
+	blocks.send(5)
+	select {
+	case <-sink:
+		assert.Fail("receipt reported before it was confirmed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	blocks.send(6)
+	select {
+	case event := <-sink:
+		assert.Nil(event.err)
+		assert.Equal(stubTx.Hash(), event.TxHash)
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for receipt")
+	}
+	// The block number is not polled
+	assert.Zero(atomic.LoadUint64(&eth.blockNumber))
+}
+
+func TestTransactionManager_CheckTxLoop_MethodConfirmations(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	bondingManagerABI, err := abi.JSON(strings.NewReader(contracts.BondingManagerABI))
+	require.Nil(err)
+	newMethodTx := func(method string, nonce uint64, args ...interface{}) *types.Transaction {
+		data, err := bondingManagerABI.Pack(method, args...)
+		require.Nil(err)
+		addr := pm.RandAddress()
+		return types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(100), Gas: 1000000, Data: data, To: &addr})
+	}
+	rewardTx := newMethodTx("reward", 1)
+	unbondTx := newMethodTx("unbond", 2, big.NewInt(500))
+
+	eth := &stubTransactionSenderReader{
+		err:   make(map[string]error),
+		mined: map[common.Hash]bool{rewardTx.Hash(): true, unbondTx.Hash(): true},
+	}
+	tm := &TransactionManager{
+		confirmations: 5,
+		cond:          sync.NewCond(&sync.Mutex{}),
+		eth:           eth,
+		txTimeout:     time.Minute,
+		quit:          make(chan struct{}),
+	}
+	tm.SetMethodConfirmations(map[string]uint64{"reward": 1})
+	blocks := &stubBlockSubscriber{}
+	tm.SetBlockWatcher(blocks)
+
+	go tm.Start()
+	defer tm.Stop()
+
+	sink := make(chan *transactionReceipt)
+	sub := tm.Subscribe(sink)
+	defer sub.Unsubscribe()
+
+	// The receipt of a method with its own number of confirmations is reported once that many blocks are mined on top
+	// of the block of the tx
+	assert.Nil(tm.SendTransaction(context.Background(), rewardTx))
+	blocks.send(2)
+	select {
+	case event := <-sink:
+		assert.Nil(event.err)
+		assert.Equal(rewardTx.Hash(), event.TxHash)
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for receipt")
+	}
+
+	// The txs of the other methods use the default number of confirmations
+	assert.Nil(tm.SendTransaction(context.Background(), unbondTx))
+	blocks.send(2)
+	select {
+	case <-sink:
+		assert.Fail("receipt reported before it was confirmed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	blocks.send(6)
+	select {
+	case event := <-sink:
+		assert.Nil(event.err)
+		assert.Equal(unbondTx.Hash(), event.TxHash)
+	case <-time.After(5 * time.Second):
+		assert.Fail("timed out waiting for receipt")
+	}
+}
+
 func TestApplyPriceBump(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,68 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxCost is the estimated cost of one or more transactions, i.e. of bonding which approves the token transfer before
+// the bond if needed
+type TxCost struct {
+	// Gas is the estimated gas used
+	Gas uint64
+	// GasPrice is the expected price per gas, i.e. the base fee of the last block plus the priority fee
+	GasPrice *big.Int
+	// Value is the ETH sent with the transactions
+	Value *big.Int
+	// Cost is the expected cost of Gas at GasPrice plus Value
+	Cost *big.Int
+	// MaxCost is the cost of Gas at the max fee per gas plus Value
+	MaxCost *big.Int
+}
+
+func (c *client) EstimateTxCost(ctx context.Context, send func(client LivepeerEthClient) (*types.Transaction, error)) (*TxCost, error) {
+	head, err := c.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := *c
+	cp.estimates = &[]*types.Transaction{}
+	if _, err := send(&cp); err != nil {
+		return nil, err
+	}
+	if len(*cp.estimates) == 0 {
+		return nil, fmt.Errorf("no transaction to estimate")
+	}
+
+	return txCost(*cp.estimates, head.BaseFee), nil
+}
+
+func txCost(txs []*types.Transaction, baseFee *big.Int) *TxCost {
+	cost := &TxCost{
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+		Cost:     big.NewInt(0),
+		MaxCost:  big.NewInt(0),
+	}
+	for _, tx := range txs {
+		gasPrice := tx.GasPrice()
+		if baseFee != nil && tx.Type() == types.DynamicFeeTxType {
+			gasPrice = new(big.Int).Add(baseFee, tx.GasTipCap())
+			if gasPrice.Cmp(tx.GasFeeCap()) > 0 {
+				gasPrice = tx.GasFeeCap()
+			}
+		}
+		gas := new(big.Int).SetUint64(tx.Gas())
+
+		cost.Gas += tx.Gas()
+		cost.GasPrice = gasPrice
+		cost.Value.Add(cost.Value, tx.Value())
+		cost.Cost.Add(cost.Cost, new(big.Int).Add(new(big.Int).Mul(gas, gasPrice), tx.Value()))
+		cost.MaxCost.Add(cost.MaxCost, tx.Cost())
+	}
+	return cost
+}
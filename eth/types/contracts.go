@@ -65,6 +65,18 @@ func ParseDelegatorStatus(s uint8) (string, error) {
 	}
 }
 
+// DelegatorInfo is the position of a delegator with the earnings that it did not claim yet. PendingStake and
+// PendingFees of the Delegator are computed off-chain with the claim algorithm of the BondingManager
+type DelegatorInfo struct {
+	*Delegator
+	// UnclaimedRewards and UnclaimedFees are the rewards and fees earned since LastClaimRound
+	UnclaimedRewards *big.Int
+	UnclaimedFees    *big.Int
+	// UnbondingLocks are the pending unbonding locks and UnbondingAmount their total amount
+	UnbondingLocks  []*UnbondingLock
+	UnbondingAmount *big.Int
+}
+
 type UnbondingLock struct {
 	ID               *big.Int
 	DelegatorAddress common.Address
@@ -125,6 +137,20 @@ func (v VoteChoice) IsValid() bool {
 	return v == Yes || v == No
 }
 
+// Poll is a governance poll created by the PollCreator
+type Poll struct {
+	Address common.Address
+	// Proposal is the IPFS hash of the text of the proposal
+	Proposal string
+	EndBlock *big.Int
+	Quorum   *big.Int
+	Quota    *big.Int
+	// Active is set until the end block of the poll, while votes are counted
+	Active bool
+	// Vote is the latest choice of the account of the node, nil if it did not vote
+	Vote *VoteChoice
+}
+
 type TranscoderPoolHints struct {
 	PosNext common.Address
 	PosPrev common.Address
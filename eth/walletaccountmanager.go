@@ -0,0 +1,249 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/lperrors"
+)
+
+// maxWalletDerivations is the number of accounts derived from each base derivation path when searching a wallet for
+// the account
+const maxWalletDerivations = 20
+
+// walletBaseDerivationPaths are the derivation paths that the account is searched for in hardware wallets
+var walletBaseDerivationPaths = []accounts.DerivationPath{accounts.DefaultBaseDerivationPath, accounts.LegacyLedgerBaseDerivationPath}
+
+var ErrWalletNotFound = lperrors.User(errors.New("no wallet with the ETH account found"))
+
+type walletAccountManager struct {
+	account  accounts.Account
+	chainID  *big.Int
+	backends []accounts.Backend
+	// wallet is the opened wallet that holds the account, nil if the account is locked
+	wallet accounts.Wallet
+}
+
+// NewHardwareWalletAccountManager creates an AccountManager for an account held by a Ledger or Trezor hardware wallet
+// connected over USB. Signing requests have to be confirmed on the device
+func NewHardwareWalletAccountManager(accountAddr ethcommon.Address, chainID *big.Int) (AccountManager, error) {
+	var backends []accounts.Backend
+	hubs := []struct {
+		name   string
+		newHub func() (*usbwallet.Hub, error)
+	}{
+		{"Ledger", usbwallet.NewLedgerHub},
+		{"Trezor", usbwallet.NewTrezorHubWithHID},
+		{"Trezor WebUSB", usbwallet.NewTrezorHubWithWebUSB},
+	}
+	for _, h := range hubs {
+		hub, err := h.newHub()
+		if err != nil {
+			glog.Warningf("Error starting %v hub err=%q", h.name, err)
+			continue
+		}
+		backends = append(backends, hub)
+	}
+
+	return newWalletAccountManager(accountAddr, chainID, backends...)
+}
+
+// newWalletAccountManager creates an AccountManager for an account held by one of the wallets of backends
+func newWalletAccountManager(accountAddr ethcommon.Address, chainID *big.Int, backends ...accounts.Backend) (AccountManager, error) {
+	if (accountAddr == ethcommon.Address{}) {
+		return nil, errors.New("an ETH account address is required for a hardware wallet")
+	}
+	if len(backends) == 0 {
+		return nil, errors.New("no wallet backends available")
+	}
+
+	glog.Infof("Using Ethereum account: %v", accountAddr.Hex())
+
+	return &walletAccountManager{
+		account:  accounts.Account{Address: accountAddr},
+		chainID:  chainID,
+		backends: backends,
+	}, nil
+}
+
+// Unlock opens the wallet that holds the account. If the wallet needs a PIN or a passphrase that pass does not
+// provide, it is prompted for
+func (am *walletAccountManager) Unlock(pass string) error {
+	// We don't care if GetPass() returns an error.
+	// The string it returns will always be valid.
+	passphrase, _ := common.GetPass(pass)
+
+	for _, backend := range am.backends {
+		for _, wallet := range backend.Wallets() {
+			if err := openWallet(wallet, passphrase); err != nil {
+				glog.Warningf("Error opening wallet url=%v err=%q", wallet.URL(), err)
+				continue
+			}
+
+			acct, err := findWalletAccount(wallet, am.account.Address)
+			if err != nil {
+				wallet.Close()
+				continue
+			}
+
+			am.account = acct
+			am.wallet = wallet
+
+			glog.Infof("Unlocked ETH account: %v in wallet %v", am.account.Address.Hex(), wallet.URL())
+
+			return nil
+		}
+	}
+
+	return ErrWalletNotFound
+}
+
+// Lock closes the wallet that holds the account
+func (am *walletAccountManager) Lock() error {
+	if am.wallet == nil {
+		return nil
+	}
+
+	if err := am.wallet.Close(); err != nil {
+		return err
+	}
+
+	am.wallet = nil
+
+	return nil
+}
+
+// CreateTransactOpts creates transact opts that sign with the wallet - account must be unlocked
+func (am *walletAccountManager) CreateTransactOpts(gasLimit uint64) (*bind.TransactOpts, error) {
+	if am.wallet == nil {
+		return nil, ErrLocked
+	}
+
+	return &bind.TransactOpts{
+		From: am.account.Address,
+		Signer: func(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != am.account.Address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return am.SignTx(tx)
+		},
+		Context:  context.Background(),
+		GasLimit: gasLimit,
+	}, nil
+}
+
+// Sign a transaction. Account must be unlocked
+func (am *walletAccountManager) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	if am.wallet == nil {
+		return nil, ErrLocked
+	}
+
+	return am.wallet.SignTx(am.account, tx, am.chainID)
+}
+
+// Sign byte array message. Account must be unlocked. Hardware wallets do not support signing messages
+func (am *walletAccountManager) Sign(msg []byte) ([]byte, error) {
+	if am.wallet == nil {
+		return nil, ErrLocked
+	}
+
+	sig, err := am.wallet.SignText(am.account, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRecoverableSig(sig), nil
+}
+
+// SignTypedData signs the EIP-712 hash of typedData. Account must be unlocked
+func (am *walletAccountManager) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	if am.wallet == nil {
+		return nil, ErrLocked
+	}
+
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return nil, err
+	}
+	typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return nil, err
+	}
+	rawData := []byte(fmt.Sprintf("\x19\x01%s%s", string(domainSeparator), string(typedDataHash)))
+
+	sig, err := am.wallet.SignData(am.account, accounts.MimetypeTypedData, rawData)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRecoverableSig(sig), nil
+}
+
+func (am *walletAccountManager) Account() accounts.Account {
+	return am.account
+}
+
+// openWallet opens wallet and prompts for the PIN or the passphrase of Trezor wallets if they are needed
+func openWallet(wallet accounts.Wallet, passphrase string) error {
+	err := wallet.Open(passphrase)
+	for err == usbwallet.ErrTrezorPINNeeded || err == usbwallet.ErrTrezorPassphraseNeeded {
+		if err == usbwallet.ErrTrezorPINNeeded {
+			glog.Infof("Please enter the PIN of wallet %v using the layout shown on the device", wallet.URL())
+		} else {
+			glog.Infof("Please enter the passphrase of wallet %v", wallet.URL())
+		}
+
+		passphrase, err = getPassphrase(false)
+		if err != nil {
+			return err
+		}
+		err = wallet.Open(passphrase)
+	}
+
+	return err
+}
+
+// findWalletAccount returns the account with addr if the wallet holds it. Hardware wallets do not list their accounts,
+// so they are derived from the default derivation paths
+func findWalletAccount(wallet accounts.Wallet, addr ethcommon.Address) (accounts.Account, error) {
+	for _, acct := range wallet.Accounts() {
+		if acct.Address == addr {
+			return acct, nil
+		}
+	}
+
+	for _, base := range walletBaseDerivationPaths {
+		next := accounts.DefaultIterator(base)
+		for i := 0; i < maxWalletDerivations; i++ {
+			path := next()
+			acct, err := wallet.Derive(path, false)
+			if err != nil {
+				return accounts.Account{}, err
+			}
+			if acct.Address == addr {
+				// Pin the account so that the wallet can sign with it
+				return wallet.Derive(path, true)
+			}
+		}
+	}
+
+	return accounts.Account{}, ErrAccountNotFound
+}
+
+// toRecoverableSig converts the V param of a signature in the [R || S || V] format from 0 or 1 to 27 or 28
+func toRecoverableSig(sig []byte) []byte {
+	if len(sig) == 65 && (sig[64] == byte(0) || sig[64] == byte(1)) {
+		sig[64] += 27
+	}
+	return sig
+}
@@ -0,0 +1,147 @@
+package eth
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/livepeer/go-livepeer/crypto"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDerivingWallet is a wallet that does not list its accounts, like a hardware wallet
+type stubDerivingWallet struct {
+	accounts.Wallet
+	accts  map[string]ethcommon.Address
+	pinned accounts.DerivationPath
+}
+
+func (w *stubDerivingWallet) URL() accounts.URL {
+	return accounts.URL{Scheme: "stub", Path: "wallet"}
+}
+
+func (w *stubDerivingWallet) Accounts() []accounts.Account {
+	return nil
+}
+
+func (w *stubDerivingWallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	if pin {
+		w.pinned = path
+	}
+	return accounts.Account{Address: w.accts[path.String()]}, nil
+}
+
+func TestWalletAccountManager_MissingAddress(t *testing.T) {
+	dir, ks := tmpKeyStore(t, false)
+	defer os.RemoveAll(dir)
+
+	_, err := newWalletAccountManager(ethcommon.Address{}, big.NewInt(1), ks)
+	assert.EqualError(t, err, "an ETH account address is required for a hardware wallet")
+
+	_, err = newWalletAccountManager(pm.RandAddress(), big.NewInt(1))
+	assert.EqualError(t, err, "no wallet backends available")
+}
+
+func TestWalletAccountManager_Unlock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, ks := tmpKeyStore(t, false)
+	defer os.RemoveAll(dir)
+
+	a, err := ks.NewAccount("")
+	require.Nil(err)
+
+	// Account not held by any wallet
+	am, err := newWalletAccountManager(pm.RandAddress(), big.NewInt(777), ks)
+	require.Nil(err)
+	assert.Equal(ErrWalletNotFound, am.Unlock(""))
+
+	am, err = newWalletAccountManager(a.Address, big.NewInt(777), ks)
+	require.Nil(err)
+	assert.Equal(a.Address, am.Account().Address)
+
+	// Locked
+	_, err = am.CreateTransactOpts(100)
+	assert.Equal(ErrLocked, err)
+	_, err = am.SignTx(types.NewTx(&types.LegacyTx{}))
+	assert.Equal(ErrLocked, err)
+	_, err = am.Sign([]byte("foo"))
+	assert.Equal(ErrLocked, err)
+
+	require.Nil(am.Unlock(""))
+	assert.Equal(a, am.Account())
+
+	assert.Nil(am.Lock())
+	_, err = am.Sign([]byte("foo"))
+	assert.Equal(ErrLocked, err)
+}
+
+func TestWalletAccountManager_Sign(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, ks := tmpKeyStore(t, false)
+	defer os.RemoveAll(dir)
+
+	a, err := ks.NewAccount("")
+	require.Nil(err)
+	// The keystore wallet signs with unlocked keys
+	require.Nil(ks.Unlock(a, ""))
+
+	chainID := big.NewInt(777)
+	am, err := newWalletAccountManager(a.Address, chainID, ks)
+	require.Nil(err)
+	require.Nil(am.Unlock(""))
+
+	sig, err := am.Sign([]byte("foo"))
+	assert.Nil(err)
+	assert.True(crypto.VerifySig(a.Address, []byte("foo"), sig))
+
+	tx, err := am.SignTx(types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000}))
+	require.Nil(err)
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	assert.Nil(err)
+	assert.Equal(a.Address, sender)
+
+	opts, err := am.CreateTransactOpts(100)
+	require.Nil(err)
+	assert.Equal(a.Address, opts.From)
+	assert.Equal(uint64(100), opts.GasLimit)
+	_, err = opts.Signer(pm.RandAddress(), tx)
+	assert.Equal(bind.ErrNotAuthorized, err)
+	tx, err = opts.Signer(a.Address, types.NewTx(&types.LegacyTx{Nonce: 2, GasPrice: big.NewInt(1), Gas: 21000}))
+	require.Nil(err)
+	sender, err = types.Sender(types.LatestSignerForChainID(chainID), tx)
+	assert.Nil(err)
+	assert.Equal(a.Address, sender)
+}
+
+func TestFindWalletAccount(t *testing.T) {
+	assert := assert.New(t)
+
+	addr := pm.RandAddress()
+	next := accounts.DefaultIterator(accounts.LegacyLedgerBaseDerivationPath)
+	next()
+	next()
+	path := next()
+	wallet := &stubDerivingWallet{accts: map[string]ethcommon.Address{path.String(): addr}}
+
+	// The account is derived and pinned
+	acct, err := findWalletAccount(wallet, addr)
+	assert.Nil(err)
+	assert.Equal(addr, acct.Address)
+	assert.Equal(path, wallet.pinned)
+
+	// The account is not derived from the first accounts of the wallet
+	wallet.pinned = nil
+	_, err = findWalletAccount(wallet, pm.RandAddress())
+	assert.Equal(ErrAccountNotFound, err)
+	assert.Nil(wallet.pinned)
+}
@@ -0,0 +1,147 @@
+package watchers
+
+import (
+	"fmt"
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
+	"github.com/livepeer/go-livepeer/eth/contracts"
+)
+
+// reloadedContracts are the contracts of the Controller that the client has bindings for
+var reloadedContracts = []string{
+	"LivepeerToken",
+	"ServiceRegistry",
+	"BondingManager",
+	"TicketBroker",
+	"RoundsManager",
+	"Minter",
+	"LivepeerTokenFaucet",
+}
+
+// ContractWatcher is a watcher of the events of contracts that are registered in the Controller
+type ContractWatcher interface {
+	// SetContractAddresses makes the watcher watch the contracts at addrs, keyed by contract name
+	SetContractAddresses(addrs map[string]ethcommon.Address)
+}
+
+// ControllerWatcher watches the Controller for SetContractInfo events and reloads the contracts of the client when
+// one of its contracts is registered at a new address, so that the node follows protocol upgrades without a restart.
+// The watchers added with AddContractWatchers are moved to the new addresses as well
+type ControllerWatcher struct {
+	dec     *EventDecoder
+	ids     map[ethcommon.Hash]string
+	watcher BlockWatcher
+	lpEth   eth.LivepeerEthClient
+	quit    chan struct{}
+
+	mu       sync.Mutex
+	watchers []ContractWatcher
+}
+
+// NewControllerWatcher creates a ControllerWatcher for the Controller at controllerAddr
+func NewControllerWatcher(controllerAddr ethcommon.Address, watcher BlockWatcher, lpEth eth.LivepeerEthClient) (*ControllerWatcher, error) {
+	dec, err := NewEventDecoder(controllerAddr, contracts.ControllerABI)
+	if err != nil {
+		return nil, fmt.Errorf("error creating decoder: %v", err)
+	}
+
+	ids := make(map[ethcommon.Hash]string)
+	for _, name := range reloadedContracts {
+		ids[crypto.Keccak256Hash([]byte(name))] = name
+	}
+
+	return &ControllerWatcher{
+		dec:     dec,
+		ids:     ids,
+		watcher: watcher,
+		lpEth:   lpEth,
+		quit:    make(chan struct{}),
+	}, nil
+}
+
+// AddContractWatchers makes cw move ws to the new contract addresses when the contracts are reloaded
+func (cw *ControllerWatcher) AddContractWatchers(ws ...ContractWatcher) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.watchers = append(cw.watchers, ws...)
+}
+
+// Watch starts the event watching loop
+func (cw *ControllerWatcher) Watch() {
+	events := make(chan []*blockwatch.Event, 10)
+	sub := cw.watcher.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-cw.quit:
+			return
+		case err := <-sub.Err():
+			glog.Error(err)
+		case events := <-events:
+			cw.handleBlockEvents(events)
+		}
+	}
+}
+
+// Stop watching for events
+func (cw *ControllerWatcher) Stop() {
+	close(cw.quit)
+}
+
+func (cw *ControllerWatcher) handleBlockEvents(events []*blockwatch.Event) {
+	for _, event := range events {
+		for _, log := range event.BlockHeader.Logs {
+			if event.Type == blockwatch.Removed {
+				log.Removed = true
+			}
+			if err := cw.handleLog(log); err != nil {
+				glog.Error(err)
+			}
+		}
+	}
+}
+
+func (cw *ControllerWatcher) handleLog(log types.Log) error {
+	eventName, err := cw.dec.FindEventName(log)
+	if err != nil {
+		// Noop if we cannot find the event name
+		return nil
+	}
+
+	if eventName != "SetContractInfo" {
+		return nil
+	}
+
+	var info contracts.ControllerSetContractInfo
+	if err := cw.dec.Decode("SetContractInfo", log, &info); err != nil {
+		return fmt.Errorf("unable to decode event: %v", err)
+	}
+
+	name, ok := cw.ids[ethcommon.Hash(info.Id)]
+	if !ok {
+		// The contract is not used by the client, i.e. the target of a proxy
+		return nil
+	}
+
+	// Contracts are also reloaded for removed logs so that the client returns to the address that is registered
+	// after the reorg
+	glog.Infof("Reloading contracts after update name=%v addr=%v removed=%v", name, info.ContractAddress.Hex(), log.Removed)
+	if err := cw.lpEth.ReloadContracts(); err != nil {
+		return fmt.Errorf("error reloading contracts: %v", err)
+	}
+
+	addrs := cw.lpEth.ContractAddresses()
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	for _, w := range cw.watchers {
+		w.SetContractAddresses(addrs)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package watchers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var stubControllerAddr = ethcommon.HexToAddress("0xf96d54e490317c557a967abfa5d6e33006be69b3")
+
+func newStubSetContractInfoLog(name string, addr ethcommon.Address) types.Log {
+	log := newStubBaseLog()
+	log.Address = stubControllerAddr
+	log.Topics = []ethcommon.Hash{crypto.Keccak256Hash([]byte("SetContractInfo(bytes32,address,bytes20)"))}
+	log.Data = append(log.Data, crypto.Keccak256([]byte(name))...)
+	log.Data = append(log.Data, ethcommon.LeftPadBytes(addr.Bytes(), 32)...)
+	log.Data = append(log.Data, ethcommon.RightPadBytes([]byte("0123456789abcdefghij"), 32)...)
+	return log
+}
+
+func TestControllerWatcher_WatchAndStop(t *testing.T) {
+	assert := assert.New(t)
+	watcher := &stubBlockWatcher{}
+	cw, err := NewControllerWatcher(stubControllerAddr, watcher, &eth.StubClient{})
+	require.Nil(t, err)
+
+	go cw.Watch()
+	time.Sleep(2 * time.Millisecond)
+
+	cw.Stop()
+	time.Sleep(2 * time.Millisecond)
+	assert.True(watcher.sub.unsubscribed)
+}
+
+func TestControllerWatcher_HandleLog(t *testing.T) {
+	assert := assert.New(t)
+	lpEth := &eth.StubClient{Errors: make(map[string]error)}
+	cw, err := NewControllerWatcher(stubControllerAddr, &stubBlockWatcher{}, lpEth)
+	require.Nil(t, err)
+
+	// Contracts are reloaded when a contract of the client is registered
+	assert.Nil(cw.handleLog(newStubSetContractInfoLog("BondingManager", stubBondingManagerAddr)))
+	assert.Equal(1, lpEth.Reloads)
+
+	log := newStubSetContractInfoLog("TicketBroker", stubTicketBrokerAddr)
+	log.Removed = true
+	assert.Nil(cw.handleLog(log))
+	assert.Equal(2, lpEth.Reloads)
+
+	// Contracts that the client does not use are ignored
+	assert.Nil(cw.handleLog(newStubSetContractInfoLog("BondingManagerTarget", stubBondingManagerAddr)))
+	assert.Equal(2, lpEth.Reloads)
+
+	// Logs of other contracts are ignored
+	log = newStubSetContractInfoLog("BondingManager", stubBondingManagerAddr)
+	log.Address = stubRoundsManagerAddr
+	assert.Nil(cw.handleLog(log))
+	assert.Nil(cw.handleLog(newStubNewRoundLog()))
+	assert.Equal(2, lpEth.Reloads)
+
+	lpEth.Errors["ReloadContracts"] = errors.New("rpc error")
+	err = cw.handleLog(newStubSetContractInfoLog("RoundsManager", stubRoundsManagerAddr))
+	assert.EqualError(err, "error reloading contracts: rpc error")
+}
+
+func TestControllerWatcher_MovesContractWatchers(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	lpEth := &eth.StubClient{}
+	cw, err := NewControllerWatcher(stubControllerAddr, &stubBlockWatcher{}, lpEth)
+	require.Nil(err)
+
+	addrs := map[string]ethcommon.Address{
+		"BondingManager": stubBondingManagerAddr,
+		"RoundsManager":  stubRoundsManagerAddr,
+		"TicketBroker":   stubTicketBrokerAddr,
+		"Minter":         ethcommon.HexToAddress("0x1111"),
+	}
+	uw, err := NewUnbondingWatcher(ethcommon.Address{}, addrs["BondingManager"], &stubBlockWatcher{}, nil)
+	require.Nil(err)
+	pw, err := NewParamsWatcher(addrs, &stubBlockWatcher{}, lpEth)
+	require.Nil(err)
+	cw.AddContractWatchers(uw, pw)
+
+	upgraded := ethcommon.HexToAddress("0x2222")
+	lpEth.ContractAddrs = map[string]ethcommon.Address{
+		"BondingManager": upgraded,
+		"RoundsManager":  addrs["RoundsManager"],
+		"TicketBroker":   addrs["TicketBroker"],
+		"Minter":         addrs["Minter"],
+	}
+	require.Nil(cw.handleLog(newStubSetContractInfoLog("BondingManager", upgraded)))
+
+	// The events of the upgraded contract are decoded instead of the events of the previous contract
+	assert.Equal(upgraded, uw.dec.Address())
+	assert.Equal(upgraded, pw.decs["BondingManager"].Address())
+	assert.Equal(addrs["RoundsManager"], pw.decs["RoundsManager"].Address())
+
+	log := newStubUnbondLog()
+	_, err = uw.dec.FindEventName(log)
+	assert.EqualError(err, "log not from known contract")
+	log.Address = upgraded
+	name, err := uw.dec.FindEventName(log)
+	assert.Nil(err)
+	assert.Equal("Unbond", name)
+}
+
+func TestControllerWatcher_HandleBlockEvents(t *testing.T) {
+	assert := assert.New(t)
+	watcher := &stubBlockWatcher{}
+	lpEth := &eth.StubClient{}
+	cw, err := NewControllerWatcher(stubControllerAddr, watcher, lpEth)
+	require.Nil(t, err)
+
+	header := defaultMiniHeader()
+	header.Logs = append(header.Logs, newStubSetContractInfoLog("Minter", stubMinterAddr))
+
+	go cw.Watch()
+	defer cw.Stop()
+	time.Sleep(2 * time.Millisecond)
+
+	watcher.sink <- []*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: header}}
+	time.Sleep(2 * time.Millisecond)
+	assert.Equal(1, lpEth.Reloads)
+}
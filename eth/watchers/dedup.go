@@ -0,0 +1,71 @@
+package watchers
+
+import (
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// defaultDedupSize is the number of recently processed logs that are remembered by a logDeduper
+const defaultDedupSize = 10000
+
+// logID uniquely identifies a log emitted in a specific block. The same log that is re-mined in a different block
+// after a reorg has a different identity
+type logID struct {
+	blockHash ethcommon.Hash
+	txHash    ethcommon.Hash
+	index     uint
+}
+
+// logDeduper remembers the state (added or removed) of recently processed logs so that logs that are
+// delivered more than once (i.e. when the block watcher backfills missed blocks after resubscribing) are
+// only processed once. A log is processed again only if its state changed since it was last processed
+type logDeduper struct {
+	mu    sync.Mutex
+	size  int
+	added map[logID]bool
+	order []logID
+	next  int
+}
+
+func newLogDeduper(size int) *logDeduper {
+	return &logDeduper{
+		size:  size,
+		added: make(map[logID]bool),
+		order: make([]logID, 0, size),
+	}
+}
+
+// shouldProcess returns true if log has not been processed in its current state and records it as processed
+func (d *logDeduper) shouldProcess(log types.Log) bool {
+	if d == nil {
+		return true
+	}
+
+	id := logID{blockHash: log.BlockHash, txHash: log.TxHash, index: log.Index}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	added, ok := d.added[id]
+	if ok {
+		if added == !log.Removed {
+			return false
+		}
+		d.added[id] = !log.Removed
+		return true
+	}
+
+	// Evict the oldest log once the deduper is full
+	if len(d.order) < d.size {
+		d.order = append(d.order, id)
+	} else {
+		delete(d.added, d.order[d.next])
+		d.order[d.next] = id
+		d.next = (d.next + 1) % d.size
+	}
+	d.added[id] = !log.Removed
+
+	return true
+}
@@ -0,0 +1,107 @@
+package watchers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogDeduper(t *testing.T) {
+	assert := assert.New(t)
+
+	d := newLogDeduper(2)
+
+	log := newStubBaseLog()
+	assert.True(d.shouldProcess(log))
+	// Re-delivered log
+	assert.False(d.shouldProcess(log))
+
+	// Removed log is processed once
+	log.Removed = true
+	assert.True(d.shouldProcess(log))
+	assert.False(d.shouldProcess(log))
+
+	// Log added back after the reorg is reverted
+	log.Removed = false
+	assert.True(d.shouldProcess(log))
+
+	// The same log mined in a different block has a different identity
+	reorged := newStubBaseLog()
+	reorged.BlockHash = pm.RandHash()
+	assert.True(d.shouldProcess(reorged))
+
+	// The oldest log is evicted once the deduper is full
+	other := newStubBaseLog()
+	other.Index = 1
+	assert.True(d.shouldProcess(other))
+	assert.True(d.shouldProcess(log))
+	assert.False(d.shouldProcess(other))
+
+	// A nil deduper processes all logs
+	var nilDedup *logDeduper
+	assert.True(nilDedup.shouldProcess(log))
+	assert.True(nilDedup.shouldProcess(log))
+}
+
+func TestSenderWatcher_RedeliveredLog(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	startDeposit := big.NewInt(10)
+	lpEth := &eth.StubClient{
+		SenderInfo: &pm.SenderInfo{
+			Deposit: big.NewInt(10),
+			Reserve: &pm.ReserveInfo{
+				FundsRemaining:        big.NewInt(5),
+				ClaimedInCurrentRound: big.NewInt(0),
+			},
+		},
+	}
+	watcher := &stubBlockWatcher{}
+
+	sw, err := NewSenderWatcher(stubTicketBrokerAddr, watcher, lpEth, &stubTimeWatcher{})
+	require.Nil(err)
+
+	_, err = sw.GetSenderInfo(stubSender)
+	require.Nil(err)
+
+	header := defaultMiniHeader()
+	depositEvent := newStubDepositFundedLog()
+	header.Logs = append(header.Logs, depositEvent)
+	blockEvent := &blockwatch.Event{
+		Type:        blockwatch.Added,
+		BlockHeader: header,
+	}
+
+	go sw.Watch()
+	defer sw.Stop()
+	time.Sleep(20 * time.Millisecond)
+
+	// The deposit is only added once when the same block is delivered twice i.e. after resubscribing
+	expectedDeposit := new(big.Int).Add(startDeposit, new(big.Int).SetBytes(depositEvent.Data))
+	watcher.sink <- []*blockwatch.Event{blockEvent}
+	time.Sleep(20 * time.Millisecond)
+	watcher.sink <- []*blockwatch.Event{blockEvent}
+	time.Sleep(20 * time.Millisecond)
+
+	info, err := sw.GetSenderInfo(stubSender)
+	assert.Nil(err)
+	assert.Zero(expectedDeposit.Cmp(info.Deposit))
+
+	// A different log is processed
+	header.Logs[1].TxHash = ethcommon.BytesToHash(pm.RandBytes(32))
+	expectedDeposit.Add(expectedDeposit, new(big.Int).SetBytes(depositEvent.Data))
+	watcher.sink <- []*blockwatch.Event{blockEvent}
+	time.Sleep(20 * time.Millisecond)
+
+	info, err = sw.GetSenderInfo(stubSender)
+	assert.Nil(err)
+	assert.Zero(expectedDeposit.Cmp(info.Deposit))
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -13,7 +14,9 @@ import (
 
 // EventDecoder decodes logs into events for known contracts
 type EventDecoder struct {
+	mu               sync.RWMutex
 	addr             ethcommon.Address
+	abi              abi.ABI
 	contract         *bind.BoundContract
 	topicToEventName map[ethcommon.Hash]string
 }
@@ -32,6 +35,7 @@ func NewEventDecoder(addr ethcommon.Address, abiJSON string) (*EventDecoder, err
 
 	return &EventDecoder{
 		addr: addr,
+		abi:  abi,
 		// Create BoundContract without a backend because we just need to access
 		// log unpacking without contract interaction
 		contract:         bind.NewBoundContract(addr, abi, nil, nil, nil),
@@ -39,9 +43,26 @@ func NewEventDecoder(addr ethcommon.Address, abiJSON string) (*EventDecoder, err
 	}, nil
 }
 
+// Address returns the address of the contract that the decoder decodes the logs of
+func (e *EventDecoder) Address() ethcommon.Address {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.addr
+}
+
+// SetAddress makes the decoder decode the logs of the contract at addr instead, i.e. after the contract was upgraded
+func (e *EventDecoder) SetAddress(addr ethcommon.Address) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.addr = addr
+	e.contract = bind.NewBoundContract(addr, e.abi, nil, nil, nil)
+}
+
 // FindEventName returns the event name for a log. An error will be returned if the log is not emitted
 // from a known contract or if it does not map to a known event
 func (e *EventDecoder) FindEventName(log types.Log) (string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	if log.Address != e.addr {
 		return "", errors.New("log not from known contract")
 	}
@@ -56,6 +77,8 @@ func (e *EventDecoder) FindEventName(log types.Log) (string, error) {
 // Decode decodes a log into an event struct. An error will be returned if the log is not emitted
 // from a known contract or if it does not map to a known event
 func (e *EventDecoder) Decode(eventName string, log types.Log, decodedLog interface{}) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	if log.Address != e.addr {
 		return errors.New("log not from known contract")
 
@@ -1,6 +1,7 @@
 package watchers
 
 import (
+	"context"
 	"math"
 	"math/big"
 	"sync"
@@ -76,6 +77,11 @@ func (ow *OrchestratorWatcher) Stop() {
 	close(ow.quit)
 }
 
+// SetContractAddresses makes the watcher decode the events of the BondingManager in addrs
+func (ow *OrchestratorWatcher) SetContractAddresses(addrs map[string]ethcommon.Address) {
+	ow.dec.SetAddress(addrs["BondingManager"])
+}
+
 func (ow *OrchestratorWatcher) handleBlockEvents(events []*blockwatch.Event) {
 	for _, event := range events {
 		for _, log := range event.BlockHeader.Logs {
@@ -89,6 +95,11 @@ func (ow *OrchestratorWatcher) handleBlockEvents(events []*blockwatch.Event) {
 	}
 }
 
+// HandleLog processes a log outside of the block subscription i.e. when replaying historical events
+func (ow *OrchestratorWatcher) HandleLog(log types.Log) error {
+	return ow.handleLog(log)
+}
+
 func (ow *OrchestratorWatcher) handleLog(log types.Log) error {
 	eventName, err := ow.dec.FindEventName(log)
 	if err != nil {
@@ -116,7 +127,7 @@ func (ow *OrchestratorWatcher) handleTranscoderActivated(log types.Log) error {
 	}
 
 	if !log.Removed {
-		uri, err := ow.lpEth.GetServiceURI(transcoderActivated.Transcoder)
+		uri, err := ow.lpEth.GetServiceURI(context.Background(), transcoderActivated.Transcoder)
 		if err != nil {
 			return err
 		}
@@ -130,7 +141,7 @@ func (ow *OrchestratorWatcher) handleTranscoderActivated(log types.Log) error {
 			},
 		)
 	}
-	t, err := ow.lpEth.GetTranscoder(transcoderActivated.Transcoder)
+	t, err := ow.lpEth.GetTranscoder(context.Background(), transcoderActivated.Transcoder)
 	if err != nil {
 		return err
 	}
@@ -158,7 +169,7 @@ func (ow *OrchestratorWatcher) handleTranscoderDeactivated(log types.Log) error
 			},
 		)
 	}
-	t, err := ow.lpEth.GetTranscoder(transcoderDeactivated.Transcoder)
+	t, err := ow.lpEth.GetTranscoder(context.Background(), transcoderDeactivated.Transcoder)
 	if err != nil {
 		return err
 	}
@@ -175,7 +186,7 @@ func (ow *OrchestratorWatcher) handleRoundEvent(log types.Log) error {
 	ow.roundMu.Lock()
 	defer ow.roundMu.Unlock()
 
-	round, err := ow.lpEth.CurrentRound()
+	round, err := ow.lpEth.CurrentRound(context.Background())
 	if err != nil {
 		return err
 	}
@@ -195,7 +206,7 @@ func (ow *OrchestratorWatcher) handleRoundEvent(log types.Log) error {
 }
 
 func (ow *OrchestratorWatcher) cacheOrchestratorStake(addr ethcommon.Address, round *big.Int) error {
-	ep, err := ow.lpEth.GetTranscoderEarningsPoolForRound(addr, round)
+	ep, err := ow.lpEth.GetTranscoderEarningsPoolForRound(context.Background(), addr, round)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,119 @@
+package watchers
+
+import (
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
+	"github.com/livepeer/go-livepeer/eth/contracts"
+)
+
+// ParamsWatcher keeps the protocol parameters cached by the client up to date. It refreshes them when a ParameterUpdate
+// event is emitted by the RoundsManager, BondingManager, Minter or TicketBroker and when a new round is initialized,
+// since the inflation is updated every round
+type ParamsWatcher struct {
+	decs    map[string]*EventDecoder
+	watcher BlockWatcher
+	lpEth   eth.LivepeerEthClient
+	quit    chan struct{}
+}
+
+// NewParamsWatcher creates a ParamsWatcher for the contracts of addrMap
+func NewParamsWatcher(addrMap map[string]ethcommon.Address, watcher BlockWatcher, lpEth eth.LivepeerEthClient) (*ParamsWatcher, error) {
+	abis := map[string]string{
+		"RoundsManager":  contracts.RoundsManagerABI,
+		"BondingManager": contracts.BondingManagerABI,
+		"Minter":         contracts.MinterABI,
+		"TicketBroker":   contracts.TicketBrokerABI,
+	}
+
+	decs := make(map[string]*EventDecoder)
+	for name, abi := range abis {
+		dec, err := NewEventDecoder(addrMap[name], abi)
+		if err != nil {
+			return nil, fmt.Errorf("error creating decoder for %v: %v", name, err)
+		}
+		decs[name] = dec
+	}
+
+	return &ParamsWatcher{
+		decs:    decs,
+		watcher: watcher,
+		lpEth:   lpEth,
+		quit:    make(chan struct{}),
+	}, nil
+}
+
+// Watch starts the event watching loop
+func (pw *ParamsWatcher) Watch() {
+	events := make(chan []*blockwatch.Event, 10)
+	sub := pw.watcher.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-pw.quit:
+			return
+		case err := <-sub.Err():
+			glog.Error(err)
+		case events := <-events:
+			pw.handleBlockEvents(events)
+		}
+	}
+}
+
+// Stop watching for events
+func (pw *ParamsWatcher) Stop() {
+	close(pw.quit)
+}
+
+// SetContractAddresses makes the watcher decode the events of the contracts in addrs
+func (pw *ParamsWatcher) SetContractAddresses(addrs map[string]ethcommon.Address) {
+	for name, dec := range pw.decs {
+		dec.SetAddress(addrs[name])
+	}
+}
+
+func (pw *ParamsWatcher) handleBlockEvents(events []*blockwatch.Event) {
+	for _, event := range events {
+		for _, log := range event.BlockHeader.Logs {
+			if event.Type == blockwatch.Removed {
+				log.Removed = true
+			}
+			if err := pw.handleLog(log); err != nil {
+				glog.Error(err)
+			}
+		}
+	}
+}
+
+func (pw *ParamsWatcher) handleLog(log types.Log) error {
+	for _, dec := range pw.decs {
+		eventName, err := dec.FindEventName(log)
+		if err != nil {
+			continue
+		}
+
+		switch eventName {
+		case "ParameterUpdate":
+			var update struct{ Param string }
+			if err := dec.Decode("ParameterUpdate", log, &update); err != nil {
+				return fmt.Errorf("unable to decode event: %v", err)
+			}
+			glog.Infof("Refreshing protocol parameters after update param=%v removed=%v", update.Param, log.Removed)
+		case "NewRound":
+			glog.V(common.DEBUG).Infof("Refreshing protocol parameters for new round removed=%v", log.Removed)
+		default:
+			return nil
+		}
+
+		// The parameters are also refreshed for removed logs because the update is reverted by the reorg
+		pw.lpEth.ForceRefresh()
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package watchers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/eth/blockwatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var stubMinterAddr = ethcommon.HexToAddress("0x8573f2f5a3bd960eee3d998473e50c75cdbe6828")
+
+func newStubParameterUpdateLog(addr ethcommon.Address, param string) types.Log {
+	log := newStubBaseLog()
+	log.Address = addr
+	log.Topics = []ethcommon.Hash{crypto.Keccak256Hash([]byte("ParameterUpdate(string)"))}
+	log.Data = append(log.Data, ethcommon.LeftPadBytes(big.NewInt(32).Bytes(), 32)...)
+	log.Data = append(log.Data, ethcommon.LeftPadBytes(big.NewInt(int64(len(param))).Bytes(), 32)...)
+	log.Data = append(log.Data, ethcommon.RightPadBytes([]byte(param), 32)...)
+	return log
+}
+
+func newStubParamsWatcher(t *testing.T, watcher BlockWatcher, lpEth eth.LivepeerEthClient) *ParamsWatcher {
+	pw, err := NewParamsWatcher(map[string]ethcommon.Address{
+		"RoundsManager":  stubRoundsManagerAddr,
+		"BondingManager": stubBondingManagerAddr,
+		"Minter":         stubMinterAddr,
+		"TicketBroker":   stubTicketBrokerAddr,
+	}, watcher, lpEth)
+	require.Nil(t, err)
+	return pw
+}
+
+func TestParamsWatcher_WatchAndStop(t *testing.T) {
+	assert := assert.New(t)
+	watcher := &stubBlockWatcher{}
+	pw := newStubParamsWatcher(t, watcher, &eth.StubClient{})
+
+	go pw.Watch()
+	time.Sleep(2 * time.Millisecond)
+
+	pw.Stop()
+	time.Sleep(2 * time.Millisecond)
+	assert.True(watcher.sub.unsubscribed)
+}
+
+func TestParamsWatcher_HandleLog(t *testing.T) {
+	assert := assert.New(t)
+	lpEth := &eth.StubClient{}
+	pw := newStubParamsWatcher(t, &stubBlockWatcher{}, lpEth)
+
+	for _, addr := range []ethcommon.Address{stubRoundsManagerAddr, stubBondingManagerAddr, stubMinterAddr, stubTicketBrokerAddr} {
+		assert.Nil(pw.handleLog(newStubParameterUpdateLog(addr, "unbondingPeriod")))
+	}
+	assert.Equal(4, lpEth.Refreshes)
+
+	// The parameters are refreshed every round
+	assert.Nil(pw.handleLog(newStubNewRoundLog()))
+	assert.Equal(5, lpEth.Refreshes)
+
+	// Reverted updates refresh the parameters too
+	log := newStubParameterUpdateLog(stubMinterAddr, "targetBondingRate")
+	log.Removed = true
+	assert.Nil(pw.handleLog(log))
+	assert.Equal(6, lpEth.Refreshes)
+
+	// Other events and contracts are ignored
+	assert.Nil(pw.handleLog(newStubUnbondLog()))
+	assert.Nil(pw.handleLog(newStubParameterUpdateLog(stubServiceRegistryAddr, "foo")))
+	assert.Equal(6, lpEth.Refreshes)
+}
+
+func TestParamsWatcher_HandleBlockEvents(t *testing.T) {
+	assert := assert.New(t)
+	watcher := &stubBlockWatcher{}
+	lpEth := &eth.StubClient{}
+	pw := newStubParamsWatcher(t, watcher, lpEth)
+
+	header := defaultMiniHeader()
+	header.Logs = append(header.Logs, newStubParameterUpdateLog(stubRoundsManagerAddr, "roundLength"))
+
+	go pw.Watch()
+	defer pw.Stop()
+	time.Sleep(2 * time.Millisecond)
+
+	watcher.sink <- []*blockwatch.Event{{Type: blockwatch.Added, BlockHeader: header}}
+	time.Sleep(2 * time.Millisecond)
+	assert.Equal(1, lpEth.Refreshes)
+}
@@ -0,0 +1,100 @@
+package watchers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/glog"
+)
+
+// defaultReplayChunkSize is the number of blocks for which logs are requested at once when replaying events
+const defaultReplayChunkSize = 10000
+
+// LogHandler processes a single contract event log
+type LogHandler interface {
+	HandleLog(log types.Log) error
+}
+
+type logFilterer interface {
+	FilterLogs(q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// EventReplayer fetches the historical events of a set of contracts and feeds them to a set of handlers.
+// Logs are processed one at a time in (block number, log index) order so that replaying the same
+// block range always results in the same state
+type EventReplayer struct {
+	client    logFilterer
+	addrs     []ethcommon.Address
+	handlers  []LogHandler
+	ChunkSize uint64
+}
+
+// NewEventReplayer creates an EventReplayer for the events emitted by the contracts at addrs
+func NewEventReplayer(client logFilterer, addrs []ethcommon.Address, handlers ...LogHandler) *EventReplayer {
+	return &EventReplayer{
+		client:    client,
+		addrs:     addrs,
+		handlers:  handlers,
+		ChunkSize: defaultReplayChunkSize,
+	}
+}
+
+// Replay processes all events emitted between fromBlock and toBlock (inclusive) and returns the number of processed logs
+func (r *EventReplayer) Replay(ctx context.Context, fromBlock, toBlock *big.Int) (int, error) {
+	if fromBlock.Cmp(toBlock) > 0 {
+		return 0, fmt.Errorf("fromBlock %v is after toBlock %v", fromBlock, toBlock)
+	}
+
+	chunkSize := new(big.Int).SetUint64(r.ChunkSize)
+	processed := 0
+
+	for start := new(big.Int).Set(fromBlock); start.Cmp(toBlock) <= 0; start = new(big.Int).Add(start, chunkSize) {
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+
+		end := new(big.Int).Add(start, chunkSize)
+		end.Sub(end, big.NewInt(1))
+		if end.Cmp(toBlock) > 0 {
+			end.Set(toBlock)
+		}
+
+		logs, err := r.client.FilterLogs(ethereum.FilterQuery{
+			FromBlock: start,
+			ToBlock:   end,
+			Addresses: r.addrs,
+			Topics:    [][]ethcommon.Hash{FilterTopics()},
+		})
+		if err != nil {
+			return processed, fmt.Errorf("failed to fetch logs for blocks %v-%v: %v", start, end, err)
+		}
+
+		sort.SliceStable(logs, func(i, j int) bool {
+			if logs[i].BlockNumber != logs[j].BlockNumber {
+				return logs[i].BlockNumber < logs[j].BlockNumber
+			}
+			return logs[i].Index < logs[j].Index
+		})
+
+		for _, log := range logs {
+			if log.Removed {
+				continue
+			}
+			for _, h := range r.handlers {
+				if err := h.HandleLog(log); err != nil {
+					return processed, fmt.Errorf("failed to replay log block=%v tx=%v index=%v: %v", log.BlockNumber, log.TxHash.Hex(), log.Index, err)
+				}
+			}
+			processed++
+		}
+
+		glog.Infof("Replayed events fromBlock=%v toBlock=%v logs=%v", start, end, len(logs))
+	}
+
+	return processed, nil
+}
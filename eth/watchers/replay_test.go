@@ -0,0 +1,121 @@
+package watchers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubLogFilterer struct {
+	logs    []types.Log
+	queries []ethereum.FilterQuery
+	err     error
+}
+
+func (f *stubLogFilterer) FilterLogs(q ethereum.FilterQuery) ([]types.Log, error) {
+	f.queries = append(f.queries, q)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var logs []types.Log
+	// Return logs in reverse order to check that the replayer sorts them
+	for i := len(f.logs) - 1; i >= 0; i-- {
+		bn := new(big.Int).SetUint64(f.logs[i].BlockNumber)
+		if bn.Cmp(q.FromBlock) >= 0 && bn.Cmp(q.ToBlock) <= 0 {
+			logs = append(logs, f.logs[i])
+		}
+	}
+	return logs, nil
+}
+
+type recordingLogHandler struct {
+	logs []types.Log
+	err  error
+}
+
+func (h *recordingLogHandler) HandleLog(log types.Log) error {
+	h.logs = append(h.logs, log)
+	return h.err
+}
+
+func TestEventReplayer_Replay(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	newLog := func(block uint64, index uint) types.Log {
+		return types.Log{BlockNumber: block, Index: index}
+	}
+	removed := newLog(7, 0)
+	removed.Removed = true
+
+	filterer := &stubLogFilterer{
+		logs: []types.Log{newLog(1, 0), newLog(1, 1), newLog(5, 0), removed, newLog(12, 3), newLog(12, 4)},
+	}
+	h := &recordingLogHandler{}
+	addrs := []common.Address{stubBondingManagerAddr}
+
+	r := NewEventReplayer(filterer, addrs, h)
+	r.ChunkSize = 5
+
+	processed, err := r.Replay(context.Background(), big.NewInt(1), big.NewInt(12))
+	require.Nil(err)
+	assert.Equal(5, processed)
+
+	// Logs are handled in order and removed logs are skipped
+	assert.Equal([]types.Log{newLog(1, 0), newLog(1, 1), newLog(5, 0), newLog(12, 3), newLog(12, 4)}, h.logs)
+
+	// Blocks are requested in chunks
+	require.Len(filterer.queries, 3)
+	assert.Equal(big.NewInt(1), filterer.queries[0].FromBlock)
+	assert.Equal(big.NewInt(5), filterer.queries[0].ToBlock)
+	assert.Equal(big.NewInt(6), filterer.queries[1].FromBlock)
+	assert.Equal(big.NewInt(10), filterer.queries[1].ToBlock)
+	assert.Equal(big.NewInt(11), filterer.queries[2].FromBlock)
+	assert.Equal(big.NewInt(12), filterer.queries[2].ToBlock)
+	assert.Equal(addrs, filterer.queries[0].Addresses)
+	assert.Equal([][]common.Hash{FilterTopics()}, filterer.queries[0].Topics)
+
+	// Invalid range
+	_, err = r.Replay(context.Background(), big.NewInt(2), big.NewInt(1))
+	assert.EqualError(err, "fromBlock 2 is after toBlock 1")
+
+	// Handler error stops the replay
+	h = &recordingLogHandler{err: errors.New("handler error")}
+	r = NewEventReplayer(filterer, addrs, h)
+	processed, err = r.Replay(context.Background(), big.NewInt(1), big.NewInt(12))
+	assert.Contains(err.Error(), "handler error")
+	assert.Equal(0, processed)
+	assert.Len(h.logs, 1)
+
+	// Client error
+	filterer.err = errors.New("client error")
+	_, err = r.Replay(context.Background(), big.NewInt(1), big.NewInt(12))
+	assert.EqualError(err, "failed to fetch logs for blocks 1-12: client error")
+}
+
+func TestEventReplayer_UnbondingWatcher(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	store := newStubUnbondingLockStore()
+	watcherAddr := common.HexToAddress("0xF75b78571F6563e8Acf1899F682Fb10A9248CCE8")
+	watcher, err := NewUnbondingWatcher(watcherAddr, stubBondingManagerAddr, &stubBlockWatcher{}, store)
+	require.Nil(err)
+
+	filterer := &stubLogFilterer{logs: []types.Log{newStubUnbondLog()}}
+	processed, err := NewEventReplayer(filterer, []common.Address{stubBondingManagerAddr}, watcher).Replay(context.Background(), big.NewInt(0), big.NewInt(100))
+	require.Nil(err)
+	assert.Equal(1, processed)
+
+	lock := store.Get(1)
+	require.NotNil(lock)
+	assert.Equal(watcherAddr, lock.Delegator)
+}
@@ -1,6 +1,7 @@
 package watchers
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"sync"
@@ -26,6 +27,7 @@ type SenderWatcher struct {
 	tw             timeWatcher
 	lpEth          eth.LivepeerEthClient
 	dec            *EventDecoder
+	dedup          *logDeduper
 
 	// subscriptions
 	reserveChangeFeed  event.Feed
@@ -47,6 +49,7 @@ func NewSenderWatcher(ticketBrokerAddr ethcommon.Address, watcher BlockWatcher,
 		senders:        make(map[ethcommon.Address]*pm.SenderInfo),
 		claimedReserve: make(map[ethcommon.Address]*big.Int),
 		dec:            dec,
+		dedup:          newLogDeduper(defaultDedupSize),
 	}, nil
 }
 
@@ -57,7 +60,7 @@ func (sw *SenderWatcher) GetSenderInfo(addr ethcommon.Address) (*pm.SenderInfo,
 	cache := sw.senders[addr]
 	sw.mu.RUnlock()
 	if cache == nil {
-		info, err := sw.lpEth.GetSenderInfo(addr)
+		info, err := sw.lpEth.GetSenderInfo(context.Background(), addr)
 		if err != nil {
 			return nil, fmt.Errorf("GetSenderInfo RPC call to remote node failed: %v", err)
 		}
@@ -86,7 +89,7 @@ func (sw *SenderWatcher) ClaimedReserve(reserveHolder ethcommon.Address, claiman
 	if claimed != nil {
 		return claimed, nil
 	}
-	claimed, err := sw.lpEth.ClaimedReserve(reserveHolder, claimant)
+	claimed, err := sw.lpEth.ClaimedReserve(context.Background(), reserveHolder, claimant)
 	if err != nil {
 		return nil, fmt.Errorf("ClaimedReserve RPC call to remote node failed: %v", err)
 	}
@@ -130,6 +133,11 @@ func (sw *SenderWatcher) Stop() {
 	sw.reserveChangeScope.Close()
 }
 
+// SetContractAddresses makes the watcher decode the events of the TicketBroker in addrs
+func (sw *SenderWatcher) SetContractAddresses(addrs map[string]ethcommon.Address) {
+	sw.dec.SetAddress(addrs["TicketBroker"])
+}
+
 // Clear removes a key-value pair from the map
 func (sw *SenderWatcher) Clear(addr ethcommon.Address) {
 	sw.mu.Lock()
@@ -153,6 +161,9 @@ func (sw *SenderWatcher) handleBlockEvents(events []*blockwatch.Event) {
 			if event.Type == blockwatch.Removed {
 				log.Removed = true
 			}
+			if !sw.dedup.shouldProcess(log) {
+				continue
+			}
 			if err := sw.handleLog(log); err != nil {
 				glog.Error(err)
 			}
@@ -259,7 +270,7 @@ func (sw *SenderWatcher) handleLog(log types.Log) error {
 	}
 
 	if _, ok := sw.senders[sender]; ok && log.Removed {
-		info, err := sw.lpEth.GetSenderInfo(sender)
+		info, err := sw.lpEth.GetSenderInfo(context.Background(), sender)
 		if err != nil {
 			return fmt.Errorf("GetSenderInfo RPC call to remote node failed: %v", err)
 		}
@@ -283,7 +294,7 @@ func (sw *SenderWatcher) handleRoundEvent(log types.Log) error {
 
 	for sender, info := range sw.senders {
 		if log.Removed {
-			i, err := sw.lpEth.GetSenderInfo(sender)
+			i, err := sw.lpEth.GetSenderInfo(context.Background(), sender)
 			if err != nil {
 				return fmt.Errorf("GetSenderInfo RPC call to remote node failed: %v", err)
 			}
@@ -295,7 +306,7 @@ func (sw *SenderWatcher) handleRoundEvent(log types.Log) error {
 
 	for sender := range sw.claimedReserve {
 		if log.Removed {
-			c, err := sw.lpEth.ClaimedReserve(sender, sw.lpEth.Account().Address)
+			c, err := sw.lpEth.ClaimedReserve(context.Background(), sender, sw.lpEth.Account().Address)
 			if err != nil {
 				return fmt.Errorf("ClaimedReserve RPC call to remote node failed: %v", err)
 			}
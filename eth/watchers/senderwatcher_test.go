@@ -453,8 +453,10 @@ func TestWinningTicketTransferEvent(t *testing.T) {
 	assert.Zero(startReserve.Cmp(info.Reserve.FundsRemaining))
 
 	// Test facevalue > deposit
+	// Use a different log because the same log is only processed once
 	senderInfo.Deposit = big.NewInt(100000000000)
 	info, err = sw.GetSenderInfo(stubSender)
+	header.Logs[1].Index++
 	watcher.sink <- []*blockwatch.Event{blockEvent}
 	time.Sleep(2 * time.Millisecond)
 	info, err = sw.GetSenderInfo(stubSender)
@@ -1,6 +1,8 @@
 package watchers
 
 import (
+	"context"
+
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/golang/glog"
@@ -56,6 +58,11 @@ func (srw *ServiceRegistryWatcher) Stop() {
 	close(srw.quit)
 }
 
+// SetContractAddresses makes the watcher decode the events of the ServiceRegistry in addrs
+func (srw *ServiceRegistryWatcher) SetContractAddresses(addrs map[string]ethcommon.Address) {
+	srw.dec.SetAddress(addrs["ServiceRegistry"])
+}
+
 func (srw *ServiceRegistryWatcher) handleBlockEvents(events []*blockwatch.Event) {
 	for _, event := range events {
 		for _, log := range event.BlockHeader.Logs {
@@ -69,6 +76,11 @@ func (srw *ServiceRegistryWatcher) handleBlockEvents(events []*blockwatch.Event)
 	}
 }
 
+// HandleLog processes a log outside of the block subscription i.e. when replaying historical events
+func (srw *ServiceRegistryWatcher) HandleLog(log types.Log) error {
+	return srw.handleLog(log)
+}
+
 func (srw *ServiceRegistryWatcher) handleLog(log types.Log) error {
 	eventName, err := srw.dec.FindEventName(log)
 	if err != nil {
@@ -98,7 +110,7 @@ func (srw *ServiceRegistryWatcher) handleServiceURIUpdate(log types.Log) error {
 			},
 		)
 	}
-	uri, err := srw.lpEth.GetServiceURI(serviceURIUpdate.Addr)
+	uri, err := srw.lpEth.GetServiceURI(context.Background(), serviceURIUpdate.Addr)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package watchers
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"sync"
@@ -62,15 +63,15 @@ func NewTimeWatcher(roundsManagerAddr ethcommon.Address, watcher BlockWatcher, l
 		dec:     dec,
 	}
 
-	lr, err := tw.lpEth.LastInitializedRound()
+	lr, err := tw.lpEth.LastInitializedRound(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("error fetching initial lastInitializedRound value err=%q", err)
 	}
-	bh, err := tw.lpEth.BlockHashForRound(lr)
+	bh, err := tw.lpEth.BlockHashForRound(context.Background(), lr)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching initial lastInitializedL1BlockHash value err=%q", err)
 	}
-	num, err := tw.lpEth.CurrentRoundStartBlock()
+	num, err := tw.lpEth.CurrentRoundStartBlock(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("error fetching current round start block err=%q", err)
 	}
@@ -86,7 +87,7 @@ func NewTimeWatcher(roundsManagerAddr ethcommon.Address, watcher BlockWatcher, l
 	}
 	tw.setLastSeenL1Block(l1BlockNum)
 
-	size, err := tw.lpEth.GetTranscoderPoolSize()
+	size, err := tw.lpEth.GetTranscoderPoolSize(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("error fetching initial transcoderPoolSize err=%q", err)
 	}
@@ -190,6 +191,11 @@ func (tw *TimeWatcher) Stop() {
 	tw.roundSubScope.Close()
 }
 
+// SetContractAddresses makes the watcher decode the events of the RoundsManager in addrs
+func (tw *TimeWatcher) SetContractAddresses(addrs map[string]ethcommon.Address) {
+	tw.dec.SetAddress(addrs["RoundsManager"])
+}
+
 func (tw *TimeWatcher) handleBlockEvents(events []*blockwatch.Event) {
 	for _, event := range events {
 		tw.handleL1BlockNum(event)
@@ -226,8 +232,9 @@ func (tw *TimeWatcher) handleLog(log types.Log) error {
 	tw.feedMu.Lock()
 	defer tw.feedMu.Unlock()
 
+	// The RoundsManager also emits ParameterUpdate events which are handled by the ParamsWatcher
 	if eventName != "NewRound" {
-		return fmt.Errorf("eventName is not NewRound")
+		return nil
 	}
 
 	var nr contracts.RoundsManagerNewRound
@@ -235,16 +242,16 @@ func (tw *TimeWatcher) handleLog(log types.Log) error {
 		return fmt.Errorf("unable to decode event: %v", err)
 	}
 
-	roundStartL1Block, err := tw.lpEth.CurrentRoundStartBlock()
+	roundStartL1Block, err := tw.lpEth.CurrentRoundStartBlock(context.Background())
 	if err != nil {
 		return err
 	}
 	if log.Removed {
-		lr, err := tw.lpEth.LastInitializedRound()
+		lr, err := tw.lpEth.LastInitializedRound(context.Background())
 		if err != nil {
 			return err
 		}
-		bh, err := tw.lpEth.BlockHashForRound(lr)
+		bh, err := tw.lpEth.BlockHashForRound(context.Background(), lr)
 		if err != nil {
 			return err
 		}
@@ -254,7 +261,7 @@ func (tw *TimeWatcher) handleLog(log types.Log) error {
 	}
 
 	// Get the active transcoder pool size when we receive a NewRound event
-	size, err := tw.lpEth.GetTranscoderPoolSize()
+	size, err := tw.lpEth.GetTranscoderPoolSize(context.Background())
 	if err != nil {
 		return err
 	}
@@ -19,6 +19,8 @@ var eventSignatures = []string{
 	"TranscoderActivated(address,uint256)",
 	"TranscoderDeactivated(address,uint256)",
 	"ServiceURIUpdate(address,string)",
+	"ParameterUpdate(string)",
+	"SetContractInfo(bytes32,address,bytes20)",
 }
 
 // FilterTopics returns a list of topics to be used when filtering logs
@@ -24,6 +24,7 @@ type UnbondingWatcher struct {
 	bw    BlockWatcher
 	store unbondingLockStore
 	dec   *EventDecoder
+	dedup *logDeduper
 
 	quit chan struct{}
 
@@ -42,6 +43,7 @@ func NewUnbondingWatcher(addr ethcommon.Address, bondingManagerAddr ethcommon.Ad
 		bw:    bw,
 		store: store,
 		dec:   dec,
+		dedup: newLogDeduper(defaultDedupSize),
 		quit:  make(chan struct{}),
 	}, nil
 }
@@ -69,12 +71,20 @@ func (w *UnbondingWatcher) Stop() {
 	close(w.quit)
 }
 
+// SetContractAddresses makes the watcher decode the events of the BondingManager in addrs
+func (w *UnbondingWatcher) SetContractAddresses(addrs map[string]ethcommon.Address) {
+	w.dec.SetAddress(addrs["BondingManager"])
+}
+
 func (w *UnbondingWatcher) handleBlockEvents(events []*blockwatch.Event) {
 	for _, event := range events {
 		for _, log := range event.BlockHeader.Logs {
 			if event.Type == blockwatch.Removed {
 				log.Removed = true
 			}
+			if !w.dedup.shouldProcess(log) {
+				continue
+			}
 			if err := w.handleLog(log); err != nil {
 				glog.Error(err)
 			}
@@ -82,6 +92,11 @@ func (w *UnbondingWatcher) handleBlockEvents(events []*blockwatch.Event) {
 	}
 }
 
+// HandleLog processes a log outside of the block subscription i.e. when replaying historical events
+func (w *UnbondingWatcher) HandleLog(log types.Log) error {
+	return w.handleLog(log)
+}
+
 func (w *UnbondingWatcher) handleLog(log types.Log) error {
 	eventName, err := w.dec.FindEventName(log)
 	if err != nil {
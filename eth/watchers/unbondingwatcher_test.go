@@ -29,6 +29,8 @@ func TestUnbondingWatcherLoop(t *testing.T) {
 	createBlockEvent := func(eventType blockwatch.EventType, blkNum uint64, logs []types.Log) *blockwatch.Event {
 		for i := 0; i < len(logs); i++ {
 			logs[i].BlockNumber = blkNum
+			logs[i].BlockHash = common.BigToHash(new(big.Int).SetUint64(blkNum))
+			logs[i].Index = uint(i)
 		}
 
 		return &blockwatch.Event{
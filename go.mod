@@ -16,7 +16,9 @@ require (
 	github.com/golang/glog v0.0.0-20210429001901-424d2337a529
 	github.com/golang/mock v1.5.0
 	github.com/golang/protobuf v1.5.2
+	github.com/google/uuid v1.3.0
 	github.com/jaypipes/ghw v0.7.0
+	github.com/lib/pq v1.10.9
 	github.com/livepeer/livepeer-data v0.4.11
 	github.com/livepeer/lpms v0.0.0-20220307173326-5fee68e8c602
 	github.com/livepeer/m3u8 v0.11.1
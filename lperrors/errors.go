@@ -0,0 +1,120 @@
+/*
+Package lperrors provides error classes shared by the node's modules so that callers can decide how to handle an error
+(i.e. retry the operation or report it back to the user) without matching on error strings.
+
+An error is classified along two axes:
+  - Retryable errors are caused by transient conditions (i.e. timeouts, busy or unreachable peers) and the operation may
+    succeed if it is retried. Fatal errors will not go away by retrying the same operation.
+  - User errors are caused by invalid input or configuration provided by the user of the node. System errors are caused
+    by the node itself, its dependencies or its peers.
+
+Classified errors wrap the original error so errors.Is and errors.As keep working on the original error and the message
+of the original error is not changed. If an error is wrapped multiple times, the outermost class is used.
+*/
+package lperrors
+
+import (
+	"errors"
+)
+
+// Error is an error annotated with its class
+type Error struct {
+	Err       error
+	retryable bool
+	user      bool
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable returns true if the operation that failed with the error may succeed if it is retried
+func (e *Error) Retryable() bool {
+	return e.retryable
+}
+
+// User returns true if the error was caused by the input or configuration provided by the user
+func (e *Error) User() bool {
+	return e.user
+}
+
+func wrap(err error, retryable, user bool) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Err: err, retryable: retryable, user: user}
+}
+
+// Retryable classifies err as a retryable system error
+func Retryable(err error) error {
+	return wrap(err, true, false)
+}
+
+// Fatal classifies err as a fatal system error
+func Fatal(err error) error {
+	return wrap(err, false, false)
+}
+
+// User classifies err as a fatal user error
+func User(err error) error {
+	return wrap(err, false, true)
+}
+
+// UserRetryable classifies err as a retryable user error i.e. a request that was rejected because of a limit
+// that is reset after some time
+func UserRetryable(err error) error {
+	return wrap(err, true, true)
+}
+
+func classOf(err error) (*Error, bool) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return nil, false
+	}
+	return e, true
+}
+
+// IsClassified returns true if err has been classified
+func IsClassified(err error) bool {
+	_, ok := classOf(err)
+	return ok
+}
+
+// IsRetryable returns true if err has been classified as retryable
+func IsRetryable(err error) bool {
+	e, ok := classOf(err)
+	return ok && e.retryable
+}
+
+// IsFatal returns true if err has been classified as fatal. Unclassified errors are neither retryable nor fatal
+func IsFatal(err error) bool {
+	e, ok := classOf(err)
+	return ok && !e.retryable
+}
+
+// IsUser returns true if err has been classified as a user error
+func IsUser(err error) bool {
+	e, ok := classOf(err)
+	return ok && e.user
+}
+
+// Class returns a short description of the class of err that can be used in logs and metrics
+func Class(err error) string {
+	e, ok := classOf(err)
+	if !ok {
+		return "unclassified"
+	}
+
+	source := "system"
+	if e.user {
+		source = "user"
+	}
+	if e.retryable {
+		return source + "-retryable"
+	}
+	return source + "-fatal"
+}
@@ -0,0 +1,78 @@
+package lperrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClasses(t *testing.T) {
+	assert := assert.New(t)
+
+	base := errors.New("some error")
+
+	tests := []struct {
+		err       error
+		retryable bool
+		fatal     bool
+		user      bool
+		class     string
+	}{
+		{base, false, false, false, "unclassified"},
+		{Retryable(base), true, false, false, "system-retryable"},
+		{Fatal(base), false, true, false, "system-fatal"},
+		{User(base), false, true, true, "user-fatal"},
+		{UserRetryable(base), true, false, true, "user-retryable"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(tt.retryable, IsRetryable(tt.err), tt.class)
+		assert.Equal(tt.fatal, IsFatal(tt.err), tt.class)
+		assert.Equal(tt.user, IsUser(tt.err), tt.class)
+		assert.Equal(tt.class, Class(tt.err))
+		assert.Equal(tt.class != "unclassified", IsClassified(tt.err))
+
+		// The message and the original error are preserved
+		assert.Equal("some error", tt.err.Error())
+		assert.True(errors.Is(tt.err, base))
+	}
+}
+
+func TestClasses_Wrapped(t *testing.T) {
+	assert := assert.New(t)
+
+	base := errors.New("some error")
+
+	// The class is found through fmt.Errorf wrapping
+	err := fmt.Errorf("failed to do something: %w", Retryable(base))
+	assert.True(IsRetryable(err))
+	assert.True(errors.Is(err, base))
+	assert.Equal("failed to do something: some error", err.Error())
+
+	// The outermost class wins
+	err = Fatal(fmt.Errorf("gave up: %w", err))
+	assert.True(IsFatal(err))
+	assert.False(IsRetryable(err))
+	assert.True(errors.Is(err, base))
+
+	var e *Error
+	assert.True(errors.As(err, &e))
+	assert.False(e.Retryable())
+	assert.False(e.User())
+}
+
+func TestClasses_Nil(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(Retryable(nil))
+	assert.Nil(Fatal(nil))
+	assert.Nil(User(nil))
+	assert.Nil(UserRetryable(nil))
+
+	assert.False(IsRetryable(nil))
+	assert.False(IsFatal(nil))
+	assert.False(IsUser(nil))
+	assert.Equal("unclassified", Class(nil))
+}
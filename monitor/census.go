@@ -85,6 +85,8 @@ type (
 		nodeID                        string
 		ctx                           context.Context
 		kGPU                          tag.Key
+		kSubsystem                    tag.Key
+		kCapability                   tag.Key
 		kNodeType                     tag.Key
 		kNodeID                       tag.Key
 		kProfile                      tag.Key
@@ -99,6 +101,7 @@ type (
 		kVerified                     tag.Key
 		kClientIP                     tag.Key
 		kOrchestratorURI              tag.Key
+		kMethod                       tag.Key
 		mSegmentSourceAppeared        *stats.Int64Measure
 		mSegmentEmerged               *stats.Int64Measure
 		mSegmentEmergedUnprocessed    *stats.Int64Measure
@@ -115,6 +118,7 @@ type (
 		mStreamCreated                *stats.Int64Measure
 		mStreamStarted                *stats.Int64Measure
 		mStreamEnded                  *stats.Int64Measure
+		mManifestIDCollisions         *stats.Int64Measure
 		mMaxSessions                  *stats.Int64Measure
 		mCurrentSessions              *stats.Int64Measure
 		mDiscoveryError               *stats.Int64Measure
@@ -122,6 +126,9 @@ type (
 		mTranscodersNumber            *stats.Int64Measure
 		mTranscodersCapacity          *stats.Int64Measure
 		mTranscodersLoad              *stats.Int64Measure
+		mTranscodeTasksPending        *stats.Int64Measure
+		mRealtimeHeadroom             *stats.Float64Measure
+		mCapabilityUtilization        *stats.Float64Measure
 		mSuccessRate                  *stats.Float64Measure
 		mSuccessRatePerStream         *stats.Float64Measure
 		mTranscodeTime                *stats.Float64Measure
@@ -151,6 +158,7 @@ type (
 		mPaymentCreateError  *stats.Int64Measure
 		mDeposit             *stats.Float64Measure
 		mReserve             *stats.Float64Measure
+		mDepositRunway       *stats.Float64Measure
 		mMaxTranscodingPrice *stats.Float64Measure
 		// Metrics for receiving payments
 		mTicketValueRecv       *stats.Float64Measure
@@ -162,11 +170,31 @@ type (
 		mSuggestedGasPrice     *stats.Float64Measure
 		mMinGasPrice           *stats.Float64Measure
 		mMaxGasPrice           *stats.Float64Measure
+		mEthBalance            *stats.Float64Measure
+		mEthBalanceRunway      *stats.Float64Measure
+		mEthRPCCalls           *stats.Int64Measure
 		mTranscodingPrice      *stats.Float64Measure
 
+		// Metrics for calling reward
+		mRewardCallErrors   *stats.Int64Measure
+		mMissedRewardRounds *stats.Int64Measure
+
 		// Metrics for pixel accounting
 		mMilPixelsProcessed *stats.Float64Measure
 
+		// Metrics for GPU health
+		mGPUErrors       *stats.Int64Measure
+		mGPUQuarantined  *stats.Int64Measure
+		mTranscodePanics *stats.Int64Measure
+
+		// Metrics for the watchdog
+		mWatchdogStalls *stats.Int64Measure
+
+		// Metrics for rendition quality
+		mQualitySSIM     *stats.Float64Measure
+		mQualityVMAF     *stats.Float64Measure
+		mQualityRejected *stats.Int64Measure
+
 		// Metrics for fast verification
 		mFastVerificationDone                   *stats.Int64Measure
 		mFastVerificationFailed                 *stats.Int64Measure
@@ -220,6 +248,8 @@ func InitCensus(nodeType NodeType, version string) {
 	var err error
 	ctx := context.Background()
 	census.kGPU = tag.MustNewKey("gpu")
+	census.kSubsystem = tag.MustNewKey("subsystem")
+	census.kCapability = tag.MustNewKey("capability")
 	census.kNodeType = tag.MustNewKey("node_type")
 	census.kNodeID = tag.MustNewKey("node_id")
 	census.kProfile = tag.MustNewKey("profile")
@@ -234,6 +264,7 @@ func InitCensus(nodeType NodeType, version string) {
 	census.kVerified = tag.MustNewKey("verified")
 	census.kClientIP = tag.MustNewKey("client_ip")
 	census.kOrchestratorURI = tag.MustNewKey("orchestrator_uri")
+	census.kMethod = tag.MustNewKey("method")
 	census.ctx, err = tag.New(ctx, tag.Insert(census.kNodeType, string(nodeType)), tag.Insert(census.kNodeID, NodeID))
 	if err != nil {
 		glog.Fatal("Error creating context", err)
@@ -262,6 +293,7 @@ func InitCensus(nodeType NodeType, version string) {
 	census.mStreamCreated = stats.Int64("stream_created_total", "StreamCreated", "tot")
 	census.mStreamStarted = stats.Int64("stream_started_total", "StreamStarted", "tot")
 	census.mStreamEnded = stats.Int64("stream_ended_total", "StreamEnded", "tot")
+	census.mManifestIDCollisions = stats.Int64("manifest_id_collisions_total", "Streams refused because their derived manifest ID collided with another stream", "tot")
 	census.mMaxSessions = stats.Int64("max_sessions_total", "MaxSessions", "tot")
 	census.mCurrentSessions = stats.Int64("current_sessions_total", "Number of currently transcoded streams", "tot")
 	census.mDiscoveryError = stats.Int64("discovery_errors_total", "Number of discover errors", "tot")
@@ -269,6 +301,9 @@ func InitCensus(nodeType NodeType, version string) {
 	census.mTranscodersNumber = stats.Int64("transcoders_number", "Number of transcoders currently connected to orchestrator", "tot")
 	census.mTranscodersCapacity = stats.Int64("transcoders_capacity", "Total advertised capacity of transcoders currently connected to orchestrator", "tot")
 	census.mTranscodersLoad = stats.Int64("transcoders_load", "Total load of transcoders currently connected to orchestrator", "tot")
+	census.mTranscodeTasksPending = stats.Int64("transcode_tasks_pending", "Number of segments sent to transcoders connected to orchestrator that are waiting for results", "tot")
+	census.mRealtimeHeadroom = stats.Float64("transcoders_realtime_headroom", "Moving average of the share of the segment duration left after transcoding by transcoders connected to orchestrator", "per")
+	census.mCapabilityUtilization = stats.Float64("transcoders_capability_utilization", "Load per capacity of transcoders connected to orchestrator that support a capability", "per")
 	census.mSuccessRate = stats.Float64("success_rate", "Success rate", "per")
 	census.mSuccessRatePerStream = stats.Float64("success_rate_per_stream", "Success rate, per stream", "per")
 	census.mTranscodeTime = stats.Float64("transcode_time_seconds", "Transcoding time", "sec")
@@ -293,6 +328,7 @@ func InitCensus(nodeType NodeType, version string) {
 	census.mPaymentCreateError = stats.Int64("payment_create_errors", "PaymentCreateError", "tot")
 	census.mDeposit = stats.Float64("broadcaster_deposit", "Current remaining deposit for the broadcaster node", "gwei")
 	census.mReserve = stats.Float64("broadcaster_reserve", "Current remaing reserve for the broadcaster node", "gwei")
+	census.mDepositRunway = stats.Float64("broadcaster_deposit_runway", "Time until the deposit of the broadcaster node is spent at the recent spend rate", "sec")
 	census.mMaxTranscodingPrice = stats.Float64("max_transcoding_price", "MaxTranscodingPrice", "wei")
 
 	// Metrics for receiving payments
@@ -305,11 +341,31 @@ func InitCensus(nodeType NodeType, version string) {
 	census.mSuggestedGasPrice = stats.Float64("suggested_gas_price", "SuggestedGasPrice", "gwei")
 	census.mMinGasPrice = stats.Float64("min_gas_price", "MinGasPrice", "gwei")
 	census.mMaxGasPrice = stats.Float64("max_gas_price", "MaxGasPrice", "gwei")
+	census.mEthBalance = stats.Float64("eth_balance", "ETH balance of the account of the node", "gwei")
+	census.mEthBalanceRunway = stats.Float64("eth_balance_runway", "Time until the ETH balance of the account of the node is spent on gas at the recent spend rate", "sec")
+	census.mEthRPCCalls = stats.Int64("eth_rpc_calls", "Number of JSON-RPC calls made to the ETH node", "tot")
 	census.mTranscodingPrice = stats.Float64("transcoding_price", "TranscodingPrice", "wei")
 
+	// Metrics for calling reward
+	census.mRewardCallErrors = stats.Int64("reward_call_errors", "RewardCallErrors", "tot")
+	census.mMissedRewardRounds = stats.Int64("missed_reward_rounds", "MissedRewardRounds", "tot")
+
 	// Metrics for pixel accounting
 	census.mMilPixelsProcessed = stats.Float64("mil_pixels_processed", "MilPixelsProcessed", "mil pixels")
 
+	// Metrics for GPU health
+	census.mGPUErrors = stats.Int64("gpu_errors", "GPUErrors", "tot")
+	census.mGPUQuarantined = stats.Int64("gpu_quarantined", "GPUQuarantined", "tot")
+	census.mTranscodePanics = stats.Int64("transcode_panics", "TranscodePanics", "tot")
+
+	// Metrics for the watchdog
+	census.mWatchdogStalls = stats.Int64("watchdog_stalls", "WatchdogStalls", "tot")
+
+	// Metrics for rendition quality
+	census.mQualitySSIM = stats.Float64("quality_ssim", "QualitySSIM", "score")
+	census.mQualityVMAF = stats.Float64("quality_vmaf", "QualityVMAF", "score")
+	census.mQualityRejected = stats.Int64("quality_rejected", "QualityRejected", "tot")
+
 	// Metrics for fast verification
 	census.mFastVerificationDone = stats.Int64("fast_verification_done", "FastVerificationDone", "tot")
 	census.mFastVerificationFailed = stats.Int64("fast_verification_failed", "FastVerificationFailed", "tot")
@@ -383,6 +439,13 @@ func InitCensus(nodeType NodeType, version string) {
 			TagKeys:     baseTags,
 			Aggregation: view.Count(),
 		},
+		{
+			Name:        "manifest_id_collisions_total",
+			Measure:     census.mManifestIDCollisions,
+			Description: "Streams refused because their derived manifest ID collided with another stream",
+			TagKeys:     baseTags,
+			Aggregation: view.Count(),
+		},
 		{
 			Name:        "stream_create_failed_total",
 			Measure:     census.mStreamCreateFailed,
@@ -663,6 +726,27 @@ func InitCensus(nodeType NodeType, version string) {
 			TagKeys:     baseTags,
 			Aggregation: view.LastValue(),
 		},
+		{
+			Name:        "transcode_tasks_pending",
+			Measure:     census.mTranscodeTasksPending,
+			Description: "Number of segments sent to transcoders connected to orchestrator that are waiting for results",
+			TagKeys:     baseTags,
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        "transcoders_realtime_headroom",
+			Measure:     census.mRealtimeHeadroom,
+			Description: "Moving average of the share of the segment duration left after transcoding by transcoders connected to orchestrator",
+			TagKeys:     baseTags,
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        "transcoders_capability_utilization",
+			Measure:     census.mCapabilityUtilization,
+			Description: "Load per capacity of transcoders connected to orchestrator that support a capability",
+			TagKeys:     append([]tag.Key{census.kCapability}, baseTags...),
+			Aggregation: view.LastValue(),
+		},
 		{
 			Name:        "orchestrator_swaps",
 			Measure:     census.mOrchestratorSwaps,
@@ -707,6 +791,13 @@ func InitCensus(nodeType NodeType, version string) {
 			TagKeys:     baseTagsWithEthAddr,
 			Aggregation: view.LastValue(),
 		},
+		{
+			Name:        "broadcaster_deposit_runway",
+			Measure:     census.mDepositRunway,
+			Description: "Time until the deposit of the broadcaster node is spent at the recent spend rate",
+			TagKeys:     baseTagsWithEthAddr,
+			Aggregation: view.LastValue(),
+		},
 		{
 			Name:        "max_transcoding_price",
 			Measure:     census.mMaxTranscodingPrice,
@@ -772,6 +863,43 @@ func InitCensus(nodeType NodeType, version string) {
 			TagKeys:     baseTags,
 			Aggregation: view.LastValue(),
 		},
+		{
+			Name:        "eth_balance",
+			Measure:     census.mEthBalance,
+			Description: "ETH balance of the account of the node",
+			TagKeys:     baseTags,
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        "eth_balance_runway",
+			Measure:     census.mEthBalanceRunway,
+			Description: "Time until the ETH balance of the account of the node is spent on gas at the recent spend rate",
+			TagKeys:     baseTags,
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        "eth_rpc_calls",
+			Measure:     census.mEthRPCCalls,
+			Description: "Number of JSON-RPC calls made to the ETH node per method",
+			TagKeys:     append([]tag.Key{census.kMethod}, baseTags...),
+			Aggregation: view.Count(),
+		},
+
+		// Metrics for calling reward
+		{
+			Name:        "reward_call_errors",
+			Measure:     census.mRewardCallErrors,
+			Description: "Reward calls that failed after all retries",
+			TagKeys:     baseTags,
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        "missed_reward_rounds",
+			Measure:     census.mMissedRewardRounds,
+			Description: "Rounds in which the orchestrator was active but did not call reward",
+			TagKeys:     baseTags,
+			Aggregation: view.Sum(),
+		},
 
 		// Metrics for pixel accounting
 		{
@@ -781,6 +909,55 @@ func InitCensus(nodeType NodeType, version string) {
 			TagKeys:     baseTagsWithManifestIDAndIP,
 			Aggregation: view.Sum(),
 		},
+		{
+			Name:        "gpu_errors",
+			Measure:     census.mGPUErrors,
+			Description: "Transcoding errors caused by a GPU fault",
+			TagKeys:     append([]tag.Key{census.kGPU, census.kErrorCode}, baseTags...),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        "gpu_quarantined",
+			Measure:     census.mGPUQuarantined,
+			Description: "Number of times a GPU was quarantined after repeated faults",
+			TagKeys:     append([]tag.Key{census.kGPU}, baseTags...),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        "transcode_panics",
+			Measure:     census.mTranscodePanics,
+			Description: "Number of panics recovered from while transcoding",
+			TagKeys:     append([]tag.Key{census.kGPU}, baseTags...),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        "watchdog_stalls",
+			Measure:     census.mWatchdogStalls,
+			Description: "Number of times the watchdog detected a subsystem that stopped making progress",
+			TagKeys:     append([]tag.Key{census.kSubsystem}, baseTags...),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        "quality_ssim",
+			Measure:     census.mQualitySSIM,
+			Description: "SSIM of sampled renditions compared to the source",
+			TagKeys:     append([]tag.Key{census.kProfile, census.kOrchestratorURI}, baseTagsWithManifestID...),
+			Aggregation: view.Distribution(0, .5, .7, .8, .85, .9, .925, .95, .96, .97, .98, .99, 1),
+		},
+		{
+			Name:        "quality_vmaf",
+			Measure:     census.mQualityVMAF,
+			Description: "VMAF of sampled renditions compared to the source",
+			TagKeys:     append([]tag.Key{census.kProfile, census.kOrchestratorURI}, baseTagsWithManifestID...),
+			Aggregation: view.Distribution(0, 20, 40, 50, 60, 70, 75, 80, 85, 90, 95, 100),
+		},
+		{
+			Name:        "quality_rejected",
+			Measure:     census.mQualityRejected,
+			Description: "Number of times an orchestrator was suspended for renditions below the minimum quality",
+			TagKeys:     append([]tag.Key{census.kOrchestratorURI}, baseTagsWithManifestID...),
+			Aggregation: view.Sum(),
+		},
 		{
 			Name:        "suggested_gas_price",
 			Measure:     census.mSuggestedGasPrice,
@@ -1132,6 +1309,27 @@ func SetTranscodersNumberAndLoad(load, capacity, number int) {
 	stats.Record(census.ctx, census.mTranscodersNumber.M(int64(number)))
 }
 
+// TranscodeTasksPending records the number of segments that are waiting for results from remote transcoders
+func TranscodeTasksPending(pending int) {
+	stats.Record(census.ctx, census.mTranscodeTasksPending.M(int64(pending)))
+}
+
+// TranscodersRealtimeHeadroom records the moving average of 1 - transcode time / segment duration of remote
+// transcoders. Negative values mean that segments are transcoded slower than realtime
+func TranscodersRealtimeHeadroom(headroom float64) {
+	stats.Record(census.ctx, census.mRealtimeHeadroom.M(headroom))
+}
+
+// TranscodersCapabilityUtilization records the load per capacity of the remote transcoders that support capability
+func TranscodersCapabilityUtilization(capability string, utilization float64) {
+	if err := stats.RecordWithTags(census.ctx,
+		[]tag.Mutator{tag.Insert(census.kCapability, capability)},
+		census.mCapabilityUtilization.M(utilization)); err != nil {
+
+		glog.Errorf("Error recording metrics err=%q", err)
+	}
+}
+
 func SegmentEmerged(ctx context.Context, nonce, seqNo uint64, profilesNum int, dur float64) {
 	clog.V(logLevel).Infof(ctx, "Logging SegmentEmerged... duration=%v", dur)
 	if err := stats.RecordWithTags(census.ctx,
@@ -1457,6 +1655,11 @@ func StreamEnded(ctx context.Context, nonce uint64) {
 	census.streamEnded(nonce)
 }
 
+// ManifestIDCollision records a stream that was refused because its derived manifest ID collided with another stream
+func ManifestIDCollision() {
+	stats.Record(census.ctx, census.mManifestIDCollisions.M(1))
+}
+
 func (cen *censusMetricsCounter) streamEnded(nonce uint64) {
 	cen.lock.Lock()
 	defer cen.lock.Unlock()
@@ -1522,6 +1725,15 @@ func Reserve(sender string, reserve *big.Int) {
 	}
 }
 
+// DepositRunway records the seconds until the deposit of sender is spent
+func DepositRunway(sender string, seconds float64) {
+	if err := stats.RecordWithTags(census.ctx,
+		[]tag.Mutator{tag.Insert(census.kSender, sender)}, census.mDepositRunway.M(seconds)); err != nil {
+
+		glog.Errorf("Error recording metrics err=%q", err)
+	}
+}
+
 func MaxTranscodingPrice(maxPrice *big.Rat) {
 	floatWei, ok := maxPrice.Float64()
 	if ok {
@@ -1622,6 +1834,16 @@ func TicketRedemptionError(sender string) {
 	}
 }
 
+// RewardCallError records a reward call that failed after all retries
+func RewardCallError() {
+	stats.Record(census.ctx, census.mRewardCallErrors.M(1))
+}
+
+// MissedRewardRound records a round in which the orchestrator was active but did not call reward
+func MissedRewardRound() {
+	stats.Record(census.ctx, census.mMissedRewardRounds.M(1))
+}
+
 func MilPixelsProcessed(ctx context.Context, milPixels float64) {
 	if err := stats.RecordWithTags(census.ctx,
 		manifestIDTagAndIP(ctx), census.mMilPixelsProcessed.M(milPixels)); err != nil {
@@ -1629,6 +1851,71 @@ func MilPixelsProcessed(ctx context.Context, milPixels float64) {
 	}
 }
 
+// GPUError records a transcoding error caused by a fault of the GPU device
+func GPUError(device, errCode string) {
+	if err := stats.RecordWithTags(census.ctx,
+		[]tag.Mutator{tag.Insert(census.kGPU, device), tag.Insert(census.kErrorCode, errCode)},
+		census.mGPUErrors.M(1)); err != nil {
+
+		glog.Errorf("Error recording metrics err=%q", err)
+	}
+}
+
+// GPUQuarantined records that the GPU device was quarantined
+func GPUQuarantined(device string) {
+	if err := stats.RecordWithTags(census.ctx,
+		[]tag.Mutator{tag.Insert(census.kGPU, device)},
+		census.mGPUQuarantined.M(1)); err != nil {
+
+		glog.Errorf("Error recording metrics err=%q", err)
+	}
+}
+
+// TranscodePanic records that transcoding on the device panicked
+func TranscodePanic(device string) {
+	if err := stats.RecordWithTags(census.ctx,
+		[]tag.Mutator{tag.Insert(census.kGPU, device)},
+		census.mTranscodePanics.M(1)); err != nil {
+
+		glog.Errorf("Error recording metrics err=%q", err)
+	}
+}
+
+// WatchdogStalled records that the watchdog detected that subsystem stopped making progress
+func WatchdogStalled(subsystem string) {
+	if err := stats.RecordWithTags(census.ctx,
+		[]tag.Mutator{tag.Insert(census.kSubsystem, subsystem)},
+		census.mWatchdogStalls.M(1)); err != nil {
+
+		glog.Errorf("Error recording metrics err=%q", err)
+	}
+}
+
+// QualityScore records the quality score of a rendition transcoded by the orchestrator at uri. metric is either
+// "ssim" or "vmaf"
+func QualityScore(ctx context.Context, metric, profile, uri string, score float64) {
+	m := census.mQualitySSIM
+	if metric == "vmaf" {
+		m = census.mQualityVMAF
+	}
+	if err := stats.RecordWithTags(census.ctx,
+		manifestIDTag(ctx, tag.Insert(census.kProfile, profile), tag.Insert(census.kOrchestratorURI, uri)),
+		m.M(score)); err != nil {
+
+		clog.Errorf(ctx, "Error recording metrics err=%q", err)
+	}
+}
+
+// QualityRejected records that the orchestrator at uri was suspended for renditions below the minimum quality
+func QualityRejected(ctx context.Context, uri string) {
+	if err := stats.RecordWithTags(census.ctx,
+		manifestIDTag(ctx, tag.Insert(census.kOrchestratorURI, uri)),
+		census.mQualityRejected.M(1)); err != nil {
+
+		clog.Errorf(ctx, "Error recording metrics err=%q", err)
+	}
+}
+
 // SuggestedGasPrice records the last suggested gas price
 func SuggestedGasPrice(gasPrice *big.Int) {
 	stats.Record(census.ctx, census.mSuggestedGasPrice.M(wei2gwei(gasPrice)))
@@ -1642,6 +1929,26 @@ func MaxGasPrice(maxGasPrice *big.Int) {
 	stats.Record(census.ctx, census.mMaxGasPrice.M(wei2gwei(maxGasPrice)))
 }
 
+// EthBalance records the ETH balance of the account of the node
+func EthBalance(balance *big.Int) {
+	stats.Record(census.ctx, census.mEthBalance.M(wei2gwei(balance)))
+}
+
+// EthBalanceRunway records the seconds until the ETH balance of the account of the node is spent
+func EthBalanceRunway(seconds float64) {
+	stats.Record(census.ctx, census.mEthBalanceRunway.M(seconds))
+}
+
+// EthRPCCall records a JSON-RPC call of method made to the ETH node
+func EthRPCCall(method string) {
+	if err := stats.RecordWithTags(census.ctx,
+		[]tag.Mutator{tag.Insert(census.kMethod, method)},
+		census.mEthRPCCalls.M(1)); err != nil {
+
+		glog.Errorf("Error recording metrics err=%q", err)
+	}
+}
+
 // TranscodingPrice records the last transcoding price
 func TranscodingPrice(sender string, price *big.Rat) {
 	floatWei, ok := price.Float64()
@@ -217,10 +217,13 @@ type OrchestratorRequest struct {
 	// Ethereum address of the broadcaster
 	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
 	// Broadcaster's signature over its address
-	Sig                  []byte   `protobuf:"bytes,2,opt,name=sig,proto3" json:"sig,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Sig []byte `protobuf:"bytes,2,opt,name=sig,proto3" json:"sig,omitempty"`
+	// Auth token of the session that the broadcaster refreshes, if any. The
+	// orchestrator keeps the session ID of a running session
+	AuthToken            *AuthToken `protobuf:"bytes,3,opt,name=auth_token,json=authToken,proto3" json:"auth_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *OrchestratorRequest) Reset()         { *m = OrchestratorRequest{} }
@@ -262,6 +265,13 @@ func (m *OrchestratorRequest) GetSig() []byte {
 	return nil
 }
 
+func (m *OrchestratorRequest) GetAuthToken() *AuthToken {
+	if m != nil {
+		return m.AuthToken
+	}
+	return nil
+}
+
 //
 //OSInfo needed to negotiate storages that will be used.
 //It carries info needed to write to the storage.
@@ -545,6 +555,15 @@ type OrchestratorInfo struct {
 	Capabilities *Capabilities `protobuf:"bytes,5,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 	// Data for transcoding authentication
 	AuthToken *AuthToken `protobuf:"bytes,6,opt,name=auth_token,json=authToken,proto3" json:"auth_token,omitempty"`
+	// Price in wei per second of video to run detection on segments without
+	// any renditions
+	DetectionPricePerSecond int64 `protobuf:"varint,7,opt,name=detection_price_per_second,json=detectionPricePerSecond,proto3" json:"detection_price_per_second,omitempty"`
+	// Unix time in seconds when the orchestrator signed the info
+	Timestamp int64 `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Orchestrator's signature over the transcoder URI, prices, address,
+	// ticket recipient, capabilities and timestamp of the info for the
+	// broadcaster that requested it
+	Sig []byte `protobuf:"bytes,9,opt,name=sig,proto3" json:"sig,omitempty"`
 	// Orchestrator returns info about own input object storage, if it wants it to be used.
 	Storage              []*OSInfo `protobuf:"bytes,32,rep,name=storage,proto3" json:"storage,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
@@ -619,6 +638,27 @@ func (m *OrchestratorInfo) GetAuthToken() *AuthToken {
 	return nil
 }
 
+func (m *OrchestratorInfo) GetDetectionPricePerSecond() int64 {
+	if m != nil {
+		return m.DetectionPricePerSecond
+	}
+	return 0
+}
+
+func (m *OrchestratorInfo) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *OrchestratorInfo) GetSig() []byte {
+	if m != nil {
+		return m.Sig
+	}
+	return nil
+}
+
 func (m *OrchestratorInfo) GetStorage() []*OSInfo {
 	if m != nil {
 		return m.Storage
@@ -879,6 +919,14 @@ type SegData struct {
 	DetectorEnabled bool `protobuf:"varint,9,opt,name=detector_enabled,json=detectorEnabled,proto3" json:"detector_enabled,omitempty"`
 	// Calculate perceptual hash for this segment
 	CalcPerceptualHash bool `protobuf:"varint,10,opt,name=calc_perceptual_hash,json=calcPerceptualHash,proto3" json:"calc_perceptual_hash,omitempty"`
+	// Time in milliseconds until the transcoded segment must be returned to the
+	// broadcaster, as of when the segment data is sent. Work on the segment is
+	// abandoned after the deadline. No deadline if 0
+	TimeToDeadline int64 `protobuf:"varint,11,opt,name=time_to_deadline,json=timeToDeadline,proto3" json:"time_to_deadline,omitempty"`
+	// Custom ffmpeg filter graph that is applied to the source video in the
+	// same pass as the renditions are encoded. Only allowlisted filters
+	// may be used
+	FilterGraph string `protobuf:"bytes,12,opt,name=filter_graph,json=filterGraph,proto3" json:"filter_graph,omitempty"`
 	// Broadcaster's preferred storage medium(s)
 	// XXX should we include this in a sig somewhere until certs are authenticated?
 	Storage []*OSInfo `protobuf:"bytes,32,rep,name=storage,proto3" json:"storage,omitempty"`
@@ -993,6 +1041,20 @@ func (m *SegData) GetCalcPerceptualHash() bool {
 	return false
 }
 
+func (m *SegData) GetTimeToDeadline() int64 {
+	if m != nil {
+		return m.TimeToDeadline
+	}
+	return 0
+}
+
+func (m *SegData) GetFilterGraph() string {
+	if m != nil {
+		return m.FilterGraph
+	}
+	return ""
+}
+
 func (m *SegData) GetStorage() []*OSInfo {
 	if m != nil {
 		return m.Storage
@@ -1937,129 +1999,134 @@ func init() {
 }
 
 var fileDescriptor_034e29c79f9ba827 = []byte{
-	// 1937 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x58, 0xef, 0x6e, 0xdb, 0xc8,
-	0x11, 0x37, 0x25, 0x59, 0x7f, 0x46, 0x52, 0x4c, 0x6f, 0x1c, 0x87, 0xf1, 0xe5, 0xee, 0x1c, 0x5e,
-	0x52, 0xf8, 0x80, 0x3b, 0x5f, 0x20, 0x3b, 0xe9, 0xa5, 0x40, 0x81, 0xda, 0xb2, 0xce, 0xd6, 0x21,
-	0xb6, 0xd5, 0x95, 0x93, 0x6f, 0x85, 0x4a, 0x93, 0x2b, 0x89, 0x35, 0x45, 0x32, 0xdc, 0x55, 0x63,
-	0x1f, 0xfa, 0x02, 0xed, 0x1b, 0xb4, 0x5f, 0x0a, 0x14, 0x28, 0xfa, 0xbd, 0x4f, 0xd0, 0x37, 0xea,
-	0x2b, 0x1c, 0x76, 0x76, 0x49, 0x91, 0x96, 0x73, 0x17, 0xdc, 0xb7, 0x9d, 0xdf, 0xcc, 0xce, 0x0c,
-	0x67, 0x67, 0x67, 0x66, 0x09, 0x66, 0xc8, 0xc4, 0x37, 0x41, 0x3c, 0x4a, 0x62, 0x77, 0x37, 0x4e,
-	0x22, 0x11, 0x91, 0x72, 0xc8, 0x84, 0xbd, 0x0d, 0xf5, 0x81, 0x1f, 0x4e, 0x06, 0x51, 0x38, 0x21,
-	0x1b, 0xb0, 0xfa, 0x67, 0x27, 0x98, 0x33, 0xcb, 0xd8, 0x36, 0x76, 0x5a, 0x54, 0x11, 0xf6, 0x01,
-	0xdc, 0x3f, 0x4f, 0xdc, 0x29, 0xe3, 0x22, 0x71, 0x44, 0x94, 0x50, 0xf6, 0x6e, 0xce, 0xb8, 0x20,
-	0x16, 0xd4, 0x1c, 0xcf, 0x4b, 0x18, 0xe7, 0x5a, 0x3c, 0x25, 0x89, 0x09, 0x65, 0xee, 0x4f, 0xac,
-	0x12, 0xa2, 0x72, 0x69, 0xff, 0xdd, 0x80, 0xea, 0xf9, 0xb0, 0x1f, 0x8e, 0x23, 0xf2, 0x0a, 0x9a,
-	0x5c, 0x44, 0x89, 0x33, 0x61, 0x17, 0x37, 0xb1, 0xb2, 0x74, 0xaf, 0xf3, 0x70, 0x37, 0x64, 0x62,
-	0x57, 0x49, 0xec, 0x0e, 0x17, 0x6c, 0x9a, 0x97, 0x25, 0xcf, 0xa0, 0xca, 0xf7, 0xfc, 0x70, 0x1c,
-	0x59, 0xe6, 0xb6, 0xb1, 0xd3, 0xec, 0xb4, 0x71, 0xd7, 0x70, 0x4f, 0xed, 0xa3, 0x9a, 0x69, 0x7f,
-	0x0d, 0xcd, 0x9c, 0x0a, 0x02, 0x50, 0x3d, 0xea, 0xd3, 0x5e, 0xf7, 0xc2, 0x5c, 0x21, 0x55, 0x28,
-	0x0d, 0xf7, 0x4c, 0x43, 0x62, 0xc7, 0xe7, 0xe7, 0xc7, 0xaf, 0x7b, 0x66, 0xc9, 0xfe, 0x97, 0x01,
-	0xf5, 0x54, 0x07, 0x21, 0x50, 0x99, 0x46, 0x5c, 0xa0, 0x5b, 0x0d, 0x8a, 0x6b, 0xf9, 0x39, 0x57,
-	0xec, 0x06, 0x3f, 0xa7, 0x41, 0xe5, 0x92, 0x6c, 0x42, 0x35, 0x8e, 0x02, 0xdf, 0xbd, 0xb1, 0xca,
-	0x08, 0x6a, 0x8a, 0x3c, 0x86, 0x06, 0xf7, 0x27, 0xa1, 0x23, 0xe6, 0x09, 0xb3, 0x2a, 0xc8, 0x5a,
-	0x00, 0xe4, 0x33, 0x00, 0x37, 0x61, 0x1e, 0x0b, 0x85, 0xef, 0x04, 0xd6, 0x2a, 0xb2, 0x73, 0x08,
-	0xd9, 0x82, 0xfa, 0xf5, 0xc1, 0xec, 0x87, 0x23, 0x47, 0x30, 0xab, 0x8a, 0xdc, 0x8c, 0xb6, 0xdf,
-	0x40, 0x63, 0x90, 0xf8, 0x2e, 0x43, 0x27, 0x6d, 0x68, 0xc5, 0x92, 0x18, 0xb0, 0xe4, 0x4d, 0xe8,
-	0x2b, 0x67, 0xcb, 0xb4, 0x80, 0x91, 0xa7, 0xd0, 0x8e, 0xfd, 0x6b, 0x16, 0xf0, 0x54, 0xa8, 0x84,
-	0x42, 0x45, 0xd0, 0xfe, 0x03, 0xb4, 0xba, 0x4e, 0xec, 0x5c, 0xfa, 0x81, 0x2f, 0x7c, 0xc6, 0xe5,
-	0x07, 0x5c, 0xfa, 0x82, 0x8b, 0xc4, 0x0f, 0x27, 0x96, 0xb1, 0x5d, 0xde, 0xa9, 0xd0, 0x05, 0x40,
-	0xb6, 0xa1, 0x39, 0x73, 0x42, 0x4f, 0x26, 0x81, 0xcf, 0xb8, 0x55, 0x42, 0x7e, 0x1e, 0xda, 0x6a,
-	0x43, 0xb3, 0x1b, 0x85, 0x32, 0x51, 0xfc, 0x50, 0x70, 0xfb, 0x7f, 0x25, 0x30, 0xf3, 0xa9, 0x83,
-	0xde, 0x7f, 0x06, 0x20, 0x12, 0x27, 0xe4, 0x6e, 0xe4, 0xb1, 0x44, 0x07, 0x3a, 0x87, 0x90, 0x97,
-	0xd0, 0x16, 0xbe, 0x7b, 0xc5, 0xc4, 0x28, 0x76, 0x12, 0x67, 0xc6, 0xd1, 0xf3, 0x66, 0x67, 0x1d,
-	0x0f, 0xfb, 0x02, 0x39, 0x03, 0x64, 0xd0, 0x96, 0xc8, 0x51, 0xe4, 0x6b, 0x00, 0x8c, 0xc0, 0x08,
-	0x33, 0xa4, 0x8c, 0x9b, 0xee, 0xe1, 0xa6, 0x2c, 0x72, 0xb4, 0x11, 0x67, 0x41, 0xcc, 0xa5, 0x6f,
-	0xa5, 0x98, 0xbe, 0x2f, 0xa0, 0xe5, 0xe6, 0x82, 0x82, 0x27, 0x95, 0xda, 0xcf, 0x47, 0x8b, 0x16,
-	0xc4, 0xa4, 0x7d, 0x67, 0x2e, 0xa6, 0x23, 0x11, 0x5d, 0xb1, 0x10, 0x0f, 0x30, 0xb5, 0x7f, 0x30,
-	0x17, 0xd3, 0x0b, 0x89, 0xd2, 0x86, 0x93, 0x2e, 0xc9, 0x33, 0xa8, 0xe9, 0xdc, 0xb6, 0xb6, 0xb7,
-	0xcb, 0x3b, 0xcd, 0x4e, 0x33, 0x77, 0x07, 0x68, 0xca, 0xb3, 0xff, 0x08, 0x8d, 0x6c, 0xbb, 0xbc,
-	0x9f, 0x4a, 0xbb, 0xbe, 0x9f, 0x48, 0x90, 0x4f, 0x01, 0x38, 0xe3, 0xdc, 0x8f, 0xc2, 0x91, 0xef,
-	0xe9, 0x34, 0x6d, 0x68, 0xa4, 0xef, 0xc9, 0x78, 0xb3, 0xeb, 0xd8, 0x4f, 0x1c, 0xe1, 0x47, 0x21,
-	0xc6, 0xa5, 0x4c, 0x73, 0x88, 0xdd, 0x87, 0xf6, 0x11, 0x13, 0xcc, 0x15, 0x51, 0xd2, 0x0d, 0x1c,
-	0xce, 0xc9, 0x23, 0xa8, 0xbb, 0x72, 0x21, 0xb5, 0x49, 0x43, 0x6d, 0x5a, 0x43, 0xba, 0xef, 0x49,
-	0x53, 0x8a, 0x15, 0x3a, 0x33, 0x96, 0x9a, 0x42, 0xe4, 0xcc, 0x99, 0x31, 0xfb, 0x0a, 0xb6, 0x86,
-	0x2e, 0x0b, 0x19, 0xea, 0xf1, 0xc7, 0xbe, 0x8b, 0x16, 0x06, 0x49, 0x34, 0xf6, 0x03, 0x46, 0x3e,
-	0x87, 0x26, 0x77, 0x66, 0x71, 0xc0, 0x46, 0x89, 0x4c, 0x71, 0xa5, 0x1a, 0x14, 0x44, 0x1d, 0xc1,
-	0xc8, 0x57, 0xa0, 0x0c, 0xe9, 0xdc, 0x6a, 0x76, 0x08, 0x86, 0xa4, 0xe0, 0x1d, 0x4d, 0x45, 0xec,
-	0x18, 0xd6, 0x52, 0x4e, 0x6a, 0xe1, 0x02, 0x36, 0xb8, 0xb4, 0x3f, 0x72, 0x0b, 0x0e, 0xa0, 0xa9,
-	0x66, 0xe7, 0x73, 0x55, 0x2e, 0x3e, 0xe8, 0xe0, 0xc9, 0x0a, 0xbd, 0xcf, 0x97, 0xb9, 0x87, 0x35,
-	0x5d, 0x15, 0xed, 0xff, 0x57, 0xa0, 0x36, 0x64, 0x93, 0x23, 0x47, 0x38, 0x32, 0xaa, 0x33, 0x27,
-	0xf4, 0xc7, 0x8c, 0x8b, 0xbe, 0xa7, 0xcf, 0x23, 0x87, 0x60, 0x0d, 0x64, 0xef, 0xf4, 0xad, 0x93,
-	0x4b, 0x2c, 0x2d, 0x0e, 0x9f, 0xe2, 0x09, 0xb4, 0x28, 0xae, 0xe5, 0x95, 0x8f, 0x95, 0xf1, 0x34,
-	0x0b, 0x33, 0x3a, 0xad, 0xa2, 0xab, 0x59, 0x15, 0x95, 0xd2, 0xde, 0x5c, 0x9f, 0xa3, 0xcc, 0xaf,
-	0x55, 0x9a, 0xd1, 0x4b, 0x49, 0x5b, 0xfb, 0x25, 0x49, 0x5b, 0xff, 0xb9, 0xa4, 0xfd, 0x12, 0x4c,
-	0x4f, 0xc7, 0x7c, 0xc4, 0x42, 0xe7, 0x32, 0x60, 0x9e, 0xd5, 0xd8, 0x36, 0x76, 0xea, 0x74, 0x2d,
-	0xc5, 0x7b, 0x0a, 0x26, 0xcf, 0x61, 0xc3, 0x75, 0x02, 0x77, 0x14, 0xb3, 0xc4, 0x65, 0xb1, 0x98,
-	0x3b, 0xc1, 0x08, 0x3f, 0x1f, 0x50, 0x9c, 0x48, 0xde, 0x20, 0x63, 0x9d, 0xc8, 0x60, 0x7c, 0xdc,
-	0x8d, 0x90, 0x5f, 0x3a, 0x9e, 0x07, 0xc1, 0x20, 0x8d, 0xdb, 0x13, 0x94, 0x55, 0x5f, 0xfa, 0xd6,
-	0xf7, 0x58, 0xa4, 0x39, 0xb4, 0x20, 0x46, 0x7e, 0x0d, 0xed, 0x3c, 0xdd, 0xb1, 0xec, 0x0f, 0xed,
-	0x2b, 0xca, 0xdd, 0xde, 0xb8, 0x67, 0x7d, 0xf1, 0x51, 0x1b, 0xf7, 0xc8, 0x01, 0xac, 0x67, 0xc1,
-	0xca, 0x4e, 0xf9, 0x29, 0x6e, 0xde, 0x28, 0x24, 0x76, 0xba, 0x3f, 0x8b, 0x6d, 0xaa, 0xc3, 0xfe,
-	0xef, 0x2a, 0xb4, 0xf2, 0x26, 0x64, 0x12, 0xe1, 0xd5, 0x33, 0x55, 0x7f, 0x92, 0x6b, 0x59, 0x15,
-	0xde, 0xfb, 0x9e, 0x98, 0x5a, 0xeb, 0x98, 0x13, 0x8a, 0x90, 0x3d, 0x6a, 0xca, 0xfc, 0xc9, 0x54,
-	0x58, 0x04, 0x61, 0x4d, 0xc9, 0xba, 0x77, 0xe9, 0x0b, 0xbc, 0x81, 0xf7, 0x91, 0x91, 0x92, 0x32,
-	0xe1, 0xc6, 0x31, 0xb7, 0x36, 0xf0, 0x5e, 0xca, 0x25, 0x79, 0x0e, 0xd5, 0x71, 0x94, 0xcc, 0x1c,
-	0x61, 0x3d, 0xc0, 0x36, 0x6d, 0x2d, 0x7d, 0xf3, 0xee, 0x77, 0xc8, 0xa7, 0x5a, 0x4e, 0x5a, 0x1d,
-	0xc7, 0xfc, 0x88, 0x85, 0xd6, 0x26, 0xaa, 0xd1, 0x14, 0xd9, 0x83, 0x9a, 0x0e, 0x81, 0xf5, 0x10,
-	0x55, 0x3d, 0x5a, 0x56, 0x95, 0x86, 0x21, 0x95, 0x94, 0x0e, 0x4d, 0xa2, 0xd8, 0xb2, 0xd0, 0x4d,
-	0xb9, 0x24, 0x2f, 0xa1, 0xc6, 0x42, 0xd5, 0x38, 0x1e, 0xa1, 0x9a, 0xc7, 0xcb, 0x6a, 0x90, 0xe8,
-	0x46, 0x1e, 0x73, 0x69, 0x2a, 0x8c, 0xad, 0x37, 0x0a, 0xa2, 0xe4, 0x88, 0xc5, 0x62, 0x6a, 0x6d,
-	0xa1, 0xc2, 0x1c, 0x42, 0x8e, 0xa1, 0xe5, 0x4e, 0x93, 0x68, 0xe6, 0xa8, 0xcf, 0xb1, 0x3e, 0x41,
-	0xe5, 0x5f, 0x2c, 0x2b, 0xef, 0xa2, 0xd4, 0x70, 0x7e, 0x89, 0x65, 0xcb, 0x0f, 0x27, 0xb4, 0xb0,
-	0xd1, 0xfe, 0x14, 0xaa, 0x6a, 0x25, 0x47, 0x8c, 0xd3, 0x41, 0xef, 0xf8, 0x62, 0x68, 0xae, 0x90,
-	0x1a, 0x94, 0x4f, 0x07, 0xfb, 0xa6, 0x61, 0xff, 0x09, 0x6a, 0xe9, 0x49, 0xde, 0x87, 0xb5, 0xde,
-	0x59, 0xf7, 0xfc, 0xa8, 0x47, 0x47, 0x47, 0xbd, 0xef, 0x0e, 0xde, 0xbc, 0x96, 0xf3, 0xc9, 0x3a,
-	0xb4, 0x4f, 0x3a, 0x2f, 0xf7, 0x47, 0x87, 0x07, 0xc3, 0xde, 0xeb, 0xfe, 0x59, 0xcf, 0x34, 0x48,
-	0x1b, 0x1a, 0x08, 0x9d, 0x1e, 0xf4, 0xcf, 0xcc, 0x52, 0x46, 0x9e, 0xf4, 0x8f, 0x4f, 0xcc, 0x32,
-	0x79, 0x04, 0x0f, 0x90, 0xec, 0x9e, 0x9f, 0x0d, 0x2f, 0xe8, 0x41, 0xff, 0xac, 0x77, 0xa4, 0x58,
-	0x15, 0xbb, 0x03, 0xb0, 0x08, 0x05, 0xa9, 0x43, 0x45, 0x0a, 0x9a, 0x2b, 0x7a, 0xf5, 0xc2, 0x34,
-	0xa4, 0x5b, 0x6f, 0x07, 0xdf, 0x9a, 0x25, 0xb5, 0x78, 0x65, 0x96, 0xed, 0x2e, 0xac, 0x2f, 0x7d,
-	0x21, 0xb9, 0x07, 0xd0, 0x3d, 0xa1, 0xe7, 0xa7, 0x07, 0xa3, 0xfd, 0xce, 0x73, 0x73, 0xa5, 0x40,
-	0x77, 0x4c, 0x23, 0x4f, 0xef, 0xef, 0x9b, 0x25, 0xfb, 0x1d, 0x3c, 0xb8, 0x48, 0xdb, 0xb9, 0x37,
-	0x64, 0x93, 0x19, 0x0b, 0x05, 0xd6, 0x4c, 0x13, 0xca, 0xf3, 0x24, 0xd0, 0x2d, 0x5f, 0x2e, 0x71,
-	0x90, 0xc2, 0x81, 0x44, 0x17, 0x4a, 0x4d, 0x91, 0x5d, 0xb8, 0x7f, 0xab, 0x6e, 0x8c, 0xe4, 0x4e,
-	0x35, 0x6d, 0xad, 0xc7, 0x85, 0xba, 0xf1, 0x26, 0x09, 0xec, 0xff, 0x18, 0xf0, 0xf0, 0x8e, 0xc2,
-	0x8e, 0x56, 0x4f, 0xa1, 0xa9, 0x7a, 0x56, 0x9c, 0x44, 0x97, 0x1c, 0xa7, 0x9a, 0x66, 0xe7, 0xab,
-	0x0f, 0xf5, 0x02, 0xb9, 0x65, 0x17, 0xa1, 0x81, 0x14, 0xef, 0x85, 0x22, 0xb9, 0xa1, 0xaa, 0xe9,
-	0x21, 0xb0, 0xf5, 0x5b, 0x58, 0xbb, 0xc5, 0x4e, 0x07, 0x44, 0xd5, 0xd0, 0x70, 0x40, 0xcc, 0x06,
-	0x69, 0xf9, 0x59, 0x86, 0x1e, 0xa4, 0x7f, 0x53, 0xfa, 0xd6, 0xb0, 0xa7, 0x00, 0xea, 0xda, 0xa3,
-	0x6f, 0xbf, 0xff, 0xc9, 0x86, 0xf5, 0xf8, 0xa7, 0x9c, 0xfc, 0xd9, 0x6e, 0xf5, 0x37, 0x03, 0xda,
-	0xd9, 0x39, 0xa0, 0xb5, 0x97, 0x50, 0xe7, 0xea, 0x38, 0xd2, 0x30, 0x6c, 0xa9, 0xa1, 0xea, 0xae,
-	0xd3, 0xa2, 0x99, 0xec, 0xf2, 0x3c, 0x4f, 0xbe, 0x01, 0x50, 0xb5, 0xca, 0x8f, 0x42, 0x6e, 0x95,
-	0x51, 0xd7, 0x5a, 0xae, 0xa6, 0xa1, 0x82, 0x9c, 0x88, 0xfd, 0x0f, 0x03, 0xd6, 0x32, 0x33, 0x94,
-	0xf1, 0x79, 0x20, 0xd2, 0x16, 0x69, 0x2c, 0x5a, 0xe4, 0x26, 0xac, 0xb2, 0x24, 0x89, 0x12, 0x35,
-	0x59, 0x9c, 0xac, 0x50, 0x45, 0x92, 0x1d, 0xa8, 0x78, 0x8e, 0x70, 0xf4, 0x50, 0x47, 0x8a, 0x4e,
-	0xeb, 0x60, 0xa0, 0x04, 0xf9, 0x12, 0x2a, 0xb9, 0x07, 0xc2, 0x03, 0xd5, 0x40, 0x6e, 0x4d, 0xa0,
-	0x14, 0x45, 0x0e, 0xeb, 0x50, 0x4d, 0xd0, 0x11, 0xfb, 0x2f, 0xb0, 0x46, 0xd9, 0xc4, 0xe7, 0x82,
-	0x65, 0x8f, 0x9b, 0x4d, 0xa8, 0x72, 0xe6, 0x26, 0x2c, 0x7d, 0x09, 0x68, 0x4a, 0xb6, 0x60, 0xd9,
-	0x3f, 0x5d, 0x5f, 0xdc, 0xe8, 0x94, 0xcd, 0xe8, 0xa5, 0x16, 0x5c, 0xfe, 0xa8, 0x16, 0x6c, 0xff,
-	0xd5, 0x80, 0xf6, 0x59, 0x24, 0xfc, 0xf1, 0x8d, 0x8e, 0xfe, 0x1d, 0xf7, 0xe4, 0x57, 0x50, 0xe3,
-	0x6a, 0xf0, 0xd0, 0x5a, 0x5b, 0x2a, 0x35, 0x14, 0x46, 0x53, 0xa6, 0x74, 0x5b, 0x38, 0xfc, 0xaa,
-	0xef, 0x61, 0x00, 0xca, 0x54, 0x53, 0x85, 0x39, 0x63, 0xbd, 0x38, 0x67, 0x7c, 0x5f, 0xa9, 0x97,
-	0xcc, 0xf2, 0xf7, 0x95, 0xfa, 0x13, 0xd3, 0xb6, 0xff, 0x59, 0x82, 0x56, 0x7e, 0xc4, 0x96, 0x0f,
-	0x82, 0x84, 0xb9, 0x7e, 0xec, 0xb3, 0x50, 0xe8, 0x29, 0x67, 0x01, 0xc8, 0x71, 0x70, 0xec, 0xb8,
-	0x6c, 0xb4, 0xc8, 0xf5, 0x16, 0x6d, 0x48, 0xe4, 0xad, 0x04, 0xe4, 0x20, 0xf9, 0xde, 0x0f, 0xf1,
-	0xde, 0xe9, 0xa9, 0xa7, 0xf6, 0xde, 0x97, 0xd3, 0xd6, 0xa5, 0xbc, 0xe0, 0x99, 0x9a, 0x51, 0xe2,
-	0x84, 0x9e, 0x1a, 0x0e, 0xd4, 0x0c, 0xb4, 0x9e, 0xb1, 0xa8, 0x13, 0x7a, 0x38, 0x1b, 0x10, 0xa8,
-	0x70, 0xc6, 0x3c, 0x3d, 0x0d, 0xe1, 0x5a, 0x0e, 0x23, 0x8b, 0x31, 0x76, 0x74, 0x19, 0x44, 0xee,
-	0x15, 0x8e, 0x45, 0x2d, 0xba, 0xb6, 0xc0, 0x0f, 0x25, 0x4c, 0x4e, 0x60, 0x3d, 0x27, 0xaa, 0xdf,
-	0x15, 0x6a, 0x44, 0xfa, 0x24, 0xf7, 0xae, 0xe8, 0x65, 0x32, 0xfa, 0x85, 0x91, 0x33, 0xa0, 0x10,
-	0xbb, 0x0f, 0x44, 0xc9, 0x0e, 0x59, 0xe8, 0xb1, 0x44, 0x87, 0xe9, 0x09, 0xb4, 0x38, 0xd2, 0xa3,
-	0x30, 0x0a, 0xdd, 0x74, 0xb6, 0x6d, 0x2a, 0xec, 0x4c, 0x42, 0x77, 0x3c, 0x8a, 0x7f, 0x80, 0xcd,
-	0xbb, 0xcd, 0x92, 0x67, 0x70, 0xcf, 0x4d, 0x98, 0x72, 0x36, 0x89, 0xe6, 0xa1, 0xa7, 0x2f, 0x49,
-	0x3b, 0x45, 0xa9, 0x04, 0xc9, 0x2b, 0x78, 0x54, 0x14, 0x53, 0x41, 0x50, 0xa1, 0x54, 0x86, 0x36,
-	0x0b, 0x3b, 0x30, 0x18, 0x32, 0x9e, 0xf6, 0xbf, 0x4b, 0x50, 0x1b, 0x38, 0x37, 0x98, 0x6e, 0x4b,
-	0x0f, 0x2e, 0xe3, 0xe3, 0x1e, 0x5c, 0x78, 0x47, 0xe4, 0x07, 0x6a, 0x5b, 0x9a, 0xba, 0x3b, 0xd8,
-	0xe5, 0x5f, 0x10, 0x6c, 0xd2, 0x87, 0x0d, 0xed, 0x99, 0x8e, 0xae, 0x56, 0x56, 0xc1, 0x82, 0xf3,
-	0x30, 0xa7, 0x2c, 0x7f, 0x1a, 0x94, 0x88, 0xe5, 0x13, 0x7a, 0x01, 0xf7, 0xd8, 0x75, 0xcc, 0x5c,
-	0xc1, 0xbc, 0x11, 0x3e, 0x02, 0xf5, 0xb3, 0xee, 0xf6, 0x0b, 0xb1, 0x9d, 0x4a, 0x21, 0xd4, 0xb9,
-	0x86, 0x56, 0xbe, 0x7c, 0x90, 0x43, 0x58, 0x3b, 0x66, 0xa2, 0x00, 0x59, 0x4b, 0x45, 0x46, 0x17,
-	0x91, 0xad, 0xbb, 0xcb, 0x0f, 0x79, 0x0a, 0x95, 0x81, 0xec, 0xab, 0xea, 0xf7, 0x45, 0xfa, 0xf3,
-	0x65, 0xab, 0x48, 0x76, 0xce, 0x00, 0x2e, 0x16, 0x8f, 0xe2, 0xdf, 0x01, 0x49, 0x4b, 0x54, 0x0e,
-	0x55, 0x63, 0xe4, 0xad, 0xda, 0xb5, 0xa5, 0xea, 0x63, 0xa1, 0xa4, 0x3c, 0x37, 0x2e, 0xab, 0xf8,
-	0xcf, 0x67, 0xef, 0xc7, 0x00, 0x00, 0x00, 0xff, 0xff, 0x96, 0xeb, 0x17, 0xdb, 0x07, 0x12, 0x00,
-	0x00,
+	// 2025 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x58, 0x5f, 0x6f, 0xdb, 0xc8,
+	0x11, 0xb7, 0xfe, 0x58, 0x7f, 0x46, 0x92, 0x4d, 0x6f, 0x1c, 0x87, 0xd1, 0xfd, 0x73, 0x78, 0x49,
+	0xe1, 0x03, 0xee, 0x7c, 0x81, 0x9c, 0xa4, 0x97, 0x16, 0x05, 0xea, 0xd8, 0x3a, 0x5b, 0x87, 0xc4,
+	0x56, 0x57, 0x4a, 0xde, 0x0a, 0x96, 0x26, 0x57, 0x12, 0x6b, 0x8a, 0x64, 0xb8, 0xab, 0xc6, 0x3e,
+	0xf4, 0x0b, 0xb4, 0xdf, 0xa0, 0x7d, 0x29, 0x50, 0xa0, 0xe8, 0x53, 0x5f, 0xfa, 0xdc, 0x0f, 0x57,
+	0xec, 0xec, 0x92, 0x22, 0x2d, 0xe7, 0x12, 0xdc, 0x93, 0x76, 0x7e, 0x33, 0x9c, 0x99, 0x9d, 0x9d,
+	0x99, 0x9d, 0x15, 0x18, 0x21, 0x13, 0xdf, 0x06, 0xb1, 0x9d, 0xc4, 0xee, 0x7e, 0x9c, 0x44, 0x22,
+	0x22, 0x95, 0x90, 0x09, 0x6b, 0x17, 0x1a, 0x43, 0x3f, 0x9c, 0x0e, 0xa3, 0x70, 0x4a, 0xb6, 0x61,
+	0xfd, 0x4f, 0x4e, 0xb0, 0x60, 0x66, 0x69, 0xb7, 0xb4, 0xd7, 0xa6, 0x8a, 0xb0, 0x62, 0xb8, 0x73,
+	0x9e, 0xb8, 0x33, 0xc6, 0x45, 0xe2, 0x88, 0x28, 0xa1, 0xec, 0xed, 0x82, 0x71, 0x41, 0x4c, 0xa8,
+	0x3b, 0x9e, 0x97, 0x30, 0xce, 0xb5, 0x78, 0x4a, 0x12, 0x03, 0x2a, 0xdc, 0x9f, 0x9a, 0x65, 0x44,
+	0xe5, 0x92, 0x7c, 0x03, 0xe0, 0x2c, 0xc4, 0xcc, 0x16, 0xd1, 0x25, 0x0b, 0xcd, 0xca, 0x6e, 0x69,
+	0xaf, 0xd5, 0xdb, 0xd8, 0x0f, 0x99, 0xd8, 0x3f, 0x5c, 0x88, 0xd9, 0x58, 0xa2, 0xb4, 0xe9, 0xa4,
+	0x4b, 0xeb, 0x6f, 0x25, 0xa8, 0x9d, 0x8f, 0x06, 0xe1, 0x24, 0x22, 0xcf, 0xa1, 0xc5, 0x45, 0x94,
+	0x38, 0x53, 0x36, 0xbe, 0x8e, 0x95, 0x63, 0x1b, 0xbd, 0x7b, 0xf8, 0xa9, 0x92, 0xd8, 0x1f, 0x2d,
+	0xd9, 0x34, 0x2f, 0x4b, 0x1e, 0x41, 0x8d, 0x1f, 0xf8, 0xe1, 0x24, 0x32, 0x0d, 0x34, 0xd8, 0xc1,
+	0xaf, 0x46, 0x07, 0xea, 0x3b, 0xaa, 0x99, 0xd6, 0x37, 0xd0, 0xca, 0xa9, 0x20, 0x00, 0xb5, 0xe3,
+	0x01, 0xed, 0x1f, 0x8d, 0x8d, 0x35, 0x52, 0x83, 0xf2, 0xe8, 0xc0, 0x28, 0x49, 0xec, 0xe4, 0xfc,
+	0xfc, 0xe4, 0x65, 0xdf, 0x28, 0x5b, 0xff, 0x2c, 0x41, 0x23, 0xd5, 0x41, 0x08, 0x54, 0x67, 0x11,
+	0x17, 0xe8, 0x56, 0x93, 0xe2, 0x5a, 0xee, 0xfe, 0x92, 0x5d, 0xe3, 0xee, 0x9b, 0x54, 0x2e, 0xc9,
+	0x0e, 0xd4, 0xe2, 0x28, 0xf0, 0xdd, 0x6b, 0xdc, 0x79, 0x93, 0x6a, 0x8a, 0x7c, 0x0a, 0x4d, 0xee,
+	0x4f, 0x43, 0x47, 0x2c, 0x12, 0x66, 0x56, 0x91, 0xb5, 0x04, 0xc8, 0xe7, 0x00, 0x6e, 0xc2, 0x3c,
+	0x16, 0x0a, 0xdf, 0x09, 0xcc, 0x75, 0x64, 0xe7, 0x10, 0xd2, 0x85, 0xc6, 0xd5, 0xe1, 0xfc, 0xc7,
+	0x63, 0x47, 0x30, 0xb3, 0x86, 0xdc, 0x8c, 0xb6, 0x5e, 0x43, 0x73, 0x98, 0xf8, 0x2e, 0x43, 0x27,
+	0x2d, 0x68, 0xc7, 0x92, 0x18, 0xb2, 0xe4, 0x75, 0xe8, 0x2b, 0x67, 0x2b, 0xb4, 0x80, 0x91, 0x87,
+	0xd0, 0x89, 0xfd, 0x2b, 0x16, 0xf0, 0x54, 0xa8, 0x8c, 0x42, 0x45, 0xd0, 0xfa, 0x3d, 0xb4, 0x8f,
+	0x9c, 0xd8, 0xb9, 0xf0, 0x03, 0x5f, 0xf8, 0x8c, 0xcb, 0x0d, 0x5c, 0xf8, 0x82, 0x8b, 0xc4, 0x0f,
+	0xa7, 0x66, 0x69, 0xb7, 0xb2, 0x57, 0xa5, 0x4b, 0x80, 0xec, 0x42, 0x6b, 0xee, 0x84, 0x9e, 0xcc,
+	0x19, 0x9f, 0x71, 0xb3, 0x8c, 0xfc, 0x3c, 0xd4, 0xed, 0x40, 0xeb, 0x28, 0x0a, 0x65, 0x5e, 0xf9,
+	0xa1, 0xe0, 0xd6, 0x7f, 0x2a, 0x60, 0xe4, 0x33, 0x0d, 0xbd, 0xff, 0x1c, 0x40, 0x24, 0x4e, 0xc8,
+	0xdd, 0xc8, 0x63, 0x89, 0x0e, 0x74, 0x0e, 0x21, 0xcf, 0xa0, 0x23, 0x7c, 0xf7, 0x92, 0x09, 0x3b,
+	0x76, 0x12, 0x67, 0xce, 0xd1, 0xf3, 0x56, 0x6f, 0x0b, 0x0f, 0x7b, 0x8c, 0x9c, 0x21, 0x32, 0x68,
+	0x5b, 0xe4, 0x28, 0x99, 0x92, 0x18, 0x01, 0x1b, 0x33, 0x24, 0x9f, 0x92, 0x59, 0xe4, 0x68, 0x33,
+	0x4e, 0x97, 0xf9, 0x6c, 0xaf, 0x16, 0xb3, 0xfd, 0x29, 0xb4, 0xdd, 0x5c, 0x50, 0xcc, 0xf5, 0x9c,
+	0xfd, 0x7c, 0xb4, 0x68, 0x41, 0xec, 0x46, 0x49, 0xd4, 0x3e, 0x50, 0x12, 0xe4, 0xd7, 0xd0, 0xf5,
+	0x98, 0x60, 0xae, 0xf0, 0xa3, 0xd0, 0x56, 0x8e, 0xc7, 0x2c, 0xb1, 0x39, 0x73, 0xa3, 0xd0, 0x33,
+	0xeb, 0x78, 0x5a, 0xf7, 0x32, 0x89, 0xa1, 0x3e, 0xdb, 0x11, 0xb2, 0xe5, 0x39, 0x09, 0x7f, 0xce,
+	0xb8, 0x70, 0xe6, 0xb1, 0xd9, 0x40, 0xd9, 0x25, 0x90, 0x96, 0x6b, 0x73, 0x59, 0xae, 0x8f, 0xa0,
+	0xae, 0x0b, 0xc9, 0xdc, 0xdd, 0xad, 0xec, 0xb5, 0x7a, 0xad, 0x5c, 0xc1, 0xd1, 0x94, 0x67, 0xfd,
+	0x01, 0x9a, 0x99, 0xaf, 0xb2, 0x77, 0xa8, 0xad, 0xe8, 0xde, 0x81, 0x04, 0xf9, 0x0c, 0x80, 0x33,
+	0xce, 0xa5, 0xd3, 0xbe, 0xa7, 0x6b, 0xa2, 0xa9, 0x91, 0x81, 0x27, 0x0f, 0x97, 0x5d, 0xc5, 0x7e,
+	0xe2, 0x48, 0xa7, 0xf1, 0x10, 0x2a, 0x34, 0x87, 0x58, 0x03, 0xe8, 0x1c, 0xe3, 0x9e, 0xa2, 0xe4,
+	0x28, 0x70, 0x38, 0x27, 0xf7, 0xa1, 0xe1, 0xca, 0x85, 0xd4, 0x26, 0x0d, 0x75, 0x68, 0x1d, 0xe9,
+	0x81, 0x27, 0x4d, 0x29, 0x56, 0xe8, 0xcc, 0x59, 0x6a, 0x0a, 0x91, 0x33, 0x67, 0xce, 0xac, 0x4b,
+	0xe8, 0x8e, 0x5c, 0x16, 0x32, 0xd4, 0xe3, 0x4f, 0x7c, 0xd7, 0x51, 0x81, 0x8a, 0x26, 0x7e, 0xc0,
+	0xc8, 0x17, 0xd0, 0xe2, 0xce, 0x3c, 0x0e, 0x98, 0x9d, 0xc8, 0x7a, 0x52, 0xaa, 0x41, 0x41, 0xd4,
+	0x11, 0x8c, 0x7c, 0x0d, 0xca, 0x90, 0x4e, 0xe4, 0x56, 0x8f, 0x60, 0x48, 0x0a, 0xde, 0xd1, 0x54,
+	0xc4, 0x8a, 0x61, 0x33, 0xe5, 0xa4, 0x16, 0xc6, 0xb0, 0xcd, 0xa5, 0x7d, 0xdb, 0x2d, 0x38, 0x80,
+	0xa6, 0x5a, 0xbd, 0x2f, 0x54, 0x6f, 0x7a, 0xaf, 0x83, 0xa7, 0x6b, 0xf4, 0x0e, 0x5f, 0xe5, 0xbe,
+	0xa8, 0xeb, 0x8e, 0x6d, 0xfd, 0x6f, 0x1d, 0xea, 0x23, 0x36, 0x3d, 0x76, 0x84, 0x23, 0xa3, 0x3a,
+	0x77, 0x42, 0x7f, 0xc2, 0xb8, 0x18, 0x78, 0xfa, 0x3c, 0x72, 0x08, 0x1e, 0x38, 0x7b, 0xab, 0x4b,
+	0x5c, 0x2e, 0xb1, 0x8f, 0x39, 0x7c, 0x86, 0x27, 0xd0, 0xa6, 0xb8, 0x96, 0xfd, 0x25, 0x56, 0xc6,
+	0xd3, 0x94, 0xcf, 0xe8, 0x34, 0x65, 0xd6, 0x97, 0x29, 0xd3, 0x85, 0x86, 0xb7, 0xd0, 0xe7, 0x28,
+	0x93, 0x79, 0x9d, 0x66, 0xf4, 0x4a, 0x85, 0xd4, 0x7f, 0x4e, 0x85, 0x34, 0x3e, 0x54, 0x21, 0x5f,
+	0x81, 0xe1, 0xe9, 0x98, 0xdb, 0x2c, 0x74, 0x2e, 0x02, 0xe6, 0x61, 0x4e, 0x37, 0xe8, 0x66, 0x8a,
+	0xf7, 0x15, 0x4c, 0x1e, 0xc3, 0xb6, 0xeb, 0x04, 0xae, 0xac, 0x20, 0x97, 0xc5, 0x62, 0xe1, 0x04,
+	0x36, 0x6e, 0x1f, 0x50, 0x9c, 0x48, 0xde, 0x30, 0x63, 0x9d, 0xca, 0x60, 0xec, 0x81, 0x21, 0x0b,
+	0xc6, 0x16, 0x91, 0xed, 0x31, 0xc7, 0x0b, 0xfc, 0x90, 0x99, 0x2d, 0x8c, 0xdf, 0x86, 0xc4, 0xc7,
+	0xd1, 0xb1, 0x46, 0xc9, 0x03, 0x68, 0x4f, 0xfc, 0x40, 0xb0, 0xc4, 0x9e, 0x26, 0x4e, 0x3c, 0x33,
+	0xdb, 0x98, 0x88, 0x2d, 0x85, 0x9d, 0x48, 0xe8, 0x23, 0xcb, 0x4b, 0x86, 0x6d, 0xb2, 0x08, 0x82,
+	0x61, 0x7a, 0x08, 0x0f, 0x76, 0x2b, 0x59, 0xd8, 0xde, 0xf8, 0x1e, 0x8b, 0x34, 0x87, 0x16, 0xc4,
+	0xc8, 0x2f, 0xa1, 0x93, 0xa7, 0x7b, 0xa6, 0xf5, 0xbe, 0xef, 0x8a, 0x72, 0x37, 0x3f, 0x3c, 0x30,
+	0xbf, 0xfc, 0xa8, 0x0f, 0x0f, 0xc8, 0x21, 0x6c, 0x65, 0x91, 0xcf, 0x52, 0xe6, 0x21, 0x7e, 0xbc,
+	0x5d, 0xa8, 0x92, 0xf4, 0x7b, 0xc3, 0x2b, 0x02, 0xdc, 0xfa, 0xef, 0x3a, 0xb4, 0xf3, 0x26, 0x64,
+	0x46, 0x62, 0x1d, 0x1b, 0xea, 0x66, 0x95, 0x6b, 0xd9, 0x62, 0xde, 0xf9, 0x9e, 0x98, 0x99, 0x5b,
+	0x98, 0x60, 0x8a, 0x90, 0xb7, 0xeb, 0x8c, 0xf9, 0xd3, 0x99, 0x30, 0x09, 0xc2, 0x9a, 0x92, 0x1d,
+	0xfb, 0xc2, 0x17, 0x58, 0xce, 0x77, 0x90, 0x91, 0x92, 0x32, 0x7b, 0x27, 0x31, 0x37, 0xb7, 0xb1,
+	0xc8, 0xe5, 0x92, 0x3c, 0x86, 0xda, 0x24, 0x4a, 0xe6, 0x8e, 0x30, 0xef, 0xe2, 0x80, 0x61, 0xae,
+	0xec, 0x79, 0xff, 0x7b, 0xe4, 0x53, 0x2d, 0x27, 0xad, 0x4e, 0x62, 0x7e, 0xcc, 0x42, 0x73, 0x07,
+	0xd5, 0x68, 0x8a, 0x1c, 0x40, 0x5d, 0x87, 0xc0, 0xbc, 0x87, 0xaa, 0xee, 0xaf, 0xaa, 0xd2, 0xbf,
+	0x34, 0x95, 0x94, 0x0e, 0x4d, 0xa3, 0xd8, 0x34, 0xd1, 0x4d, 0xb9, 0x24, 0xcf, 0xa0, 0xce, 0x42,
+	0x75, 0xe5, 0xdd, 0x47, 0x35, 0x9f, 0xae, 0xaa, 0x41, 0xe2, 0x28, 0xf2, 0x98, 0x4b, 0x53, 0x61,
+	0x1c, 0x1a, 0xa2, 0x20, 0x4a, 0x8e, 0x59, 0x2c, 0x66, 0x66, 0x17, 0x15, 0xe6, 0x10, 0x72, 0x02,
+	0x6d, 0x77, 0x96, 0x44, 0x73, 0x47, 0x6d, 0xc7, 0xfc, 0x04, 0x95, 0x7f, 0xb9, 0xaa, 0xfc, 0x08,
+	0xa5, 0x46, 0x8b, 0x0b, 0xec, 0x81, 0x7e, 0x38, 0xa5, 0x85, 0x0f, 0xad, 0xcf, 0xa0, 0xa6, 0x56,
+	0x72, 0x38, 0x7a, 0x35, 0xec, 0x9f, 0x8c, 0x47, 0xc6, 0x1a, 0xa9, 0x43, 0xe5, 0xd5, 0xf0, 0x89,
+	0x51, 0xb2, 0xfe, 0x08, 0xf5, 0xf4, 0x24, 0xef, 0xc0, 0x66, 0xff, 0xec, 0xe8, 0xfc, 0xb8, 0x4f,
+	0xed, 0xe3, 0xfe, 0xf7, 0x87, 0xaf, 0x5f, 0xca, 0xc9, 0x6a, 0x0b, 0x3a, 0xa7, 0xbd, 0x67, 0x4f,
+	0xec, 0x17, 0x87, 0xa3, 0xfe, 0xcb, 0xc1, 0x59, 0xdf, 0x28, 0x91, 0x0e, 0x34, 0x11, 0x7a, 0x75,
+	0x38, 0x38, 0x33, 0xca, 0x19, 0x79, 0x3a, 0x38, 0x39, 0x35, 0x2a, 0xe4, 0x3e, 0xdc, 0x45, 0xf2,
+	0xe8, 0xfc, 0x6c, 0x34, 0xa6, 0x87, 0x83, 0xb3, 0xfe, 0xb1, 0x62, 0x55, 0xad, 0x1e, 0xc0, 0x32,
+	0x14, 0xa4, 0x01, 0x55, 0x29, 0x68, 0xac, 0xe9, 0xd5, 0x53, 0xa3, 0x24, 0xdd, 0x7a, 0x33, 0xfc,
+	0xce, 0x28, 0xab, 0xc5, 0x73, 0xa3, 0x62, 0x1d, 0xc1, 0xd6, 0xca, 0x0e, 0xc9, 0x06, 0xc0, 0xd1,
+	0x29, 0x3d, 0x7f, 0x75, 0x68, 0x3f, 0xe9, 0x3d, 0x36, 0xd6, 0x0a, 0x74, 0xcf, 0x28, 0xe5, 0xe9,
+	0x27, 0x4f, 0x8c, 0xb2, 0xf5, 0x16, 0xee, 0x8e, 0xd3, 0x41, 0xc4, 0x1b, 0xb1, 0xe9, 0x9c, 0x85,
+	0x02, 0x1b, 0xb0, 0x01, 0x95, 0x45, 0x12, 0xe8, 0x61, 0x45, 0x2e, 0x71, 0x04, 0xc4, 0x51, 0x4a,
+	0x77, 0x5d, 0x4d, 0x91, 0x7d, 0xb8, 0x73, 0xa3, 0x09, 0xd9, 0xf2, 0x4b, 0x35, 0x27, 0x6e, 0xc5,
+	0x85, 0x26, 0xf4, 0x3a, 0x09, 0xac, 0x7f, 0x97, 0xe0, 0xde, 0x2d, 0xb7, 0x04, 0x5a, 0x7d, 0x05,
+	0x2d, 0x75, 0x01, 0xc6, 0x49, 0x74, 0xc1, 0x71, 0x1e, 0x6b, 0xf5, 0xbe, 0x7e, 0xdf, 0xc5, 0x22,
+	0x3f, 0xd9, 0x47, 0x68, 0x28, 0xc5, 0xfb, 0xa1, 0x48, 0xae, 0x29, 0xb8, 0x19, 0xd0, 0xfd, 0x0d,
+	0x6c, 0xde, 0x60, 0xa7, 0xa3, 0xad, 0xba, 0x1d, 0xe5, 0x72, 0xf9, 0x62, 0x90, 0xdb, 0x2a, 0xe9,
+	0x17, 0xc3, 0xaf, 0xca, 0xdf, 0x95, 0xac, 0x19, 0x80, 0x2a, 0x7b, 0xf4, 0xed, 0x77, 0x3f, 0x79,
+	0xfb, 0x7d, 0xfa, 0x53, 0x4e, 0x7e, 0xf0, 0xea, 0xfb, 0x6b, 0x09, 0x3a, 0xd9, 0x39, 0xa0, 0xb5,
+	0x67, 0xd0, 0xe0, 0xea, 0x38, 0xd2, 0x30, 0x74, 0xd5, 0x38, 0x78, 0xdb, 0x69, 0xd1, 0x4c, 0xf6,
+	0x96, 0x87, 0xcb, 0xb7, 0x00, 0xd9, 0x50, 0xc5, 0xcd, 0x0a, 0xea, 0xda, 0xcc, 0xf5, 0x34, 0x54,
+	0x90, 0x13, 0xb1, 0xfe, 0x5e, 0x82, 0xcd, 0xcc, 0x0c, 0x65, 0x7c, 0x11, 0x88, 0xf4, 0xbe, 0x2d,
+	0x2d, 0xef, 0xdb, 0x1d, 0x58, 0x67, 0x49, 0x12, 0x25, 0x6a, 0x4c, 0x39, 0x5d, 0xa3, 0x8a, 0x24,
+	0x7b, 0x50, 0xf5, 0x1c, 0xe1, 0xe8, 0x71, 0x94, 0x14, 0x9d, 0xd6, 0xc1, 0x40, 0x09, 0xf2, 0x15,
+	0x54, 0x73, 0x4f, 0x9b, 0xbb, 0xea, 0x02, 0xb9, 0x31, 0x3b, 0x53, 0x14, 0x79, 0xd1, 0x80, 0x5a,
+	0x82, 0x8e, 0x58, 0x7f, 0x86, 0x4d, 0xca, 0xa6, 0x3e, 0x17, 0x2c, 0x7b, 0xc5, 0xed, 0x40, 0x8d,
+	0x33, 0x37, 0x61, 0xe9, 0x1b, 0x46, 0x53, 0xf2, 0x3e, 0x97, 0x97, 0xb1, 0xeb, 0x8b, 0x6b, 0x9d,
+	0xb2, 0x19, 0xbd, 0x72, 0x9f, 0x57, 0x3e, 0xea, 0x3e, 0xb7, 0xfe, 0x52, 0x82, 0xce, 0x59, 0x24,
+	0xfc, 0xc9, 0xb5, 0x8e, 0xfe, 0x2d, 0x75, 0xf2, 0x0b, 0xa8, 0x73, 0x35, 0xc5, 0x68, 0xad, 0x6d,
+	0x95, 0x1a, 0x0a, 0xa3, 0x29, 0x53, 0xba, 0x2d, 0x1c, 0x7e, 0x39, 0xf0, 0x30, 0x00, 0x15, 0xaa,
+	0xa9, 0xc2, 0xd0, 0xb2, 0x55, 0x1c, 0x5a, 0x7e, 0xa8, 0x36, 0xca, 0x46, 0xe5, 0x87, 0x6a, 0xe3,
+	0x81, 0x61, 0x59, 0xff, 0x28, 0x43, 0x3b, 0xff, 0x38, 0x90, 0x23, 0x72, 0xc2, 0x5c, 0x3f, 0xf6,
+	0x59, 0x28, 0xf4, 0xc8, 0xb4, 0x04, 0xe4, 0x6c, 0x39, 0x71, 0x5c, 0x66, 0x2f, 0x73, 0xbd, 0x4d,
+	0x9b, 0x12, 0x79, 0x23, 0x01, 0x39, 0x95, 0xbe, 0xf3, 0x43, 0xac, 0x3b, 0x3d, 0x42, 0xd5, 0xdf,
+	0xf9, 0x72, 0x74, 0xbb, 0x90, 0x05, 0x9e, 0xa9, 0xb1, 0x13, 0x27, 0xf4, 0xd4, 0xa4, 0xa1, 0x06,
+	0xaa, 0xad, 0x8c, 0x45, 0x9d, 0xd0, 0xc3, 0x41, 0x83, 0x40, 0x95, 0x33, 0xe6, 0xe9, 0xd1, 0x0a,
+	0xd7, 0x72, 0xb2, 0x59, 0xce, 0xc4, 0xf6, 0x45, 0x10, 0xb9, 0x97, 0x38, 0x63, 0xb5, 0xe9, 0xe6,
+	0x12, 0x7f, 0x21, 0x61, 0x72, 0x0a, 0x5b, 0x39, 0x51, 0xfd, 0x22, 0x52, 0xf3, 0xd6, 0x27, 0xb9,
+	0x17, 0x51, 0x3f, 0x93, 0xd1, 0x6f, 0x23, 0x83, 0xdd, 0x40, 0xac, 0x01, 0x10, 0x25, 0x3b, 0x62,
+	0xa1, 0xc7, 0x12, 0x1d, 0xa6, 0x07, 0xd0, 0xe6, 0x48, 0xdb, 0x61, 0x14, 0xba, 0xe9, 0xa0, 0xdc,
+	0x52, 0xd8, 0x99, 0x84, 0x56, 0x8b, 0xc8, 0xfa, 0x11, 0x76, 0x6e, 0x37, 0x4b, 0x1e, 0xc1, 0x86,
+	0x9b, 0x30, 0xe5, 0x6c, 0x12, 0x2d, 0x42, 0x4f, 0x17, 0x49, 0x27, 0x45, 0xa9, 0x04, 0xc9, 0x73,
+	0xb8, 0x5f, 0x14, 0x53, 0x41, 0x50, 0xa1, 0x54, 0x86, 0x76, 0x0a, 0x5f, 0x60, 0x30, 0x64, 0x3c,
+	0xad, 0x7f, 0x95, 0xa1, 0x3e, 0x74, 0xae, 0x31, 0xdd, 0x56, 0x9e, 0x8a, 0xa5, 0x8f, 0x7b, 0x2a,
+	0x62, 0x8d, 0xc8, 0x0d, 0x6a, 0x5b, 0x9a, 0xba, 0x3d, 0xd8, 0x95, 0x9f, 0x11, 0x6c, 0x32, 0x80,
+	0x6d, 0xed, 0x99, 0x8e, 0xae, 0x56, 0x56, 0xc5, 0x86, 0x73, 0x2f, 0xa7, 0x2c, 0x7f, 0x1a, 0x94,
+	0x88, 0xd5, 0x13, 0x7a, 0x0a, 0x1b, 0xec, 0x2a, 0x66, 0xae, 0x60, 0x9e, 0x7a, 0x27, 0x9a, 0xeb,
+	0xb9, 0xc9, 0x79, 0xf9, 0xb6, 0xed, 0xa4, 0x52, 0x08, 0xf5, 0xae, 0xa0, 0x9d, 0x6f, 0x1f, 0xe4,
+	0x05, 0x6c, 0x9e, 0x30, 0x51, 0x80, 0xcc, 0x95, 0x26, 0xa3, 0x9b, 0x48, 0xf7, 0xf6, 0xf6, 0x43,
+	0x1e, 0x42, 0x55, 0xfe, 0xb5, 0x44, 0xd4, 0x1f, 0x2f, 0xe9, 0xbf, 0x4c, 0xdd, 0x22, 0xd9, 0x3b,
+	0x03, 0x18, 0x2f, 0x9f, 0xf3, 0xbf, 0x05, 0x92, 0xb6, 0xa8, 0x1c, 0xaa, 0xc6, 0xc8, 0x1b, 0xbd,
+	0xab, 0xab, 0xfa, 0x63, 0xa1, 0xa5, 0x3c, 0x2e, 0x5d, 0xd4, 0xf0, 0xcf, 0xad, 0x83, 0xff, 0x0f,
+	0x00, 0xde, 0x87, 0xb2, 0x5a, 0xf0, 0x12, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1,6 +1,7 @@
 package pm
 
 import (
+	"context"
 	"math/big"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -34,35 +35,39 @@ type ReserveInfo struct {
 // including processing deposits and pay outs
 type Broker interface {
 	// FundDepositAndReserve funds a sender's deposit and reserve
-	FundDepositAndReserve(depositAmount, reserveAmount *big.Int) (*types.Transaction, error)
+	FundDepositAndReserve(ctx context.Context, depositAmount, reserveAmount *big.Int) (*types.Transaction, error)
 
 	// FundDeposit funds a sender's deposit
-	FundDeposit(amount *big.Int) (*types.Transaction, error)
+	FundDeposit(ctx context.Context, amount *big.Int) (*types.Transaction, error)
 
 	// FundReserve funds a sender's reserve
-	FundReserve(amount *big.Int) (*types.Transaction, error)
+	FundReserve(ctx context.Context, amount *big.Int) (*types.Transaction, error)
 
 	// Unlock initiates the unlock period for a sender after which a sender can withdraw its
 	// deposit and penalty escrow
-	Unlock() (*types.Transaction, error)
+	Unlock(ctx context.Context) (*types.Transaction, error)
 
 	// CancelUnlock stops a sender's active unlock period
-	CancelUnlock() (*types.Transaction, error)
+	CancelUnlock(ctx context.Context) (*types.Transaction, error)
 
 	// Withdraw credits a sender with its deposit and penalty escrow after the sender
 	// waits through the unlock period
-	Withdraw() (*types.Transaction, error)
+	Withdraw(ctx context.Context) (*types.Transaction, error)
 
 	// RedeemWinningTicket submits a ticket to be validated by the broker and if a valid winning ticket
 	// the broker pays the ticket's face value to the ticket's recipient
-	RedeemWinningTicket(ticket *Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error)
+	RedeemWinningTicket(ctx context.Context, ticket *Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error)
+
+	// BatchRedeemWinningTickets submits several tickets to the broker in a single transaction. Tickets that are not
+	// valid winning tickets are skipped by the broker without failing the others
+	BatchRedeemWinningTickets(ctx context.Context, tickets []*SignedTicket) (*types.Transaction, error)
 
 	// IsUsedTicket checks if a ticket has been used
-	IsUsedTicket(ticket *Ticket) (bool, error)
+	IsUsedTicket(ctx context.Context, ticket *Ticket) (bool, error)
 
 	// CheckTx waits for a transaction to confirm on-chain and returns an error
-	// if the transaction failed
-	CheckTx(tx *types.Transaction) error
+	// if the transaction failed or ctx is done before it confirms
+	CheckTx(ctx context.Context, tx *types.Transaction) error
 }
 
 // TimeManager defines the methods for fetching the last
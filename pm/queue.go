@@ -2,11 +2,11 @@ package pm
 
 import (
 	"math/big"
-	"strings"
 	"sync"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/lperrors"
 )
 
 const ticketValidityPeriod = 2
@@ -19,12 +19,18 @@ type RedeemableEmitter interface {
 	Redeemable() chan *redemption
 }
 
+// redemption is a request to redeem winning tickets. Several tickets are redeemed together in a single transaction
 type redemption struct {
-	SignedTicket *SignedTicket
-	resCh        chan struct {
-		txHash ethcommon.Hash
-		err    error
-	}
+	SignedTickets []*SignedTicket
+	resCh         chan redemptionResult
+}
+
+// redemptionResult is the result of a redemption
+type redemptionResult struct {
+	txHash ethcommon.Hash
+	err    error
+	// ticketErrs are the errors of the tickets that were left out of the transaction of a batch, by ticket index
+	ticketErrs map[int]error
 }
 
 // ticketQueue is a queue of winning tickets that are in line for redemption on-chain.
@@ -43,6 +49,7 @@ type ticketQueue struct {
 
 	sender ethcommon.Address
 	store  TicketStore
+	cfg    *LocalSenderMonitorConfig
 
 	quit chan struct{}
 
@@ -54,6 +61,7 @@ func newTicketQueue(sender ethcommon.Address, sm *LocalSenderMonitor) *ticketQue
 		tm:         sm.tm,
 		redeemable: make(chan *redemption),
 		store:      sm.ticketStore,
+		cfg:        sm.cfg,
 		sender:     sender,
 		quit:       make(chan struct{}),
 	}
@@ -121,51 +129,91 @@ func (q *ticketQueue) handleBlockEvent(latestL1Block *big.Int) {
 		glog.Errorf("Error getting queue length err=%q", err)
 		return
 	}
-	for i := 0; i < int(numTickets); i++ {
-		nextTicket, err := q.store.SelectEarliestWinningTicket(q.sender, new(big.Int).Sub(q.tm.LastInitializedRound(), big.NewInt(ticketValidityPeriod)).Int64())
-		if err != nil {
-			glog.Errorf("Unable to select earliest winning ticket err=%q", err)
-			return
-		}
-		if nextTicket == nil {
-			return
+	if numTickets == 0 {
+		return
+	}
+	minCreationRound := new(big.Int).Sub(q.tm.LastInitializedRound(), big.NewInt(ticketValidityPeriod)).Int64()
+	tickets, err := q.store.SelectEarliestWinningTickets(q.sender, minCreationRound, numTickets)
+	if err != nil {
+		glog.Errorf("Unable to select earliest winning tickets err=%q", err)
+		return
+	}
+
+	// All tickets that are redeemable in this block, e.g. the backlog of tickets held while the gas price was too
+	// high, are redeemed together in a single transaction
+	gasTooHigh := q.isRedeemGasPriceTooHigh()
+	var redeemable []*SignedTicket
+	for _, ticket := range tickets {
+		// Tickets are selected oldest first, so once a ticket is not in its last valid round neither are the rest
+		if gasTooHigh && ticket.CreationRound > minCreationRound {
+			glog.V(5).Infof("Holding winning tickets until the gas price drops sender=%v", q.sender.Hex())
+			break
 		}
-		if !q.isRecipientActive(nextTicket.Recipient) {
-			glog.V(5).Infof("Ticket recipient is not active in this round, cannot redeem ticket recipient=%v", nextTicket.Recipient.Hex())
+		if !q.isRecipientActive(ticket.Recipient) {
+			glog.V(5).Infof("Ticket recipient is not active in this round, cannot redeem ticket recipient=%v", ticket.Recipient.Hex())
 			continue
 		}
-		if nextTicket.ParamsExpirationBlock.Cmp(latestL1Block) <= 0 {
-			resCh := make(chan struct {
-				txHash ethcommon.Hash
-				err    error
-			})
-
-			q.redeemable <- &redemption{nextTicket, resCh}
-			select {
-			case res := <-resCh:
-				// after receiving the response we can close the channel so it can be GC'd
-				close(resCh)
-				if res.err != nil {
-					glog.Errorf("Error redeeming err=%q", res.err)
-					// If the error is non-retryable then we mark the ticket as redeemed
-					if !isNonRetryableTicketErr(res.err) {
-						continue
-					}
-				}
-				if err := q.store.MarkWinningTicketRedeemed(nextTicket, res.txHash); err != nil {
-					glog.Error(err)
+		if ticket.ParamsExpirationBlock.Cmp(latestL1Block) <= 0 {
+			redeemable = append(redeemable, ticket)
+		}
+	}
+	if len(redeemable) == 0 {
+		return
+	}
+
+	resCh := make(chan redemptionResult)
+	select {
+	case q.redeemable <- &redemption{redeemable, resCh}:
+	case <-q.quit:
+		return
+	}
+
+	select {
+	case res := <-resCh:
+		// after receiving the response we can close the channel so it can be GC'd
+		close(resCh)
+		for i, ticket := range redeemable {
+			txHash, err := res.txHash, res.err
+			if ticketErr, ok := res.ticketErrs[i]; ok {
+				txHash, err = ethcommon.Hash{}, ticketErr
+			}
+			if err != nil {
+				glog.Errorf("Error redeeming err=%q", err)
+				// If the error is non-retryable then we mark the ticket as redeemed
+				if !isNonRetryableTicketErr(err) {
 					continue
 				}
-			case <-q.quit:
-				return
+			}
+			if err := q.store.MarkWinningTicketRedeemed(ticket, txHash); err != nil {
+				glog.Error(err)
 			}
 		}
+	case <-q.quit:
+	}
+}
+
+// isRedeemGasPriceTooHigh returns whether the current gas price exceeds the max gas price for ticket redemptions
+func (q *ticketQueue) isRedeemGasPriceTooHigh() bool {
+	if q.cfg == nil || q.cfg.MaxRedeemGasPrice == nil {
+		return false
+	}
+
+	// The gas price polled by the gas price monitor is used to avoid a request for every block
+	var gasPrice *big.Int
+	if q.cfg.GasPrice != nil {
+		gasPrice = q.cfg.GasPrice()
+	}
+	if gasPrice == nil {
+		// If the gas price is unavailable, try to redeem the tickets which fails later if it is still unavailable
+		glog.Errorf("Unable to get gas price to check the max redeem gas price")
+		return false
 	}
+	return gasPrice.Cmp(q.cfg.MaxRedeemGasPrice) > 0
 }
 
 func isNonRetryableTicketErr(err error) bool {
-	// The latter check depends on logic in eth.client.CheckTx()
-	return err == errIsUsedTicket || strings.Contains(err.Error(), "transaction failed")
+	// User errors (i.e. a locked account) are not considered because the redemption can succeed once they are addressed
+	return err == errIsUsedTicket || (lperrors.IsFatal(err) && !lperrors.IsUser(err))
 }
 
 func (q *ticketQueue) isRecipientActive(addr ethcommon.Address) bool {
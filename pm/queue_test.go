@@ -8,6 +8,7 @@ import (
 	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/lperrors"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -32,34 +33,41 @@ func defaultSignedTicket(sender ethcommon.Address, senderNonce uint32) *SignedTi
 }
 
 type queueConsumer struct {
-	redeemable    []*redemption
+	redeemable    []*SignedTicket
+	redemptions   int
 	mu            sync.Mutex
 	redemptionErr error
 }
 
 // Redeemable returns the consumed redeemable tickets from a ticket queue
-func (qc *queueConsumer) Redeemable() []*redemption {
+func (qc *queueConsumer) Redeemable() []*SignedTicket {
 	qc.mu.Lock()
 	defer qc.mu.Unlock()
 
 	return qc.redeemable
 }
 
+// Redemptions returns the number of redemptions consumed from a ticket queue
+func (qc *queueConsumer) Redemptions() int {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	return qc.redemptions
+}
+
 // Wait receives on the output channel from a ticket queue
 // until it has received a certain number of tickets and then exits
 func (qc *queueConsumer) Wait(num int, e RedeemableEmitter, done chan struct{}) {
 	count := 0
 	for count < num {
 		select {
-		case ticket := <-e.Redeemable():
-			count++
+		case red := <-e.Redeemable():
+			count += len(red.SignedTickets)
 			qc.mu.Lock()
-			qc.redeemable = append(qc.redeemable, ticket)
+			qc.redeemable = append(qc.redeemable, red.SignedTickets...)
+			qc.redemptions++
 			qc.mu.Unlock()
-			ticket.resCh <- struct {
-				txHash ethcommon.Hash
-				err    error
-			}{ticket.SignedTicket.Hash(), qc.redemptionErr}
+			red.resCh <- redemptionResult{txHash: red.SignedTickets[0].Hash(), err: qc.redemptionErr}
 		}
 	}
 	done <- struct{}{}
@@ -121,10 +129,12 @@ func TestTicketQueueLoop(t *testing.T) {
 	// synchronously with sender nonces 0..9 the array
 	// of popped tickets should have sender nonces 0..9
 	// in order
+	// The tickets that are redeemable in the same block are redeemed together
+	assert.Equal(1, qc.Redemptions())
 	redeemable := qc.Redeemable()
 	for i := 0; i < numTickets; i++ {
-		assert.Equal(uint32(i), redeemable[i].SignedTicket.SenderNonce)
-		assert.True(ts.submitted[fmt.Sprintf("%x", redeemable[i].SignedTicket.Sig)])
+		assert.Equal(uint32(i), redeemable[i].SenderNonce)
+		assert.True(ts.submitted[fmt.Sprintf("%x", redeemable[i].Sig)])
 	}
 }
 
@@ -174,7 +184,7 @@ func TestTicketQueueLoop_IsNonRetryableTicketErr_MarkAsRedeemed(t *testing.T) {
 	addTicket(ticket)
 
 	qc = &queueConsumer{
-		redemptionErr: errors.New("transaction failed txHash=abc"),
+		redemptionErr: lperrors.Fatal(errors.New("transaction failed txHash=abc")),
 	}
 	consumeQueue(qc)
 	assert.True(ts.submitted[fmt.Sprintf("%x", ticket.Sig)])
@@ -190,6 +200,81 @@ func TestTicketQueueLoop_IsNonRetryableTicketErr_MarkAsRedeemed(t *testing.T) {
 	assert.False(ts.submitted[fmt.Sprintf("%x", ticket.Sig)])
 }
 
+func TestTicketQueue_MaxRedeemGasPrice(t *testing.T) {
+	assert := assert.New(t)
+
+	sender := RandAddress()
+	ts := newStubTicketStore()
+	tm := &stubTimeManager{round: big.NewInt(100)}
+	gasPrice := big.NewInt(20)
+	cfg := stubLocalSenderMonitorCfg()
+	cfg.MaxRedeemGasPrice = big.NewInt(10)
+	cfg.GasPrice = func() *big.Int { return gasPrice }
+	sm := &LocalSenderMonitor{
+		cfg:         cfg,
+		ticketStore: ts,
+		tm:          tm,
+	}
+
+	q := newTicketQueue(sender, sm)
+
+	// The ticket in its last valid round is redeemed although the gas price is too high
+	expiring := defaultSignedTicket(sender, 0)
+	expiring.CreationRound = 100 - ticketValidityPeriod
+	q.Add(expiring)
+	held := []*SignedTicket{defaultSignedTicket(sender, 1), defaultSignedTicket(sender, 2)}
+	for _, ticket := range held {
+		q.Add(ticket)
+	}
+
+	handleBlockEvent := func(numRedeemed int) *queueConsumer {
+		qc := &queueConsumer{}
+		done := make(chan struct{})
+		go qc.Wait(numRedeemed, q, done)
+		q.handleBlockEvent(big.NewInt(1))
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for redemptions")
+		}
+		return qc
+	}
+
+	qc := handleBlockEvent(1)
+	assert.Len(qc.Redeemable(), 1)
+	assert.True(ts.submitted[fmt.Sprintf("%x", expiring.Sig)])
+	qlen, err := q.Length()
+	assert.Nil(err)
+	assert.Equal(2, qlen)
+
+	// The held tickets are redeemed together once the gas price drops
+	gasPrice = big.NewInt(10)
+	qc = handleBlockEvent(2)
+	assert.Len(qc.Redeemable(), 2)
+	assert.Equal(1, qc.Redemptions())
+	for _, ticket := range held {
+		assert.True(ts.submitted[fmt.Sprintf("%x", ticket.Sig)])
+	}
+
+	// Tickets are redeemed if the gas price is unavailable
+	ticket := defaultSignedTicket(sender, 3)
+	q.Add(ticket)
+	cfg.GasPrice = func() *big.Int { return nil }
+	handleBlockEvent(1)
+	assert.True(ts.submitted[fmt.Sprintf("%x", ticket.Sig)])
+}
+
+func TestIsNonRetryableTicketErr(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(isNonRetryableTicketErr(errIsUsedTicket))
+	assert.True(isNonRetryableTicketErr(lperrors.Fatal(errors.New("transaction failed txHash=abc"))))
+	assert.True(isNonRetryableTicketErr(fmt.Errorf("redemption failed: %w", lperrors.Fatal(errors.New("transaction failed txHash=abc")))))
+	assert.False(isNonRetryableTicketErr(lperrors.Retryable(errors.New("transaction timed out txHash=abc"))))
+	assert.False(isNonRetryableTicketErr(lperrors.User(errors.New("account locked"))))
+	assert.False(isNonRetryableTicketErr(errors.New("some other error")))
+}
+
 func TestTicketQueueLoopConcurrent(t *testing.T) {
 	assert := assert.New(t)
 
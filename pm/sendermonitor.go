@@ -72,6 +72,11 @@ type LocalSenderMonitorConfig struct {
 	RedeemGas       int
 	SuggestGasPrice func(context.Context) (*big.Int, error)
 	RPCTimeout      time.Duration
+	// GasPrice returns the latest gas price polled by the gas price monitor, which is compared with MaxRedeemGasPrice
+	GasPrice func() *big.Int
+	// Gas price above which winning tickets are held in the queue instead of being redeemed, unless they are about to
+	// expire. Held tickets are redeemed together once the gas price drops. If nil, tickets are redeemed right away
+	MaxRedeemGasPrice *big.Int
 }
 
 type LocalSenderMonitor struct {
@@ -285,16 +290,26 @@ func (sm *LocalSenderMonitor) cache(addr ethcommon.Address) {
 // that receives redeemable tickets from a ticketQueue and feeds them into
 // a single output channel in a fan-in manner
 func (sm *LocalSenderMonitor) startTicketQueueConsumerLoop(queue *ticketQueue, done chan struct{}) {
+	// Pending redemptions are abandoned when the monitor exits
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-sm.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	for {
 		select {
 		case red := <-queue.Redeemable():
-			tx, err := sm.redeemWinningTicket(red.SignedTicket)
-			res := struct {
-				txHash ethcommon.Hash
-				err    error
-			}{
-				ethcommon.Hash{},
-				err,
+			var tx *types.Transaction
+			res := redemptionResult{}
+			if len(red.SignedTickets) == 1 {
+				tx, res.err = sm.redeemWinningTicket(ctx, red.SignedTickets[0])
+			} else {
+				tx, res.ticketErrs, res.err = sm.batchRedeemWinningTickets(ctx, red.SignedTickets)
 			}
 			// FIXME: If there are replacement txs then tx.Hash() could be different
 			// from the hash of the replacement tx that was mined
@@ -352,14 +367,14 @@ func (sm *LocalSenderMonitor) cleanup() {
 }
 
 // Returns a non-nil tx if one is sent. Otherwise, returns a nil tx
-func (sm *LocalSenderMonitor) redeemWinningTicket(ticket *SignedTicket) (*types.Transaction, error) {
+func (sm *LocalSenderMonitor) redeemWinningTicket(ctx context.Context, ticket *SignedTicket) (*types.Transaction, error) {
 	availableFunds, err := sm.availableFunds(ticket.Sender)
 	if err != nil {
 		return nil, err
 	}
 
 	// Fail early if ticket is used
-	used, err := sm.broker.IsUsedTicket(ticket.Ticket)
+	used, err := sm.broker.IsUsedTicket(ctx, ticket.Ticket)
 	if err != nil {
 		if monitor.Enabled {
 			monitor.TicketRedemptionError(ticket.Sender.Hex())
@@ -373,8 +388,8 @@ func (sm *LocalSenderMonitor) redeemWinningTicket(ticket *SignedTicket) (*types.
 		return nil, errIsUsedTicket
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), sm.cfg.RPCTimeout)
-	gasPrice, err := sm.cfg.SuggestGasPrice(ctx)
+	gasCtx, cancel := context.WithTimeout(ctx, sm.cfg.RPCTimeout)
+	gasPrice, err := sm.cfg.SuggestGasPrice(gasCtx)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -413,7 +428,7 @@ func (sm *LocalSenderMonitor) redeemWinningTicket(ticket *SignedTicket) (*types.
 
 	// Assume that that this call will return immediately if there
 	// is an error in transaction submission
-	tx, err := sm.broker.RedeemWinningTicket(ticket.Ticket, ticket.Sig, ticket.RecipientRand)
+	tx, err := sm.broker.RedeemWinningTicket(ctx, ticket.Ticket, ticket.Sig, ticket.RecipientRand)
 	if err != nil {
 		if monitor.Enabled {
 			monitor.TicketRedemptionError(ticket.Sender.Hex())
@@ -422,7 +437,7 @@ func (sm *LocalSenderMonitor) redeemWinningTicket(ticket *SignedTicket) (*types.
 	}
 
 	// Wait for transaction to confirm
-	if err := sm.broker.CheckTx(tx); err != nil {
+	if err := sm.broker.CheckTx(ctx, tx); err != nil {
 		if monitor.Enabled {
 			monitor.TicketRedemptionError(ticket.Sender.Hex())
 		}
@@ -439,6 +454,89 @@ func (sm *LocalSenderMonitor) redeemWinningTicket(ticket *SignedTicket) (*types.
 	return tx, nil
 }
 
+// batchRedeemWinningTickets redeems tickets in a single transaction. Tickets that are used or whose face value doesn't
+// cover their share of the transaction cost are left out of the transaction, with their errors returned by ticket
+// index. Returns a non-nil tx if one is sent. Otherwise, returns a nil tx
+func (sm *LocalSenderMonitor) batchRedeemWinningTickets(ctx context.Context, tickets []*SignedTicket) (*types.Transaction, map[int]error, error) {
+	sender := tickets[0].Sender
+	availableFunds, err := sm.availableFunds(sender)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gasCtx, cancel := context.WithTimeout(ctx, sm.cfg.RPCTimeout)
+	gasPrice, err := sm.cfg.SuggestGasPrice(gasCtx)
+	cancel()
+	if err != nil {
+		return nil, nil, err
+	}
+	// Each ticket must cover the cost of its own redemption
+	ticketTxCost := new(big.Int).Mul(big.NewInt(int64(sm.cfg.RedeemGas)), gasPrice)
+
+	ticketErrs := make(map[int]error)
+	var batch []*SignedTicket
+	faceValue := big.NewInt(0)
+	for i, ticket := range tickets {
+		used, err := sm.broker.IsUsedTicket(ctx, ticket.Ticket)
+		if err == nil && used {
+			err = errIsUsedTicket
+		}
+		if err == nil && ticket.FaceValue.Cmp(ticketTxCost) <= 0 {
+			err = errors.New("insufficient ticket face value for redeem tx cost")
+		}
+		if err != nil {
+			if monitor.Enabled {
+				monitor.TicketRedemptionError(sender.Hex())
+			}
+			ticketErrs[i] = err
+			continue
+		}
+		batch = append(batch, ticket)
+		faceValue.Add(faceValue, ticket.FaceValue)
+	}
+	if len(batch) == 0 {
+		return nil, ticketErrs, nil
+	}
+
+	// We only submit a redemption if availableFunds covers the redemption tx cost
+	// Otherwise, we return an error so we can try the redemption later
+	txCost := new(big.Int).Mul(ticketTxCost, big.NewInt(int64(len(batch))))
+	if availableFunds.Cmp(txCost) <= 0 {
+		return nil, ticketErrs, errors.New("insufficient sender funds for redeem tx cost")
+	}
+
+	// The face values are considered pending until the redemption transaction confirms on-chain
+	sm.subFloat(sender, faceValue)
+	defer func() {
+		if err := sm.addFloat(sender, faceValue); err != nil {
+			glog.Error(err)
+		}
+	}()
+
+	tx, err := sm.broker.BatchRedeemWinningTickets(ctx, batch)
+	if err != nil {
+		if monitor.Enabled {
+			monitor.TicketRedemptionError(sender.Hex())
+		}
+		return nil, ticketErrs, err
+	}
+
+	// Wait for transaction to confirm
+	if err := sm.broker.CheckTx(ctx, tx); err != nil {
+		if monitor.Enabled {
+			monitor.TicketRedemptionError(sender.Hex())
+		}
+		// Return tx so caller can utilize the tx if it fails
+		return tx, ticketErrs, err
+	}
+
+	if monitor.Enabled {
+		monitor.ValueRedeemed(sender.Hex(), faceValue)
+	}
+
+	return tx, ticketErrs, nil
+}
+
 // SubscribeMaxFloatChange notifies subcribers when the max float for a sender has changed
 // and that it should call LocalSenderMonitor.MaxFloat() to get the latest value
 func (sm *LocalSenderMonitor) SubscribeMaxFloatChange(sender ethcommon.Address, sink chan<- struct{}) event.Subscription {
@@ -273,7 +273,7 @@ func TestQueueTicketAndSignalNewBlock(t *testing.T) {
 	assert.Equal(qlen, 0)
 
 	// check that ticket is used
-	assert.True(b.IsUsedTicket(signedT.Ticket))
+	assert.True(b.IsUsedTicket(context.Background(), signedT.Ticket))
 
 	// Test queue tickets from multiple senders
 
@@ -306,8 +306,8 @@ func TestQueueTicketAndSignalNewBlock(t *testing.T) {
 	tm.blockNumSink <- big.NewInt(5)
 	time.Sleep(20 * time.Millisecond)
 
-	assert.True(b.IsUsedTicket(signedT2.Ticket))
-	assert.True(b.IsUsedTicket(signedT3.Ticket))
+	assert.True(b.IsUsedTicket(context.Background(), signedT2.Ticket))
+	assert.True(b.IsUsedTicket(context.Background(), signedT3.Ticket))
 }
 
 func TestCleanup(t *testing.T) {
@@ -629,23 +629,23 @@ func TestRedeemWinningTicket_IsUsedTicket(t *testing.T) {
 
 	// test error
 	b.isUsedErr = errors.New("isUsed error")
-	tx, err := sm.redeemWinningTicket(signedT)
+	tx, err := sm.redeemWinningTicket(context.Background(), signedT)
 	assert.Nil(tx)
 	assert.EqualError(err, b.isUsedErr.Error())
 
 	// test used
 	b.isUsedErr = nil
 	b.usedTickets[signedT.Hash()] = true
-	tx, err = sm.redeemWinningTicket(signedT)
+	tx, err = sm.redeemWinningTicket(context.Background(), signedT)
 	assert.Nil(tx)
 	assert.EqualError(err, errIsUsedTicket.Error())
 
 	// test not used
 	b.usedTickets[signedT.Hash()] = false
-	tx, err = sm.redeemWinningTicket(signedT)
+	tx, err = sm.redeemWinningTicket(context.Background(), signedT)
 	assert.Nil(err)
 	assert.NotNil(tx)
-	assert.True(b.IsUsedTicket(signedT.Ticket))
+	assert.True(b.IsUsedTicket(context.Background(), signedT.Ticket))
 }
 
 func TestRedeemWinningTicket_CheckAvailableFundsAndFaceValue(t *testing.T) {
@@ -670,7 +670,7 @@ func TestRedeemWinningTicket_CheckAvailableFundsAndFaceValue(t *testing.T) {
 
 	// Trigger availableFunds() error
 	smgr.err = errors.New("GetSenderInfo() error")
-	_, err := sm.redeemWinningTicket(signedT)
+	_, err := sm.redeemWinningTicket(context.Background(), signedT)
 	assert.EqualError(err, smgr.err.Error())
 
 	smgr.err = nil
@@ -679,7 +679,7 @@ func TestRedeemWinningTicket_CheckAvailableFundsAndFaceValue(t *testing.T) {
 	gasPriceErr := errors.New("SuggestGasPrice() error")
 	cfg.SuggestGasPrice = func(ctx context.Context) (*big.Int, error) { return nil, gasPriceErr }
 	sm = NewSenderMonitor(cfg, b, smgr, tm, ts)
-	_, err = sm.redeemWinningTicket(signedT)
+	_, err = sm.redeemWinningTicket(context.Background(), signedT)
 	assert.EqualError(err, gasPriceErr.Error())
 
 	// Trigger SuggestGasPrice() timeout
@@ -695,14 +695,14 @@ func TestRedeemWinningTicket_CheckAvailableFundsAndFaceValue(t *testing.T) {
 		return nil, errors.New("incorrect timeout error")
 	}
 	sm = NewSenderMonitor(cfg, b, smgr, tm, ts)
-	_, err = sm.redeemWinningTicket(signedT)
+	_, err = sm.redeemWinningTicket(context.Background(), signedT)
 	assert.EqualError(err, timeoutErr.Error())
 
 	// Trigger insufficient funds to cover redeem tx cost error when availableFunds < txCost
 	cfg.RedeemGas = 1
 	cfg.SuggestGasPrice = func(ctx context.Context) (*big.Int, error) { return big.NewInt(1000000000), nil }
 	sm = NewSenderMonitor(cfg, b, smgr, tm, ts)
-	_, err = sm.redeemWinningTicket(signedT)
+	_, err = sm.redeemWinningTicket(context.Background(), signedT)
 	assert.Contains(err.Error(), "insufficient sender funds")
 
 	// Trigger insufficient funds to cover redeem tx cost error when availableFunds = txCost
@@ -711,7 +711,7 @@ func TestRedeemWinningTicket_CheckAvailableFundsAndFaceValue(t *testing.T) {
 	cfg.RedeemGas = 1
 	cfg.SuggestGasPrice = func(ctx context.Context) (*big.Int, error) { return funds, nil }
 	sm = NewSenderMonitor(cfg, b, smgr, tm, ts)
-	_, err = sm.redeemWinningTicket(signedT)
+	_, err = sm.redeemWinningTicket(context.Background(), signedT)
 	assert.Contains(err.Error(), "insufficient sender funds")
 
 	// Trigger insufficient face value to cover redeem tx cost error when face value < txCost
@@ -721,20 +721,20 @@ func TestRedeemWinningTicket_CheckAvailableFundsAndFaceValue(t *testing.T) {
 	badSignedT := defaultSignedTicket(addr, uint32(0))
 	badSignedT.FaceValue = new(big.Int).Sub(txCost, big.NewInt(1))
 	sm = NewSenderMonitor(cfg, b, smgr, tm, ts)
-	_, err = sm.redeemWinningTicket(signedT)
+	_, err = sm.redeemWinningTicket(context.Background(), signedT)
 	assert.Contains(err.Error(), "insufficient ticket face value")
 
 	// Trigger insufficient face value to cover redeem tx cost error when face value = txCost
 	badSignedT.FaceValue = txCost
 	sm = NewSenderMonitor(cfg, b, smgr, tm, ts)
-	_, err = sm.redeemWinningTicket(signedT)
+	_, err = sm.redeemWinningTicket(context.Background(), signedT)
 	assert.Contains(err.Error(), "insufficient ticket face value")
 
 	// Pass available funds and face value check when availableFunds > txCost and face value > txCost
 	cfg.RedeemGas = 0
 	cfg.SuggestGasPrice = func(ctx context.Context) (*big.Int, error) { return big.NewInt(0), nil }
 	sm = NewSenderMonitor(cfg, b, smgr, tm, ts)
-	tx, err := sm.redeemWinningTicket(signedT)
+	tx, err := sm.redeemWinningTicket(context.Background(), signedT)
 	assert.Nil(err)
 	assert.NotNil(tx)
 }
@@ -762,10 +762,10 @@ func TestRedeemWinningTicket_SingleTicket_RedeemError(t *testing.T) {
 	signedT := defaultSignedTicket(addr, uint32(0))
 
 	b.redeemShouldFail = true
-	tx, err := sm.redeemWinningTicket(signedT)
+	tx, err := sm.redeemWinningTicket(context.Background(), signedT)
 	assert.EqualError(err, "stub broker redeem error")
 	assert.Nil(tx)
-	used, err := b.IsUsedTicket(signedT.Ticket)
+	used, err := b.IsUsedTicket(context.Background(), signedT.Ticket)
 	assert.NoError(err)
 	assert.False(used)
 }
@@ -793,7 +793,7 @@ func TestRedeemWinningTicket_SingleTicket_CheckTxError(t *testing.T) {
 
 	signedT := defaultSignedTicket(addr, uint32(0))
 
-	tx, err := sm.redeemWinningTicket(signedT)
+	tx, err := sm.redeemWinningTicket(context.Background(), signedT)
 	assert.NotNil(tx)
 	assert.Equal(expErr, err)
 }
@@ -819,15 +819,78 @@ func TestRedeemWinningTicket_SingleTicket(t *testing.T) {
 
 	signedT := defaultSignedTicket(addr, uint32(0))
 
-	tx, err := sm.redeemWinningTicket(signedT)
+	tx, err := sm.redeemWinningTicket(context.Background(), signedT)
 	assert.Nil(err)
 	assert.NotNil(tx)
 
-	ok, err := b.IsUsedTicket(signedT.Ticket)
+	ok, err := b.IsUsedTicket(context.Background(), signedT.Ticket)
 	assert.Nil(err)
 	assert.True(ok)
 }
 
+func TestBatchRedeemWinningTickets(t *testing.T) {
+	cfg, b, smgr, tm := localSenderMonitorFixture()
+	addr := RandAddress()
+	smgr.info[addr] = &SenderInfo{
+		Deposit:       big.NewInt(500),
+		WithdrawRound: big.NewInt(0),
+		Reserve: &ReserveInfo{
+			FundsRemaining:        big.NewInt(1000),
+			ClaimedInCurrentRound: big.NewInt(0),
+		},
+	}
+	smgr.claimedReserve[addr] = big.NewInt(0)
+
+	ts := newStubTicketStore()
+	cfg.RedeemGas = 1
+	cfg.SuggestGasPrice = func(ctx context.Context) (*big.Int, error) { return big.NewInt(10), nil }
+	sm := NewSenderMonitor(cfg, b, smgr, tm, ts)
+	assert := assert.New(t)
+
+	used := defaultSignedTicket(addr, 0)
+	b.usedTickets[used.Hash()] = true
+	lowFaceValue := defaultSignedTicket(addr, 1)
+	lowFaceValue.FaceValue = big.NewInt(10)
+	tickets := []*SignedTicket{used, lowFaceValue, defaultSignedTicket(addr, 2), defaultSignedTicket(addr, 3)}
+
+	// The used ticket and the ticket that doesn't cover its tx cost are left out of the transaction
+	tx, ticketErrs, err := sm.batchRedeemWinningTickets(context.Background(), tickets)
+	assert.Nil(err)
+	assert.NotNil(tx)
+	assert.Equal(1, b.batches)
+	assert.Len(ticketErrs, 2)
+	assert.Equal(errIsUsedTicket, ticketErrs[0])
+	assert.EqualError(ticketErrs[1], "insufficient ticket face value for redeem tx cost")
+	for _, ticket := range tickets[2:] {
+		assert.True(b.IsUsedTicket(context.Background(), ticket.Ticket))
+	}
+	assert.False(b.IsUsedTicket(context.Background(), lowFaceValue.Ticket))
+
+	// No transaction if no ticket can be redeemed
+	tx, ticketErrs, err = sm.batchRedeemWinningTickets(context.Background(), tickets[2:])
+	assert.Nil(err)
+	assert.Nil(tx)
+	assert.Len(ticketErrs, 2)
+	assert.Equal(1, b.batches)
+
+	// The sender funds must cover the tx cost of all tickets in the batch
+	funds, err := sm.availableFunds(addr)
+	require.Nil(t, err)
+	cfg.SuggestGasPrice = func(ctx context.Context) (*big.Int, error) { return new(big.Int).Div(funds, big.NewInt(2)), nil }
+	highFaceValue := []*SignedTicket{defaultSignedTicket(addr, 4), defaultSignedTicket(addr, 5)}
+	for _, ticket := range highFaceValue {
+		ticket.FaceValue = funds
+	}
+	_, _, err = sm.batchRedeemWinningTickets(context.Background(), highFaceValue)
+	assert.EqualError(err, "insufficient sender funds for redeem tx cost")
+
+	// Redemption errors fail the batch
+	cfg.SuggestGasPrice = func(ctx context.Context) (*big.Int, error) { return big.NewInt(0), nil }
+	b.redeemShouldFail = true
+	_, _, err = sm.batchRedeemWinningTickets(context.Background(), highFaceValue)
+	assert.EqualError(err, "stub broker redeem error")
+}
+
 func TestRedeemWinningTicket_addFloatError(t *testing.T) {
 	cfg, b, smgr, tm := localSenderMonitorFixture()
 	addr := RandAddress()
@@ -853,7 +916,7 @@ func TestRedeemWinningTicket_addFloatError(t *testing.T) {
 	sm.senders[addr].pendingAmount = big.NewInt(-100)
 
 	errLogsBefore := glog.Stats.Error.Lines()
-	tx, err := sm.redeemWinningTicket(signedT)
+	tx, err := sm.redeemWinningTicket(context.Background(), signedT)
 	assert.NotNil(tx)
 	errLogsAfter := glog.Stats.Error.Lines()
 	assert.Nil(err)
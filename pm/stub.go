@@ -1,6 +1,7 @@
 package pm
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"sync"
@@ -72,6 +73,24 @@ func (ts *stubTicketStore) SelectEarliestWinningTicket(sender ethcommon.Address,
 	return nil, nil
 }
 
+func (ts *stubTicketStore) SelectEarliestWinningTickets(sender ethcommon.Address, minCreationRound int64, limit int) ([]*SignedTicket, error) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	if ts.loadShouldFail {
+		return nil, fmt.Errorf("stub TicketStore load error")
+	}
+	var tickets []*SignedTicket
+	for _, t := range ts.tickets[sender] {
+		if len(tickets) == limit {
+			break
+		}
+		if !ts.submitted[fmt.Sprintf("%x", t.Sig)] {
+			tickets = append(tickets, t)
+		}
+	}
+	return tickets, nil
+}
+
 func (ts *stubTicketStore) MarkWinningTicketRedeemed(ticket *SignedTicket, txHash ethcommon.Hash) error {
 	ts.lock.Lock()
 	defer ts.lock.Unlock()
@@ -147,6 +166,9 @@ type stubBroker struct {
 
 	checkTxErr error
 	isUsedErr  error
+
+	// batches is the number of batch redemption transactions
+	batches int
 }
 
 func newStubBroker() *stubBroker {
@@ -156,31 +178,31 @@ func newStubBroker() *stubBroker {
 	}
 }
 
-func (b *stubBroker) FundDepositAndReserve(depositAmount, reserveAmount *big.Int) (*types.Transaction, error) {
+func (b *stubBroker) FundDepositAndReserve(ctx context.Context, depositAmount, reserveAmount *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
 
-func (b *stubBroker) FundDeposit(amount *big.Int) (*types.Transaction, error) {
+func (b *stubBroker) FundDeposit(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
 
-func (b *stubBroker) FundReserve(amount *big.Int) (*types.Transaction, error) {
+func (b *stubBroker) FundReserve(ctx context.Context, amount *big.Int) (*types.Transaction, error) {
 	return nil, nil
 }
 
-func (b *stubBroker) Unlock() (*types.Transaction, error) {
+func (b *stubBroker) Unlock(ctx context.Context) (*types.Transaction, error) {
 	return nil, nil
 }
 
-func (b *stubBroker) CancelUnlock() (*types.Transaction, error) {
+func (b *stubBroker) CancelUnlock(ctx context.Context) (*types.Transaction, error) {
 	return nil, nil
 }
 
-func (b *stubBroker) Withdraw() (*types.Transaction, error) {
+func (b *stubBroker) Withdraw(ctx context.Context) (*types.Transaction, error) {
 	return nil, nil
 }
 
-func (b *stubBroker) RedeemWinningTicket(ticket *Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error) {
+func (b *stubBroker) RedeemWinningTicket(ctx context.Context, ticket *Ticket, sig []byte, recipientRand *big.Int) (*types.Transaction, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -193,7 +215,23 @@ func (b *stubBroker) RedeemWinningTicket(ticket *Ticket, sig []byte, recipientRa
 	return types.NewTx(&types.DynamicFeeTx{}), nil
 }
 
-func (b *stubBroker) IsUsedTicket(ticket *Ticket) (bool, error) {
+func (b *stubBroker) BatchRedeemWinningTickets(ctx context.Context, tickets []*SignedTicket) (*types.Transaction, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.redeemShouldFail {
+		return nil, fmt.Errorf("stub broker redeem error")
+	}
+
+	b.batches++
+	for _, ticket := range tickets {
+		b.usedTickets[ticket.Hash()] = true
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{}), nil
+}
+
+func (b *stubBroker) IsUsedTicket(ctx context.Context, ticket *Ticket) (bool, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -212,7 +250,7 @@ func (b *stubBroker) ClaimableReserve(reserveHolder ethcommon.Address, claimant
 	return b.reserves[reserveHolder], nil
 }
 
-func (b *stubBroker) CheckTx(tx *types.Transaction) error {
+func (b *stubBroker) CheckTx(ctx context.Context, tx *types.Transaction) error {
 	return b.checkTxErr
 }
 
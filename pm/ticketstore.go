@@ -12,6 +12,10 @@ type TicketStore interface {
 	// which is not yet redeemed
 	SelectEarliestWinningTicket(sender ethcommon.Address, minCreationRound int64) (*SignedTicket, error)
 
+	// SelectEarliestWinningTickets selects up to 'limit' of the earliest stored winning tickets for a 'sender'
+	// which are not yet redeemed
+	SelectEarliestWinningTickets(sender ethcommon.Address, minCreationRound int64, limit int) ([]*SignedTicket, error)
+
 	// RemoveWinningTicket removes a ticket
 	RemoveWinningTicket(ticket *SignedTicket) error
 
@@ -0,0 +1,147 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+)
+
+// The policy gRPC service exchanges the request and response types of this package as JSON, i.e. with the
+// application/grpc+json content type, so that external policies can be written in any language without protobuf
+// definitions
+const (
+	serviceName  = "livepeer.policy.Policy"
+	selectMethod = "/" + serviceName + "/Select"
+	priceMethod  = "/" + serviceName + "/Price"
+	codecName    = "json"
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// Client calls the policies served by an external process
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// Dial connects to the policy service at addr, i.e. host:port or unix:///path/to/socket
+func Dial(addr string) (*Client, error) {
+	conn, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Select calls the selection policy of the external process
+func (c *Client) Select(ctx context.Context, req *SelectRequest) (*SelectResponse, error) {
+	var res SelectResponse
+	if err := c.conn.Invoke(ctx, selectMethod, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Price calls the pricing policy of the external process
+func (c *Client) Price(ctx context.Context, req *PriceRequest) (*PriceResponse, error) {
+	var res PriceResponse
+	if err := c.conn.Invoke(ctx, priceMethod, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Close closes the connection to the external process
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// policyServer serves the policies. Policies that are nil are unimplemented
+type policyServer struct {
+	selection SelectionPolicy
+	pricing   PricingPolicy
+}
+
+type policyService interface {
+	Select(ctx context.Context, req *SelectRequest) (*SelectResponse, error)
+	Price(ctx context.Context, req *PriceRequest) (*PriceResponse, error)
+}
+
+func (s *policyServer) Select(ctx context.Context, req *SelectRequest) (*SelectResponse, error) {
+	if s.selection == nil {
+		return nil, status.Error(codes.Unimplemented, "selection policy not implemented")
+	}
+	return s.selection.Select(ctx, req)
+}
+
+func (s *policyServer) Price(ctx context.Context, req *PriceRequest) (*PriceResponse, error) {
+	if s.pricing == nil {
+		return nil, status.Error(codes.Unimplemented, "pricing policy not implemented")
+	}
+	return s.pricing.Price(ctx, req)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*policyService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Select",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(SelectRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(policyService).Select(ctx, req.(*SelectRequest))
+				}
+				if interceptor == nil {
+					return handler(ctx, req)
+				}
+				return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: selectMethod}, handler)
+			},
+		},
+		{
+			MethodName: "Price",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(PriceRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(policyService).Price(ctx, req.(*PriceRequest))
+				}
+				if interceptor == nil {
+					return handler(ctx, req)
+				}
+				return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: priceMethod}, handler)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+// RegisterServer registers the policy service serving selection and pricing on s. Either policy may be nil. External
+// policies written in Go use it to serve their policies to the node
+func RegisterServer(s *grpc.Server, selection SelectionPolicy, pricing PricingPolicy) {
+	s.RegisterService(&serviceDesc, &policyServer{selection: selection, pricing: pricing})
+}
@@ -0,0 +1,99 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stubSelection struct {
+	req *SelectRequest
+	err error
+}
+
+func (s *stubSelection) Select(ctx context.Context, req *SelectRequest) (*SelectResponse, error) {
+	s.req = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &SelectResponse{Index: len(req.Candidates) - 1}, nil
+}
+
+type stubPricing struct{}
+
+func (p *stubPricing) Price(ctx context.Context, req *PriceRequest) (*PriceResponse, error) {
+	return &PriceResponse{Price: Price{PricePerUnit: req.BasePrice.PricePerUnit * 2, PixelsPerUnit: req.BasePrice.PixelsPerUnit}}, nil
+}
+
+func startPolicyServer(t *testing.T, selection SelectionPolicy, pricing PricingPolicy) (*Client, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	s := grpc.NewServer()
+	RegisterServer(s, selection, pricing)
+	go s.Serve(lis)
+
+	c, err := Dial(lis.Addr().String())
+	require.Nil(t, err)
+	return c, func() {
+		c.Close()
+		s.Stop()
+	}
+}
+
+func TestClient_Select(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sel := &stubSelection{}
+	c, stop := startPolicyServer(t, sel, nil)
+	defer stop()
+
+	req := &SelectRequest{
+		ManifestID: "mid",
+		Candidates: []Candidate{
+			{Transcoder: "https://foo.com"},
+			{Transcoder: "https://bar.com", Price: &Price{PricePerUnit: 1, PixelsPerUnit: 2}, LatencyScore: 0.5, Scored: true, Stake: 100},
+		},
+	}
+	res, err := c.Select(context.Background(), req)
+	require.Nil(err)
+	assert.Equal(1, res.Index)
+	assert.Equal(req, sel.req)
+
+	// Errors of the policy are returned to the client
+	sel.err = errors.New("policy error")
+	_, err = c.Select(context.Background(), req)
+	assert.EqualError(err, status.Error(codes.Unknown, "policy error").Error())
+
+	// The pricing policy is not implemented by the server
+	_, err = c.Price(context.Background(), &PriceRequest{})
+	assert.Equal(codes.Unimplemented, status.Code(err))
+}
+
+func TestClient_Price(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	c, stop := startPolicyServer(t, nil, &stubPricing{})
+	defer stop()
+
+	res, err := c.Price(context.Background(), &PriceRequest{Broadcaster: "0x01", BasePrice: Price{PricePerUnit: 3, PixelsPerUnit: 4}})
+	require.Nil(err)
+	assert.Equal(Price{PricePerUnit: 6, PixelsPerUnit: 4}, res.Price)
+
+	// The selection policy is not implemented by the server
+	_, err = c.Select(context.Background(), &SelectRequest{})
+	assert.Equal(codes.Unimplemented, status.Code(err))
+}
+
+func TestLoadPlugin_Errors(t *testing.T) {
+	_, _, err := LoadPlugin("/does/not/exist.so")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// LoadPlugin loads the policies from the Go plugin at path. The plugin exports the policies as the variables
+// Selection and Pricing, either of which may be omitted. The plugin must be built with the same Go version and
+// dependency versions as the node
+func LoadPlugin(path string) (SelectionPolicy, PricingPolicy, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sel SelectionPolicy
+	if sym, err := p.Lookup("Selection"); err == nil {
+		s, ok := sym.(*SelectionPolicy)
+		if !ok {
+			return nil, nil, fmt.Errorf("plugin %s: Selection is %T, not a policy.SelectionPolicy", path, sym)
+		}
+		sel = *s
+	}
+
+	var pricing PricingPolicy
+	if sym, err := p.Lookup("Pricing"); err == nil {
+		s, ok := sym.(*PricingPolicy)
+		if !ok {
+			return nil, nil, fmt.Errorf("plugin %s: Pricing is %T, not a policy.PricingPolicy", path, sym)
+		}
+		pricing = *s
+	}
+
+	if sel == nil && pricing == nil {
+		return nil, nil, fmt.Errorf("plugin %s exports neither Selection nor Pricing", path)
+	}
+	return sel, pricing, nil
+}
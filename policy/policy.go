@@ -0,0 +1,77 @@
+// Package policy defines the extension points that let operators supply their own orchestrator selection and pricing
+// logic without forking the node. Policies are loaded from a Go plugin or run in an external process that serves the
+// policy gRPC service
+package policy
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// Timeout is the time a policy has to make a decision before the node falls back to its built-in logic
+var Timeout = 500 * time.Millisecond
+
+var ErrInvalidPrice = errors.New("invalid price")
+
+// Price is a price in wei per PixelsPerUnit pixels
+type Price struct {
+	PricePerUnit  int64 `json:"pricePerUnit"`
+	PixelsPerUnit int64 `json:"pixelsPerUnit"`
+}
+
+// Rat returns the price per pixel
+func (p Price) Rat() (*big.Rat, error) {
+	if p.PricePerUnit < 0 || p.PixelsPerUnit <= 0 {
+		return nil, ErrInvalidPrice
+	}
+	return big.NewRat(p.PricePerUnit, p.PixelsPerUnit), nil
+}
+
+// Candidate is an orchestrator session that the broadcaster can send the next segment of a stream to
+type Candidate struct {
+	Transcoder string `json:"transcoder"`
+	Address    string `json:"address,omitempty"`
+	Price      *Price `json:"price,omitempty"`
+	// LatencyScore is the ratio of the transcoding time to the duration of the last segment. It is only set if Scored
+	LatencyScore float64 `json:"latencyScore"`
+	Scored       bool    `json:"scored"`
+	Trusted      bool    `json:"trusted"`
+	// Stake is the stake of the orchestrator in LPTU if known
+	Stake int64 `json:"stake,omitempty"`
+}
+
+// SelectRequest asks the policy to select one of the candidates for the next segment of the stream
+type SelectRequest struct {
+	ManifestID string      `json:"manifestID"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// SelectResponse is the index of the selected candidate. A negative index defers the decision to the node
+type SelectResponse struct {
+	Index int `json:"index"`
+}
+
+// PriceRequest asks the policy for the price to quote a broadcaster. Price is the price that the node would quote,
+// i.e. the base price with the overhead for redeeming tickets
+type PriceRequest struct {
+	Broadcaster string `json:"broadcaster"`
+	BasePrice   Price  `json:"basePrice"`
+	Price       Price  `json:"price"`
+}
+
+// PriceResponse is the price to quote the broadcaster
+type PriceResponse struct {
+	Price Price `json:"price"`
+}
+
+// SelectionPolicy selects the orchestrator that the broadcaster sends the next segment of a stream to
+type SelectionPolicy interface {
+	Select(ctx context.Context, req *SelectRequest) (*SelectResponse, error)
+}
+
+// PricingPolicy sets the price that the orchestrator quotes broadcasters
+type PricingPolicy interface {
+	Price(ctx context.Context, req *PriceRequest) (*PriceResponse, error)
+}
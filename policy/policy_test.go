@@ -0,0 +1,26 @@
+package policy
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrice_Rat(t *testing.T) {
+	assert := assert.New(t)
+
+	r, err := Price{PricePerUnit: 3, PixelsPerUnit: 6}.Rat()
+	assert.Nil(err)
+	assert.Zero(big.NewRat(1, 2).Cmp(r))
+
+	r, err = Price{PricePerUnit: 0, PixelsPerUnit: 1}.Rat()
+	assert.Nil(err)
+	assert.Zero(r.Sign())
+
+	_, err = Price{PricePerUnit: -1, PixelsPerUnit: 1}.Rat()
+	assert.Equal(ErrInvalidPrice, err)
+
+	_, err = Price{PricePerUnit: 1, PixelsPerUnit: 0}.Rat()
+	assert.Equal(ErrInvalidPrice, err)
+}
@@ -0,0 +1,674 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/clog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/monitor"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/livepeer/lpms/stream"
+	"github.com/livepeer/m3u8"
+)
+
+// BatchCallbackClient posts the results of the assets of batch jobs to their callback URLs
+var BatchCallbackClient = &http.Client{Timeout: 10 * time.Second}
+
+// batchMaxAttempts is how many times an asset is attempted to be transcoded before it fails
+var batchMaxAttempts = 3
+
+// batchRetryBackoff is the delay before the first retry of a failed asset. It doubles with each further retry
+var batchRetryBackoff = 30 * time.Second
+
+// batchSegmentFile cuts a source file into MPEG TS segments and an HLS playlist, like the segmenter of RTMP streams
+var batchSegmentFile = ffmpeg.RTMPToHLS
+
+var batchAssetIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// batchRequest is the body of a request to the /batch endpoint
+type batchRequest struct {
+	Assets   []batchAsset         `json:"assets"`
+	Presets  []string             `json:"presets"`
+	Profiles []ffmpeg.JsonProfile `json:"profiles"`
+	// ObjectStore is the object storage the renditions and their playlists are uploaded to
+	ObjectStore string `json:"objectStore"`
+	// CallbackURL is posted the result of each asset once it is transcoded or failed
+	CallbackURL string `json:"callbackUrl"`
+}
+
+type batchAsset struct {
+	ID string `json:"id"`
+	// URL is the HLS media playlist or the file of the source of the asset
+	URL string `json:"url"`
+}
+
+// batchJob is a validated batchRequest
+type batchJob struct {
+	id       string
+	assets   []batchAsset
+	profiles []ffmpeg.VideoProfile
+	// osURL is the URL of the object store that os is parsed from
+	osURL    string
+	os       drivers.OSDriver
+	callback *url.URL
+}
+
+// batchAssetResult is posted to the callback URL of a batch job for each asset
+type batchAssetResult struct {
+	BatchID string `json:"batchId"`
+	AssetID string `json:"assetId"`
+	// Status is the final state of the asset, completed or failed
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Playlist is the URL of the master playlist of the renditions
+	Playlist   string           `json:"playlist,omitempty"`
+	Renditions []batchRendition `json:"renditions,omitempty"`
+}
+
+type batchRendition struct {
+	Name     string `json:"name"`
+	Playlist string `json:"playlist"`
+}
+
+// batchSegment is a segment of the source of an asset
+type batchSegment struct {
+	url      string
+	duration float64
+	// local is set for the segments of source files, url is then their path in the work dir of the node
+	local bool
+}
+
+func (seg batchSegment) data(ctx context.Context) ([]byte, error) {
+	if seg.local {
+		return ioutil.ReadFile(seg.url)
+	}
+	return downloadSeg(ctx, seg.url)
+}
+
+// parseBatchRequest validates req and returns the job to run for it
+func parseBatchRequest(req *batchRequest) (*batchJob, error) {
+	if len(req.Assets) == 0 {
+		return nil, errors.New("missing assets")
+	}
+	ids := make(map[string]bool)
+	for _, a := range req.Assets {
+		if !batchAssetIDPattern.MatchString(a.ID) {
+			return nil, fmt.Errorf("invalid asset id=%q", a.ID)
+		}
+		if ids[a.ID] {
+			return nil, fmt.Errorf("duplicate asset id=%s", a.ID)
+		}
+		ids[a.ID] = true
+		if u, err := url.Parse(a.URL); err != nil || u.Scheme == "" {
+			return nil, fmt.Errorf("invalid source url of asset id=%s", a.ID)
+		}
+	}
+
+	profiles, err := parsePresets(req.Presets)
+	if err != nil {
+		return nil, err
+	}
+	jsonProfiles, err := ffmpeg.ParseProfilesFromJsonProfileArray(req.Profiles)
+	if err != nil {
+		return nil, err
+	}
+	profiles = append(profiles, jsonProfiles...)
+	if len(profiles) == 0 {
+		profiles = append(profiles, BroadcastJobVideoProfiles...)
+	}
+
+	// The renditions are only kept if they are uploaded to an object store
+	if req.ObjectStore == "" {
+		return nil, errors.New("missing object store")
+	}
+	os, err := drivers.ParseOSURL(req.ObjectStore, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object store: %w", err)
+	}
+
+	callback, err := url.ParseRequestURI(req.CallbackURL)
+	if err != nil {
+		return nil, errors.New("invalid callback url")
+	}
+
+	return &batchJob{
+		id:       common.RandName(),
+		assets:   req.Assets,
+		profiles: profiles,
+		osURL:    req.ObjectStore,
+		os:       os,
+		callback: callback,
+	}, nil
+}
+
+// queuedBatchAssets returns the rows of the assets of job for the queue of batch assets
+func queuedBatchAssets(job *batchJob) ([]*common.DBBatchAsset, error) {
+	profiles, err := json.Marshal(job.profiles)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	assets := make([]*common.DBBatchAsset, len(job.assets))
+	for i, a := range job.assets {
+		assets[i] = &common.DBBatchAsset{
+			BatchID:     job.id,
+			AssetID:     a.ID,
+			Position:    i,
+			NodeID:      monitor.NodeID,
+			URL:         a.URL,
+			Profiles:    string(profiles),
+			ObjectStore: job.osURL,
+			CallbackURL: job.callback.String(),
+			State:       common.BatchAssetQueued,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+	return assets, nil
+}
+
+// batchJobFromAsset returns the job that a queued asset belongs to, without its assets
+func batchJobFromAsset(asset *common.DBBatchAsset) (*batchJob, error) {
+	var profiles []ffmpeg.VideoProfile
+	if err := json.Unmarshal([]byte(asset.Profiles), &profiles); err != nil {
+		return nil, fmt.Errorf("invalid profiles: %w", err)
+	}
+	os, err := drivers.ParseOSURL(asset.ObjectStore, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object store: %w", err)
+	}
+	callback, err := url.ParseRequestURI(asset.CallbackURL)
+	if err != nil {
+		return nil, errors.New("invalid callback url")
+	}
+	return &batchJob{id: asset.BatchID, profiles: profiles, osURL: asset.ObjectStore, os: os, callback: callback}, nil
+}
+
+// batchHandler queues a batch job for the assets of the request and responds with the id of the job. The assets are
+// transcoded one after the other and the result of each is posted to the callback URL of the job. The queue is kept
+// in the DB, so the jobs are resumed after a restart of the node
+func batchHandler(s *LivepeerServer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		db := s.LivepeerNode.Database
+		if db == nil {
+			respondWith500(w, "batch jobs require a DB")
+			return
+		}
+		var req batchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWith400(w, fmt.Sprintf("invalid batch request: %v", err))
+			return
+		}
+		job, err := parseBatchRequest(&req)
+		if err != nil {
+			respondWith400(w, err.Error())
+			return
+		}
+
+		assets, err := queuedBatchAssets(job)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		for _, a := range assets {
+			if err := db.InsertBatchAsset(a); err != nil {
+				respondWith500(w, fmt.Sprintf("could not queue batch job: %v", err))
+				return
+			}
+		}
+
+		ctx := clog.AddVal(context.Background(), "batchID", job.id)
+		clog.Infof(ctx, "Starting batch job assets=%d profiles=%s", len(job.assets), common.ProfilesNames(job.profiles))
+		s.startBatchJob(ctx, job, assets)
+
+		data, err := json.Marshal(struct {
+			ID string `json:"id"`
+		}{job.id})
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(data)
+	})
+}
+
+// ResumeBatchJobs restarts the batch jobs of this node that have unfinished assets, i.e. the jobs that were
+// interrupted by a restart. The assets that were being transcoded are attempted again
+func (s *LivepeerServer) ResumeBatchJobs() error {
+	db := s.LivepeerNode.Database
+	if db == nil {
+		return nil
+	}
+	assets, err := db.BatchAssets(&common.DBBatchAssetFilter{NodeID: monitor.NodeID, States: common.BatchAssetUnfinished})
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	jobAssets := make(map[string][]*common.DBBatchAsset)
+	for _, a := range assets {
+		if _, ok := jobAssets[a.BatchID]; !ok {
+			ids = append(ids, a.BatchID)
+		}
+		jobAssets[a.BatchID] = append(jobAssets[a.BatchID], a)
+	}
+	for _, id := range ids {
+		ctx := clog.AddVal(context.Background(), "batchID", id)
+		job, err := batchJobFromAsset(jobAssets[id][0])
+		if err != nil {
+			clog.Errorf(ctx, "Unable to resume batch job err=%q", err)
+			continue
+		}
+		clog.Infof(ctx, "Resuming batch job assets=%d", len(jobAssets[id]))
+		s.startBatchJob(ctx, job, jobAssets[id])
+	}
+	return nil
+}
+
+// startBatchJob runs the queued assets of job in the background until they are finished or the job is cancelled
+func (s *LivepeerServer) startBatchJob(ctx context.Context, job *batchJob, assets []*common.DBBatchAsset) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.batchLock.Lock()
+	if s.batchJobs == nil {
+		s.batchJobs = make(map[string]context.CancelFunc)
+	}
+	s.batchJobs[job.id] = cancel
+	s.batchLock.Unlock()
+
+	go func() {
+		defer func() {
+			s.batchLock.Lock()
+			delete(s.batchJobs, job.id)
+			s.batchLock.Unlock()
+			cancel()
+		}()
+		s.runBatchJob(ctx, job, assets)
+	}()
+}
+
+func (s *LivepeerServer) runBatchJob(ctx context.Context, job *batchJob, assets []*common.DBBatchAsset) {
+	for _, asset := range assets {
+		if !s.runBatchAsset(clog.AddVal(ctx, "assetID", asset.AssetID), job, asset) {
+			clog.Infof(ctx, "Batch job cancelled")
+			return
+		}
+	}
+}
+
+// runBatchAsset transcodes asset until it is completed or out of attempts and posts its result to the callback URL
+// of job. It returns false if the job was cancelled
+func (s *LivepeerServer) runBatchAsset(ctx context.Context, job *batchJob, asset *common.DBBatchAsset) bool {
+	for {
+		if wait := time.Until(asset.NextAttemptAt); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		asset.Attempts++
+		if !s.updateBatchAsset(ctx, asset, common.BatchAssetTranscoding) {
+			return false
+		}
+		res := &batchAssetResult{BatchID: job.id, AssetID: asset.AssetID, Status: common.BatchAssetCompleted}
+		err := s.transcodeBatchAsset(ctx, job, batchAsset{ID: asset.AssetID, URL: asset.URL}, res, func() {
+			s.updateBatchAsset(ctx, asset, common.BatchAssetUploading)
+		})
+		if ctx.Err() != nil {
+			return false
+		}
+
+		if err == nil {
+			data, err := json.Marshal(res)
+			if err != nil {
+				clog.Errorf(ctx, "Unable to marshal batch asset result err=%q", err)
+			}
+			asset.Error, asset.Result = "", string(data)
+			if !s.updateBatchAsset(ctx, asset, common.BatchAssetCompleted) {
+				return false
+			}
+			clog.Infof(ctx, "Batch asset completed playlist=%s", res.Playlist)
+			postBatchCallback(ctx, job.callback, res)
+			return true
+		}
+
+		asset.Error = err.Error()
+		if asset.Attempts < batchMaxAttempts {
+			backoff := batchRetryBackoff << (asset.Attempts - 1)
+			asset.NextAttemptAt = time.Now().Add(backoff)
+			clog.Errorf(ctx, "Batch asset failed, retrying attempt=%d backoff=%v err=%q", asset.Attempts, backoff, err)
+			if !s.updateBatchAsset(ctx, asset, common.BatchAssetQueued) {
+				return false
+			}
+			continue
+		}
+		clog.Errorf(ctx, "Batch asset failed attempts=%d err=%q", asset.Attempts, err)
+		if !s.updateBatchAsset(ctx, asset, common.BatchAssetFailed) {
+			return false
+		}
+		postBatchCallback(ctx, job.callback, &batchAssetResult{BatchID: job.id, AssetID: asset.AssetID, Status: common.BatchAssetFailed, Error: err.Error()})
+		return true
+	}
+}
+
+// updateBatchAsset stores asset with state in the DB. It returns false if the asset was cancelled. Errors of the DB
+// are logged and do not stop the job
+func (s *LivepeerServer) updateBatchAsset(ctx context.Context, asset *common.DBBatchAsset, state string) bool {
+	asset.State = state
+	asset.UpdatedAt = time.Now()
+	ok, err := s.LivepeerNode.Database.UpdateBatchAsset(asset)
+	if err != nil {
+		clog.Errorf(ctx, "Unable to update batch asset state=%s err=%q", state, err)
+		return true
+	}
+	return ok
+}
+
+// transcodeBatchAsset transcodes the segments of the source of asset with the same pipeline as the streams pushed over
+// HTTP and uploads VOD playlists of the renditions next to them. uploading is called once all segments are transcoded
+func (s *LivepeerServer) transcodeBatchAsset(ctx context.Context, job *batchJob, asset batchAsset, res *batchAssetResult, uploading func()) error {
+	mid := core.ManifestID(job.id + "_" + asset.ID)
+	ctx = clog.AddManifestID(ctx, string(mid))
+	workDir := filepath.Join(s.LivepeerNode.WorkDir, "batch", string(mid))
+	defer os.RemoveAll(workDir)
+	segs, err := batchSourceSegments(ctx, asset.URL, workDir)
+	if err != nil {
+		return err
+	}
+	format := common.ProfileExtensionFormat(path.Ext(segs[0].url))
+	if format == ffmpeg.FormatNone {
+		return fmt.Errorf("unsupported source segment format url=%s", segs[0].url)
+	}
+	first, err := segs[0].data(ctx)
+	if err != nil {
+		return fmt.Errorf("error downloading source segment url=%s: %w", segs[0].url, err)
+	}
+	_, _, vcodecStr, pixelFormat, err := ffmpeg.GetCodecInfoBytes(first)
+	if err != nil {
+		return fmt.Errorf("error getting codec info: %w", err)
+	}
+	var vcodec *ffmpeg.VideoCodec
+	if vcodecVal, ok := ffmpeg.FfmpegNameToVideoCodec[vcodecStr]; ok {
+		vcodec = &vcodecVal
+	}
+
+	params := &core.StreamParameters{
+		ManifestID: mid,
+		Profiles:   append([]ffmpeg.VideoProfile(nil), job.profiles...),
+		OS:         job.os.NewSession(string(mid)),
+		Format:     format,
+		Nonce:      rand.Uint64(),
+	}
+	for i, p := range params.Profiles {
+		if p.Format == ffmpeg.FormatNone {
+			params.Profiles[i].Format = format
+		}
+	}
+	cxn, err := s.registerConnection(ctx, stream.NewBasicRTMPVideoStream(params), vcodec, pixelFormat)
+	if err != nil {
+		return err
+	}
+	defer removeRTMPStream(ctx, s, mid)
+
+	profiles := cxn.params.Profiles
+	playlists := make([]*m3u8.MediaPlaylist, len(profiles))
+	for i := range playlists {
+		if playlists[i], err = m3u8.NewMediaPlaylist(0, uint(len(segs))); err != nil {
+			return err
+		}
+	}
+	for i, src := range segs {
+		data := first
+		if i > 0 {
+			if data, err = src.data(ctx); err != nil {
+				return fmt.Errorf("error downloading source segment url=%s: %w", src.url, err)
+			}
+		}
+		seg := &stream.HLSSegment{
+			Data:     data,
+			Name:     path.Base(src.url),
+			SeqNo:    uint64(i),
+			Duration: src.duration,
+		}
+		urls, err := processSegment(clog.AddSeqNo(ctx, seg.SeqNo), cxn, seg)
+		if err != nil {
+			return fmt.Errorf("error transcoding segment seqNo=%d: %w", i, err)
+		}
+		if len(urls) != len(profiles) {
+			return fmt.Errorf("error transcoding segment seqNo=%d: %w", i, errNoOrchs)
+		}
+		for j, u := range urls {
+			// Batch jobs need all renditions, including those of output groups that failed
+			if u == "" {
+				return fmt.Errorf("error transcoding segment seqNo=%d rendition=%s: %w", i, profiles[j].Name, errNoOrchs)
+			}
+		}
+		for j, u := range urls {
+			if err := playlists[j].Append(u, src.duration, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The segments of the renditions were uploaded by processSegment, only the playlists are left
+	uploading()
+	sess := cxn.pl.GetOSSession()
+	master := m3u8.NewMasterPlaylist()
+	for i, p := range profiles {
+		playlists[i].Close()
+		name := p.Name + "/index.m3u8"
+		uri, err := sess.SaveData(ctx, name, playlists[i].Encode().Bytes(), nil, 0)
+		if err != nil {
+			return fmt.Errorf("error uploading playlist name=%s: %w", name, err)
+		}
+		master.Append(name, playlists[i], ffmpeg.VideoProfileToVariantParams(p))
+		res.Renditions = append(res.Renditions, batchRendition{Name: p.Name, Playlist: uri})
+	}
+	if res.Playlist, err = sess.SaveData(ctx, "index.m3u8", master.Encode().Bytes(), nil, 0); err != nil {
+		return fmt.Errorf("error uploading master playlist: %w", err)
+	}
+	return nil
+}
+
+// batchSourceSegments returns the segments of the source at src. An HLS media playlist is transcoded segment by
+// segment, any other source is a file that is first segmented into workDir
+func batchSourceSegments(ctx context.Context, src, workDir string) ([]batchSegment, error) {
+	base, err := url.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	if path.Ext(base.Path) != ".m3u8" {
+		return batchFileSegments(ctx, src, workDir)
+	}
+	data, err := downloadSeg(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading source playlist: %w", err)
+	}
+	return batchPlaylistSegments(data, base, false)
+}
+
+// batchFileSegments segments the source file at src into MPEG TS segments of SegLen in workDir. The streams are
+// copied, so the segments are cut at the keyframes of the source
+func batchFileSegments(ctx context.Context, src, workDir string) ([]batchSegment, error) {
+	if err := os.MkdirAll(workDir, 0700); err != nil {
+		return nil, err
+	}
+	outp := filepath.Join(workDir, "source.m3u8")
+	tmpl := filepath.Join(workDir, "source_%d.ts")
+	seglen := strconv.FormatFloat(SegLen.Seconds(), 'f', 6, 64)
+	clog.V(common.VERBOSE).Infof(ctx, "Segmenting batch source url=%s", src)
+	if err := batchSegmentFile(src, outp, tmpl, seglen, 0); err != nil {
+		return nil, fmt.Errorf("error segmenting source: %w", err)
+	}
+	data, err := ioutil.ReadFile(outp)
+	if err != nil {
+		return nil, fmt.Errorf("error reading source playlist: %w", err)
+	}
+	return batchPlaylistSegments(data, &url.URL{Path: outp}, true)
+}
+
+// batchPlaylistSegments returns the segments of the HLS media playlist in data with their URIs resolved against base
+func batchPlaylistSegments(data []byte, base *url.URL, local bool) ([]batchSegment, error) {
+	pl, listType, err := m3u8.DecodeFrom(bytes.NewReader(data), true)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding source playlist: %w", err)
+	}
+	if listType != m3u8.MEDIA {
+		return nil, errors.New("source is not a media playlist")
+	}
+
+	var segs []batchSegment
+	for _, seg := range pl.(*m3u8.MediaPlaylist).Segments {
+		if seg == nil {
+			break
+		}
+		u, err := base.Parse(seg.URI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source segment uri=%s", seg.URI)
+		}
+		uri := u.String()
+		if local {
+			uri = u.Path
+		}
+		segs = append(segs, batchSegment{url: uri, duration: seg.Duration, local: local})
+	}
+	if len(segs) == 0 {
+		return nil, errors.New("source playlist without segments")
+	}
+	return segs, nil
+}
+
+// batchJobAsset is an asset of a batch job in the responses of the CLI API
+type batchJobAsset struct {
+	BatchID  string `json:"batchId"`
+	AssetID  string `json:"assetId"`
+	NodeID   string `json:"nodeId"`
+	URL      string `json:"url"`
+	State    string `json:"state"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+	// Playlist and Renditions are set once the asset is completed
+	Playlist    string           `json:"playlist,omitempty"`
+	Renditions  []batchRendition `json:"renditions,omitempty"`
+	NextAttempt *time.Time       `json:"nextAttempt,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
+
+// batchJobsHandler responds with the assets of the batch jobs in the order they were queued. The assets can be
+// limited to the batch in the id param and to the comma separated states in the state param
+func batchJobsHandler(db *common.DB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if db == nil {
+			respondWith500(w, "batch jobs require a DB")
+			return
+		}
+		filter := &common.DBBatchAssetFilter{BatchID: r.FormValue("id")}
+		if state := r.FormValue("state"); state != "" {
+			filter.States = strings.Split(state, ",")
+		}
+		assets, err := db.BatchAssets(filter)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+
+		res := make([]batchJobAsset, 0, len(assets))
+		for _, a := range assets {
+			ja := batchJobAsset{BatchID: a.BatchID, AssetID: a.AssetID, NodeID: a.NodeID, URL: a.URL, State: a.State,
+				Attempts: a.Attempts, Error: a.Error, CreatedAt: a.CreatedAt, UpdatedAt: a.UpdatedAt}
+			if a.Result != "" {
+				var result batchAssetResult
+				if err := json.Unmarshal([]byte(a.Result), &result); err == nil {
+					ja.Playlist, ja.Renditions = result.Playlist, result.Renditions
+				}
+			}
+			if a.State == common.BatchAssetQueued && a.NextAttemptAt.After(a.CreatedAt) {
+				next := a.NextAttemptAt
+				ja.NextAttempt = &next
+			}
+			res = append(res, ja)
+		}
+		data, err := json.Marshal(res)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	})
+}
+
+// cancelBatchJobHandler cancels the unfinished assets of the batch job in the id param. The asset that is being
+// transcoded is stopped and no results are posted for the cancelled assets
+func cancelBatchJobHandler(s *LivepeerServer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		db := s.LivepeerNode.Database
+		if db == nil {
+			respondWith500(w, "batch jobs require a DB")
+			return
+		}
+		id := r.FormValue("id")
+		n, err := db.CancelBatchAssets(id, time.Now())
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not cancel batch job: %v", err))
+			return
+		}
+		if n == 0 {
+			respondWith400(w, fmt.Sprintf("no unfinished batch job id=%s", id))
+			return
+		}
+
+		s.batchLock.Lock()
+		cancel, ok := s.batchJobs[id]
+		s.batchLock.Unlock()
+		if ok {
+			cancel()
+		}
+		glog.Infof("Cancelled batch job batchID=%s assets=%d", id, n)
+		respondOk(w, []byte(fmt.Sprintf("cancelled %d assets", n)))
+	})
+}
+
+func postBatchCallback(ctx context.Context, callback *url.URL, res *batchAssetResult) {
+	jsonValue, err := json.Marshal(res)
+	if err != nil {
+		clog.Errorf(ctx, "Unable to marshal batch asset result err=%q", err)
+		return
+	}
+	resp, err := BatchCallbackClient.Post(callback.String(), "application/json", bytes.NewBuffer(jsonValue))
+	if err != nil {
+		clog.Errorf(ctx, "Unable to POST batch asset result on callback url=%v err=%q", callback.Redacted(), err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		rbody, _ := ioutil.ReadAll(resp.Body)
+		clog.Errorf(ctx, "Batch callback returned error status=%v err=%q", resp.StatusCode, string(rbody))
+	}
+}
@@ -0,0 +1,332 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/monitor"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBatchRequest(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	drivers.Testing = true
+
+	valid := func() *batchRequest {
+		return &batchRequest{
+			Assets:      []batchAsset{{ID: "movie-1", URL: "https://source/movie1/index.m3u8"}, {ID: "movie_2", URL: "https://source/movie2/index.m3u8"}},
+			Presets:     []string{"P240p30fps16x9"},
+			ObjectStore: "memory://batch",
+			CallbackURL: "https://callback/assets",
+		}
+	}
+
+	job, err := parseBatchRequest(valid())
+	require.Nil(err)
+	assert.NotEmpty(job.id)
+	assert.Len(job.assets, 2)
+	assert.Equal([]ffmpeg.VideoProfile{ffmpeg.P240p30fps16x9}, job.profiles)
+	assert.NotNil(job.os)
+	assert.Equal("https://callback/assets", job.callback.String())
+
+	// The default profiles of the broadcaster are used if none are set
+	req := valid()
+	req.Presets = nil
+	job, err = parseBatchRequest(req)
+	require.Nil(err)
+	assert.Equal(BroadcastJobVideoProfiles, job.profiles)
+
+	req = valid()
+	req.Assets = nil
+	_, err = parseBatchRequest(req)
+	assert.EqualError(err, "missing assets")
+
+	// Asset ids end up in the paths of the renditions
+	req = valid()
+	req.Assets[0].ID = "../movie"
+	_, err = parseBatchRequest(req)
+	assert.EqualError(err, `invalid asset id="../movie"`)
+
+	req = valid()
+	req.Assets[1].ID = req.Assets[0].ID
+	_, err = parseBatchRequest(req)
+	assert.EqualError(err, "duplicate asset id=movie-1")
+
+	req = valid()
+	req.Assets[0].URL = "movie1.m3u8"
+	_, err = parseBatchRequest(req)
+	assert.EqualError(err, "invalid source url of asset id=movie-1")
+
+	req = valid()
+	req.Presets = []string{"unknown"}
+	_, err = parseBatchRequest(req)
+	assert.ErrorIs(err, errPreset)
+
+	req = valid()
+	req.ObjectStore = ""
+	_, err = parseBatchRequest(req)
+	assert.EqualError(err, "missing object store")
+
+	req = valid()
+	req.CallbackURL = ""
+	_, err = parseBatchRequest(req)
+	assert.EqualError(err, "invalid callback url")
+}
+
+func TestBatchSourceSegments(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	oldDownloadSeg := downloadSeg
+	defer func() { downloadSeg = oldDownloadSeg }()
+	sources := map[string]string{
+		"https://source/movie/index.m3u8": "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:4\n#EXTINF:4.000,\n0.ts\n#EXTINF:2.500,\nhttps://cdn/movie/1.ts\n#EXT-X-ENDLIST\n",
+		"https://source/master.m3u8":      "#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=400000,RESOLUTION=426x240\nmovie/index.m3u8\n",
+		"https://source/empty.m3u8":       "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:4\n#EXT-X-ENDLIST\n",
+	}
+	downloadSeg = func(ctx context.Context, url string) ([]byte, error) {
+		if data, ok := sources[url]; ok {
+			return []byte(data), nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	// Relative segment URIs are resolved against the URL of the playlist
+	segs, err := batchSourceSegments(context.Background(), "https://source/movie/index.m3u8", "")
+	require.Nil(err)
+	assert.Equal([]batchSegment{{url: "https://source/movie/0.ts", duration: 4}, {url: "https://cdn/movie/1.ts", duration: 2.5}}, segs)
+
+	_, err = batchSourceSegments(context.Background(), "https://source/master.m3u8", "")
+	assert.EqualError(err, "source is not a media playlist")
+
+	_, err = batchSourceSegments(context.Background(), "https://source/empty.m3u8", "")
+	assert.EqualError(err, "source playlist without segments")
+
+	_, err = batchSourceSegments(context.Background(), "https://source/missing.m3u8", "")
+	assert.EqualError(err, "error downloading source playlist: not found")
+}
+
+func TestBatchSourceSegments_File(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	oldSegmentFile := batchSegmentFile
+	defer func() { batchSegmentFile = oldSegmentFile }()
+	var src, seglen string
+	batchSegmentFile = func(in, outp, tmpl, segLen string, segStart int) error {
+		src, seglen = in, segLen
+		for i := 0; i < 2; i++ {
+			if err := ioutil.WriteFile(fmt.Sprintf(tmpl, i), []byte{byte(i)}, 0600); err != nil {
+				return err
+			}
+		}
+		return ioutil.WriteFile(outp, []byte("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:2\n#EXTINF:2.000,\nsource_0.ts\n#EXTINF:1.500,\nsource_1.ts\n#EXT-X-ENDLIST\n"), 0600)
+	}
+
+	// Files are segmented into the work dir and their segments are read from there
+	workDir := filepath.Join(t.TempDir(), "batch", "movie")
+	segs, err := batchSourceSegments(context.Background(), "https://source/movie.mp4", workDir)
+	require.Nil(err)
+	assert.Equal("https://source/movie.mp4", src)
+	assert.Equal("2.000000", seglen)
+	assert.Equal([]batchSegment{
+		{url: filepath.Join(workDir, "source_0.ts"), duration: 2, local: true},
+		{url: filepath.Join(workDir, "source_1.ts"), duration: 1.5, local: true},
+	}, segs)
+	data, err := segs[1].data(context.Background())
+	assert.Nil(err)
+	assert.Equal([]byte{1}, data)
+
+	batchSegmentFile = func(in, outp, tmpl, segLen string, segStart int) error { return errors.New("no video") }
+	_, err = batchSourceSegments(context.Background(), "https://source/movie.mp4", workDir)
+	assert.EqualError(err, "error segmenting source: no video")
+}
+
+func TestBatchHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	drivers.Testing = true
+	oldDownloadSeg, oldMaxAttempts, oldBackoff := downloadSeg, batchMaxAttempts, batchRetryBackoff
+	defer func() { downloadSeg, batchMaxAttempts, batchRetryBackoff = oldDownloadSeg, oldMaxAttempts, oldBackoff }()
+	downloadSeg = func(ctx context.Context, url string) ([]byte, error) { return nil, errors.New("not found") }
+	batchMaxAttempts, batchRetryBackoff = 2, time.Millisecond
+
+	results := make(chan *batchAssetResult, 2)
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var res batchAssetResult
+		assert.Nil(json.NewDecoder(r.Body).Decode(&res))
+		results <- &res
+	}))
+	defer callback.Close()
+
+	node, _ := core.NewLivepeerNode(nil, "", nil)
+	handler := batchHandler(&LivepeerServer{LivepeerNode: node})
+
+	resp := httpGetResp(handler)
+	assert.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+
+	// The queue of batch jobs is kept in the DB
+	resp = httpPostResp(handler, strings.NewReader(`{"assets":[]}`), nil)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+	node.Database = dbh
+
+	resp = httpPostResp(handler, strings.NewReader(`{"assets":[]}`), nil)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("missing assets", strings.TrimSpace(string(body)))
+
+	// The result of each asset is posted to the callback URL, including failures
+	req := `{"assets":[{"id":"a","url":"https://source/a.m3u8"},{"id":"b","url":"https://source/b.m3u8"}],
+		"objectStore":"memory://batch","callbackUrl":"` + callback.URL + `"}`
+	resp = httpPostResp(handler, strings.NewReader(req), nil)
+	body, _ = ioutil.ReadAll(resp.Body)
+	require.Equal(http.StatusAccepted, resp.StatusCode)
+	var started struct {
+		ID string `json:"id"`
+	}
+	require.Nil(json.Unmarshal(body, &started))
+	assert.NotEmpty(started.ID)
+
+	for _, id := range []string{"a", "b"} {
+		select {
+		case res := <-results:
+			assert.Equal(started.ID, res.BatchID)
+			assert.Equal(id, res.AssetID)
+			assert.Equal(common.BatchAssetFailed, res.Status)
+			assert.Equal("error downloading source playlist: not found", res.Error)
+			assert.Empty(res.Playlist)
+		case <-time.After(5 * time.Second):
+			require.Fail("timed out waiting for callback")
+		}
+	}
+
+	// Failed assets are retried before they fail
+	assets, err := dbh.BatchAssets(&common.DBBatchAssetFilter{BatchID: started.ID})
+	require.Nil(err)
+	require.Len(assets, 2)
+	for _, a := range assets {
+		assert.Equal(common.BatchAssetFailed, a.State)
+		assert.Equal(2, a.Attempts)
+		assert.Equal("error downloading source playlist: not found", a.Error)
+	}
+}
+
+func TestBatchJobs_ListCancelResume(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	drivers.Testing = true
+	oldDownloadSeg := downloadSeg
+	defer func() { downloadSeg = oldDownloadSeg }()
+	// Downloads block until the job is cancelled
+	downloading := make(chan string, 1)
+	downloadSeg = func(ctx context.Context, url string) ([]byte, error) {
+		downloading <- url
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	results := make(chan *batchAssetResult, 1)
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var res batchAssetResult
+		assert.Nil(json.NewDecoder(r.Body).Decode(&res))
+		results <- &res
+	}))
+	defer callback.Close()
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+	node, _ := core.NewLivepeerNode(nil, "", dbh)
+	s := &LivepeerServer{LivepeerNode: node}
+	oldNodeID := monitor.NodeID
+	defer func() { monitor.NodeID = oldNodeID }()
+	monitor.NodeID = "node"
+
+	// Unfinished assets of this node are resumed in order, the ones of other nodes and finished ones are not
+	now := time.Now()
+	queued := func(batchID, assetID string, position int, nodeID, state string) *common.DBBatchAsset {
+		return &common.DBBatchAsset{BatchID: batchID, AssetID: assetID, Position: position, NodeID: nodeID, URL: "https://source/" + assetID + ".m3u8",
+			Profiles: `[{"Name":"P240p30fps16x9"}]`, ObjectStore: "memory://batch", CallbackURL: callback.URL, State: state, CreatedAt: now, UpdatedAt: now}
+	}
+	require.Nil(dbh.InsertBatchAsset(queued("job", "done", 0, monitor.NodeID, common.BatchAssetCompleted)))
+	require.Nil(dbh.InsertBatchAsset(queued("job", "a", 1, monitor.NodeID, common.BatchAssetTranscoding)))
+	require.Nil(dbh.InsertBatchAsset(queued("job", "b", 2, monitor.NodeID, common.BatchAssetQueued)))
+	require.Nil(dbh.InsertBatchAsset(queued("other", "c", 0, "other", common.BatchAssetQueued)))
+	require.Nil(s.ResumeBatchJobs())
+
+	select {
+	case url := <-downloading:
+		assert.Equal("https://source/a.m3u8", url)
+	case <-time.After(5 * time.Second):
+		require.Fail("timed out waiting for resumed job")
+	}
+
+	list := batchJobsHandler(dbh)
+	resp := httpPostFormResp(list, strings.NewReader("id=job"))
+	body, _ := ioutil.ReadAll(resp.Body)
+	require.Equal(http.StatusOK, resp.StatusCode)
+	var jobAssets []batchJobAsset
+	require.Nil(json.Unmarshal(body, &jobAssets))
+	require.Len(jobAssets, 3)
+	assert.Equal("done", jobAssets[0].AssetID)
+	assert.Equal(common.BatchAssetCompleted, jobAssets[0].State)
+	assert.Equal("a", jobAssets[1].AssetID)
+	assert.Equal(common.BatchAssetTranscoding, jobAssets[1].State)
+	assert.Equal(1, jobAssets[1].Attempts)
+	assert.Equal("b", jobAssets[2].AssetID)
+	assert.Equal(common.BatchAssetQueued, jobAssets[2].State)
+
+	resp = httpPostFormResp(list, strings.NewReader("state=queued"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	require.Nil(json.Unmarshal(body, &jobAssets))
+	assert.Len(jobAssets, 2)
+
+	// Cancelling stops the running asset and skips the remaining ones without posting results
+	cancel := cancelBatchJobHandler(s)
+	resp = httpPostFormResp(cancel, strings.NewReader("id=job"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("cancelled 2 assets", string(body))
+
+	require.Eventually(func() bool {
+		s.batchLock.Lock()
+		defer s.batchLock.Unlock()
+		return len(s.batchJobs) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	select {
+	case res := <-results:
+		assert.Fail("unexpected result", "asset=%s", res.AssetID)
+	default:
+	}
+	assets, err := dbh.BatchAssets(&common.DBBatchAssetFilter{BatchID: "job", States: []string{common.BatchAssetCancelled}})
+	require.Nil(err)
+	assert.Len(assets, 2)
+
+	resp = httpPostFormResp(cancel, strings.NewReader("id=job"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("no unfinished batch job id=job", strings.TrimSpace(string(body)))
+}
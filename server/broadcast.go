@@ -3,6 +3,7 @@ package server
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,11 +19,13 @@ import (
 	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
 
 	"github.com/livepeer/go-livepeer/clog"
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/core"
 	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/lperrors"
 	"github.com/livepeer/go-livepeer/monitor"
 	"github.com/livepeer/go-livepeer/net"
 	"github.com/livepeer/go-livepeer/pm"
@@ -41,18 +44,27 @@ var maxDurationSec = common.MaxDuration.Seconds()
 var maxRefreshSessionsThreshold = 8.0
 
 var Policy *verification.Policy
+
+// QualityScorer scores a sample of the transcoded renditions against the source. Nil disables quality scoring
+var QualityScorer *verification.QualityScorer
+
 var BroadcastCfg = &BroadcastConfig{}
 var MaxAttempts = 3
 
+// SourceBitrateFactor caps the bitrate of each rendition at the bitrate of the source segment multiplied by the
+// factor so that low bitrate sources are not inflated into large renditions. 0 disables the cap
+var SourceBitrateFactor = 0.0
+
 var MetadataQueue event.Producer
 var MetadataPublishTimeout = 1 * time.Second
 
-var getOrchestratorInfoRPC = GetOrchestratorInfo
+var getOrchestratorInfoRPC = refreshOrchestratorInfo
 var downloadSeg = drivers.GetSegmentData
 
 type BroadcastConfig struct {
-	maxPrice *big.Rat
-	mu       sync.RWMutex
+	maxPrice          *big.Rat
+	maxDetectionPrice *big.Int
+	mu                sync.RWMutex
 }
 
 type SegFlightMetadata struct {
@@ -76,6 +88,19 @@ func (cfg *BroadcastConfig) SetMaxPrice(price *big.Rat) {
 	}
 }
 
+// MaxDetectionPrice returns the max price in wei per second of video to run detection on segments without renditions
+func (cfg *BroadcastConfig) MaxDetectionPrice() *big.Int {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.maxDetectionPrice
+}
+
+func (cfg *BroadcastConfig) SetMaxDetectionPrice(price *big.Int) {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	cfg.maxDetectionPrice = price
+}
+
 type sessionsCreator func() ([]*BroadcastSession, error)
 type SessionPool struct {
 	mid core.ManifestID
@@ -94,6 +119,7 @@ type SessionPool struct {
 
 	createSessions sessionsCreator
 	sus            *suspender
+	events         *streamEventLog
 }
 
 func NewSessionPool(mid core.ManifestID, poolSize, numOrchs int, sus *suspender, createSession sessionsCreator,
@@ -278,6 +304,7 @@ func (sp *SessionPool) selectSessions(ctx context.Context, sessionsNum int) []*B
 				if monitor.Enabled {
 					monitor.OrchestratorSwapped(ctx)
 				}
+				sp.events.record(streamEventOrchSwitched, map[string]interface{}{"from": sess.Transcoder()})
 			}
 		}
 	}
@@ -285,6 +312,9 @@ func (sp *SessionPool) selectSessions(ctx context.Context, sessionsNum int) []*B
 		// No session found, return nil
 		sp.lastSess = nil
 	} else {
+		if len(sp.lastSess) == 0 {
+			sp.events.record(streamEventOrchSelected, map[string]interface{}{"orchestrators": getOrchs(selectedSessions)})
+		}
 		for _, ls := range sp.lastSess {
 			if !includesSession(selectedSessions, ls) {
 				clog.V(common.DEBUG).Infof(ctx, "Swapping from orch=%v to orch=%+v for manifestID=%s", ls.Transcoder(),
@@ -292,6 +322,7 @@ func (sp *SessionPool) selectSessions(ctx context.Context, sessionsNum int) []*B
 				if monitor.Enabled {
 					monitor.OrchestratorSwapped(ctx)
 				}
+				sp.events.record(streamEventOrchSwitched, map[string]interface{}{"from": ls.Transcoder(), "to": getOrchs(selectedSessions)})
 			}
 		}
 		sp.lastSess = append([]*BroadcastSession{}, selectedSessions...)
@@ -306,6 +337,14 @@ func (sp *SessionPool) removeSession(session *BroadcastSession) {
 	delete(sp.sessMap, session.Transcoder())
 }
 
+// activeSessions returns the sessions that were used for the last segment
+func (sp *SessionPool) activeSessions() []*BroadcastSession {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	return append([]*BroadcastSession{}, sp.lastSess...)
+}
+
 func (sp *SessionPool) cleanup() {
 	sp.lock.Lock()
 	defer sp.lock.Unlock()
@@ -346,11 +385,18 @@ type BroadcastSessionsManager struct {
 	sessLock sync.Mutex
 
 	finished bool // set at stream end
+	// done is closed at stream end to stop the keepalive loop
+	done chan struct{}
 
 	trustedPool   *SessionPool
 	untrustedPool *SessionPool
 
 	verifiedSession *BroadcastSession
+
+	// latencies tracks the end-to-end latencies of the orchestrators for LatencyBudget
+	latencies *latencyTracker
+
+	events *streamEventLog
 }
 
 func NewSessionManager(ctx context.Context, node *core.LivepeerNode, params *core.StreamParameters, sel BroadcastSessionsSelectorFactory) *BroadcastSessionsManager {
@@ -374,17 +420,61 @@ func NewSessionManager(ctx context.Context, node *core.LivepeerNode, params *cor
 	if node.Eth != nil {
 		stakeRdr = &storeStakeReader{store: node.Database}
 	}
+	var trustedSel, untrustedSel BroadcastSessionsSelector
+	if SelectionPolicy != nil {
+		trustedSel = NewPolicySelector(SelectionPolicy, params.ManifestID, NewMinLSSelector(stakeRdr, 1.0))
+		untrustedSel = NewPolicySelector(SelectionPolicy, params.ManifestID, NewMinLSSelectorWithRandFreq(stakeRdr, 1.0, SelectRandFreq))
+	} else {
+		trustedSel = NewMinLSSelector(stakeRdr, 1.0)
+		untrustedSel = NewMinLSSelectorWithRandFreq(stakeRdr, 1.0, SelectRandFreq)
+	}
 	bsm := &BroadcastSessionsManager{
 		mid:              params.ManifestID,
 		VerificationFreq: params.VerificationFreq,
-		trustedPool:      NewSessionPool(params.ManifestID, int(trustedPoolSize), trustedNumOrchs, susTrusted, createSessionsTrusted, NewMinLSSelector(stakeRdr, 1.0)),
-		untrustedPool:    NewSessionPool(params.ManifestID, int(untrustedPoolSize), untrustedNumOrchs, susUntrusted, createSessionsUntrusted, NewMinLSSelectorWithRandFreq(stakeRdr, 1.0, SelectRandFreq)),
-	}
+		trustedPool:      NewSessionPool(params.ManifestID, int(trustedPoolSize), trustedNumOrchs, susTrusted, createSessionsTrusted, trustedSel),
+		untrustedPool:    NewSessionPool(params.ManifestID, int(untrustedPoolSize), untrustedNumOrchs, susUntrusted, createSessionsUntrusted, untrustedSel),
+		latencies:        newLatencyTracker(),
+		events:           newStreamEventLog(node, params.ManifestID),
+		done:             make(chan struct{}),
+	}
+	bsm.trustedPool.events = bsm.events
+	bsm.untrustedPool.events = bsm.events
 	bsm.trustedPool.refreshSessions(ctx)
 	bsm.untrustedPool.refreshSessions(ctx)
+	// The stream can outlive ctx i.e. for HTTP push so the loop only stops once the stream ends
+	go bsm.keepaliveLoop(clog.Clone(context.Background(), ctx))
 	return bsm
 }
 
+// keepaliveLoop periodically sends keepalives for the sessions in use so that orchestrators can
+// tear down the sessions quickly if the broadcaster disappears without ending the stream
+func (bsm *BroadcastSessionsManager) keepaliveLoop(ctx context.Context) {
+	ticker := time.NewTicker(core.KeepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bsm.done:
+			return
+		case <-ticker.C:
+		}
+
+		bsm.sessLock.Lock()
+		if bsm.finished {
+			bsm.sessLock.Unlock()
+			return
+		}
+		sessions := append(bsm.trustedPool.activeSessions(), bsm.untrustedPool.activeSessions()...)
+		bsm.sessLock.Unlock()
+
+		for _, sess := range sessions {
+			if err := sendKeepalive(ctx, sess); err != nil {
+				clog.V(common.DEBUG).Infof(ctx, "Error sending keepalive orch=%s err=%q", sess.Transcoder(), err)
+			}
+		}
+	}
+}
+
 func (bsm *BroadcastSessionsManager) suspendAndRemoveOrch(sess *BroadcastSession) {
 	if sess.OrchestratorScore == common.Score_Untrusted {
 		bsm.untrustedPool.suspend(sess.OrchestratorInfo.GetTranscoder())
@@ -461,6 +551,9 @@ func (bsm *BroadcastSessionsManager) selectSessions(ctx context.Context) ([]*Bro
 func (bsm *BroadcastSessionsManager) cleanup() {
 	bsm.sessLock.Lock()
 	defer bsm.sessLock.Unlock()
+	if !bsm.finished && bsm.done != nil {
+		close(bsm.done)
+	}
 	bsm.finished = true
 
 	bsm.trustedPool.cleanup()
@@ -632,6 +725,23 @@ func selectOrchestrator(ctx context.Context, n *core.LivepeerNode, params *core.
 			continue
 		}
 
+		if params.MaxPrice != nil {
+			price, err := common.RatPriceInfo(tinfo.PriceInfo)
+			if err == nil && price != nil && price.Cmp(params.MaxPrice) > 0 {
+				clog.V(common.DEBUG).Infof(ctx, "Orchestrator price too high for stream orch=%v price=%v maxPrice=%v",
+					tinfo.Transcoder, price.FloatString(3), params.MaxPrice.FloatString(3))
+				continue
+			}
+		}
+
+		if maxDetectionPrice := BroadcastCfg.MaxDetectionPrice(); maxDetectionPrice != nil && params.DetectionOnly() {
+			if price := big.NewInt(tinfo.GetDetectionPricePerSecond()); price.Cmp(maxDetectionPrice) > 0 {
+				clog.V(common.DEBUG).Infof(ctx, "Orchestrator detection price too high for stream orch=%v price=%v maxDetectionPrice=%v",
+					tinfo.Transcoder, price, maxDetectionPrice)
+				continue
+			}
+		}
+
 		if n.Sender != nil {
 			if tinfo.TicketParams == nil {
 				clog.Errorf(ctx, "Missing ticket params orch=%v", tinfo.Transcoder)
@@ -679,7 +789,6 @@ func selectOrchestrator(ctx context.Context, n *core.LivepeerNode, params *core.
 
 func processSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSegment) ([]string, error) {
 
-	rtmpStrm := cxn.stream
 	nonce := cxn.nonce
 	cpl := cxn.pl
 	mid := cxn.mid
@@ -691,6 +800,20 @@ func processSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSeg
 	}
 
 	clog.V(common.DEBUG).Infof(ctx, "Processing segment dur=%v bytes=%v", seg.Duration, len(seg.Data))
+	if cxn.splices != nil {
+		// SCTE-35 cues of the source are passed on to the media playlists of all renditions
+		if cue := cxn.splices.Next(core.ParseSCTE35(seg.Data), seg.Duration); cue != nil {
+			clog.V(common.DEBUG).Infof(ctx, "Cueing segment cueType=%v", cue.CueType)
+			cpl.CueHLSSegment(seg.SeqNo, cue)
+		}
+	}
+	// ID3 timed metadata of the source is muxed into the renditions by the transcoder and announced in the playlists
+	if tm := core.ParseTimedMetadata(seg.Data); tm != nil {
+		start := time.Now().UTC()
+		for i, tag := range tm.Tags {
+			cpl.TagHLSSegment(seg.SeqNo, id3DateRange(seg, i, start, tag))
+		}
+	}
 	if monitor.Enabled {
 		monitor.SegmentEmerged(ctx, nonce, seg.SeqNo, len(BroadcastJobVideoProfiles), seg.Duration)
 	}
@@ -779,14 +902,31 @@ func processSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSeg
 	}
 
 	var sv *verification.SegmentVerifier
-	if Policy != nil {
+	if cxn.params != nil && cxn.params.DetectionOnly() {
+		// Detection-only streams have no renditions to transcode, so only the segments sampled for detection are
+		// submitted and there are no results to verify
+		if seg.SeqNo%uint64(cxn.params.Detection.Freq) != 0 {
+			return nil, nil
+		}
+	} else if Policy != nil {
 		sv = verification.NewSegmentVerifier(Policy)
 	}
 
+	if len(cxn.groups) > 0 {
+		return transcodeOutputGroups(ctx, cxn, seg, name)
+	}
+	return transcodeSegmentWithRetries(ctx, cxn, seg, name, sv)
+}
+
+// transcodeSegmentWithRetries transcodes seg with the sessions of cxn and retries up to MaxAttempts times
+func transcodeSegmentWithRetries(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSegment, name string,
+	sv *verification.SegmentVerifier) ([]string, error) {
+
 	var (
 		startTime = time.Now()
 		attempts  []data.TranscodeAttemptInfo
 		urls      []string
+		err       error
 	)
 	for len(attempts) < MaxAttempts {
 		// if transcodeSegment fails, retry; rudimentary
@@ -796,14 +936,20 @@ func processSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSeg
 		if err == nil {
 			break
 		}
+		cxn.sessManager.events.record(streamEventSegmentFailed, map[string]interface{}{
+			"seqNo":        seg.SeqNo,
+			"attempt":      len(attempts),
+			"orchestrator": info.Orchestrator.TranscoderUri,
+			"error":        err.Error(),
+		})
 
 		if shouldStopStream(err) {
 			clog.Warningf(ctx, "Stopping current stream due to err=%q", err)
-			rtmpStrm.Close()
+			cxn.inputStream().Close()
 			break
 		}
 		if isNonRetryableError(err) {
-			clog.Warningf(ctx, "Not retrying current segment due to non-retryable error err=%q", err)
+			clog.Warningf(ctx, "Not retrying current segment due to non-retryable error class=%s err=%q", lperrors.Class(err), err)
 			break
 		}
 		if ctxErr := ctx.Err(); ctxErr != nil {
@@ -815,11 +961,8 @@ func processSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSeg
 	}
 	if MetadataQueue != nil {
 		success := err == nil && len(urls) > 0
-		streamID := string(mid)
-		if cxn.params != nil && cxn.params.ExternalStreamID != "" {
-			streamID = cxn.params.ExternalStreamID
-		}
-		key := newTranscodeEventKey(mid, streamID)
+		streamID := cxn.streamHealthID()
+		key := newTranscodeEventKey(cxn.mid, streamID)
 		evt := newTranscodeEvent(streamID, seg, startTime, success, attempts)
 		go func() {
 			ctx, cancel := context.WithTimeout(context.Background(), MetadataPublishTimeout)
@@ -830,7 +973,7 @@ func processSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSeg
 		}()
 	}
 	if len(attempts) == MaxAttempts && err != nil {
-		err = fmt.Errorf("Hit max transcode attempts: %w", err)
+		err = lperrors.Fatal(fmt.Errorf("Hit max transcode attempts: %w", err))
 	}
 	return urls, err
 }
@@ -842,13 +985,14 @@ func transcodeSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSS
 	info := &data.TranscodeAttemptInfo{}
 	var err error
 
-	defer func(startTime time.Time) {
+	startTime := time.Now()
+	defer func() {
 		info.LatencyMs = time.Since(startTime).Milliseconds()
 		if err != nil {
 			errStr := err.Error()
 			info.Error = &errStr
 		}
-	}(time.Now())
+	}()
 
 	nonce := cxn.nonce
 	sessions, calcPerceptualHash, verified := cxn.sessManager.selectSessions(ctx)
@@ -894,54 +1038,13 @@ func transcodeSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSS
 			}
 			return nil, info, err
 		}
-		// [EXPERIMENTAL] send content detection results to callback webhook
+		// [EXPERIMENTAL] send content detection results to callback webhook and tag the segment in the playlists
 		// for now use detection only in common path
-		if DetectionWebhookURL != nil && len(res.Detections) > 0 {
-			clog.V(common.DEBUG).Infof(ctx, "Got detection result %v", res.Detections)
-			go func(mid core.ManifestID, config core.DetectionConfig, seqNo uint64, detections []*net.DetectData) {
-				req := common.DetectionWebhookRequest{ManifestID: string(mid), SeqNo: seqNo}
-				for _, detection := range detections {
-					switch x := detection.Value.(type) {
-					case *net.DetectData_SceneClassification:
-						probs := x.SceneClassification.ClassProbs
-						// match returned probs (key: class id) with one of the user-selected class names
-						for _, name := range config.SelectedClassNames {
-							if id, ok := ffmpeg.DetectorClassIDLookup[name]; ok {
-								if prob, ok := probs[uint32(id)]; ok {
-									req.SceneClassification = append(req.SceneClassification,
-										common.SceneClassificationResult{
-											Name:        name,
-											Probability: prob,
-										})
-								}
-							}
-						}
-					}
-				}
-				jsonValue, err := json.Marshal(req)
-				if err != nil {
-					clog.Errorf(ctx, "Unable to marshal detection result into JSON ")
-					return
-				}
-				resp, err := DetectionWhClient.Post(DetectionWebhookURL.String(), "application/json", bytes.NewBuffer(jsonValue))
-				if err != nil {
-					clog.Errorf(ctx, "Unable to POST detection result on webhook url=%v err=%q",
-						DetectionWebhookURL.Redacted(), err)
-				} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-					rbody, rerr := ioutil.ReadAll(resp.Body)
-					resp.Body.Close()
-					if rerr != nil {
-						clog.Errorf(ctx, "Detection webhook returned error status=%v with unreadable body err=%q",
-							resp.StatusCode, rerr)
-					} else {
-						clog.Errorf(ctx, "Detection webhook returned error status=%v err=%q",
-							resp.StatusCode, string(rbody))
-					}
-				}
-			}(cxn.mid, cxn.params.Detection, seg.SeqNo, res.Detections)
+		if len(res.Detections) > 0 {
+			handleDetections(ctx, cxn, seg, res.Detections)
 		}
 		// Ensure perceptual hash is generated if we ask for it
-		if calcPerceptualHash {
+		if calcPerceptualHash && len(res.Segments) > 0 {
 			segmToCheckIndex := rand.Intn(len(res.Segments))
 			segHash, err := drivers.GetSegmentData(ctx, res.Segments[segmToCheckIndex].PerceptualHashUrl)
 			if err != nil || len(segHash) <= 0 {
@@ -951,6 +1054,9 @@ func transcodeSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSS
 			}
 		}
 		urls, err = downloadResults(ctx, cxn, seg, sess, res, verifier)
+		if err == nil {
+			cxn.sessManager.trackLatency(ctx, sess, time.Since(startTime))
+		}
 		return urls, info, err
 	} else {
 		resc := make(chan *SubmitResult, len(sessions))
@@ -983,10 +1089,112 @@ func transcodeSegment(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSS
 		}
 
 		urls, err = downloadResults(ctx, cxn, seg, sess, results, verifier)
+		if err == nil {
+			cxn.sessManager.trackLatency(ctx, sess, time.Since(startTime))
+		}
 		return urls, info, err
 	}
 }
 
+// handleDetections reports the detections of the selected classes with a probability above the threshold of the
+// stream to the detection webhook and tags the segment in the playlists if requested. Stream level classifications
+// that start or stop with the segment are reported with the detections
+func handleDetections(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSegment, detections []*net.DetectData) {
+	clog.V(common.DEBUG).Infof(ctx, "Got detection result %v", detections)
+	config := cxn.params.Detection
+	req := common.DetectionWebhookRequest{ManifestID: string(cxn.mid), SeqNo: seg.SeqNo}
+	classProbs := make(map[string]float64)
+	for _, detection := range detections {
+		switch x := detection.Value.(type) {
+		case *net.DetectData_SceneClassification:
+			probs := x.SceneClassification.ClassProbs
+			// match returned probs (key: class id) with one of the user-selected class names
+			for _, name := range config.SelectedClassNames {
+				if id, ok := ffmpeg.DetectorClassIDLookup[name]; ok {
+					prob, ok := probs[uint32(id)]
+					if !ok {
+						continue
+					}
+					classProbs[name] = prob
+					if prob >= config.Threshold {
+						req.SceneClassification = append(req.SceneClassification,
+							common.SceneClassificationResult{
+								Name:        name,
+								Probability: prob,
+							})
+					}
+				}
+			}
+		}
+	}
+	if cxn.detections != nil {
+		req.Events = cxn.detections.Add(seg.SeqNo, classProbs)
+		for _, evt := range req.Events {
+			clog.Infof(ctx, "Detection event class=%s type=%s probability=%v", evt.Class, evt.Type, evt.Probability)
+		}
+	}
+	if config.Threshold > 0 && len(req.SceneClassification) == 0 && len(req.Events) == 0 {
+		return
+	}
+
+	if config.TagPlaylist && len(req.SceneClassification) > 0 {
+		cxn.pl.TagHLSSegment(seg.SeqNo, detectionDateRange(seg, req.SceneClassification))
+	}
+
+	if DetectionWebhookURL != nil {
+		go postDetectionWebhook(ctx, req)
+	}
+}
+
+// detectionDateRange returns the EXT-X-DATERANGE tag for a segment with detections
+func detectionDateRange(seg *stream.HLSSegment, results []common.SceneClassificationResult) *core.DateRange {
+	classes := make([]string, len(results))
+	for i, r := range results {
+		classes[i] = fmt.Sprintf("%s:%.3f", r.Name, r.Probability)
+	}
+	return &core.DateRange{
+		ID:         fmt.Sprintf("detection-%d", seg.SeqNo),
+		Class:      "io.livepeer.detection",
+		StartDate:  time.Now().UTC(),
+		Duration:   seg.Duration,
+		Attributes: map[string]string{"scene-classification": strings.Join(classes, ",")},
+	}
+}
+
+// id3DateRange returns the EXT-X-DATERANGE tag for the i-th ID3 tag of a segment that started at start
+func id3DateRange(seg *stream.HLSSegment, i int, start time.Time, tag core.ID3Tag) *core.DateRange {
+	return &core.DateRange{
+		ID:         fmt.Sprintf("id3-%d-%d", seg.SeqNo, i),
+		Class:      "io.livepeer.id3",
+		StartDate:  start.Add(tag.Offset),
+		Offset:     tag.Offset,
+		Attributes: map[string]string{"id3": base64.StdEncoding.EncodeToString(tag.Data)},
+	}
+}
+
+func postDetectionWebhook(ctx context.Context, req common.DetectionWebhookRequest) {
+	jsonValue, err := json.Marshal(req)
+	if err != nil {
+		clog.Errorf(ctx, "Unable to marshal detection result into JSON ")
+		return
+	}
+	resp, err := DetectionWhClient.Post(DetectionWebhookURL.String(), "application/json", bytes.NewBuffer(jsonValue))
+	if err != nil {
+		clog.Errorf(ctx, "Unable to POST detection result on webhook url=%v err=%q",
+			DetectionWebhookURL.Redacted(), err)
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		rbody, rerr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if rerr != nil {
+			clog.Errorf(ctx, "Detection webhook returned error status=%v with unreadable body err=%q",
+				resp.StatusCode, rerr)
+		} else {
+			clog.Errorf(ctx, "Detection webhook returned error status=%v err=%q",
+				resp.StatusCode, string(rbody))
+		}
+	}
+}
+
 type SubmitResult struct {
 	Session         *BroadcastSession
 	TranscodeResult *ReceivedTranscodeResult
@@ -1060,6 +1268,7 @@ func downloadResults(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSe
 		}
 	}
 	cpl := cxn.pl
+	scoreQuality := QualityScorer != nil && QualityScorer.ShouldSample()
 
 	var dlErr error
 	segData := make([][]byte, len(res.Segments))
@@ -1087,7 +1296,8 @@ func downloadResults(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSe
 		// Download segment data in the following cases:
 		// - A verification policy is set. The segment data is needed for signature verification and/or pixel count verification
 		// - The segment data needs to be uploaded to the broadcaster's own OS
-		if verifier != nil || bros != nil || bos != nil && !bos.IsOwn(url) {
+		// - The segment was sampled for quality scoring
+		if verifier != nil || scoreQuality || bros != nil || bos != nil && !bos.IsOwn(url) {
 			d, err := downloadSeg(ctx, url)
 			if err != nil {
 				errFunc(monitor.SegmentTranscodeErrorDownload, url, err)
@@ -1188,6 +1398,11 @@ func downloadResults(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSe
 	if verifier != nil {
 		// verify potentially can change content of segURLs
 		err := verify(verifier, cxn, sess, seg, res.TranscodeData, segURLs, segData)
+		result := map[string]interface{}{"seqNo": seg.SeqNo, "orchestrator": sess.Transcoder(), "passed": err == nil}
+		if err != nil {
+			result["error"] = err.Error()
+		}
+		cxn.sessManager.events.record(streamEventVerification, result)
 		if err != nil {
 			clog.Errorf(ctx, "Error verifying nonce=%d manifestID=%s seqNo=%d err=%q", nonce, cxn.mid, seg.SeqNo, err)
 			return nil, err
@@ -1212,11 +1427,15 @@ func downloadResults(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSe
 		monitor.SegmentFullyTranscoded(ctx, nonce, seg.SeqNo, common.ProfilesNames(sess.Params.Profiles), errCode)
 	}
 
+	if scoreQuality {
+		go scoreRenditionQuality(ctx, cxn, sess, seg, segData)
+	}
+
 	clog.V(common.DEBUG).Infof(ctx, "Successfully validated segment")
 	return segURLs, nil
 }
 
-var sessionErrStrings = []string{"dial tcp", "unexpected EOF", core.ErrOrchBusy.Error(), core.ErrOrchCap.Error()}
+var sessionErrStrings = []string{"dial tcp", "unexpected EOF", core.ErrOrchBusy.Error(), core.ErrOrchCap.Error(), core.ErrStreamLimit.Error(), core.ErrOrchMaintenance.Error()}
 
 var sessionErrRegex = common.GenErrRegex(sessionErrStrings)
 
@@ -1306,6 +1525,19 @@ func updateSession(sess *BroadcastSession, res *ReceivedTranscodeResult) {
 
 	oInfo := res.Info
 	oldInfo := sess.OrchestratorInfo
+	// The info must be signed by the orchestrator that the session was created with
+	var expected ethcommon.Address
+	if oldInfo != nil && len(oldInfo.Address) > 0 {
+		expected = ethcommon.BytesToAddress(oldInfo.Address)
+	}
+	var bcast ethcommon.Address
+	if sess.Broadcaster != nil {
+		bcast = sess.Broadcaster.Address()
+	}
+	if err := verifyOrchestratorInfo(oInfo, bcast, expected, time.Now()); err != nil {
+		clog.Errorf(context.Background(), "Ignoring invalid orchestrator info orch=%v err=%q", oInfo.Transcoder, err)
+		return
+	}
 	sess.OrchestratorInfo = oInfo
 
 	if len(oInfo.Storage) > 0 {
@@ -1336,7 +1568,10 @@ func refreshSession(ctx context.Context, sess *BroadcastSession) error {
 	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
 	defer cancel()
 
-	oInfo, err := getOrchestratorInfoRPC(ctx, sess.Broadcaster, uri)
+	sess.lock.RLock()
+	authToken := sess.OrchestratorInfo.GetAuthToken()
+	sess.lock.RUnlock()
+	oInfo, err := getOrchestratorInfoRPC(ctx, sess.Broadcaster, uri, authToken)
 	if err != nil {
 		return err
 	}
@@ -1385,6 +1620,65 @@ func shouldRefreshSession(ctx context.Context, sess *BroadcastSession) (bool, er
 	return false, nil
 }
 
+// scoreRenditionQuality compares the renditions transcoded by sess to the source segment, records the scores and
+// suspends the orchestrator if any rendition is below the minimum quality
+func scoreRenditionQuality(ctx context.Context, cxn *rtmpConnection, sess *BroadcastSession, seg *stream.HLSSegment, segData [][]byte) {
+	orch := sess.Transcoder()
+	metric := string(QualityScorer.Metric)
+	var (
+		scores   []renditionQuality
+		rejected bool
+	)
+	for i, data := range segData {
+		if len(data) == 0 || i >= len(sess.Params.Profiles) {
+			continue
+		}
+		profile := sess.Params.Profiles[i].Name
+		score, err := QualityScorer.Score(seg.Data, data)
+		if err != nil {
+			clog.Errorf(ctx, "Error scoring rendition quality seqNo=%d profile=%s err=%q", seg.SeqNo, profile, err)
+			continue
+		}
+		clog.V(common.DEBUG).Infof(ctx, "Scored rendition quality seqNo=%d profile=%s orch=%s %s=%v", seg.SeqNo, profile, orch, metric, score)
+		if monitor.Enabled {
+			monitor.QualityScore(ctx, metric, profile, orch, score)
+		}
+		scores = append(scores, renditionQuality{Profile: profile, Score: score})
+		rejected = rejected || QualityScorer.BelowMin(score)
+	}
+	if len(scores) == 0 {
+		return
+	}
+
+	if rejected {
+		clog.Warningf(ctx, "Suspending orchestrator for renditions below the minimum quality orch=%s seqNo=%d %s=%+v min=%v",
+			orch, seg.SeqNo, metric, scores, QualityScorer.MinScore)
+		cxn.sessManager.suspendAndRemoveOrch(sess)
+		if monitor.Enabled {
+			monitor.QualityRejected(ctx, orch)
+		}
+	}
+
+	if MetadataQueue != nil {
+		streamID := cxn.streamHealthID()
+		key := newQualityEventKey(cxn.mid, streamID)
+		evt := newQualityEvent(streamID, seg, sess, metric, scores, rejected)
+		ctx, cancel := context.WithTimeout(context.Background(), MetadataPublishTimeout)
+		defer cancel()
+		if err := MetadataQueue.Publish(ctx, key, evt, false); err != nil {
+			clog.Errorf(ctx, "Error publishing stream quality event: err=%q key=%q event=%+v", err, key, evt)
+		}
+	}
+}
+
+// streamHealthID returns the ID of the stream used in stream health events
+func (cxn *rtmpConnection) streamHealthID() string {
+	if cxn.params != nil && cxn.params.ExternalStreamID != "" {
+		return cxn.params.ExternalStreamID
+	}
+	return string(cxn.mid)
+}
+
 func newTranscodeEventKey(mid core.ManifestID, streamID string) string {
 	shardKey := string(mid[0])
 	return fmt.Sprintf("stream_health.transcode.%s.%s", shardKey, streamID)
@@ -1400,6 +1694,54 @@ func newTranscodeEvent(streamID string, seg *stream.HLSSegment, startTime time.T
 	return data.NewTranscodeEvent(monitor.NodeID, streamID, segMeta, startTime, success, attempts)
 }
 
+const qualityEventType data.EventType = "quality"
+
+// qualityEvent reports the quality scores of the sampled renditions of a segment as part of the stream health
+type qualityEvent struct {
+	data.Base
+	NodeID       string                    `json:"nodeId"`
+	Segment      data.SegmentMetadata      `json:"segment"`
+	Orchestrator data.OrchestratorMetadata `json:"orchestrator"`
+	Metric       string                    `json:"metric"`
+	Scores       []renditionQuality        `json:"scores"`
+	Rejected     bool                      `json:"rejected"`
+}
+
+type renditionQuality struct {
+	Profile string  `json:"profile"`
+	Score   float64 `json:"score"`
+}
+
+func newQualityEventKey(mid core.ManifestID, streamID string) string {
+	shardKey := string(mid[0])
+	return fmt.Sprintf("stream_health.quality.%s.%s", shardKey, streamID)
+}
+
+func newQualityEvent(streamID string, seg *stream.HLSSegment, sess *BroadcastSession, metric string, scores []renditionQuality, rejected bool) *qualityEvent {
+	return &qualityEvent{
+		Base: data.Base{
+			Type_:      qualityEventType,
+			ID_:        uuid.New(),
+			Timestamp_: data.UnixMillisTime{Time: time.Now().UTC()},
+			StreamID_:  streamID,
+		},
+		NodeID: monitor.NodeID,
+		Segment: data.SegmentMetadata{
+			Name:     seg.Name,
+			SeqNo:    seg.SeqNo,
+			Duration: seg.Duration,
+			ByteSize: len(seg.Data),
+		},
+		Orchestrator: data.OrchestratorMetadata{
+			TranscoderUri: sess.Transcoder(),
+			Address:       sess.Address(),
+		},
+		Metric:   metric,
+		Scores:   scores,
+		Rejected: rejected,
+	}
+}
+
 func getSegDurMsString(seg *stream.HLSSegment) string {
 	return strconv.Itoa(int(seg.Duration * 1000))
 }
@@ -1415,13 +1757,13 @@ func nonRetryableErrMapInit() map[string]bool {
 var NonRetryableErrMap = nonRetryableErrMapInit()
 
 func isNonRetryableError(err error) bool {
+	if lperrors.IsFatal(err) {
+		return true
+	}
 	for e := err; e != nil; e = errors.Unwrap(e) {
 		if NonRetryableErrMap[e.Error()] {
 			return true
 		}
 	}
-	if strings.HasPrefix(err.Error(), "Hit max transcode attempts:") {
-		return true
-	}
 	return false
 }
@@ -2,11 +2,17 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -17,6 +23,7 @@ import (
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/core"
 	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/lperrors"
 	"github.com/livepeer/go-livepeer/net"
 	"github.com/livepeer/go-livepeer/pm"
 	"github.com/livepeer/go-livepeer/verification"
@@ -211,7 +218,11 @@ type stubPlaylistManager struct {
 	profile    ffmpeg.VideoProfile
 	uri        string
 	os         drivers.OSSession
-	lock       sync.Mutex
+	dateRanges map[uint64][]*core.DateRange
+	cues       map[uint64]*m3u8.SCTE
+	// seqNo of the last segment that follows a discontinuity
+	discontinuity uint64
+	lock          sync.Mutex
 }
 
 func (pm *stubPlaylistManager) ManifestID() core.ManifestID {
@@ -235,6 +246,30 @@ func (pm *stubPlaylistManager) GetHLSMediaPlaylist(rendition string) *m3u8.Media
 	return nil
 }
 
+func (pm *stubPlaylistManager) TagHLSSegment(seqNo uint64, dateRange *core.DateRange) {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	if pm.dateRanges == nil {
+		pm.dateRanges = make(map[uint64][]*core.DateRange)
+	}
+	pm.dateRanges[seqNo] = append(pm.dateRanges[seqNo], dateRange)
+}
+
+func (pm *stubPlaylistManager) CueHLSSegment(seqNo uint64, cue *m3u8.SCTE) {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	if pm.cues == nil {
+		pm.cues = make(map[uint64]*m3u8.SCTE)
+	}
+	pm.cues[seqNo] = cue
+}
+
+func (pm *stubPlaylistManager) DiscontinueHLSSegment(seqNo uint64) {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	pm.discontinuity = seqNo
+}
+
 func (pm *stubPlaylistManager) GetOSSession() drivers.OSSession {
 	return pm.os
 }
@@ -267,6 +302,8 @@ func TestStopSessionErrors(t *testing.T) {
 		"Unable to submit segment 5 Post https://127.0.0.1:8936/segment: dial tcp 127.0.0.1:8936: getsockopt: connection refused",
 		core.ErrOrchBusy.Error(),
 		core.ErrOrchCap.Error(),
+		core.ErrStreamLimit.Error(),
+		core.ErrOrchMaintenance.Error(),
 	}
 
 	// Sanity check that we're checking each failure case
@@ -292,6 +329,33 @@ func TestStopSessionErrors(t *testing.T) {
 
 }
 
+func TestIsNonRetryableError(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(isNonRetryableError(lperrors.Fatal(errors.New("some error"))))
+	assert.True(isNonRetryableError(lperrors.Fatal(fmt.Errorf("Hit max transcode attempts: %w", core.ErrOrchBusy))))
+	assert.False(isNonRetryableError(core.ErrOrchBusy))
+	assert.False(isNonRetryableError(errors.New("some error")))
+}
+
+func TestRemoteError(t *testing.T) {
+	assert := assert.New(t)
+
+	// Known orchestrator errors keep their class
+	err := remoteError(core.ErrOrchBusy.Error())
+	assert.Equal(core.ErrOrchBusy, err)
+	assert.True(lperrors.IsRetryable(err))
+
+	err = remoteError(core.ErrNoTranscodersAvailable.Error())
+	assert.Equal(core.ErrNoTranscodersAvailable, err)
+	assert.True(lperrors.IsRetryable(err))
+
+	// Unknown errors are unclassified
+	err = remoteError("100% unknown")
+	assert.EqualError(err, "100% unknown")
+	assert.False(lperrors.IsClassified(err))
+}
+
 func TestNewSessionManager(t *testing.T) {
 	n, _ := core.NewLivepeerNode(nil, "", nil)
 	assert := assert.New(t)
@@ -383,7 +447,7 @@ func TestSelectSession_MultipleInFlight2(t *testing.T) {
 	defer func() { getOrchestratorInfoRPC = oldGetOrchestratorInfoRPC }()
 
 	orchInfoCalled := 0
-	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL) (*net.OrchestratorInfo, error) {
+	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL, authToken *net.AuthToken) (*net.OrchestratorInfo, error) {
 		orchInfoCalled++
 		return successOrchInfoUpdate, nil
 	}
@@ -572,7 +636,7 @@ func TestTranscodeSegment_RefreshSession(t *testing.T) {
 	oldGetOrchestratorInfoRPC := getOrchestratorInfoRPC
 	defer func() { getOrchestratorInfoRPC = oldGetOrchestratorInfoRPC }()
 
-	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL) (*net.OrchestratorInfo, error) {
+	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL, authToken *net.AuthToken) (*net.OrchestratorInfo, error) {
 		return successOrchInfoUpdate, nil
 	}
 
@@ -978,6 +1042,165 @@ func TestProcessSegment_MetadataQueueTranscodeEvent(t *testing.T) {
 	assert.Zero(len(queue.C))
 }
 
+func TestHandleDetections(t *testing.T) {
+	assert := assert.New(t)
+
+	detections := []*net.DetectData{
+		{Value: &net.DetectData_SceneClassification{
+			SceneClassification: &net.SceneClassificationData{
+				ClassProbs: map[uint32]float64{0: 0.9, 1: 0.3},
+			},
+		}},
+	}
+	pl := &stubPlaylistManager{}
+	cxn := &rtmpConnection{
+		mid: "dummy1",
+		pl:  pl,
+		params: &core.StreamParameters{
+			Detection: core.DetectionConfig{SelectedClassNames: []string{"adult", "soccer"}, Threshold: 0.5},
+		},
+	}
+	seg := &stream.HLSSegment{SeqNo: 7, Duration: 2}
+
+	// Playlist tagging is disabled
+	handleDetections(context.Background(), cxn, seg, detections)
+	assert.Empty(pl.dateRanges)
+
+	// Only classes above the threshold are tagged
+	cxn.params.Detection.TagPlaylist = true
+	handleDetections(context.Background(), cxn, seg, detections)
+	require.Len(t, pl.dateRanges[7], 1)
+	dr := pl.dateRanges[7][0]
+	assert.NotNil(dr)
+	assert.Equal("detection-7", dr.ID)
+	assert.Equal("io.livepeer.detection", dr.Class)
+	assert.Equal(2.0, dr.Duration)
+	assert.Equal(map[string]string{"scene-classification": "adult:0.900"}, dr.Attributes)
+
+	// Nothing is reported if no class is above the threshold
+	cxn.params.Detection.Threshold = 0.95
+	handleDetections(context.Background(), cxn, &stream.HLSSegment{SeqNo: 8}, detections)
+	assert.Nil(pl.dateRanges[8])
+}
+
+func TestHandleDetections_Events(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	oldURL, oldClient := DetectionWebhookURL, DetectionWhClient
+	defer func() {
+		DetectionWebhookURL, DetectionWhClient = oldURL, oldClient
+	}()
+	reqs := make(chan common.DetectionWebhookRequest, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req common.DetectionWebhookRequest
+		require.Nil(json.NewDecoder(r.Body).Decode(&req))
+		reqs <- req
+	}))
+	defer ts.Close()
+	DetectionWebhookURL, _ = url.Parse(ts.URL)
+	DetectionWhClient = &http.Client{}
+
+	config := core.DetectionConfig{SelectedClassNames: []string{"adult"}, Threshold: 0.95, StartThreshold: 0.8, StopThreshold: 0.4, Smoothing: 1}
+	cxn := &rtmpConnection{
+		mid:        "dummy1",
+		pl:         &stubPlaylistManager{},
+		params:     &core.StreamParameters{Detection: config},
+		detections: core.NewDetectionAggregator(config),
+	}
+	detect := func(prob float64) []*net.DetectData {
+		return []*net.DetectData{{Value: &net.DetectData_SceneClassification{
+			SceneClassification: &net.SceneClassificationData{ClassProbs: map[uint32]float64{0: prob}},
+		}}}
+	}
+	receive := func() common.DetectionWebhookRequest {
+		select {
+		case req := <-reqs:
+			return req
+		case <-time.After(5 * time.Second):
+			require.Fail("timed out waiting for the detection webhook")
+		}
+		return common.DetectionWebhookRequest{}
+	}
+
+	// The start event is reported even though the segment is below the per segment threshold
+	handleDetections(context.Background(), cxn, &stream.HLSSegment{SeqNo: 1}, detect(0.9))
+	req := receive()
+	assert.Equal(uint64(1), req.SeqNo)
+	assert.Empty(req.SceneClassification)
+	assert.Equal([]common.DetectionEvent{{Class: "adult", Type: common.DetectionStart, Probability: 0.9, SeqNo: 1}}, req.Events)
+
+	// Nothing is reported while the classification is active
+	handleDetections(context.Background(), cxn, &stream.HLSSegment{SeqNo: 2}, detect(0.5))
+	assert.Equal([]string{"adult"}, cxn.detections.Active())
+
+	handleDetections(context.Background(), cxn, &stream.HLSSegment{SeqNo: 3}, detect(0.1))
+	req = receive()
+	assert.Equal(uint64(3), req.SeqNo)
+	assert.Equal([]common.DetectionEvent{{Class: "adult", Type: common.DetectionStop, Probability: 0.1, SeqNo: 3}}, req.Events)
+	assert.Empty(cxn.detections.Active())
+	assert.Len(reqs, 0)
+}
+
+func TestScoreRenditionQuality(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	oldQueue, oldScorer := MetadataQueue, QualityScorer
+	defer func() {
+		MetadataQueue, QualityScorer = oldQueue, oldScorer
+	}()
+	queue := producerChan{make(chan queueEvent, 1), nil}
+	MetadataQueue = queue
+
+	// The fake ffmpeg binary reports the content of the rendition as the SSIM score
+	dir, err := ioutil.TempDir("", "quality")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "ffmpeg")
+	require.Nil(ioutil.WriteFile(bin, []byte("#!/bin/sh\necho \"SSIM Y:1 All:$(cat $4) (1)\" >&2\n"), 0755))
+	QualityScorer = &verification.QualityScorer{Metric: verification.QualitySSIM, SampleRate: 1, MinScore: 0.9, FFmpeg: bin}
+
+	sess := StubBroadcastSession("transcoder1")
+	sess.Params.Profiles = []ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9, ffmpeg.P240p30fps16x9}
+	cxn := &rtmpConnection{
+		mid:         "dummy1",
+		params:      &core.StreamParameters{ManifestID: "dummy1", ExternalStreamID: "ext_dummy"},
+		sessManager: bsmWithSessList([]*BroadcastSession{sess}),
+	}
+	seg := &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 123}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Renditions above the minimum quality
+	scoreRenditionQuality(ctx, cxn, sess, seg, [][]byte{[]byte("0.95"), []byte("0.99")})
+	evt, ok := queue.receive(ctx)
+	require.True(ok)
+	assert.Equal("stream_health.quality.d.ext_dummy", evt.key)
+	require.IsType(&qualityEvent{}, evt.data)
+	qualityEvt := evt.data.(*qualityEvent)
+	assert.EqualValues("quality", qualityEvt.Type())
+	assert.Equal("ext_dummy", qualityEvt.StreamID())
+	assert.Equal(seg.SeqNo, qualityEvt.Segment.SeqNo)
+	assert.Equal("transcoder1", qualityEvt.Orchestrator.TranscoderUri)
+	assert.Equal("ssim", qualityEvt.Metric)
+	assert.Equal([]renditionQuality{{Profile: "P144p30fps16x9", Score: 0.95}, {Profile: "P240p30fps16x9", Score: 0.99}}, qualityEvt.Scores)
+	assert.False(qualityEvt.Rejected)
+	assert.Zero(cxn.sessManager.trustedPool.sus.Suspended("transcoder1"))
+	assert.Len(cxn.sessManager.trustedPool.sessMap, 1)
+
+	// Renditions that were not downloaded are skipped and the orchestrator is suspended for a rendition below the
+	// minimum quality
+	scoreRenditionQuality(ctx, cxn, sess, seg, [][]byte{nil, []byte("0.5")})
+	evt, ok = queue.receive(ctx)
+	require.True(ok)
+	qualityEvt = evt.data.(*qualityEvent)
+	assert.Equal([]renditionQuality{{Profile: "P240p30fps16x9", Score: 0.5}}, qualityEvt.Scores)
+	assert.True(qualityEvt.Rejected)
+	assert.Greater(cxn.sessManager.trustedPool.sus.Suspended("transcoder1"), 0)
+	assert.Len(cxn.sessManager.trustedPool.sessMap, 0)
+}
+
 func TestTranscodeSegment_VerifyPixels(t *testing.T) {
 	require := require.New(t)
 	assert := assert.New(t)
@@ -1120,6 +1343,39 @@ func TestUpdateSession(t *testing.T) {
 	assert.Equal(balances.Balance(ethcommon.Address{}, core.ManifestID("diffdiff")), big.NewRat(5, 1))
 }
 
+func TestUpdateSession_VerifiesOrchestratorInfo(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+	orch := newStubOrchestrator()
+	orch.priceInfo = &net.PriceInfo{PricePerUnit: 5, PixelsPerUnit: 1}
+	bcast := stubBroadcaster2()
+	info, err := orchestratorInfo(orch, bcast.Address(), "http://orch.com", "")
+	require.Nil(err)
+	sess := &BroadcastSession{Broadcaster: bcast, OrchestratorInfo: info, lock: &sync.RWMutex{}}
+
+	// Info in segment responses that was changed after it was signed is ignored
+	spoofed, err := orchestratorInfo(orch, bcast.Address(), "http://orch.com", "")
+	require.Nil(err)
+	spoofed.PriceInfo.PricePerUnit = 1
+	updateSession(sess, &ReceivedTranscodeResult{Info: spoofed})
+	assert.Equal(info, sess.OrchestratorInfo)
+
+	// Info signed by another orchestrator is ignored
+	other := newStubOrchestrator()
+	other.priceInfo = &net.PriceInfo{PricePerUnit: 1, PixelsPerUnit: 1}
+	otherInfo, err := orchestratorInfo(other, bcast.Address(), "http://orch.com", "")
+	require.Nil(err)
+	updateSession(sess, &ReceivedTranscodeResult{Info: otherInfo})
+	assert.Equal(info, sess.OrchestratorInfo)
+
+	updated, err := orchestratorInfo(orch, bcast.Address(), "http://orch.com", "")
+	require.Nil(err)
+	updateSession(sess, &ReceivedTranscodeResult{Info: updated})
+	assert.Equal(updated, sess.OrchestratorInfo)
+}
+
 func TestHLSInsertion(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1441,26 +1697,30 @@ func TestRefreshSession(t *testing.T) {
 	assert.Contains(err.Error(), "invalid control character in URL")
 
 	// trigger getOrchestratorInfo error
-	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL) (*net.OrchestratorInfo, error) {
+	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL, authToken *net.AuthToken) (*net.OrchestratorInfo, error) {
 		return nil, errors.New("some error")
 	}
 	sess = StubBroadcastSession("foo")
 	err = refreshSession(context.TODO(), sess)
 	assert.EqualError(err, "some error")
 
-	// trigger update
-	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL) (*net.OrchestratorInfo, error) {
+	// trigger update with the auth token of the session
+	var refreshedToken *net.AuthToken
+	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL, authToken *net.AuthToken) (*net.OrchestratorInfo, error) {
+		refreshedToken = authToken
 		return successOrchInfoUpdate, nil
 	}
+	authToken := sess.OrchestratorInfo.AuthToken
 	err = refreshSession(context.TODO(), sess)
 	assert.Nil(err)
 	assert.Equal(sess.OrchestratorInfo, successOrchInfoUpdate)
+	assert.Equal(authToken, refreshedToken)
 
 	// trigger timeout
 	oldRefreshTimeout := refreshTimeout
 	defer func() { refreshTimeout = oldRefreshTimeout }()
 	refreshTimeout = 10 * time.Millisecond
-	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, serv *url.URL) (*net.OrchestratorInfo, error) {
+	getOrchestratorInfoRPC = func(ctx context.Context, bcast common.Broadcaster, serv *url.URL, authToken *net.AuthToken) (*net.OrchestratorInfo, error) {
 		// Wait until the refreshTimeout has elapsed
 		select {
 		case <-ctx.Done():
@@ -1654,6 +1914,62 @@ func TestProcessSegment_CheckDuration(t *testing.T) {
 	assert.Equal("invalid duration 300.01", err.Error())
 }
 
+func TestProcessSegment_Filters(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	buf, err := proto.Marshal(&net.TranscodeResult{
+		Result: &net.TranscodeResult_Data{
+			Data: &net.TranscodeData{Segments: []*net.TranscodedSegmentData{{Url: "test.ts", Pixels: 100}}},
+		},
+	})
+	require.Nil(err)
+	type submission struct {
+		body     []byte
+		segCreds string
+	}
+	received := make(chan submission, 1)
+	transcoderURL := stubTestTranscoder(ctx, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- submission{body, r.Header.Get(segmentHeader)}
+		w.Write(buf)
+	})
+	bcastOS := &stubOSSession{host: "test://broad.com"}
+	sess := genBcastSess(ctx, t, "", bcastOS, "")
+	sess.OrchestratorInfo.Transcoder = transcoderURL
+	filters := []core.FilterStep{{Name: "fps", Args: map[string]string{"fps": "30"}}}
+	sess.Params.Filters = filters
+	sourceProfile := ffmpeg.P240p30fps16x9
+	cxn := &rtmpConnection{
+		params:      &core.StreamParameters{Filters: filters},
+		pl:          &stubPlaylistManager{os: bcastOS},
+		profile:     &sourceProfile,
+		sessManager: bsmWithSessList([]*BroadcastSession{sess}),
+	}
+
+	// The source is submitted as is and the orchestrator applies the filters when it transcodes the segment
+	seg := &stream.HLSSegment{Data: []byte("dummy")}
+	oldDownloadSeg := downloadSeg
+	defer func() { downloadSeg = oldDownloadSeg }()
+	downloadSeg = func(ctx context.Context, url string) ([]byte, error) { return []byte(url), nil }
+	_, err = processSegment(ctx, cxn, seg)
+	require.Nil(err)
+	select {
+	case sub := <-received:
+		assert.Equal("dummy", string(sub.body))
+		creds, err := base64.StdEncoding.DecodeString(sub.segCreds)
+		require.Nil(err)
+		var segData net.SegData
+		require.Nil(proto.Unmarshal(creds, &segData))
+		assert.Equal("fps=fps=30", segData.FilterGraph)
+	default:
+		require.Fail("segment was not submitted")
+	}
+	assert.Equal("dummy", string(seg.Data))
+}
+
 func genBcastSess(ctx context.Context, t *testing.T, url string, os drivers.OSSession, mid core.ManifestID) *BroadcastSession {
 	segData := []*net.TranscodedSegmentData{
 		{Url: url, Pixels: 100},
@@ -1714,3 +2030,85 @@ func TestCollectResults(t *testing.T) {
 	// the first result should always come from the verified session
 	assert.Equal(untrustedSessVerified, untrustedResults[0].Session)
 }
+
+func TestProcessSegment_DetectionOnly(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	buf, err := proto.Marshal(&net.TranscodeResult{
+		Result: &net.TranscodeResult_Data{
+			Data: &net.TranscodeData{Detections: []*net.DetectData{
+				{Value: &net.DetectData_SceneClassification{
+					SceneClassification: &net.SceneClassificationData{ClassProbs: map[uint32]float64{0: 0.9}},
+				}},
+			}},
+		},
+	})
+	require.Nil(err)
+	received := make(chan []byte, 2)
+	transcoderURL := stubTestTranscoder(ctx, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- body
+		w.Write(buf)
+	})
+	bcastOS := &stubOSSession{host: "test://broad.com"}
+	sess := genBcastSess(ctx, t, "", bcastOS, "")
+	sess.OrchestratorInfo.Transcoder = transcoderURL
+	sess.Params = &core.StreamParameters{
+		Detection: core.DetectionConfig{
+			Freq:               2,
+			SelectedClassNames: []string{"adult"},
+			Profiles:           []ffmpeg.DetectorProfile{&ffmpeg.SceneClassificationProfile{SampleRate: 10}},
+		},
+	}
+	require.True(sess.Params.DetectionOnly())
+	sourceProfile := ffmpeg.P240p30fps16x9
+	pl := &stubPlaylistManager{os: bcastOS}
+	cxn := &rtmpConnection{
+		params:      sess.Params,
+		pl:          pl,
+		profile:     &sourceProfile,
+		sessManager: bsmWithSessList([]*BroadcastSession{sess}),
+	}
+
+	// Segments sampled for detection are submitted without renditions
+	urls, err := processSegment(ctx, cxn, &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 2})
+	require.Nil(err)
+	assert.Empty(urls)
+	assert.Len(received, 1)
+
+	// Other segments are not submitted at all
+	urls, err = processSegment(ctx, cxn, &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 3})
+	require.Nil(err)
+	assert.Empty(urls)
+	assert.Len(received, 1)
+}
+
+func TestProcessSegment_SpliceCues(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sourceProfile := ffmpeg.P240p30fps16x9
+	pl := &stubPlaylistManager{os: &stubOSSession{}}
+	cxn := &rtmpConnection{
+		params:  &core.StreamParameters{},
+		pl:      pl,
+		profile: &sourceProfile,
+		splices: core.NewSpliceTracker(),
+	}
+
+	// Segments outside of breaks are not cued
+	_, err := processSegment(context.Background(), cxn, &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 1, Duration: 2, IsZeroFrame: true})
+	require.Nil(err)
+	assert.Empty(pl.cues)
+
+	// Segments within a break are cued
+	cxn.splices.Next([]*core.SpliceCue{{Out: true, Duration: 30}}, 2)
+	_, err = processSegment(context.Background(), cxn, &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 2, Duration: 2, IsZeroFrame: true})
+	require.Nil(err)
+	require.Contains(pl.cues, uint64(2))
+	assert.Equal(m3u8.SCTE35Cue_Mid, pl.cues[2].CueType)
+	assert.Equal(2.0, pl.cues[2].Elapsed)
+}
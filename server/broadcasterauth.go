@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/metadata"
+)
+
+// BroadcasterTokenSecret is the secret that the orchestrator signs broadcaster tokens with. If set, broadcasters must
+// present a token issued for their address to get orchestrator info
+var BroadcasterTokenSecret []byte
+
+// AllowedBroadcasters are the addresses of the broadcasters that get orchestrator info without a token. If set, other
+// broadcasters must present a token
+var AllowedBroadcasters map[ethcommon.Address]bool
+
+// OrchestratorTokens are the broadcaster tokens that the broadcaster presents to orchestrators, keyed by the host of
+// the orchestrator
+var OrchestratorTokens map[string]string
+
+// broadcasterTokenKey is the gRPC metadata key that broadcasters present their token with
+const broadcasterTokenKey = "livepeer-broadcaster-token"
+
+var errBroadcasterNotAuthorized = errors.New("broadcaster not authorized")
+
+// IssueBroadcasterToken returns a token for the broadcaster addr signed with secret. The token does not expire if
+// expiration is zero. Tokens are bound to the address, so a token is useless to broadcasters without the key of addr
+func IssueBroadcasterToken(secret []byte, addr ethcommon.Address, expiration time.Time) string {
+	var exp int64
+	if !expiration.IsZero() {
+		exp = expiration.Unix()
+	}
+	return fmt.Sprintf("%d.%s", exp, hex.EncodeToString(broadcasterTokenMAC(secret, addr, exp)))
+}
+
+func broadcasterTokenMAC(secret []byte, addr ethcommon.Address, exp int64) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(fmt.Sprintf("%s.%d", addr.Hex(), exp)))
+	return mac.Sum(nil)
+}
+
+// verifyBroadcasterToken returns an error if token was not issued for addr with secret or is expired
+func verifyBroadcasterToken(secret []byte, addr ethcommon.Address, token string, now time.Time) error {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return errors.New("malformed broadcaster token")
+	}
+	exp, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return errors.New("malformed broadcaster token")
+	}
+	sig, err := hex.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, broadcasterTokenMAC(secret, addr, exp)) {
+		return errors.New("invalid broadcaster token")
+	}
+	if exp != 0 && now.Unix() > exp {
+		return errors.New("expired broadcaster token")
+	}
+	return nil
+}
+
+// authorizeBroadcaster returns an error if broadcasters must be authorized and the broadcaster addr is neither allowed
+// nor presents a valid token in the gRPC metadata of ctx. The signature of the request proves that the broadcaster
+// holds the key of addr
+func authorizeBroadcaster(ctx context.Context, addr ethcommon.Address) error {
+	if len(BroadcasterTokenSecret) == 0 && len(AllowedBroadcasters) == 0 {
+		return nil
+	}
+	if AllowedBroadcasters[addr] {
+		return nil
+	}
+	if len(BroadcasterTokenSecret) == 0 {
+		return errBroadcasterNotAuthorized
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(broadcasterTokenKey)
+	if len(tokens) == 0 {
+		return errBroadcasterNotAuthorized
+	}
+	if err := verifyBroadcasterToken(BroadcasterTokenSecret, addr, tokens[0], time.Now()); err != nil {
+		return fmt.Errorf("%v: %v", errBroadcasterNotAuthorized, err)
+	}
+	return nil
+}
+
+// withOrchestratorToken adds the token of the orchestrator host to the gRPC metadata of ctx
+func withOrchestratorToken(ctx context.Context, host string) context.Context {
+	if token, ok := OrchestratorTokens[host]; ok {
+		return metadata.AppendToOutgoingContext(ctx, broadcasterTokenKey, token)
+	}
+	return ctx
+}
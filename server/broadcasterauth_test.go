@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/net"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestBroadcasterToken(t *testing.T) {
+	assert := assert.New(t)
+
+	secret := []byte("secret")
+	addr := ethcommon.HexToAddress("0x1")
+	now := time.Now()
+
+	token := IssueBroadcasterToken(secret, addr, time.Time{})
+	assert.True(strings.HasPrefix(token, "0."))
+	assert.Nil(verifyBroadcasterToken(secret, addr, token, now))
+
+	// Tokens are bound to the address and the secret
+	assert.EqualError(verifyBroadcasterToken(secret, ethcommon.HexToAddress("0x2"), token, now), "invalid broadcaster token")
+	assert.EqualError(verifyBroadcasterToken([]byte("other"), addr, token, now), "invalid broadcaster token")
+
+	// Expiring tokens
+	token = IssueBroadcasterToken(secret, addr, now.Add(time.Hour))
+	assert.Nil(verifyBroadcasterToken(secret, addr, token, now))
+	assert.EqualError(verifyBroadcasterToken(secret, addr, token, now.Add(2*time.Hour)), "expired broadcaster token")
+
+	// The expiration cannot be changed without the secret
+	tampered := "0" + token[strings.Index(token, "."):]
+	assert.EqualError(verifyBroadcasterToken(secret, addr, tampered, now), "invalid broadcaster token")
+
+	assert.EqualError(verifyBroadcasterToken(secret, addr, "foo", now), "malformed broadcaster token")
+	assert.EqualError(verifyBroadcasterToken(secret, addr, "x.00", now), "malformed broadcaster token")
+}
+
+func TestGetOrchestrator_BroadcasterAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func() { BroadcasterTokenSecret, AllowedBroadcasters = nil, nil }()
+
+	orch := &mockOrchestrator{}
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
+	orch.On("ServiceURI").Return(url.Parse("http://someuri.com"))
+	orch.On("Address").Return(ethcommon.Address{})
+	orch.On("TicketParams", mock.Anything, mock.Anything).Return(nil, nil)
+	orch.On("PriceInfo", mock.Anything).Return(nil, nil)
+	orch.On("AuthToken", mock.Anything, mock.Anything).Return(&net.AuthToken{})
+	orch.On("Sign", mock.Anything).Return(nil, nil)
+
+	addr := ethcommon.HexToAddress("0x1")
+	req := &net.OrchestratorRequest{Address: addr.Bytes()}
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(broadcasterTokenKey, token))
+	}
+
+	// Broadcasters are not authorized unless required
+	_, err := getOrchestrator(context.Background(), orch, req)
+	assert.Nil(err)
+
+	// Broadcasters must present a token issued for their address
+	BroadcasterTokenSecret = []byte("secret")
+	_, err = getOrchestrator(context.Background(), orch, req)
+	assert.Equal(errBroadcasterNotAuthorized, err)
+	_, err = getOrchestrator(withToken(IssueBroadcasterToken([]byte("secret"), ethcommon.HexToAddress("0x2"), time.Time{})), orch, req)
+	assert.EqualError(err, "broadcaster not authorized: invalid broadcaster token")
+	_, err = getOrchestrator(withToken(IssueBroadcasterToken([]byte("secret"), addr, time.Time{})), orch, req)
+	assert.Nil(err)
+
+	// Allowed broadcasters do not need a token
+	AllowedBroadcasters = map[ethcommon.Address]bool{addr: true}
+	_, err = getOrchestrator(context.Background(), orch, req)
+	assert.Nil(err)
+	BroadcasterTokenSecret = nil
+	_, err = getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{Address: ethcommon.HexToAddress("0x2").Bytes()})
+	assert.Equal(errBroadcasterNotAuthorized, err)
+}
+
+func TestWithOrchestratorToken(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func() { OrchestratorTokens = nil }()
+	OrchestratorTokens = map[string]string{"127.0.0.1:8935": "token"}
+
+	md, _ := metadata.FromOutgoingContext(withOrchestratorToken(context.Background(), "127.0.0.1:8935"))
+	assert.Equal([]string{"token"}, md.Get(broadcasterTokenKey))
+	md, _ = metadata.FromOutgoingContext(withOrchestratorToken(context.Background(), "127.0.0.1:8936"))
+	assert.Empty(md.Get(broadcasterTokenKey))
+}
+
+func TestIssueBroadcasterTokenHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func() { BroadcasterTokenSecret = nil }()
+	handler := mustHaveFormParams(issueBroadcasterTokenHandler(), "address")
+
+	resp := httpPostFormResp(handler, strings.NewReader("address=0x0000000000000000000000000000000000000001"))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(string(body), "broadcaster tokens are not enabled")
+
+	BroadcasterTokenSecret = []byte("secret")
+	resp = httpPostFormResp(handler, strings.NewReader("address=foo"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("invalid address", strings.TrimSpace(string(body)))
+
+	resp = httpPostFormResp(handler, strings.NewReader("address=0x0000000000000000000000000000000000000001&ttl=foo"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(`invalid ttl "foo"`, strings.TrimSpace(string(body)))
+
+	resp = httpPostFormResp(handler, strings.NewReader("address=0x0000000000000000000000000000000000000001&ttl=720h"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Nil(verifyBroadcasterToken([]byte("secret"), ethcommon.HexToAddress("0x1"), string(body), time.Now()))
+	assert.NotNil(verifyBroadcasterToken([]byte("secret"), ethcommon.HexToAddress("0x1"), string(body), time.Now().Add(721*time.Hour)))
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/livepeer/lpms/ffmpeg"
+)
+
+// BroadcastBudget caps the spend of the broadcaster across all streams. Spend is not capped if nil
+var BroadcastBudget *SpendBudget
+
+var errBudgetExhausted = errors.New("ErrBudgetExhausted")
+
+// SpendBudget is the maximum amount of wei that a broadcaster spends on tickets per Window. The spend of a ticket is
+// its expected value. Windows are aligned to multiples of Window, so a budget with a Window of 24h resets at midnight
+// UTC. New streams are refused once Max has been spent in the current window, and new streams are degraded to their
+// cheapest rendition once DegradeAt of Max has been spent. Streams that were already admitted are not affected
+type SpendBudget struct {
+	Max       *big.Int
+	Window    time.Duration
+	DegradeAt float64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	spent       *big.Rat
+}
+
+// NewSpendBudget creates a SpendBudget of max wei per window
+func NewSpendBudget(max *big.Int, window time.Duration, degradeAt float64) *SpendBudget {
+	return &SpendBudget{
+		Max:       max,
+		Window:    window,
+		DegradeAt: degradeAt,
+		spent:     big.NewRat(0, 1),
+	}
+}
+
+// spend records ev wei as spent in the current window
+func (b *SpendBudget) spend(ev *big.Rat, now time.Time) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rotate(now)
+	b.spent.Add(b.spent, ev)
+}
+
+// Spent returns the wei spent in the current window
+func (b *SpendBudget) Spent(now time.Time) *big.Rat {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rotate(now)
+	return new(big.Rat).Set(b.spent)
+}
+
+// admit returns the profiles that a new stream with profiles is transcoded to given the spend in the current window.
+// Returns errBudgetExhausted if the stream is refused
+func (b *SpendBudget) admit(profiles []ffmpeg.VideoProfile, now time.Time) ([]ffmpeg.VideoProfile, error) {
+	if b == nil {
+		return profiles, nil
+	}
+
+	spent := b.Spent(now)
+	max := new(big.Rat).SetInt(b.Max)
+	if spent.Cmp(max) >= 0 {
+		return nil, errBudgetExhausted
+	}
+
+	// Parse the shortest decimal representation so that e.g. 0.9 is exactly 9/10
+	degradeAt, _ := new(big.Rat).SetString(strconv.FormatFloat(b.DegradeAt, 'f', -1, 64))
+	threshold := new(big.Rat).Mul(max, degradeAt)
+	if len(profiles) <= 1 || spent.Cmp(threshold) < 0 {
+		return profiles, nil
+	}
+
+	return []ffmpeg.VideoProfile{cheapestProfile(profiles)}, nil
+}
+
+// rotate starts a new window if now is past the current window. The caller must hold mu
+func (b *SpendBudget) rotate(now time.Time) {
+	if start := now.Truncate(b.Window); !start.Equal(b.windowStart) {
+		b.windowStart = start
+		b.spent.SetInt64(0)
+	}
+}
+
+// cheapestProfile returns the profile with the fewest pixels per frame. Profiles with an invalid resolution are
+// considered the most expensive
+func cheapestProfile(profiles []ffmpeg.VideoProfile) ffmpeg.VideoProfile {
+	cheapest, minPixels := profiles[0], int64(-1)
+	for _, p := range profiles {
+		w, h, err := ffmpeg.VideoProfileResolution(p)
+		if err != nil {
+			continue
+		}
+		if pixels := int64(w) * int64(h); minPixels < 0 || pixels < minPixels {
+			cheapest, minPixels = p, pixels
+		}
+	}
+	return cheapest
+}
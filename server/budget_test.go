@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/net"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/livepeer/lpms/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	profiles := []ffmpeg.VideoProfile{ffmpeg.P720p30fps16x9, ffmpeg.P144p30fps16x9, ffmpeg.P360p30fps16x9}
+	now := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// Nil SpendBudget admits all streams
+	var nilBudget *SpendBudget
+	nilBudget.spend(big.NewRat(1000, 1), now)
+	admitted, err := nilBudget.admit(profiles, now)
+	assert.Nil(err)
+	assert.Equal(profiles, admitted)
+
+	b := NewSpendBudget(big.NewInt(1000), time.Hour, 0.9)
+	b.spend(big.NewRat(1799, 2), now)
+	admitted, err = b.admit(profiles, now)
+	assert.Nil(err)
+	assert.Equal(profiles, admitted)
+
+	// Streams are degraded to their lowest resolution rendition once the threshold is reached
+	b.spend(big.NewRat(1, 2), now.Add(time.Minute))
+	assert.Zero(big.NewRat(900, 1).Cmp(b.Spent(now)))
+	admitted, err = b.admit(profiles, now)
+	assert.Nil(err)
+	assert.Equal([]ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9}, admitted)
+	admitted, err = b.admit(nil, now)
+	assert.Nil(err)
+	assert.Empty(admitted)
+
+	// Streams are refused once the budget is spent
+	b.spend(big.NewRat(100, 1), now)
+	_, err = b.admit(profiles, now)
+	assert.Equal(errBudgetExhausted, err)
+
+	// The budget resets at the start of the next window
+	now = now.Add(time.Hour)
+	assert.Zero(b.Spent(now).Sign())
+	admitted, err = b.admit(profiles, now)
+	assert.Nil(err)
+	assert.Equal(profiles, admitted)
+}
+
+func TestCheapestProfile(t *testing.T) {
+	assert := assert.New(t)
+
+	invalid := ffmpeg.VideoProfile{Name: "invalid", Resolution: "foo"}
+	assert.Equal(ffmpeg.P240p30fps16x9, cheapestProfile([]ffmpeg.VideoProfile{invalid, ffmpeg.P720p30fps16x9, ffmpeg.P240p30fps16x9}))
+	assert.Equal(invalid, cheapestProfile([]ffmpeg.VideoProfile{invalid}))
+}
+
+func TestGenPayment_SpendBudget(t *testing.T) {
+	require := require.New(t)
+
+	defer func(b *SpendBudget) { BroadcastBudget = b }(BroadcastBudget)
+	BroadcastBudget = NewSpendBudget(big.NewInt(1000), time.Hour, 0.9)
+
+	sender := &pm.MockSender{}
+	s := &BroadcastSession{
+		Broadcaster:      stubBroadcaster2(),
+		Params:           &core.StreamParameters{ManifestID: core.RandomManifestID()},
+		OrchestratorInfo: &net.OrchestratorInfo{PriceInfo: &net.PriceInfo{PricePerUnit: 1, PixelsPerUnit: 1}, AuthToken: stubAuthToken},
+		PMSessionID:      "foo",
+		Sender:           sender,
+	}
+	// Max win prob, so the EV of a ticket is its face value
+	batch := &pm.TicketBatch{
+		TicketParams: &pm.TicketParams{
+			Recipient:       pm.RandAddress(),
+			FaceValue:       big.NewInt(100),
+			WinProb:         new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+			Seed:            big.NewInt(7777),
+			ExpirationBlock: big.NewInt(1000),
+		},
+		TicketExpirationParams: &pm.TicketExpirationParams{},
+		SenderParams:           []*pm.TicketSenderParams{{}, {}, {}},
+	}
+	sender.On("CreateTicketBatch", s.PMSessionID, 3).Return(batch, nil)
+
+	_, err := genPayment(context.TODO(), s, 3)
+	require.Nil(err)
+	assert.Zero(t, big.NewRat(300, 1).Cmp(BroadcastBudget.Spent(time.Now())))
+}
+
+func TestRegisterConnection_SpendBudget(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s, cancel := setupServerWithCancel()
+	defer serverCleanup(s)
+	defer cancel()
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+
+	defer func(b *SpendBudget) { BroadcastBudget = b }(BroadcastBudget)
+	BroadcastBudget = NewSpendBudget(big.NewInt(1000), time.Hour, 0.5)
+
+	newStream := func(name string) stream.RTMPVideoStream {
+		mid := core.SplitStreamIDString(t.Name() + name).ManifestID
+		profiles := []ffmpeg.VideoProfile{ffmpeg.P720p30fps16x9, ffmpeg.P240p30fps16x9}
+		return stream.NewBasicRTMPVideoStream(&core.StreamParameters{ManifestID: mid, Profiles: profiles})
+	}
+
+	cxn, err := s.registerConnection(context.TODO(), newStream("a"), nil, PixelFormatNone())
+	require.Nil(err)
+	assert.Len(cxn.params.Profiles, 2)
+
+	BroadcastBudget.spend(big.NewRat(500, 1), time.Now())
+	cxn, err = s.registerConnection(context.TODO(), newStream("b"), nil, PixelFormatNone())
+	require.Nil(err)
+	assert.Equal([]ffmpeg.VideoProfile{ffmpeg.P240p30fps16x9}, cxn.params.Profiles)
+
+	BroadcastBudget.spend(big.NewRat(500, 1), time.Now())
+	_, err = s.registerConnection(context.TODO(), newStream("c"), nil, PixelFormatNone())
+	assert.Equal(errBudgetExhausted, err)
+}
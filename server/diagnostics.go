@@ -0,0 +1,163 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	rpprof "runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+)
+
+// AdminToken is the bearer token required by the profiling and diagnostics endpoints of the CLI API. The endpoints
+// are disabled if it is empty
+var AdminToken string
+
+// RecentLogs keeps the recent logs of the node to include in diagnostic archives if set
+var RecentLogs *common.LogBuffer
+
+// MaxProfileSeconds is the maximum duration of CPU profiles and execution traces
+const MaxProfileSeconds = 60
+
+// profiling is a semaphore that only allows one CPU profile or execution trace at a time
+var profiling = make(chan struct{}, 1)
+
+func init() {
+	// net/http/pprof registers its handlers on the default mux. Reset it so that pprof is not accidentally served
+	// without the admin token by other listeners
+	http.DefaultServeMux = http.NewServeMux()
+}
+
+// requireAdminToken only serves requests with the admin token in the Authorization header
+func requireAdminToken(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if AdminToken == "" {
+			respondWithError(w, "endpoint disabled, set -adminToken to enable it", http.StatusForbidden)
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(AdminToken)) != 1 {
+			glog.Warningf("Rejected unauthorized admin request path=%s remoteAddr=%s", r.URL.Path, r.RemoteAddr)
+			respondWithError(w, "invalid admin token", http.StatusUnauthorized)
+			return
+		}
+		glog.Infof("Serving admin request path=%s remoteAddr=%s", r.URL.Path, r.RemoteAddr)
+		h.ServeHTTP(w, r)
+	})
+}
+
+// limitProfiling caps the duration of profiles at MaxProfileSeconds and rejects profiles while another is running
+func limitProfiling(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s := r.FormValue("seconds"); s != "" {
+			sec, err := strconv.ParseFloat(s, 64)
+			if err != nil || sec <= 0 || sec > MaxProfileSeconds {
+				respondWith400(w, fmt.Sprintf("seconds must be between 0 and %d", MaxProfileSeconds))
+				return
+			}
+		}
+		select {
+		case profiling <- struct{}{}:
+			defer func() { <-profiling }()
+		default:
+			respondWithError(w, "another profile is in progress", http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// registerDiagnosticsHandlers registers the pprof and diagnostics endpoints on mux behind the admin token
+func (s *LivepeerServer) registerDiagnosticsHandlers(mux *http.ServeMux) {
+	mux.Handle("/debug/pprof/", requireAdminToken(http.HandlerFunc(pprof.Index)))
+	mux.Handle("/debug/pprof/cmdline", requireAdminToken(http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle("/debug/pprof/profile", requireAdminToken(limitProfiling(http.HandlerFunc(pprof.Profile))))
+	mux.Handle("/debug/pprof/symbol", requireAdminToken(http.HandlerFunc(pprof.Symbol)))
+	mux.Handle("/debug/pprof/trace", requireAdminToken(limitProfiling(http.HandlerFunc(pprof.Trace))))
+	mux.Handle("/diagnostics", requireAdminToken(s.diagnosticsHandler()))
+}
+
+// diagnosticsHandler responds with a gzipped tar archive of a goroutine dump, a heap profile, the recent logs and the
+// node status to attach to support tickets
+func (s *LivepeerServer) diagnosticsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		files := map[string][]byte{
+			"version.txt": []byte(core.LivepeerVersion + "\n"),
+		}
+
+		var goroutines bytes.Buffer
+		if err := rpprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+			respondWith500(w, fmt.Sprintf("could not dump goroutines: %v", err))
+			return
+		}
+		files["goroutines.txt"] = goroutines.Bytes()
+
+		var heap bytes.Buffer
+		if err := rpprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+			respondWith500(w, fmt.Sprintf("could not write heap profile: %v", err))
+			return
+		}
+		files["heap.pprof"] = heap.Bytes()
+
+		if RecentLogs != nil {
+			files["logs.txt"] = RecentLogs.Bytes()
+		}
+
+		if status := s.GetNodeStatus(); status != nil {
+			data, err := json.MarshalIndent(status, "", "  ")
+			if err != nil {
+				respondWith500(w, fmt.Sprintf("could not marshal status: %v", err))
+				return
+			}
+			files["status.json"] = data
+		}
+
+		archive, err := tarGzip(files)
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not create archive: %v", err))
+			return
+		}
+
+		name := fmt.Sprintf("livepeer-diagnostics-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z"))
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		respondOk(w, archive)
+	})
+}
+
+func tarGzip(files map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, name := range []string{"version.txt", "status.json", "goroutines.txt", "heap.pprof", "logs.txt"} {
+		data, ok := files[name]
+		if !ok {
+			continue
+		}
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
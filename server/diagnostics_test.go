@@ -0,0 +1,150 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func adminGet(t *testing.T, url, token string) (*http.Response, []byte) {
+	req, err := http.NewRequest("GET", url, nil)
+	require.Nil(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.Nil(t, err)
+	return resp, body
+}
+
+func TestDiagnostics_AdminToken(t *testing.T) {
+	assert := assert.New(t)
+	srv := newMockServer()
+	defer srv.Close()
+	defer func() { AdminToken = "" }()
+
+	// Disabled without admin token
+	resp, body := adminGet(t, srv.URL+"/debug/pprof/", "foo")
+	assert.Equal(http.StatusForbidden, resp.StatusCode)
+	assert.Equal("endpoint disabled, set -adminToken to enable it", strings.TrimSpace(string(body)))
+
+	AdminToken = "secret"
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/pprof/cmdline", "/debug/pprof/profile", "/debug/pprof/trace", "/diagnostics"} {
+		resp, body = adminGet(t, srv.URL+path, "")
+		assert.Equal(http.StatusUnauthorized, resp.StatusCode, path)
+		assert.Equal("invalid admin token", strings.TrimSpace(string(body)))
+
+		resp, _ = adminGet(t, srv.URL+path, "wrong")
+		assert.Equal(http.StatusUnauthorized, resp.StatusCode, path)
+	}
+
+	resp, body = adminGet(t, srv.URL+"/debug/pprof/", "secret")
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Contains(string(body), "goroutine")
+
+	resp, _ = adminGet(t, srv.URL+"/debug/pprof/heap", "secret")
+	assert.Equal(http.StatusOK, resp.StatusCode)
+
+	// pprof is not served by the default mux
+	rec := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/pprof/", nil))
+	assert.Equal(http.StatusNotFound, rec.Code)
+}
+
+func TestDiagnostics_LimitProfiling(t *testing.T) {
+	assert := assert.New(t)
+	srv := newMockServer()
+	defer srv.Close()
+	AdminToken = "secret"
+	defer func() { AdminToken = "" }()
+
+	for _, seconds := range []string{"0", "61", "foo"} {
+		resp, body := adminGet(t, srv.URL+"/debug/pprof/profile?seconds="+seconds, "secret")
+		assert.Equal(http.StatusBadRequest, resp.StatusCode)
+		assert.Equal("seconds must be between 0 and 60", strings.TrimSpace(string(body)))
+	}
+
+	// Only one profile at a time
+	profiling <- struct{}{}
+	resp, body := adminGet(t, srv.URL+"/debug/pprof/trace?seconds=1", "secret")
+	<-profiling
+	assert.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal("another profile is in progress", strings.TrimSpace(string(body)))
+
+	resp, _ = adminGet(t, srv.URL+"/debug/pprof/trace?seconds=0.1", "secret")
+	assert.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func TestDiagnostics_Archive(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	srv := newMockServer()
+	defer srv.Close()
+	AdminToken = "secret"
+	RecentLogs = common.NewLogBuffer(1024)
+	defer func() {
+		AdminToken = ""
+		RecentLogs = nil
+	}()
+	RecentLogs.Write([]byte("I0101 recent log line\n"))
+
+	resp, body := adminGet(t, srv.URL+"/diagnostics", "secret")
+	require.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("application/gzip", resp.Header.Get("Content-Type"))
+	assert.Contains(resp.Header.Get("Content-Disposition"), "livepeer-diagnostics-")
+
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	require.Nil(err)
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(err)
+		data, err := ioutil.ReadAll(tr)
+		require.Nil(err)
+		files[hdr.Name] = data
+	}
+
+	assert.Equal(core.LivepeerVersion+"\n", string(files["version.txt"]))
+	assert.Contains(string(files["goroutines.txt"]), "goroutine")
+	assert.NotEmpty(files["heap.pprof"])
+	assert.Equal("I0101 recent log line\n", string(files["logs.txt"]))
+	var status common.NodeStatus
+	assert.Nil(json.Unmarshal(files["status.json"], &status))
+
+	// Logs are omitted if they are not captured
+	RecentLogs = nil
+	resp, body = adminGet(t, srv.URL+"/diagnostics", "secret")
+	require.Equal(http.StatusOK, resp.StatusCode)
+	gz, err = gzip.NewReader(bytes.NewReader(body))
+	require.Nil(err)
+	tr = tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(err)
+		names = append(names, hdr.Name)
+	}
+	assert.Equal([]string{"version.txt", "status.json", "goroutines.txt", "heap.pprof"}, names)
+}
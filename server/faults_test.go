@@ -0,0 +1,73 @@
+//go:build faults
+// +build faults
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultsHandler(t *testing.T) {
+	assert := assert.New(t)
+	defer common.ClearFault("")
+	handler := faultsHandler()
+
+	resp := httpPostResp(handler, strings.NewReader(`{"fault":"storage","every":2,"count":1}`), nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	resp = httpPostResp(handler, strings.NewReader(`{"fault":"rpcDelay","delayMs":10}`), nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+
+	resp = httpGetResp(handler)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.JSONEq(`[{"fault":"rpcDelay","every":1,"delayMs":10,"occurrences":0,"injected":0},
+		{"fault":"storage","every":2,"count":1,"occurrences":0,"injected":0}]`, string(body))
+
+	// Invalid configs
+	resp = httpPostResp(handler, strings.NewReader(`{"fault":"foo"}`), nil)
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(`unknown fault "foo"`, strings.TrimSpace(string(body)))
+	resp = httpPostResp(handler, strings.NewReader(`foo`), nil)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	// Clear all faults
+	resp = httpResp(handler, "DELETE", nil, nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Empty(common.Faults())
+}
+
+func TestSendReqWithTimeout_FaultRPCDelay(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	defer common.ClearFault("")
+
+	ts, mux := stubTLSServer()
+	defer ts.Close()
+	mux.HandleFunc("/segment", func(w http.ResponseWriter, r *http.Request) {})
+
+	require.Nil(common.SetFault(common.FaultConfig{Fault: common.FaultRPCDelay, Every: 2, DelayMs: 500}))
+
+	// The first request is not delayed
+	req, err := http.NewRequestWithContext(context.Background(), "POST", ts.URL+"/segment", nil)
+	require.Nil(err)
+	resp, err := sendReqWithTimeout(req, 100*time.Millisecond)
+	require.Nil(err)
+	resp.Body.Close()
+
+	// The second request is delayed beyond the timeout
+	start := time.Now()
+	_, err = sendReqWithTimeout(req, 100*time.Millisecond)
+	assert.True(errors.Is(err, context.Canceled))
+	assert.Less(int64(time.Since(start)), int64(500*time.Millisecond))
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/monitor"
+	"github.com/livepeer/go-livepeer/pm"
+)
+
+// BroadcastFunds watches the deposit and reserve of the broadcaster. New streams are not refused for low funds if nil
+var BroadcastFunds *FundsWatcher
+
+var errFundsLow = errors.New("ErrFundsLow")
+
+// fundsWatchInterval is how often the funds of the broadcaster are checked
+var fundsWatchInterval = time.Minute
+
+// fundsSpendBucket is the granularity that spend is recorded at
+const fundsSpendBucket = time.Minute
+
+type senderInfoGetter interface {
+	GetSenderInfo(addr ethcommon.Address) (*pm.SenderInfo, error)
+}
+
+// FundsWatcher estimates the runway of the broadcaster, which is how long its deposit lasts at the spend rate of the
+// last SpendWindow. The spend of a ticket is its expected value. An alert is logged when the runway drops below
+// MinRunway or when the deposit and reserve are being unlocked, and new streams are refused while the runway is below
+// MinRunway so that streams do not fail mid-broadcast once the deposit is spent. Streams that were already admitted
+// are not affected
+type FundsWatcher struct {
+	MinRunway   time.Duration
+	SpendWindow time.Duration
+
+	addr    ethcommon.Address
+	senders senderInfoGetter
+	started time.Time
+
+	mu        sync.Mutex
+	spends    []fundsSpend
+	low       bool
+	unlocking bool
+	quit      chan struct{}
+}
+
+type fundsSpend struct {
+	start time.Time
+	ev    *big.Rat
+}
+
+// FundsStatus is the state of the funds of the broadcaster
+type FundsStatus struct {
+	Deposit       *big.Int `json:"deposit"`
+	Reserve       *big.Int `json:"reserve"`
+	WithdrawRound *big.Int `json:"withdrawRound"`
+	// Wei spent per hour over the spend window
+	SpendRate *big.Int `json:"spendRate"`
+	// Seconds until the deposit is spent, -1 if nothing was spent
+	RunwaySeconds float64 `json:"runwaySeconds"`
+	Low           bool    `json:"low"`
+	Unlocking     bool    `json:"unlocking"`
+}
+
+// NewFundsWatcher creates a FundsWatcher for the funds of the sender addr
+func NewFundsWatcher(addr ethcommon.Address, senders senderInfoGetter, minRunway, spendWindow time.Duration) *FundsWatcher {
+	return &FundsWatcher{
+		MinRunway:   minRunway,
+		SpendWindow: spendWindow,
+		addr:        addr,
+		senders:     senders,
+		started:     time.Now(),
+		quit:        make(chan struct{}),
+	}
+}
+
+// Start checks the funds every fundsWatchInterval until Stop is called
+func (fw *FundsWatcher) Start() {
+	go func() {
+		ticker := time.NewTicker(fundsWatchInterval)
+		defer ticker.Stop()
+		for {
+			fw.check(time.Now())
+			select {
+			case <-ticker.C:
+			case <-fw.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops checking the funds
+func (fw *FundsWatcher) Stop() {
+	close(fw.quit)
+}
+
+// spend records ev wei as spent
+func (fw *FundsWatcher) spend(ev *big.Rat, now time.Time) {
+	if fw == nil {
+		return
+	}
+
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	start := now.Truncate(fundsSpendBucket)
+	if n := len(fw.spends); n > 0 && fw.spends[n-1].start.Equal(start) {
+		fw.spends[n-1].ev.Add(fw.spends[n-1].ev, ev)
+	} else {
+		fw.spends = append(fw.spends, fundsSpend{start: start, ev: new(big.Rat).Set(ev)})
+	}
+	fw.prune(now)
+}
+
+// prune removes the spend older than the spend window. The caller must hold mu
+func (fw *FundsWatcher) prune(now time.Time) {
+	i := 0
+	for i < len(fw.spends) && now.Sub(fw.spends[i].start) > fw.SpendWindow {
+		i++
+	}
+	fw.spends = fw.spends[i:]
+}
+
+// spendRate returns the wei spent per second over the spend window, or over the time since the watcher was created
+// if that is shorter
+func (fw *FundsWatcher) spendRate(now time.Time) *big.Rat {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	fw.prune(now)
+	total := big.NewRat(0, 1)
+	for _, s := range fw.spends {
+		total.Add(total, s.ev)
+	}
+	window := fw.SpendWindow
+	if elapsed := now.Sub(fw.started); elapsed < window {
+		window = elapsed
+	}
+	if window < time.Second {
+		window = time.Second
+	}
+	return total.Quo(total, big.NewRat(int64(window/time.Second), 1))
+}
+
+// Status returns the funds of the broadcaster and their runway
+func (fw *FundsWatcher) Status(now time.Time) (*FundsStatus, error) {
+	info, err := fw.senders.GetSenderInfo(fw.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := fw.spendRate(now)
+	hourly := new(big.Rat).Mul(rate, big.NewRat(3600, 1))
+	status := &FundsStatus{
+		Deposit:       info.Deposit,
+		Reserve:       info.Reserve.FundsRemaining,
+		WithdrawRound: info.WithdrawRound,
+		SpendRate:     new(big.Int).Quo(hourly.Num(), hourly.Denom()),
+		RunwaySeconds: -1,
+		Unlocking:     info.WithdrawRound != nil && info.WithdrawRound.Sign() != 0,
+	}
+	if rate.Sign() > 0 {
+		runway, _ := new(big.Rat).Quo(new(big.Rat).SetInt(info.Deposit), rate).Float64()
+		status.RunwaySeconds = runway
+		status.Low = runway < fw.MinRunway.Seconds()
+	}
+	return status, nil
+}
+
+// check logs an alert when the funds become low or start to be unlocked and records the runway
+func (fw *FundsWatcher) check(now time.Time) {
+	status, err := fw.Status(now)
+	if err != nil {
+		glog.Errorf("Unable to get broadcaster funds err=%q", err)
+		return
+	}
+
+	fw.mu.Lock()
+	wasLow, wasUnlocking := fw.low, fw.unlocking
+	fw.low, fw.unlocking = status.Low, status.Unlocking
+	fw.mu.Unlock()
+
+	if status.Low && !wasLow {
+		glog.Errorf("Broadcaster funds are low, refusing new streams deposit=%v runway=%.0fs minRunway=%v",
+			eth.FormatUnits(status.Deposit, "ETH"), status.RunwaySeconds, fw.MinRunway)
+	} else if !status.Low && wasLow {
+		glog.Infof("Broadcaster funds recovered, accepting new streams deposit=%v", eth.FormatUnits(status.Deposit, "ETH"))
+	}
+	if status.Unlocking && !wasUnlocking {
+		glog.Errorf("Broadcaster deposit and reserve are being unlocked, streams will fail once they are withdrawable withdrawRound=%v", status.WithdrawRound)
+	}
+
+	if monitor.Enabled && status.RunwaySeconds >= 0 {
+		monitor.DepositRunway(fw.addr.Hex(), status.RunwaySeconds)
+	}
+}
+
+// admit returns errFundsLow if new streams are refused because the runway of the funds is below MinRunway
+func (fw *FundsWatcher) admit(now time.Time) error {
+	if fw == nil {
+		return nil
+	}
+
+	status, err := fw.Status(now)
+	if err != nil {
+		// Do not refuse streams because of a failure to get the funds, sending tickets fails if there are none
+		glog.Errorf("Unable to get broadcaster funds err=%q", err)
+		return nil
+	}
+	if status.Low {
+		return errFundsLow
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/pm"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/livepeer/lpms/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubSenderInfoGetter struct {
+	info *pm.SenderInfo
+	err  error
+}
+
+func (s *stubSenderInfoGetter) GetSenderInfo(addr ethcommon.Address) (*pm.SenderInfo, error) {
+	return s.info, s.err
+}
+
+func newStubSenderInfo(deposit int64) *pm.SenderInfo {
+	return &pm.SenderInfo{
+		Deposit:       big.NewInt(deposit),
+		WithdrawRound: big.NewInt(0),
+		Reserve:       &pm.ReserveInfo{FundsRemaining: big.NewInt(500), ClaimedInCurrentRound: big.NewInt(0)},
+	}
+}
+
+func TestFundsWatcher_Runway(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// Nil FundsWatcher admits all streams
+	var nilWatcher *FundsWatcher
+	nilWatcher.spend(big.NewRat(1000, 1), time.Now())
+	assert.Nil(nilWatcher.admit(time.Now()))
+
+	senders := &stubSenderInfoGetter{info: newStubSenderInfo(36000)}
+	fw := NewFundsWatcher(ethcommon.Address{}, senders, 5*time.Hour, time.Hour)
+	now := fw.started.Add(2 * time.Hour)
+
+	// The runway is unknown until something is spent
+	status, err := fw.Status(now)
+	require.Nil(err)
+	assert.Equal(float64(-1), status.RunwaySeconds)
+	assert.False(status.Low)
+	assert.Zero(status.SpendRate.Sign())
+
+	// 3600 wei per hour lasts 10 hours
+	fw.spend(big.NewRat(1800, 1), now.Add(-90*time.Minute))
+	fw.spend(big.NewRat(1800, 1), now.Add(-30*time.Minute))
+	fw.spend(big.NewRat(1800, 1), now.Add(-time.Minute))
+	status, err = fw.Status(now)
+	require.Nil(err)
+	assert.Equal(big.NewInt(3600), status.SpendRate)
+	assert.Equal(float64(36000), status.RunwaySeconds)
+	assert.False(status.Low)
+	assert.Nil(fw.admit(now))
+
+	// Streams are refused once the runway drops below the minimum
+	senders.info = newStubSenderInfo(14400)
+	status, err = fw.Status(now)
+	require.Nil(err)
+	assert.True(status.Low)
+	assert.Equal(errFundsLow, fw.admit(now))
+	fw.check(now)
+	assert.True(fw.low)
+
+	// Streams are admitted again once the spend rate drops
+	now = now.Add(time.Hour)
+	assert.Nil(fw.admit(now))
+	fw.check(now)
+	assert.False(fw.low)
+
+	// Unlocking funds are reported
+	senders.info.WithdrawRound = big.NewInt(10)
+	status, err = fw.Status(now)
+	require.Nil(err)
+	assert.True(status.Unlocking)
+	fw.check(now)
+	assert.True(fw.unlocking)
+
+	// Streams are not refused if the funds are unavailable
+	senders.err = errors.New("some error")
+	_, err = fw.Status(now)
+	assert.EqualError(err, "some error")
+	assert.Nil(fw.admit(now))
+}
+
+func TestFundsWatcher_SpendRateSinceStart(t *testing.T) {
+	assert := assert.New(t)
+
+	fw := NewFundsWatcher(ethcommon.Address{}, &stubSenderInfoGetter{info: newStubSenderInfo(0)}, 0, time.Hour)
+	now := fw.started.Add(10 * time.Minute)
+
+	// The rate is estimated over the time since the start until the window has passed
+	fw.spend(big.NewRat(600, 1), now)
+	assert.Zero(big.NewRat(1, 1).Cmp(fw.spendRate(now)))
+}
+
+func TestRegisterConnection_FundsLow(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s, cancel := setupServerWithCancel()
+	defer serverCleanup(s)
+	defer cancel()
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+
+	senders := &stubSenderInfoGetter{info: newStubSenderInfo(36000)}
+	defer func(fw *FundsWatcher) { BroadcastFunds = fw }(BroadcastFunds)
+	BroadcastFunds = NewFundsWatcher(ethcommon.Address{}, senders, 5*time.Hour, time.Hour)
+	BroadcastFunds.started = time.Now().Add(-time.Hour)
+	BroadcastFunds.spend(big.NewRat(3600, 1), time.Now())
+
+	newStream := func(name string) stream.RTMPVideoStream {
+		mid := core.SplitStreamIDString(t.Name() + name).ManifestID
+		profiles := []ffmpeg.VideoProfile{ffmpeg.P240p30fps16x9}
+		return stream.NewBasicRTMPVideoStream(&core.StreamParameters{ManifestID: mid, Profiles: profiles})
+	}
+
+	_, err := s.registerConnection(context.TODO(), newStream("a"), nil, PixelFormatNone())
+	require.Nil(err)
+
+	senders.info = newStubSenderInfo(3600)
+	_, err = s.registerConnection(context.TODO(), newStream("b"), nil, PixelFormatNone())
+	assert.Equal(errFundsLow, err)
+}
+
+func TestFundsStatusHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	defer func(fw *FundsWatcher) { BroadcastFunds = fw }(BroadcastFunds)
+	BroadcastFunds = nil
+
+	resp := httpGetResp(fundsStatusHandler())
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	senders := &stubSenderInfoGetter{err: errors.New("some error")}
+	BroadcastFunds = NewFundsWatcher(ethcommon.Address{}, senders, time.Hour, time.Hour)
+	resp = httpGetResp(fundsStatusHandler())
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+
+	senders.err = nil
+	senders.info = newStubSenderInfo(1000)
+	resp = httpGetResp(fundsStatusHandler())
+	require.Equal(http.StatusOK, resp.StatusCode)
+	body, _ := ioutil.ReadAll(resp.Body)
+	var status FundsStatus
+	require.Nil(json.Unmarshal(body, &status))
+	assert.Equal(big.NewInt(1000), status.Deposit)
+	assert.Equal(big.NewInt(500), status.Reserve)
+	assert.Equal(float64(-1), status.RunwaySeconds)
+}
@@ -1,23 +1,30 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
+	"strconv"
+	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/golang/glog"
 	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
 	"github.com/livepeer/go-livepeer/eth"
 	"github.com/livepeer/go-livepeer/eth/types"
 	"github.com/livepeer/go-livepeer/pm"
 )
 
-const MainnetChainId = 1
-const RinkebyChainId = 4
+// drainTimeout is how long a drain request waits for the running segments of the transcoder to be transcoded
+var drainTimeout = 2 * time.Minute
 
 func respondOk(w http.ResponseWriter, msg []byte) {
 	w.WriteHeader(http.StatusOK)
@@ -94,7 +101,7 @@ func currentBlockHandler(getter BlockGetter) http.Handler {
 
 func currentRoundHandler(client eth.LivepeerEthClient) http.Handler {
 	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		currentRound, err := client.CurrentRound()
+		currentRound, err := client.CurrentRound(r.Context())
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not query current round: %v", err))
 			return
@@ -105,6 +112,168 @@ func currentRoundHandler(client eth.LivepeerEthClient) http.Handler {
 	)
 }
 
+// ethAccountsHandler returns the ETH accounts that the node can send transactions from and the active account
+func ethAccountsHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var accts []ethcommon.Address
+		for _, acct := range client.Accounts() {
+			accts = append(accts, acct.Address)
+		}
+		data, err := json.Marshal(map[string]interface{}{
+			"active":   client.Account().Address,
+			"accounts": accts,
+		})
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	}),
+	)
+}
+
+// setEthAccountHandler makes the ETH account in the address param the account that the node sends transactions from
+// and signs with
+func setEthAccountHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addrStr := r.FormValue("address")
+		if !ethcommon.IsHexAddress(addrStr) {
+			respondWith400(w, "invalid address")
+			return
+		}
+
+		if err := client.SetAccount(ethcommon.HexToAddress(addrStr)); err != nil {
+			respondWith400(w, fmt.Sprintf("could not set account: %v", err))
+			return
+		}
+
+		respondOk(w, nil)
+	}),
+	)
+}
+
+// estimateTxCostHandler returns the estimated gas and ETH cost of the transaction that the CLI API would submit for
+// the method param with the same params as the endpoint of the method, without submitting it
+func estimateTxCostHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bigIntParam := func(name string) (*big.Int, error) {
+			v, err := common.ParseBigInt(r.FormValue(name))
+			if err != nil {
+				return nil, fmt.Errorf("invalid %v: %v", name, err)
+			}
+			return v, nil
+		}
+		addrParam := func(name string) (ethcommon.Address, error) {
+			v := r.FormValue(name)
+			if !ethcommon.IsHexAddress(v) {
+				return ethcommon.Address{}, fmt.Errorf("invalid %v", name)
+			}
+			return ethcommon.HexToAddress(v), nil
+		}
+
+		var send func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error)
+		var err error
+		switch method := r.FormValue("method"); method {
+		case "bond":
+			var amount *big.Int
+			var toAddr ethcommon.Address
+			if amount, err = bigIntParam("amount"); err == nil {
+				toAddr, err = addrParam("toAddr")
+			}
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				return c.Bond(ctx, amount, toAddr)
+			}
+		case "unbond":
+			var amount *big.Int
+			amount, err = bigIntParam("amount")
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				return c.Unbond(ctx, amount)
+			}
+		case "rebond":
+			var id *big.Int
+			var toAddr ethcommon.Address
+			if id, err = bigIntParam("unbondingLockId"); err == nil && r.FormValue("toAddr") != "" {
+				toAddr, err = addrParam("toAddr")
+			}
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				if toAddr != (ethcommon.Address{}) {
+					return c.RebondFromUnbonded(ctx, toAddr, id)
+				}
+				return c.Rebond(ctx, id)
+			}
+		case "withdrawStake":
+			var id *big.Int
+			id, err = bigIntParam("unbondingLockId")
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				return c.WithdrawStake(ctx, id)
+			}
+		case "withdrawFees":
+			var amount *big.Int
+			amount, err = bigIntParam("amount")
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				return c.WithdrawFees(ctx, c.Account().Address, amount)
+			}
+		case "claimEarnings":
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				round, err := c.CurrentRound(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return c.ClaimEarnings(ctx, round)
+			}
+		case "reward":
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				return c.Reward(ctx)
+			}
+		case "initializeRound":
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				return c.InitializeRound(ctx)
+			}
+		case "transferTokens":
+			var to ethcommon.Address
+			var amount *big.Int
+			if to, err = addrParam("to"); err == nil {
+				amount, err = bigIntParam("amount")
+			}
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				return c.Transfer(ctx, to, amount)
+			}
+		case "fundDepositAndReserve":
+			var deposit, reserve *big.Int
+			if deposit, err = bigIntParam("depositAmount"); err == nil {
+				reserve, err = bigIntParam("reserveAmount")
+			}
+			send = func(ctx context.Context, c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+				return c.FundDepositAndReserve(ctx, deposit, reserve)
+			}
+		default:
+			err = fmt.Errorf("unsupported method %v", method)
+		}
+		if err != nil {
+			respondWith400(w, err.Error())
+			return
+		}
+
+		cost, err := client.EstimateTxCost(r.Context(), func(c eth.LivepeerEthClient) (*ethtypes.Transaction, error) {
+			return send(r.Context(), c)
+		})
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not estimate transaction cost: %v", err))
+			return
+		}
+
+		data, err := json.Marshal(cost)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	}),
+	)
+}
+
 func fundDepositAndReserveHandler(client eth.LivepeerEthClient) http.Handler {
 	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		depositAmount, err := common.ParseBigInt(r.FormValue("depositAmount"))
@@ -119,13 +288,13 @@ func fundDepositAndReserveHandler(client eth.LivepeerEthClient) http.Handler {
 			return
 		}
 
-		tx, err := client.FundDepositAndReserve(depositAmount, reserveAmount)
+		tx, err := client.FundDepositAndReserve(r.Context(), depositAmount, reserveAmount)
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute fundDepositAndReserve: %v", err))
 			return
 		}
 
-		err = client.CheckTx(tx)
+		err = client.CheckTx(r.Context(), tx)
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute fundDepositAndReserve: %v", err))
 			return
@@ -144,13 +313,13 @@ func fundDepositHandler(client eth.LivepeerEthClient) http.Handler {
 			return
 		}
 
-		tx, err := client.FundDeposit(amount)
+		tx, err := client.FundDeposit(r.Context(), amount)
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute fundDeposit: %v", err))
 			return
 		}
 
-		err = client.CheckTx(tx)
+		err = client.CheckTx(r.Context(), tx)
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute fundDeposit: %v", err))
 			return
@@ -163,13 +332,13 @@ func fundDepositHandler(client eth.LivepeerEthClient) http.Handler {
 
 func unlockHandler(client eth.LivepeerEthClient) http.Handler {
 	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tx, err := client.Unlock()
+		tx, err := client.Unlock(r.Context())
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute unlock: %v", err))
 			return
 		}
 
-		err = client.CheckTx(tx)
+		err = client.CheckTx(r.Context(), tx)
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute unlock: %v", err))
 			return
@@ -182,13 +351,13 @@ func unlockHandler(client eth.LivepeerEthClient) http.Handler {
 
 func cancelUnlockHandler(client eth.LivepeerEthClient) http.Handler {
 	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tx, err := client.CancelUnlock()
+		tx, err := client.CancelUnlock(r.Context())
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute cancelUnlock: %v", err))
 			return
 		}
 
-		err = client.CheckTx(tx)
+		err = client.CheckTx(r.Context(), tx)
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute cancelUnlock: %v", err))
 			return
@@ -201,13 +370,13 @@ func cancelUnlockHandler(client eth.LivepeerEthClient) http.Handler {
 
 func withdrawHandler(client eth.LivepeerEthClient) http.Handler {
 	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tx, err := client.Withdraw()
+		tx, err := client.Withdraw(r.Context())
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute withdraw: %v", err))
 			return
 		}
 
-		err = client.CheckTx(tx)
+		err = client.CheckTx(r.Context(), tx)
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute withdraw: %v", err))
 			return
@@ -218,9 +387,54 @@ func withdrawHandler(client eth.LivepeerEthClient) http.Handler {
 	)
 }
 
+// EthBalance watches the ETH balance of the node's account. The ETH balance status is not available if nil
+var EthBalance *eth.BalanceWatcher
+
+func ethBalanceStatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if EthBalance == nil {
+			respondWith400(w, "ETH balance is not watched")
+			return
+		}
+		status, err := EthBalance.Status(r.Context(), time.Now())
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not get ETH balance: %v", err))
+			return
+		}
+		data, err := json.Marshal(status)
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not parse ETH balance: %v", err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	})
+}
+
+func fundsStatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if BroadcastFunds == nil {
+			respondWith400(w, "broadcaster funds are not watched")
+			return
+		}
+		status, err := BroadcastFunds.Status(time.Now())
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not get broadcaster funds: %v", err))
+			return
+		}
+		data, err := json.Marshal(status)
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not parse broadcaster funds: %v", err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	})
+}
+
 func senderInfoHandler(client eth.LivepeerEthClient) http.Handler {
 	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		info, err := client.GetSenderInfo(client.Account().Address)
+		info, err := client.GetSenderInfo(r.Context(), client.Account().Address)
 		if err != nil {
 			if err.Error() == "ErrNoResult" {
 				info = &pm.SenderInfo{
@@ -252,7 +466,7 @@ func senderInfoHandler(client eth.LivepeerEthClient) http.Handler {
 
 func ticketBrokerParamsHandler(client eth.LivepeerEthClient) http.Handler {
 	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		unlockPeriod, err := client.UnlockPeriod()
+		unlockPeriod, err := client.UnlockPeriod(r.Context())
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not query TicketBroker unlockPeriod: %v", err))
 			return
@@ -352,6 +566,7 @@ func voteHandler(client eth.LivepeerEthClient) http.Handler {
 
 		// submit tx
 		tx, err := client.Vote(
+			r.Context(),
 			ethcommon.HexToAddress(poll),
 			choiceID,
 		)
@@ -360,7 +575,7 @@ func voteHandler(client eth.LivepeerEthClient) http.Handler {
 			return
 		}
 
-		if err := client.CheckTx(tx); err != nil {
+		if err := client.CheckTx(r.Context(), tx); err != nil {
 			respondWith500(w, fmt.Sprintf("unable to mine vote transaction err=%q", err))
 			return
 		}
@@ -370,6 +585,38 @@ func voteHandler(client eth.LivepeerEthClient) http.Handler {
 	)
 }
 
+// pollsHandler responds with the governance polls created since the fromBlock param, or since genesis if not set,
+// with the latest vote of the node in each of them
+func pollsHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromBlock := big.NewInt(0)
+		if fromStr := r.FormValue("fromBlock"); fromStr != "" {
+			var ok bool
+			if fromBlock, ok = new(big.Int).SetString(fromStr, 10); !ok || fromBlock.Sign() < 0 {
+				respondWith400(w, "fromBlock is not a valid block number")
+				return
+			}
+		}
+
+		polls, err := client.Polls(r.Context(), fromBlock)
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not query polls: %v", err))
+			return
+		}
+		if polls == nil {
+			polls = []*types.Poll{}
+		}
+
+		data, err := json.Marshal(polls)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	}))
+}
+
 func withdrawFeesHandler(client eth.LivepeerEthClient, getChainId func() (int64, error)) http.Handler {
 	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// for L1 contracts backwards-compatibility
@@ -381,7 +628,7 @@ func withdrawFeesHandler(client eth.LivepeerEthClient, getChainId func() (int64,
 		}
 		if isL1Network {
 			// L1 contracts
-			tx, err = client.L1WithdrawFees()
+			tx, err = client.L1WithdrawFees(r.Context())
 			if err != nil {
 				respondWith500(w, fmt.Sprintf("could not execute WithdrawFees: %v", err))
 				return
@@ -399,14 +646,14 @@ func withdrawFeesHandler(client eth.LivepeerEthClient, getChainId func() (int64,
 				return
 			}
 
-			tx, err = client.WithdrawFees(client.Account().Address, amount)
+			tx, err = client.WithdrawFees(r.Context(), client.Account().Address, amount)
 			if err != nil {
 				respondWith500(w, fmt.Sprintf("could not execute WithdrawFees: %v", err))
 				return
 			}
 		}
 
-		err = client.CheckTx(tx)
+		err = client.CheckTx(r.Context(), tx)
 		if err != nil {
 			respondWith500(w, fmt.Sprintf("could not execute WithdrawFees: %v", err))
 			return
@@ -417,6 +664,31 @@ func withdrawFeesHandler(client eth.LivepeerEthClient, getChainId func() (int64,
 	)
 }
 
+// broadcastSignedTxHandler submits a transaction that was prepared by the node and signed on an offline machine
+func broadcastSignedTxHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawTx, err := hexutil.Decode(r.FormValue("tx"))
+		if err != nil {
+			respondWith400(w, fmt.Sprintf("invalid tx: %v", err))
+			return
+		}
+
+		tx, err := eth.SendSignedTransaction(r.Context(), client.Backend(), rawTx)
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not broadcast signed transaction: %v", err))
+			return
+		}
+
+		if err := client.CheckTx(r.Context(), tx); err != nil {
+			respondWith500(w, fmt.Sprintf("could not broadcast signed transaction: %v", err))
+			return
+		}
+
+		respondOk(w, []byte(tx.Hash().Hex()))
+	}),
+	)
+}
+
 func minGasPriceHandler(client eth.LivepeerEthClient) http.Handler {
 	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		respondOk(w, []byte(client.Backend().GasPriceMonitor().MinGasPrice().String()))
@@ -443,6 +715,622 @@ func isL1Network(getChainId func() (int64, error)) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	isL1Network := chainId == MainnetChainId || chainId == RinkebyChainId
-	return isL1Network, err
+	return eth.ChainByID(big.NewInt(chainId)).L1, nil
+}
+
+// streamEvent is an event of the stream event log in the responses of the CLI API
+type streamEvent struct {
+	Type    string          `json:"type"`
+	NodeID  string          `json:"nodeID"`
+	Time    time.Time       `json:"time"`
+	Details json.RawMessage `json:"details"`
+}
+
+// streamEventsHandler responds with the events of the stream in the manifestID param in the order they were created.
+// The events can be limited to the ones created at or after the RFC 3339 time in the since param
+func streamEventsHandler(db *common.DB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if db == nil || StreamEventsRetention <= 0 {
+			respondWith400(w, "stream event log is disabled")
+			return
+		}
+
+		var since time.Time
+		if sinceStr := r.FormValue("since"); sinceStr != "" {
+			var err error
+			since, err = time.Parse(time.RFC3339, sinceStr)
+			if err != nil {
+				respondWith400(w, fmt.Sprintf("invalid since param: %v", err))
+				return
+			}
+		}
+
+		events, err := db.StreamEvents(r.FormValue("manifestID"), since)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+
+		res := make([]streamEvent, 0, len(events))
+		for _, e := range events {
+			details := json.RawMessage(e.Details)
+			if !json.Valid(details) {
+				details = json.RawMessage("{}")
+			}
+			res = append(res, streamEvent{Type: e.Type, NodeID: e.NodeID, Time: e.CreatedAt, Details: details})
+		}
+		data, err := json.Marshal(res)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	})
+}
+
+// contractEvent is an indexed contract event in the responses of the CLI API
+type contractEvent struct {
+	BlockNumber uint64            `json:"blockNumber"`
+	BlockHash   ethcommon.Hash    `json:"blockHash"`
+	TxHash      ethcommon.Hash    `json:"txHash"`
+	LogIndex    uint              `json:"logIndex"`
+	Contract    ethcommon.Address `json:"contract"`
+	Name        string            `json:"name"`
+	Args        json.RawMessage   `json:"args"`
+}
+
+// contractEventsHandler responds with the indexed contract events in the order they were emitted. The events can be
+// filtered by the event name, an address that is an indexed argument of the event and a block range with the name,
+// address, fromBlock and toBlock params
+func contractEventsHandler(db *common.DB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if db == nil {
+			respondWith400(w, "contract event index is unavailable")
+			return
+		}
+
+		filter := &common.DBContractEventFilter{Name: r.FormValue("name")}
+		if addr := r.FormValue("address"); addr != "" {
+			if !ethcommon.IsHexAddress(addr) {
+				respondWith400(w, "invalid address")
+				return
+			}
+			a := ethcommon.HexToAddress(addr)
+			filter.Address = &a
+		}
+		for param, block := range map[string]**big.Int{"fromBlock": &filter.FromBlock, "toBlock": &filter.ToBlock} {
+			if blockStr := r.FormValue(param); blockStr != "" {
+				n, ok := new(big.Int).SetString(blockStr, 10)
+				if !ok {
+					respondWith400(w, fmt.Sprintf("%v is not a valid integer value", param))
+					return
+				}
+				*block = n
+			}
+		}
+
+		events, err := db.ContractEvents(filter)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+
+		res := make([]contractEvent, 0, len(events))
+		for _, e := range events {
+			res = append(res, contractEvent{
+				BlockNumber: e.BlockNumber,
+				BlockHash:   e.BlockHash,
+				TxHash:      e.TxHash,
+				LogIndex:    e.LogIndex,
+				Contract:    e.Contract,
+				Name:        e.Name,
+				Args:        json.RawMessage(e.Data),
+			})
+		}
+		data, err := json.Marshal(res)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	})
+}
+
+// txJournalEntry is an entry of the tx journal in the responses of the CLI API
+type txJournalEntry struct {
+	Hash        ethcommon.Hash  `json:"hash"`
+	Replaces    *ethcommon.Hash `json:"replaces,omitempty"`
+	Method      string          `json:"method"`
+	Inputs      string          `json:"inputs"`
+	Nonce       uint64          `json:"nonce"`
+	Gas         uint64          `json:"gas"`
+	GasPrice    string          `json:"gasPrice"`
+	Status      string          `json:"status"`
+	BlockNumber uint64          `json:"blockNumber,omitempty"`
+	GasUsed     uint64          `json:"gasUsed,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Time        time.Time       `json:"time"`
+}
+
+// txJournalHandler responds with the entries of the journal of the submitted txs in the order they were created. The
+// entries can be filtered by the contract method, the hash of a tx, which also matches the txs that replaced it, and
+// the RFC 3339 time they were created at or after with the method, hash and since params. The limit param restricts
+// the response to the latest entries
+func txJournalHandler(db *common.DB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if db == nil {
+			respondWith400(w, "transaction journal is unavailable")
+			return
+		}
+
+		filter := &common.DBTxJournalFilter{Method: r.FormValue("method")}
+		if hashStr := r.FormValue("hash"); hashStr != "" {
+			hash := ethcommon.HexToHash(hashStr)
+			filter.Hash = &hash
+		}
+		if sinceStr := r.FormValue("since"); sinceStr != "" {
+			since, err := time.Parse(time.RFC3339, sinceStr)
+			if err != nil {
+				respondWith400(w, fmt.Sprintf("invalid since param: %v", err))
+				return
+			}
+			filter.Since = since
+		}
+		if limitStr := r.FormValue("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				respondWith400(w, "limit is not a valid positive integer value")
+				return
+			}
+			filter.Limit = limit
+		}
+
+		entries, err := db.TxJournal(filter)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+
+		res := make([]txJournalEntry, 0, len(entries))
+		for _, e := range entries {
+			entry := txJournalEntry{
+				Hash:        e.Hash,
+				Method:      e.Method,
+				Inputs:      e.Inputs,
+				Nonce:       e.Nonce,
+				Gas:         e.Gas,
+				Status:      e.Status,
+				BlockNumber: e.BlockNumber,
+				GasUsed:     e.GasUsed,
+				Error:       e.Error,
+				Time:        e.CreatedAt,
+			}
+			if e.Replaces != (ethcommon.Hash{}) {
+				replaces := e.Replaces
+				entry.Replaces = &replaces
+			}
+			if e.GasPrice != nil {
+				entry.GasPrice = e.GasPrice.String()
+			}
+			res = append(res, entry)
+		}
+		data, err := json.Marshal(res)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	})
+}
+
+// tokenSpender returns the address of the spender param, or of the BondingManager if the param is not set
+func tokenSpender(client eth.LivepeerEthClient, r *http.Request) (ethcommon.Address, error) {
+	spender := r.FormValue("spender")
+	if spender == "" {
+		return client.ContractAddresses()["BondingManager"], nil
+	}
+	if !ethcommon.IsHexAddress(spender) {
+		return ethcommon.Address{}, errors.New("invalid spender")
+	}
+	return ethcommon.HexToAddress(spender), nil
+}
+
+// tokenAllowanceHandler responds with the amount of the tokens of the account that the spender param, or the
+// BondingManager if it is not set, is allowed to transfer
+func tokenAllowanceHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spender, err := tokenSpender(client, r)
+		if err != nil {
+			respondWith400(w, err.Error())
+			return
+		}
+
+		allowance, err := client.Allowance(r.Context(), client.Account().Address, spender)
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not get allowance: %v", err))
+			return
+		}
+
+		respondOk(w, []byte(allowance.String()))
+	}))
+}
+
+// approveTokensHandler allows the spender param, or the BondingManager if it is not set, to transfer the amount param
+// of the tokens of the account. An amount of max approves the transfer of any amount
+func approveTokensHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spender, err := tokenSpender(client, r)
+		if err != nil {
+			respondWith400(w, err.Error())
+			return
+		}
+
+		amount := math.MaxBig256
+		if amountStr := r.FormValue("amount"); amountStr != "max" {
+			if amount, err = common.ParseBigInt(amountStr); err != nil {
+				respondWith400(w, fmt.Sprintf("invalid amount: %v", err))
+				return
+			}
+		}
+
+		tx, err := client.Approve(r.Context(), spender, amount)
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not execute approve: %v", err))
+			return
+		}
+
+		if err := client.CheckTx(r.Context(), tx); err != nil {
+			respondWith500(w, fmt.Sprintf("could not mine approve: %v", err))
+			return
+		}
+
+		respondOk(w, []byte(tx.Hash().Hex()))
+	}))
+}
+
+// pendingTx is a transaction pending approval in the responses of the CLI API
+type pendingTx struct {
+	ID     uint64            `json:"id"`
+	Method string            `json:"method"`
+	Inputs string            `json:"inputs"`
+	From   ethcommon.Address `json:"from"`
+	Cost   string            `json:"cost"`
+	Reason string            `json:"reason"`
+	Time   time.Time         `json:"time"`
+}
+
+// pendingApprovalsHandler responds with the transactions that are held because they exceed the spend limits
+func pendingApprovalsHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pending := client.PendingApprovals()
+		res := make([]pendingTx, 0, len(pending))
+		for _, p := range pending {
+			res = append(res, pendingTx{
+				ID:     p.ID,
+				Method: p.Method,
+				Inputs: p.Inputs,
+				From:   p.From,
+				Cost:   p.Cost.String(),
+				Reason: p.Reason,
+				Time:   p.CreatedAt,
+			})
+		}
+		data, err := json.Marshal(res)
+		if err != nil {
+			respondWith500(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respondOk(w, data)
+	}))
+}
+
+// approveTransactionHandler sends the transaction pending approval with the id param regardless of the spend limits
+// and responds with its hash once it is mined
+func approveTransactionHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.FormValue("id"), 10, 64)
+		if err != nil {
+			respondWith400(w, "id is not a valid integer value")
+			return
+		}
+
+		tx, err := client.ApproveTransaction(r.Context(), id)
+		if errors.Is(err, eth.ErrUnknownPendingTx) {
+			respondWith400(w, err.Error())
+			return
+		}
+		if err != nil {
+			respondWith500(w, fmt.Sprintf("could not send approved transaction: %v", err))
+			return
+		}
+
+		if err := client.CheckTx(r.Context(), tx); err != nil {
+			respondWith500(w, fmt.Sprintf("could not mine approved transaction: %v", err))
+			return
+		}
+
+		respondOk(w, []byte(tx.Hash().Hex()))
+	}))
+}
+
+// rejectTransactionHandler drops the transaction pending approval with the id param without sending it
+func rejectTransactionHandler(client eth.LivepeerEthClient) http.Handler {
+	return mustHaveClient(client, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.FormValue("id"), 10, 64)
+		if err != nil {
+			respondWith400(w, "id is not a valid integer value")
+			return
+		}
+
+		if err := client.RejectTransaction(id); err != nil {
+			respondWith400(w, err.Error())
+			return
+		}
+
+		respondOk(w, nil)
+	}))
+}
+
+// drainHandler stops the standalone transcoder from receiving new segments and responds once the segments that it is
+// transcoding are done, so that it can be used as the pre-stop hook of the transcoder
+func drainHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			respondWithError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		select {
+		case <-transcoderDrainer.drain():
+			respondOk(w, []byte("drained"))
+		case <-time.After(drainTimeout):
+			respondWith500(w, "timed out waiting for running segments to be transcoded")
+		case <-r.Context().Done():
+		}
+	})
+}
+
+// maintenanceHandler returns whether the orchestrator is in maintenance on GET and puts the orchestrator into or takes
+// it out of maintenance according to the enabled param on POST. If the deregister param is set when entering
+// maintenance, the self-bonded stake of the orchestrator is also unbonded so that it leaves the active set. The stake
+// has to be rebonded to rejoin the active set after maintenance
+func maintenanceHandler(node *core.LivepeerNode) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			data, err := json.Marshal(map[string]bool{"maintenance": node.InMaintenance()})
+			if err != nil {
+				respondWith500(w, err.Error())
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			respondOk(w, data)
+		case http.MethodPost:
+			if err := r.ParseForm(); err != nil {
+				respondWith400(w, fmt.Sprintf("parse form error: %v", err))
+				return
+			}
+			enabled, err := strconv.ParseBool(r.FormValue("enabled"))
+			if err != nil {
+				respondWith400(w, "enabled is not a valid boolean value")
+				return
+			}
+			deregister := false
+			if deregisterStr := r.FormValue("deregister"); deregisterStr != "" {
+				if deregister, err = strconv.ParseBool(deregisterStr); err != nil {
+					respondWith400(w, "deregister is not a valid boolean value")
+					return
+				}
+			}
+			if deregister && !enabled {
+				respondWith400(w, "can only deregister when entering maintenance")
+				return
+			}
+			if deregister && node.Eth == nil {
+				respondWith500(w, "missing ETH client")
+				return
+			}
+
+			node.SetMaintenance(enabled)
+			glog.Infof("Set maintenance to %v", enabled)
+
+			if deregister {
+				if err := unbondSelfStake(r.Context(), node.Eth); err != nil {
+					respondWith500(w, fmt.Sprintf("could not deregister: %v", err))
+					return
+				}
+			}
+			respondOk(w, nil)
+		default:
+			respondWithError(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// unbondSelfStake unbonds all of the stake that the orchestrator bonded to itself, which removes it from the
+// transcoder pool
+func unbondSelfStake(ctx context.Context, client eth.LivepeerEthClient) error {
+	addr := client.Account().Address
+	d, err := client.GetDelegator(ctx, addr)
+	if err != nil {
+		return fmt.Errorf("could not get delegator: %v", err)
+	}
+	if d.DelegateAddress != addr || d.BondedAmount == nil || d.BondedAmount.Sign() == 0 {
+		// Not registered
+		return nil
+	}
+
+	tx, err := client.Unbond(ctx, d.BondedAmount)
+	if err != nil {
+		return err
+	}
+	return client.CheckTx(ctx, tx)
+}
+
+type scheduledOrchestratorConfig struct {
+	Round          *big.Int `json:"round"`
+	BlockRewardCut *float64 `json:"blockRewardCut,omitempty"`
+	FeeShare       *float64 `json:"feeShare,omitempty"`
+	PricePerPixel  string   `json:"pricePerPixel,omitempty"`
+}
+
+// orchestratorConfigScheduleHandler returns the orchestrator config changes that are scheduled for future rounds on
+// GET, schedules the blockRewardCut, feeShare and pricePerUnit/pixelsPerUnit params to take effect at the round param
+// on POST and cancels the change scheduled for the round param on DELETE
+func orchestratorConfigScheduleHandler(node *core.LivepeerNode) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if node.ParamsScheduler == nil {
+			respondWith500(w, "orchestrator config scheduling is not enabled")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			scheduled := []scheduledOrchestratorConfig{}
+			for _, params := range node.ParamsScheduler.Scheduled() {
+				config := scheduledOrchestratorConfig{Round: params.Round}
+				if params.RewardCut != nil {
+					rewardCut := eth.ToPerc(params.RewardCut)
+					config.BlockRewardCut = &rewardCut
+				}
+				if params.FeeShare != nil {
+					feeShare := eth.ToPerc(params.FeeShare)
+					config.FeeShare = &feeShare
+				}
+				if params.PricePerPixel != nil {
+					config.PricePerPixel = params.PricePerPixel.FloatString(3)
+				}
+				scheduled = append(scheduled, config)
+			}
+			data, err := json.Marshal(scheduled)
+			if err != nil {
+				respondWith500(w, err.Error())
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			respondOk(w, data)
+		case http.MethodPost:
+			if err := r.ParseForm(); err != nil {
+				respondWith400(w, fmt.Sprintf("parse form error: %v", err))
+				return
+			}
+			round, err := common.ParseBigInt(r.FormValue("round"))
+			if err != nil {
+				respondWith400(w, fmt.Sprintf("invalid round: %v", err))
+				return
+			}
+			params := &eth.ScheduledParams{Round: round}
+			if rewardCutStr := r.FormValue("blockRewardCut"); rewardCutStr != "" {
+				rewardCut, err := strconv.ParseFloat(rewardCutStr, 64)
+				if err != nil {
+					respondWith400(w, fmt.Sprintf("invalid blockRewardCut: %v", err))
+					return
+				}
+				params.RewardCut = eth.FromPerc(rewardCut)
+			}
+			if feeShareStr := r.FormValue("feeShare"); feeShareStr != "" {
+				feeShare, err := strconv.ParseFloat(feeShareStr, 64)
+				if err != nil {
+					respondWith400(w, fmt.Sprintf("invalid feeShare: %v", err))
+					return
+				}
+				params.FeeShare = eth.FromPerc(feeShare)
+			}
+			pricePerUnitStr, pixelsPerUnitStr := r.FormValue("pricePerUnit"), r.FormValue("pixelsPerUnit")
+			if pricePerUnitStr != "" || pixelsPerUnitStr != "" {
+				pricePerUnit, pixelsPerUnit, err := parsePriceInfo(pricePerUnitStr, pixelsPerUnitStr)
+				if err != nil {
+					respondWith400(w, err.Error())
+					return
+				}
+				params.PricePerPixel = big.NewRat(pricePerUnit, pixelsPerUnit)
+			}
+
+			if err := node.ParamsScheduler.Schedule(params); err != nil {
+				respondWith400(w, fmt.Sprintf("could not schedule config: %v", err))
+				return
+			}
+			respondOk(w, nil)
+		case http.MethodDelete:
+			round, err := common.ParseBigInt(r.FormValue("round"))
+			if err != nil {
+				respondWith400(w, fmt.Sprintf("invalid round: %v", err))
+				return
+			}
+			if !node.ParamsScheduler.Cancel(round) {
+				respondWithError(w, fmt.Sprintf("no config scheduled for round %v", round), http.StatusNotFound)
+				return
+			}
+			respondOk(w, nil)
+		default:
+			respondWithError(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// faultsHandler lists the injected faults on GET, sets a fault from a JSON common.FaultConfig on POST and clears the
+// fault in the fault param, or all faults if it is not set, on DELETE. Faults are only available in builds with the
+// faults tag
+func faultsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			data, err := json.Marshal(common.Faults())
+			if err != nil {
+				respondWith500(w, err.Error())
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			respondOk(w, data)
+		case http.MethodPost:
+			var c common.FaultConfig
+			if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+				respondWith400(w, fmt.Sprintf("invalid fault config: %v", err))
+				return
+			}
+			if err := common.SetFault(c); err != nil {
+				respondWith400(w, err.Error())
+				return
+			}
+			respondOk(w, nil)
+		case http.MethodDelete:
+			common.ClearFault(common.Fault(r.URL.Query().Get("fault")))
+			respondOk(w, nil)
+		default:
+			respondWithError(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// issueBroadcasterTokenHandler issues a broadcaster token for the broadcaster address param. The token expires after the
+// ttl param, i.e. 720h, or never if ttl is not set
+func issueBroadcasterTokenHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(BroadcasterTokenSecret) == 0 {
+			respondWith400(w, "broadcaster tokens are not enabled, start the orchestrator with -broadcasterTokenSecret")
+			return
+		}
+
+		addr := r.FormValue("address")
+		if !ethcommon.IsHexAddress(addr) {
+			respondWith400(w, "invalid address")
+			return
+		}
+
+		var expiration time.Time
+		if ttl := r.FormValue("ttl"); ttl != "" {
+			d, err := time.ParseDuration(ttl)
+			if err != nil || d <= 0 {
+				respondWith400(w, fmt.Sprintf("invalid ttl %q", ttl))
+				return
+			}
+			expiration = time.Now().Add(d)
+		}
+
+		respondOk(w, []byte(IssueBroadcasterToken(BroadcasterTokenSecret, ethcommon.HexToAddress(addr), expiration)))
+	})
 }
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,11 +13,15 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
 	"github.com/livepeer/go-livepeer/eth"
+	lpTypes "github.com/livepeer/go-livepeer/eth/types"
 	"github.com/livepeer/go-livepeer/pm"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -892,6 +897,54 @@ func TestVoteHandler(t *testing.T) {
 	assert.Equal((types.NewTx(&types.DynamicFeeTx{})).Hash().Bytes(), body)
 }
 
+func TestPollsHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	resp := httpGetResp(pollsHandler(nil))
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+
+	client := &eth.StubClient{}
+	handler := pollsHandler(client)
+
+	form := url.Values{"fromBlock": {"foo"}}
+	resp = httpPostFormResp(handler, strings.NewReader(form.Encode()))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("fromBlock is not a valid block number", strings.TrimSpace(string(body)))
+
+	client.Err = errors.New("unknown PollCreator address")
+	resp = httpGetResp(handler)
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("could not query polls: unknown PollCreator address", strings.TrimSpace(string(body)))
+	client.Err = nil
+
+	// No polls
+	resp = httpGetResp(handler)
+	body, _ = ioutil.ReadAll(resp.Body)
+	require.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("[]", string(body))
+
+	no := lpTypes.No
+	client.GovernancePolls = []*lpTypes.Poll{
+		{Address: ethcommon.HexToAddress("111"), Proposal: "QmA", EndBlock: big.NewInt(100), Active: true, Vote: &no},
+		{Address: ethcommon.HexToAddress("222"), Proposal: "QmB", EndBlock: big.NewInt(50)},
+	}
+	form = url.Values{"fromBlock": {"10"}}
+	resp = httpPostFormResp(handler, strings.NewReader(form.Encode()))
+	body, _ = ioutil.ReadAll(resp.Body)
+	require.Equal(http.StatusOK, resp.StatusCode)
+	var polls []*lpTypes.Poll
+	require.Nil(json.Unmarshal(body, &polls))
+	require.Len(polls, 2)
+	assert.Equal("QmA", polls[0].Proposal)
+	assert.True(polls[0].Active)
+	require.NotNil(polls[0].Vote)
+	assert.Equal(lpTypes.No, *polls[0].Vote)
+	assert.Nil(polls[1].Vote)
+}
+
 func TestWithdrawFeesHandler_MissingClient(t *testing.T) {
 	handler := withdrawFeesHandler(nil, stubChainIdProvider)
 
@@ -1021,6 +1074,224 @@ func TestL1WithdrawFeesHandler_Success(t *testing.T) {
 	assert.Equal(http.StatusOK, resp.StatusCode)
 }
 
+func TestBroadcastSignedTxHandler_MissingClient(t *testing.T) {
+	handler := broadcastSignedTxHandler(nil)
+
+	resp := httpGetResp(handler)
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("missing ETH client", strings.TrimSpace(string(body)))
+}
+
+func TestBroadcastSignedTxHandler_InvalidTx(t *testing.T) {
+	client := &eth.MockClient{}
+	handler := broadcastSignedTxHandler(client)
+
+	form := url.Values{
+		"tx": {"foo"},
+	}
+	resp := httpPostFormResp(handler, strings.NewReader(form.Encode()))
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(strings.TrimSpace(string(body)), "invalid tx")
+}
+
+func TestFaultsHandler_Unavailable(t *testing.T) {
+	if common.FaultsEnabled {
+		t.Skip("faults are available in builds with the faults tag")
+	}
+	assert := assert.New(t)
+	handler := faultsHandler()
+
+	resp := httpGetResp(handler)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("[]", string(body))
+
+	resp = httpPostResp(handler, strings.NewReader(`{"fault":"storage"}`), nil)
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("fault injection is not available in this build, rebuild with -tags faults", strings.TrimSpace(string(body)))
+
+	resp = httpResp(handler, "PUT", nil, nil)
+	assert.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestDrainHandler(t *testing.T) {
+	assert := assert.New(t)
+	defer func(d *drainer, timeout time.Duration) {
+		transcoderDrainer = d
+		drainTimeout = timeout
+	}(transcoderDrainer, drainTimeout)
+	transcoderDrainer = newDrainer()
+	drainTimeout = 50 * time.Millisecond
+	handler := drainHandler()
+
+	resp := httpGetResp(handler)
+	assert.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+	select {
+	case <-transcoderDrainer.draining:
+		assert.Fail("draining on GET")
+	default:
+	}
+
+	// Running segments are not transcoded in time
+	resp = httpPostResp(handler, nil, nil)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("timed out waiting for running segments to be transcoded", strings.TrimSpace(string(body)))
+
+	close(transcoderDrainer.drained)
+	resp = httpPostResp(handler, nil, nil)
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("drained", string(body))
+}
+
+func TestEthAccountsHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	addr := ethcommon.HexToAddress("0x1111")
+	resp := httpGetResp(ethAccountsHandler(&eth.StubClient{TranscoderAddress: addr}))
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	var res struct {
+		Active   ethcommon.Address
+		Accounts []ethcommon.Address
+	}
+	require.Nil(json.NewDecoder(resp.Body).Decode(&res))
+	assert.Equal(addr, res.Active)
+	assert.Equal([]ethcommon.Address{addr}, res.Accounts)
+}
+
+func TestSetEthAccountHandler(t *testing.T) {
+	assert := assert.New(t)
+	handler := setEthAccountHandler(&eth.StubClient{})
+
+	resp := httpPostFormResp(handler, strings.NewReader("address=foo"))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("invalid address", strings.TrimSpace(string(body)))
+
+	resp = httpPostFormResp(handler, strings.NewReader("address=0x0000000000000000000000000000000000001111"))
+	assert.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func TestOrchestratorConfigScheduleHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	n, _ := core.NewLivepeerNode(nil, "", nil)
+	handler := orchestratorConfigScheduleHandler(n)
+
+	resp := httpGetResp(handler)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+
+	n.ParamsScheduler = eth.NewParamsScheduler(&eth.StubClient{}, &stubTimeManager{round: big.NewInt(100)}, n.SetBasePrice)
+
+	// Invalid params
+	resp = httpPostFormResp(handler, strings.NewReader("round=foo&feeShare=10"))
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	resp = httpPostFormResp(handler, strings.NewReader("round=101&pricePerUnit=1"))
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	resp = httpPostFormResp(handler, strings.NewReader("round=100&feeShare=10"))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("could not schedule config: round 100 is not after the current round 100", strings.TrimSpace(string(body)))
+
+	resp = httpPostFormResp(handler, strings.NewReader("round=101&blockRewardCut=5&feeShare=10&pricePerUnit=3&pixelsPerUnit=2"))
+	require.Equal(http.StatusOK, resp.StatusCode)
+	resp = httpPostFormResp(handler, strings.NewReader("round=102&pricePerUnit=1&pixelsPerUnit=1"))
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	resp = httpGetResp(handler)
+	require.Equal(http.StatusOK, resp.StatusCode)
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.JSONEq(`[{"round":101,"blockRewardCut":5,"feeShare":10,"pricePerPixel":"1.500"},{"round":102,"pricePerPixel":"1.000"}]`, string(body))
+
+	// Cancel
+	resp = httpResp(handler, "DELETE", strings.NewReader(""), nil)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	req := httptest.NewRequest("DELETE", "http://example.com?round=103", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(http.StatusNotFound, w.Result().StatusCode)
+	req = httptest.NewRequest("DELETE", "http://example.com?round=101", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(http.StatusOK, w.Result().StatusCode)
+	assert.Len(n.ParamsScheduler.Scheduled(), 1)
+}
+
+func TestMaintenanceHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	n, _ := core.NewLivepeerNode(nil, "", nil)
+	handler := maintenanceHandler(n)
+
+	status := func() map[string]bool {
+		resp := httpGetResp(handler)
+		require.Equal(http.StatusOK, resp.StatusCode)
+		var res map[string]bool
+		require.Nil(json.NewDecoder(resp.Body).Decode(&res))
+		return res
+	}
+	assert.Equal(map[string]bool{"maintenance": false}, status())
+
+	resp := httpPostFormResp(handler, strings.NewReader("enabled=foo"))
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	resp = httpPostFormResp(handler, strings.NewReader("enabled=false&deregister=true"))
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	resp = httpPostFormResp(handler, strings.NewReader("enabled=true&deregister=true"))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("missing ETH client", strings.TrimSpace(string(body)))
+	assert.False(n.InMaintenance())
+
+	resp = httpPostFormResp(handler, strings.NewReader("enabled=true"))
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(map[string]bool{"maintenance": true}, status())
+	resp = httpPostFormResp(handler, strings.NewReader("enabled=false"))
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.False(n.InMaintenance())
+
+	// Deregistering unbonds the self-bonded stake
+	client := &eth.MockClient{}
+	n.Eth = client
+	addr := pm.RandAddress()
+	client.On("Account").Return(accounts.Account{Address: addr})
+	client.On("GetDelegator", addr).Return(nil, errors.New("GetDelegator error")).Once()
+	resp = httpPostFormResp(handler, strings.NewReader("enabled=true&deregister=true"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("could not deregister: could not get delegator: GetDelegator error", strings.TrimSpace(string(body)))
+	assert.True(n.InMaintenance())
+
+	client.On("GetDelegator", addr).Return(&lpTypes.Delegator{DelegateAddress: addr, BondedAmount: big.NewInt(100)}, nil).Once()
+	client.On("Unbond", big.NewInt(100)).Return(nil, nil).Once()
+	client.On("CheckTx").Return(nil).Once()
+	resp = httpPostFormResp(handler, strings.NewReader("enabled=true&deregister=true"))
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	client.AssertExpectations(t)
+
+	// Stake that is delegated to another orchestrator is not unbonded
+	client.On("GetDelegator", addr).Return(&lpTypes.Delegator{DelegateAddress: pm.RandAddress(), BondedAmount: big.NewInt(100)}, nil).Once()
+	resp = httpPostFormResp(handler, strings.NewReader("enabled=true&deregister=true"))
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	client.AssertNumberOfCalls(t, "Unbond", 1)
+
+	req := httptest.NewRequest(http.MethodPut, "http://example.com", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(http.StatusMethodNotAllowed, w.Code)
+}
+
 func stubL1ChainIdProvider() (int64, error) {
 	return 1, nil
 }
@@ -1053,3 +1324,340 @@ func httpResp(handler http.Handler, method string, body io.Reader, headers map[s
 
 	return w.Result()
 }
+
+func TestContractEventsHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	get := func(handler http.Handler, query string) (int, string) {
+		req := httptest.NewRequest("GET", "/contractEvents?"+query, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		body, _ := ioutil.ReadAll(rr.Result().Body)
+		return rr.Code, string(body)
+	}
+
+	code, body := get(contractEventsHandler(nil), "")
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal("contract event index is unavailable", strings.TrimSpace(body))
+
+	handler := contractEventsHandler(dbh)
+	code, body = get(handler, "address=foo")
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal("invalid address", strings.TrimSpace(body))
+	code, body = get(handler, "toBlock=foo")
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal("toBlock is not a valid integer value", strings.TrimSpace(body))
+
+	code, body = get(handler, "")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("[]", body)
+
+	orch, delegator := pm.RandAddress(), pm.RandAddress()
+	require.Nil(dbh.InsertContractEvent(&common.DBContractEvent{BlockNumber: 1, Name: "Reward", Addresses: []ethcommon.Address{orch}, Data: `{"amount":1}`}))
+	require.Nil(dbh.InsertContractEvent(&common.DBContractEvent{BlockNumber: 2, Name: "Transfer", Addresses: []ethcommon.Address{orch, delegator}, Data: `{"value":2}`}))
+	require.Nil(dbh.InsertContractEvent(&common.DBContractEvent{BlockNumber: 3, Name: "Reward", Addresses: []ethcommon.Address{orch}, Data: `{"amount":3}`}))
+
+	var events []contractEvent
+	code, body = get(handler, "name=Reward&fromBlock=2")
+	require.Equal(http.StatusOK, code)
+	require.Nil(json.Unmarshal([]byte(body), &events))
+	require.Len(events, 1)
+	assert.Equal(uint64(3), events[0].BlockNumber)
+	assert.Equal("Reward", events[0].Name)
+	assert.JSONEq(`{"amount":3}`, string(events[0].Args))
+
+	code, body = get(handler, "address="+delegator.Hex())
+	require.Equal(http.StatusOK, code)
+	require.Nil(json.Unmarshal([]byte(body), &events))
+	require.Len(events, 1)
+	assert.Equal("Transfer", events[0].Name)
+}
+
+func TestTxJournalHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	get := func(handler http.Handler, query string) (int, string) {
+		req := httptest.NewRequest("GET", "/txJournal?"+query, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		body, _ := ioutil.ReadAll(rr.Result().Body)
+		return rr.Code, string(body)
+	}
+
+	code, body := get(txJournalHandler(nil), "")
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal("transaction journal is unavailable", strings.TrimSpace(body))
+
+	handler := txJournalHandler(dbh)
+	code, body = get(handler, "since=foo")
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Contains(body, "invalid since param")
+	code, body = get(handler, "limit=0")
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal("limit is not a valid positive integer value", strings.TrimSpace(body))
+
+	code, body = get(handler, "")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("[]", body)
+
+	hash, replacement := pm.RandHash(), pm.RandHash()
+	now := time.Now()
+	require.Nil(dbh.InsertTxJournalEntry(&common.DBTxJournalEntry{Hash: hash, Method: "claimEarnings", GasPrice: big.NewInt(10), Status: "sent", CreatedAt: now}))
+	require.Nil(dbh.InsertTxJournalEntry(&common.DBTxJournalEntry{Hash: replacement, Replaces: hash, Method: "claimEarnings", GasPrice: big.NewInt(11), Status: "sent", CreatedAt: now.Add(time.Second)}))
+	require.Nil(dbh.InsertTxJournalEntry(&common.DBTxJournalEntry{Hash: replacement, Method: "claimEarnings", Status: "mined", BlockNumber: 5, CreatedAt: now.Add(2 * time.Second)}))
+	require.Nil(dbh.InsertTxJournalEntry(&common.DBTxJournalEntry{Hash: pm.RandHash(), Method: "reward", Status: "send_failed", Error: "nonce too low", CreatedAt: now.Add(3 * time.Second)}))
+
+	var entries []txJournalEntry
+	code, body = get(handler, "hash="+hash.Hex())
+	require.Equal(http.StatusOK, code)
+	require.Nil(json.Unmarshal([]byte(body), &entries))
+	require.Len(entries, 2)
+	assert.Equal(hash, entries[0].Hash)
+	assert.Nil(entries[0].Replaces)
+	assert.Equal("10", entries[0].GasPrice)
+	assert.Equal(replacement, entries[1].Hash)
+	assert.Equal(&hash, entries[1].Replaces)
+
+	code, body = get(handler, "method=claimEarnings&limit=1")
+	require.Equal(http.StatusOK, code)
+	require.Nil(json.Unmarshal([]byte(body), &entries))
+	require.Len(entries, 1)
+	assert.Equal("mined", entries[0].Status)
+	assert.Equal(uint64(5), entries[0].BlockNumber)
+
+	code, body = get(handler, "since="+now.Add(3*time.Second).UTC().Format(time.RFC3339Nano))
+	require.Equal(http.StatusOK, code)
+	require.Nil(json.Unmarshal([]byte(body), &entries))
+	require.Len(entries, 1)
+	assert.Equal("reward", entries[0].Method)
+	assert.Equal("nonce too low", entries[0].Error)
+}
+
+type stubBalanceReader struct {
+	balance *big.Int
+	err     error
+}
+
+func (r *stubBalanceReader) BalanceAt(ctx context.Context, account ethcommon.Address, blockNumber *big.Int) (*big.Int, error) {
+	return r.balance, r.err
+}
+
+func TestEthBalanceStatusHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	defer func(bw *eth.BalanceWatcher) { EthBalance = bw }(EthBalance)
+	EthBalance = nil
+
+	resp := httpGetResp(ethBalanceStatusHandler())
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	balances := &stubBalanceReader{err: errors.New("some error")}
+	EthBalance = eth.NewBalanceWatcher(ethcommon.Address{}, balances, dbh, big.NewInt(5000000), time.Hour, []string{"reward"})
+	resp = httpGetResp(ethBalanceStatusHandler())
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+
+	balances.err = nil
+	balances.balance = big.NewInt(4000000)
+	require.Nil(dbh.InsertTxJournalEntry(&common.DBTxJournalEntry{Hash: pm.RandHash(), Method: "reward", GasPrice: big.NewInt(10), GasUsed: 100000, Status: "mined", CreatedAt: time.Now()}))
+	resp = httpGetResp(ethBalanceStatusHandler())
+	require.Equal(http.StatusOK, resp.StatusCode)
+	body, _ := ioutil.ReadAll(resp.Body)
+	var status eth.BalanceStatus
+	require.Nil(json.Unmarshal(body, &status))
+	assert.Equal(big.NewInt(4000000), status.Balance)
+	assert.Equal(big.NewInt(1000000), status.SpendRate)
+	assert.Equal(float64(14400), status.RunwaySeconds)
+	assert.True(status.Low)
+	assert.Equal([]string{"reward"}, status.Halted)
+}
+
+func TestEstimateTxCostHandler(t *testing.T) {
+	assert := assert.New(t)
+	client := &eth.StubClient{}
+	handler := estimateTxCostHandler(client)
+
+	resp := httpPostFormResp(handler, strings.NewReader("method=foo"))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("unsupported method foo", strings.TrimSpace(string(body)))
+
+	resp = httpPostFormResp(handler, strings.NewReader("method=bond&amount=foo&toAddr=0x0000000000000000000000000000000000001111"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(string(body), "invalid amount")
+
+	resp = httpPostFormResp(handler, strings.NewReader("method=bond&amount=10&toAddr=foo"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("invalid toAddr", strings.TrimSpace(string(body)))
+
+	resp = httpPostFormResp(handler, strings.NewReader("method=bond&amount=10&toAddr=0x0000000000000000000000000000000000001111"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	var cost eth.TxCost
+	assert.Nil(json.Unmarshal(body, &cost))
+	assert.Equal(big.NewInt(0), cost.Cost)
+
+	client.Err = errors.New("execution reverted")
+	resp = httpPostFormResp(handler, strings.NewReader("method=reward"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("could not estimate transaction cost: execution reverted", strings.TrimSpace(string(body)))
+}
+
+func TestPendingApprovalsHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	client := &eth.StubClient{}
+	handler := pendingApprovalsHandler(client)
+
+	resp := httpGetResp(handler)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("[]", string(body))
+
+	now := time.Now().UTC()
+	client.PendingTxs = []*eth.PendingTx{
+		{ID: 2, Method: "reward", Cost: big.NewInt(1010), Reason: "cost 1010 exceeds max transaction cost 1000", CreatedAt: now},
+	}
+	resp = httpGetResp(handler)
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	var pending []pendingTx
+	require.Nil(json.Unmarshal(body, &pending))
+	require.Len(pending, 1)
+	assert.Equal(uint64(2), pending[0].ID)
+	assert.Equal("reward", pending[0].Method)
+	assert.Equal("1010", pending[0].Cost)
+	assert.Equal("cost 1010 exceeds max transaction cost 1000", pending[0].Reason)
+	assert.True(now.Equal(pending[0].Time))
+}
+
+func TestApproveTransactionHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &eth.StubClient{}
+	handler := approveTransactionHandler(client)
+
+	resp := httpPostFormResp(handler, strings.NewReader("id=foo"))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("id is not a valid integer value", strings.TrimSpace(string(body)))
+
+	resp = httpPostFormResp(handler, strings.NewReader("id=1"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(types.NewTx(&types.DynamicFeeTx{}).Hash().Hex(), string(body))
+
+	client.Err = fmt.Errorf("%w id=1", eth.ErrUnknownPendingTx)
+	resp = httpPostFormResp(handler, strings.NewReader("id=1"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("no transaction pending approval id=1", strings.TrimSpace(string(body)))
+
+	client.Err = errors.New("insufficient funds")
+	resp = httpPostFormResp(handler, strings.NewReader("id=1"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("could not send approved transaction: insufficient funds", strings.TrimSpace(string(body)))
+
+	client.Err = nil
+	client.CheckTxErr = errors.New("transaction reverted")
+	resp = httpPostFormResp(handler, strings.NewReader("id=1"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("could not mine approved transaction: transaction reverted", strings.TrimSpace(string(body)))
+}
+
+func TestRejectTransactionHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &eth.StubClient{}
+	handler := rejectTransactionHandler(client)
+
+	resp := httpPostFormResp(handler, strings.NewReader("id=-1"))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("id is not a valid integer value", strings.TrimSpace(string(body)))
+
+	resp = httpPostFormResp(handler, strings.NewReader("id=1"))
+	assert.Equal(http.StatusOK, resp.StatusCode)
+
+	client.Err = fmt.Errorf("%w id=1", eth.ErrUnknownPendingTx)
+	resp = httpPostFormResp(handler, strings.NewReader("id=1"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("no transaction pending approval id=1", strings.TrimSpace(string(body)))
+}
+
+func TestTokenAllowanceHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &eth.StubClient{}
+	handler := tokenAllowanceHandler(client)
+
+	resp := httpGetResp(handler)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("0", string(body))
+
+	resp = httpPostFormResp(handler, strings.NewReader("spender=foo"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("invalid spender", strings.TrimSpace(string(body)))
+
+	client.Err = errors.New("execution reverted")
+	resp = httpPostFormResp(handler, strings.NewReader("spender=0x1111111111111111111111111111111111111111"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("could not get allowance: execution reverted", strings.TrimSpace(string(body)))
+}
+
+func TestApproveTokensHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &eth.StubClient{}
+	handler := approveTokensHandler(client)
+
+	resp := httpPostFormResp(handler, strings.NewReader("amount=foo"))
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("invalid amount: failed to parse big integer", strings.TrimSpace(string(body)))
+
+	resp = httpPostFormResp(handler, strings.NewReader("amount=max&spender=0x1111111111111111111111111111111111111111"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(types.NewTx(&types.DynamicFeeTx{}).Hash().Hex(), string(body))
+
+	client.Err = errors.New("insufficient funds")
+	resp = httpPostFormResp(handler, strings.NewReader("amount=100"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("could not execute approve: insufficient funds", strings.TrimSpace(string(body)))
+
+	client.Err = nil
+	client.CheckTxErr = errors.New("transaction reverted")
+	resp = httpPostFormResp(handler, strings.NewReader("amount=100"))
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal("could not mine approve: transaction reverted", strings.TrimSpace(string(body)))
+}
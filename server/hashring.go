@@ -0,0 +1,43 @@
+package server
+
+import (
+	"hash/crc32"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// hashRingReplicas is the number of points of each node on the ring, which spreads the keys evenly across the nodes
+const hashRingReplicas = 100
+
+// hashRing maps keys to nodes by consistent hashing, so that adding or removing a node only moves the keys of that node
+type hashRing struct {
+	hashes []uint32
+	nodes  map[uint32]*url.URL
+}
+
+func newHashRing(nodes []*url.URL) *hashRing {
+	h := &hashRing{nodes: make(map[uint32]*url.URL)}
+	for _, node := range nodes {
+		for i := 0; i < hashRingReplicas; i++ {
+			hash := crc32.ChecksumIEEE([]byte(strconv.Itoa(i) + node.String()))
+			h.hashes = append(h.hashes, hash)
+			h.nodes[hash] = node
+		}
+	}
+	sort.Slice(h.hashes, func(i, j int) bool { return h.hashes[i] < h.hashes[j] })
+	return h
+}
+
+// get returns the node of key or nil if the ring has no nodes
+func (h *hashRing) get(key string) *url.URL {
+	if len(h.hashes) == 0 {
+		return nil
+	}
+	hash := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(h.hashes), func(i int) bool { return h.hashes[i] >= hash })
+	if i == len(h.hashes) {
+		i = 0
+	}
+	return h.nodes[h.hashes[i]]
+}
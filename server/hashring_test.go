@@ -0,0 +1,47 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashRing(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(newHashRing(nil).get("foo"))
+
+	var nodes []*url.URL
+	for i := 0; i < 4; i++ {
+		nodes = append(nodes, &url.URL{Scheme: "https", Host: fmt.Sprintf("127.0.0.1:%d", 8935+i)})
+	}
+	ring := newHashRing(nodes)
+
+	// Keys always map to the same node and are spread across all nodes
+	counts := make(map[string]int)
+	keys := make(map[string]*url.URL)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("manifest%d", i)
+		node := ring.get(key)
+		assert.Equal(node, ring.get(key))
+		keys[key] = node
+		counts[node.Host]++
+	}
+	assert.Len(counts, len(nodes))
+	for _, count := range counts {
+		assert.Greater(count, 100)
+	}
+
+	// Removing a node only moves its keys
+	removed := nodes[1]
+	ring = newHashRing(append([]*url.URL{nodes[0]}, nodes[2:]...))
+	for key, node := range keys {
+		if node != removed {
+			assert.Equal(node, ring.get(key))
+		} else {
+			assert.NotEqual(removed, ring.get(key))
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/livepeer/go-livepeer/clog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/lpms/ffmpeg"
+)
+
+// AutoLadderMaxHeight is the height of the top rendition of the ladders that are generated from the source of the
+// streams that use the default transcoding options. Ladders are not generated if 0
+var AutoLadderMaxHeight int
+
+// defaultAutoLadderMaxHeight is the height of the top rendition if -transcodingOptions is auto without a height
+const defaultAutoLadderMaxHeight = 1080
+
+// ladderRung is a rendition of the generated ladders with its bitrate at up to 30 fps in kbps
+type ladderRung struct {
+	height  int
+	bitrate int
+}
+
+// ladderRungs are the renditions of the generated ladders from the top, with the bitrates of the standard profiles
+var ladderRungs = []ladderRung{
+	{height: 1080, bitrate: 6000},
+	{height: 720, bitrate: 4000},
+	{height: 480, bitrate: 2000},
+	{height: 360, bitrate: 1000},
+	{height: 240, bitrate: 700},
+}
+
+// parseAutoLadder returns the height of the top rendition of the transcoding options auto or auto:<height>p, i.e.
+// auto:720p. Returns false if opts does not select generated ladders
+func parseAutoLadder(opts string) (int, bool, error) {
+	if opts != "auto" && !strings.HasPrefix(opts, "auto:") {
+		return 0, false, nil
+	}
+	if opts == "auto" {
+		return defaultAutoLadderMaxHeight, true, nil
+	}
+	height, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(opts, "auto:"), "p"))
+	if err != nil || height < ladderRungs[len(ladderRungs)-1].height {
+		return 0, false, fmt.Errorf("invalid auto transcoding options: %v", opts)
+	}
+	return height, true, nil
+}
+
+// generateLadder returns the renditions of a ladder for the source with the top rendition at most maxHeight high. The
+// renditions keep the aspect ratio of the source and are not higher than the source. Their bitrates are raised by half
+// for sources above 30 fps and capped at the bitrate of the source. The renditions below 720p are limited to half the
+// framerate of sources above 30 fps, the others keep the framerate of the source
+func generateLadder(src *core.SourceInfo, maxHeight int) []ffmpeg.VideoProfile {
+	if src == nil || src.Width <= 0 || src.Height <= 0 {
+		return nil
+	}
+
+	top := maxHeight
+	if src.Height < top {
+		top = src.Height
+	}
+	rungs := make([]ladderRung, 0, len(ladderRungs))
+	for _, r := range ladderRungs {
+		if r.height <= top {
+			rungs = append(rungs, r)
+		}
+	}
+	if len(rungs) == 0 {
+		// Sources below the lowest rendition get a single rendition at their own height
+		rungs = append(rungs, ladderRung{height: top, bitrate: ladderRungs[len(ladderRungs)-1].bitrate})
+	}
+
+	profiles := make([]ffmpeg.VideoProfile, 0, len(rungs))
+	for _, r := range rungs {
+		bitrate := r.bitrate * 1000
+		if src.Framerate > 30 {
+			bitrate = bitrate * 3 / 2
+		}
+		if src.Bitrate > 0 && bitrate >= src.Bitrate {
+			if r.height == src.Height {
+				// The rendition would only duplicate the source
+				continue
+			}
+			bitrate = src.Bitrate
+		}
+		var framerate uint
+		if src.Framerate > 30 && r.height < 720 {
+			framerate = uint(math.Round(src.Framerate / 2))
+		}
+		// Encoders require even dimensions
+		width := int(math.Round(float64(r.height)*float64(src.Width)/float64(src.Height)/2)) * 2
+		profiles = append(profiles, ffmpeg.VideoProfile{
+			Name:       fmt.Sprintf("%dp", r.height),
+			Bitrate:    strconv.Itoa(bitrate),
+			Framerate:  framerate,
+			Resolution: fmt.Sprintf("%dx%d", width, r.height),
+		})
+	}
+	return profiles
+}
+
+// defaultLadder returns the ladder of a 16:9 source at 30 fps that is maxHeight high. It is used for the streams whose
+// ladder was not generated yet or whose source could not be probed
+func defaultLadder(maxHeight int) []ffmpeg.VideoProfile {
+	return generateLadder(&core.SourceInfo{Width: maxHeight * 16 / 9, Height: maxHeight, Framerate: 30}, maxHeight)
+}
+
+// applyAutoLadder replaces the profiles of the stream with a ladder generated from the source segment data of the
+// given duration in seconds if params.AutoLadder is set. It must be called before the first segment of the stream is
+// transcoded. The generated renditions use the same format and codec as the default ladder, so the capabilities of the
+// stream do not change. The profiles are kept if the source cannot be probed
+func applyAutoLadder(ctx context.Context, params *core.StreamParameters, data []byte, duration float64) {
+	if params == nil || !params.AutoLadder {
+		return
+	}
+	params.AutoLadder = false
+
+	src := core.ProbeSegment(data, duration)
+	profiles := generateLadder(src, AutoLadderMaxHeight)
+	if len(profiles) == 0 {
+		clog.Warningf(ctx, "Could not probe source for transcoding ladder, using default profiles=%v", common.ProfilesNames(params.Profiles))
+		return
+	}
+	if len(params.Profiles) > 0 {
+		for i := range profiles {
+			profiles[i].Format = params.Profiles[0].Format
+		}
+	}
+	params.Profiles = profiles
+	clog.Infof(ctx, "Generated transcoding ladder source=%dx%d fps=%.2f bitrate=%d profiles=%v", src.Width, src.Height,
+		src.Framerate, src.Bitrate, common.ProfilesNames(profiles))
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAutoLadder(t *testing.T) {
+	assert := assert.New(t)
+
+	height, auto, err := parseAutoLadder("auto")
+	assert.Nil(err)
+	assert.True(auto)
+	assert.Equal(1080, height)
+
+	height, auto, err = parseAutoLadder("auto:720p")
+	assert.Nil(err)
+	assert.True(auto)
+	assert.Equal(720, height)
+
+	height, auto, err = parseAutoLadder("auto:1440")
+	assert.Nil(err)
+	assert.True(auto)
+	assert.Equal(1440, height)
+
+	_, auto, err = parseAutoLadder("P240p30fps16x9,P360p30fps16x9")
+	assert.Nil(err)
+	assert.False(auto)
+
+	_, _, err = parseAutoLadder("auto:foo")
+	assert.EqualError(err, "invalid auto transcoding options: auto:foo")
+	_, _, err = parseAutoLadder("auto:100p")
+	assert.EqualError(err, "invalid auto transcoding options: auto:100p")
+}
+
+func TestGenerateLadder(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(generateLadder(nil, 1080))
+	assert.Nil(generateLadder(&core.SourceInfo{}, 1080))
+
+	// 1080p30 source with a high bitrate
+	assert.Equal([]ffmpeg.VideoProfile{
+		{Name: "1080p", Bitrate: "6000000", Resolution: "1920x1080"},
+		{Name: "720p", Bitrate: "4000000", Resolution: "1280x720"},
+		{Name: "480p", Bitrate: "2000000", Resolution: "854x480"},
+		{Name: "360p", Bitrate: "1000000", Resolution: "640x360"},
+		{Name: "240p", Bitrate: "700000", Resolution: "426x240"},
+	}, generateLadder(&core.SourceInfo{Width: 1920, Height: 1080, Framerate: 30, Bitrate: 10000000}, 1080))
+
+	// The top rendition is capped at the max height
+	ladder := generateLadder(&core.SourceInfo{Width: 1920, Height: 1080, Framerate: 30}, 720)
+	assert.Len(ladder, 4)
+	assert.Equal("720p", ladder[0].Name)
+
+	// 720p60 source with a low bitrate: the rendition at the source height is left out, the bitrates are capped at the
+	// source bitrate and the lower renditions have half the framerate
+	assert.Equal([]ffmpeg.VideoProfile{
+		{Name: "480p", Bitrate: "2500000", Framerate: 30, Resolution: "854x480"},
+		{Name: "360p", Bitrate: "1500000", Framerate: 30, Resolution: "640x360"},
+		{Name: "240p", Bitrate: "1050000", Framerate: 30, Resolution: "426x240"},
+	}, generateLadder(&core.SourceInfo{Width: 1280, Height: 720, Framerate: 59.94, Bitrate: 2500000}, 1080))
+
+	// Portrait source keeps its aspect ratio
+	ladder = generateLadder(&core.SourceInfo{Width: 720, Height: 1280, Framerate: 30}, 720)
+	assert.Equal("406x720", ladder[0].Resolution)
+
+	// Source below the lowest rendition
+	assert.Equal([]ffmpeg.VideoProfile{
+		{Name: "144p", Bitrate: "700000", Resolution: "256x144"},
+	}, generateLadder(&core.SourceInfo{Width: 256, Height: 144, Framerate: 25}, 1080))
+}
+
+func TestDefaultLadder(t *testing.T) {
+	ladder := defaultLadder(720)
+	assert.Len(t, ladder, 4)
+	assert.Equal(t, ffmpeg.VideoProfile{Name: "720p", Bitrate: "4000000", Resolution: "1280x720"}, ladder[0])
+}
+
+func TestApplyAutoLadder(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func() { AutoLadderMaxHeight = 0 }()
+	AutoLadderMaxHeight = 720
+	defaults := defaultLadder(720)
+	for i := range defaults {
+		defaults[i].Format = ffmpeg.FormatMP4
+	}
+
+	// Profiles are kept if the stream does not use generated ladders
+	params := &core.StreamParameters{Profiles: defaults}
+	applyAutoLadder(context.Background(), params, nil, 2)
+	assert.Equal(defaults, params.Profiles)
+
+	// Profiles are kept if the source cannot be probed, and the ladder is only generated once
+	params.AutoLadder = true
+	applyAutoLadder(context.Background(), params, []byte("foo"), 2)
+	assert.Equal(defaults, params.Profiles)
+	assert.False(params.AutoLadder)
+}
+
+func TestCreateRTMPStreamHandler_AutoLadder(t *testing.T) {
+	assert := assert.New(t)
+
+	s, cancel := setupServerWithCancel()
+	defer serverCleanup(s)
+	defer cancel()
+	createSid := createRTMPStreamIDHandler(context.TODO(), s)
+	u := mustParseUrl(t, "rtmp://localhost/movie")
+
+	params := createSid(u).(*core.StreamParameters)
+	assert.False(params.AutoLadder)
+
+	defer func() { AutoLadderMaxHeight = 0 }()
+	AutoLadderMaxHeight = 720
+	params = createSid(u).(*core.StreamParameters)
+	assert.True(params.AutoLadder)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/livepeer/go-livepeer/clog"
+	"github.com/livepeer/go-livepeer/common"
+)
+
+// LatencyBudget is the end-to-end latency, from submitting a segment to downloading its renditions, that the
+// broadcaster allows per segment. Orchestrators whose p95 latency exceeds it are switched away from before they fail
+// outright. The latency is not tracked if 0
+var LatencyBudget time.Duration
+
+// latencyWindow is the number of the latest segments of an orchestrator that its p95 latency is calculated over
+const latencyWindow = 20
+
+// latencyMinSamples is the number of segments an orchestrator must have transcoded before its latency is compared to
+// the budget, so that a single slow segment does not switch orchestrators
+const latencyMinSamples = 5
+
+// latencyTracker keeps the end-to-end latencies of the latest segments per orchestrator
+type latencyTracker struct {
+	mu      sync.Mutex
+	samples map[string][]time.Duration
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{samples: make(map[string][]time.Duration)}
+}
+
+// add records the latency of a segment transcoded by orch and returns the p95 latency of orch, or 0 if orch has too
+// few samples
+func (t *latencyTracker) add(orch string, latency time.Duration) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	samples := append(t.samples[orch], latency)
+	if len(samples) > latencyWindow {
+		samples = samples[len(samples)-latencyWindow:]
+	}
+	t.samples[orch] = samples
+
+	if len(samples) < latencyMinSamples {
+		return 0
+	}
+	return percentile(samples, 95)
+}
+
+// reset drops the samples of orch so that it starts over if it is selected again
+func (t *latencyTracker) reset(orch string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.samples, orch)
+}
+
+// percentile returns the p-th percentile of samples with the nearest-rank method
+func percentile(samples []time.Duration, p int) time.Duration {
+	sorted := append([]time.Duration{}, samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// trackLatency records the end-to-end latency of a segment transcoded by sess. If the p95 latency of the orchestrator
+// exceeds LatencyBudget, the orchestrator is switched away from if the pool has other orchestrators. Otherwise the pool
+// is refreshed so that the next segments can be sent to a faster orchestrator
+func (bsm *BroadcastSessionsManager) trackLatency(ctx context.Context, sess *BroadcastSession, latency time.Duration) {
+	if LatencyBudget <= 0 || bsm.latencies == nil {
+		return
+	}
+
+	orch := sess.Transcoder()
+	p95 := bsm.latencies.add(orch, latency)
+	if p95 <= LatencyBudget {
+		return
+	}
+
+	pool := bsm.trustedPool
+	if sess.OrchestratorScore == common.Score_Untrusted {
+		pool = bsm.untrustedPool
+	}
+
+	bsm.events.record(streamEventLatencyBudgetExceeded, map[string]interface{}{
+		"orchestrator": orch,
+		"p95Ms":        p95.Milliseconds(),
+		"budgetMs":     LatencyBudget.Milliseconds(),
+	})
+
+	if !pool.hasAlternatives(sess) {
+		clog.Warningf(ctx, "Latency budget exceeded without other orchestrators to switch to orch=%s p95=%s budget=%s", orch, p95, LatencyBudget)
+		go pool.refreshSessions(ctx)
+		return
+	}
+
+	clog.Warningf(ctx, "Switching from orch=%s due to latency budget exceeded p95=%s budget=%s", orch, p95, LatencyBudget)
+	bsm.latencies.reset(orch)
+	bsm.suspendAndRemoveOrch(sess)
+}
+
+// hasAlternatives returns whether the pool has sessions other than sess to switch to
+func (sp *SessionPool) hasAlternatives(sess *BroadcastSession) bool {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	for orch := range sp.sessMap {
+		if orch != sess.Transcoder() {
+			return true
+		}
+	}
+	return false
+}
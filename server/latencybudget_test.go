@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	assert := assert.New(t)
+
+	samples := []time.Duration{5, 1, 4, 2, 3}
+	assert.Equal(time.Duration(5), percentile(samples, 95))
+	assert.Equal(time.Duration(3), percentile(samples, 50))
+	assert.Equal(time.Duration(1), percentile(samples, 0))
+	// The samples are not reordered
+	assert.Equal([]time.Duration{5, 1, 4, 2, 3}, samples)
+
+	samples = nil
+	for i := 1; i <= 20; i++ {
+		samples = append(samples, time.Duration(i))
+	}
+	assert.Equal(time.Duration(19), percentile(samples, 95))
+}
+
+func TestLatencyTracker(t *testing.T) {
+	assert := assert.New(t)
+
+	tr := newLatencyTracker()
+	for i := 0; i < latencyMinSamples-1; i++ {
+		assert.Equal(time.Duration(0), tr.add("orch1", time.Second))
+	}
+	assert.Equal(time.Second, tr.add("orch1", time.Second))
+	// Orchestrators are tracked separately
+	assert.Equal(time.Duration(0), tr.add("orch2", time.Minute))
+
+	// Only the latest segments count
+	for i := 0; i < latencyWindow; i++ {
+		tr.add("orch1", 2*time.Second)
+	}
+	assert.Len(tr.samples["orch1"], latencyWindow)
+	assert.Equal(2*time.Second, tr.add("orch1", time.Second))
+
+	tr.reset("orch1")
+	assert.Equal(time.Duration(0), tr.add("orch1", time.Second))
+}
+
+func TestTrackLatency(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(budget time.Duration) { LatencyBudget = budget }(LatencyBudget)
+	LatencyBudget = 2 * time.Second
+
+	sess1 := StubBroadcastSession("transcoder1")
+	sess2 := StubBroadcastSession("transcoder2")
+	bsm := bsmWithSessListExt([]*BroadcastSession{sess1, sess2}, nil, true)
+	bsm.latencies = newLatencyTracker()
+	ctx := context.Background()
+
+	// Orchestrators within the budget are kept
+	for i := 0; i < latencyWindow; i++ {
+		bsm.trackLatency(ctx, sess1, time.Second)
+	}
+	assert.Contains(bsm.trustedPool.sessMap, "transcoder1")
+
+	// A single slow segment does not exceed the p95 latency
+	bsm.trackLatency(ctx, sess1, 3*time.Second)
+	assert.Contains(bsm.trustedPool.sessMap, "transcoder1")
+
+	// Orchestrators whose p95 latency exceeds the budget are switched away from
+	bsm.trackLatency(ctx, sess1, 3*time.Second)
+	assert.NotContains(bsm.trustedPool.sessMap, "transcoder1")
+	assert.Contains(bsm.trustedPool.sus.list, "transcoder1")
+	assert.NotContains(bsm.latencies.samples, "transcoder1")
+
+	// The last orchestrator of the pool is kept
+	for i := 0; i < latencyMinSamples; i++ {
+		bsm.trackLatency(ctx, sess2, 3*time.Second)
+	}
+	assert.Contains(bsm.trustedPool.sessMap, "transcoder2")
+
+	// The latency is not tracked without a budget
+	LatencyBudget = 0
+	bsm.trackLatency(ctx, sess1, time.Second)
+	assert.NotContains(bsm.latencies.samples, "transcoder1")
+}
@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"mime/multipart"
+	gonet "net"
 	"net/http"
 	"net/textproto"
 	"net/url"
@@ -66,6 +67,25 @@ var DetectionWhClient = &http.Client{Timeout: 2 * time.Second}
 
 var SelectRandFreq float64
 
+// SharedSessions shares the state of streams through the DB with the other broadcasters that use the same DB, i.e. a
+// Postgres DB set with -dbUrl. Any broadcaster of the cluster can then serve the playlists of a stream and take over
+// the stream if the broadcaster that ingests it goes down
+var SharedSessions bool
+
+// ReconnectGracePeriod is how long the session of an RTMP stream is kept after the input stream disconnects. An input
+// stream that reconnects within the period continues the session with the same manifest, orchestrators and segment
+// numbering. 0 ends the session when the input stream disconnects
+var ReconnectGracePeriod time.Duration
+
+// ManifestIDSecret is the secret that the manifest IDs of streams are derived from their stream keys with, so that the
+// playback URLs of a stream can be predicted before it starts. Manifest IDs are taken from the ingest URL if empty
+var ManifestIDSecret []byte
+
+// TrustedProxies are the networks of the reverse proxies in front of the node. The client address of a request that is
+// received from a trusted proxy is read from its X-Forwarded-For header, which is ignored for other requests so that
+// clients cannot spoof their address, i.e. to evade the per-IP stream limit
+var TrustedProxies []*gonet.IPNet
+
 func PixelFormatNone() ffmpeg.PixelFormat {
 	return ffmpeg.PixelFormat{ffmpeg.PixelFormatNone}
 }
@@ -88,6 +108,33 @@ type rtmpConnection struct {
 	lastUsed        time.Time
 	sourceBytes     uint64
 	transcodedBytes uint64
+	detections      *core.DetectionAggregator
+	splices         *core.SpliceTracker
+	// nextSeqNo is the seqNo of the next segment of the input stream. Accessed atomically
+	nextSeqNo uint64
+	// suspended is the timer that ends the stream while the input stream is disconnected and waits to reconnect
+	suspended *time.Timer
+	// standby is the other ingest of the stream, i.e. the backup ingest while the primary ingest is live, which takes
+	// over when stream disconnects
+	standby stream.RTMPVideoStream
+	// streamLock guards stream for readers that do not hold the connectionLock, as stream is replaced when the input
+	// stream reconnects
+	streamLock sync.RWMutex
+	// groups are the sub-sessions that transcode the output groups of a simulcast stream, each with orchestrators of
+	// its own. sessManager is the session manager of the first group
+	groups []*rtmpConnection
+	// parent is the stream that an output group sub-session belongs to and group the name of its output group
+	parent *rtmpConnection
+	group  string
+}
+
+func (cxn *rtmpConnection) inputStream() stream.RTMPVideoStream {
+	if cxn.parent != nil {
+		return cxn.parent.inputStream()
+	}
+	cxn.streamLock.RLock()
+	defer cxn.streamLock.RUnlock()
+	return cxn.stream
 }
 
 type LivepeerServer struct {
@@ -106,6 +153,10 @@ type LivepeerServer struct {
 	lastManifestID    core.ManifestID
 	context           context.Context
 	connectionLock    *sync.RWMutex
+
+	// batchJobs are the cancel functions of the batch jobs that run on this node by id, protected by batchLock
+	batchJobs map[string]context.CancelFunc
+	batchLock sync.Mutex
 }
 
 func (s *LivepeerServer) SetContextFromUnitTest(c context.Context) {
@@ -127,13 +178,29 @@ type authWebhookResponse struct {
 	PreviousSessions []string             `json:"previousSessions"`
 	Detection        struct {
 		// Run detection on 1/freq segments
-		Freq                uint `json:"freq"`
-		SampleRate          uint `json:"sampleRate"`
+		Freq uint `json:"freq"`
+		// Only run detection without transcoding any renditions
+		Only       bool `json:"only"`
+		SampleRate uint `json:"sampleRate"`
+		// Report detections with a probability of at least threshold
+		Threshold float64 `json:"threshold"`
+		// Tag segments with reported detections in the HLS playlists
+		TagPlaylist bool `json:"tagPlaylist"`
+		// Report stream level classifications that start when the smoothed probability of a class rises to
+		// startThreshold and stop when it falls below stopThreshold
+		StartThreshold float64 `json:"startThreshold"`
+		StopThreshold  float64 `json:"stopThreshold"`
+		// Weight of the latest segment in the smoothed probability
+		Smoothing           float64 `json:"smoothing"`
 		SceneClassification []struct {
 			Name string `json:"name"`
 		} `json:"sceneClassification"`
 	} `json:"detection"`
-	VerificationFreq uint `json:"verificationFreq"`
+	// Custom filters applied to the source segments before transcoding
+	Filters          []core.FilterStep `json:"filters"`
+	VerificationFreq uint              `json:"verificationFreq"`
+	// Ladders that the stream is simulcast to instead of the presets and profiles
+	OutputGroups []authOutputGroup `json:"outputGroups"`
 }
 
 func NewLivepeerServer(rtmpAddr string, lpNode *core.LivepeerNode, httpIngest bool, transcodingOptions string) (*LivepeerServer, error) {
@@ -150,7 +217,13 @@ func NewLivepeerServer(rtmpAddr string, lpNode *core.LivepeerNode, httpIngest bo
 		if transcodingOptions != "" {
 			var profiles []ffmpeg.VideoProfile
 			content, err := ioutil.ReadFile(transcodingOptions)
-			if err == nil && len(content) > 0 {
+			if maxHeight, auto, autoErr := parseAutoLadder(transcodingOptions); autoErr != nil {
+				return nil, autoErr
+			} else if auto {
+				// The streams start with the default ladder until their ladder is generated from the first segment
+				AutoLadderMaxHeight = maxHeight
+				profiles = defaultLadder(maxHeight)
+			} else if err == nil && len(content) > 0 {
 				stubResp := &authWebhookResponse{}
 				err = json.Unmarshal(content, &stubResp.Profiles)
 				if err != nil {
@@ -162,7 +235,10 @@ func NewLivepeerServer(rtmpAddr string, lpNode *core.LivepeerNode, httpIngest bo
 				}
 			} else {
 				// check the built-in profiles
-				profiles = parsePresets(strings.Split(transcodingOptions, ","))
+				profiles, err = parsePresets(strings.Split(transcodingOptions, ","))
+				if err != nil {
+					return nil, err
+				}
 			}
 			if len(profiles) <= 0 {
 				return nil, fmt.Errorf("No transcoding profiles found")
@@ -211,7 +287,7 @@ func (s *LivepeerServer) StartMediaServer(ctx context.Context, httpAddr string)
 	if s.LivepeerNode.NodeType == core.BroadcasterNode {
 		go func() {
 			glog.V(4).Infof("HTTP Server listening on http://%v", httpAddr)
-			ec <- http.ListenAndServe(httpAddr, s.HTTPMux)
+			ec <- http.ListenAndServe(httpAddr, playbackHandler(s.HTTPMux))
 		}()
 	}
 
@@ -243,7 +319,10 @@ func createRTMPStreamIDHandler(_ctx context.Context, s *LivepeerServer) func(url
 		var oss, ross drivers.OSSession
 		profiles := []ffmpeg.VideoProfile{}
 		detectionConfig := core.DetectionConfig{}
+		var filters []core.FilterStep
 		var VerificationFreq uint
+		var autoLadder bool
+		var outputGroups []core.OutputGroup
 		nonce := rand.Uint64()
 
 		// do not replace captured _ctx variable
@@ -260,7 +339,11 @@ func createRTMPStreamIDHandler(_ctx context.Context, s *LivepeerServer) func(url
 			}
 			// Process transcoding options presets
 			if len(resp.Presets) > 0 {
-				profiles = parsePresets(resp.Presets)
+				profiles, err = parsePresets(resp.Presets)
+				if err != nil {
+					clog.Errorf(ctx, "Failed to parse transcoding presets for streamID url=%s err=%q", url.String(), err)
+					return nil
+				}
 			}
 
 			parsedProfiles, err := ffmpeg.ParseProfilesFromJsonProfileArray(resp.Profiles)
@@ -270,9 +353,27 @@ func createRTMPStreamIDHandler(_ctx context.Context, s *LivepeerServer) func(url
 			}
 			profiles = append(profiles, parsedProfiles...)
 
+			if len(resp.OutputGroups) > 0 {
+				if len(resp.Profiles) > 0 || len(resp.Presets) > 0 || resp.Detection.Only {
+					clog.Errorf(ctx, "Output groups with profiles, presets or detection-only for streamID url=%s", url.String())
+					return nil
+				}
+				outputGroups, err = parseOutputGroups(resp.OutputGroups)
+				if err != nil {
+					clog.Errorf(ctx, "Failed to parse output groups for streamID url=%s err=%q", url.String(), err)
+					return nil
+				}
+				profiles = outputGroupsProfiles(outputGroups)
+			}
+
 			// Only set defaults if user did not specify a preset/profile
-			if len(resp.Profiles) <= 0 && len(resp.Presets) <= 0 {
+			if len(resp.Profiles) <= 0 && len(resp.Presets) <= 0 && len(outputGroups) <= 0 && !resp.Detection.Only {
 				profiles = BroadcastJobVideoProfiles
+				autoLadder = AutoLadderMaxHeight > 0
+			}
+			if resp.Detection.Only && (resp.Detection.Freq == 0 || len(profiles) > 0) {
+				clog.Errorf(ctx, "Detection-only stream without detection config or with profiles for streamID url=%s", url.String())
+				return nil
 			}
 
 			// set OS if it was provided
@@ -300,15 +401,34 @@ func createRTMPStreamIDHandler(_ctx context.Context, s *LivepeerServer) func(url
 					return nil
 				}
 			}
+			// set custom filters if provided
+			if err := core.ValidateFilterSteps(resp.Filters); err != nil {
+				clog.Errorf(ctx, "Invalid filters for streamID url=%s err=%q", url.String(), err)
+				return nil
+			}
+			// Orchestrators transcode filtered streams without the detector
+			if len(resp.Filters) > 0 && resp.Detection.Freq != 0 {
+				clog.Errorf(ctx, "Filters are not supported with detection for streamID url=%s", url.String())
+				return nil
+			}
+			filters = resp.Filters
 			VerificationFreq = resp.VerificationFreq
 		} else {
 			profiles = BroadcastJobVideoProfiles
+			autoLadder = AutoLadderMaxHeight > 0
 		}
 
 		sid := parseStreamID(url.Path)
 		extmid := sid.ManifestID
+		var derivedFrom string
 		if mid == "" {
 			mid, key = sid.ManifestID, sid.Rendition
+			if mid != "" && len(ManifestIDSecret) > 0 && !optOutDerivedManifestID(url) {
+				derivedFrom = string(mid)
+				mid = core.DeriveManifestID(ManifestIDSecret, derivedFrom, time.Now())
+				// Do not expose the stream key in the path of the recordings
+				extmid = mid
+			}
 		}
 		if mid == "" {
 			mid = core.RandomManifestID()
@@ -329,6 +449,10 @@ func createRTMPStreamIDHandler(_ctx context.Context, s *LivepeerServer) func(url
 		} else if drivers.RecordStorage != nil {
 			ross = drivers.RecordStorage.NewSession(recordPath)
 		}
+		if ross != nil && drivers.RecordEncryption != nil {
+			// Each stream is recorded with a data key of its own
+			ross = drivers.NewEncryptedSession(ross, drivers.RecordEncryption)
+		}
 		// Ensure there's no concurrent StreamID with the same name
 		s.connectionLock.RLock()
 		defer s.connectionLock.RUnlock()
@@ -336,6 +460,13 @@ func createRTMPStreamIDHandler(_ctx context.Context, s *LivepeerServer) func(url
 			clog.Errorf(ctx, "Too many connections for streamID url=%s err=%q", url.String(), err)
 			return nil
 		}
+		if cxn, exists := s.rtmpConnections[mid]; exists && derivedFrom != "" && cxn.params.DerivedFrom != derivedFrom {
+			clog.Errorf(ctx, "Derived manifestID collides with the manifestID of another stream url=%s", url.String())
+			if monitor.Enabled {
+				monitor.ManifestIDCollision()
+			}
+			return nil
+		}
 		return &core.StreamParameters{
 			ManifestID:       mid,
 			ExternalStreamID: extStreamID,
@@ -346,12 +477,30 @@ func createRTMPStreamIDHandler(_ctx context.Context, s *LivepeerServer) func(url
 			OS:               oss,
 			RecordOS:         ross,
 			Detection:        detectionConfig,
+			Filters:          filters,
 			VerificationFreq: VerificationFreq,
 			Nonce:            nonce,
+			Backup:           isBackupIngest(url),
+			AutoLadder:       autoLadder,
+			OutputGroups:     outputGroups,
+			DerivedFrom:      derivedFrom,
 		}
 	}
 }
 
+// optOutDerivedManifestID returns true if the manifest ID of the stream is taken from the ingest URL even though
+// ManifestIDSecret is set, i.e. rtmp://host/movie?deriveManifestID=false
+func optOutDerivedManifestID(url *url.URL) bool {
+	derive, err := strconv.ParseBool(url.Query().Get("deriveManifestID"))
+	return err == nil && !derive
+}
+
+// isBackupIngest returns true if the ingest URL is the backup ingest of a stream, i.e. rtmp://host/movie?backup=true
+func isBackupIngest(url *url.URL) bool {
+	backup, _ := strconv.ParseBool(url.Query().Get("backup"))
+	return backup
+}
+
 func authenticateStream(url string) (*authWebhookResponse, error) {
 	if AuthWebhookURL == nil {
 		return nil, nil
@@ -396,6 +545,23 @@ func jsonDetectionToDetectionConfig(ctx context.Context, resp *authWebhookRespon
 		Freq:               resp.Detection.Freq,
 		SelectedClassNames: []string{},
 		Profiles:           []ffmpeg.DetectorProfile{},
+		Threshold:          resp.Detection.Threshold,
+		TagPlaylist:        resp.Detection.TagPlaylist,
+		StartThreshold:     resp.Detection.StartThreshold,
+		StopThreshold:      resp.Detection.StopThreshold,
+		Smoothing:          resp.Detection.Smoothing,
+	}
+	if detection.Threshold < 0 || detection.Threshold > 1 {
+		return detection, fmt.Errorf("invalid detection threshold %v", detection.Threshold)
+	}
+	if detection.StartThreshold < 0 || detection.StartThreshold > 1 {
+		return detection, fmt.Errorf("invalid detection start threshold %v", detection.StartThreshold)
+	}
+	if detection.StopThreshold < 0 || detection.StopThreshold > detection.StartThreshold {
+		return detection, fmt.Errorf("invalid detection stop threshold %v", detection.StopThreshold)
+	}
+	if detection.Smoothing < 0 || detection.Smoothing > 1 {
+		return detection, fmt.Errorf("invalid detection smoothing %v", detection.Smoothing)
 	}
 	modelPaths := make(map[string]bool)
 	for _, class := range resp.Detection.SceneClassification {
@@ -429,46 +595,38 @@ func streamParams(d stream.AppData) *core.StreamParameters {
 func gotRTMPStreamHandler(s *LivepeerServer) func(url *url.URL, rtmpStrm stream.RTMPVideoStream) (err error) {
 	return func(url *url.URL, rtmpStrm stream.RTMPVideoStream) (err error) {
 
-		cxn, err := s.registerConnection(context.Background(), rtmpStrm, nil, PixelFormatNone())
-		if err != nil {
-			return err
+		if s.standbyRTMPStream(context.Background(), rtmpStrm) {
+			return nil
+		}
+
+		cxn, reconnected := s.resumeRTMPStream(context.Background(), rtmpStrm)
+		if !reconnected {
+			cxn, err = s.registerConnection(context.Background(), rtmpStrm, nil, PixelFormatNone())
+			if err != nil {
+				return err
+			}
 		}
 
 		mid := cxn.mid
 		nonce := cxn.nonce
 		startSeq := 0
-
-		streamStarted := false
-		//Segment the stream, insert the segments into the broadcaster
-		go func(rtmpStrm stream.RTMPVideoStream) {
-			hid := string(core.RandomManifestID()) // ffmpeg m3u8 output name
-			hlsStrm := stream.NewBasicHLSVideoStream(hid, stream.DefaultHLSStreamWin)
-			hlsStrm.SetSubscriber(func(seg *stream.HLSSegment, eof bool) {
-				if eof {
-					// XXX update HLS manifest
-					return
-				}
-				if !streamStarted {
-					streamStarted = true
-					if monitor.Enabled {
-						monitor.StreamStarted(nonce)
-					}
-				}
-				go processSegment(context.Background(), cxn, seg)
-			})
-
-			segOptions := segmenter.SegmenterOptions{
-				StartSeq:  startSeq,
-				SegLength: SegLen,
-			}
-			err := s.RTMPSegmenter.SegmentRTMPToHLS(context.Background(), rtmpStrm, hlsStrm, segOptions)
-			if err != nil {
-				// Stop the incoming RTMP connection.
-				// TODO retry segmentation if err != SegmenterTimeout; may be recoverable
-				rtmpStrm.Close()
+		if reconnected {
+			// Continue the numbering of the session, the timestamps of the input stream start over
+			startSeq = int(atomic.LoadUint64(&cxn.nextSeqNo))
+			cxn.pl.DiscontinueHLSSegment(uint64(startSeq))
+		} else if spl, ok := cxn.pl.(*core.SharedPlaylistManager); ok {
+			if lastSeqNo, resumed := spl.LastSeqNo(); resumed {
+				// Continue the stream where the previous ingest node left off
+				startSeq = int(lastSeqNo) + 1
 			}
+		}
 
-		}(rtmpStrm)
+		s.segmentRTMPStream(cxn, rtmpStrm, startSeq, reconnected)
+
+		if reconnected {
+			glog.Infof("Video reconnected with ManifestID: %v", mid)
+			return nil
+		}
 
 		if monitor.Enabled {
 			monitor.StreamCreated(string(mid), nonce)
@@ -480,6 +638,45 @@ func gotRTMPStreamHandler(s *LivepeerServer) func(url *url.URL, rtmpStrm stream.
 	}
 }
 
+// segmentRTMPStream segments the input stream rtmpStrm of cxn and processes the segments. started is set if segments of
+// the stream were already processed, i.e. for a reconnected input stream
+func (s *LivepeerServer) segmentRTMPStream(cxn *rtmpConnection, rtmpStrm stream.RTMPVideoStream, startSeq int, started bool) {
+	streamStarted := started
+	//Segment the stream, insert the segments into the broadcaster
+	go func(rtmpStrm stream.RTMPVideoStream) {
+		hid := string(core.RandomManifestID()) // ffmpeg m3u8 output name
+		hlsStrm := stream.NewBasicHLSVideoStream(hid, stream.DefaultHLSStreamWin)
+		hlsStrm.SetSubscriber(func(seg *stream.HLSSegment, eof bool) {
+			if eof {
+				// XXX update HLS manifest
+				return
+			}
+			if !streamStarted {
+				streamStarted = true
+				if monitor.Enabled {
+					monitor.StreamStarted(cxn.nonce)
+				}
+				// No segment of the stream is being processed yet, so the profiles can be replaced
+				applyAutoLadder(context.Background(), cxn.params, seg.Data, seg.Duration)
+			}
+			atomic.StoreUint64(&cxn.nextSeqNo, seg.SeqNo+1)
+			go processSegment(context.Background(), cxn, seg)
+		})
+
+		segOptions := segmenter.SegmenterOptions{
+			StartSeq:  startSeq,
+			SegLength: SegLen,
+		}
+		err := s.RTMPSegmenter.SegmentRTMPToHLS(context.Background(), rtmpStrm, hlsStrm, segOptions)
+		if err != nil {
+			// Stop the incoming RTMP connection.
+			// TODO retry segmentation if err != SegmenterTimeout; may be recoverable
+			rtmpStrm.Close()
+		}
+
+	}(rtmpStrm)
+}
+
 func endRTMPStreamHandler(s *LivepeerServer) func(url *url.URL, rtmpStrm stream.RTMPVideoStream) error {
 	return func(url *url.URL, rtmpStrm stream.RTMPVideoStream) error {
 		params := streamParams(rtmpStrm.AppData())
@@ -487,6 +684,14 @@ func endRTMPStreamHandler(s *LivepeerServer) func(url *url.URL, rtmpStrm stream.
 			return errMismatchedParams
 		}
 
+		if s.switchRTMPStream(context.Background(), params.ManifestID, rtmpStrm) {
+			return nil
+		}
+
+		if ReconnectGracePeriod > 0 && s.suspendRTMPStream(context.Background(), params.ManifestID, rtmpStrm) {
+			return nil
+		}
+
 		//Remove RTMP stream
 		err := removeRTMPStream(context.Background(), s, params.ManifestID)
 		if err != nil {
@@ -496,6 +701,127 @@ func endRTMPStreamHandler(s *LivepeerServer) func(url *url.URL, rtmpStrm stream.
 	}
 }
 
+// standbyRTMPStream keeps the input stream rtmpStrm on standby if the session of its manifest is live and rtmpStrm or
+// the live input stream of the session is a backup ingest. The standby stream is not segmented until it takes over
+// the session. Returns false if rtmpStrm was not put on standby
+func (s *LivepeerServer) standbyRTMPStream(ctx context.Context, rtmpStrm stream.RTMPVideoStream) bool {
+	params := streamParams(rtmpStrm.AppData())
+	if params == nil {
+		return false
+	}
+
+	s.connectionLock.Lock()
+	defer s.connectionLock.Unlock()
+
+	cxn, ok := s.rtmpConnections[params.ManifestID]
+	if !ok || cxn.suspended != nil || cxn.standby != nil || cxn.stream == nil {
+		return false
+	}
+	if live := streamParams(cxn.stream.AppData()); !params.Backup && (live == nil || !live.Backup) {
+		// Two primary ingests of a stream are not allowed
+		return false
+	}
+	cxn.standby = rtmpStrm
+	clog.Infof(ctx, "Input stream on standby manifestID=%s backup=%v", params.ManifestID, params.Backup)
+	return true
+}
+
+// switchRTMPStream hands the session of the disconnected input stream rtmpStrm over to the standby input stream of the
+// session, or removes rtmpStrm from the session if it is the standby stream. Returns false if rtmpStrm is the live
+// input stream of a session without a standby stream
+func (s *LivepeerServer) switchRTMPStream(ctx context.Context, mid core.ManifestID, rtmpStrm stream.RTMPVideoStream) bool {
+	s.connectionLock.Lock()
+	cxn, ok := s.rtmpConnections[mid]
+	if !ok {
+		s.connectionLock.Unlock()
+		return false
+	}
+	if cxn.standby == rtmpStrm {
+		cxn.standby = nil
+		s.connectionLock.Unlock()
+		clog.Infof(ctx, "Standby input stream disconnected manifestID=%s", mid)
+		return true
+	}
+	if cxn.stream != rtmpStrm || cxn.standby == nil {
+		s.connectionLock.Unlock()
+		return false
+	}
+	standby := cxn.standby
+	cxn.standby = nil
+	cxn.streamLock.Lock()
+	cxn.stream = standby
+	cxn.streamLock.Unlock()
+	cxn.lastUsed = time.Now()
+	s.connectionLock.Unlock()
+
+	// Continue the numbering of the session, the timestamps of the standby stream are not aligned with the disconnected stream
+	startSeq := atomic.LoadUint64(&cxn.nextSeqNo)
+	cxn.pl.DiscontinueHLSSegment(startSeq)
+	s.segmentRTMPStream(cxn, standby, int(startSeq), true)
+
+	backup := false
+	if params := streamParams(standby.AppData()); params != nil {
+		backup = params.Backup
+	}
+	cxn.sessManager.events.record(streamEventIngestSwitched, map[string]interface{}{"backup": backup})
+	clog.Infof(ctx, "Input stream switched to standby manifestID=%s backup=%v", mid, backup)
+	return true
+}
+
+// suspendRTMPStream keeps the session of the disconnected input stream rtmpStrm for ReconnectGracePeriod so that the
+// stream can reconnect to it, and ends the stream after the period. Returns false if rtmpStrm has no session
+func (s *LivepeerServer) suspendRTMPStream(ctx context.Context, mid core.ManifestID, rtmpStrm stream.RTMPVideoStream) bool {
+	s.connectionLock.Lock()
+	defer s.connectionLock.Unlock()
+
+	cxn, ok := s.rtmpConnections[mid]
+	if !ok || cxn.stream != rtmpStrm || cxn.suspended != nil {
+		return false
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(ReconnectGracePeriod, func() {
+		s.connectionLock.Lock()
+		defer s.connectionLock.Unlock()
+		// The stream might have reconnected in the meantime
+		if s.rtmpConnections[mid] != cxn || cxn.suspended != timer {
+			return
+		}
+		clog.Infof(ctx, "Input stream did not reconnect manifestID=%s gracePeriod=%s", mid, ReconnectGracePeriod)
+		removeRTMPStreamLocked(ctx, s, mid)
+	})
+	cxn.suspended = timer
+	cxn.sessManager.events.record(streamEventIngestSuspended, nil)
+	clog.Infof(ctx, "Input stream disconnected, waiting for it to reconnect manifestID=%s gracePeriod=%s", mid, ReconnectGracePeriod)
+	return true
+}
+
+// resumeRTMPStream hands the suspended session of the manifest of rtmpStrm over to rtmpStrm. Returns false if the
+// manifest has no suspended session
+func (s *LivepeerServer) resumeRTMPStream(ctx context.Context, rtmpStrm stream.RTMPVideoStream) (*rtmpConnection, bool) {
+	params := streamParams(rtmpStrm.AppData())
+	if params == nil {
+		return nil, false
+	}
+
+	s.connectionLock.Lock()
+	defer s.connectionLock.Unlock()
+
+	cxn, ok := s.rtmpConnections[params.ManifestID]
+	if !ok || cxn.suspended == nil {
+		return nil, false
+	}
+	cxn.suspended.Stop()
+	cxn.suspended = nil
+	cxn.streamLock.Lock()
+	cxn.stream = rtmpStrm
+	cxn.streamLock.Unlock()
+	cxn.lastUsed = time.Now()
+	cxn.sessManager.events.record(streamEventIngestResumed, nil)
+	clog.Infof(ctx, "Input stream reconnected manifestID=%s", params.ManifestID)
+	return cxn, true
+}
+
 func (s *LivepeerServer) registerConnection(ctx context.Context, rtmpStrm stream.RTMPVideoStream, actualStreamCodec *ffmpeg.VideoCodec, pixelFormat ffmpeg.PixelFormat) (*rtmpConnection, error) {
 	ctx = clog.Clone(context.Background(), ctx)
 	// Set up the connection tracking
@@ -546,7 +872,37 @@ func (s *LivepeerServer) registerConnection(ctx context.Context, rtmpStrm stream
 		return oldCxn, errAlreadyExists
 	}
 
-	playlist := core.NewBasicPlaylistManager(mid, storage, recordStorage)
+	if err := BroadcastFunds.admit(time.Now()); err != nil {
+		clog.Errorf(ctx, "Refusing stream, broadcaster funds are low")
+		return nil, err
+	}
+
+	profiles, err := BroadcastBudget.admit(params.Profiles, time.Now())
+	if err != nil {
+		clog.Errorf(ctx, "Refusing stream, spend budget exhausted")
+		return nil, err
+	}
+	if len(profiles) < len(params.Profiles) {
+		clog.Warningf(ctx, "Spend budget nearly exhausted, degrading stream to profile=%s", profiles[0].Name)
+		params.Profiles = profiles
+		// Keep the degraded profile instead of generating a ladder or simulcasting
+		params.AutoLadder = false
+		params.OutputGroups = nil
+		if params.Capabilities, err = core.JobCapabilities(params); err != nil {
+			return nil, err
+		}
+	}
+
+	var playlist core.PlaylistManager
+	if SharedSessions && s.LivepeerNode.Database != nil {
+		playlist, err = core.NewSharedPlaylistManager(mid, storage, recordStorage, s.LivepeerNode.Database, monitor.NodeID)
+		if err != nil {
+			clog.Errorf(ctx, "Unable to load shared stream err=%q", err)
+			return nil, err
+		}
+	} else {
+		playlist = core.NewBasicPlaylistManager(mid, storage, recordStorage)
+	}
 	var stakeRdr stakeReader
 	if s.LivepeerNode.Eth != nil {
 		stakeRdr = &storeStakeReader{store: s.LivepeerNode.Database}
@@ -555,14 +911,23 @@ func (s *LivepeerServer) registerConnection(ctx context.Context, rtmpStrm stream
 		return NewMinLSSelectorWithRandFreq(stakeRdr, 1.0, SelectRandFreq)
 	}
 	cxn := &rtmpConnection{
-		mid:         mid,
-		nonce:       params.Nonce,
-		stream:      rtmpStrm,
-		pl:          playlist,
-		profile:     &vProfile,
-		params:      params,
-		sessManager: NewSessionManager(ctx, s.LivepeerNode, params, selFactory),
-		lastUsed:    time.Now(),
+		mid:        mid,
+		nonce:      params.Nonce,
+		stream:     rtmpStrm,
+		pl:         playlist,
+		profile:    &vProfile,
+		params:     params,
+		lastUsed:   time.Now(),
+		detections: core.NewDetectionAggregator(params.Detection),
+		splices:    core.NewSpliceTracker(),
+	}
+	if len(params.OutputGroups) > 0 {
+		if cxn.groups, err = newOutputGroups(ctx, s.LivepeerNode, cxn, selFactory); err != nil {
+			return nil, err
+		}
+		cxn.sessManager = cxn.groups[0].sessManager
+	} else {
+		cxn.sessManager = NewSessionManager(ctx, s.LivepeerNode, params, selFactory)
 	}
 
 	s.connectionLock.Lock()
@@ -572,7 +937,7 @@ func (s *LivepeerServer) registerConnection(ctx context.Context, rtmpStrm stream
 	if exists {
 		// We can only have one concurrent stream per ManifestID
 		s.connectionLock.Unlock()
-		cxn.sessManager.cleanup()
+		cxn.cleanupSessions()
 		return oldCxn, errAlreadyExists
 	}
 	s.rtmpConnections[mid] = cxn
@@ -587,6 +952,11 @@ func (s *LivepeerServer) registerConnection(ctx context.Context, rtmpStrm stream
 		monitor.FastVerificationEnabledAndUsingCurrentSessions(fastVerificationEnabled, fastVerificationUsing)
 	}
 
+	cxn.sessManager.events.record(streamEventIngestStarted, map[string]interface{}{
+		"nonce":    params.Nonce,
+		"profiles": common.ProfilesNames(params.Profiles),
+	})
+
 	return cxn, nil
 }
 
@@ -606,6 +976,11 @@ func countStreamsWithFastVerificationEnabled(rtmpConnections map[core.ManifestID
 func removeRTMPStream(ctx context.Context, s *LivepeerServer, extmid core.ManifestID) error {
 	s.connectionLock.Lock()
 	defer s.connectionLock.Unlock()
+	return removeRTMPStreamLocked(ctx, s, extmid)
+}
+
+// removeRTMPStreamLocked is removeRTMPStream for callers that hold the connectionLock
+func removeRTMPStreamLocked(ctx context.Context, s *LivepeerServer, extmid core.ManifestID) error {
 	intmid := extmid
 	if _intmid, exists := s.internalManifests[extmid]; exists {
 		// Use the internal manifestID that was stored for the provided manifestID
@@ -617,9 +992,16 @@ func removeRTMPStream(ctx context.Context, s *LivepeerServer, extmid core.Manife
 		clog.Warningf(ctx, "Attempted to end unknown stream with manifestID=%s", extmid)
 		return errUnknownStream
 	}
+	if cxn.suspended != nil {
+		cxn.suspended.Stop()
+	}
+	if cxn.standby != nil {
+		cxn.standby.Close()
+	}
 	cxn.stream.Close()
-	cxn.sessManager.cleanup()
+	cxn.cleanupSessions()
 	cxn.pl.Cleanup()
+	cxn.sessManager.events.record(streamEventStreamEnded, nil)
 	clog.Infof(ctx, "Ended stream with manifestID=%s external manifestID=%s", intmid, extmid)
 	delete(s.rtmpConnections, intmid)
 	delete(s.internalManifests, extmid)
@@ -654,7 +1036,7 @@ func getHLSMasterPlaylistHandler(s *LivepeerServer) func(url *url.URL) (*m3u8.Ma
 		defer s.connectionLock.RUnlock()
 		cxn, ok := s.rtmpConnections[manifestID]
 		if !ok || cxn.pl == nil {
-			return nil, vidplayer.ErrNotFound
+			return getSharedMasterPlaylist(s, manifestID)
 		}
 		cpl := cxn.pl
 
@@ -673,7 +1055,7 @@ func getHLSMediaPlaylistHandler(s *LivepeerServer) func(url *url.URL) (*m3u8.Med
 		defer s.connectionLock.RUnlock()
 		cxn, ok := s.rtmpConnections[mid]
 		if !ok || cxn.pl == nil {
-			return nil, vidplayer.ErrNotFound
+			return getSharedMediaPlaylist(s, mid, strmID.Rendition)
 		}
 
 		//Get the hls playlist
@@ -685,6 +1067,37 @@ func getHLSMediaPlaylistHandler(s *LivepeerServer) func(url *url.URL) (*m3u8.Med
 	}
 }
 
+// getSharedMasterPlaylist returns the master playlist of a stream that is ingested by another broadcaster of the
+// cluster
+func getSharedMasterPlaylist(s *LivepeerServer, mid core.ManifestID) (*m3u8.MasterPlaylist, error) {
+	if !SharedSessions || s.LivepeerNode.Database == nil {
+		return nil, vidplayer.ErrNotFound
+	}
+	pl, err := core.SharedMasterPlaylist(s.LivepeerNode.Database, mid)
+	if err != nil {
+		glog.Errorf("Error loading shared master playlist manifestID=%s err=%q", mid, err)
+	}
+	if pl == nil {
+		return nil, vidplayer.ErrNotFound
+	}
+	return pl, nil
+}
+
+// getSharedMediaPlaylist returns a media playlist of a stream that is ingested by another broadcaster of the cluster
+func getSharedMediaPlaylist(s *LivepeerServer, mid core.ManifestID, rendition string) (*m3u8.MediaPlaylist, error) {
+	if !SharedSessions || s.LivepeerNode.Database == nil {
+		return nil, vidplayer.ErrNotFound
+	}
+	pl, err := core.SharedMediaPlaylist(s.LivepeerNode.Database, mid, rendition)
+	if err != nil {
+		glog.Errorf("Error loading shared media playlist manifestID=%s rendition=%s err=%q", mid, rendition, err)
+	}
+	if pl == nil {
+		return nil, vidplayer.ErrNotFound
+	}
+	return pl, nil
+}
+
 func getHLSSegmentHandler(s *LivepeerServer) func(url *url.URL) ([]byte, error) {
 	return func(url *url.URL) ([]byte, error) {
 		// Strip the /stream/ prefix
@@ -825,6 +1238,12 @@ func (s *LivepeerServer) HandlePush(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	duration, err := strconv.Atoi(r.Header.Get("Content-Duration"))
+	if err != nil {
+		duration = 2000
+		glog.Info("Missing duration; filling in a default of 2000ms")
+	}
+
 	// Check for presence and register if a fresh cxn
 	if !exists {
 		appData := (createRTMPStreamIDHandler(ctx, s))(r.URL)
@@ -857,6 +1276,7 @@ func (s *LivepeerServer) HandlePush(w http.ResponseWriter, r *http.Request) {
 			s.connectionLock.RUnlock()
 		}
 		st := stream.NewBasicRTMPVideoStream(appData)
+		applyAutoLadder(ctx, params, body, float64(duration)/1000.0)
 		// Set output formats if not explicitly specified
 		for i, v := range params.Profiles {
 			if ffmpeg.FormatNone == v.Format {
@@ -867,7 +1287,10 @@ func (s *LivepeerServer) HandlePush(w http.ResponseWriter, r *http.Request) {
 		cxn, err = s.registerConnection(ctx, st, vcodec, pixelFormat)
 		if err != nil {
 			st.Close()
-			if err != errAlreadyExists {
+			if err == errBudgetExhausted || err == errFundsLow {
+				errorOut(http.StatusServiceUnavailable, "http push error url=%s err=%q", r.URL, err)
+				return
+			} else if err != errAlreadyExists {
 				errorOut(http.StatusInternalServerError, "http push error url=%s err=%q", r.URL, err)
 				return
 			} // else we continue with the old cxn
@@ -935,12 +1358,6 @@ func (s *LivepeerServer) HandlePush(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx = clog.AddSeqNo(ctx, seq)
 
-	duration, err := strconv.Atoi(r.Header.Get("Content-Duration"))
-	if err != nil {
-		duration = 2000
-		glog.Info("Missing duration; filling in a default of 2000ms")
-	}
-
 	seg := &stream.HLSSegment{
 		Data:        body,
 		Name:        fname,
@@ -1023,6 +1440,10 @@ func (s *LivepeerServer) HandlePush(w http.ResponseWriter, r *http.Request) {
 	mw := multipart.NewWriter(w)
 	var fw io.Writer
 	for i, url := range urls {
+		if url == "" {
+			// The rendition belongs to an output group that failed to transcode the segment
+			continue
+		}
 		mw.SetBoundary(boundary)
 		var typ, ext string
 		length := len(renditionData[i])
@@ -1220,7 +1641,19 @@ func (s *LivepeerServer) streamMP4(w http.ResponseWriter, r *http.Request, jpl *
 				glog.Errorf("Non-200 response for status=%v uri=%s manifestID=%s request=%s", resp.Status, segUri, manifestID, r.URL.String())
 				return
 			}
-			wn, err := io.Copy(iw, resp.Body)
+			var body io.Reader = resp.Body
+			if drivers.RecordEncryption != nil {
+				data, err := ioutil.ReadAll(resp.Body)
+				if err == nil {
+					data, err = drivers.DecryptObject(r.Context(), drivers.RecordEncryption, data)
+				}
+				if err != nil {
+					glog.Errorf("Error decrypting uri=%s manifestID=%s err=%q", segUri, manifestID, err)
+					return
+				}
+				body = bytes.NewReader(data)
+			}
+			wn, err := io.Copy(iw, body)
 			if err != nil {
 				glog.Errorf("Error transmuxing to mp4 request=%s uri=%s manifestID=%s err=%q", r.URL.String(), segUri, manifestID, err)
 			}
@@ -1236,6 +1669,18 @@ func (s *LivepeerServer) streamMP4(w http.ResponseWriter, r *http.Request, jpl *
 	}
 }
 
+// authorizeRecordingDecryption returns an error if the recordings of mid may not be served decrypted. Decryption
+// requires a valid playback token if tokens are required, and the authentication webhook otherwise
+func authorizeRecordingDecryption(mid core.ManifestID, token string) error {
+	if len(PlaybackTokenSecret) > 0 {
+		return verifyPlaybackToken(PlaybackTokenSecret, mid, token, time.Now())
+	}
+	if AuthWebhookURL == nil {
+		return errors.New("neither playback tokens nor the auth webhook are configured")
+	}
+	return nil
+}
+
 // HandleRecordings handle requests to /recordings/ endpoint
 func (s *LivepeerServer) HandleRecordings(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
@@ -1274,10 +1719,23 @@ func (s *LivepeerServer) HandleRecordings(w http.ResponseWriter, r *http.Request
 	}
 	manifestID := pp[2]
 	requestFileName := strings.Join(pp[2:], "/")
+	// Encrypted recordings are decrypted for authorized requests only. Without a playback token the webhook authorizes
+	// the request, so its response is only reused for requests with the same query, i.e. the same credentials
+	authKey := manifestID
+	if drivers.RecordEncryption != nil {
+		if err := authorizeRecordingDecryption(core.ManifestID(manifestID), r.URL.Query().Get(playbackTokenParam)); err != nil {
+			glog.Errorf("Decryption of recording not authorized url=%s err=%q", r.URL.String(), err)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if len(PlaybackTokenSecret) == 0 {
+			authKey = manifestID + "?" + r.URL.RawQuery
+		}
+	}
 	var fromCache bool
 	var err error
 	var resp *authWebhookResponse
-	if cresp, has := s.recordingsAuthResponses.Get(manifestID); has {
+	if cresp, has := s.recordingsAuthResponses.Get(authKey); has {
 		resp = cresp.(*authWebhookResponse)
 		fromCache = true
 	} else if resp, err = authenticateStream(r.URL.String()); err != nil {
@@ -1292,7 +1750,7 @@ func (s *LivepeerServer) HandleRecordings(w http.ResponseWriter, r *http.Request
 	var sess drivers.OSSession
 	ctx := r.Context()
 	if resp != nil && !fromCache {
-		s.recordingsAuthResponses.SetDefault(manifestID, resp)
+		s.recordingsAuthResponses.SetDefault(authKey, resp)
 	}
 	ctx = clog.AddManifestID(ctx, manifestID)
 
@@ -1311,6 +1769,15 @@ func (s *LivepeerServer) HandleRecordings(w http.ResponseWriter, r *http.Request
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	// Segments of encrypted recordings are only served decrypted through the node
+	var extURL string
+	if resp != nil {
+		extURL = resp.RecordObjectStoreURL
+	}
+	if drivers.RecordEncryption != nil {
+		sess = drivers.NewEncryptedSession(sess, drivers.RecordEncryption)
+		extURL = ""
+	}
 
 	startRead := time.Now()
 	fi, err := sess.ReadData(ctx, requestFileName)
@@ -1446,7 +1913,7 @@ func (s *LivepeerServer) HandleRecordings(w http.ResponseWriter, r *http.Request
 	if finalize {
 		for trackName := range mainJspl.Segments {
 			mpl := mediaLists[trackName]
-			mainJspl.AddSegmentsToMPL(manifests, trackName, mpl, resp.RecordObjectStoreURL)
+			mainJspl.AddSegmentsToMPL(manifests, trackName, mpl, extURL)
 			fileName := trackName + ".m3u8"
 			nows := time.Now()
 			_, err = sess.SaveData(ctx, fileName, mpl.Encode().Bytes(), nil, 0)
@@ -1468,7 +1935,7 @@ func (s *LivepeerServer) HandleRecordings(w http.ResponseWriter, r *http.Request
 	} else if !returnMasterPlaylist {
 		mpl := mediaLists[track]
 		if mpl != nil {
-			mainJspl.AddSegmentsToMPL(manifests, track, mpl, resp.RecordObjectStoreURL)
+			mainJspl.AddSegmentsToMPL(manifests, track, mpl, extURL)
 			// check (debug code)
 			startSeq := mpl.Segments[0].SeqId
 			for _, seg := range mpl.Segments[1:] {
@@ -1520,14 +1987,18 @@ func parseManifestID(reqPath string) core.ManifestID {
 	return parseStreamID(reqPath).ManifestID
 }
 
-func parsePresets(presets []string) []ffmpeg.VideoProfile {
+// parsePresets returns the built-in profiles with the given names. Unknown names are rejected rather than skipped
+// so that a typo does not silently result in a different transcoding ladder
+func parsePresets(presets []string) ([]ffmpeg.VideoProfile, error) {
 	profs := make([]ffmpeg.VideoProfile, 0)
 	for _, v := range presets {
-		if p, ok := ffmpeg.VideoProfileLookup[strings.TrimSpace(v)]; ok {
-			profs = append(profs, p)
+		p, ok := ffmpeg.VideoProfileLookup[strings.TrimSpace(v)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", errPreset, v)
 		}
+		profs = append(profs, p)
 	}
-	return profs
+	return profs, nil
 }
 
 func (s *LivepeerServer) LastManifestID() core.ManifestID {
@@ -1557,6 +2028,9 @@ func (s *LivepeerServer) GetNodeStatus() *common.NodeStatus {
 		m[string(cpl.ManifestID())] = cpl.GetHLSMasterPlaylist()
 		sb := atomic.LoadUint64(&cxn.sourceBytes)
 		tb := atomic.LoadUint64(&cxn.transcodedBytes)
+		for _, g := range cxn.groups {
+			tb += atomic.LoadUint64(&g.transcodedBytes)
+		}
 		streamInfo[string(cpl.ManifestID())] = common.StreamInfo{
 			SourceBytes:     sb,
 			TranscodedBytes: tb,
@@ -1607,10 +2081,37 @@ func shouldStopStream(err error) bool {
 	return ok
 }
 
+// getRemoteAddr returns the IP address of the client of r
 func getRemoteAddr(r *http.Request) string {
-	addr := r.RemoteAddr
-	if proxiedAddr := r.Header.Get("X-Forwarded-For"); proxiedAddr != "" {
-		addr = strings.Split(proxiedAddr, ",")[0]
+	addr, _, err := gonet.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		addr = r.RemoteAddr
+	}
+	proxied := r.Header.Get("X-Forwarded-For")
+	if proxied == "" || !isTrustedProxy(addr) {
+		return addr
+	}
+	// Each proxy appends the address that it received the request from, so the right-most address that is not a
+	// trusted proxy is the client. The addresses to its left are set by the client and can be spoofed
+	hops := strings.Split(proxied, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr = strings.TrimSpace(hops[i])
+		if !isTrustedProxy(addr) {
+			break
+		}
+	}
+	return addr
+}
+
+func isTrustedProxy(addr string) bool {
+	ip := gonet.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range TrustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
 	}
-	return strings.Split(addr, ":")[0]
+	return false
 }
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -12,6 +13,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -31,6 +33,7 @@ import (
 	ffmpeg "github.com/livepeer/lpms/ffmpeg"
 	"github.com/livepeer/lpms/segmenter"
 	"github.com/livepeer/lpms/stream"
+	"github.com/livepeer/lpms/vidplayer"
 )
 
 // var S *LivepeerServer
@@ -538,6 +541,11 @@ func TestCreateRTMPStreamHandlerWebhook(t *testing.T) {
 
 	// set presets (with some invalid)
 	ts6 := makeServer(`{"manifestID":"a", "presets":["P240p30fps16x9", "unknown", "P720p30fps16x9"]}`)
+	assert.Nil(createSid(u), "Should fail on unknown presets")
+	ts6.Close()
+
+	// set presets
+	ts6 = makeServer(`{"manifestID":"a", "presets":["P240p30fps16x9", "P720p30fps16x9"]}`)
 	defer ts6.Close()
 	params = createSid(u).(*core.StreamParameters)
 	assert.Len(params.Profiles, 2)
@@ -619,11 +627,10 @@ func TestCreateRTMPStreamHandlerWebhook(t *testing.T) {
 	assert.Len(params.Profiles, 6)
 	assert.Equal(jointProfiles, params.Profiles, "Did not have matching profiles")
 
-	// all invalid presets in webhook should lead to empty set
+	// all invalid presets in webhook should fail
 	ts10 := makeServer(`{"manifestID":"a", "presets":["very", "unknown"]}`)
 	defer ts10.Close()
-	params = createSid(u).(*core.StreamParameters)
-	assert.Len(params.Profiles, 0, "Unexpected value in presets")
+	assert.Nil(createSid(u), "Should fail on unknown presets")
 
 	// invalid gops
 	ts11 := makeServer(`{"manifestID":"a", "profiles": [ {"gop": " 1 "}]}`)
@@ -691,6 +698,136 @@ func TestCreateRTMPStreamHandlerWebhook(t *testing.T) {
 	defer ts19.Close()
 	sid = createSid(u)
 	assert.Nil(sid)
+
+	// set detection threshold and playlist tagging
+	ts20 := makeServer(`{"manifestID":"a", "detection": {"freq": 5, "sampleRate": 10, "threshold": 0.8, "tagPlaylist": true, "sceneClassification": [{"name": "adult"}]}}`)
+	defer ts20.Close()
+	params = createSid(u).(*core.StreamParameters)
+	assert.Equal(0.8, params.Detection.Threshold)
+	assert.True(params.Detection.TagPlaylist)
+
+	// do not create stream if detection threshold is invalid
+	ts21 := makeServer(`{"manifestID":"a", "detection": {"freq": 5, "sampleRate": 10, "threshold": 1.5, "sceneClassification": [{"name": "adult"}]}}`)
+	defer ts21.Close()
+	sid = createSid(u)
+	assert.Nil(sid)
+
+	ts22 := makeServer(`{"manifestID":"a", "detection": {"freq": 5, "sampleRate": 10, "startThreshold": 0.8, "stopThreshold": 0.4, "smoothing": 0.3, "sceneClassification": [{"name": "adult"}]}}`)
+	defer ts22.Close()
+	params = createSid(u).(*core.StreamParameters)
+	assert.Equal(0.8, params.Detection.StartThreshold)
+	assert.Equal(0.4, params.Detection.StopThreshold)
+	assert.Equal(0.3, params.Detection.Smoothing)
+
+	// do not create stream if the stop threshold is above the start threshold
+	ts23 := makeServer(`{"manifestID":"a", "detection": {"freq": 5, "sampleRate": 10, "startThreshold": 0.4, "stopThreshold": 0.8, "sceneClassification": [{"name": "adult"}]}}`)
+	defer ts23.Close()
+	sid = createSid(u)
+	assert.Nil(sid)
+
+	ts24 := makeServer(`{"manifestID":"a", "filters": [{"name": "crop", "args": {"w": "iw-20", "h": "ih-20"}}, {"name": "fps", "args": {"fps": "30"}}]}`)
+	defer ts24.Close()
+	params = createSid(u).(*core.StreamParameters)
+	assert.Equal([]core.FilterStep{
+		{Name: "crop", Args: map[string]string{"w": "iw-20", "h": "ih-20"}},
+		{Name: "fps", Args: map[string]string{"fps": "30"}},
+	}, params.Filters)
+
+	// do not create stream if a filter is not allowed
+	ts25 := makeServer(`{"manifestID":"a", "filters": [{"name": "movie", "args": {"filename": "/etc/passwd"}}]}`)
+	defer ts25.Close()
+	sid = createSid(u)
+	assert.Nil(sid)
+	// do not create stream with filters and detection
+	ts25a := makeServer(`{"manifestID":"a", "filters": [{"name": "fps", "args": {"fps": "30"}}], "detection": {"freq": 5, "sampleRate": 10, "sceneClassification": [{"name": "adult"}]}}`)
+	defer ts25a.Close()
+	sid = createSid(u)
+	assert.Nil(sid)
+
+	// detection-only streams have no renditions
+	ts26 := makeServer(`{"manifestID":"a", "detection": {"freq": 1, "only": true, "sceneClassification": [{"name": "adult"}]}}`)
+	defer ts26.Close()
+	params = createSid(u).(*core.StreamParameters)
+	assert.Empty(params.Profiles)
+	assert.True(params.DetectionOnly())
+
+	// do not create detection-only streams without detection or with renditions
+	ts27 := makeServer(`{"manifestID":"a", "detection": {"only": true}}`)
+	defer ts27.Close()
+	sid = createSid(u)
+	assert.Nil(sid)
+	ts28 := makeServer(`{"manifestID":"a", "presets": ["P240p30fps16x9"], "detection": {"freq": 1, "only": true, "sceneClassification": [{"name": "adult"}]}}`)
+	defer ts28.Close()
+	sid = createSid(u)
+	assert.Nil(sid)
+
+	// simulcast streams have the profiles of all output groups
+	ts29 := makeServer(`{"manifestID":"a", "outputGroups": [{"name": "premium", "presets": ["P720p30fps16x9"], "maxPrice": {"pricePerUnit": 10, "pixelsPerUnit": 1}}, {"name": "mobile", "presets": ["P144p30fps16x9"]}]}`)
+	defer ts29.Close()
+	params = createSid(u).(*core.StreamParameters)
+	assert.Equal([]ffmpeg.VideoProfile{ffmpeg.P720p30fps16x9, ffmpeg.P144p30fps16x9}, params.Profiles)
+	require.Len(t, params.OutputGroups, 2)
+	assert.Equal("premium", params.OutputGroups[0].Name)
+	assert.Equal(big.NewRat(10, 1), params.OutputGroups[0].MaxPrice)
+	assert.False(params.AutoLadder)
+
+	// do not create simulcast streams with profiles of their own
+	ts30 := makeServer(`{"manifestID":"a", "presets": ["P240p30fps16x9"], "outputGroups": [{"name": "mobile", "presets": ["P144p30fps16x9"]}]}`)
+	defer ts30.Close()
+	sid = createSid(u)
+	assert.Nil(sid)
+}
+
+func TestCreateRTMPStreamHandler_DerivedManifestID(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s, cancel := setupServerWithCancel()
+	defer serverCleanup(s)
+	defer cancel()
+	createSid := createRTMPStreamIDHandler(context.TODO(), s)
+
+	defer func() { ManifestIDSecret = nil }()
+	ManifestIDSecret = []byte("secret")
+	expectedMid := core.DeriveManifestID(ManifestIDSecret, "streamkey", time.Now())
+
+	params := createSid(mustParseUrl(t, "rtmp://localhost/live/streamkey")).(*core.StreamParameters)
+	assert.Equal(expectedMid, params.ManifestID)
+	assert.Equal("streamkey", params.DerivedFrom)
+
+	// The key of the ingest URL is kept
+	params = createSid(mustParseUrl(t, "rtmp://localhost/streamkey/rtmpkey")).(*core.StreamParameters)
+	assert.Equal(expectedMid, params.ManifestID)
+	assert.Equal("rtmpkey", params.RtmpKey)
+
+	// Streams can opt out of derived manifest IDs
+	params = createSid(mustParseUrl(t, "rtmp://localhost/live/streamkey?deriveManifestID=false")).(*core.StreamParameters)
+	assert.Equal(core.ManifestID("streamkey"), params.ManifestID)
+	assert.Empty(params.DerivedFrom)
+
+	// The stream with the same key continues to use the manifest ID, another key that derives the same ID is refused
+	s.connectionLock.Lock()
+	s.rtmpConnections[expectedMid] = &rtmpConnection{params: &core.StreamParameters{ManifestID: expectedMid, DerivedFrom: "streamkey"}}
+	s.connectionLock.Unlock()
+	require.NotNil(createSid(mustParseUrl(t, "rtmp://localhost/live/streamkey")))
+	s.connectionLock.Lock()
+	s.rtmpConnections[expectedMid].params.DerivedFrom = "otherkey"
+	s.connectionLock.Unlock()
+	assert.Nil(createSid(mustParseUrl(t, "rtmp://localhost/live/streamkey")))
+	s.connectionLock.Lock()
+	delete(s.rtmpConnections, expectedMid)
+	s.connectionLock.Unlock()
+
+	// Manifest IDs of the webhook take precedence
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"manifestID":"webhookmid"}`))
+	}))
+	defer ts.Close()
+	defer func() { AuthWebhookURL = nil }()
+	AuthWebhookURL = mustParseUrl(t, ts.URL)
+	params = createSid(mustParseUrl(t, "rtmp://localhost/live/streamkey")).(*core.StreamParameters)
+	assert.Equal(core.ManifestID("webhookmid"), params.ManifestID)
+	assert.Empty(params.DerivedFrom)
 }
 
 func TestCreateRTMPStreamHandler(t *testing.T) {
@@ -805,6 +942,128 @@ func TestEndRTMPStreamHandler(t *testing.T) {
 	st.Close()
 }
 
+func TestEndRTMPStreamHandler_Reconnect(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	s, cancel := setupServerWithCancel()
+	defer serverCleanup(s)
+	defer cancel()
+	s.RTMPSegmenter = &StubSegmenter{skip: true}
+	ReconnectGracePeriod = 100 * time.Millisecond
+	defer func() { ReconnectGracePeriod = 0 }()
+	createSid := createRTMPStreamIDHandler(context.TODO(), s)
+	handler := gotRTMPStreamHandler(s)
+	endHandler := endRTMPStreamHandler(s)
+	u := mustParseUrl(t, "rtmp://localhost")
+	sid := createSid(u)
+	st := stream.NewBasicRTMPVideoStream(sid)
+	defer st.Close()
+	mid := streamParams(sid).ManifestID
+	connection := func() *rtmpConnection {
+		s.connectionLock.RLock()
+		defer s.connectionLock.RUnlock()
+		return s.rtmpConnections[mid]
+	}
+
+	require.Nil(handler(u, st))
+	cxn := connection()
+	require.NotNil(cxn)
+	atomic.StoreUint64(&cxn.nextSeqNo, 5)
+
+	// The session is kept after the input stream disconnects
+	assert.Nil(endHandler(u, st))
+	assert.Equal(cxn, connection())
+
+	// The reconnected stream continues the session after a discontinuity
+	st2 := stream.NewBasicRTMPVideoStream(sid)
+	defer st2.Close()
+	require.Nil(handler(u, st2))
+	assert.Equal(cxn, connection())
+	assert.Equal(st2, cxn.inputStream())
+	require.Nil(cxn.pl.InsertHLSSegment(cxn.profile, 4, "4.ts", 2))
+	require.Nil(cxn.pl.InsertHLSSegment(cxn.profile, 5, "5.ts", 2))
+	assert.Contains(cxn.pl.GetHLSMediaPlaylist(cxn.profile.Name).Encode().String(), "4.ts\n#EXT-X-DISCONTINUITY\n#EXTINF:2.000,\n5.ts\n")
+	// The grace period of the first disconnect does not end the reconnected stream
+	time.Sleep(2 * ReconnectGracePeriod)
+	assert.Equal(cxn, connection())
+
+	// The session ends if the stream does not reconnect within the grace period
+	assert.Nil(endHandler(u, st2))
+	assert.Eventually(func() bool { return connection() == nil }, time.Second, 10*time.Millisecond)
+	assert.Equal(errUnknownStream, endHandler(u, st2))
+}
+
+func TestEndRTMPStreamHandler_Backup(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	s, cancel := setupServerWithCancel()
+	defer serverCleanup(s)
+	defer cancel()
+	s.RTMPSegmenter = &StubSegmenter{skip: true}
+	createSid := createRTMPStreamIDHandler(context.TODO(), s)
+	handler := gotRTMPStreamHandler(s)
+	endHandler := endRTMPStreamHandler(s)
+	u := mustParseUrl(t, "rtmp://localhost/movie")
+	backupURL := mustParseUrl(t, "rtmp://localhost/movie?backup=true")
+	newStream := func(u *url.URL) stream.RTMPVideoStream {
+		st := stream.NewBasicRTMPVideoStream(createSid(u))
+		t.Cleanup(func() { st.Close() })
+		return st
+	}
+	connection := func() *rtmpConnection {
+		s.connectionLock.RLock()
+		defer s.connectionLock.RUnlock()
+		return s.rtmpConnections["movie"]
+	}
+	standby := func() stream.RTMPVideoStream {
+		s.connectionLock.RLock()
+		defer s.connectionLock.RUnlock()
+		return connection().standby
+	}
+
+	assert.False(isBackupIngest(u))
+	assert.True(isBackupIngest(backupURL))
+
+	primary := newStream(u)
+	require.Nil(handler(u, primary))
+	cxn := connection()
+	require.NotNil(cxn)
+	// A stream can only have one primary ingest
+	assert.Equal(errAlreadyExists, handler(u, newStream(u)))
+
+	// The backup ingest waits on standby
+	backup := newStream(backupURL)
+	require.Nil(handler(backupURL, backup))
+	assert.Equal(primary, cxn.inputStream())
+	assert.Equal(backup, standby())
+	assert.Equal(errAlreadyExists, handler(backupURL, newStream(backupURL)))
+
+	// The backup ingest takes over when the primary ingest disconnects
+	atomic.StoreUint64(&cxn.nextSeqNo, 3)
+	assert.Nil(endHandler(u, primary))
+	assert.Equal(cxn, connection())
+	assert.Equal(backup, cxn.inputStream())
+	assert.Nil(standby())
+	require.Nil(cxn.pl.InsertHLSSegment(cxn.profile, 2, "2.ts", 2))
+	require.Nil(cxn.pl.InsertHLSSegment(cxn.profile, 3, "3.ts", 2))
+	assert.Contains(cxn.pl.GetHLSMediaPlaylist(cxn.profile.Name).Encode().String(), "2.ts\n#EXT-X-DISCONTINUITY\n#EXTINF:2.000,\n3.ts\n")
+
+	// The reconnected primary ingest waits on standby until the backup ingest disconnects
+	reconnected := newStream(u)
+	require.Nil(handler(u, reconnected))
+	assert.Equal(backup, cxn.inputStream())
+	assert.Equal(reconnected, standby())
+
+	// Standby ingests can disconnect without affecting the stream
+	assert.Nil(endHandler(u, reconnected))
+	assert.Equal(cxn, connection())
+	assert.Nil(standby())
+
+	// The stream ends when its last ingest disconnects
+	assert.Nil(endHandler(backupURL, backup))
+	assert.Nil(connection())
+}
+
 // Should publish RTMP stream, turn the RTMP stream into HLS, and broadcast the HLS stream.
 func TestGotRTMPStreamHandler(t *testing.T) {
 	s, cancel := setupServerWithCancel()
@@ -996,6 +1255,77 @@ func TestGetHLSMasterPlaylistHandler(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 }
 
+func TestSharedSessions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	s, cancel := setupServerWithCancel()
+	defer serverCleanup(s)
+	defer cancel()
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+	s.LivepeerNode.Database = dbh
+	defer func() { s.LivepeerNode.Database = nil }()
+	SharedSessions = true
+	defer func() { SharedSessions = false }()
+
+	mid := core.SplitStreamIDString(t.Name()).ManifestID
+	strm := stream.NewBasicRTMPVideoStream(&core.StreamParameters{ManifestID: mid})
+	cxn, err := s.registerConnection(context.TODO(), strm, nil, PixelFormatNone())
+	require.Nil(err)
+	require.IsType(&core.SharedPlaylistManager{}, cxn.pl)
+	vProfile := ffmpeg.P144p30fps16x9
+	vProfile.Name = "source"
+	require.Nil(cxn.pl.InsertHLSSegment(&vProfile, 5, "http://store/5.ts", 2))
+	require.Eventually(func() bool {
+		stream, err := dbh.FindStream(string(mid))
+		return err == nil && stream != nil && stream.LastSeqNo == 5
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// Another broadcaster of the cluster serves the playlists of the stream
+	s.connectionLock.Lock()
+	delete(s.rtmpConnections, mid)
+	s.connectionLock.Unlock()
+	lost := cxn
+	defer func() {
+		lost.cleanupSessions()
+		lost.pl.Cleanup()
+	}()
+
+	master, err := getHLSMasterPlaylistHandler(s)(mustParseUrl(t, fmt.Sprintf("http://localhost/stream/%s.m3u8", mid)))
+	require.Nil(err)
+	require.Len(master.Variants, 1)
+	assert.Equal(fmt.Sprintf("%s/source.m3u8", mid), master.Variants[0].URI)
+
+	media, err := getHLSMediaPlaylistHandler(s)(mustParseUrl(t, fmt.Sprintf("http://localhost/stream/%s/source.m3u8", mid)))
+	require.Nil(err)
+	assert.Equal(uint(1), media.Count())
+	assert.Equal("http://store/5.ts", media.Segments[0].URI)
+
+	_, err = getHLSMediaPlaylistHandler(s)(mustParseUrl(t, fmt.Sprintf("http://localhost/stream/%s/unknown.m3u8", mid)))
+	assert.Equal(vidplayer.ErrNotFound, err)
+	_, err = getHLSMasterPlaylistHandler(s)(mustParseUrl(t, "http://localhost/stream/unknown.m3u8"))
+	assert.Equal(vidplayer.ErrNotFound, err)
+
+	// and takes over the stream
+	cxn, err = s.registerConnection(context.TODO(), strm, nil, PixelFormatNone())
+	require.Nil(err)
+	lastSeqNo, resumed := cxn.pl.(*core.SharedPlaylistManager).LastSeqNo()
+	assert.True(resumed)
+	assert.Equal(uint64(5), lastSeqNo)
+	assert.Equal(uint(1), cxn.pl.GetHLSMediaPlaylist("source").Count())
+	require.Nil(removeRTMPStream(context.TODO(), s, mid))
+
+	// Playlists are not shared if disabled
+	SharedSessions = false
+	cxn, err = s.registerConnection(context.TODO(), strm, nil, PixelFormatNone())
+	require.Nil(err)
+	assert.IsType(&core.BasicPlaylistManager{}, cxn.pl)
+	require.Nil(removeRTMPStream(context.TODO(), s, mid))
+}
+
 func TestRegisterConnection(t *testing.T) {
 	assert := assert.New(t)
 	s, cancel := setupServerWithCancel()
@@ -1240,16 +1570,25 @@ func TestParsePresets(t *testing.T) {
 	assert := assert.New(t)
 	presets := []string{"P240p30fps16x9", "unknown", "P720p30fps16x9"}
 
-	p := parsePresets([]string{})
+	p, err := parsePresets([]string{})
+	assert.Nil(err)
 	assert.Equal([]ffmpeg.VideoProfile{}, p)
 
-	p = parsePresets(nil)
+	p, err = parsePresets(nil)
+	assert.Nil(err)
 	assert.Equal([]ffmpeg.VideoProfile{}, p)
 
-	p = parsePresets([]string{"bad", "example"})
-	assert.Equal([]ffmpeg.VideoProfile{}, p)
+	p, err = parsePresets([]string{"bad", "example"})
+	assert.True(errors.Is(err, errPreset))
+	assert.Nil(p)
 
-	p = parsePresets(presets)
+	// Unknown presets are rejected instead of skipped
+	p, err = parsePresets(presets)
+	assert.EqualError(err, `unrecognized transcoding preset: "unknown"`)
+	assert.Nil(p)
+
+	p, err = parsePresets([]string{"P240p30fps16x9", " P720p30fps16x9"})
+	assert.Nil(err)
 	assert.Equal([]ffmpeg.VideoProfile{ffmpeg.P240p30fps16x9, ffmpeg.P720p30fps16x9}, p)
 
 }
@@ -1344,3 +1683,33 @@ func mustParseUrl(t *testing.T, str string) *url.URL {
 	}
 	return url
 }
+
+func TestGetRemoteAddr(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func() { TrustedProxies = nil }()
+	req := func(remoteAddr, forwardedFor string) *http.Request {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			r.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		return r
+	}
+
+	assert.Equal("1.2.3.4", getRemoteAddr(req("1.2.3.4:5678", "")))
+	assert.Equal("2001:db8::1", getRemoteAddr(req("[2001:db8::1]:5678", "")))
+
+	// X-Forwarded-For is ignored for requests that are not from a trusted proxy
+	assert.Equal("1.2.3.4", getRemoteAddr(req("1.2.3.4:5678", "5.6.7.8")))
+
+	_, proxies, _ := gonet.ParseCIDR("10.0.0.0/8")
+	TrustedProxies = []*gonet.IPNet{proxies}
+	assert.Equal("5.6.7.8", getRemoteAddr(req("10.0.0.1:5678", "5.6.7.8")))
+	assert.Equal("2001:db8::2", getRemoteAddr(req("10.0.0.1:5678", "2001:db8::2")))
+	// The client cannot spoof its address by prepending addresses
+	assert.Equal("5.6.7.8", getRemoteAddr(req("10.0.0.1:5678", "9.9.9.9, 5.6.7.8")))
+	// Chained trusted proxies are skipped
+	assert.Equal("5.6.7.8", getRemoteAddr(req("10.0.0.1:5678", "9.9.9.9, 5.6.7.8, 10.0.0.2")))
+	assert.Equal("1.2.3.4", getRemoteAddr(req("1.2.3.4:5678", "5.6.7.8")))
+}
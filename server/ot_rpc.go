@@ -46,13 +46,56 @@ var errCapabilities = errors.New("incompatible segment capabilities")
 
 // Standalone Transcoder
 
+// drainer stops the standalone transcoder from receiving new segments so that it can be shut down, i.e. when it is
+// scaled down, without dropping the segments that it is transcoding
+type drainer struct {
+	once     sync.Once
+	draining chan struct{}
+	drained  chan struct{}
+}
+
+func newDrainer() *drainer {
+	return &drainer{
+		draining: make(chan struct{}),
+		drained:  make(chan struct{}),
+	}
+}
+
+// drain starts draining the transcoder and returns a channel that is closed once the running segments are transcoded
+func (d *drainer) drain() <-chan struct{} {
+	d.once.Do(func() {
+		glog.Info("Draining transcoder")
+		close(d.draining)
+	})
+	return d.drained
+}
+
+var transcoderDrainer = newDrainer()
+
 // RunTranscoder is main routing of standalone transcoder
 // Exiting it will terminate executable
 func RunTranscoder(n *core.LivepeerNode, orchAddr string, capacity int, caps []core.Capability) {
+	d := transcoderDrainer
+	defer close(d.drained)
+
+	// Stop reconnecting to the orchestrator once the transcoder is draining
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-d.draining:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	expb := backoff.NewExponentialBackOff()
 	expb.MaxInterval = time.Minute
 	expb.MaxElapsedTime = 0
 	backoff.Retry(func() error {
+		if ctx.Err() != nil {
+			return nil
+		}
 		glog.Info("Registering transcoder to ", orchAddr)
 		err := runTranscoder(n, orchAddr, capacity, caps)
 		glog.Info("Unregistering transcoder: ", err)
@@ -61,9 +104,13 @@ func RunTranscoder(n *core.LivepeerNode, orchAddr string, capacity int, caps []c
 			// Returning nil here will make `backoff` to stop trying to reconnect and exit
 			return nil
 		}
+		if ctx.Err() != nil {
+			glog.Info("Terminating drained transcoder")
+			return nil
+		}
 		// By returning error we tell `backoff` to try to connect again
 		return err
-	}, expb)
+	}, backoff.WithContext(expb, ctx))
 }
 
 func checkTranscoderError(err error) error {
@@ -115,6 +162,12 @@ func runTranscoder(n *core.LivepeerNode, orchAddr string, capacity int, caps []c
 			// Cancelling context will close connection to orchestrator
 			cancel()
 			return
+		case <-transcoderDrainer.draining:
+			// The orchestrator stops sending segments once the connection is closed
+			cancel()
+			return
+		case <-ctx.Done():
+			return
 		}
 	}()
 
@@ -157,6 +210,11 @@ func runTranscode(n *core.LivepeerNode, orchAddr string, httpc *http.Client, not
 	}
 	ctx = clog.AddSeqNo(ctx, uint64(md.Seq))
 	ctx = clog.AddVal(ctx, "taskId", strconv.FormatInt(notify.TaskId, 10))
+	if md.TimeToDeadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, md.TimeToDeadline)
+		defer cancel()
+	}
 	if n.Capabilities != nil && !md.Caps.CompatibleWith(n.Capabilities.ToNetCapabilities()) {
 		clog.Errorf(ctx, "Requested capabilities for segment are not compatible with this node taskId=%d url=%s err=%q", notify.TaskId, notify.Url, errCapabilities)
 		sendTranscodeResult(ctx, n, orchAddr, httpc, notify, contentType, &body, tData, errCapabilities)
@@ -188,6 +246,12 @@ func runTranscode(n *core.LivepeerNode, orchAddr string, httpc *http.Client, not
 	md.Fname = fname
 	clog.V(common.DEBUG).Infof(ctx, "Segment from taskId=%d url=%s saved to file=%s", notify.TaskId, notify.Url, fname)
 
+	if ctx.Err() == context.DeadlineExceeded {
+		clog.Errorf(ctx, "Abandoning segment past its deadline taskId=%d url=%s", notify.TaskId, notify.Url)
+		sendTranscodeResult(ctx, n, orchAddr, httpc, notify, contentType, &body, tData, core.ErrSegmentDeadlineExceeded)
+		return
+	}
+
 	start := time.Now()
 	tData, err = n.Transcoder.Transcode(ctx, md)
 	clog.V(common.VERBOSE).InfofErr(ctx, "Transcoding done for taskId=%d url=%s dur=%v", notify.TaskId, notify.Url, time.Since(start), err)
@@ -17,6 +17,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/core"
@@ -52,6 +53,24 @@ func (st *stubTranscoder) Transcode(ctx context.Context, md *core.SegTranscoding
 
 // Tests that `runTranscode` actually calls transcoders `Transcoder` function,
 // sends results back by HTTP, and doesn't panic if it can't contact orchestrator
+func TestRunTranscoder_Drain(t *testing.T) {
+	defer func(d *drainer) { transcoderDrainer = d }(transcoderDrainer)
+	transcoderDrainer = newDrainer()
+
+	drained := transcoderDrainer.drain()
+	// Draining more than once is a no-op
+	assert.Equal(t, drained, transcoderDrainer.drain())
+
+	// A draining transcoder does not connect to the orchestrator
+	node, _ := core.NewLivepeerNode(nil, "/tmp/thisdirisnotactuallyusedinthistest", nil)
+	go RunTranscoder(node, "127.0.0.1:1", 1, nil)
+	select {
+	case <-drained:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "transcoder not drained")
+	}
+}
+
 func TestRemoteTranscoder_Profiles(t *testing.T) {
 	httpc := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
 	profiles := []ffmpeg.VideoProfile{ffmpeg.P720p60fps16x9, ffmpeg.P144p30fps16x9}
@@ -207,7 +226,9 @@ func TestRemoteTranscoder_Error(t *testing.T) {
 	assert := assert.New(t)
 	assert.Nil(nil)
 	var segmentRead int
+	var segmentDelay time.Duration
 	segmentTs := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(segmentDelay)
 		_, err := ioutil.ReadAll(r.Body)
 		assert.NoError(err)
 		w.Write([]byte("segment's binary data"))
@@ -272,6 +293,15 @@ func TestRemoteTranscoder_Error(t *testing.T) {
 	assert.NotNil(body)
 	assert.Equal("segment / profile mismatch", string(body))
 
+	// work is abandoned at the deadline of the segment
+	segmentDelay = 100 * time.Millisecond
+	notify.SegData.TimeToDeadline = 10
+	runTranscode(node, parsedURL.Host, httpc, notify)
+	assert.Equal(2, tr.called)
+	assert.Equal(core.ErrSegmentDeadlineExceeded.Error(), string(body))
+	segmentDelay = 0
+	notify.SegData.TimeToDeadline = 0
+
 	// unrecoverable error
 	// send the response and panic
 	tr.err = core.NewUnrecoverableError(errors.New("some error"))
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/livepeer/go-livepeer/clog"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/policy"
+	"github.com/livepeer/go-livepeer/verification"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/livepeer/lpms/stream"
+)
+
+// authOutputGroup is an output group of the stream in the response of the auth webhook
+type authOutputGroup struct {
+	Name     string               `json:"name"`
+	Presets  []string             `json:"presets"`
+	Profiles []ffmpeg.JsonProfile `json:"profiles"`
+	// MaxPrice is the max price of the orchestrators of the group. The max price of the broadcaster applies if not set
+	MaxPrice *policy.Price `json:"maxPrice"`
+}
+
+// parseOutputGroups parses the output groups of the auth webhook response. The renditions of all groups end up in the
+// playlists of the stream, so their names must be unique across the groups
+func parseOutputGroups(groups []authOutputGroup) ([]core.OutputGroup, error) {
+	var parsed []core.OutputGroup
+	groupNames := make(map[string]bool)
+	renditionNames := make(map[string]bool)
+	for _, g := range groups {
+		if g.Name == "" {
+			return nil, errors.New("missing output group name")
+		}
+		if groupNames[g.Name] {
+			return nil, fmt.Errorf("duplicate output group name=%s", g.Name)
+		}
+		groupNames[g.Name] = true
+
+		profiles, err := parsePresets(g.Presets)
+		if err != nil {
+			return nil, err
+		}
+		jsonProfiles, err := ffmpeg.ParseProfilesFromJsonProfileArray(g.Profiles)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, jsonProfiles...)
+		if len(profiles) == 0 {
+			return nil, fmt.Errorf("output group without profiles name=%s", g.Name)
+		}
+		for _, p := range profiles {
+			if renditionNames[p.Name] {
+				return nil, fmt.Errorf("duplicate rendition name=%s in output group name=%s", p.Name, g.Name)
+			}
+			renditionNames[p.Name] = true
+		}
+
+		group := core.OutputGroup{Name: g.Name, Profiles: profiles}
+		if g.MaxPrice != nil {
+			if group.MaxPrice, err = g.MaxPrice.Rat(); err != nil {
+				return nil, fmt.Errorf("invalid max price of output group name=%s: %w", g.Name, err)
+			}
+		}
+		parsed = append(parsed, group)
+	}
+	return parsed, nil
+}
+
+// outputGroupsProfiles returns the profiles of all groups in the order of the groups
+func outputGroupsProfiles(groups []core.OutputGroup) []ffmpeg.VideoProfile {
+	var profiles []ffmpeg.VideoProfile
+	for _, g := range groups {
+		profiles = append(profiles, g.Profiles...)
+	}
+	return profiles
+}
+
+// newOutputGroups creates the sub-sessions of cxn that transcode its output groups. Each sub-session selects
+// orchestrators of its own for the profiles and max price of its group. Content detection runs with the first group
+func newOutputGroups(ctx context.Context, node *core.LivepeerNode, cxn *rtmpConnection,
+	sel BroadcastSessionsSelectorFactory) ([]*rtmpConnection, error) {
+
+	var groups []*rtmpConnection
+	// The profiles of the stream are those of the groups in order, with the output formats set for HTTP push
+	offset := 0
+	for i, g := range cxn.params.OutputGroups {
+		params := *cxn.params
+		params.Profiles = append([]ffmpeg.VideoProfile(nil), cxn.params.Profiles[offset:offset+len(g.Profiles)]...)
+		offset += len(g.Profiles)
+		params.MaxPrice = g.MaxPrice
+		params.OutputGroups = nil
+		params.AutoLadder = false
+		if i > 0 {
+			params.Detection = core.DetectionConfig{}
+		}
+		caps, err := core.JobCapabilities(&params)
+		if err != nil {
+			for _, g := range groups {
+				g.sessManager.cleanup()
+			}
+			return nil, err
+		}
+		params.Capabilities = caps
+
+		gctx := clog.AddVal(ctx, "outputGroup", g.Name)
+		groups = append(groups, &rtmpConnection{
+			mid:         cxn.mid,
+			nonce:       cxn.nonce,
+			pl:          cxn.pl,
+			profile:     cxn.profile,
+			params:      &params,
+			sessManager: NewSessionManager(gctx, node, &params, sel),
+			detections:  cxn.detections,
+			parent:      cxn,
+			group:       g.Name,
+		})
+	}
+	return groups, nil
+}
+
+// cleanupSessions ends the sessions of the stream and of its output groups
+func (cxn *rtmpConnection) cleanupSessions() {
+	cxn.sessManager.cleanup()
+	for _, g := range cxn.groups {
+		if g.sessManager != cxn.sessManager {
+			g.sessManager.cleanup()
+		}
+	}
+}
+
+// transcodeOutputGroups transcodes seg for all output groups of the stream concurrently. The URLs of the renditions
+// are returned in the order of the groups, which is the order of the profiles of the stream. Groups fail
+// independently: the renditions of the groups that succeeded are added to the playlists and returned, while the
+// URLs of the renditions of the failed groups are left empty. The segment only fails if all groups failed
+func transcodeOutputGroups(ctx context.Context, cxn *rtmpConnection, seg *stream.HLSSegment, name string) ([]string, error) {
+	urls := make([][]string, len(cxn.groups))
+	errs := make([]error, len(cxn.groups))
+	var wg sync.WaitGroup
+	for i, g := range cxn.groups {
+		wg.Add(1)
+		go func(i int, g *rtmpConnection) {
+			defer wg.Done()
+			var sv *verification.SegmentVerifier
+			if Policy != nil {
+				sv = verification.NewSegmentVerifier(Policy)
+			}
+			gctx := clog.AddVal(ctx, "outputGroup", g.group)
+			urls[i], errs[i] = transcodeSegmentWithRetries(gctx, g, seg, name, sv)
+		}(i, g)
+	}
+	wg.Wait()
+
+	var (
+		all       []string
+		succeeded int
+		firstErr  error
+	)
+	for i, g := range cxn.groups {
+		err := errs[i]
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("output group name=%s: %w", g.group, err)
+		}
+		if err == nil && len(urls[i]) == 0 {
+			err = errNoOrchs
+		}
+		if err != nil {
+			clog.Errorf(ctx, "Error transcoding output group name=%s seqNo=%d err=%q", g.group, seg.SeqNo, err)
+			// Keep the URLs of the other groups lined up with the profiles of the stream
+			all = append(all, make([]string, len(g.params.Profiles))...)
+			continue
+		}
+		succeeded++
+		all = append(all, urls[i]...)
+	}
+	if succeeded == 0 {
+		// No renditions and no error if there were no sessions available for any group
+		return nil, firstErr
+	}
+	return all, nil
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/drivers"
+	"github.com/livepeer/go-livepeer/net"
+	"github.com/livepeer/go-livepeer/policy"
+	"github.com/livepeer/lpms/ffmpeg"
+	"github.com/livepeer/lpms/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputGroups(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	groups, err := parseOutputGroups([]authOutputGroup{
+		{Name: "premium", Presets: []string{"P720p30fps16x9"}, MaxPrice: &policy.Price{PricePerUnit: 10, PixelsPerUnit: 1}},
+		{Name: "mobile", Profiles: []ffmpeg.JsonProfile{{Name: "mobile240", Width: 426, Height: 240, Bitrate: 300000, FPS: 30}}},
+	})
+	require.Nil(err)
+	require.Len(groups, 2)
+	assert.Equal("premium", groups[0].Name)
+	assert.Equal([]ffmpeg.VideoProfile{ffmpeg.P720p30fps16x9}, groups[0].Profiles)
+	assert.Equal(big.NewRat(10, 1), groups[0].MaxPrice)
+	assert.Equal("mobile", groups[1].Name)
+	require.Len(groups[1].Profiles, 1)
+	assert.Equal("mobile240", groups[1].Profiles[0].Name)
+	assert.Nil(groups[1].MaxPrice)
+	profiles := outputGroupsProfiles(groups)
+	require.Len(profiles, 2)
+	assert.Equal("P720p30fps16x9", profiles[0].Name)
+	assert.Equal("mobile240", profiles[1].Name)
+
+	_, err = parseOutputGroups([]authOutputGroup{{Presets: []string{"P720p30fps16x9"}}})
+	assert.EqualError(err, "missing output group name")
+
+	_, err = parseOutputGroups([]authOutputGroup{{Name: "a", Presets: []string{"P720p30fps16x9"}}, {Name: "a", Presets: []string{"P360p30fps16x9"}}})
+	assert.EqualError(err, "duplicate output group name=a")
+
+	_, err = parseOutputGroups([]authOutputGroup{{Name: "a"}})
+	assert.EqualError(err, "output group without profiles name=a")
+
+	// Renditions of all groups end up in the same playlists
+	_, err = parseOutputGroups([]authOutputGroup{{Name: "a", Presets: []string{"P720p30fps16x9"}}, {Name: "b", Presets: []string{"P720p30fps16x9"}}})
+	assert.EqualError(err, "duplicate rendition name=P720p30fps16x9 in output group name=b")
+
+	_, err = parseOutputGroups([]authOutputGroup{{Name: "a", Presets: []string{"P720p30fps16x9"}, MaxPrice: &policy.Price{PricePerUnit: 1}}})
+	assert.EqualError(err, "invalid max price of output group name=a: invalid price")
+
+	_, err = parseOutputGroups([]authOutputGroup{{Name: "a", Presets: []string{"unknown"}}})
+	assert.ErrorIs(err, errPreset)
+}
+
+func TestNewOutputGroups(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	groups := []core.OutputGroup{
+		{Name: "premium", Profiles: []ffmpeg.VideoProfile{ffmpeg.P720p30fps16x9, ffmpeg.P360p30fps16x9}, MaxPrice: big.NewRat(10, 1)},
+		{Name: "mobile", Profiles: []ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9}},
+	}
+	params := &core.StreamParameters{
+		ManifestID:   "simulcast",
+		Profiles:     outputGroupsProfiles(groups),
+		OutputGroups: groups,
+		OS:           drivers.NewMemoryDriver(nil).NewSession("simulcast"),
+		Detection:    core.DetectionConfig{Freq: 1},
+	}
+	// Output formats set on the profiles of the stream carry over to the groups
+	for i := range params.Profiles {
+		params.Profiles[i].Format = ffmpeg.FormatMP4
+	}
+	cxn := &rtmpConnection{mid: params.ManifestID, params: params}
+	node, _ := core.NewLivepeerNode(nil, "", nil)
+
+	subs, err := newOutputGroups(context.Background(), node, cxn, nil)
+	require.Nil(err)
+	require.Len(subs, 2)
+	defer func() {
+		cxn.sessManager = subs[0].sessManager
+		cxn.groups = subs
+		cxn.cleanupSessions()
+	}()
+
+	assert.Equal("premium", subs[0].group)
+	assert.Equal(cxn, subs[0].parent)
+	require.Len(subs[0].params.Profiles, 2)
+	assert.Equal("P720p30fps16x9", subs[0].params.Profiles[0].Name)
+	assert.Equal("P360p30fps16x9", subs[0].params.Profiles[1].Name)
+	assert.Equal(ffmpeg.FormatMP4, subs[0].params.Profiles[0].Format)
+	assert.Equal(big.NewRat(10, 1), subs[0].params.MaxPrice)
+	assert.Equal(uint(1), subs[0].params.Detection.Freq)
+	assert.NotNil(subs[0].params.Capabilities)
+	assert.Nil(subs[0].params.OutputGroups)
+
+	assert.Equal("mobile", subs[1].group)
+	require.Len(subs[1].params.Profiles, 1)
+	assert.Equal("P144p30fps16x9", subs[1].params.Profiles[0].Name)
+	assert.Equal(ffmpeg.FormatMP4, subs[1].params.Profiles[0].Format)
+	assert.Nil(subs[1].params.MaxPrice)
+	// Content detection only runs with the first group
+	assert.Zero(subs[1].params.Detection.Freq)
+	assert.NotEqual(subs[0].sessManager, subs[1].sessManager)
+}
+
+func TestProcessSegment_OutputGroups(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The groups upload concurrently, so each uses a stub OS session of its own
+	bcastOS := &stubOSSession{host: "test://broad.com"}
+	premium := genBcastSess(ctx, t, "premium.ts", &stubOSSession{host: "test://broad.com"}, "")
+	premium.Params.Profiles = []ffmpeg.VideoProfile{ffmpeg.P720p30fps16x9}
+	mobile := genBcastSess(ctx, t, "mobile.ts", &stubOSSession{host: "test://broad.com"}, "")
+
+	oldDownloadSeg := downloadSeg
+	defer func() { downloadSeg = oldDownloadSeg }()
+	downloadSeg = func(ctx context.Context, url string) ([]byte, error) { return []byte(url), nil }
+
+	sourceProfile := ffmpeg.P240p30fps16x9
+	pl := &stubPlaylistManager{os: bcastOS}
+	cxn := &rtmpConnection{
+		params:  &core.StreamParameters{Profiles: []ffmpeg.VideoProfile{ffmpeg.P720p30fps16x9, ffmpeg.P144p30fps16x9}},
+		pl:      pl,
+		profile: &sourceProfile,
+	}
+	newGroup := func(name string, sess *BroadcastSession) *rtmpConnection {
+		return &rtmpConnection{pl: pl, profile: &sourceProfile, params: sess.Params,
+			sessManager: bsmWithSessList([]*BroadcastSession{sess}), parent: cxn, group: name}
+	}
+	cxn.groups = []*rtmpConnection{newGroup("premium", premium), newGroup("mobile", mobile)}
+	cxn.sessManager = cxn.groups[0].sessManager
+
+	// The renditions of the groups are returned in the order of the groups
+	urls, err := processSegment(ctx, cxn, &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 1})
+	require.Nil(err)
+	require.Len(urls, 2)
+	assert.Contains(urls[0], "P720p30fps16x9/1")
+	assert.Contains(urls[1], "P144p30fps16x9/1")
+
+	// A group without orchestrators doesn't fail the renditions of the other groups
+	cxn.groups[1].sessManager = bsmWithSessList(nil)
+	urls, err = processSegment(ctx, cxn, &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 2})
+	require.Nil(err)
+	require.Len(urls, 2)
+	assert.Contains(urls[0], "P720p30fps16x9/2")
+	assert.Empty(urls[1])
+
+	// A failed group doesn't fail the renditions of the other groups
+	failing := func() *BroadcastSession {
+		sess := genBcastSess(ctx, t, "failed.ts", &stubOSSession{host: "test://broad.com"}, "")
+		buf, err := proto.Marshal(&net.TranscodeResult{Result: &net.TranscodeResult_Error{Error: "TranscoderBusy"}})
+		require.Nil(err)
+		sess.OrchestratorInfo.Transcoder = stubTestTranscoder(ctx, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write(buf)
+		})
+		return sess
+	}
+	cxn.groups[1].sessManager = bsmWithSessList([]*BroadcastSession{failing()})
+	urls, err = processSegment(ctx, cxn, &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 3})
+	require.Nil(err)
+	require.Len(urls, 2)
+	assert.Contains(urls[0], "P720p30fps16x9/3")
+	assert.Empty(urls[1])
+
+	// No renditions if all groups failed, as for a stream without output groups
+	cxn.groups[0].sessManager = bsmWithSessList([]*BroadcastSession{failing()})
+	cxn.groups[1].sessManager = bsmWithSessList([]*BroadcastSession{failing()})
+	urls, err = processSegment(ctx, cxn, &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 4})
+	assert.Nil(err)
+	assert.Empty(urls)
+
+	// No renditions without orchestrators for any group
+	cxn.groups[0].sessManager = bsmWithSessList(nil)
+	cxn.groups[1].sessManager = bsmWithSessList(nil)
+	urls, err = processSegment(ctx, cxn, &stream.HLSSegment{Data: []byte("dummy"), SeqNo: 5})
+	assert.Nil(err)
+	assert.Empty(urls)
+}
+
+func TestSelectOrchestrator_MaxPrice(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	node, _ := core.NewLivepeerNode(nil, "", nil)
+	node.OrchestratorPool = &stubDiscovery{infos: []*net.OrchestratorInfo{
+		{Transcoder: "cheap", PriceInfo: &net.PriceInfo{PricePerUnit: 1, PixelsPerUnit: 1}, AuthToken: stubAuthToken},
+		{Transcoder: "expensive", PriceInfo: &net.PriceInfo{PricePerUnit: 5, PixelsPerUnit: 1}, AuthToken: stubAuthToken},
+	}}
+	params := &core.StreamParameters{ManifestID: "mid", OS: drivers.NewMemoryDriver(nil).NewSession("mid"), MaxPrice: big.NewRat(2, 1)}
+
+	sessions, err := selectOrchestrator(context.TODO(), node, params, 2, newSuspender(), common.ScoreAtLeast(0))
+	require.Nil(err)
+	require.Len(sessions, 1)
+	assert.Equal("cheap", sessions[0].OrchestratorInfo.Transcoder)
+
+	params.MaxPrice = nil
+	sessions, err = selectOrchestrator(context.TODO(), node, params, 2, newSuspender(), common.ScoreAtLeast(0))
+	require.Nil(err)
+	assert.Len(sessions, 2)
+}
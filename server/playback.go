@@ -0,0 +1,239 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/core"
+)
+
+// PlaylistCacheControl is the Cache-Control header of the playlists served on the playback endpoints. The default
+// header is kept if empty
+var PlaylistCacheControl string
+
+// SegmentCacheControl is the Cache-Control header of the segments served on the playback endpoints. The default header
+// is kept if empty
+var SegmentCacheControl string
+
+// PlaybackCORSOrigins are the origins that browsers may play streams back from. All origins are allowed if empty
+var PlaybackCORSOrigins []string
+
+// PlaybackOriginAuthHeader and PlaybackOriginAuthValue are the header that playback requests must carry, i.e. a secret
+// header that the CDN adds to its requests to the origin, so that viewers cannot bypass the CDN. Not checked if the
+// header is empty
+var PlaybackOriginAuthHeader, PlaybackOriginAuthValue string
+
+// PlaybackTokenSecret is the secret that playback tokens are signed with. If set, playback requests must present a
+// token issued for the stream in the token query parameter
+var PlaybackTokenSecret []byte
+
+const playbackTokenParam = "token"
+
+// IssuePlaybackToken returns a token for playing back the stream mid signed with secret. The token does not expire if
+// expiration is zero
+func IssuePlaybackToken(secret []byte, mid core.ManifestID, expiration time.Time) string {
+	var exp int64
+	if !expiration.IsZero() {
+		exp = expiration.Unix()
+	}
+	return fmt.Sprintf("%d.%s", exp, hex.EncodeToString(playbackTokenMAC(secret, mid, exp)))
+}
+
+func playbackTokenMAC(secret []byte, mid core.ManifestID, exp int64) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(fmt.Sprintf("%s.%d", mid, exp)))
+	return mac.Sum(nil)
+}
+
+// verifyPlaybackToken returns an error if token was not issued for mid with secret or is expired
+func verifyPlaybackToken(secret []byte, mid core.ManifestID, token string, now time.Time) error {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return errors.New("malformed playback token")
+	}
+	exp, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return errors.New("malformed playback token")
+	}
+	sig, err := hex.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, playbackTokenMAC(secret, mid, exp)) {
+		return errors.New("invalid playback token")
+	}
+	if exp != 0 && now.Unix() > exp {
+		return errors.New("expired playback token")
+	}
+	return nil
+}
+
+// playbackManifestID returns the manifest ID of the stream that is played back with reqPath, and false if reqPath is
+// not a playback path
+func playbackManifestID(reqPath string) (core.ManifestID, bool) {
+	var p string
+	switch {
+	case strings.HasPrefix(reqPath, "/stream/"):
+		p = strings.TrimPrefix(reqPath, "/stream/")
+	case strings.HasPrefix(reqPath, "/recordings/"):
+		p = strings.TrimPrefix(reqPath, "/recordings/")
+	default:
+		return "", false
+	}
+	parts := strings.SplitN(p, "/", 2)
+	if len(parts) == 1 {
+		// Master playlist i.e. /stream/<manifestID>.m3u8
+		return core.ManifestID(strings.TrimSuffix(parts[0], path.Ext(parts[0]))), true
+	}
+	return core.ManifestID(parts[0]), true
+}
+
+// playbackHandler serves the playback endpoints of next with the configured origin authentication, playback tokens,
+// CORS and Cache-Control headers so that the node can be the origin of a CDN
+func playbackHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mid, ok := playbackManifestID(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if PlaybackOriginAuthHeader != "" &&
+			subtle.ConstantTimeCompare([]byte(r.Header.Get(PlaybackOriginAuthHeader)), []byte(PlaybackOriginAuthValue)) != 1 {
+			glog.Errorf("Playback request without origin auth header url=%s", r.URL.Path)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		if r.Method == http.MethodOptions {
+			setPlaybackCORSHeaders(w.Header(), r)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		token := r.URL.Query().Get(playbackTokenParam)
+		if len(PlaybackTokenSecret) > 0 {
+			if err := verifyPlaybackToken(PlaybackTokenSecret, mid, token, time.Now()); err != nil {
+				glog.Errorf("Playback request not authorized manifestID=%s err=%q", mid, err)
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+		} else {
+			token = ""
+		}
+
+		pw := &playbackResponseWriter{ResponseWriter: w, r: r, playlist: path.Ext(r.URL.Path) == ".m3u8", token: token}
+		next.ServeHTTP(pw, r)
+		pw.flush()
+	})
+}
+
+func setPlaybackCORSHeaders(h http.Header, r *http.Request) {
+	if len(PlaybackCORSOrigins) == 0 {
+		h.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	origin := r.Header.Get("Origin")
+	h.Add("Vary", "Origin")
+	for _, o := range PlaybackCORSOrigins {
+		if o == origin {
+			h.Set("Access-Control-Allow-Origin", origin)
+			return
+		}
+	}
+	h.Del("Access-Control-Allow-Origin")
+}
+
+// playbackResponseWriter overrides the headers set by the playback handlers once they write the response. Playlists
+// are buffered if a token is presented, so that the token can be added to the URIs of the renditions and segments
+type playbackResponseWriter struct {
+	http.ResponseWriter
+	r           *http.Request
+	playlist    bool
+	token       string
+	status      int
+	wroteHeader bool
+	buf         bytes.Buffer
+}
+
+func (pw *playbackResponseWriter) buffered() bool {
+	return pw.playlist && pw.token != ""
+}
+
+func (pw *playbackResponseWriter) WriteHeader(status int) {
+	if pw.wroteHeader {
+		return
+	}
+	pw.wroteHeader = true
+	pw.status = status
+
+	h := pw.Header()
+	setPlaybackCORSHeaders(h, pw.r)
+	// Errors are not cached for long, segments that do not exist yet are requested again
+	if status == http.StatusOK {
+		cc := SegmentCacheControl
+		if pw.playlist {
+			cc = PlaylistCacheControl
+		}
+		if cc != "" {
+			h.Set("Cache-Control", cc)
+		}
+	}
+	if !pw.buffered() {
+		pw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (pw *playbackResponseWriter) Write(b []byte) (int, error) {
+	if !pw.wroteHeader {
+		pw.WriteHeader(http.StatusOK)
+	}
+	if pw.buffered() {
+		return pw.buf.Write(b)
+	}
+	return pw.ResponseWriter.Write(b)
+}
+
+// flush writes the buffered playlist with the token added to its URIs
+func (pw *playbackResponseWriter) flush() {
+	if !pw.buffered() || !pw.wroteHeader {
+		return
+	}
+	body := pw.buf.Bytes()
+	if pw.status == http.StatusOK {
+		body = addPlaylistToken(body, pw.token)
+	}
+	pw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	pw.ResponseWriter.WriteHeader(pw.status)
+	pw.ResponseWriter.Write(body)
+}
+
+// addPlaylistToken adds token to the query of the URI lines of the HLS playlist pl
+func addPlaylistToken(pl []byte, token string) []byte {
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(pl))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" && !strings.HasPrefix(line, "#") {
+			sep := "?"
+			if strings.Contains(line, "?") {
+				sep = "&"
+			}
+			line += sep + playbackTokenParam + "=" + url.QueryEscape(token)
+		}
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubPlaybackMux serves playlists and segments with the headers of the LPMS playback handlers
+func stubPlaybackMux() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stream/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Cache-Control", "max-age=5")
+		if strings.HasSuffix(r.URL.Path, "missing.ts") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if path.Ext(r.URL.Path) == ".m3u8" {
+			w.Write([]byte("#EXTM3U\n#EXTINF:2.000,\nmovie/source/0.ts\n#EXTINF:2.000,\nmovie/source/1.ts?foo=bar\n"))
+			return
+		}
+		w.Write([]byte("segment"))
+	})
+	mux.HandleFunc("/live/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+func servePlayback(method, reqPath string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, reqPath, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	playbackHandler(stubPlaybackMux()).ServeHTTP(w, req)
+	return w
+}
+
+func TestPlaybackToken(t *testing.T) {
+	assert := assert.New(t)
+
+	secret := []byte("secret")
+	mid := core.ManifestID("movie")
+	now := time.Now()
+
+	token := IssuePlaybackToken(secret, mid, time.Time{})
+	assert.Nil(verifyPlaybackToken(secret, mid, token, now))
+	assert.EqualError(verifyPlaybackToken(secret, "other", token, now), "invalid playback token")
+
+	token = IssuePlaybackToken(secret, mid, now.Add(time.Hour))
+	assert.Nil(verifyPlaybackToken(secret, mid, token, now))
+	assert.EqualError(verifyPlaybackToken(secret, mid, token, now.Add(2*time.Hour)), "expired playback token")
+	assert.EqualError(verifyPlaybackToken(secret, mid, "", now), "malformed playback token")
+}
+
+func TestPlaybackManifestID(t *testing.T) {
+	assert := assert.New(t)
+
+	for reqPath, expected := range map[string]core.ManifestID{
+		"/stream/movie.m3u8":              "movie",
+		"/stream/movie/source.m3u8":       "movie",
+		"/stream/movie/source/0.ts":       "movie",
+		"/recordings/movie/index.m3u8":    "movie",
+		"/recordings/movie/source/0.ts":   "movie",
+		"/recordings/movie/source.mp4":    "movie",
+		"/stream/movie_with.dots/0.ts":    "movie_with.dots",
+		"/stream/current.m3u8":            "current",
+		"/recordings/movie/source/0.m3u8": "movie",
+	} {
+		mid, ok := playbackManifestID(reqPath)
+		assert.True(ok, reqPath)
+		assert.Equal(expected, mid, reqPath)
+	}
+	_, ok := playbackManifestID("/live/movie/0.ts")
+	assert.False(ok)
+}
+
+func TestPlaybackHandler_Headers(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func() { PlaylistCacheControl, SegmentCacheControl, PlaybackCORSOrigins = "", "", nil }()
+
+	// The default headers are kept
+	w := servePlayback("GET", "/stream/movie.m3u8", nil)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("max-age=5", w.Header().Get("Cache-Control"))
+	assert.Equal("*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	PlaylistCacheControl = "max-age=1"
+	SegmentCacheControl = "public, max-age=3600"
+	PlaybackCORSOrigins = []string{"https://player.example.com"}
+	origin := http.Header{"Origin": []string{"https://player.example.com"}}
+
+	w = servePlayback("GET", "/stream/movie.m3u8", origin)
+	assert.Equal("max-age=1", w.Header().Get("Cache-Control"))
+	assert.Equal("https://player.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal("Origin", w.Header().Get("Vary"))
+
+	w = servePlayback("GET", "/stream/movie/source/0.ts", origin)
+	assert.Equal("public, max-age=3600", w.Header().Get("Cache-Control"))
+	assert.Equal("segment", w.Body.String())
+
+	// Segments that do not exist yet are not cached for long
+	w = servePlayback("GET", "/stream/movie/source/missing.ts", origin)
+	assert.Equal(http.StatusNotFound, w.Code)
+	assert.Equal("max-age=5", w.Header().Get("Cache-Control"))
+
+	// Other origins are not allowed
+	w = servePlayback("GET", "/stream/movie.m3u8", http.Header{"Origin": []string{"https://other.example.com"}})
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Preflight requests
+	w = servePlayback("OPTIONS", "/stream/movie.m3u8", origin)
+	assert.Equal(http.StatusNoContent, w.Code)
+	assert.Equal("https://player.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Other paths are not changed
+	w = servePlayback("PUT", "/live/movie/0.ts", origin)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestPlaybackHandler_OriginAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	PlaybackOriginAuthHeader, PlaybackOriginAuthValue = "X-Origin-Auth", "secret"
+	defer func() { PlaybackOriginAuthHeader, PlaybackOriginAuthValue = "", "" }()
+
+	w := servePlayback("GET", "/stream/movie.m3u8", nil)
+	assert.Equal(http.StatusForbidden, w.Code)
+	w = servePlayback("GET", "/stream/movie.m3u8", http.Header{"X-Origin-Auth": []string{"wrong"}})
+	assert.Equal(http.StatusForbidden, w.Code)
+	w = servePlayback("GET", "/stream/movie.m3u8", http.Header{"X-Origin-Auth": []string{"secret"}})
+	assert.Equal(http.StatusOK, w.Code)
+
+	// Other paths do not need the header
+	w = servePlayback("PUT", "/live/movie/0.ts", nil)
+	assert.Equal(http.StatusOK, w.Code)
+}
+
+func TestPlaybackHandler_Token(t *testing.T) {
+	assert := assert.New(t)
+
+	PlaybackTokenSecret = []byte("secret")
+	defer func() { PlaybackTokenSecret = nil }()
+
+	w := servePlayback("GET", "/stream/movie.m3u8", nil)
+	assert.Equal(http.StatusForbidden, w.Code)
+	token := IssuePlaybackToken(PlaybackTokenSecret, "other", time.Time{})
+	w = servePlayback("GET", "/stream/movie.m3u8?token="+token, nil)
+	assert.Equal(http.StatusForbidden, w.Code)
+
+	// The token is added to the URIs of the playlist so that players present it for the segments
+	token = IssuePlaybackToken(PlaybackTokenSecret, "movie", time.Now().Add(time.Hour))
+	w = servePlayback("GET", "/stream/movie.m3u8?token="+token, nil)
+	assert.Equal(http.StatusOK, w.Code)
+	expected := "#EXTM3U\n#EXTINF:2.000,\nmovie/source/0.ts?token=" + token + "\n#EXTINF:2.000,\nmovie/source/1.ts?foo=bar&token=" + token + "\n"
+	assert.Equal(expected, w.Body.String())
+	assert.Equal(len(expected), int(w.Result().ContentLength))
+
+	w = servePlayback("GET", "/stream/movie/source/0.ts?token="+token, nil)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("segment", w.Body.String())
+}
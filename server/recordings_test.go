@@ -6,7 +6,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/livepeer/go-livepeer/core"
@@ -14,6 +16,7 @@ import (
 	lpmon "github.com/livepeer/go-livepeer/monitor"
 	"github.com/livepeer/lpms/ffmpeg"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRecordingHandler(t *testing.T) {
@@ -187,3 +190,74 @@ func TestRecording(t *testing.T) {
 	assert.NotNil(err)
 	assert.Nil(fir)
 }
+
+func TestRecording_EncryptedAuthorization(t *testing.T) {
+	drivers.Testing = true
+	lpmon.NodeID = "testNode"
+	assert := assert.New(t)
+	require := require.New(t)
+	s, cancel := setupServerWithCancel()
+	defer serverCleanup(s)
+	defer cancel()
+
+	kms, err := drivers.NewLocalKMS(make([]byte, 32))
+	require.Nil(err)
+	oldEncryption := drivers.RecordEncryption
+	defer func() { drivers.RecordEncryption = oldEncryption }()
+	drivers.RecordEncryption = kms
+
+	whts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out, _ := ioutil.ReadAll(r.Body)
+		var req authWebhookReq
+		if err := json.Unmarshal(out, &req); err != nil || !strings.Contains(req.URL, "jwt=viewer") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"manifestID":"enctest01", "recordObjectStore": "memory://recstore6"}`))
+	}))
+	defer whts.Close()
+	oldURL := AuthWebhookURL
+	defer func() { AuthWebhookURL = oldURL }()
+
+	makeReq := func(uri string) (int, string) {
+		writer := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", uri, nil)
+		s.HandleRecordings(writer, req)
+		resp := writer.Result()
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp.StatusCode, string(body)
+	}
+
+	// Not decrypted without any authorization configured
+	AuthWebhookURL = nil
+	code, _ := makeReq("/live/enc1/testNode/source/1.ts?jwt=viewer")
+	assert.Equal(http.StatusForbidden, code)
+
+	AuthWebhookURL = mustParseUrl(t, whts.URL)
+	os, err := drivers.ParseOSURL("memory://recstore6", true)
+	require.Nil(err)
+	msess := drivers.NewEncryptedSession(os.NewSession("enc1"), kms)
+	_, err = msess.SaveData(context.TODO(), "testNode/source/1.ts", []byte("segmentdata"), nil, 0)
+	require.Nil(err)
+
+	// Decrypted for requests authorized by the webhook
+	code, body := makeReq("/live/enc1/testNode/source/1.ts?jwt=viewer")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("segmentdata", body)
+
+	// The authorization of another request is not reused
+	code, _ = makeReq("/live/enc1/testNode/source/1.ts")
+	assert.Equal(http.StatusForbidden, code)
+
+	// Playback tokens are required if configured
+	PlaybackTokenSecret = []byte("secret")
+	defer func() { PlaybackTokenSecret = nil }()
+	code, _ = makeReq("/live/enc1/testNode/source/1.ts?jwt=viewer")
+	assert.Equal(http.StatusForbidden, code)
+
+	token := IssuePlaybackToken(PlaybackTokenSecret, "enc1", time.Now().Add(time.Hour))
+	code, body = makeReq("/live/enc1/testNode/source/1.ts?jwt=viewer&token=" + token)
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("segmentdata", body)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"io"
@@ -13,8 +14,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/golang/glog"
 	"github.com/golang/mock/gomock"
@@ -132,6 +135,83 @@ func TestRedeemerServer_QueueTicket(t *testing.T) {
 	assert.EqualError(err, fmt.Sprintf("rpc error: code = PermissionDenied desc = invalid ticket recipient 0x%x, expected %v", ticket.TicketParams.Recipient, r.recipient.Hex()))
 }
 
+func TestRedeemerServer_QueueTicket_SimulatedClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	// The broadcaster funds its deposit and reserve on the simulated chain that the orchestrator redeems on
+	key, err := ethcrypto.GenerateKey()
+	require.Nil(err)
+	signer := &stubKeySigner{key: key}
+	bcast := signer.Account().Address
+	orch := pm.RandAddress()
+	client := eth.NewSimulatedClient(orch)
+	bcastClient, err := client.WithAccount(bcast)
+	require.Nil(err)
+	_, err = bcastClient.FundDepositAndReserve(ctx, big.NewInt(1000000), big.NewInt(1000000))
+	require.Nil(err)
+
+	tm := &stubTimeManager{round: big.NewInt(1), lastSeenBlock: big.NewInt(100), transcoderPoolSize: big.NewInt(1)}
+	sm := newStubSenderMonitor()
+	sm.maxFloat = big.NewInt(1000000)
+	val := pm.NewValidator(&pm.DefaultSigVerifier{}, tm)
+	recipient, err := pm.NewRecipient(orch, client, val, &stubGasPriceMonitor{}, sm, tm, pm.TicketParamsConfig{EV: big.NewInt(1000), RedeemGas: 100000, TxCostMultiplier: 100})
+	require.Nil(err)
+	params, err := recipient.TicketParams(bcast, big.NewRat(1, 1))
+	require.Nil(err)
+	sender := pm.NewSender(signer, tm, &simSenderManager{client}, big.NewRat(1000000, 1), 1)
+	sessionID := sender.StartSession(*params)
+
+	// With a win probability of 1/100 the broadcaster pays with tickets until one wins. The recipient queues the
+	// first ticket that lost as well, so that its recipientRand is revealed
+	var winner, loser *pm.SignedTicket
+	for i := 0; i < 2000 && (winner == nil || loser == nil); i++ {
+		batch, err := sender.CreateTicketBatch(sessionID, 1)
+		require.Nil(err)
+		ticket := pm.NewTicket(batch.TicketParams, batch.TicketExpirationParams, batch.Sender, batch.SenderParams[0].SenderNonce)
+		sig := batch.SenderParams[0].Sig
+		_, won, err := recipient.ReceiveTicket(ticket, sig, params.Seed)
+		require.Nil(err)
+		if won && winner != nil || !won && loser != nil {
+			continue
+		}
+		require.Nil(recipient.RedeemWinningTicket(ticket, sig, params.Seed))
+		if won {
+			winner = sm.queued[len(sm.queued)-1]
+		} else {
+			loser = sm.queued[len(sm.queued)-1]
+		}
+	}
+	require.NotNil(winner)
+	require.NotNil(loser)
+
+	// The winning ticket is queued with the redeemer of the orchestrator and redeemed on chain
+	rsm := newStubSenderMonitor()
+	r := &Redeemer{recipient: orch, eth: client, sm: rsm, quit: make(chan struct{})}
+	_, err = r.QueueTicket(ctx, protoTicket(winner))
+	require.Nil(err)
+	require.Len(rsm.queued, 1)
+	won := rsm.queued[0]
+	_, err = client.RedeemWinningTicket(ctx, won.Ticket, won.Sig, won.RecipientRand)
+	require.Nil(err)
+	d, err := client.GetDelegator(ctx, orch)
+	require.Nil(err)
+	assert.Equal(params.FaceValue, d.Fees)
+	info, err := client.GetSenderInfo(ctx, bcast)
+	require.Nil(err)
+	assert.Equal(new(big.Int).Sub(big.NewInt(1000000), params.FaceValue), info.Deposit)
+
+	// Tickets that did not win or that are tampered with are rejected
+	_, err = client.RedeemWinningTicket(ctx, loser.Ticket, loser.Sig, loser.RecipientRand)
+	assert.EqualError(err, "ticket did not win")
+	tampered := *won.Ticket
+	tampered.FaceValue = new(big.Int).Mul(won.FaceValue, big.NewInt(2))
+	tampered.SenderNonce++
+	_, err = client.RedeemWinningTicket(ctx, &tampered, won.Sig, won.RecipientRand)
+	assert.EqualError(err, "invalid ticket signature")
+}
+
 func TestRedeemerServer_MonitorMaxFloat_Success(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), GRPCConnectTimeout)
 	defer cancel()
@@ -848,6 +928,52 @@ func (s *stubSenderManager) Clear(addr ethcommon.Address) {
 	delete(s.claimedReserve, addr)
 }
 
+// stubKeySigner signs messages like the account manager of the node
+type stubKeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *stubKeySigner) Sign(msg []byte) ([]byte, error) {
+	sig, err := ethcrypto.Sign(accounts.TextHash(msg), s.key)
+	if err != nil {
+		return nil, err
+	}
+	// Convert the V param to 27 or 28
+	sig[64] += 27
+	return sig, nil
+}
+
+func (s *stubKeySigner) Account() accounts.Account {
+	return accounts.Account{Address: ethcrypto.PubkeyToAddress(s.key.PublicKey)}
+}
+
+// simSenderManager serves the sender info of a SimulatedClient without caching it
+type simSenderManager struct {
+	*eth.SimulatedClient
+}
+
+func (s *simSenderManager) GetSenderInfo(addr ethcommon.Address) (*pm.SenderInfo, error) {
+	return s.SimulatedClient.GetSenderInfo(context.Background(), addr)
+}
+
+func (s *simSenderManager) ClaimedReserve(reserveHolder ethcommon.Address, claimant ethcommon.Address) (*big.Int, error) {
+	return s.SimulatedClient.ClaimedReserve(context.Background(), reserveHolder, claimant)
+}
+
+func (s *simSenderManager) Clear(addr ethcommon.Address) {}
+
+func (s *simSenderManager) SubscribeReserveChange(sink chan<- ethcommon.Address) event.Subscription {
+	return &stubSubscription{errCh: make(chan error)}
+}
+
+type stubGasPriceMonitor struct {
+	gasPrice *big.Int
+}
+
+func (s *stubGasPriceMonitor) GasPrice() *big.Int {
+	return s.gasPrice
+}
+
 type stubTimeManager struct {
 	round              *big.Int
 	blkHash            [32]byte
@@ -862,6 +988,10 @@ func (m *stubTimeManager) LastInitializedRound() *big.Int {
 	return m.round
 }
 
+func (m *stubTimeManager) CurrentRoundStartL1Block() *big.Int {
+	return nil
+}
+
 func (m *stubTimeManager) LastInitializedL1BlockHash() [32]byte {
 	return m.blkHash
 }
@@ -1,15 +1,23 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	gonet "net"
+	"net/http"
+	"net/http/httputil"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
 	"github.com/livepeer/go-livepeer/clog"
+	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/net"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -20,16 +28,41 @@ import (
 const getOrchestratorTimeout = 2 * time.Second
 
 var errNoOrchestrators = errors.New("no orchestrators")
+var errMissingAuthToken = errors.New("missing auth token")
 
 type Router struct {
 	uris []*url.URL
 	srv  *grpc.Server
+
+	// Set if the router shards the streams across the orchestrators
+	ring       *hashRing
+	proxies    map[*url.URL]*httputil.ReverseProxy
+	serviceURI *url.URL
+	httpSrv    *http.Server
 }
 
 func NewRouter(uris []*url.URL) *Router {
 	return &Router{uris: uris}
 }
 
+// NewShardRouter creates a router for the machines of a single on-chain orchestrator, which all use the on-chain
+// address as -ethOrchAddr. The router receives the segments of the broadcasters and sends all the segments of an
+// orchestrator session to the same orchestrator, picked by consistent hashing of the session's manifest ID. The
+// orchestrators have to use the same -orchShardSecret to accept the auth tokens and tickets issued by each other
+func NewShardRouter(uris []*url.URL) *Router {
+	proxies := make(map[*url.URL]*httputil.ReverseProxy)
+	for _, uri := range uris {
+		proxy := httputil.NewSingleHostReverseProxy(uri)
+		proxy.Transport = httpClient.Transport
+		proxies[uri] = proxy
+	}
+	return &Router{
+		uris:    uris,
+		ring:    newHashRing(uris),
+		proxies: proxies,
+	}
+}
+
 func (r *Router) Start(uri *url.URL, serviceURI *url.URL, workDir string) error {
 	listener, err := gonet.Listen("tcp", uri.Host)
 	if err != nil {
@@ -42,12 +75,22 @@ func (r *Router) Start(uri *url.URL, serviceURI *url.URL, workDir string) error
 		return err
 	}
 
-	creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
-	if err != nil {
-		return err
+	var s *grpc.Server
+	var serve func() error
+	if r.ring != nil {
+		// Segments are received over HTTP on the same port as the gRPC requests
+		r.serviceURI = serviceURI
+		s = grpc.NewServer()
+		r.httpSrv = &http.Server{Handler: r.shardHandler(s)}
+		serve = func() error { return r.httpSrv.ServeTLS(listener, certFile, keyFile) }
+	} else {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		s = grpc.NewServer(grpc.Creds(creds))
+		serve = func() error { return s.Serve(listener) }
 	}
-
-	s := grpc.NewServer(grpc.Creds(creds))
 	r.srv = s
 
 	net.RegisterOrchestratorServer(s, r)
@@ -55,13 +98,13 @@ func (r *Router) Start(uri *url.URL, serviceURI *url.URL, workDir string) error
 
 	errCh := make(chan error)
 	go func() {
-		errCh <- s.Serve(listener)
+		errCh <- serve()
 	}()
 
 	time.Sleep(1 * time.Second)
 
 	if err := checkAvailability(context.Background(), serviceURI); err != nil {
-		s.Stop()
+		r.Stop()
 		return err
 	}
 
@@ -71,11 +114,84 @@ func (r *Router) Start(uri *url.URL, serviceURI *url.URL, workDir string) error
 }
 
 func (r *Router) Stop() {
+	if r.httpSrv != nil {
+		r.httpSrv.Close()
+	}
 	r.srv.Stop()
 }
 
 func (r *Router) GetOrchestrator(ctx context.Context, req *net.OrchestratorRequest) (*net.OrchestratorInfo, error) {
-	return getOrchestratorInfo(ctx, r.uris, req)
+	info, err := getOrchestratorInfo(ctx, r.uris, req)
+	if err != nil || r.ring == nil {
+		return info, err
+	}
+	// Segments are sent to the router to be sharded
+	info.Transcoder = r.serviceURI.String()
+	return info, nil
+}
+
+func (r *Router) shardHandler(s *grpc.Server) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/segment", r.proxySegment)
+	mux.HandleFunc("/keepalive", r.proxyKeepalive)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ct := req.Header.Get("Content-Type")
+		if req.ProtoMajor == 2 && strings.HasPrefix(ct, "application/grpc") {
+			s.ServeHTTP(w, req)
+		} else {
+			mux.ServeHTTP(w, req)
+		}
+	})
+}
+
+// proxySegment sends a segment to the orchestrator of its session
+func (r *Router) proxySegment(w http.ResponseWriter, req *http.Request) {
+	buf, err := base64.StdEncoding.DecodeString(req.Header.Get(segmentHeader))
+	if err != nil {
+		http.Error(w, errSegEncoding.Error(), http.StatusBadRequest)
+		return
+	}
+	var segData net.SegData
+	if err := proto.Unmarshal(buf, &segData); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.proxy(w, req, segData.AuthToken)
+}
+
+// proxyKeepalive sends a keepalive to the orchestrator of its session
+func (r *Router) proxyKeepalive(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxKeepaliveSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var authToken net.AuthToken
+	if err := proto.Unmarshal(body, &authToken); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	r.proxy(w, req, &authToken)
+}
+
+func (r *Router) proxy(w http.ResponseWriter, req *http.Request, authToken *net.AuthToken) {
+	if authToken == nil || authToken.SessionId == "" {
+		http.Error(w, errMissingAuthToken.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The session ID is the manifest ID of the stream on the orchestrator
+	uri := r.ring.get(authToken.SessionId)
+	if uri == nil {
+		http.Error(w, errNoOrchestrators.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	glog.V(common.DEBUG).Infof("Forwarding %v manifestID=%v orch=%v", req.URL.Path, authToken.SessionId, uri)
+	r.proxies[uri].ServeHTTP(w, req)
 }
 
 func (r *Router) Ping(ctx context.Context, req *net.PingPong) (*net.PingPong, error) {
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/livepeer/go-livepeer/net"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestShardRouter_Proxy(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// Orchestrators record the paths and bodies of the requests they receive
+	received := make(map[string][]string)
+	var uris []*url.URL
+	for i := 0; i < 3; i++ {
+		var ts *httptest.Server
+		ts = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			received[ts.URL] = append(received[ts.URL], r.URL.Path+" "+string(body))
+		}))
+		defer ts.Close()
+		uri, err := url.Parse(ts.URL)
+		require.Nil(err)
+		uris = append(uris, uri)
+	}
+
+	router := NewShardRouter(uris)
+	handler := router.shardHandler(grpc.NewServer())
+
+	segCreds := func(sessionID string) string {
+		segData := &net.SegData{ManifestId: []byte("manifest")}
+		if sessionID != "" {
+			segData.AuthToken = &net.AuthToken{SessionId: sessionID}
+		}
+		buf, err := proto.Marshal(segData)
+		require.Nil(err)
+		return base64.StdEncoding.EncodeToString(buf)
+	}
+
+	// Segments and keepalives of a session are sent to the orchestrator of the session
+	for _, sessionID := range []string{"foo", "bar", "baz"} {
+		req := httptest.NewRequest("POST", "/segment", strings.NewReader("segment"))
+		req.Header.Set(segmentHeader, segCreds(sessionID))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(http.StatusOK, w.Code)
+
+		keepalive, err := proto.Marshal(&net.AuthToken{SessionId: sessionID})
+		require.Nil(err)
+		req = httptest.NewRequest("POST", "/keepalive", strings.NewReader(string(keepalive)))
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(http.StatusOK, w.Code)
+
+		orch := router.ring.get(sessionID).String()
+		require.True(len(received[orch]) >= 2)
+		reqs := received[orch][len(received[orch])-2:]
+		assert.Equal("/segment segment", reqs[0])
+		assert.Equal("/keepalive "+string(keepalive), reqs[1])
+	}
+
+	// Invalid segment credentials
+	req := httptest.NewRequest("POST", "/segment", nil)
+	req.Header.Set(segmentHeader, "not base64")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(http.StatusBadRequest, w.Code)
+
+	// Missing auth token
+	req = httptest.NewRequest("POST", "/segment", nil)
+	req.Header.Set(segmentHeader, segCreds(""))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(http.StatusBadRequest, w.Code)
+	assert.Contains(w.Body.String(), errMissingAuthToken.Error())
+
+	req = httptest.NewRequest("POST", "/keepalive", strings.NewReader("not a proto"))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(http.StatusBadRequest, w.Code)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/livepeer/go-livepeer/clog"
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/core"
+	lpcrypto "github.com/livepeer/go-livepeer/crypto"
 	"github.com/livepeer/go-livepeer/drivers"
 	"github.com/livepeer/go-livepeer/net"
 	"github.com/livepeer/go-livepeer/pm"
@@ -31,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 )
 
@@ -38,6 +40,15 @@ const GRPCConnectTimeout = 3 * time.Second
 const GRPCTimeout = 8 * time.Second
 
 var authTokenValidPeriod = 30 * time.Minute
+
+// orchestratorInfoMaxAge is how long broadcasters accept the info signed by an orchestrator, which also bounds the
+// clock drift between them
+var orchestratorInfoMaxAge = 5 * time.Minute
+
+// RequireOrchestratorInfoSig makes broadcasters that are connected to Ethereum reject orchestrator info that is not
+// signed, or that is not signed by the address that the orchestrator is registered with. It is disabled while
+// orchestrators upgrade to signing their info, broadcasters then only warn about unsigned info
+var RequireOrchestratorInfoSig = false
 var discoveryAuthWebhookCacheCleanup = 5 * time.Minute
 
 var discoveryAuthWebhookCache = cache.New(authTokenValidPeriod, discoveryAuthWebhookCacheCleanup)
@@ -49,6 +60,9 @@ type Orchestrator interface {
 	Sign([]byte) ([]byte, error)
 	VerifySig(ethcommon.Address, string, []byte) bool
 	CheckCapacity(core.ManifestID) error
+	KeepAlive(core.ManifestID) error
+	CheckStreamLimits(mid core.ManifestID, sender ethcommon.Address, ip string) error
+	CheckMaintenance(mid core.ManifestID) error
 	TranscodeSeg(context.Context, *core.SegTranscodingMetadata, *stream.HLSSegment) (*core.TranscodeResult, error)
 	ServeTranscoder(stream net.Transcoder_RegisterTranscoderServer, capacity int, capabilities *net.Capabilities)
 	TranscoderResults(job int64, res *core.RemoteTranscoderResult)
@@ -57,6 +71,8 @@ type Orchestrator interface {
 	PriceInfo(sender ethcommon.Address) (*net.PriceInfo, error)
 	SufficientBalance(addr ethcommon.Address, manifestID core.ManifestID) bool
 	DebitFees(addr ethcommon.Address, manifestID core.ManifestID, price *net.PriceInfo, pixels int64)
+	DetectionPrice() *big.Int
+	DebitDetectionFees(addr ethcommon.Address, manifestID core.ManifestID, duration time.Duration)
 	Capabilities() *net.Capabilities
 	AuthToken(sessionID string, expiration int64) *net.AuthToken
 }
@@ -139,6 +155,24 @@ func (bs *BroadcastSession) Clone() *BroadcastSession {
 	return &newSess
 }
 
+// NewOffchainBroadcastSession returns a session that submits the segments of a stream with params to the
+// orchestrator described by info without payments, i.e. to drive offchain orchestrators from tools
+func NewOffchainBroadcastSession(bcast common.Broadcaster, params *core.StreamParameters, info *net.OrchestratorInfo) *BroadcastSession {
+	var orchOS drivers.OSSession
+	if len(info.Storage) > 0 {
+		orchOS = drivers.NewSession(info.Storage[0])
+	}
+	return &BroadcastSession{
+		Broadcaster:       bcast,
+		Params:            params,
+		OrchestratorInfo:  info,
+		OrchestratorOS:    orchOS,
+		BroadcasterOS:     params.OS,
+		OrchestratorScore: common.Score_Trusted,
+		lock:              &sync.RWMutex{},
+	}
+}
+
 func (bs *BroadcastSession) IsTrusted() bool {
 	return bs.OrchestratorScore == common.Score_Trusted
 }
@@ -167,7 +201,7 @@ func (h *lphttp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *lphttp) GetOrchestrator(context context.Context, req *net.OrchestratorRequest) (*net.OrchestratorInfo, error) {
-	return getOrchestrator(h.orchestrator, req)
+	return getOrchestrator(context, h.orchestrator, req)
 }
 
 func (h *lphttp) Ping(context context.Context, req *net.PingPong) (*net.PingPong, error) {
@@ -184,6 +218,7 @@ func StartTranscodeServer(orch Orchestrator, bind string, mux *http.ServeMux, wo
 	}
 	net.RegisterOrchestratorServer(s, &lp)
 	lp.transRPC.HandleFunc("/segment", lp.ServeSegment)
+	lp.transRPC.HandleFunc("/keepalive", lp.ServeKeepalive)
 	if acceptRemoteTranscoders {
 		net.RegisterTranscoderServer(s, &lp)
 		lp.transRPC.HandleFunc("/transcodeResults", lp.TranscodeResults)
@@ -245,6 +280,14 @@ func ping(context context.Context, req *net.PingPong, orch Orchestrator) (*net.P
 
 // GetOrchestratorInfo - the broadcaster calls GetOrchestratorInfo which invokes GetOrchestrator on the orchestrator
 func GetOrchestratorInfo(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL) (*net.OrchestratorInfo, error) {
+	return refreshOrchestratorInfo(ctx, bcast, orchestratorServer, nil)
+}
+
+// refreshOrchestratorInfo gets new orchestrator info for the session with authToken so that the orchestrator keeps the
+// session ID of a running session. New sessions have no auth token
+func refreshOrchestratorInfo(ctx context.Context, bcast common.Broadcaster, orchestratorServer *url.URL,
+	authToken *net.AuthToken) (*net.OrchestratorInfo, error) {
+
 	c, conn, err := startOrchestratorClient(ctx, orchestratorServer)
 	if err != nil {
 		return nil, err
@@ -252,10 +295,20 @@ func GetOrchestratorInfo(ctx context.Context, bcast common.Broadcaster, orchestr
 	defer conn.Close()
 
 	req, err := genOrchestratorReq(bcast)
-	r, err := c.GetOrchestrator(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	req.AuthToken = authToken
+	ex := newOrchestratorExchange(orchestratorServer, req)
+	r, err := c.GetOrchestrator(withOrchestratorToken(ctx, orchestratorServer.Host), req)
+	ex.setOrchestratorResult(r, err)
+	RPCRecord.Record(ex)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Could not get orchestrator orch=%v", orchestratorServer)
 	}
+	if err := verifyOrchestratorInfo(r, bcast.Address(), ethcommon.Address{}, time.Now()); err != nil {
+		return nil, errors.Wrapf(err, "Invalid orchestrator info orch=%v", orchestratorServer)
+	}
 
 	return r, nil
 }
@@ -283,18 +336,33 @@ func genOrchestratorReq(b common.Broadcaster) (*net.OrchestratorRequest, error)
 	return &net.OrchestratorRequest{Address: b.Address().Bytes(), Sig: sig}, nil
 }
 
-func getOrchestrator(orch Orchestrator, req *net.OrchestratorRequest) (*net.OrchestratorInfo, error) {
+func getOrchestrator(ctx context.Context, orch Orchestrator, req *net.OrchestratorRequest) (*net.OrchestratorInfo, error) {
 	addr := ethcommon.BytesToAddress(req.Address)
 	if err := verifyOrchestratorReq(orch, addr, req.Sig); err != nil {
 		return nil, fmt.Errorf("Invalid orchestrator request: %v", err)
 	}
 
+	if err := authorizeBroadcaster(ctx, addr); err != nil {
+		return nil, err
+	}
+
 	if _, err := authenticateBroadcaster(addr.Hex()); err != nil {
 		return nil, fmt.Errorf("authentication failed: %v", err)
 	}
 
+	// Keep the session ID of a session that the broadcaster refreshes
+	sessionID := ""
+	if req.AuthToken != nil && verifyAuthToken(orch, req.AuthToken) == nil {
+		sessionID = req.AuthToken.SessionId
+	}
+
+	// Stop advertising to broadcasters while in maintenance, except to refresh the sessions of running streams
+	if err := orch.CheckMaintenance(core.ManifestID(sessionID)); err != nil {
+		return nil, err
+	}
+
 	// currently, orchestrator == transcoder
-	return orchestratorInfo(orch, addr, orch.ServiceURI().String())
+	return orchestratorInfo(orch, addr, orch.ServiceURI().String(), sessionID)
 }
 
 func getPriceInfo(orch Orchestrator, addr ethcommon.Address) (*net.PriceInfo, error) {
@@ -307,7 +375,9 @@ func getPriceInfo(orch Orchestrator, addr ethcommon.Address) (*net.PriceInfo, er
 	return orch.PriceInfo(addr)
 }
 
-func orchestratorInfo(orch Orchestrator, addr ethcommon.Address, serviceURI string) (*net.OrchestratorInfo, error) {
+// orchestratorInfo returns the info of the orchestrator for the broadcaster with addr. The auth token is issued for
+// sessionID, or for a new session if empty
+func orchestratorInfo(orch Orchestrator, addr ethcommon.Address, serviceURI string, sessionID string) (*net.OrchestratorInfo, error) {
 	priceInfo, err := getPriceInfo(orch, addr)
 	if err != nil {
 		return nil, err
@@ -319,7 +389,9 @@ func orchestratorInfo(orch Orchestrator, addr ethcommon.Address, serviceURI stri
 	}
 
 	// Generate auth token
-	sessionID := string(core.RandomManifestID())
+	if sessionID == "" {
+		sessionID = string(core.RandomManifestID())
+	}
 	expiration := time.Now().Add(authTokenValidPeriod).Unix()
 	authToken := orch.AuthToken(sessionID, expiration)
 
@@ -330,6 +402,17 @@ func orchestratorInfo(orch Orchestrator, addr ethcommon.Address, serviceURI stri
 		Address:      orch.Address().Bytes(),
 		Capabilities: orch.Capabilities(),
 		AuthToken:    authToken,
+
+		DetectionPricePerSecond: orch.DetectionPrice().Int64(),
+	}
+
+	tr.Timestamp = time.Now().Unix()
+	msg, err := orchestratorInfoMsg(&tr, addr)
+	if err != nil {
+		return nil, err
+	}
+	if tr.Sig, err = orch.Sign(msg); err != nil {
+		return nil, err
 	}
 
 	os := drivers.NodeStorage.NewSession(authToken.SessionId)
@@ -345,6 +428,77 @@ func orchestratorInfo(orch Orchestrator, addr ethcommon.Address, serviceURI stri
 	return &tr, nil
 }
 
+// orchestratorInfoMsg returns the message that an orchestrator signs over the info that it sends to the broadcaster
+// with addr, so that the info cannot be spoofed or replayed to other broadcasters
+func orchestratorInfoMsg(info *net.OrchestratorInfo, broadcaster ethcommon.Address) ([]byte, error) {
+	msg := []byte(fmt.Sprintf("%v|%x|%x|%v|%v|%v|%x|%v|",
+		info.Transcoder,
+		info.Address,
+		broadcaster.Bytes(),
+		info.PriceInfo.GetPricePerUnit(),
+		info.PriceInfo.GetPixelsPerUnit(),
+		info.DetectionPricePerSecond,
+		info.TicketParams.GetRecipient(),
+		info.Timestamp,
+	))
+	if info.Capabilities != nil {
+		var buf proto.Buffer
+		buf.SetDeterministic(true)
+		if err := buf.Marshal(info.Capabilities); err != nil {
+			return nil, err
+		}
+		msg = append(msg, buf.Bytes()...)
+	}
+	return msg, nil
+}
+
+// verifyOrchestratorInfo returns an error if info was not signed by the orchestrator for the broadcaster with addr
+// within orchestratorInfoMaxAge of now, or if it was not signed by the expected address of the orchestrator when that
+// is known. Unsigned info is only accepted by broadcasters that are not connected to Ethereum, as orchestrators that
+// are not connected to Ethereum cannot sign it, unless RequireOrchestratorInfoSig is disabled
+func verifyOrchestratorInfo(info *net.OrchestratorInfo, broadcaster, expected ethcommon.Address, now time.Time) error {
+	if len(info.Sig) == 0 {
+		if broadcaster == (ethcommon.Address{}) {
+			return nil
+		}
+		if RequireOrchestratorInfoSig {
+			return errors.New("orchestrator info is not signed")
+		}
+		glog.V(common.DEBUG).Infof("Accepting unsigned orchestrator info orch=%v addr=%x", info.Transcoder, info.Address)
+		return VerifyOrchestratorAddress(info, expected)
+	}
+	signedAt := time.Unix(info.Timestamp, 0)
+	if age := now.Sub(signedAt); age > orchestratorInfoMaxAge || age < -orchestratorInfoMaxAge {
+		return fmt.Errorf("orchestrator info is stale signedAt=%v", signedAt.UTC().Format(time.RFC3339))
+	}
+	msg, err := orchestratorInfoMsg(info, broadcaster)
+	if err != nil {
+		return err
+	}
+	if !lpcrypto.VerifySig(ethcommon.BytesToAddress(info.Address), crypto.Keccak256(msg), info.Sig) {
+		return errors.New("orchestrator info sig check failed")
+	}
+	return VerifyOrchestratorAddress(info, expected)
+}
+
+// VerifyOrchestratorAddress returns an error if info was not signed by expected, the address that the orchestrator is
+// known by, i.e. its on-chain registered address. The check is skipped if expected is empty. Orchestrators that sign
+// with another account than the one they are registered with, i.e. with -ethOrchAddr, are accepted if they pay out
+// to expected unless RequireOrchestratorInfoSig is enabled
+func VerifyOrchestratorAddress(info *net.OrchestratorInfo, expected ethcommon.Address) error {
+	if expected == (ethcommon.Address{}) {
+		return nil
+	}
+	signer := ethcommon.BytesToAddress(info.Address)
+	if signer == expected {
+		return nil
+	}
+	if !RequireOrchestratorInfoSig && ethcommon.BytesToAddress(info.TicketParams.GetRecipient()) == expected {
+		return nil
+	}
+	return fmt.Errorf("orchestrator info signed by unexpected address signer=%v expected=%v", signer.Hex(), expected.Hex())
+}
+
 func verifyOrchestratorReq(orch Orchestrator, addr ethcommon.Address, sig []byte) error {
 	if !orch.VerifySig(addr, addr.Hex(), sig) {
 		glog.Error("orchestrator req sig check failed")
@@ -481,9 +635,12 @@ func coreSegMetadata(segData *net.SegData) (*core.SegTranscodingMetadata, error)
 		var detectorProfile ffmpeg.DetectorProfile
 		// Refer to the following for type magic:
 		// https://developers.google.com/protocol-buffers/docs/reference/go-generated#oneof
-		switch x := detector.Value.(type) {
+		switch x := detector.GetValue().(type) {
 		case *net.DetectorProfile_SceneClassification:
 			profile := x.SceneClassification
+			if profile == nil {
+				return nil, errDetectorProfile
+			}
 			classes := []ffmpeg.DetectorClass{}
 			for _, class := range profile.Classes {
 				classes = append(classes, ffmpeg.DetectorClass{
@@ -495,10 +652,24 @@ func coreSegMetadata(segData *net.SegData) (*core.SegTranscodingMetadata, error)
 				SampleRate: uint(profile.SampleRate),
 				Classes:    classes,
 			}
+		default:
+			return nil, errDetectorProfile
 		}
 		detectorProfs = append(detectorProfs, detectorProfile)
 	}
 
+	timeToDeadline := time.Duration(segData.TimeToDeadline) * time.Millisecond
+	if timeToDeadline < 0 {
+		glog.Error("Invalid time to deadline")
+		return nil, errTimeToDeadline
+	}
+
+	filters, err := core.ParseFilterGraph(segData.FilterGraph)
+	if err != nil {
+		glog.Errorf("Invalid filter graph err=%q", err)
+		return nil, err
+	}
+
 	return &core.SegTranscodingMetadata{
 		ManifestID:         core.ManifestID(segData.ManifestId),
 		Seq:                segData.Seq,
@@ -511,5 +682,7 @@ func coreSegMetadata(segData *net.SegData) (*core.SegTranscodingMetadata, error)
 		DetectorEnabled:    segData.DetectorEnabled,
 		DetectorProfiles:   detectorProfs,
 		CalcPerceptualHash: segData.CalcPerceptualHash,
+		TimeToDeadline:     timeToDeadline,
+		Filters:            filters,
 	}, nil
 }
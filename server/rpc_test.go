@@ -64,17 +64,23 @@ func (m *mockBalance) Clear() {
 }
 
 type stubOrchestrator struct {
-	priv         *ecdsa.PrivateKey
-	block        *big.Int
-	signErr      error
-	sessCapErr   error
-	ticketParams *net.TicketParams
-	priceInfo    *net.PriceInfo
-	serviceURI   string
-	res          *core.TranscodeResult
-	offchain     bool
-	caps         *core.Capabilities
-	authToken    *net.AuthToken
+	priv           *ecdsa.PrivateKey
+	block          *big.Int
+	signErr        error
+	sessCapErr     error
+	keepAliveErr   error
+	streamLimitErr error
+	maintenanceErr error
+	// session that is exempt from maintenance
+	runningSession core.ManifestID
+	ticketParams   *net.TicketParams
+	priceInfo      *net.PriceInfo
+	serviceURI     string
+	res            *core.TranscodeResult
+	offchain       bool
+	caps           *core.Capabilities
+	authToken      *net.AuthToken
+	detectionPrice int64
 }
 
 func (r *stubOrchestrator) ServiceURI() *url.URL {
@@ -148,6 +154,13 @@ func (r *stubOrchestrator) SufficientBalance(addr ethcommon.Address, manifestID
 func (r *stubOrchestrator) DebitFees(addr ethcommon.Address, manifestID core.ManifestID, price *net.PriceInfo, pixels int64) {
 }
 
+func (r *stubOrchestrator) DetectionPrice() *big.Int {
+	return big.NewInt(r.detectionPrice)
+}
+
+func (r *stubOrchestrator) DebitDetectionFees(addr ethcommon.Address, manifestID core.ManifestID, duration time.Duration) {
+}
+
 func (r *stubOrchestrator) Capabilities() *net.Capabilities {
 	if r.caps != nil {
 		return r.caps.ToNetCapabilities()
@@ -176,6 +189,18 @@ func newStubOrchestrator() *stubOrchestrator {
 func (r *stubOrchestrator) CheckCapacity(mid core.ManifestID) error {
 	return r.sessCapErr
 }
+func (r *stubOrchestrator) KeepAlive(mid core.ManifestID) error {
+	return r.keepAliveErr
+}
+func (r *stubOrchestrator) CheckStreamLimits(mid core.ManifestID, sender ethcommon.Address, ip string) error {
+	return r.streamLimitErr
+}
+func (r *stubOrchestrator) CheckMaintenance(mid core.ManifestID) error {
+	if mid != "" && mid == r.runningSession {
+		return nil
+	}
+	return r.maintenanceErr
+}
 func (r *stubOrchestrator) ServeTranscoder(stream net.Transcoder_RegisterTranscoderServer, capacity int, capabilities *net.Capabilities) {
 }
 func (r *stubOrchestrator) TranscoderResults(job int64, res *core.RemoteTranscoderResult) {
@@ -701,6 +726,31 @@ func TestValidatePrice(t *testing.T) {
 	err = validatePrice(s)
 	assert.EqualError(err, fmt.Sprintf("Orchestrator price higher than the set maximum price of %v wei per %v pixels", int64(1), int64(5)))
 
+	// Stream MaxPrice < O Price
+	BroadcastCfg.SetMaxPrice(nil)
+	s.Params.MaxPrice = big.NewRat(1, 4)
+	err = validatePrice(s)
+	assert.EqualError(err, fmt.Sprintf("Orchestrator price higher than the maximum price of the stream of %v wei per %v pixels", int64(1), int64(4)))
+	s.Params.MaxPrice = nil
+
+	// B MaxDetectionPrice only applies to detection-only streams
+	defer BroadcastCfg.SetMaxDetectionPrice(nil)
+	BroadcastCfg.SetMaxDetectionPrice(big.NewInt(10))
+	s.OrchestratorInfo.DetectionPricePerSecond = 11
+	err = validatePrice(s)
+	assert.Nil(err)
+
+	// B MaxDetectionPrice < O detection price
+	s.Params.Detection = core.DetectionConfig{Freq: 1}
+	err = validatePrice(s)
+	assert.EqualError(err, "Orchestrator detection price higher than the set maximum detection price of 10 wei per second")
+
+	// B MaxDetectionPrice == O detection price
+	s.OrchestratorInfo.DetectionPricePerSecond = 10
+	err = validatePrice(s)
+	assert.Nil(err)
+	s.Params.Detection = core.DetectionConfig{}
+
 	// O.PriceInfo is nil
 	s.OrchestratorInfo.PriceInfo = nil
 	err = validatePrice(s)
@@ -809,19 +859,163 @@ func TestGetOrchestrator_GivenValidSig_ReturnsTranscoderURI(t *testing.T) {
 	orch.On("TicketParams", mock.Anything, mock.Anything).Return(nil, nil)
 	orch.On("PriceInfo", mock.Anything).Return(nil, nil)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(&net.AuthToken{})
-	oInfo, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	orch.On("Sign", mock.Anything).Return(nil, nil)
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert := assert.New(t)
 	assert.Nil(err)
 	assert.Equal(uri, oInfo.Transcoder)
 }
 
+func TestGetOrchestrator_Maintenance(t *testing.T) {
+	orch := newStubOrchestrator()
+	orch.offchain = true
+	orch.maintenanceErr = core.ErrOrchMaintenance
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
+
+	assert := assert.New(t)
+	assert.Equal(core.ErrOrchMaintenance, err)
+	assert.Nil(oInfo)
+
+	// Running sessions are refreshed with the same session ID
+	orch.runningSession = "running"
+	authToken := orch.AuthToken("running", time.Now().Add(time.Hour).Unix())
+	oInfo, err = getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{AuthToken: authToken})
+	assert.Nil(err)
+	assert.Equal("running", oInfo.AuthToken.SessionId)
+
+	// Sessions that are not running are refused
+	oInfo, err = getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{
+		AuthToken: orch.AuthToken("ended", time.Now().Add(time.Hour).Unix()),
+	})
+	assert.Equal(core.ErrOrchMaintenance, err)
+	assert.Nil(oInfo)
+
+	// Invalid or expired auth tokens do not exempt the session
+	oInfo, err = getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{
+		AuthToken: &net.AuthToken{Token: []byte("notfoo"), SessionId: "running", Expiration: authToken.Expiration},
+	})
+	assert.Equal(core.ErrOrchMaintenance, err)
+	assert.Nil(oInfo)
+	oInfo, err = getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{
+		AuthToken: orch.AuthToken("running", time.Now().Add(-time.Hour).Unix()),
+	})
+	assert.Equal(core.ErrOrchMaintenance, err)
+	assert.Nil(oInfo)
+
+	// Out of maintenance, refreshed sessions keep their session ID and new sessions get a new one
+	orch.maintenanceErr = nil
+	oInfo, err = getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{AuthToken: authToken})
+	assert.Nil(err)
+	assert.Equal("running", oInfo.AuthToken.SessionId)
+	oInfo, err = getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
+	assert.Nil(err)
+	assert.NotEqual("running", oInfo.AuthToken.SessionId)
+	assert.NotEmpty(oInfo.AuthToken.SessionId)
+}
+
+func TestGetOrchestrator_ReturnsDetectionPrice(t *testing.T) {
+	orch := newStubOrchestrator()
+	orch.offchain = true
+	orch.detectionPrice = 1000
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(int64(1000), oInfo.DetectionPricePerSecond)
+}
+
+func TestOrchestratorInfo_SignVerify(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	orch := newStubOrchestrator()
+	orch.priceInfo = &net.PriceInfo{PricePerUnit: 5, PixelsPerUnit: 1}
+	orch.ticketParams = &net.TicketParams{Recipient: orch.Address().Bytes()}
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+	bcast := ethcommon.HexToAddress("0x1234")
+	now := time.Now()
+
+	info, err := orchestratorInfo(orch, bcast, "http://someuri.com", "")
+	require.Nil(err)
+	assert.NotEmpty(info.Sig)
+	assert.Nil(verifyOrchestratorInfo(info, bcast, ethcommon.Address{}, now))
+
+	// Info cannot be replayed to other broadcasters
+	err = verifyOrchestratorInfo(info, ethcommon.HexToAddress("0x5678"), ethcommon.Address{}, now)
+	assert.EqualError(err, "orchestrator info sig check failed")
+
+	// Stale info is rejected
+	err = verifyOrchestratorInfo(info, bcast, ethcommon.Address{}, now.Add(orchestratorInfoMaxAge+time.Minute))
+	assert.Contains(err.Error(), "orchestrator info is stale")
+	err = verifyOrchestratorInfo(info, bcast, ethcommon.Address{}, now.Add(-orchestratorInfoMaxAge-time.Minute))
+	assert.Contains(err.Error(), "orchestrator info is stale")
+
+	// Info that was changed after it was signed is rejected
+	spoofed := proto.Clone(info).(*net.OrchestratorInfo)
+	spoofed.PriceInfo.PricePerUnit = 1
+	assert.EqualError(verifyOrchestratorInfo(spoofed, bcast, ethcommon.Address{}, now), "orchestrator info sig check failed")
+	spoofed = proto.Clone(info).(*net.OrchestratorInfo)
+	spoofed.Transcoder = "http://spoofed.com"
+	assert.EqualError(verifyOrchestratorInfo(spoofed, bcast, ethcommon.Address{}, now), "orchestrator info sig check failed")
+	spoofed = proto.Clone(info).(*net.OrchestratorInfo)
+	spoofed.Timestamp = info.Timestamp - 1
+	assert.EqualError(verifyOrchestratorInfo(spoofed, bcast, ethcommon.Address{}, now), "orchestrator info sig check failed")
+
+	// Unsigned info is only accepted by broadcasters that are not connected to Ethereum
+	orch.offchain = true
+	info, err = orchestratorInfo(orch, ethcommon.Address{}, "http://someuri.com", "")
+	require.Nil(err)
+	assert.Empty(info.Sig)
+	assert.Nil(verifyOrchestratorInfo(info, ethcommon.Address{}, ethcommon.Address{}, now))
+
+	// Broadcasters that are connected to Ethereum only accept unsigned info while the sig is not required
+	assert.Nil(verifyOrchestratorInfo(info, bcast, ethcommon.Address{}, now))
+	defer func() { RequireOrchestratorInfoSig = false }()
+	RequireOrchestratorInfoSig = true
+	assert.EqualError(verifyOrchestratorInfo(info, bcast, ethcommon.Address{}, now), "orchestrator info is not signed")
+}
+
+func TestOrchestratorInfo_VerifyExpectedAddress(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	orch := newStubOrchestrator()
+	orch.priceInfo = &net.PriceInfo{PricePerUnit: 5, PixelsPerUnit: 1}
+	recipient := ethcommon.HexToAddress("0x9999")
+	orch.ticketParams = &net.TicketParams{Recipient: recipient.Bytes()}
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+	bcast := ethcommon.HexToAddress("0x1234")
+	now := time.Now()
+
+	info, err := orchestratorInfo(orch, bcast, "http://someuri.com", "")
+	require.Nil(err)
+	assert.Nil(verifyOrchestratorInfo(info, bcast, orch.Address(), now))
+
+	// Info signed by another orchestrator is rejected even if the signature is valid
+	other := ethcommon.HexToAddress("0x5678")
+	err = verifyOrchestratorInfo(info, bcast, other, now)
+	assert.EqualError(err, fmt.Sprintf("orchestrator info signed by unexpected address signer=%v expected=%v", orch.Address().Hex(), other.Hex()))
+
+	// Orchestrators that sign with another account than their registered address are accepted while the sig is not
+	// required if they pay out to their registered address
+	assert.Nil(verifyOrchestratorInfo(info, bcast, recipient, now))
+	defer func() { RequireOrchestratorInfoSig = false }()
+	RequireOrchestratorInfoSig = true
+	assert.NotNil(verifyOrchestratorInfo(info, bcast, recipient, now))
+	assert.Nil(verifyOrchestratorInfo(info, bcast, orch.Address(), now))
+}
+
 func TestGetOrchestrator_GivenInvalidSig_ReturnsError(t *testing.T) {
 	orch := &mockOrchestrator{}
 	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(false)
 
-	oInfo, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert := assert.New(t)
 	assert.Contains(err.Error(), "sig")
@@ -839,7 +1033,8 @@ func TestGetOrchestrator_GivenValidSig_ReturnsOrchTicketParams(t *testing.T) {
 	orch.On("TicketParams", mock.Anything, mock.Anything).Return(expectedParams, nil)
 	orch.On("PriceInfo", mock.Anything, mock.Anything).Return(nil, nil)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(&net.AuthToken{})
-	oInfo, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	orch.On("Sign", mock.Anything).Return(nil, nil)
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert := assert.New(t)
 	assert.Nil(err)
@@ -852,7 +1047,7 @@ func TestGetOrchestrator_WebhookAuth_Error(t *testing.T) {
 	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 
-	oInfo, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert := assert.New(t)
 	assert.Nil(oInfo)
@@ -877,7 +1072,7 @@ func TestGetOrchestrator_WebhookAuth_ReturnsNotOK(t *testing.T) {
 	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 
-	oInfo, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert := assert.New(t)
 	assert.Nil(oInfo)
@@ -912,7 +1107,8 @@ func TestGetOrchestratorWebhookAuth_ReturnsOK(t *testing.T) {
 	orch.On("TicketParams", mock.Anything, mock.Anything).Return(expectedParams, nil)
 	orch.On("PriceInfo", mock.Anything, mock.Anything).Return(nil, nil)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(&net.AuthToken{})
-	oInfo, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	orch.On("Sign", mock.Anything).Return(nil, nil)
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert := assert.New(t)
 	assert.Nil(err)
@@ -930,7 +1126,7 @@ func TestGetOrchestrator_TicketParamsError(t *testing.T) {
 	orch.On("PriceInfo", mock.Anything).Return(nil, nil)
 	orch.On("TicketParams", mock.Anything, mock.Anything).Return(nil, expErr)
 
-	_, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	_, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert := assert.New(t)
 	assert.EqualError(err, expErr.Error())
@@ -950,7 +1146,8 @@ func TestGetOrchestrator_GivenValidSig_ReturnsOrchPriceInfo(t *testing.T) {
 	orch.On("TicketParams", mock.Anything, mock.Anything).Return(nil, nil)
 	orch.On("PriceInfo", mock.Anything).Return(expectedPrice, nil)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(&net.AuthToken{})
-	oInfo, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	orch.On("Sign", mock.Anything).Return(nil, nil)
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert := assert.New(t)
 	assert.Nil(err)
@@ -968,7 +1165,7 @@ func TestGetOrchestrator_PriceInfoError(t *testing.T) {
 	orch.On("Address").Return(ethcommon.Address{})
 	orch.On("PriceInfo", mock.Anything).Return(nil, expErr)
 
-	_, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	_, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert.EqualError(t, err, expErr.Error())
 }
@@ -992,8 +1189,9 @@ func TestGetOrchestrator_GivenValidSig_ReturnsAuthToken(t *testing.T) {
 	// when the mocked AuthToken is called. 1 second would need to elapse which should only really happen if the test
 	// is run in a really slow environment
 	orch.On("AuthToken", authToken.SessionId, authToken.Expiration).Return(authToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
-	oInfo, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 
 	assert := assert.New(t)
 	assert.Nil(err)
@@ -1010,14 +1208,14 @@ func TestGetOrchestrator_StorageInit(t *testing.T) {
 	orch.authToken = stubAuthToken
 
 	// Check when local storage is used
-	oInfo, err := getOrchestrator(orch, &net.OrchestratorRequest{})
+	oInfo, err := getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 	assert.Nil(err)
 	assert.Nil(oInfo.Storage)
 
 	// Check when external storage is used
 	drivers.NodeStorage, _ = drivers.ParseOSURL("s3://key:secret@us/livepeer", false)
 
-	oInfo, err = getOrchestrator(orch, &net.OrchestratorRequest{})
+	oInfo, err = getOrchestrator(context.Background(), orch, &net.OrchestratorRequest{})
 	assert.Nil(err)
 	assert.Len(oInfo.Storage, 1)
 	assert.Equal(stubAuthToken.SessionId, oInfo.Storage[0].S3Info.Key)
@@ -1222,6 +1420,34 @@ func TestGenVerify_RoundTrip_Duration(t *testing.T) {
 	})
 }
 
+func TestGenVerify_RoundTrip_Filters(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	orch := &stubOrchestrator{offchain: true}
+	sess := &BroadcastSession{
+		Broadcaster: stubBroadcaster2(),
+		Params: &core.StreamParameters{
+			Profiles: []ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9},
+			Filters: []core.FilterStep{
+				{Name: "crop", Args: map[string]string{"w": "iw-20", "h": "ih-20"}},
+				{Name: "fps", Args: map[string]string{"fps": "30"}},
+			},
+		},
+		OrchestratorInfo: &net.OrchestratorInfo{AuthToken: orch.AuthToken("bar", time.Now().Add(1*time.Hour).Unix())},
+	}
+
+	// The filters are sent to the orchestrator with the segment
+	creds, err := genSegCreds(sess, &stream.HLSSegment{}, false)
+	require.Nil(err)
+	md, _, err := verifySegCreds(context.TODO(), orch, creds, ethcommon.Address{})
+	require.Nil(err)
+	assert.Equal(sess.Params.Filters, md.Filters)
+
+	// Filters that are not allowed are rejected
+	_, err = coreSegMetadata(&net.SegData{FilterGraph: "movie=filename=/etc/passwd"})
+	assert.EqualError(err, `filter "movie" is not allowed`)
+}
+
 func TestCoreNetSegData_RoundTrip_Duration(t *testing.T) {
 	// check invariant : NetSegMetadata(coreSegMetadata(dur)).Duration == dur
 	// and vice versa.
@@ -1319,6 +1545,19 @@ func (o *mockOrchestrator) CheckCapacity(mid core.ManifestID) error {
 	return nil
 }
 
+func (o *mockOrchestrator) KeepAlive(mid core.ManifestID) error {
+	args := o.Called(mid)
+	return args.Error(0)
+}
+
+func (o *mockOrchestrator) CheckStreamLimits(mid core.ManifestID, sender ethcommon.Address, ip string) error {
+	return nil
+}
+
+func (o *mockOrchestrator) CheckMaintenance(mid core.ManifestID) error {
+	return nil
+}
+
 func (o *mockOrchestrator) SufficientBalance(addr ethcommon.Address, manifestID core.ManifestID) bool {
 	args := o.Called(addr, manifestID)
 	return args.Bool(0)
@@ -1328,6 +1567,14 @@ func (o *mockOrchestrator) DebitFees(addr ethcommon.Address, manifestID core.Man
 	o.Called(addr, manifestID, price, pixels)
 }
 
+func (o *mockOrchestrator) DetectionPrice() *big.Int {
+	return big.NewInt(0)
+}
+
+func (o *mockOrchestrator) DebitDetectionFees(addr ethcommon.Address, manifestID core.ManifestID, duration time.Duration) {
+	o.Called(addr, manifestID, duration)
+}
+
 func (o *mockOrchestrator) Capabilities() *net.Capabilities {
 	return core.NewCapabilities(nil, nil).ToNetCapabilities()
 }
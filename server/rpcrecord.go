@@ -0,0 +1,317 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/net"
+)
+
+const (
+	RPCGetOrchestrator = "GetOrchestrator"
+	RPCSegment         = "Segment"
+)
+
+// RPCRecord records the RPC exchanges of the broadcaster with orchestrators if set
+var RPCRecord *RPCRecorder
+
+// RPCExchange is a recorded RPC exchange between a broadcaster and an orchestrator. Segment payloads are not recorded,
+// only their hash and size, so the payloads have to be provided separately to replay the exchange
+type RPCExchange struct {
+	Time         time.Time `json:"time"`
+	Version      string    `json:"version"`
+	Method       string    `json:"method"`
+	Orchestrator string    `json:"orchestrator"`
+
+	// Request headers for segments, marshaled net.OrchestratorRequest for GetOrchestrator
+	Headers map[string]string `json:"headers,omitempty"`
+	Request []byte            `json:"request,omitempty"`
+
+	SegmentHash     string  `json:"segmentHash,omitempty"`
+	SegmentSize     int     `json:"segmentSize,omitempty"`
+	SegmentDuration float64 `json:"segmentDuration,omitempty"`
+
+	// Marshaled net.OrchestratorInfo for GetOrchestrator, net.TranscodeResult for segments
+	Status   int           `json:"status,omitempty"`
+	Response []byte        `json:"response,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Latency  time.Duration `json:"latency"`
+}
+
+// HashSegment returns the hash that identifies the segment payload in recorded exchanges
+func HashSegment(data []byte) string {
+	return hex.EncodeToString(crypto.Keccak256(data))
+}
+
+func newSegmentExchange(orch string, header http.Header, data []byte, duration float64) *RPCExchange {
+	if RPCRecord == nil {
+		return nil
+	}
+	headers := make(map[string]string)
+	for k := range header {
+		headers[k] = header.Get(k)
+	}
+	return &RPCExchange{
+		Time:            time.Now(),
+		Version:         core.LivepeerVersion,
+		Method:          RPCSegment,
+		Orchestrator:    orch,
+		Headers:         headers,
+		SegmentHash:     HashSegment(data),
+		SegmentSize:     len(data),
+		SegmentDuration: duration,
+	}
+}
+
+func newOrchestratorExchange(orch *url.URL, req *net.OrchestratorRequest) *RPCExchange {
+	if RPCRecord == nil {
+		return nil
+	}
+	ex := &RPCExchange{Time: time.Now(), Version: core.LivepeerVersion, Method: RPCGetOrchestrator, Orchestrator: orch.String()}
+	if req != nil {
+		ex.Request, _ = proto.Marshal(req)
+	}
+	return ex
+}
+
+func (ex *RPCExchange) setResult(status int, resp []byte, err error) {
+	if ex == nil {
+		return
+	}
+	ex.Status = status
+	ex.Response = resp
+	if err != nil {
+		ex.Error = err.Error()
+	}
+	ex.Latency = time.Since(ex.Time)
+}
+
+func (ex *RPCExchange) setOrchestratorResult(info *net.OrchestratorInfo, err error) {
+	if ex == nil {
+		return
+	}
+	var resp []byte
+	if info != nil {
+		resp, _ = proto.Marshal(info)
+	}
+	ex.setResult(0, resp, err)
+}
+
+// RPCRecorder appends RPC exchanges to a file as JSON lines
+type RPCRecorder struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// NewRPCRecorder opens the file at path to append exchanges to it
+func NewRPCRecorder(path string) (*RPCRecorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCRecorder{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Record writes the exchange to the file
+func (r *RPCRecorder) Record(ex *RPCExchange) {
+	if r == nil || ex == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.enc.Encode(ex); err != nil {
+		glog.Errorf("Error recording RPC exchange method=%s orch=%s err=%q", ex.Method, ex.Orchestrator, err)
+	}
+}
+
+// Close closes the file
+func (r *RPCRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.f.Close()
+}
+
+// ReadRPCExchanges reads the exchanges recorded in the file at path
+func ReadRPCExchanges(path string) ([]*RPCExchange, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var exchanges []*RPCExchange
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for dec.More() {
+		var ex RPCExchange
+		if err := dec.Decode(&ex); err != nil {
+			return nil, fmt.Errorf("invalid exchange #%d: %v", len(exchanges), err)
+		}
+		exchanges = append(exchanges, &ex)
+	}
+	return exchanges, nil
+}
+
+// RPCReplayResult is the result of replaying a recorded exchange. Mismatch describes how the replayed exchange differs
+// from the recorded one and is empty if they match
+type RPCReplayResult struct {
+	Exchange *RPCExchange
+	Skipped  bool
+	Status   int
+	Error    string
+	Mismatch string
+	Latency  time.Duration
+}
+
+// ReplayRPC replays the recorded exchanges in order against the orchestrator at orch. The payloads of segments are
+// looked up by hash in segments and segments without a payload are skipped. Segments are sent to the transcoder and
+// with the auth token returned by the last replayed GetOrchestrator exchange so that they are accepted by orch
+func ReplayRPC(ctx context.Context, exchanges []*RPCExchange, orch *url.URL, segments map[string][]byte) []*RPCReplayResult {
+	var info *net.OrchestratorInfo
+	var results []*RPCReplayResult
+	for _, ex := range exchanges {
+		res := &RPCReplayResult{Exchange: ex}
+		start := time.Now()
+		switch ex.Method {
+		case RPCGetOrchestrator:
+			var oInfo *net.OrchestratorInfo
+			oInfo, res.Error = replayGetOrchestrator(ctx, ex, orch)
+			if oInfo != nil {
+				info = oInfo
+			}
+			if (ex.Error == "") != (res.Error == "") {
+				res.Mismatch = fmt.Sprintf("error %q != %q", ex.Error, res.Error)
+			}
+		case RPCSegment:
+			data, ok := segments[ex.SegmentHash]
+			if !ok {
+				res.Skipped = true
+				break
+			}
+			var body []byte
+			res.Status, body, res.Error = replaySegment(ctx, ex, orch, info, data)
+			if ex.Status != res.Status {
+				res.Mismatch = fmt.Sprintf("status %d != %d", ex.Status, res.Status)
+			} else if recorded, replayed := describeTranscodeResult(ex.Response), describeTranscodeResult(body); recorded != replayed {
+				res.Mismatch = fmt.Sprintf("result %q != %q", recorded, replayed)
+			}
+		default:
+			res.Skipped = true
+		}
+		res.Latency = time.Since(start)
+		results = append(results, res)
+	}
+	return results
+}
+
+func replayGetOrchestrator(ctx context.Context, ex *RPCExchange, orch *url.URL) (*net.OrchestratorInfo, string) {
+	var req net.OrchestratorRequest
+	if err := proto.Unmarshal(ex.Request, &req); err != nil {
+		return nil, err.Error()
+	}
+	c, conn, err := startOrchestratorClient(ctx, orch)
+	if err != nil {
+		return nil, err.Error()
+	}
+	defer conn.Close()
+
+	info, err := c.GetOrchestrator(ctx, &req)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return info, ""
+}
+
+func replaySegment(ctx context.Context, ex *RPCExchange, orch *url.URL, info *net.OrchestratorInfo, data []byte) (int, []byte, string) {
+	transcoder := orch.String()
+	if info != nil && info.Transcoder != "" {
+		transcoder = info.Transcoder
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, common.HTTPTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", transcoder+"/segment", bytes.NewBuffer(data))
+	if err != nil {
+		return 0, nil, err.Error()
+	}
+	for k, v := range ex.Headers {
+		req.Header.Set(k, v)
+	}
+	if info.GetAuthToken() != nil {
+		segCreds, err := replaceAuthToken(req.Header.Get(segmentHeader), info.AuthToken)
+		if err != nil {
+			return 0, nil, err.Error()
+		}
+		req.Header.Set(segmentHeader, segCreds)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err.Error()
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, err.Error()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil, string(bytes.TrimSpace(body))
+	}
+	return resp.StatusCode, body, ""
+}
+
+// replaceAuthToken replaces the auth token in the segment credentials with token
+func replaceAuthToken(segCreds string, token *net.AuthToken) (string, error) {
+	buf, err := base64.StdEncoding.DecodeString(segCreds)
+	if err != nil {
+		return "", err
+	}
+	var segData net.SegData
+	if err := proto.Unmarshal(buf, &segData); err != nil {
+		return "", err
+	}
+	segData.AuthToken = token
+	buf, err = proto.Marshal(&segData)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// describeTranscodeResult summarizes a marshaled transcode result for comparisons between node versions
+func describeTranscodeResult(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var tr net.TranscodeResult
+	if err := proto.Unmarshal(data, &tr); err != nil {
+		return "invalid"
+	}
+	switch res := tr.Result.(type) {
+	case *net.TranscodeResult_Error:
+		return "error: " + res.Error
+	case *net.TranscodeResult_Data:
+		return fmt.Sprintf("%d segments", len(res.Data.GetSegments()))
+	default:
+		return "unknown"
+	}
+}
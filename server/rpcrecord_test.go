@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/net"
+	"github.com/livepeer/lpms/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPCRecord_Segment(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "rpcrecord")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+	fname := filepath.Join(dir, "record.jsonl")
+
+	rec, err := NewRPCRecorder(fname)
+	require.Nil(err)
+	RPCRecord = rec
+	defer func() { RPCRecord = nil }()
+
+	tr := &net.TranscodeResult{
+		Result: &net.TranscodeResult_Data{
+			Data: &net.TranscodeData{Segments: []*net.TranscodedSegmentData{{Url: "test.flv", Pixels: 100}}},
+		},
+	}
+	buf, err := proto.Marshal(tr)
+	require.Nil(err)
+
+	ts, mux := stubTLSServer()
+	defer ts.Close()
+	fail := false
+	mux.HandleFunc("/segment", func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "OrchestratorCapped", http.StatusForbidden)
+			return
+		}
+		w.Write(buf)
+	})
+
+	sess := StubBroadcastSession(ts.URL)
+	seg := &stream.HLSSegment{SeqNo: 1, Data: []byte("dummy"), Duration: 2}
+	_, err = SubmitSegment(context.TODO(), sess, seg, 0, false, true)
+	require.Nil(err)
+
+	fail = true
+	_, err = SubmitSegment(context.TODO(), sess, seg, 0, false, true)
+	require.NotNil(err)
+	require.Nil(rec.Close())
+
+	exchanges, err := ReadRPCExchanges(fname)
+	require.Nil(err)
+	require.Len(exchanges, 2)
+
+	ex := exchanges[0]
+	assert.Equal(RPCSegment, ex.Method)
+	assert.Equal(core.LivepeerVersion, ex.Version)
+	assert.Equal(ts.URL, ex.Orchestrator)
+	assert.Equal(HashSegment([]byte("dummy")), ex.SegmentHash)
+	assert.Equal(5, ex.SegmentSize)
+	assert.Equal(2.0, ex.SegmentDuration)
+	assert.NotEmpty(ex.Headers[segmentHeader])
+	assert.Equal("video/MP2T", ex.Headers["Content-Type"])
+	assert.Equal(http.StatusOK, ex.Status)
+	assert.Equal(buf, ex.Response)
+	assert.Empty(ex.Error)
+
+	ex = exchanges[1]
+	assert.Equal(http.StatusForbidden, ex.Status)
+	assert.Empty(ex.Response)
+	assert.Equal("OrchestratorCapped", ex.Error)
+
+	// Nothing is recorded if recording is disabled
+	RPCRecord = nil
+	fail = false
+	_, err = SubmitSegment(context.TODO(), sess, seg, 0, false, true)
+	require.Nil(err)
+	exchanges, err = ReadRPCExchanges(fname)
+	require.Nil(err)
+	assert.Len(exchanges, 2)
+}
+
+func TestReplayRPC(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tr := &net.TranscodeResult{
+		Result: &net.TranscodeResult_Data{
+			Data: &net.TranscodeData{Segments: []*net.TranscodedSegmentData{{Url: "test.flv", Pixels: 100}}},
+		},
+	}
+	buf, err := proto.Marshal(tr)
+	require.Nil(err)
+
+	var received [][]byte
+	ts, mux := stubTLSServer()
+	defer ts.Close()
+	mux.HandleFunc("/segment", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		received = append(received, data)
+		assert.Equal("creds", r.Header.Get(segmentHeader))
+		if string(data) == "seg1" {
+			http.Error(w, "OrchestratorBusy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(buf)
+	})
+	orch, err := url.Parse(ts.URL)
+	require.Nil(err)
+
+	headers := map[string]string{segmentHeader: "creds"}
+	exchanges := []*RPCExchange{
+		{Method: RPCSegment, Headers: headers, SegmentHash: HashSegment([]byte("seg0")), Status: http.StatusOK, Response: buf},
+		{Method: RPCSegment, Headers: headers, SegmentHash: HashSegment([]byte("seg1")), Status: http.StatusOK, Response: buf},
+		{Method: RPCSegment, Headers: headers, SegmentHash: HashSegment([]byte("missing")), Status: http.StatusOK},
+		{Method: "Unknown"},
+	}
+	segments := map[string][]byte{
+		HashSegment([]byte("seg0")): []byte("seg0"),
+		HashSegment([]byte("seg1")): []byte("seg1"),
+	}
+
+	results := ReplayRPC(context.Background(), exchanges, orch, segments)
+	require.Len(results, 4)
+	assert.Equal([][]byte{[]byte("seg0"), []byte("seg1")}, received)
+
+	assert.False(results[0].Skipped)
+	assert.Equal(http.StatusOK, results[0].Status)
+	assert.Empty(results[0].Error)
+	assert.Empty(results[0].Mismatch)
+
+	assert.Equal(http.StatusServiceUnavailable, results[1].Status)
+	assert.Equal("OrchestratorBusy", results[1].Error)
+	assert.Equal("status 200 != 503", results[1].Mismatch)
+
+	// Segments without payload and unknown methods are skipped
+	assert.True(results[2].Skipped)
+	assert.True(results[3].Skipped)
+}
+
+func TestReplaceAuthToken(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	segData := &net.SegData{ManifestId: []byte("mid"), Seq: 3, AuthToken: &net.AuthToken{SessionId: "old"}}
+	buf, err := proto.Marshal(segData)
+	require.Nil(err)
+
+	segCreds, err := replaceAuthToken(base64.StdEncoding.EncodeToString(buf), stubAuthToken)
+	require.Nil(err)
+	buf, err = base64.StdEncoding.DecodeString(segCreds)
+	require.Nil(err)
+	var replaced net.SegData
+	require.Nil(proto.Unmarshal(buf, &replaced))
+	assert.Equal([]byte("mid"), replaced.ManifestId)
+	assert.Equal(int64(3), replaced.Seq)
+	assert.True(proto.Equal(stubAuthToken, replaced.AuthToken))
+
+	_, err = replaceAuthToken("not base64!", stubAuthToken)
+	assert.NotNil(err)
+}
+
+func TestDescribeTranscodeResult(t *testing.T) {
+	assert := assert.New(t)
+
+	marshal := func(tr *net.TranscodeResult) []byte {
+		buf, err := proto.Marshal(tr)
+		require.Nil(t, err)
+		return buf
+	}
+
+	assert.Equal("", describeTranscodeResult(nil))
+	assert.Equal("invalid", describeTranscodeResult([]byte("foo")))
+	assert.Equal("error: OrchestratorBusy", describeTranscodeResult(marshal(&net.TranscodeResult{Result: &net.TranscodeResult_Error{Error: "OrchestratorBusy"}})))
+	assert.Equal("2 segments", describeTranscodeResult(marshal(&net.TranscodeResult{Result: &net.TranscodeResult_Data{
+		Data: &net.TranscodeData{Segments: []*net.TranscodedSegmentData{{}, {}}},
+	}})))
+	assert.Equal("unknown", describeTranscodeResult(marshal(&net.TranscodeResult{Seq: 1})))
+}
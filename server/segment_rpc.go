@@ -11,6 +11,7 @@ import (
 	"math/big"
 	gonet "net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/livepeer/go-livepeer/core"
 	"github.com/livepeer/go-livepeer/drivers"
 	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/lperrors"
 	"github.com/livepeer/go-livepeer/monitor"
 	"github.com/livepeer/go-livepeer/net"
 	"github.com/livepeer/lpms/ffmpeg"
@@ -46,10 +48,41 @@ var errFormat = errors.New("unrecognized profile output format")
 var errProfile = errors.New("unrecognized encoder profile")
 var errEncoder = errors.New("unrecognized video codec")
 var errDuration = errors.New("invalid duration")
+var errTimeToDeadline = errors.New("invalid time to deadline")
+var errVideoProfile = lperrors.User(errors.New("invalid video profile"))
+var errDetectorProfile = lperrors.User(errors.New("unrecognized detector profile"))
+var errPreset = lperrors.User(errors.New("unrecognized transcoding preset"))
+
+// remoteErrs are the classified errors that an orchestrator can return to a broadcaster
+var remoteErrs = []error{
+	core.ErrOrchBusy,
+	core.ErrOrchCap,
+	core.ErrTranscoderBusy,
+	core.ErrRemoteTranscoderTimeout,
+	core.ErrNoTranscodersAvailable,
+	core.ErrStreamLimit,
+	core.ErrNoHealthyTranscoders,
+	core.ErrOrchMaintenance,
+}
+
+// remoteError converts an error message received from an orchestrator back into the corresponding
+// classified error so that the class of the error is preserved across the RPC
+func remoteError(msg string) error {
+	for _, e := range remoteErrs {
+		if e.Error() == msg {
+			return e
+		}
+	}
+	return errors.New(msg)
+}
+
 var errCapCompat = errors.New("incompatible capabilities")
 
 var dialTimeout = 2 * time.Second
 
+// maxKeepaliveSize is the maximum size of a keepalive request body
+const maxKeepaliveSize = 1024
+
 var tlsConfig = &tls.Config{InsecureSkipVerify: true}
 var httpClient = &http.Client{
 	Transport: &http.Transport{
@@ -96,6 +129,25 @@ func (h *lphttp) ServeSegment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	ctx = clog.AddSeqNo(ctx, uint64(segData.Seq))
+	if segData.TimeToDeadline > 0 {
+		// Abandon any work on the segment, i.e. transcoding or uploading the results, once the broadcaster stops
+		// waiting for it
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, segData.TimeToDeadline)
+		defer cancel()
+	}
+
+	if err := orch.CheckMaintenance(core.ManifestID(segData.AuthToken.SessionId)); err != nil {
+		clog.Errorf(ctx, "Refusing new stream in maintenance")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := orch.CheckStreamLimits(core.ManifestID(segData.AuthToken.SessionId), sender, remoteAddr); err != nil {
+		clog.Errorf(ctx, "Stream limit reached err=%q", err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 
 	clog.V(common.VERBOSE).Infof(ctx, "Received segment dur=%v", segData.Duration)
 
@@ -118,7 +170,7 @@ func (h *lphttp) ServeSegment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	oInfo, err := orchestratorInfo(orch, sender, orch.ServiceURI().String())
+	oInfo, err := orchestratorInfo(orch, sender, orch.ServiceURI().String(), "")
 	if err != nil {
 		clog.Errorf(ctx, "Error updating orchestrator info - err=%q", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -222,8 +274,18 @@ func (h *lphttp) ServeSegment(w http.ResponseWriter, r *http.Request) {
 		segments = append(segments, d)
 	}
 
-	// Debit the fee for the total pixel count
-	orch.DebitFees(sender, core.ManifestID(segData.AuthToken.SessionId), payment.GetExpectedPrice(), pixels)
+	if len(segData.Profiles) == 0 && segData.DetectorEnabled {
+		// Debit the fee for the duration of detection-only segments, which is read from the segment itself rather than
+		// taken from the broadcaster when possible
+		duration, ok := core.SegmentDuration(data)
+		if !ok {
+			duration = segData.Duration
+		}
+		orch.DebitDetectionFees(sender, core.ManifestID(segData.AuthToken.SessionId), duration)
+	} else {
+		// Debit the fee for the total pixel count
+		orch.DebitFees(sender, core.ManifestID(segData.AuthToken.SessionId), payment.GetExpectedPrice(), pixels)
+	}
 	if monitor.Enabled {
 		monitor.MilPixelsProcessed(ctx, float64(pixels)/1000000.0)
 	}
@@ -279,6 +341,16 @@ func getPaymentSender(payment net.Payment) ethcommon.Address {
 func makeFfmpegVideoProfiles(protoProfiles []*net.VideoProfile) ([]ffmpeg.VideoProfile, error) {
 	profiles := make([]ffmpeg.VideoProfile, 0, len(protoProfiles))
 	for _, profile := range protoProfiles {
+		if profile == nil || profile.Width < 0 || profile.Height < 0 || profile.Bitrate < 0 {
+			return nil, errVideoProfile
+		}
+		if profile.FpsDen > 0 && profile.Fps == 0 {
+			return nil, errVideoProfile
+		}
+		// The name is used to build output file names
+		if strings.ContainsAny(profile.Name, `/\`) || strings.Contains(profile.Name, "..") {
+			return nil, errVideoProfile
+		}
 		name := profile.Name
 		if name == "" {
 			name = "net_" + ffmpeg.DefaultProfileName(int(profile.Width), int(profile.Height), int(profile.Bitrate))
@@ -362,6 +434,74 @@ func makeNetDetectData(ffmpegDetectData []ffmpeg.DetectData) []*net.DetectData {
 	return netDataList
 }
 
+// ServeKeepalive keeps the session identified by the auth token in the request body alive while the broadcaster
+// is not sending segments for it
+func (h *lphttp) ServeKeepalive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxKeepaliveSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var authToken net.AuthToken
+	if err := proto.Unmarshal(body, &authToken); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := verifyAuthToken(h.orchestrator, &authToken); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if err := h.orchestrator.KeepAlive(core.ManifestID(authToken.SessionId)); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// sendKeepalive notifies the orchestrator of sess that the session is still in use
+func sendKeepalive(ctx context.Context, sess *BroadcastSession) error {
+	sess.lock.RLock()
+	ti := sess.OrchestratorInfo
+	sess.lock.RUnlock()
+
+	if ti.GetAuthToken() == nil {
+		return nil
+	}
+
+	data, err := proto.Marshal(ti.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, core.KeepaliveInterval)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", ti.Transcoder+"/keepalive", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("keepalive failed code=%d err=%q", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
 func verifySegCreds(ctx context.Context, orch Orchestrator, segCreds string, broadcaster ethcommon.Address) (*core.SegTranscodingMetadata, context.Context, error) {
 	buf, err := base64.StdEncoding.DecodeString(segCreds)
 	if err != nil {
@@ -391,22 +531,11 @@ func verifySegCreds(ctx context.Context, orch Orchestrator, segCreds string, bro
 		return nil, ctx, errCapCompat
 	}
 
-	// Check that auth token is valid and not expired
-	if segData.AuthToken == nil {
-		return nil, ctx, errors.New("missing auth token")
-	}
-
-	verifyToken := orch.AuthToken(segData.AuthToken.SessionId, segData.AuthToken.Expiration)
-	if !bytes.Equal(verifyToken.Token, segData.AuthToken.Token) {
-		return nil, ctx, errors.New("invalid auth token")
+	if err := verifyAuthToken(orch, segData.AuthToken); err != nil {
+		return nil, ctx, err
 	}
 	ctx = clog.AddOrchSessionID(ctx, segData.AuthToken.SessionId)
 
-	expiration := time.Unix(segData.AuthToken.Expiration, 0)
-	if time.Now().After(expiration) {
-		return nil, ctx, errors.New("expired auth token")
-	}
-
 	if err := orch.CheckCapacity(core.ManifestID(segData.AuthToken.SessionId)); err != nil {
 		clog.Errorf(ctx, "Cannot process manifest err=%q", err)
 		return nil, ctx, err
@@ -442,9 +571,14 @@ func SubmitSegment(ctx context.Context, sess *BroadcastSession, seg *stream.HLSS
 	}
 
 	params := sess.Params
-	fee, err := estimateFee(seg, params.Profiles, priceInfo)
-	if err != nil {
-		return nil, err
+	var fee *big.Rat
+	if params.DetectionOnly() {
+		fee = detectionFee(seg, sess.OrchestratorInfo)
+	} else {
+		fee, err = estimateFee(seg, params.Profiles, priceInfo)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Create a BalanceUpdate to be completed when this function returns
@@ -470,12 +604,7 @@ func SubmitSegment(ctx context.Context, sess *BroadcastSession, seg *stream.HLSS
 	}
 
 	// timeout for the whole HTTP call: segment upload, transcoding, reading response
-	httpTimeout := common.HTTPTimeout
-	// set a minimum timeout to accommodate transport / processing overhead
-	paddedDur := segHttpPushTimeoutMultiplier * seg.Duration
-	if paddedDur > httpTimeout.Seconds() {
-		httpTimeout = time.Duration(paddedDur * float64(time.Second))
-	}
+	httpTimeout := segmentHTTPTimeout(seg)
 	// timeout for the segment upload, until HTTP returns OK 200
 	uploadTimeout := time.Duration(segUploadTimeoutMultiplier * seg.Duration * float64(time.Second))
 	if uploadTimeout <= 0 {
@@ -506,12 +635,16 @@ func SubmitSegment(ctx context.Context, sess *BroadcastSession, seg *stream.HLSS
 		req.Header.Set("Content-Type", "video/MP2T")
 	}
 
+	ex := newSegmentExchange(ti.Transcoder, req.Header, data, seg.Duration)
+	defer RPCRecord.Record(ex)
+
 	clog.Infof(ctx, "Submitting segment bytes=%v orch=%s timeout=%s uploadTimeout=%s segDur=%v",
 		len(data), ti.Transcoder, httpTimeout, uploadTimeout, seg.Duration)
 	start := time.Now()
 	resp, err := sendReqWithTimeout(req, uploadTimeout)
 	uploadDur := time.Since(start)
 	if err != nil {
+		ex.setResult(0, nil, err)
 		clog.Errorf(ctx, "Unable to submit segment orch=%v orch=%s uploadDur=%s err=%q", ti.Transcoder, ti.Transcoder, uploadDur, err)
 		if monitor.Enabled {
 			monitor.SegmentUploadFailed(ctx, nonce, seg.SeqNo, monitor.SegmentUploadErrorUnknown, err, false, sess.OrchestratorInfo.Transcoder)
@@ -531,6 +664,7 @@ func SubmitSegment(ctx context.Context, sess *BroadcastSession, seg *stream.HLSS
 	if resp.StatusCode != 200 {
 		data, _ := ioutil.ReadAll(resp.Body)
 		errorString := strings.TrimSpace(string(data))
+		ex.setResult(resp.StatusCode, nil, errors.New(errorString))
 		clog.Errorf(ctx, "Error submitting segment code=%d orch=%s err=%q", resp.StatusCode, ti.Transcoder, string(data))
 		if monitor.Enabled {
 			if resp.StatusCode == 403 && strings.Contains(errorString, "OrchestratorCapped") {
@@ -540,7 +674,7 @@ func SubmitSegment(ctx context.Context, sess *BroadcastSession, seg *stream.HLSS
 					fmt.Errorf("Code: %d Error: %s", resp.StatusCode, errorString), false, sess.OrchestratorInfo.Transcoder)
 			}
 		}
-		return nil, fmt.Errorf(errorString)
+		return nil, remoteError(errorString)
 	}
 	clog.Infof(ctx, "Uploaded segment orch=%s dur=%s", ti.Transcoder, uploadDur)
 	if monitor.Enabled {
@@ -549,6 +683,7 @@ func SubmitSegment(ctx context.Context, sess *BroadcastSession, seg *stream.HLSS
 
 	data, err = ioutil.ReadAll(resp.Body)
 	tookAllDur := time.Since(start)
+	ex.setResult(resp.StatusCode, data, err)
 
 	if err != nil {
 		clog.Errorf(ctx, "Unable to read response body for segment orch=%s err=%q", ti.Transcoder, err)
@@ -573,7 +708,7 @@ func SubmitSegment(ctx context.Context, sess *BroadcastSession, seg *stream.HLSS
 	var tdata *net.TranscodeData
 	switch res := tr.Result.(type) {
 	case *net.TranscodeResult_Error:
-		err = fmt.Errorf(res.Error)
+		err = remoteError(res.Error)
 		clog.Errorf(ctx, "Transcode failed for segment orch=%s err=%q", ti.Transcoder, err)
 		if err.Error() == "MediaStats Failure" {
 			clog.Infof(ctx, "Ensure the keyframe interval is 4 seconds or less")
@@ -603,7 +738,10 @@ func SubmitSegment(ctx context.Context, sess *BroadcastSession, seg *stream.HLSS
 
 	// We treat a response as "receiving change" where the change is the difference between the credit and debit for the update
 	balUpdate.Status = ReceivedChange
-	if priceInfo != nil {
+	if params.DetectionOnly() {
+		// The update's debit is the detection fee for the duration of the segment
+		balUpdate.Debit = detectionFee(seg, sess.OrchestratorInfo)
+	} else if priceInfo != nil {
 		// The update's debit is the transcoding fee which is computed as the total number of pixels processed
 		// for all results returned multiplied by the orchestrator's price
 		var pixelCount int64
@@ -634,6 +772,37 @@ func SubmitSegment(ctx context.Context, sess *BroadcastSession, seg *stream.HLSS
 	}, nil
 }
 
+// verifyAuthToken checks that an auth token was issued by orch and is not expired
+func verifyAuthToken(orch Orchestrator, authToken *net.AuthToken) error {
+	if authToken == nil {
+		return errors.New("missing auth token")
+	}
+
+	verifyToken := orch.AuthToken(authToken.SessionId, authToken.Expiration)
+	if !bytes.Equal(verifyToken.Token, authToken.Token) {
+		return errors.New("invalid auth token")
+	}
+
+	expiration := time.Unix(authToken.Expiration, 0)
+	if time.Now().After(expiration) {
+		return errors.New("expired auth token")
+	}
+
+	return nil
+}
+
+// segmentHTTPTimeout returns the time that the broadcaster waits for the transcoded seg to be returned after it
+// starts to submit seg, which is also the deadline that orchestrators and transcoders have to meet
+func segmentHTTPTimeout(seg *stream.HLSSegment) time.Duration {
+	httpTimeout := common.HTTPTimeout
+	// set a minimum timeout to accommodate transport / processing overhead
+	paddedDur := segHttpPushTimeoutMultiplier * seg.Duration
+	if paddedDur > httpTimeout.Seconds() {
+		httpTimeout = time.Duration(paddedDur * float64(time.Second))
+	}
+	return httpTimeout
+}
+
 func genSegCreds(sess *BroadcastSession, seg *stream.HLSSegment, calcPerceptualHash bool) (string, error) {
 
 	// Send credentials for our own storage
@@ -658,7 +827,7 @@ func genSegCreds(sess *BroadcastSession, seg *stream.HLSSegment, calcPerceptualH
 		ManifestID:         params.ManifestID,
 		Seq:                int64(seg.SeqNo),
 		Hash:               ethcommon.BytesToHash(hash),
-		Profiles:           params.Profiles,
+		Profiles:           capProfileBitrates(params.Profiles, seg),
 		OS:                 storage,
 		Duration:           time.Duration(seg.Duration * float64(time.Second)),
 		Caps:               params.Capabilities,
@@ -666,6 +835,8 @@ func genSegCreds(sess *BroadcastSession, seg *stream.HLSSegment, calcPerceptualH
 		DetectorEnabled:    detectorEnabled,
 		DetectorProfiles:   detectorProfiles,
 		CalcPerceptualHash: calcPerceptualHash,
+		TimeToDeadline:     segmentHTTPTimeout(seg),
+		Filters:            params.Filters,
 	}
 	sig, err := sess.Broadcaster.Sign(md.Flatten())
 	if err != nil {
@@ -687,6 +858,26 @@ func genSegCreds(sess *BroadcastSession, seg *stream.HLSSegment, calcPerceptualH
 	return base64.StdEncoding.EncodeToString(data), nil
 }
 
+// capProfileBitrates returns profiles with their bitrates capped at the bitrate of seg multiplied by SourceBitrateFactor.
+// The bitrate of seg is estimated from its size and duration
+func capProfileBitrates(profiles []ffmpeg.VideoProfile, seg *stream.HLSSegment) []ffmpeg.VideoProfile {
+	if SourceBitrateFactor <= 0 || seg.Duration <= 0 || len(seg.Data) == 0 {
+		return profiles
+	}
+
+	maxBitrate := int(float64(len(seg.Data)*8) / seg.Duration * SourceBitrateFactor)
+	capped := make([]ffmpeg.VideoProfile, len(profiles))
+	for i, p := range profiles {
+		capped[i] = p
+		bitrate, err := strconv.Atoi(strings.Replace(p.Bitrate, "k", "000", 1))
+		if err != nil || bitrate <= maxBitrate {
+			continue
+		}
+		capped[i].Bitrate = strconv.Itoa(maxBitrate)
+	}
+	return capped
+}
+
 func estimateFee(seg *stream.HLSSegment, profiles []ffmpeg.VideoProfile, priceInfo *big.Rat) (*big.Rat, error) {
 	if priceInfo == nil {
 		return nil, nil
@@ -725,6 +916,12 @@ func estimateFee(seg *stream.HLSSegment, profiles []ffmpeg.VideoProfile, priceIn
 	return fee, nil
 }
 
+// detectionFee returns the fee charged by the orchestrator to run detection on a segment without renditions
+func detectionFee(seg *stream.HLSSegment, info *net.OrchestratorInfo) *big.Rat {
+	price := big.NewInt(info.GetDetectionPricePerSecond())
+	return core.DetectionFee(price, time.Duration(seg.Duration*float64(time.Second)))
+}
+
 func newBalanceUpdate(sess *BroadcastSession, minCredit *big.Rat) (*BalanceUpdate, error) {
 	update := &BalanceUpdate{
 		ExistingCredit: big.NewRat(0, 1),
@@ -801,6 +998,11 @@ func genPayment(ctx context.Context, sess *BroadcastSession, numTickets int) (st
 			return "", err
 		}
 
+		ev := new(big.Rat).Mul(batch.WinProbRat(), new(big.Rat).SetInt(batch.FaceValue))
+		ev.Mul(ev, big.NewRat(int64(numTickets), 1))
+		BroadcastBudget.spend(ev, time.Now())
+		BroadcastFunds.spend(ev, time.Now())
+
 		protoPayment.TicketParams = &net.TicketParams{
 			Recipient:         batch.Recipient.Bytes(),
 			FaceValue:         batch.FaceValue.Bytes(),
@@ -858,6 +1060,15 @@ func validatePrice(sess *BroadcastSession) error {
 	if maxPrice != nil && oPrice.Cmp(maxPrice) == 1 {
 		return fmt.Errorf("Orchestrator price higher than the set maximum price of %v wei per %v pixels", maxPrice.Num().Int64(), maxPrice.Denom().Int64())
 	}
+	if streamMax := sess.Params.MaxPrice; streamMax != nil && oPrice.Cmp(streamMax) == 1 {
+		return fmt.Errorf("Orchestrator price higher than the maximum price of the stream of %v wei per %v pixels", streamMax.Num().Int64(), streamMax.Denom().Int64())
+	}
+	if sess.Params.DetectionOnly() {
+		maxDetectionPrice := BroadcastCfg.MaxDetectionPrice()
+		if maxDetectionPrice != nil && big.NewInt(sess.OrchestratorInfo.GetDetectionPricePerSecond()).Cmp(maxDetectionPrice) == 1 {
+			return fmt.Errorf("Orchestrator detection price higher than the set maximum detection price of %v wei per second", maxDetectionPrice)
+		}
+	}
 	return nil
 }
 
@@ -865,6 +1076,13 @@ func sendReqWithTimeout(req *http.Request, timeout time.Duration) (*http.Respons
 	ctx, cancel := context.WithCancel(req.Context())
 	timeouter := time.AfterFunc(timeout, cancel)
 
+	if delay := common.InjectedDelay(common.FaultRPCDelay); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
 	req = req.WithContext(ctx)
 	resp, err := httpClient.Do(req)
 	if timeouter.Stop() {
@@ -86,6 +86,7 @@ func TestServeSegment_MismatchHashError(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -121,6 +122,74 @@ func TestServeSegment_MismatchHashError(t *testing.T) {
 	assert.Equal("Forbidden", strings.TrimSpace(string(body)))
 }
 
+func TestServeSegment_StreamLimitError(t *testing.T) {
+	orch := newStubOrchestrator()
+	orch.offchain = true
+	orch.streamLimitErr = core.ErrStreamLimit
+	handler := serveSegmentHandler(orch)
+
+	s := &BroadcastSession{
+		Broadcaster: stubBroadcaster2(),
+		Params: &core.StreamParameters{
+			ManifestID: core.RandomManifestID(),
+			Profiles:   []ffmpeg.VideoProfile{ffmpeg.P720p30fps16x9},
+		},
+		OrchestratorInfo: &net.OrchestratorInfo{AuthToken: stubAuthToken},
+	}
+	creds, err := genSegCreds(s, &stream.HLSSegment{}, false)
+	require.Nil(t, err)
+
+	headers := map[string]string{
+		paymentHeader: "",
+		segmentHeader: creds,
+	}
+	resp := httpPostResp(handler, bytes.NewReader([]byte("foo")), headers)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.Nil(t, err)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusForbidden, resp.StatusCode)
+	assert.Equal(core.ErrStreamLimit.Error(), strings.TrimSpace(string(body)))
+	// The broadcaster retries the segment with another orchestrator
+	assert.Equal(core.ErrStreamLimit, remoteError(strings.TrimSpace(string(body))))
+}
+
+func TestServeSegment_MaintenanceError(t *testing.T) {
+	orch := newStubOrchestrator()
+	orch.offchain = true
+	orch.maintenanceErr = core.ErrOrchMaintenance
+	handler := serveSegmentHandler(orch)
+
+	s := &BroadcastSession{
+		Broadcaster: stubBroadcaster2(),
+		Params: &core.StreamParameters{
+			ManifestID: core.RandomManifestID(),
+			Profiles:   []ffmpeg.VideoProfile{ffmpeg.P720p30fps16x9},
+		},
+		OrchestratorInfo: &net.OrchestratorInfo{AuthToken: stubAuthToken},
+	}
+	creds, err := genSegCreds(s, &stream.HLSSegment{}, false)
+	require.Nil(t, err)
+
+	headers := map[string]string{
+		paymentHeader: "",
+		segmentHeader: creds,
+	}
+	resp := httpPostResp(handler, bytes.NewReader([]byte("foo")), headers)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.Nil(t, err)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	// The broadcaster moves the stream to another orchestrator
+	assert.Equal(core.ErrOrchMaintenance, remoteError(strings.TrimSpace(string(body))))
+	assert.True(shouldStopSession(remoteError(strings.TrimSpace(string(body)))))
+}
+
 func TestServeSegment_TranscodeSegError(t *testing.T) {
 	orch := &mockOrchestrator{}
 	handler := serveSegmentHandler(orch)
@@ -129,6 +198,7 @@ func TestServeSegment_TranscodeSegError(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -220,6 +290,39 @@ func TestVerifySegCreds_Duration(t *testing.T) {
 	assert.Nil(md)
 }
 
+func TestSegCreds_TimeToDeadline(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	orch := &stubOrchestrator{offchain: true}
+
+	// The deadline is when the broadcaster stops waiting for the results
+	sess := &BroadcastSession{
+		Broadcaster:      stubBroadcaster2(),
+		Params:           &core.StreamParameters{ManifestID: core.RandomManifestID(), Profiles: []ffmpeg.VideoProfile{ffmpeg.P144p30fps16x9}},
+		OrchestratorInfo: &net.OrchestratorInfo{AuthToken: stubAuthToken},
+	}
+	creds, err := genSegCreds(sess, &stream.HLSSegment{Duration: 10}, false)
+	require.Nil(err)
+	md, _, err := verifySegCreds(context.TODO(), orch, creds, ethcommon.Address{})
+	require.Nil(err)
+	assert.Equal(40*time.Second, md.TimeToDeadline)
+
+	creds, err = genSegCreds(sess, &stream.HLSSegment{Duration: 1}, false)
+	require.Nil(err)
+	md, _, err = verifySegCreds(context.TODO(), orch, creds, ethcommon.Address{})
+	require.Nil(err)
+	assert.Equal(common.HTTPTimeout, md.TimeToDeadline)
+
+	// No deadline
+	md, err = coreSegMetadata(&net.SegData{AuthToken: stubAuthToken})
+	require.Nil(err)
+	assert.Zero(md.TimeToDeadline)
+
+	md, err = coreSegMetadata(&net.SegData{TimeToDeadline: -1, AuthToken: stubAuthToken})
+	assert.Equal(errTimeToDeadline, err)
+	assert.Nil(md)
+}
+
 func TestCoreSegMetadata_Profiles(t *testing.T) {
 	assert := assert.New(t)
 	// testing with the following profiles doesn't work: ffmpeg.P720p60fps16x9, ffmpeg.P144p25fps16x9
@@ -368,6 +471,54 @@ func TestGenSegCreds_FullProfiles(t *testing.T) {
 	assert.Equal(common.ErrFormatProto, err)
 }
 
+func TestCapProfileBitrates(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func() { SourceBitrateFactor = 0 }()
+	profiles := []ffmpeg.VideoProfile{
+		{Name: "prof1", Bitrate: "400k", Resolution: "426x240"},
+		{Name: "prof2", Bitrate: "6000000", Resolution: "1920x1080"},
+		{Name: "prof3", Bitrate: "foo", Resolution: "1280x720"},
+	}
+	// 2s segment with a bitrate of 1000kbps
+	seg := &stream.HLSSegment{Data: make([]byte, 250000), Duration: 2}
+
+	// Disabled by default
+	assert.Equal(profiles, capProfileBitrates(profiles, seg))
+
+	SourceBitrateFactor = 1.5
+	capped := capProfileBitrates(profiles, seg)
+	assert.Equal("400k", capped[0].Bitrate)
+	assert.Equal("1500000", capped[1].Bitrate)
+	assert.Equal("foo", capped[2].Bitrate)
+	for i := range profiles {
+		assert.Equal(profiles[i].Name, capped[i].Name)
+		assert.Equal(profiles[i].Resolution, capped[i].Resolution)
+	}
+	// The stream profiles are not modified
+	assert.Equal("6000000", profiles[1].Bitrate)
+
+	// The bitrate can't be estimated without a duration
+	assert.Equal(profiles, capProfileBitrates(profiles, &stream.HLSSegment{Data: make([]byte, 250000)}))
+
+	// The capped profiles are sent to the orchestrator
+	s := &BroadcastSession{
+		Broadcaster: stubBroadcaster2(),
+		Params: &core.StreamParameters{
+			ManifestID: core.RandomManifestID(),
+			Profiles:   profiles[:2],
+		},
+	}
+	data, err := genSegCreds(s, seg, false)
+	assert.Nil(err)
+	buf, err := base64.StdEncoding.DecodeString(data)
+	assert.Nil(err)
+	segData := net.SegData{}
+	assert.Nil(proto.Unmarshal(buf, &segData))
+	assert.Equal(int32(400000), segData.FullProfiles[0].Bitrate)
+	assert.Equal(int32(1500000), segData.FullProfiles[1].Bitrate)
+}
+
 func TestGenSegCreds_Profiles(t *testing.T) {
 	assert := assert.New(t)
 	profiles := []ffmpeg.VideoProfile{ffmpeg.P720p60fps16x9, ffmpeg.P360p30fps16x9}
@@ -560,6 +711,17 @@ func TestCoreSegMetadata_Detection(t *testing.T) {
 	md, err = coreSegMetadata(segData)
 	assert.Nil(err)
 	assert.False(md.DetectorEnabled)
+
+	// Unknown or empty detector profiles are rejected
+	segData.DetectorProfiles = []*net.DetectorProfile{{}}
+	md, err = coreSegMetadata(segData)
+	assert.Nil(md)
+	assert.Equal(errDetectorProfile, err)
+
+	segData.DetectorProfiles = []*net.DetectorProfile{{Value: &net.DetectorProfile_SceneClassification{}}}
+	md, err = coreSegMetadata(segData)
+	assert.Nil(md)
+	assert.Equal(errDetectorProfile, err)
 }
 
 func TestMakeFfmpegVideoProfiles(t *testing.T) {
@@ -637,6 +799,22 @@ func TestMakeFfmpegVideoProfiles(t *testing.T) {
 	ffmpegProfiles, err = makeFfmpegVideoProfiles(videoProfiles)
 	assert.Nil(ffmpegProfiles)
 	assert.Equal(errFormat, err)
+
+	// Malformed profiles should return error instead of being partially accepted
+	invalidProfiles := []*net.VideoProfile{
+		nil,
+		{Name: "neg", Width: -1, Height: 2},
+		{Name: "neg", Width: 1, Height: -2},
+		{Name: "neg", Width: 1, Height: 2, Bitrate: -3},
+		{Name: "fps", Width: 1, Height: 2, FpsDen: 2},
+		{Name: "../prof", Width: 1, Height: 2},
+		{Name: "a/b", Width: 1, Height: 2},
+	}
+	for _, p := range invalidProfiles {
+		ffmpegProfiles, err = makeFfmpegVideoProfiles([]*net.VideoProfile{p})
+		assert.Nil(ffmpegProfiles)
+		assert.Equal(errVideoProfile, err)
+	}
 }
 
 func TestServeSegment_SaveDataFormat(t *testing.T) {
@@ -737,6 +915,7 @@ func TestServeSegment_OSSaveDataError(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -808,6 +987,7 @@ func TestServeSegment_ReturnSingleTranscodedSegmentData(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -875,6 +1055,7 @@ func TestServeSegment_ReturnMultipleTranscodedSegmentData(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -943,6 +1124,7 @@ func TestServeSegment_TooBigSegment(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -1001,6 +1183,7 @@ func TestServeSegment_ProcessPaymentError(t *testing.T) {
 	assert := assert.New(t)
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -1062,6 +1245,7 @@ func TestServeSegment_UpdateOrchestratorInfo(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", oldAuthToken.SessionId, oldAuthToken.Expiration).Return(oldAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -1127,6 +1311,7 @@ func TestServeSegment_UpdateOrchestratorInfo(t *testing.T) {
 	// when the mocked AuthToken is called. 1 second would need to elapse which should only really happen if the test
 	// is run in a really slow environment
 	orch.On("AuthToken", newAuthToken.SessionId, newAuthToken.Expiration).Return(newAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	headers := map[string]string{
 		paymentHeader: paymentString,
@@ -1193,6 +1378,7 @@ func TestServeSegment_UpdateOrchestratorInfo_WebhookCache_PriceInfo(t *testing.T
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", oldAuthToken.SessionId, oldAuthToken.Expiration).Return(oldAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -1266,6 +1452,7 @@ func TestServeSegment_UpdateOrchestratorInfo_WebhookCache_PriceInfo(t *testing.T
 	// when the mocked AuthToken is called. 1 second would need to elapse which should only really happen if the test
 	// is run in a really slow environment
 	orch.On("AuthToken", newAuthToken.SessionId, newAuthToken.Expiration).Return(newAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	headers := map[string]string{
 		paymentHeader: paymentString,
@@ -1307,6 +1494,7 @@ func TestServeSegment_InsufficientBalance(t *testing.T) {
 	assert := assert.New(t)
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -1371,6 +1559,7 @@ func TestServeSegment_DebitFees_SingleRendition(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -1440,6 +1629,7 @@ func TestServeSegment_DebitFees_MultipleRenditions(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -1519,6 +1709,7 @@ func TestServeSegment_DebitFees_OSSaveDataError_BreakLoop(t *testing.T) {
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -1601,6 +1792,7 @@ func TestServeSegment_DebitFees_TranscodeSegError_ZeroPixelsBilled(t *testing.T)
 
 	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
 	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
 
 	s := &BroadcastSession{
 		Broadcaster: stubBroadcaster2(),
@@ -1652,6 +1844,92 @@ func TestServeSegment_DebitFees_TranscodeSegError_ZeroPixelsBilled(t *testing.T)
 	orch.AssertCalled(t, "DebitFees", mock.Anything, core.ManifestID(s.OrchestratorInfo.AuthToken.SessionId), mock.Anything, int64(0))
 }
 
+func TestServeSegment_DebitDetectionFees(t *testing.T) {
+	// Segments that can't be decoded are charged for the duration claimed by the broadcaster
+	testServeSegmentDebitDetectionFees(t, []byte("foo"), 2500*time.Millisecond)
+}
+
+func TestServeSegment_DebitDetectionFees_DecodedDuration(t *testing.T) {
+	// The duration of MPEG-TS segments is read from their timestamps rather than taken from the broadcaster
+	data, err := ioutil.ReadFile("../core/test.ts")
+	require.Nil(t, err)
+	testServeSegmentDebitDetectionFees(t, data, 8682*time.Millisecond)
+}
+
+func testServeSegmentDebitDetectionFees(t *testing.T, data []byte, duration time.Duration) {
+	orch := &mockOrchestrator{}
+	handler := serveSegmentHandler(orch)
+
+	require := require.New(t)
+
+	orch.On("VerifySig", mock.Anything, mock.Anything, mock.Anything).Return(true)
+	orch.On("AuthToken", mock.Anything, mock.Anything).Return(stubAuthToken)
+	orch.On("Sign", mock.Anything).Return(nil, nil)
+
+	s := &BroadcastSession{
+		Broadcaster: stubBroadcaster2(),
+		Params: &core.StreamParameters{
+			ManifestID: core.RandomManifestID(),
+			Detection: core.DetectionConfig{
+				Freq:     1,
+				Profiles: []ffmpeg.DetectorProfile{&ffmpeg.SceneClassificationProfile{SampleRate: 10}},
+			},
+		},
+		OrchestratorInfo: &net.OrchestratorInfo{AuthToken: stubAuthToken},
+	}
+	seg := &stream.HLSSegment{Data: data, Duration: 2.5}
+	creds, err := genSegCreds(s, seg, false)
+	require.Nil(err)
+
+	md, _, err := verifySegCreds(context.TODO(), orch, creds, ethcommon.Address{})
+	require.Nil(err)
+	drivers.NodeStorage = drivers.NewMemoryDriver(nil)
+	url, _ := url.Parse("foo")
+	orch.On("ServiceURI").Return(url)
+	orch.On("Address").Return(ethcommon.Address{})
+	orch.On("PriceInfo", mock.Anything).Return(&net.PriceInfo{}, nil)
+	orch.On("TicketParams", mock.Anything, mock.Anything).Return(&net.TicketParams{}, nil)
+	orch.On("ProcessPayment", net.Payment{}, core.ManifestID(s.OrchestratorInfo.AuthToken.SessionId)).Return(nil)
+	orch.On("SufficientBalance", mock.Anything, core.ManifestID(s.OrchestratorInfo.AuthToken.SessionId)).Return(true)
+
+	// Detection-only segments return detections without any renditions
+	tData := &core.TranscodeData{
+		Pixels:     int64(110592000),
+		Detections: []ffmpeg.DetectData{ffmpeg.SceneClassificationData{1: 0.8}},
+	}
+	tRes := &core.TranscodeResult{
+		TranscodeData: tData,
+		Sig:           []byte("foo"),
+		OS:            drivers.NewMemoryDriver(nil).NewSession(""),
+	}
+	orch.On("TranscodeSeg", md, mock.Anything).Return(tRes, nil)
+	orch.On("DebitDetectionFees", mock.Anything, core.ManifestID(s.OrchestratorInfo.AuthToken.SessionId), duration)
+
+	headers := map[string]string{
+		paymentHeader: "",
+		segmentHeader: creds,
+	}
+	resp := httpPostResp(handler, bytes.NewReader(seg.Data), headers)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.Nil(err)
+
+	var tr net.TranscodeResult
+	err = proto.Unmarshal(body, &tr)
+	require.Nil(err)
+
+	assert := assert.New(t)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+
+	res, ok := tr.Result.(*net.TranscodeResult_Data)
+	require.True(ok)
+	assert.Empty(res.Data.Segments)
+	assert.Len(res.Data.Detections, 1)
+	orch.AssertCalled(t, "DebitDetectionFees", mock.Anything, core.ManifestID(s.OrchestratorInfo.AuthToken.SessionId), duration)
+	orch.AssertNotCalled(t, "DebitFees", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestSubmitSegment_GenSegCredsError(t *testing.T) {
 	b := stubBroadcaster2()
 	b.signErr = errors.New("Sign error")
@@ -2297,3 +2575,69 @@ func stubTLSServer() (*httptest.Server, *http.ServeMux) {
 
 	return ts, mux
 }
+
+func TestServeKeepalive(t *testing.T) {
+	assert := assert.New(t)
+
+	orch := newStubOrchestrator()
+	lp := lphttp{orchestrator: orch}
+	handler := http.HandlerFunc(lp.ServeKeepalive)
+
+	tokenData, err := proto.Marshal(stubAuthToken)
+	require.Nil(t, err)
+
+	// Only POST is allowed
+	resp := httpGetResp(handler)
+	assert.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+
+	// Invalid body
+	resp = httpPostResp(handler, bytes.NewReader([]byte{0xff, 0xff}), nil)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	// Invalid auth token
+	invalidToken, err := proto.Marshal(&net.AuthToken{Token: []byte("bar"), SessionId: "bar", Expiration: stubAuthToken.Expiration})
+	require.Nil(t, err)
+	resp = httpPostResp(handler, bytes.NewReader(invalidToken), nil)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(http.StatusForbidden, resp.StatusCode)
+	assert.Equal("invalid auth token", strings.TrimSpace(string(body)))
+
+	// Session does not exist
+	orch.keepAliveErr = core.ErrSessionNotFound
+	resp = httpPostResp(handler, bytes.NewReader(tokenData), nil)
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+
+	orch.keepAliveErr = nil
+	resp = httpPostResp(handler, bytes.NewReader(tokenData), nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func TestSendKeepalive(t *testing.T) {
+	assert := assert.New(t)
+
+	orch := newStubOrchestrator()
+	lp := lphttp{orchestrator: orch}
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		lp.ServeKeepalive(w, r)
+	}))
+	defer ts.Close()
+
+	sess := &BroadcastSession{
+		lock:             &sync.RWMutex{},
+		OrchestratorInfo: &net.OrchestratorInfo{Transcoder: ts.URL, AuthToken: stubAuthToken},
+	}
+
+	assert.Nil(sendKeepalive(context.Background(), sess))
+	assert.Equal([]string{"/keepalive"}, paths)
+
+	orch.keepAliveErr = core.ErrSessionNotFound
+	err := sendKeepalive(context.Background(), sess)
+	assert.EqualError(err, `keepalive failed code=404 err="session not found"`)
+
+	// Nothing is sent without an auth token
+	sess.OrchestratorInfo = &net.OrchestratorInfo{Transcoder: ts.URL}
+	assert.Nil(sendKeepalive(context.Background(), sess))
+	assert.Len(paths, 2)
+}
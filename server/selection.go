@@ -8,8 +8,13 @@ import (
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/livepeer/go-livepeer/clog"
 	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/policy"
 )
 
+// SelectionPolicy selects the orchestrators for segments instead of the default selection if set
+var SelectionPolicy policy.SelectionPolicy
+
 // BroadcastSessionsSelector selects the next BroadcastSession to use
 type BroadcastSessionsSelector interface {
 	Add(sessions []*BroadcastSession)
@@ -277,3 +282,86 @@ func (s *LIFOSelector) Size() int {
 func (s *LIFOSelector) Clear() {
 	*s = nil
 }
+
+// PolicySelector selects sessions with a selection policy supplied by the operator. It keeps its sessions in a
+// MinLSSelector which is used if the policy fails, times out or defers the decision
+type PolicySelector struct {
+	*MinLSSelector
+
+	policy policy.SelectionPolicy
+	mid    core.ManifestID
+}
+
+// NewPolicySelector returns a selector for the sessions of the stream mid that uses sel as the fallback
+func NewPolicySelector(p policy.SelectionPolicy, mid core.ManifestID, sel *MinLSSelector) *PolicySelector {
+	return &PolicySelector{MinLSSelector: sel, policy: p, mid: mid}
+}
+
+// Select returns the session selected by the policy
+func (s *PolicySelector) Select(ctx context.Context) *BroadcastSession {
+	n := len(s.unknownSessions)
+	if n+s.knownSessions.Len() == 0 {
+		return nil
+	}
+
+	sessions := append(append([]*BroadcastSession{}, s.unknownSessions...), *s.knownSessions...)
+	req := &policy.SelectRequest{ManifestID: string(s.mid), Candidates: s.candidates(ctx, sessions, n)}
+	pctx, cancel := context.WithTimeout(ctx, policy.Timeout)
+	defer cancel()
+	res, err := s.policy.Select(pctx, req)
+	if err != nil {
+		clog.Errorf(ctx, "Selection policy failed, using the default selection err=%q", err)
+		return s.MinLSSelector.Select(ctx)
+	}
+	if res.Index < 0 || res.Index >= len(sessions) {
+		if res.Index >= len(sessions) {
+			clog.Errorf(ctx, "Selection policy returned invalid index=%d candidates=%d, using the default selection", res.Index, len(sessions))
+		}
+		return s.MinLSSelector.Select(ctx)
+	}
+
+	if res.Index < n {
+		s.removeUnknownSession(res.Index)
+	} else {
+		heap.Remove(s.knownSessions, res.Index-n)
+	}
+	return sessions[res.Index]
+}
+
+// candidates describes the sessions to the policy. The first numUnknown sessions do not have a latency score yet
+func (s *PolicySelector) candidates(ctx context.Context, sessions []*BroadcastSession, numUnknown int) []policy.Candidate {
+	var stakes map[ethcommon.Address]int64
+	if s.stakeRdr != nil {
+		var addrs []ethcommon.Address
+		for _, sess := range sessions {
+			if sess.OrchestratorInfo.GetTicketParams() != nil {
+				addrs = append(addrs, ethcommon.BytesToAddress(sess.OrchestratorInfo.TicketParams.Recipient))
+			}
+		}
+		var err error
+		if stakes, err = s.stakeRdr.Stakes(addrs); err != nil {
+			clog.Errorf(ctx, "failed to read stake weights for selection policy err=%q", err)
+		}
+	}
+
+	candidates := make([]policy.Candidate, len(sessions))
+	for i, sess := range sessions {
+		info := sess.OrchestratorInfo
+		c := policy.Candidate{
+			Transcoder:   info.GetTranscoder(),
+			LatencyScore: sess.LatencyScore,
+			Scored:       i >= numUnknown,
+			Trusted:      sess.OrchestratorScore == common.Score_Trusted,
+		}
+		if info.GetTicketParams() != nil {
+			addr := ethcommon.BytesToAddress(info.TicketParams.Recipient)
+			c.Address = addr.Hex()
+			c.Stake = stakes[addr]
+		}
+		if pi := info.GetPriceInfo(); pi != nil {
+			c.Price = &policy.Price{PricePerUnit: pi.PricePerUnit, PixelsPerUnit: pi.PixelsPerUnit}
+		}
+		candidates[i] = c
+	}
+	return candidates
+}
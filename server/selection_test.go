@@ -16,6 +16,7 @@ import (
 	"github.com/livepeer/go-livepeer/common"
 	"github.com/livepeer/go-livepeer/core"
 	"github.com/livepeer/go-livepeer/net"
+	"github.com/livepeer/go-livepeer/policy"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -560,3 +561,73 @@ func TestMinLSSelector_RemoveUnknownSession(t *testing.T) {
 	sel.removeUnknownSession(0)
 	assert.Empty(sel.unknownSessions)
 }
+
+type stubSelectionPolicy struct {
+	req *policy.SelectRequest
+	res *policy.SelectResponse
+	err error
+}
+
+func (p *stubSelectionPolicy) Select(ctx context.Context, req *policy.SelectRequest) (*policy.SelectResponse, error) {
+	p.req = req
+	return p.res, p.err
+}
+
+func TestPolicySelector(t *testing.T) {
+	assert := assert.New(t)
+
+	stakeRdr := newStubStakeReader()
+	addrs := []ethcommon.Address{ethcommon.BytesToAddress([]byte("foo")), ethcommon.BytesToAddress([]byte("bar"))}
+	stakeRdr.SetStakes(map[ethcommon.Address]int64{addrs[0]: 100, addrs[1]: 200})
+
+	unknown := &BroadcastSession{OrchestratorInfo: &net.OrchestratorInfo{
+		Transcoder:   "https://foo.com",
+		TicketParams: &net.TicketParams{Recipient: addrs[0].Bytes()},
+		PriceInfo:    &net.PriceInfo{PricePerUnit: 1, PixelsPerUnit: 2},
+	}}
+	known := &BroadcastSession{
+		OrchestratorInfo:  &net.OrchestratorInfo{Transcoder: "https://bar.com", TicketParams: &net.TicketParams{Recipient: addrs[1].Bytes()}},
+		OrchestratorScore: common.Score_Trusted,
+		LatencyScore:      0.5,
+	}
+
+	p := &stubSelectionPolicy{res: &policy.SelectResponse{Index: 1}}
+	sel := NewPolicySelector(p, core.ManifestID("mid"), NewMinLSSelector(stakeRdr, 1.0))
+
+	// Return nil when there are no sessions
+	assert.Nil(sel.Select(context.TODO()))
+	assert.Nil(p.req)
+
+	sel.Add([]*BroadcastSession{unknown})
+	sel.Complete(known)
+	assert.Equal(2, sel.Size())
+
+	// The policy selects the known session
+	assert.Equal(known, sel.Select(context.TODO()))
+	assert.Equal(1, sel.Size())
+	assert.Equal("mid", p.req.ManifestID)
+	assert.Equal([]policy.Candidate{
+		{Transcoder: "https://foo.com", Address: addrs[0].Hex(), Price: &policy.Price{PricePerUnit: 1, PixelsPerUnit: 2}, Stake: 100},
+		{Transcoder: "https://bar.com", Address: addrs[1].Hex(), LatencyScore: 0.5, Scored: true, Trusted: true, Stake: 200},
+	}, p.req.Candidates)
+
+	// The policy selects the unknown session
+	sel.Complete(known)
+	p.res = &policy.SelectResponse{Index: 0}
+	assert.Equal(unknown, sel.Select(context.TODO()))
+	assert.Equal(1, sel.Size())
+	assert.Empty(sel.unknownSessions)
+
+	// Fall back to the default selection if the policy fails, defers or returns an invalid index
+	for _, fallback := range []*stubSelectionPolicy{
+		{err: errors.New("policy error")},
+		{res: &policy.SelectResponse{Index: -1}},
+		{res: &policy.SelectResponse{Index: 2}},
+	} {
+		sel.policy = fallback
+		assert.Equal(known, sel.Select(context.TODO()))
+		assert.NotNil(fallback.req)
+		assert.Zero(sel.Size())
+		sel.Complete(known)
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/livepeer/go-livepeer/monitor"
+)
+
+// StreamEventsRetention is how long the events of streams are kept in the DB. The stream event log is disabled if 0
+var StreamEventsRetention time.Duration
+
+// streamEventsCleanupInterval is how often the events older than StreamEventsRetention are deleted
+var streamEventsCleanupInterval = time.Hour
+
+// Types of the events in the stream event log
+const (
+	streamEventIngestStarted         = "ingest_started"
+	streamEventIngestSuspended       = "ingest_suspended"
+	streamEventIngestResumed         = "ingest_resumed"
+	streamEventIngestSwitched        = "ingest_switched"
+	streamEventOrchSelected          = "orchestrator_selected"
+	streamEventOrchSwitched          = "orchestrator_switched"
+	streamEventSegmentFailed         = "segment_failed"
+	streamEventVerification          = "verification"
+	streamEventLatencyBudgetExceeded = "latency_budget_exceeded"
+	streamEventStreamEnded           = "stream_ended"
+)
+
+// streamEventLog persists the timeline of a stream in the DB for post-incident analysis. A nil streamEventLog does
+// not record any events
+type streamEventLog struct {
+	db  *common.DB
+	mid core.ManifestID
+}
+
+// newStreamEventLog returns the event log of the stream with mid, nil if the stream event log is disabled
+func newStreamEventLog(node *core.LivepeerNode, mid core.ManifestID) *streamEventLog {
+	if StreamEventsRetention <= 0 || node.Database == nil {
+		return nil
+	}
+	return &streamEventLog{db: node.Database, mid: mid}
+}
+
+// record appends an event of typ with the JSON encoded details to the timeline of the stream
+func (l *streamEventLog) record(typ string, details map[string]interface{}) {
+	if l == nil {
+		return
+	}
+
+	event := &common.DBStreamEvent{
+		ManifestID: string(l.mid),
+		NodeID:     monitor.NodeID,
+		Type:       typ,
+		Details:    "{}",
+		CreatedAt:  time.Now(),
+	}
+	if len(details) > 0 {
+		data, err := json.Marshal(details)
+		if err != nil {
+			glog.Errorf("Error encoding stream event manifestID=%s type=%s err=%q", l.mid, typ, err)
+			return
+		}
+		event.Details = string(data)
+	}
+	if err := l.db.InsertStreamEvent(event); err != nil {
+		glog.Errorf("Error recording stream event manifestID=%s type=%s err=%q", l.mid, typ, err)
+	}
+}
+
+// RunStreamEventsCleanup deletes the stream events that are older than StreamEventsRetention until ctx is done
+func RunStreamEventsCleanup(ctx context.Context, db *common.DB) {
+	ticker := time.NewTicker(streamEventsCleanupInterval)
+	defer ticker.Stop()
+	for {
+		if err := db.DeleteStreamEvents(time.Now().Add(-StreamEventsRetention)); err != nil {
+			glog.Errorf("Error deleting old stream events err=%q", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamEventLog(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	defer func(retention time.Duration) { StreamEventsRetention = retention }(StreamEventsRetention)
+	n, _ := core.NewLivepeerNode(nil, "", dbh)
+
+	// Disabled
+	StreamEventsRetention = 0
+	l := newStreamEventLog(n, "foo")
+	assert.Nil(l)
+	l.record(streamEventIngestStarted, nil)
+	StreamEventsRetention = time.Hour
+	assert.Nil(newStreamEventLog(&core.LivepeerNode{}, "foo"))
+
+	l = newStreamEventLog(n, "foo")
+	require.NotNil(l)
+	l.record(streamEventIngestStarted, nil)
+	l.record(streamEventSegmentFailed, map[string]interface{}{"seqNo": 1, "error": "boom"})
+
+	events, err := dbh.StreamEvents("foo", time.Time{})
+	require.Nil(err)
+	require.Len(events, 2)
+	assert.Equal(streamEventIngestStarted, events[0].Type)
+	assert.Equal("{}", events[0].Details)
+	assert.Equal(streamEventSegmentFailed, events[1].Type)
+	assert.JSONEq(`{"seqNo":1,"error":"boom"}`, events[1].Details)
+}
+
+func TestRunStreamEventsCleanup(t *testing.T) {
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	defer func(retention, interval time.Duration) {
+		StreamEventsRetention = retention
+		streamEventsCleanupInterval = interval
+	}(StreamEventsRetention, streamEventsCleanupInterval)
+	StreamEventsRetention = time.Hour
+	streamEventsCleanupInterval = 10 * time.Millisecond
+
+	require.Nil(dbh.InsertStreamEvent(&common.DBStreamEvent{ManifestID: "foo", Type: "old", CreatedAt: time.Now().Add(-2 * time.Hour)}))
+	require.Nil(dbh.InsertStreamEvent(&common.DBStreamEvent{ManifestID: "foo", Type: "new", CreatedAt: time.Now()}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		RunStreamEventsCleanup(ctx, dbh)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	events, err := dbh.StreamEvents("foo", time.Time{})
+	require.Nil(err)
+	require.Len(events, 1)
+	assert.Equal(t, "new", events[0].Type)
+}
+
+func TestStreamEventsHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.Nil(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	defer func(retention time.Duration) { StreamEventsRetention = retention }(StreamEventsRetention)
+	StreamEventsRetention = 0
+
+	get := func(handler http.Handler, query string) (int, string) {
+		req := httptest.NewRequest("GET", "/streamEvents?"+query, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		body, _ := ioutil.ReadAll(rr.Result().Body)
+		return rr.Code, string(body)
+	}
+
+	// Disabled
+	code, body := get(streamEventsHandler(dbh), "manifestID=foo")
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Equal("stream event log is disabled", strings.TrimSpace(body))
+	StreamEventsRetention = time.Hour
+	code, _ = get(streamEventsHandler(nil), "manifestID=foo")
+	assert.Equal(http.StatusBadRequest, code)
+
+	handler := mustHaveFormParams(streamEventsHandler(dbh), "manifestID")
+	code, _ = get(handler, "")
+	assert.Equal(http.StatusBadRequest, code)
+	code, body = get(handler, "manifestID=foo&since=yesterday")
+	assert.Equal(http.StatusBadRequest, code)
+	assert.Contains(body, "invalid since param")
+
+	code, body = get(handler, "manifestID=foo")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("[]", body)
+
+	start := time.Now().Truncate(time.Second)
+	require.Nil(dbh.InsertStreamEvent(&common.DBStreamEvent{ManifestID: "foo", NodeID: "node1", Type: streamEventIngestStarted, Details: `{"nonce":1}`, CreatedAt: start}))
+	require.Nil(dbh.InsertStreamEvent(&common.DBStreamEvent{ManifestID: "foo", NodeID: "node1", Type: streamEventStreamEnded, Details: "{}", CreatedAt: start.Add(time.Minute)}))
+
+	code, body = get(handler, "manifestID=foo")
+	require.Equal(http.StatusOK, code)
+	var events []streamEvent
+	require.Nil(json.Unmarshal([]byte(body), &events))
+	require.Len(events, 2)
+	assert.Equal(streamEventIngestStarted, events[0].Type)
+	assert.Equal("node1", events[0].NodeID)
+	assert.True(start.Equal(events[0].Time))
+	assert.JSONEq(`{"nonce":1}`, string(events[0].Details))
+	assert.Equal(streamEventStreamEnded, events[1].Type)
+
+	code, body = get(handler, "manifestID=foo&since="+start.Add(time.Second).Format(time.RFC3339))
+	require.Equal(http.StatusOK, code)
+	require.Nil(json.Unmarshal([]byte(body), &events))
+	require.Len(events, 1)
+	assert.Equal(streamEventStreamEnded, events[0].Type)
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 
 	"flag"
@@ -13,9 +14,6 @@ import (
 	"strings"
 	"time"
 
-	// pprof adds handlers to default mux via `init()`
-	_ "net/http/pprof"
-
 	"github.com/cenkalti/backoff"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -31,7 +29,7 @@ import (
 
 var vFlag *glog.Level = flag.Lookup("v").Value.(*glog.Level)
 
-func (s *LivepeerServer) setServiceURI(serviceURI string) error {
+func (s *LivepeerServer) setServiceURI(ctx context.Context, serviceURI string) error {
 
 	parsedURI, err := url.Parse(serviceURI)
 	if err != nil {
@@ -41,13 +39,13 @@ func (s *LivepeerServer) setServiceURI(serviceURI string) error {
 
 	glog.Infof("Storing service URI %v in service registry...", serviceURI)
 
-	tx, err := s.LivepeerNode.Eth.SetServiceURI(serviceURI)
+	tx, err := s.LivepeerNode.Eth.SetServiceURI(ctx, serviceURI)
 	if err != nil {
 		glog.Error(err)
 		return err
 	}
 
-	err = s.LivepeerNode.Eth.CheckTx(tx)
+	err = s.LivepeerNode.Eth.CheckTx(ctx, tx)
 	if err != nil {
 		glog.Error(err)
 		return err
@@ -77,15 +75,15 @@ func (s *LivepeerServer) StartCliWebserver(bindAddr string) {
 func (s *LivepeerServer) setOnChainConfig() {}
 
 func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
-	// Override default mux because pprof only uses the default mux
-	// We really don't want to accidentally pull pprof into other listeners.
-	// Pprof, like the CLI, is a strictly private API!
-	mux := http.DefaultServeMux
-	http.DefaultServeMux = http.NewServeMux()
+	mux := http.NewServeMux()
+
+	// Pprof, like the CLI, is a strictly private API! It is also gated behind the admin token
+	s.registerDiagnosticsHandlers(mux)
 
 	mux.Handle("/signMessage", mustHaveFormParams(signMessageHandler(s.LivepeerNode.Eth), "message"))
 
 	mux.Handle("/vote", mustHaveFormParams(voteHandler(s.LivepeerNode.Eth), "poll", "choiceID"))
+	mux.Handle("/polls", pollsHandler(s.LivepeerNode.Eth))
 
 	//Set the broadcast config for creating onchain jobs.
 	mux.HandleFunc("/setBroadcastConfig", func(w http.ResponseWriter, r *http.Request) {
@@ -148,6 +146,8 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 				return
 			}
 			BroadcastJobVideoProfiles = profiles
+			// The profiles are used as they are instead of generating ladders from the source
+			AutoLadderMaxHeight = 0
 			glog.Infof("Transcode Job Type: %v", BroadcastJobVideoProfiles)
 		}
 	})
@@ -193,13 +193,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 	mux.HandleFunc("/initializeRound", func(w http.ResponseWriter, r *http.Request) {
 		if s.LivepeerNode.Eth != nil {
-			tx, err := s.LivepeerNode.Eth.InitializeRound()
+			tx, err := s.LivepeerNode.Eth.InitializeRound(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			err = s.LivepeerNode.Eth.CheckTx(tx)
+			err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -209,7 +209,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 	mux.HandleFunc("/roundInitialized", func(w http.ResponseWriter, r *http.Request) {
 		if s.LivepeerNode.Eth != nil {
-			initialized, err := s.LivepeerNode.Eth.CurrentRoundInitialized()
+			initialized, err := s.LivepeerNode.Eth.CurrentRoundInitialized(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
@@ -220,7 +220,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 	//Activate the orchestrator on-chain.
 	mux.HandleFunc("/activateOrchestrator", func(w http.ResponseWriter, r *http.Request) {
-		t, err := s.LivepeerNode.Eth.GetTranscoder(s.LivepeerNode.Eth.Account().Address)
+		t, err := s.LivepeerNode.Eth.GetTranscoder(r.Context(), s.LivepeerNode.Eth.Account().Address)
 		if err != nil {
 			glog.Error(err)
 			respondWith500(w, err.Error())
@@ -232,7 +232,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 			return
 		}
 
-		isLocked, err := s.LivepeerNode.Eth.CurrentRoundLocked()
+		isLocked, err := s.LivepeerNode.Eth.CurrentRoundLocked(r.Context())
 		if err != nil {
 			respondWith500(w, err.Error())
 			return
@@ -295,13 +295,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 			glog.Infof("Rebonding with unbonding lock %v...", unbondingLockID)
 
-			tx, err := s.LivepeerNode.Eth.RebondFromUnbonded(s.LivepeerNode.Eth.Account().Address, unbondingLockID)
+			tx, err := s.LivepeerNode.Eth.RebondFromUnbonded(r.Context(), s.LivepeerNode.Eth.Account().Address, unbondingLockID)
 			if err != nil {
 				respondWith500(w, err.Error())
 				return
 			}
 
-			err = s.LivepeerNode.Eth.CheckTx(tx)
+			err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			if err != nil {
 				respondWith500(w, err.Error())
 				return
@@ -319,13 +319,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 			if amount.Cmp(big.NewInt(0)) == 1 {
 				glog.Infof("Bonding %v...", amount)
 
-				tx, err := s.LivepeerNode.Eth.Bond(amount, s.LivepeerNode.Eth.Account().Address)
+				tx, err := s.LivepeerNode.Eth.Bond(r.Context(), amount, s.LivepeerNode.Eth.Account().Address)
 				if err != nil {
 					respondWith500(w, err.Error())
 					return
 				}
 
-				err = s.LivepeerNode.Eth.CheckTx(tx)
+				err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 				if err != nil {
 					respondWith500(w, err.Error())
 					return
@@ -335,26 +335,26 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 		glog.Infof("Setting orchestrator commission rates %v", s.LivepeerNode.Eth.Account().Address.Hex())
 
-		tx, err := s.LivepeerNode.Eth.Transcoder(eth.FromPerc(blockRewardCut), eth.FromPerc(feeShare))
+		tx, err := s.LivepeerNode.Eth.Transcoder(r.Context(), eth.FromPerc(blockRewardCut), eth.FromPerc(feeShare))
 		if err != nil {
 			respondWith500(w, err.Error())
 			return
 		}
 
-		err = s.LivepeerNode.Eth.CheckTx(tx)
+		err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 		if err != nil {
 			respondWith500(w, err.Error())
 			return
 		}
 
-		currentServiceURI, err := s.LivepeerNode.Eth.GetServiceURI(s.LivepeerNode.Eth.Account().Address)
+		currentServiceURI, err := s.LivepeerNode.Eth.GetServiceURI(r.Context(), s.LivepeerNode.Eth.Account().Address)
 		if err != nil {
 			respondWith500(w, err.Error())
 			return
 		}
 
 		if currentServiceURI != serviceURI {
-			if err := s.setServiceURI(serviceURI); err != nil {
+			if err := s.setServiceURI(r.Context(), serviceURI); err != nil {
 				respondWith500(w, err.Error())
 				return
 			}
@@ -411,7 +411,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 			}
 		}
 
-		t, err := s.LivepeerNode.Eth.GetTranscoder(s.LivepeerNode.Eth.Account().Address)
+		t, err := s.LivepeerNode.Eth.GetTranscoder(r.Context(), s.LivepeerNode.Eth.Account().Address)
 		if err != nil {
 			glog.Error(err)
 			respondWith500(w, err.Error())
@@ -419,7 +419,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 		}
 
 		if feeShareStr != "" && blockRewardCutStr != "" && (t.RewardCut.Cmp(eth.FromPerc(blockRewardCut)) != 0 || t.FeeShare.Cmp(eth.FromPerc(feeShare)) != 0) {
-			tx, err := s.LivepeerNode.Eth.Transcoder(eth.FromPerc(blockRewardCut), eth.FromPerc(feeShare))
+			tx, err := s.LivepeerNode.Eth.Transcoder(r.Context(), eth.FromPerc(blockRewardCut), eth.FromPerc(feeShare))
 			if err != nil {
 				glog.Error(err)
 				respondWith500(w, err.Error())
@@ -428,7 +428,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 			glog.Infof("Setting orchestrator commission rates for %v: reward cut=%v feeshare=%v", s.LivepeerNode.Eth.Account().Address.Hex(), blockRewardCut, feeShare)
 
-			err = s.LivepeerNode.Eth.CheckTx(tx)
+			err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			if err != nil {
 				glog.Error(err)
 				respondWith500(w, err.Error())
@@ -445,7 +445,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 			}
 
 			if t.ServiceURI != serviceURI {
-				if err := s.setServiceURI(serviceURI); err != nil {
+				if err := s.setServiceURI(r.Context(), serviceURI); err != nil {
 					glog.Error(err)
 					respondWith500(w, err.Error())
 					return
@@ -480,13 +480,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 				return
 			}
 
-			tx, err := s.LivepeerNode.Eth.Bond(amount, common.HexToAddress(toAddr))
+			tx, err := s.LivepeerNode.Eth.Bond(r.Context(), amount, common.HexToAddress(toAddr))
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			err = s.LivepeerNode.Eth.CheckTx(tx)
+			err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -517,13 +517,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 			toAddr := r.FormValue("toAddr")
 			if toAddr != "" {
 				// toAddr provided - invoke rebondFromUnbonded()
-				tx, err = s.LivepeerNode.Eth.RebondFromUnbonded(common.HexToAddress(toAddr), unbondingLockID)
+				tx, err = s.LivepeerNode.Eth.RebondFromUnbonded(r.Context(), common.HexToAddress(toAddr), unbondingLockID)
 			} else {
 				// toAddr not provided - invoke rebond()
-				tx, err = s.LivepeerNode.Eth.Rebond(unbondingLockID)
+				tx, err = s.LivepeerNode.Eth.Rebond(r.Context(), unbondingLockID)
 			}
 
-			err = s.LivepeerNode.Eth.CheckTx(tx)
+			err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -549,13 +549,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 				return
 			}
 
-			tx, err := s.LivepeerNode.Eth.Unbond(amount)
+			tx, err := s.LivepeerNode.Eth.Unbond(r.Context(), amount)
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			err = s.LivepeerNode.Eth.CheckTx(tx)
+			err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -580,13 +580,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 				glog.Errorf("Cannot convert unbondingLockId: %v", err)
 				return
 			}
-			tx, err := s.LivepeerNode.Eth.WithdrawStake(unbondingLockID)
+			tx, err := s.LivepeerNode.Eth.WithdrawStake(r.Context(), unbondingLockID)
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			err = s.LivepeerNode.Eth.CheckTx(tx)
+			err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -603,7 +603,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 			dAddr := s.LivepeerNode.Eth.Account().Address
 
-			d, err := s.LivepeerNode.Eth.GetDelegator(dAddr)
+			d, err := s.LivepeerNode.Eth.GetDelegator(r.Context(), dAddr)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -630,7 +630,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 				// Update unbonding locks in local DB if necessary
 				for id := range missingUnbondingLockIDs {
-					lock, err := s.LivepeerNode.Eth.GetDelegatorUnbondingLock(dAddr, id)
+					lock, err := s.LivepeerNode.Eth.GetDelegatorUnbondingLock(r.Context(), dAddr, id)
 					if err != nil {
 						glog.Error(err)
 						continue
@@ -651,7 +651,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 			withdrawableStr := r.FormValue("withdrawable")
 			if withdrawable, err := strconv.ParseBool(withdrawableStr); withdrawable {
-				currentRound, err = s.LivepeerNode.Eth.CurrentRound()
+				currentRound, err = s.LivepeerNode.Eth.CurrentRound(r.Context())
 				if err != nil {
 					glog.Error(err)
 					return
@@ -691,7 +691,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 	mux.HandleFunc("/claimEarnings", func(w http.ResponseWriter, r *http.Request) {
 		if s.LivepeerNode.Eth != nil {
 			claim := func() error {
-				init, err := s.LivepeerNode.Eth.CurrentRoundInitialized()
+				init, err := s.LivepeerNode.Eth.CurrentRoundInitialized(r.Context())
 				if err != nil {
 					glog.Errorf("Trying to claim but round not initialized.")
 					return err
@@ -699,15 +699,15 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 				if !init {
 					return errors.New("Round not initialized")
 				}
-				currRound, err := s.LivepeerNode.Eth.CurrentRound()
+				currRound, err := s.LivepeerNode.Eth.CurrentRound(r.Context())
 				if err != nil {
 					return err
 				}
-				tx, err := s.LivepeerNode.Eth.ClaimEarnings(currRound)
+				tx, err := s.LivepeerNode.Eth.ClaimEarnings(r.Context(), currRound)
 				if err != nil {
 					return err
 				}
-				return s.LivepeerNode.Eth.CheckTx(tx)
+				return s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			}
 
 			if err := backoff.Retry(claim, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second*15), 5)); err != nil {
@@ -718,7 +718,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 	mux.HandleFunc("/delegatorInfo", func(w http.ResponseWriter, r *http.Request) {
 		if s.LivepeerNode.Eth != nil {
-			d, err := s.LivepeerNode.Eth.GetDelegator(s.LivepeerNode.Eth.Account().Address)
+			d, err := s.LivepeerNode.Eth.DelegatorInfo(r.Context(), s.LivepeerNode.Eth.Account().Address)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -747,7 +747,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 				return
 			}
 
-			tp, err := s.LivepeerNode.Eth.GetTranscoderEarningsPoolForRound(s.LivepeerNode.Eth.Account().Address, round)
+			tp, err := s.LivepeerNode.Eth.GetTranscoderEarningsPoolForRound(r.Context(), s.LivepeerNode.Eth.Account().Address, round)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -847,49 +847,49 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 		if s.LivepeerNode.Eth != nil {
 			lp := s.LivepeerNode.Eth
 
-			numActiveOrchestrators, err := lp.GetTranscoderPoolMaxSize()
+			numActiveOrchestrators, err := lp.GetTranscoderPoolMaxSize(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			roundLength, err := lp.RoundLength()
+			roundLength, err := lp.RoundLength(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			roundLockAmount, err := lp.RoundLockAmount()
+			roundLockAmount, err := lp.RoundLockAmount(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			unbondingPeriod, err := lp.UnbondingPeriod()
+			unbondingPeriod, err := lp.UnbondingPeriod(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			inflation, err := lp.Inflation()
+			inflation, err := lp.Inflation(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			inflationChange, err := lp.InflationChange()
+			inflationChange, err := lp.InflationChange(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			targetBondingRate, err := lp.TargetBondingRate()
+			targetBondingRate, err := lp.TargetBondingRate(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			totalBonded, err := lp.GetTotalBonded()
+			totalBonded, err := lp.GetTotalBonded(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
@@ -902,16 +902,16 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 			}
 			var totalSupply *big.Int
 			if isL1Network {
-				totalSupply, err = lp.TotalSupply()
+				totalSupply, err = lp.TotalSupply(r.Context())
 			} else {
-				totalSupply, err = lp.GetGlobalTotalSupply()
+				totalSupply, err = lp.GetGlobalTotalSupply(r.Context())
 			}
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			paused, err := lp.Paused()
+			paused, err := lp.Paused(r.Context())
 			if err != nil {
 				glog.Error(err)
 				return
@@ -947,9 +947,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 		}
 	})
 
+	mux.Handle("/ethAccounts", ethAccountsHandler(s.LivepeerNode.Eth))
+	mux.Handle("/setEthAccount", mustHaveFormParams(setEthAccountHandler(s.LivepeerNode.Eth), "address"))
+	mux.Handle("/estimateTxCost", mustHaveFormParams(estimateTxCostHandler(s.LivepeerNode.Eth), "method"))
+
 	mux.HandleFunc("/tokenBalance", func(w http.ResponseWriter, r *http.Request) {
 		if s.LivepeerNode.Eth != nil {
-			b, err := s.LivepeerNode.Eth.BalanceOf(s.LivepeerNode.Eth.Account().Address)
+			b, err := s.LivepeerNode.Eth.BalanceOf(r.Context(), s.LivepeerNode.Eth.Account().Address)
 			if err != nil {
 				glog.Error(err)
 				w.Write([]byte(""))
@@ -974,7 +978,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 	mux.HandleFunc("/registeredOrchestrators", func(w http.ResponseWriter, r *http.Request) {
 		if s.LivepeerNode.Eth != nil {
-			orchestrators, err := s.LivepeerNode.Eth.TranscoderPool()
+			orchestrators, err := s.LivepeerNode.Eth.TranscoderPool(r.Context())
 			if err != nil {
 				glog.Error(err)
 				w.WriteHeader(http.StatusInternalServerError)
@@ -1009,7 +1013,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 	mux.HandleFunc("/orchestratorInfo", func(w http.ResponseWriter, r *http.Request) {
 		if s.LivepeerNode.Eth != nil {
-			t, err := s.LivepeerNode.Eth.GetTranscoder(s.LivepeerNode.Eth.Account().Address)
+			t, err := s.LivepeerNode.Eth.GetTranscoder(r.Context(), s.LivepeerNode.Eth.Account().Address)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -1053,13 +1057,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 				return
 			}
 
-			tx, err := s.LivepeerNode.Eth.Transfer(common.HexToAddress(to), amount)
+			tx, err := s.LivepeerNode.Eth.Transfer(r.Context(), common.HexToAddress(to), amount)
 			if err != nil {
 				glog.Error(err)
 				return
 			}
 
-			err = s.LivepeerNode.Eth.CheckTx(tx)
+			err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			if err != nil {
 				glog.Error(err)
 				return
@@ -1069,10 +1073,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 		}
 	})
 
+	mux.Handle("/tokenAllowance", tokenAllowanceHandler(s.LivepeerNode.Eth))
+	mux.Handle("/approveTokens", mustHaveFormParams(approveTokensHandler(s.LivepeerNode.Eth), "amount"))
+
 	mux.HandleFunc("/requestTokens", func(w http.ResponseWriter, r *http.Request) {
 		if s.LivepeerNode.Eth != nil {
 
-			nextValidRequest, err := s.LivepeerNode.Eth.NextValidRequest(s.LivepeerNode.Eth.Account().Address)
+			nextValidRequest, err := s.LivepeerNode.Eth.NextValidRequest(r.Context(), s.LivepeerNode.Eth.Account().Address)
 			if err != nil {
 				glog.Errorf("Unable to get the time for the next valid request from faucet: %v", err)
 				return
@@ -1093,13 +1100,13 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 			glog.Infof("Requesting tokens from faucet")
 
-			tx, err := s.LivepeerNode.Eth.Request()
+			tx, err := s.LivepeerNode.Eth.Request(r.Context())
 			if err != nil {
 				glog.Errorf("Error requesting tokens from faucet: %v", err)
 				return
 			}
 
-			err = s.LivepeerNode.Eth.CheckTx(tx)
+			err = s.LivepeerNode.Eth.CheckTx(r.Context(), tx)
 			if err != nil {
 				glog.Errorf("Error requesting tokens from faucet: %v", err)
 				return
@@ -1127,12 +1134,12 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 
 	mux.HandleFunc("/reward", func(w http.ResponseWriter, r *http.Request) {
 		glog.Infof("Calling reward")
-		tx, err := s.LivepeerNode.Eth.Reward()
+		tx, err := s.LivepeerNode.Eth.Reward(r.Context())
 		if err != nil {
 			glog.Errorf("Error calling reward: %v", err)
 			return
 		}
-		if err := s.LivepeerNode.Eth.CheckTx(tx); err != nil {
+		if err := s.LivepeerNode.Eth.CheckTx(r.Context(), tx); err != nil {
 			glog.Errorf("Error calling reward: %v", err)
 			return
 		}
@@ -1179,6 +1186,7 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 	mux.Handle("/minGasPrice", minGasPriceHandler(s.LivepeerNode.Eth))
 	mux.Handle("/setMinGasPrice", mustHaveFormParams(setMinGasPriceHandler(s.LivepeerNode.Eth), "minGasPrice"))
 	mux.Handle("/currentBlock", currentBlockHandler(s.LivepeerNode.Database))
+	mux.Handle("/broadcastSignedTx", mustHaveFormParams(broadcastSignedTxHandler(s.LivepeerNode.Eth), "tx"))
 
 	// TicketBroker
 	mux.Handle("/fundDepositAndReserve", mustHaveFormParams(fundDepositAndReserveHandler(s.LivepeerNode.Eth), "depositAmount", "reserveAmount"))
@@ -1187,8 +1195,46 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 	mux.Handle("/cancelUnlock", cancelUnlockHandler(s.LivepeerNode.Eth))
 	mux.Handle("/withdraw", withdrawHandler(s.LivepeerNode.Eth))
 	mux.Handle("/senderInfo", senderInfoHandler(s.LivepeerNode.Eth))
+	mux.Handle("/fundsStatus", fundsStatusHandler())
 	mux.Handle("/ticketBrokerParams", ticketBrokerParamsHandler(s.LivepeerNode.Eth))
 
+	// Fault injection
+	mux.Handle("/faults", faultsHandler())
+
+	// Stream event log
+	mux.Handle("/streamEvents", mustHaveFormParams(streamEventsHandler(s.LivepeerNode.Database), "manifestID"))
+
+	// Indexed contract events
+	mux.Handle("/contractEvents", contractEventsHandler(s.LivepeerNode.Database))
+
+	// Journal of the submitted transactions
+	mux.Handle("/txJournal", txJournalHandler(s.LivepeerNode.Database))
+	mux.Handle("/ethBalanceStatus", ethBalanceStatusHandler())
+
+	// Transactions that exceed the spend limits
+	mux.Handle("/pendingApprovals", pendingApprovalsHandler(s.LivepeerNode.Eth))
+	mux.Handle("/approveTransaction", mustHaveFormParams(approveTransactionHandler(s.LivepeerNode.Eth), "id"))
+	mux.Handle("/rejectTransaction", mustHaveFormParams(rejectTransactionHandler(s.LivepeerNode.Eth), "id"))
+
+	// Batch transcoding of files
+	if s.LivepeerNode.NodeType == core.BroadcasterNode {
+		mux.Handle("/batch", batchHandler(s))
+		mux.Handle("/batchJobs", batchJobsHandler(s.LivepeerNode.Database))
+		mux.Handle("/cancelBatchJob", mustHaveFormParams(cancelBatchJobHandler(s), "id"))
+	}
+
+	// Maintenance of orchestrators
+	if s.LivepeerNode.NodeType == core.OrchestratorNode {
+		mux.Handle("/maintenance", maintenanceHandler(s.LivepeerNode))
+		mux.Handle("/orchestratorConfigSchedule", orchestratorConfigScheduleHandler(s.LivepeerNode))
+		mux.Handle("/issueBroadcasterToken", mustHaveFormParams(issueBroadcasterTokenHandler(), "address"))
+	}
+
+	// Pre-stop hook of standalone transcoders
+	if s.LivepeerNode.NodeType == core.TranscoderNode {
+		mux.Handle("/drain", drainHandler())
+	}
+
 	// Metrics
 	if monitor.Enabled {
 		mux.Handle("/metrics", monitor.Exporter)
@@ -1198,39 +1244,49 @@ func (s *LivepeerServer) cliWebServerHandlers(bindAddr string) *http.ServeMux {
 }
 
 func (s *LivepeerServer) setOrchestratorPriceInfo(pricePerUnitStr, pixelsPerUnitStr string) error {
-	ok, err := regexp.MatchString("^[0-9]+$", pricePerUnitStr)
+	pricePerUnit, pixelsPerUnit, err := parsePriceInfo(pricePerUnitStr, pixelsPerUnitStr)
 	if err != nil {
 		return err
 	}
+
+	s.LivepeerNode.SetBasePrice(big.NewRat(pricePerUnit, pixelsPerUnit))
+	glog.Infof("Price per pixel set to %d wei for %d pixels\n", pricePerUnit, pixelsPerUnit)
+	return nil
+}
+
+// parsePriceInfo parses and validates the price per unit and pixels per unit of an orchestrator
+func parsePriceInfo(pricePerUnitStr, pixelsPerUnitStr string) (int64, int64, error) {
+	ok, err := regexp.MatchString("^[0-9]+$", pricePerUnitStr)
+	if err != nil {
+		return 0, 0, err
+	}
 	if !ok {
-		return fmt.Errorf("pricePerUnit is not a valid integer, provided %v", pricePerUnitStr)
+		return 0, 0, fmt.Errorf("pricePerUnit is not a valid integer, provided %v", pricePerUnitStr)
 	}
 
 	ok, err = regexp.MatchString("^[0-9]+$", pixelsPerUnitStr)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 	if !ok {
-		return fmt.Errorf("pixelsPerUnit is not a valid integer, provided %v", pixelsPerUnitStr)
+		return 0, 0, fmt.Errorf("pixelsPerUnit is not a valid integer, provided %v", pixelsPerUnitStr)
 	}
 
 	pricePerUnit, err := strconv.ParseInt(pricePerUnitStr, 10, 64)
 	if err != nil {
-		return fmt.Errorf("error converting pricePerUnit string to int64: %v", err)
+		return 0, 0, fmt.Errorf("error converting pricePerUnit string to int64: %v", err)
 	}
 	if pricePerUnit < 0 {
-		return fmt.Errorf("price unit must be greater than or equal to 0, provided %d", pricePerUnit)
+		return 0, 0, fmt.Errorf("price unit must be greater than or equal to 0, provided %d", pricePerUnit)
 	}
 
 	pixelsPerUnit, err := strconv.ParseInt(pixelsPerUnitStr, 10, 64)
 	if err != nil {
-		return fmt.Errorf("error converting pixelsPerUnit string to int64: %v", err)
+		return 0, 0, fmt.Errorf("error converting pixelsPerUnit string to int64: %v", err)
 	}
 	if pixelsPerUnit <= 0 {
-		return fmt.Errorf("pixels per unit must be greater than 0, provided %d", pixelsPerUnit)
+		return 0, 0, fmt.Errorf("pixels per unit must be greater than 0, provided %d", pixelsPerUnit)
 	}
 
-	s.LivepeerNode.SetBasePrice(big.NewRat(pricePerUnit, pixelsPerUnit))
-	glog.Infof("Price per pixel set to %d wei for %d pixels\n", pricePerUnit, pixelsPerUnit)
-	return nil
+	return pricePerUnit, pixelsPerUnit, nil
 }
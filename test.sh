@@ -12,8 +12,13 @@ go test -run LB_ -race
 # Be more strict with nvidia tests: run with race detector enabled
 go test -run Nvidia_ -race
 go test -run Capabilities_ -race
+# Test the GPU code paths with fake GPUs
+go test -tags fakegpu -run FakeGPU_ -race
 cd ..
 
+# Test fault injection
+go test -tags faults -run Fault ./common ./server
+
 # Be more strict with discovery tests: run with race detector enabled
 cd discovery
 go test -race
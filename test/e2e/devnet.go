@@ -0,0 +1,248 @@
+//go:build e2e
+// +build e2e
+
+// Package e2e runs broadcaster, orchestrator and transcoder nodes against a private devnet with the Livepeer protocol
+// deployed and drives them through a full job -> segments -> ticket redemption -> reward cycle. The tests only build
+// with the e2e tag and need docker and ffmpeg:
+//
+//	go test -tags e2e -v ./test/e2e
+//
+// By default a geth container with the protocol deployed is started for every test. Set E2E_ETH_URL to use a devnet
+// that is already running instead and E2E_GETH_IMAGE to use a different image.
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/livepeer/go-livepeer/eth"
+	"github.com/livepeer/go-livepeer/eth/devtools"
+	"github.com/stretchr/testify/require"
+)
+
+const defaultGethImage = "livepeer/geth-with-livepeer-protocol:streamflow"
+
+// setContractInfoTopic is the topic of the SetContractInfo event emitted by the Controller
+var setContractInfoTopic = ethcommon.HexToHash("0xf9a6cf519167d81bc5cb3d26c60c0c9a19704aa908c148e82a861b570f4cd2d7")
+
+// Devnet is a private ETH network with the Livepeer protocol deployed
+type Devnet struct {
+	URL        string
+	Controller ethcommon.Address
+	ChainID    *big.Int
+
+	rpc     *rpc.Client
+	backend *ethclient.Client
+	// miner is the unlocked account of the geth node that mines blocks
+	miner  ethcommon.Address
+	funder *devtools.Funder
+}
+
+// Account is an ETH account in a keystore directory with an empty password
+type Account struct {
+	Address     ethcommon.Address
+	KeystoreDir string
+}
+
+// StartDevnet starts a geth container with the protocol deployed, unless E2E_ETH_URL is set, and waits until the
+// node is ready. The container is removed when the test finishes
+func StartDevnet(t *testing.T) *Devnet {
+	url := os.Getenv("E2E_ETH_URL")
+	if url == "" {
+		image := os.Getenv("E2E_GETH_IMAGE")
+		if image == "" {
+			image = defaultGethImage
+		}
+		out, err := exec.Command("docker", "run", "-d", "--rm", "-p", "127.0.0.1::8545", image).Output()
+		require.NoError(t, err, "failed to start geth container")
+		container := strings.TrimSpace(string(out))
+		t.Cleanup(func() {
+			exec.Command("docker", "rm", "-f", container).Run()
+		})
+
+		out, err = exec.Command("docker", "port", container, "8545").Output()
+		require.NoError(t, err, "failed to get geth port")
+		url = "http://" + strings.Split(strings.TrimSpace(string(out)), "\n")[0]
+	}
+
+	d := &Devnet{URL: url}
+	waitFor(t, time.Minute, "devnet to be ready", func() bool {
+		client, err := rpc.Dial(url)
+		if err != nil {
+			return false
+		}
+		backend := ethclient.NewClient(client)
+		chainID, err := backend.ChainID(context.Background())
+		if err != nil {
+			client.Close()
+			return false
+		}
+		d.rpc, d.backend, d.ChainID = client, backend, chainID
+		return true
+	})
+	t.Cleanup(d.rpc.Close)
+
+	var accounts []ethcommon.Address
+	require.NoError(t, d.rpc.Call(&accounts, "eth_accounts"))
+	require.NotEmpty(t, accounts, "no mining account on the devnet")
+	d.miner = accounts[0]
+	funder, err := devtools.NewFunder(context.Background(), d.rpc)
+	require.NoError(t, err)
+	d.funder = funder
+
+	logs, err := d.backend.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Topics:    [][]ethcommon.Hash{{setContractInfoTopic}},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, logs, "no Controller deployed on the devnet")
+	d.Controller = logs[0].Address
+
+	return d
+}
+
+// NewAccount creates an account in a new keystore directory under dir and funds it with ETH from the miner
+func (d *Devnet) NewAccount(t *testing.T, dir string) Account {
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	acct, err := ks.NewAccount("")
+	require.NoError(t, err)
+
+	value := new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	require.NoError(t, d.funder.FundETH(context.Background(), acct.Address, value))
+
+	return Account{Address: acct.Address, KeystoreDir: dir}
+}
+
+// MineBlocks mines n blocks by sending empty transactions from the miner to itself
+func (d *Devnet) MineBlocks(t *testing.T, n int) {
+	for i := 0; i < n; i++ {
+		var hash ethcommon.Hash
+		require.NoError(t, d.rpc.Call(&hash, "eth_sendTransaction", map[string]interface{}{
+			"from": d.miner,
+			"to":   d.miner,
+		}))
+		d.waitMined(t, hash)
+	}
+}
+
+func (d *Devnet) waitMined(t *testing.T, hash ethcommon.Hash) {
+	var receipt *types.Receipt
+	waitFor(t, time.Minute, fmt.Sprintf("tx %s to be mined", hash.Hex()), func() bool {
+		var err error
+		receipt, err = d.backend.TransactionReceipt(context.Background(), hash)
+		return err == nil
+	})
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "tx %s failed", hash.Hex())
+}
+
+// Client returns a Livepeer ETH client that sends transactions from acct
+func (d *Devnet) Client(t *testing.T, acct Account) eth.LivepeerEthClient {
+	gpm := eth.NewGasPriceMonitor(d.backend, time.Second, big.NewInt(0), nil)
+	_, err := gpm.Start(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { gpm.Stop() })
+
+	am, err := eth.NewAccountManager(acct.Address, acct.KeystoreDir, d.ChainID)
+	require.NoError(t, err)
+	require.NoError(t, am.Unlock(""))
+
+	tm := eth.NewTransactionManager(d.backend, gpm, am, time.Minute, 0, 0, 0)
+	go tm.Start()
+	t.Cleanup(tm.Stop)
+
+	client, err := eth.NewClient(eth.LivepeerEthClientConfig{
+		AccountManager:     am,
+		ControllerAddr:     d.Controller,
+		EthClient:          d.backend,
+		GasPriceMonitor:    gpm,
+		TransactionManager: tm,
+		Signer:             types.LatestSignerForChainID(d.ChainID),
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.SetGasInfo(0))
+	return client
+}
+
+// SetupBroadcaster funds the deposit and the reserve of the broadcaster
+func SetupBroadcaster(t *testing.T, client eth.LivepeerEthClient, deposit, reserve *big.Int) {
+	ctx := context.Background()
+	tx, err := client.FundDepositAndReserve(ctx, deposit, reserve)
+	require.NoError(t, err)
+	require.NoError(t, client.CheckTx(ctx, tx))
+}
+
+// SetupOrchestrator funds the orchestrator with LPT from the faucet, bonds them to the orchestrator itself, registers it as a
+// transcoder and stores its service URI. Rounds are advanced on the devnet as needed
+func (d *Devnet) SetupOrchestrator(t *testing.T, client eth.LivepeerEthClient, serviceURI string) {
+	ctx := context.Background()
+	bond := big.NewInt(500)
+	require.NoError(t, d.funder.FundLPT(ctx, client, bond))
+
+	// The first round is initialized and locked, so the orchestrator has to register in a later round
+	d.NextRound(t, client)
+
+	addr := client.Account().Address
+	tx, err := client.Bond(ctx, bond, addr)
+	require.NoError(t, err)
+	require.NoError(t, client.CheckTx(ctx, tx))
+
+	tx, err = client.Transcoder(ctx, eth.FromPerc(10), eth.FromPerc(5))
+	require.NoError(t, err)
+	require.NoError(t, client.CheckTx(ctx, tx))
+
+	tx, err = client.SetServiceURI(ctx, serviceURI)
+	require.NoError(t, err)
+	require.NoError(t, client.CheckTx(ctx, tx))
+
+	// The orchestrator becomes active in the next round
+	d.NextRound(t, client)
+}
+
+// NextRound mines blocks until the next round starts and initializes it
+func (d *Devnet) NextRound(t *testing.T, client eth.LivepeerEthClient) {
+	ctx := context.Background()
+	current, err := client.CurrentRound(ctx)
+	require.NoError(t, err)
+	length, err := client.RoundLength(ctx)
+	require.NoError(t, err)
+
+	for i := int64(0); ; i++ {
+		round, err := client.CurrentRound(ctx)
+		require.NoError(t, err)
+		if round.Cmp(current) > 0 {
+			break
+		}
+		require.True(t, i <= length.Int64(), "round did not advance after %d blocks", length)
+		d.MineBlocks(t, 1)
+	}
+
+	tx, err := client.InitializeRound(ctx)
+	if err != nil && err.Error() == "ErrRoundInitialized" {
+		return
+	}
+	require.NoError(t, err)
+	require.NoError(t, client.CheckTx(ctx, tx))
+}
+
+// waitFor polls cond until it returns true and fails the test after timeout
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			require.FailNow(t, "timed out waiting for "+what)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,135 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "e2e_bin")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	livepeerBin, err = buildLivepeer(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to build livepeer:", err)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestE2E_FullCycle(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	devnet := StartDevnet(t)
+	keystores, err := ioutil.TempDir("", "e2e_keystores")
+	require.NoError(err)
+	defer os.RemoveAll(keystores)
+
+	// On-chain setup of the broadcaster and the orchestrator
+	eth1 := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	bAcct := devnet.NewAccount(t, keystores+"/broadcaster")
+	bClient := devnet.Client(t, bAcct)
+	SetupBroadcaster(t, bClient, new(big.Int).Mul(big.NewInt(100), eth1), new(big.Int).Mul(big.NewInt(100), eth1))
+
+	oAcct := devnet.NewAccount(t, keystores+"/orchestrator")
+	oClient := devnet.Client(t, oAcct)
+	serviceAddr := freeAddr(t)
+	devnet.SetupOrchestrator(t, oClient, "https://"+serviceAddr)
+
+	// Every ticket wins so that a single segment results in a redemption
+	ticketEV := eth1.String()
+	orch := StartNode(t, "orchestrator", append(EthArgs(devnet, oAcct),
+		"-orchestrator", "-orchSecret", "e2e", "-serviceAddr", serviceAddr,
+		"-pricePerUnit", "1", "-ticketEV", ticketEV, "-initializeRound", "-reward")...)
+	StartNode(t, "transcoder", "-transcoder", "-orchAddr", serviceAddr, "-orchSecret", "e2e")
+	waitFor(t, time.Minute, "transcoder to register", func() bool {
+		return orch.LogContains("Got a RegisterTranscoder request")
+	})
+	bcast := StartNode(t, "broadcaster", append(EthArgs(devnet, bAcct),
+		"-broadcaster", "-orchAddr", serviceAddr, "-httpIngest", "-transcodingOptions", "P144p30fps16x9,P240p30fps16x9",
+		"-maxPricePerUnit", "10", "-maxTicketEV", ticketEV, "-depositMultiplier", "1")...)
+
+	before, err := bClient.GetSenderInfo(ctx, bAcct.Address)
+	require.NoError(err)
+
+	// Segments are transcoded to every profile
+	seg, err := ioutil.ReadFile("../../core/test.ts")
+	require.NoError(err)
+	for i := 0; i < 3; i++ {
+		renditions := pushSegment(t, bcast, "e2e", i, seg)
+		assert.Equal(2, renditions)
+	}
+
+	// The orchestrator redeems the winning tickets from the broadcaster's deposit
+	waitFor(t, 2*time.Minute, "winning tickets to be redeemed", func() bool {
+		info, err := bClient.GetSenderInfo(ctx, bAcct.Address)
+		return err == nil && info.Deposit.Cmp(before.Deposit) < 0
+	})
+
+	// The fees and the rewards of the next round are distributed to the orchestrator
+	devnet.NextRound(t, oClient)
+	round, err := oClient.CurrentRound(ctx)
+	require.NoError(err)
+	waitFor(t, 2*time.Minute, "orchestrator to call reward", func() bool {
+		tr, err := oClient.GetTranscoder(ctx, oAcct.Address)
+		return err == nil && tr.LastRewardRound.Cmp(round) == 0
+	})
+	delegator, err := oClient.GetDelegator(ctx, oAcct.Address)
+	require.NoError(err)
+	assert.True(delegator.PendingFees.Sign() > 0, "no pending fees")
+	assert.True(delegator.PendingStake.Cmp(big.NewInt(500)) > 0, "no pending rewards")
+}
+
+// pushSegment pushes a segment to the broadcaster over HTTP and returns the number of renditions in the response
+func pushSegment(t *testing.T, n *Node, manifestID string, seqNo int, data []byte) int {
+	url := fmt.Sprintf("http://%s/live/%s/%d.ts", n.HTTPAddr, manifestID, seqNo)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Accept", "multipart/mixed")
+	req.Header.Set("Content-Duration", "2000")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "segment %d was not transcoded", seqNo)
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	mr := multipart.NewReader(resp.Body, params["boundary"])
+	renditions := 0
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(p)
+		require.NoError(t, err)
+		if len(body) > 0 {
+			renditions++
+		}
+	}
+	return renditions
+}
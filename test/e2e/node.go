@@ -0,0 +1,118 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// livepeerBin is the livepeer binary built by TestMain
+var livepeerBin string
+
+// buildLivepeer builds the livepeer binary into dir
+func buildLivepeer(dir string) (string, error) {
+	bin := filepath.Join(dir, "livepeer")
+	out, err := exec.Command("go", "build", "-o", bin, "github.com/livepeer/go-livepeer/cmd/livepeer").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, out)
+	}
+	return bin, nil
+}
+
+// Node is a livepeer process
+type Node struct {
+	Name     string
+	DataDir  string
+	CLIAddr  string
+	HTTPAddr string
+
+	cmd *exec.Cmd
+	log string
+}
+
+// StartNode starts a livepeer process with args on free CLI and HTTP ports and waits until its CLI server is up.
+// The process is killed when the test finishes and its log is printed if the test failed
+func StartNode(t *testing.T, name string, args ...string) *Node {
+	dataDir, err := ioutil.TempDir("", "e2e_"+name)
+	require.NoError(t, err)
+
+	n := &Node{
+		Name:     name,
+		DataDir:  dataDir,
+		CLIAddr:  freeAddr(t),
+		HTTPAddr: freeAddr(t),
+		log:      filepath.Join(dataDir, name+".log"),
+	}
+	logFile, err := os.Create(n.log)
+	require.NoError(t, err)
+
+	args = append([]string{"-v", "6", "-datadir", dataDir, "-cliAddr", n.CLIAddr, "-httpAddr", n.HTTPAddr, "-monitor=false"}, args...)
+	n.cmd = exec.Command(livepeerBin, args...)
+	n.cmd.Stdout, n.cmd.Stderr = logFile, logFile
+	require.NoError(t, n.cmd.Start())
+	t.Cleanup(func() {
+		n.cmd.Process.Kill()
+		n.cmd.Wait()
+		logFile.Close()
+		if t.Failed() {
+			if log, err := ioutil.ReadFile(n.log); err == nil {
+				t.Logf("%s log:\n%s", name, log)
+			}
+		}
+		os.RemoveAll(dataDir)
+	})
+
+	waitFor(t, time.Minute, name+" to start", func() bool {
+		resp, err := http.Get("http://" + n.CLIAddr + "/status")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	})
+	return n
+}
+
+// EthArgs returns the flags that connect a node to the devnet with acct
+func EthArgs(d *Devnet, acct Account) []string {
+	return []string{
+		"-network", "devenv",
+		"-ethUrl", d.URL,
+		"-ethController", d.Controller.Hex(),
+		"-ethAcctAddr", acct.Address.Hex(),
+		"-ethKeystorePath", acct.KeystoreDir,
+		"-ethPassword", "",
+		"-blockPollingInterval", "1",
+	}
+}
+
+// freeAddr returns a localhost address with a free port
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// Logs returns the log of the node
+func (n *Node) Logs() string {
+	log, _ := ioutil.ReadFile(n.log)
+	return string(log)
+}
+
+// LogContains returns true if the log of the node contains s
+func (n *Node) LogContains(s string) bool {
+	return strings.Contains(n.Logs(), s)
+}
@@ -0,0 +1,151 @@
+package verification
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// QualityMetric is an objective video quality metric that compares a rendition to its source
+type QualityMetric string
+
+const (
+	QualitySSIM QualityMetric = "ssim"
+	QualityVMAF QualityMetric = "vmaf"
+)
+
+var ErrQualityScoreNotFound = errors.New("QualityScoreNotFound")
+
+var qualityScoreRegex = map[QualityMetric]*regexp.Regexp{
+	QualitySSIM: regexp.MustCompile(`SSIM .*All:([0-9.]+)`),
+	QualityVMAF: regexp.MustCompile(`VMAF score[:=]\s*([0-9.]+)`),
+}
+
+// qualityFilters are the names of the ffmpeg filters that compute the metrics
+var qualityFilters = map[QualityMetric]string{
+	QualitySSIM: "ssim",
+	QualityVMAF: "libvmaf",
+}
+
+// QualityScorer computes the quality of renditions compared to their source with an external ffmpeg binary. Scores
+// are in the range of the metric, i.e. [0, 1] for SSIM and [0, 100] for VMAF
+type QualityScorer struct {
+	Metric QualityMetric
+
+	// How often to score a segment, on a per-segment basis
+	SampleRate float64
+
+	// Renditions scoring below MinScore are considered bad. 0 disables the check
+	MinScore float64
+
+	// Path of the ffmpeg binary
+	FFmpeg string
+}
+
+// NewQualityScorer returns a scorer for metric after checking that the ffmpeg binary in the PATH supports it
+func NewQualityScorer(metric string, sampleRate, minScore float64) (*QualityScorer, error) {
+	m := QualityMetric(strings.ToLower(metric))
+	if _, ok := qualityFilters[m]; !ok {
+		return nil, fmt.Errorf("unknown quality metric %q", metric)
+	}
+	if sampleRate <= 0 || sampleRate > 1 {
+		return nil, fmt.Errorf("invalid quality sample rate %v", sampleRate)
+	}
+
+	bin, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, err
+	}
+	if !AvailableQualityMetrics(bin)[m] {
+		return nil, fmt.Errorf("ffmpeg binary %s does not support the %s filter", bin, qualityFilters[m])
+	}
+
+	return &QualityScorer{Metric: m, SampleRate: sampleRate, MinScore: minScore, FFmpeg: bin}, nil
+}
+
+// ShouldSample returns true if the next segment should be scored
+func (q *QualityScorer) ShouldSample() bool {
+	return rand.Float64() < q.SampleRate
+}
+
+// BelowMin returns true if score is below the minimum acceptable score
+func (q *QualityScorer) BelowMin(score float64) bool {
+	return q.MinScore > 0 && score < q.MinScore
+}
+
+// Score compares the rendition data to the source segment data
+func (q *QualityScorer) Score(src, rendition []byte) (float64, error) {
+	dir, err := ioutil.TempDir("", "quality")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(dir)
+
+	srcFname := filepath.Join(dir, "source.ts")
+	if err := ioutil.WriteFile(srcFname, src, 0644); err != nil {
+		return 0, err
+	}
+	renditionFname := filepath.Join(dir, "rendition.ts")
+	if err := ioutil.WriteFile(renditionFname, rendition, 0644); err != nil {
+		return 0, err
+	}
+
+	return ScoreQuality(q.FFmpeg, q.Metric, srcFname, renditionFname)
+}
+
+// ScoreQuality compares the rendition file to the source file with the ffmpeg binary. The source is scaled to the
+// resolution of the rendition before the comparison
+func ScoreQuality(ffmpegBin string, metric QualityMetric, src, rendition string) (float64, error) {
+	filter, ok := qualityFilters[metric]
+	if !ok {
+		return 0, fmt.Errorf("unknown quality metric %q", metric)
+	}
+
+	lavfi := fmt.Sprintf("[1:v][0:v]scale2ref[ref][dist];[dist][ref]%s", filter)
+	out, err := exec.Command(ffmpegBin, "-hide_banner", "-nostats", "-i", rendition, "-i", src, "-lavfi", lavfi, "-f", "null", "-").CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("%v: %s", err, out)
+	}
+	return ParseQualityScore(metric, string(out))
+}
+
+// ParseQualityScore extracts the score of metric from the ffmpeg output
+func ParseQualityScore(metric QualityMetric, out string) (float64, error) {
+	re, ok := qualityScoreRegex[metric]
+	if !ok {
+		return 0, fmt.Errorf("unknown quality metric %q", metric)
+	}
+	m := re.FindStringSubmatch(out)
+	if m == nil {
+		return 0, ErrQualityScoreNotFound
+	}
+	return strconv.ParseFloat(m[1], 64)
+}
+
+// AvailableQualityMetrics returns the metrics supported by the ffmpeg binary
+func AvailableQualityMetrics(ffmpegBin string) map[QualityMetric]bool {
+	metrics := make(map[QualityMetric]bool)
+	out, err := exec.Command(ffmpegBin, "-hide_banner", "-filters").Output()
+	if err != nil {
+		return metrics
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for m, filter := range qualityFilters {
+			if fields[1] == filter {
+				metrics[m] = true
+			}
+		}
+	}
+	return metrics
+}
@@ -0,0 +1,74 @@
+package verification
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFFmpeg writes a script that prints the filter list or the output of a quality filter like ffmpeg does
+func fakeFFmpeg(t *testing.T, dir, filters, score string) string {
+	bin := filepath.Join(dir, "ffmpeg")
+	script := "#!/bin/sh\nif [ \"$2\" = \"-filters\" ]; then\n  printf '" + filters + "'\n  exit 0\nfi\necho '" + score + "' >&2\n"
+	require.Nil(t, ioutil.WriteFile(bin, []byte(script), 0755))
+	return bin
+}
+
+func TestParseQualityScore(t *testing.T) {
+	assert := assert.New(t)
+
+	score, err := ParseQualityScore(QualitySSIM, "[Parsed_ssim_1 @ 0x55d0] SSIM Y:0.982102 (17.473) U:0.990453 (20.202) V:0.990736 (20.332) All:0.985013 (18.242)")
+	assert.Nil(err)
+	assert.Equal(0.985013, score)
+
+	score, err = ParseQualityScore(QualityVMAF, "[libvmaf @ 0x55d0] VMAF score: 93.471342")
+	assert.Nil(err)
+	assert.Equal(93.471342, score)
+
+	_, err = ParseQualityScore(QualityVMAF, "Conversion failed!")
+	assert.Equal(ErrQualityScoreNotFound, err)
+
+	_, err = ParseQualityScore("psnr", "")
+	assert.EqualError(err, `unknown quality metric "psnr"`)
+}
+
+func TestQualityScorer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "quality")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	bin := fakeFFmpeg(t, dir, " ... ssim              VV->V      Calculate the SSIM between two video streams.\\n", "[Parsed_ssim_1 @ 0x55d0] SSIM Y:0.98 All:0.975 (16.0)")
+	assert.Equal(map[QualityMetric]bool{QualitySSIM: true}, AvailableQualityMetrics(bin))
+	assert.Empty(AvailableQualityMetrics(filepath.Join(dir, "missing")))
+
+	// The binary is looked up in the PATH
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir)
+
+	_, err = NewQualityScorer("psnr", 0.1, 0)
+	assert.EqualError(err, `unknown quality metric "psnr"`)
+	_, err = NewQualityScorer("ssim", 0, 0)
+	assert.EqualError(err, "invalid quality sample rate 0")
+	_, err = NewQualityScorer("vmaf", 0.1, 0)
+	assert.EqualError(err, "ffmpeg binary "+bin+" does not support the libvmaf filter")
+
+	q, err := NewQualityScorer("SSIM", 1, 0.98)
+	require.Nil(err)
+	assert.Equal(QualitySSIM, q.Metric)
+	assert.True(q.ShouldSample())
+
+	score, err := q.Score([]byte("source"), []byte("rendition"))
+	assert.Nil(err)
+	assert.Equal(0.975, score)
+	assert.True(q.BelowMin(score))
+	assert.False(q.BelowMin(0.99))
+	q.MinScore = 0
+	assert.False(q.BelowMin(score))
+}